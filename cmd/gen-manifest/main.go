@@ -0,0 +1,82 @@
+// Command gen-manifest writes the signed checksum manifest of a directory
+// of installer assets, which the installer verifies at startup before
+// trusting them, see installer.WithAssetVerification.
+//
+// Create a signing key once, keep it out of the repository, and build the
+// installer with the public key it prints:
+//
+//	go run github.com/mmso2016/setupkit/cmd/gen-manifest -genkey -key signing.key
+//
+// Then, before each build, sign the directory that is embedded:
+//
+//	go run github.com/mmso2016/setupkit/cmd/gen-manifest -key signing.key assets
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func main() {
+	keyFile := flag.String("key", "", "file with the hex encoded Ed25519 signing key")
+	genKey := flag.Bool("genkey", false, "create a new signing key in -key and print its public key")
+	out := flag.String("out", "", "manifest file to write (default <dir>/"+core.AssetManifestFile+")")
+	flag.Parse()
+
+	if err := run(*keyFile, *genKey, *out, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "gen-manifest:", err)
+		os.Exit(1)
+	}
+}
+
+func run(keyFile string, genKey bool, out string, args []string) error {
+	if keyFile == "" {
+		return fmt.Errorf("-key is required")
+	}
+	if genKey {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(keyFile, []byte(hex.EncodeToString(private.Seed())+"\n"), 0600); err != nil {
+			return err
+		}
+		fmt.Println("Public key:", hex.EncodeToString(public))
+		return nil
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("usage: gen-manifest -key FILE [-out FILE] DIR")
+	}
+	dir := args[0]
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("%s does not hold an Ed25519 signing key", keyFile)
+	}
+	key := ed25519.NewKeyFromSeed(seed)
+
+	manifest, err := core.GenerateAssetManifest(os.DirFS(dir), key)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		out = filepath.Join(dir, core.AssetManifestFile)
+	}
+	if err := os.WriteFile(out, manifest, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s, public key %s\n", out, hex.EncodeToString(key.Public().(ed25519.PublicKey)))
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -89,20 +90,9 @@ func main() {
 	installer.SetContext(context)  // Set the context on the installer
 
 	// Set install handler for demo (creates dummy files)
-	installer.SetInstallHandler(func(installPath string, components []core.Component) error {
-		for _, comp := range components {
-			// Create dummy file for each component
-			compFile := filepath.Join(installPath, comp.ID+".txt")
-			content := fmt.Sprintf("Component: %s\nName: %s\nInstalled at: %s\n",
-				comp.ID, comp.Name, time.Now().Format(time.RFC3339))
-			if err := os.WriteFile(compFile, []byte(content), 0644); err != nil {
-				return fmt.Errorf("failed to install component %s: %w", comp.ID, err)
-			}
-		}
-		return nil
-	})
+	installer.AddInstallHandler(dummyFileHandler{})
 
-	context.Metadata["installer"] = installer
+	context.Session = core.NewInstallSession(config, installer)
 
 	// Create DFA controller
 	dfaController := controller.NewInstallerController(config, installer)
@@ -241,4 +231,21 @@ func main() {
 			fmt.Printf("Connection string: %s\n", config.GetConnectionString())
 		}
 	}
-}
\ No newline at end of file
+}
+
+// dummyFileHandler creates a dummy file for each component and reports its progress
+type dummyFileHandler struct{}
+
+func (dummyFileHandler) Install(ctx context.Context, installPath string, components []core.Component) error {
+	progress := core.ProgressReporterFromContext(ctx)
+	for n, comp := range components {
+		compFile := filepath.Join(installPath, comp.ID+".txt")
+		content := fmt.Sprintf("Component: %s\nName: %s\nInstalled at: %s\n",
+			comp.ID, comp.Name, time.Now().Format(time.RFC3339))
+		if err := os.WriteFile(compFile, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to install component %s: %w", comp.ID, err)
+		}
+		core.ReportProgress(progress, int64(n+1), int64(len(components)), fmt.Sprintf("Wrote %s", compFile))
+	}
+	return nil
+}
@@ -13,11 +13,11 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/mmso2016/setupkit/pkg/installer"
 	"github.com/mmso2016/setupkit/pkg/installer/controller"
 	"github.com/mmso2016/setupkit/pkg/installer/core"
 	"github.com/mmso2016/setupkit/pkg/installer/ui"
 	"github.com/mmso2016/setupkit/pkg/installer/ui/cli"
-	"gopkg.in/yaml.v3"
 )
 
 //go:embed installer.yml
@@ -117,15 +117,18 @@ type InstallerConfig struct {
 	Components    []ComponentYAML  `yaml:"components"`
 	Settings      SettingsYAML     `yaml:"settings"`
 	Profiles      map[string]ProfileYAML `yaml:"profiles"`
+	Profile       string           `yaml:"-"` // Selected profile, set by resolveConfig
 }
 
 type ComponentYAML struct {
 	ID          string   `yaml:"id"`
 	Name        string   `yaml:"name"`
 	Description string   `yaml:"description"`
+	Category    string   `yaml:"category"`
 	Required    bool     `yaml:"required"`
 	Selected    bool     `yaml:"selected"`
 	Files       []string `yaml:"files"`
+	Dependencies []string `yaml:"dependencies"`
 }
 
 type SettingsYAML struct {
@@ -145,21 +148,27 @@ type ProfileYAML struct {
 func main() {
 	// Command line flags
 	var (
-		mode         = flag.String("mode", "", "UI mode: gui, browser, cli, auto (overrides config)")
-		installDir   = flag.String("dir", "", "Installation directory (overrides config)")
-		silent       = flag.Bool("silent", false, "Silent installation")
-		configFile   = flag.String("config", "", "YAML configuration file (if not specified, uses embedded config)")
-		profile      = flag.String("profile", "", "Installation profile: minimal, full, developer")
-		unattended   = flag.Bool("unattended", false, "Unattended installation (auto-accept license)")
-		listProfiles = flag.Bool("list-profiles", false, "List available installation profiles")
+		configFile     = flag.String("config", "", "YAML configuration file (if not specified, uses embedded config)")
+		listProfiles   = flag.Bool("list-profiles", false, "List available installation profiles")
+		listComponents = flag.Bool("list-components", false, "List available components")
+		jsonOutput     = flag.Bool("json", false, "Print -list-components output as JSON")
+		showConfig     = flag.Bool("show-config", false, "Show the resolved settings and where each value came from")
+		explain        = flag.Bool("explain", false, "List every action the installation would take, without installing")
+		uninstall      = flag.Bool("uninstall", false, "Remove the installation in the target directory (with -silent: no prompts, JSON summary)")
+		force          = flag.Bool("force", false, "Reinstall even if the same configuration is already installed")
+		modify         = flag.Bool("modify", false, "Add or remove components of the installation in the target directory")
 	)
-	flag.Parse()
 
-	fmt.Printf("DemoApp Installer\n")
-	fmt.Printf("Built with SetupKit Framework\n\n")
+	// Setting flags; read back through core.FlagSettings so only explicitly set flags override
+	flag.String("mode", "", "UI mode: gui, browser, cli, auto (overrides config)")
+	flag.String("dir", "", "Installation directory (overrides config)")
+	flag.Bool("silent", false, "Silent installation")
+	flag.String("profile", "", "Installation profile: minimal, full, developer")
+	flag.Bool("unattended", false, "Unattended installation (auto-accept license)")
+	flag.Parse()
 
-	// Load YAML configuration
-	yamlConfig, err := loadYAMLConfig(*configFile)
+	// Resolve configuration: flags > env > profile > file > embedded
+	yamlConfig, provenance, err := resolveConfig(*configFile)
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
@@ -170,34 +179,64 @@ func main() {
 		return
 	}
 
-	// Apply profile if specified
-	if *profile != "" {
-		if err := applyProfile(yamlConfig, *profile); err != nil {
-			log.Fatalf("Failed to apply profile '%s': %v", *profile, err)
+	// Create installer configuration from YAML
+	config := createConfigFromYAML(yamlConfig)
+	config.Profile = yamlConfig.Profile
+	config.Force = *force
+	source, err := core.EmbedSource(embeddedAssets, "assets")
+	if err != nil {
+		log.Fatalf("Failed to open embedded assets: %v", err)
+	}
+	config.Source = source
+	config.Provenance = provenance
+	if _, ok := provenance[core.SettingInstallDir]; !ok {
+		config.Provenance[core.SettingInstallDir] = core.SourceDefault
+	}
+
+	// Handle component listing (machine-readable, so no banner)
+	if *listComponents {
+		components := core.New(config).ListComponents()
+		if err := core.WriteComponentList(os.Stdout, components, *jsonOutput); err != nil {
+			log.Fatalf("Failed to list components: %v", err)
 		}
-		fmt.Printf("Applied installation profile: %s\n", *profile)
+		return
 	}
 
-	// Override YAML config with command-line flags
-	if *mode != "" {
-		yamlConfig.Mode = *mode
+	// Handle settings report
+	if *showConfig {
+		printProvenance(yamlConfig, config, core.New(config).ConfigProvenance())
+		return
 	}
-	if *installDir != "" {
-		yamlConfig.InstallDir = *installDir
+
+	// Handle plan explanation
+	if *explain {
+		if err := core.New(config).ExplainPlan(os.Stdout); err != nil {
+			log.Fatalf("Failed to explain the installation plan: %v", err)
+		}
+		return
 	}
-	if *unattended {
-		yamlConfig.Unattended = true
-		yamlConfig.AcceptLicense = true
+
+	// Handle uninstall; silent mode prints a JSON summary and exits with its code
+	if *uninstall {
+		if determineUIMode(yamlConfig.Mode, yamlConfig.Unattended) == core.ModeSilent {
+			code := installer.SilentUninstall(config, os.Stdout)
+			if code == installer.ExitSuccess {
+				removeSelf(config.InstallDir)
+			}
+			os.Exit(code)
+		}
+		runUninstall(config)
+		removeSelf(config.InstallDir)
+		return
 	}
-	if *silent {
-		yamlConfig.Mode = "silent"
-		yamlConfig.Unattended = true
-		yamlConfig.AcceptLicense = true
+
+	fmt.Printf("DemoApp Installer\n")
+	fmt.Printf("Built with SetupKit Framework\n\n")
+
+	if config.Profile != "" {
+		fmt.Printf("Applied installation profile: %s\n", config.Profile)
 	}
 
-	// Create installer configuration from YAML
-	config := createConfigFromYAML(yamlConfig)
-	
 	fmt.Printf("Installing: %s v%s\n", config.AppName, config.Version)
 	fmt.Printf("Publisher: %s\n", config.Publisher)
 	if yamlConfig.Unattended {
@@ -217,34 +256,37 @@ func main() {
 	// Create and configure installer
 	installer := core.New(config)
 	installer.SetContext(ctx)
-	installer.SetInstallHandler(func(installPath string, components []core.Component) error {
-		return copyInstallationFiles(installPath, components)
-	})
-	ctx.Metadata["installer"] = installer
+	if *modify {
+		if _, err := installer.LoadExistingInstall(); err != nil {
+			log.Fatalf("No installation to modify in %s: %v", config.InstallDir, err)
+		}
+	}
+	ctx.Session = core.NewInstallSession(config, installer)
 
 	// Create DFA controller - ALL UI modes use the same DFA approach
 	dfaController := controller.NewInstallerController(config, installer)
 
 	// Determine UI mode
 	uiMode := determineUIMode(yamlConfig.Mode, yamlConfig.Unattended)
+	config.Mode = uiMode
 
 	// Create DFA-controlled UI based on mode
 	fmt.Printf("Starting installation with %s interface...\n", getModeName(uiMode))
 	var installerView controller.InstallerView
 	switch uiMode {
 	case core.ModeCLI:
-		cliUI := cli.NewDFA()
-		if err := cliUI.Initialize(ctx); err != nil {
-			log.Fatalf("Failed to initialize CLI UI: %v", err)
-		}
-		cliUI.SetController(dfaController)
-		installerView = cliUI
+		installerView = newCLIView(ctx, dfaController)
 
 	case core.ModeGUI:
 		guiUI := ui.NewWebViewGUI()
 		if guiInitializer, ok := guiUI.(interface{ Initialize(*core.Context) error }); ok {
 			if err := guiInitializer.Initialize(ctx); err != nil {
-				log.Fatalf("Failed to initialize GUI UI: %v", err)
+				if config.DisableUIFallback {
+					log.Fatalf("Failed to initialize GUI UI: %v", err)
+				}
+				log.Printf("Warning: GUI unavailable (%v), falling back to the CLI", err)
+				installerView = newCLIView(ctx, dfaController)
+				break
 			}
 		}
 		if setController, ok := guiUI.(interface{ SetController(*controller.InstallerController) }); ok {
@@ -256,7 +298,12 @@ func main() {
 		browserUI := ui.NewGUIDFA()
 		if browserInitializer, ok := browserUI.(interface{ Initialize(*core.Context) error }); ok {
 			if err := browserInitializer.Initialize(ctx); err != nil {
-				log.Fatalf("Failed to initialize browser UI: %v", err)
+				if config.DisableUIFallback {
+					log.Fatalf("Failed to initialize browser UI: %v", err)
+				}
+				log.Printf("Warning: Browser UI unavailable (%v), falling back to the CLI", err)
+				installerView = newCLIView(ctx, dfaController)
+				break
 			}
 		}
 		if setController, ok := browserUI.(interface{ SetController(*controller.InstallerController) }); ok {
@@ -300,33 +347,75 @@ func main() {
 		}
 	}
 
+	if installer.UpToDate() {
+		fmt.Printf("\n%s %s is already installed, nothing to do (use -force to reinstall)\n", config.AppName, config.Version)
+		return
+	}
+	if installer.RebootRequired() {
+		fmt.Printf("\n%s installation completed; restart the computer to finish it\n", config.AppName)
+		os.Exit(exitCode(installer))
+	}
 	fmt.Printf("\n%s installation completed successfully! 🎉\n", config.AppName)
 }
 
-// loadYAMLConfig loads configuration from external file or embedded config
-func loadYAMLConfig(filename string) (*InstallerConfig, error) {
-	var data []byte
-	var err error
+// exitCode returns the exit code of a finished installation, which tells
+// deployment tools about a required restart in silent mode
+func exitCode(inst *core.Installer) int {
+	return installer.ExitCodeFor(inst, nil)
+}
 
-	if filename != "" {
-		// Load from external file if specified
-		data, err = os.ReadFile(filename)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read config file '%s': %w", filename, err)
-		}
-		fmt.Printf("Using external config file: %s\n", filename)
-	} else {
-		// Use embedded configuration
-		data = embeddedConfig
-		fmt.Printf("Using embedded configuration\n")
+// uninstallView reports the end of the uninstall flow on a channel
+type uninstallView struct {
+	*cli.CLIDFA
+	done chan error
+}
+
+func (v *uninstallView) ShowUninstallComplete(plan *core.RemovalPlan) error {
+	err := v.CLIDFA.ShowUninstallComplete(plan)
+	v.done <- err
+	return err
+}
+
+func (v *uninstallView) ShowErrorMessage(err error) error {
+	v.CLIDFA.ShowErrorMessage(err)
+	v.done <- err
+	return nil
+}
+
+// runUninstall removes the installation interactively on the command line
+func runUninstall(config *core.Config) {
+	uninstaller, err := core.NewUninstaller(config)
+	if err != nil {
+		log.Fatalf("Nothing to uninstall in %s: %v", config.InstallDir, err)
 	}
 
-	var config InstallerConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	view := &uninstallView{CLIDFA: cli.NewDFA(), done: make(chan error, 1)}
+	uc := controller.NewUninstallController(uninstaller)
+	uc.SetView(view)
+	if err := uc.Start(); err != nil {
+		log.Fatalf("Uninstall aborted: %v", err)
 	}
+	if err := uc.Next(); err != nil {
+		log.Fatalf("Uninstall failed: %v", err)
+	}
+	if err := <-view.done; err != nil {
+		os.Exit(installer.ExitFileError)
+	}
+}
 
-	return &config, nil
+// removeSelf deletes the running uninstaller after exit if it lives in the
+// install directory, so that the directory is removed completely
+func removeSelf(installDir string) {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	if rel, err := filepath.Rel(installDir, exe); err != nil || strings.HasPrefix(rel, "..") {
+		return
+	}
+	if err := core.SelfDelete(); err != nil {
+		log.Printf("Uninstaller could not remove itself: %v", err)
+	}
 }
 
 // listInstallationProfiles displays available installation profiles
@@ -348,48 +437,25 @@ func listInstallationProfiles(config *InstallerConfig) {
 	fmt.Println("Usage: installer -profile=<name>")
 }
 
-// applyProfile applies an installation profile to the configuration
-func applyProfile(config *InstallerConfig, profileName string) error {
-	profile, exists := config.Profiles[profileName]
-	if !exists {
-		return fmt.Errorf("profile '%s' not found", profileName)
-	}
-
-	// Reset all component selections
-	for i := range config.Components {
-		config.Components[i].Selected = config.Components[i].Required
+// newCLIView initializes the CLI view for the DFA controller
+func newCLIView(ctx *core.Context, dfaController *controller.InstallerController) controller.InstallerView {
+	cliUI := cli.NewDFA()
+	if err := cliUI.Initialize(ctx); err != nil {
+		log.Fatalf("Failed to initialize CLI UI: %v", err)
 	}
-
-	// Select components specified in profile
-	for _, componentID := range profile.Components {
-		found := false
-		for i, comp := range config.Components {
-			if comp.ID == componentID {
-				config.Components[i].Selected = true
-				found = true
-				break
-			}
-		}
-		if !found {
-			return fmt.Errorf("component '%s' specified in profile not found", componentID)
-		}
-	}
-
-	// Apply profile settings
-	config.Settings.CreateShortcuts = profile.CreateShortcuts
-	if profile.AddToPath {
-		config.Settings.AddToPath = profile.AddToPath
-	}
-
-	return nil
+	cliUI.SetController(dfaController)
+	return cliUI
 }
 
 // createConfigFromYAML converts YAML config to core.Config
 func createConfigFromYAML(yamlConfig *InstallerConfig) *core.Config {
 	// Determine installation directory
-	installDir := yamlConfig.InstallDir
+	installDir, err := core.ExpandPath(yamlConfig.InstallDir)
+	if err != nil {
+		log.Fatalf("Invalid install_dir: %v", err)
+	}
 	if installDir == "" {
-		installDir = getDefaultInstallDir(yamlConfig.AppName)
+		installDir = core.DefaultInstallDir(yamlConfig.AppName, core.ScopeAuto)
 	}
 
 	// Convert components
@@ -399,10 +465,12 @@ func createConfigFromYAML(yamlConfig *InstallerConfig) *core.Config {
 			ID:          comp.ID,
 			Name:        comp.Name,
 			Description: comp.Description,
+			Category:    comp.Category,
 			Size:        calculateComponentSize(comp.Files),
 			Required:    comp.Required,
 			Selected:    comp.Selected,
 			Files:       comp.Files,
+			Dependencies: comp.Dependencies,
 		})
 	}
 
@@ -439,18 +507,6 @@ func createConfigFromYAML(yamlConfig *InstallerConfig) *core.Config {
 	return config
 }
 
-// getDefaultInstallDir returns the default installation directory for the current platform
-func getDefaultInstallDir(appName string) string {
-	switch runtime.GOOS {
-	case "windows":
-		return fmt.Sprintf("C:\\Program Files\\%s", appName)
-	case "darwin":
-		return fmt.Sprintf("/Applications/%s", appName)
-	default:
-		return fmt.Sprintf("/opt/%s", strings.ToLower(appName))
-	}
-}
-
 // calculateComponentSize calculates the size of a component based on its files
 func calculateComponentSize(files []string) int64 {
 	var totalSize int64
@@ -509,76 +565,6 @@ func getModeName(mode core.Mode) string {
 	}
 }
 
-// copyInstallationFiles copies the selected component files to the installation directory
-func copyInstallationFiles(installPath string, components []core.Component) error {
-	fmt.Printf("Installing files to: %s\n", installPath)
-
-	// Create installation directory
-	if err := os.MkdirAll(installPath, 0755); err != nil {
-		return fmt.Errorf("failed to create installation directory: %w", err)
-	}
-
-	totalFiles := 0
-	copiedFiles := 0
-
-	// Count total files to copy
-	for _, comp := range components {
-		if comp.Selected || comp.Required {
-			totalFiles += len(comp.Files)
-		}
-	}
-
-	// Copy files for each selected component
-	for _, comp := range components {
-		if !comp.Selected && !comp.Required {
-			continue
-		}
-
-		fmt.Printf("Installing component: %s\n", comp.Name)
-
-		for _, filename := range comp.Files {
-			assetPath := "assets/" + filename
-			dstPath := filepath.Join(installPath, filename)
-
-			if err := copyEmbeddedFile(assetPath, dstPath); err != nil {
-				return fmt.Errorf("failed to copy %s: %w", filename, err)
-			}
-
-			copiedFiles++
-			progress := float64(copiedFiles) / float64(totalFiles)
-			fmt.Printf("  Copied %s (%.0f%%)\n", filename, progress*100)
-		}
-	}
-
-	fmt.Printf("Successfully installed %d files from embedded assets\n", copiedFiles)
-	return nil
-}
-
-// copyEmbeddedFile copies a file from embedded assets to destination
-func copyEmbeddedFile(embeddedPath, dstPath string) error {
-	// Open embedded file
-	srcFile, err := embeddedAssets.Open(embeddedPath)
-	if err != nil {
-		return fmt.Errorf("failed to open embedded file %s: %w", embeddedPath, err)
-	}
-	defer srcFile.Close()
-
-	// Create destination file
-	dstFile, err := os.Create(dstPath)
-	if err != nil {
-		return fmt.Errorf("failed to create destination file %s: %w", dstPath, err)
-	}
-	defer dstFile.Close()
-
-	// Copy data
-	_, err = io.Copy(dstFile, srcFile)
-	if err != nil {
-		return fmt.Errorf("failed to copy data: %w", err)
-	}
-
-	return nil
-}
-
 // copyFile copies a single file from src to dst (kept for backward compatibility)
 func copyFile(src, dst string) error {
 	srcFile, err := os.Open(src)
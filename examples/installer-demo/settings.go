@@ -0,0 +1,223 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"gopkg.in/yaml.v3"
+)
+
+// Demo settings in addition to the core setting keys
+const (
+	settingComponents      = "components"
+	settingCreateShortcuts = "create_shortcuts"
+	settingAddToPath       = "add_to_path"
+)
+
+// resolveConfig loads the embedded and external YAML configuration and
+// applies profile, environment and flag overrides. It returns the resolved
+// configuration and the source of every setting.
+func resolveConfig(configFile string) (*InstallerConfig, map[string]string, error) {
+	config, err := parseYAMLConfig(embeddedConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("embedded config: %w", err)
+	}
+	layers := []core.SettingsLayer{{Source: core.SourceEmbedded, Settings: yamlSettings(config)}}
+
+	if configFile != "" {
+		data, err := os.ReadFile(configFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read config file '%s': %w", configFile, err)
+		}
+		fmt.Fprintf(os.Stderr, "Using external config file: %s\n", configFile)
+		if config, err = parseYAMLConfig(data); err != nil {
+			return nil, nil, err
+		}
+		layers = append(layers, core.SettingsLayer{Source: core.SourceFile, Settings: yamlSettings(config)})
+	} else {
+		fmt.Fprintf(os.Stderr, "Using embedded configuration\n")
+	}
+
+	env := core.SettingsLayer{Source: core.SourceEnv, Settings: core.EnvSettings(core.DefaultEnvPrefix, os.LookupEnv)}
+	flags := core.SettingsLayer{Source: core.SourceFlags, Settings: flagSettings(flag.CommandLine)}
+
+	// The profile can be chosen by any source; its settings rank above the config file
+	selected, _ := core.ResolveSettings(append(layers, env, flags)...)
+	if name := selected[core.SettingProfile]; name != "" {
+		profile, exists := config.Profiles[name]
+		if !exists {
+			return nil, nil, fmt.Errorf("profile '%s' not found", name)
+		}
+		layers = append(layers, core.SettingsLayer{Source: core.SourceProfile, Settings: profileSettings(profile)})
+	}
+	layers = append(layers, env, flags)
+
+	settings, provenance := core.ResolveSettings(layers...)
+	if err := applySettings(config, settings); err != nil {
+		return nil, nil, err
+	}
+	return config, provenance, nil
+}
+
+// parseYAMLConfig parses an installer.yml document
+func parseYAMLConfig(data []byte) (*InstallerConfig, error) {
+	var config InstallerConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return &config, nil
+}
+
+// yamlSettings returns the settings a YAML configuration defines
+func yamlSettings(config *InstallerConfig) core.Settings {
+	settings := core.Settings{
+		settingCreateShortcuts: strconv.FormatBool(config.Settings.CreateShortcuts),
+		settingAddToPath:       strconv.FormatBool(config.Settings.AddToPath),
+	}
+	if config.InstallDir != "" {
+		settings[core.SettingInstallDir] = config.InstallDir
+	}
+	if config.Mode != "" {
+		settings[core.SettingMode] = config.Mode
+	}
+	if config.Unattended {
+		settings[core.SettingUnattended] = "true"
+	}
+	if config.AcceptLicense {
+		settings[core.SettingAcceptLicense] = "true"
+	}
+
+	var selected []string
+	for _, comp := range config.Components {
+		if comp.Selected || comp.Required {
+			selected = append(selected, comp.ID)
+		}
+	}
+	settings[settingComponents] = strings.Join(selected, ",")
+	return settings
+}
+
+// profileSettings returns the settings an installation profile defines
+func profileSettings(profile ProfileYAML) core.Settings {
+	settings := core.Settings{
+		settingComponents:      strings.Join(profile.Components, ","),
+		settingCreateShortcuts: strconv.FormatBool(profile.CreateShortcuts),
+	}
+	if profile.AddToPath {
+		settings[settingAddToPath] = "true"
+	}
+	return settings
+}
+
+// flagSettings returns the settings of explicitly set command-line flags
+func flagSettings(fs *flag.FlagSet) core.Settings {
+	raw := core.FlagSettings(fs, map[string]string{"dir": core.SettingInstallDir})
+
+	settings := core.Settings{}
+	for _, key := range []string{core.SettingInstallDir, core.SettingMode, core.SettingProfile} {
+		if value, ok := raw[key]; ok {
+			settings[key] = value
+		}
+	}
+	if raw["unattended"] == "true" {
+		settings[core.SettingUnattended] = "true"
+		settings[core.SettingAcceptLicense] = "true"
+	}
+	if raw["silent"] == "true" {
+		settings[core.SettingMode] = "silent"
+		settings[core.SettingUnattended] = "true"
+		settings[core.SettingAcceptLicense] = "true"
+	}
+	return settings
+}
+
+// applySettings writes resolved settings into the YAML configuration
+func applySettings(config *InstallerConfig, settings core.Settings) error {
+	boolSetting := func(key string, target *bool) error {
+		value, ok := settings[key]
+		if !ok {
+			return nil
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %q", key, value)
+		}
+		*target = enabled
+		return nil
+	}
+
+	if dir, ok := settings[core.SettingInstallDir]; ok {
+		config.InstallDir = dir
+	}
+	if mode, ok := settings[core.SettingMode]; ok {
+		if _, err := core.ParseMode(mode); err != nil {
+			return err
+		}
+		config.Mode = mode
+	}
+	config.Profile = settings[core.SettingProfile]
+	for key, target := range map[string]*bool{
+		core.SettingUnattended:    &config.Unattended,
+		core.SettingAcceptLicense: &config.AcceptLicense,
+		settingCreateShortcuts:    &config.Settings.CreateShortcuts,
+		settingAddToPath:          &config.Settings.AddToPath,
+	} {
+		if err := boolSetting(key, target); err != nil {
+			return err
+		}
+	}
+
+	if list, ok := settings[settingComponents]; ok {
+		selected := make(map[string]bool)
+		for _, id := range strings.Split(list, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				selected[id] = true
+			}
+		}
+		for i := range config.Components {
+			comp := &config.Components[i]
+			comp.Selected = comp.Required || selected[comp.ID]
+			delete(selected, comp.ID)
+		}
+		for id := range selected {
+			return fmt.Errorf("component '%s' specified in settings not found", id)
+		}
+	}
+	return nil
+}
+
+// printProvenance shows each resolved setting and the source it came from
+func printProvenance(yamlConfig *InstallerConfig, config *core.Config, provenance map[string]string) {
+	var selected []string
+	for _, comp := range config.Components {
+		if comp.Selected {
+			selected = append(selected, comp.ID)
+		}
+	}
+	values := map[string]string{
+		core.SettingInstallDir:    config.InstallDir,
+		core.SettingMode:          yamlConfig.Mode,
+		core.SettingUnattended:    strconv.FormatBool(config.Unattended),
+		core.SettingAcceptLicense: strconv.FormatBool(config.AcceptLicense),
+		core.SettingProfile:       config.Profile,
+		settingComponents:         strings.Join(selected, ","),
+		settingCreateShortcuts:    strconv.FormatBool(yamlConfig.Settings.CreateShortcuts),
+		settingAddToPath:          strconv.FormatBool(yamlConfig.Settings.AddToPath),
+	}
+
+	keys := make([]string, 0, len(provenance))
+	for key := range provenance {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Println("Resolved settings (flags > env > profile > file > embedded):")
+	for _, key := range keys {
+		fmt.Printf("  %-16s %-40s (%s)\n", key, values[key], provenance[key])
+	}
+}
@@ -113,7 +113,7 @@ func (e *Element) ToggleClass(class string) *Element {
 	if len(classes) == 0 {
 		delete(e.attributes, "class")
 	} else {
-		e.attributes["class"] = strings.Join(classes, " ")
+		e.Attr("class", strings.Join(classes, " "))
 	}
 	return e
 }
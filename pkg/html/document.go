@@ -154,6 +154,161 @@ func (d *Document) AddDefaultSetupKitStyles() *Document {
 			text-align: center;
 			margin-bottom: 40px;
 		}
+		.logo img {
+			max-width: min(240px, 60%);
+			max-height: 96px;
+			height: auto;
+			margin-bottom: 20px;
+		}
+		.help {
+			position: relative;
+			text-align: right;
+		}
+		.help-button {
+			width: 28px;
+			height: 28px;
+			border-radius: 50%;
+			border: 1px solid rgba(255,255,255,0.6);
+			background: transparent;
+			color: white;
+			font-weight: 600;
+			cursor: pointer;
+		}
+		.help-text {
+			display: none;
+			position: absolute;
+			right: 0;
+			top: 36px;
+			max-width: 360px;
+			padding: 12px 16px;
+			border-radius: 8px;
+			background: rgba(0,0,0,0.8);
+			text-align: left;
+			z-index: 10;
+		}
+		.help:hover .help-text,
+		.help:focus-within .help-text {
+			display: block;
+		}
+		.error-hint {
+			margin: 0 auto 30px;
+			max-width: 560px;
+			padding: 16px 20px;
+			border-left: 4px solid #FFC107;
+			border-radius: 8px;
+			background: rgba(255, 193, 7, 0.2);
+			text-align: left;
+			font-size: 1.1rem;
+		}
+		.error-hint p {
+			margin: 6px 0 0;
+		}
+		.error-detail {
+			white-space: pre-wrap;
+			text-align: left;
+			opacity: 0.8;
+		}
+		.presets {
+			display: flex;
+			flex-wrap: wrap;
+			align-items: center;
+			gap: 10px;
+			margin-bottom: 20px;
+		}
+		.presets:empty {
+			display: none;
+		}
+		.preset-button[aria-pressed="true"] {
+			font-weight: bold;
+		}
+		.preset-current {
+			opacity: 0.8;
+		}
+		.requirement-warnings {
+			margin: 20px auto;
+			max-width: 560px;
+			padding: 12px 20px;
+			border-left: 4px solid #FFC107;
+			border-radius: 8px;
+			background: rgba(255, 193, 7, 0.2);
+			text-align: left;
+		}
+		.requirement-warnings:empty {
+			display: none;
+		}
+		.requirement-warnings ul {
+			margin: 6px 0;
+		}
+		.release-notes {
+			max-height: 320px;
+			overflow-y: auto;
+			padding: 15px 20px;
+			border-radius: 10px;
+			background: rgba(255, 255, 255, 0.1);
+			text-align: left;
+		}
+		.release-notes h2 {
+			font-size: 1.2rem;
+			margin: 16px 0 8px;
+		}
+		.release-notes pre {
+			white-space: pre-wrap;
+		}
+		.language-list {
+			display: flex;
+			flex-direction: column;
+			gap: 10px;
+			max-width: 320px;
+			margin: 20px auto;
+			text-align: left;
+		}
+		.language-option {
+			display: flex;
+			align-items: center;
+			gap: 10px;
+			padding: 10px 15px;
+			border-radius: 8px;
+			background: rgba(255, 255, 255, 0.1);
+			cursor: pointer;
+		}
+		.completion-actions {
+			display: flex;
+			flex-wrap: wrap;
+			justify-content: center;
+			gap: 10px;
+		}
+		.action-status {
+			min-height: 1.2em;
+			word-break: break-all;
+			opacity: 0.8;
+		}
+		.diagnostics-status {
+			min-height: 1.2em;
+			word-break: break-all;
+			opacity: 0.8;
+		}
+		.password-control {
+			display: flex;
+			gap: 8px;
+		}
+		.password-control input {
+			flex: 1;
+		}
+		.password-toggle {
+			min-width: 64px;
+			cursor: pointer;
+		}
+		.change-link {
+			margin-left: 12px;
+			padding: 0;
+			border: none;
+			background: none;
+			color: inherit;
+			font-size: 0.9rem;
+			text-decoration: underline;
+			cursor: pointer;
+			opacity: 0.8;
+		}
 		.title {
 			font-size: 2.5rem;
 			font-weight: 300;
@@ -240,6 +395,14 @@ func (d *Document) AddDefaultSetupKitStyles() *Document {
 			background: linear-gradient(90deg, #4CAF50, #45a049);
 			transition: width 0.3s ease;
 		}
+		.progress.component {
+			height: 8px;
+			margin: 8px 0 20px;
+		}
+		.component-status {
+			text-align: center;
+			margin: 0;
+		}
 	`
 	return d.AddCSS(css)
 }
\ No newline at end of file
@@ -5,6 +5,7 @@ package html
 import (
 	"fmt"
 	"html"
+	"sort"
 	"strings"
 )
 
@@ -12,6 +13,7 @@ import (
 type Element struct {
 	tag        string
 	attributes map[string]string
+	attrOrder  []string      // Attribute names in insertion order for stable rendering
 	children   []interface{} // Can contain Element, string, or other renderable content
 	selfClose  bool          // For elements like <br>, <img>, etc.
 }
@@ -41,14 +43,22 @@ func NewSelfClosingElement(tag string) *Element {
 
 // Attr sets an attribute on the element
 func (e *Element) Attr(name, value string) *Element {
+	if _, exists := e.attributes[name]; !exists {
+		e.attrOrder = append(e.attrOrder, name)
+	}
 	e.attributes[name] = value
 	return e
 }
 
 // Attrs sets multiple attributes on the element
 func (e *Element) Attrs(attrs Attributes) *Element {
-	for name, value := range attrs {
-		e.attributes[name] = value
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		e.Attr(name, attrs[name])
 	}
 	return e
 }
@@ -103,8 +113,8 @@ func (e *Element) Render() string {
 	result.WriteString(e.tag)
 	
 	// Attributes
-	for name, value := range e.attributes {
-		result.WriteString(fmt.Sprintf(` %s="%s"`, name, html.EscapeString(value)))
+	for _, name := range e.attributeNames() {
+		result.WriteString(fmt.Sprintf(` %s="%s"`, name, html.EscapeString(e.attributes[name])))
 	}
 	
 	if e.selfClose {
@@ -136,6 +146,27 @@ func (e *Element) Render() string {
 	return result.String()
 }
 
+// attributeNames returns the set attribute names in insertion order
+func (e *Element) attributeNames() []string {
+	names := make([]string, 0, len(e.attributes))
+	seen := make(map[string]bool, len(e.attributes))
+	for _, name := range e.attrOrder {
+		if _, exists := e.attributes[name]; exists && !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+	// Attributes set without Attr (e.g. class helpers) follow in sorted order
+	var rest []string
+	for name := range e.attributes {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
 // String implements Stringer interface
 func (e *Element) String() string {
 	return e.Render()
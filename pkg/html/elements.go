@@ -44,6 +44,11 @@ func SCRIPT(js string) *Element {
 	return NewElement("script").HTML(js)
 }
 
+// NOSCRIPT creates a <noscript> element
+func NOSCRIPT() *Element {
+	return NewElement("noscript")
+}
+
 // Block elements
 
 // DIV creates a <div> element
@@ -0,0 +1,227 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// RenderFormPage renders a form state from its declared fields and values
+// by field ID, e.g. those of a controller.FormStateHandler. Next posts the fields to /api/form, or passes
+// them to installerSetFormValues in the WebView, and moves on once they are
+// accepted; rejected fields show their message below the control.
+func (r *SSRRenderer) RenderFormPage(config *core.Config, form *core.UIStateConfig, values map[string]interface{}) *Document {
+	doc := NewDocument().
+		SetTitle(config.AppName + " - " + form.Title).
+		SetCharset("utf-8").
+		SetViewport("").
+		AddDefaultSetupKitStyles()
+
+	// Posted by the Next button of browsers with JavaScript disabled
+	fields := FORM().ID("stateForm").Class("state-form").NoValidate().Attr("method", "POST").Attr("action", "/api/form")
+	for _, field := range form.Fields {
+		fields.Child(FormField(field, values[field.ID]))
+	}
+
+	container := DIV().Class("container").Children(
+		r.pageHeader(config, form.Help,
+			DIV().Class("title").Text(form.Title),
+			DIV().Class("subtitle").Text(form.Description),
+		),
+
+		MAIN().Child(fields),
+
+		DIV().Class("buttons").Style("text-align: center; margin-top: 40px;").Children(
+			BUTTON("Back").Class("button").ID("btnBack"),
+			BUTTON(r.nextLabel()).Class("button primary").ID("btnNext"),
+			BUTTON("Cancel").Class("button").ID("btnCancel"),
+		),
+	)
+
+	container.Child(r.noscriptButtons(config, true, BUTTON(r.nextLabel()).Class("button primary").Attr("form", "stateForm")))
+	doc.AddToBody(container)
+
+	js := `
+		document.addEventListener('DOMContentLoaded', function() {
+			const navigate = function(action) {
+				fetch('/api/' + action, { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'ok') {
+							window.location.reload();
+						}
+					});
+			};
+
+			const formValues = function() {
+				const values = {};
+				Array.from(document.getElementById('stateForm').elements).forEach(function(el) {
+					if (!el.name) {
+						return;
+					}
+					if (el.type === 'checkbox') {
+						values[el.name] = el.checked ? 'true' : 'false';
+					} else if (el.type === 'radio') {
+						if (el.checked) {
+							values[el.name] = el.value;
+						}
+					} else if (el.type === 'password' && el.value === '') {
+						// An empty password keeps the stored one
+					} else {
+						values[el.name] = el.value;
+					}
+				});
+				return values;
+			};
+
+			const showErrors = function(errors) {
+				document.querySelectorAll('.field-error').forEach(function(el) {
+					el.textContent = '';
+				});
+				(errors || []).forEach(function(e) {
+					const el = document.getElementById('error-' + e.field);
+					if (el) {
+						el.textContent = e.message;
+					}
+				});
+			};
+
+			document.getElementById('btnNext').addEventListener('click', function() {
+				const values = formValues();
+				const submit = typeof installerSetFormValues === 'function'
+					? installerSetFormValues(values).then(errors => ({ status: errors && errors.length ? 'error' : 'ok', errors: errors }))
+					: fetch('/api/form', {
+						method: 'POST',
+						headers: {'Content-Type': 'application/x-www-form-urlencoded'},
+						body: new URLSearchParams(values).toString()
+					}).then(response => response.json());
+				submit.then(data => {
+					showErrors(data.errors);
+					if (data.status === 'ok') {
+						navigate('next');
+					}
+				});
+			});
+			document.getElementById('btnBack').addEventListener('click', function() {
+				navigate('prev');
+			});
+			document.getElementById('btnCancel').addEventListener('click', function() {
+				// The installer asks for confirmation in its own dialog
+				fetch('/api/cancel', { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'cancelled') {
+							window.close();
+						} else {
+							window.location.reload();
+						}
+					});
+			});
+		});
+	`
+
+	doc.AddJS(js)
+	return doc
+}
+
+// FormField renders the control for a declared field with its label, help
+// and a placeholder for its validation message. Each field type maps to one
+// control: inputs for text, numbers and paths, PasswordField for passwords,
+// a checkbox, radio buttons, a select for dropdowns and textareas for long
+// text and lists, one item per line.
+func FormField(field core.UIField, value interface{}) *Element {
+	id := "field-" + field.ID
+	label := field.Label
+	if label == "" {
+		label = field.ID
+	}
+	if field.Required {
+		label += " *"
+	}
+	text := formText(value)
+
+	var control *Element
+	switch field.Type {
+	case core.FieldTypePassword:
+		control = PasswordField(field.ID, label)
+	case core.FieldTypeCheckbox:
+		box := INPUT("checkbox").ID(id).Name(field.ID).Value("true")
+		if checked, _ := value.(bool); checked {
+			box.Checked()
+		}
+		control = LABEL("").Class("checkbox-field").Children(box, SPAN(" "+label))
+	case core.FieldTypeRadio:
+		group := FIELDSET().ID(id).Class("radio-field").Role("radiogroup").Child(LEGEND(label))
+		for _, option := range field.Options {
+			radio := INPUT("radio").Name(field.ID).Value(option.ID)
+			if option.ID == text {
+				radio.Checked()
+			}
+			if option.Disabled {
+				radio.Disabled()
+			}
+			group.Child(LABEL("").Class("radio-option").Children(radio, SPAN(" "+formOptionLabel(option))))
+		}
+		control = group
+	case core.FieldTypeDropdown:
+		sel := SELECT().ID(id).Name(field.ID)
+		for _, option := range field.Options {
+			opt := OPTION(option.ID, formOptionLabel(option))
+			if option.ID == text {
+				opt.Selected()
+			}
+			if option.Disabled {
+				opt.Disabled()
+			}
+			sel.Child(opt)
+		}
+		control = DIV().Children(LABEL(label).Attr("for", id), sel)
+	case core.FieldTypeTextArea, core.FieldTypeList:
+		area := TEXTAREA(text).ID(id).Name(field.ID)
+		if field.Placeholder != "" {
+			area.Placeholder(field.Placeholder)
+		}
+		control = DIV().Children(LABEL(label).Attr("for", id), area)
+	default:
+		inputType := "text"
+		if field.Type == core.FieldTypeNumber {
+			inputType = "number"
+		}
+		input := INPUT(inputType).ID(id).Name(field.ID).Value(text)
+		if field.Placeholder != "" {
+			input.Placeholder(field.Placeholder)
+		}
+		if field.Validation != "" {
+			input.Pattern(field.Validation)
+		}
+		if field.Required {
+			input.Required()
+		}
+		control = DIV().Children(LABEL(label).Attr("for", id), input)
+	}
+
+	group := DIV().Class("form-group").Data("field", field.ID).Child(control)
+	if field.Help != "" {
+		group.Child(P(field.Help).Class("field-help"))
+	}
+	return group.Child(DIV().Class("field-error").ID("error-" + field.ID).Role("alert"))
+}
+
+// formText formats a field value for a control; lists get one item per line
+func formText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(v, "\n")
+	}
+	return fmt.Sprint(value)
+}
+
+func formOptionLabel(option core.FieldOption) string {
+	if option.Label != "" {
+		return option.Label
+	}
+	return option.ID
+}
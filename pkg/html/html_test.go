@@ -1,8 +1,16 @@
 package html
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
 	"strings"
 	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/controller"
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
 )
 
 func TestBasicElements(t *testing.T) {
@@ -106,6 +114,28 @@ func TestAttributes(t *testing.T) {
 	}
 }
 
+func TestAttributeOrder(t *testing.T) {
+	// Attributes render in the order they were first set, Attrs in sorted order
+	element := INPUT("checkbox").ID("accept").Attr("name", "accept").Attrs(Attributes{
+		"data-b": "2",
+		"data-a": "1",
+	}).Attr("type", "hidden").ToggleClass("active")
+
+	expected := `<input type="hidden" id="accept" name="accept" data-a="1" data-b="2" class="active" />`
+	for i := 0; i < 20; i++ {
+		if result := element.Render(); result != expected {
+			t.Fatalf("Expected %q, got %q", expected, result)
+		}
+	}
+
+	// An attribute removed and set again keeps its place
+	element.ToggleClass("active").Attr("title", "Accept").ToggleClass("checked")
+	expected = `<input type="hidden" id="accept" name="accept" data-a="1" data-b="2" class="checked" title="Accept" />`
+	if result := element.Render(); result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
 func TestHTMLEscaping(t *testing.T) {
 	// Test that text content is properly escaped
 	element := DIV().Text("<script>alert('xss')</script>")
@@ -215,4 +245,763 @@ func TestDocument(t *testing.T) {
 			t.Errorf("Expected result to contain %q", part)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestSSRPrimaryLabel(t *testing.T) {
+	config := &core.Config{AppName: "LabelApp", Version: "1.0.0"}
+	r := NewSSRRenderer()
+
+	if out := r.RenderWelcomePage(config).Render(); !strings.Contains(out, `id="btnNext">Next</button>`) {
+		t.Error("Welcome page should default to Next")
+	}
+	r.SetPrimaryLabel("Install")
+	if out := r.RenderSummaryPage(config, nil, "/opt/app").Render(); !strings.Contains(out, ">Install</button>") {
+		t.Error("Summary page should use the configured label")
+	}
+	r.SetPrimaryLabel("Finish")
+	if out := r.RenderCompletionPage(config, true).Render(); !strings.Contains(out, ">Finish</button>") {
+		t.Error("Completion page should use the configured label")
+	}
+}
+
+func TestSSRConfirmDialog(t *testing.T) {
+	config := &core.Config{AppName: "ConfirmApp", Version: "1.0.0"}
+	r := NewSSRRenderer()
+
+	out := r.RenderConfirmDialog(config, "Cancel installation", "Are you sure?").Render()
+	for _, want := range []string{"Cancel installation", "Are you sure?", `id="btnYes"`, `id="btnNo"`, "/api/confirm"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("confirm dialog lacks %q", want)
+		}
+	}
+	if out := r.RenderWelcomePage(config).Render(); strings.Contains(out, "confirm('Are you sure") {
+		t.Error("cancel must be confirmed by the installer, not the page")
+	}
+}
+
+func TestSSRLogoHeader(t *testing.T) {
+	config := &core.Config{AppName: "LogoApp", Version: "1.0.0"}
+	r := NewSSRRenderer()
+
+	if out := r.RenderWelcomePage(config).Render(); strings.Contains(out, "<img") {
+		t.Error("header without a logo should not contain an image")
+	}
+
+	config.Logo = &core.Logo{Data: []byte("<svg/>"), MIMEType: "image/svg+xml"}
+	img := `<div class="logo"><img src="data:image/svg+xml;base64,PHN2Zy8+" alt="LogoApp" /></div>`
+	pages := map[string]string{
+		"welcome":    r.RenderWelcomePage(config).Render(),
+		"components": r.RenderComponentsPage(config).Render(),
+		"summary":    r.RenderSummaryPage(config, nil, "/opt/app").Render(),
+		"completion": r.RenderCompletionPage(config, true).Render(),
+	}
+	for name, out := range pages {
+		if !strings.Contains(out, `<header class="header">`+img) {
+			t.Errorf("%s page header lacks the logo", name)
+		}
+	}
+}
+
+func TestSSRHelp(t *testing.T) {
+	config := &core.Config{AppName: "HelpApp", Version: "1.0.0"}
+	r := NewSSRRenderer()
+
+	if out := r.RenderInstallPathPage(config, "/opt/app").Render(); strings.Contains(out, `id="btnHelp"`) {
+		t.Error("page without help should not offer it")
+	}
+
+	r.SetHelp("Choose an empty directory.")
+	out := r.RenderInstallPathPage(config, "/opt/app").Render()
+	for _, want := range []string{`id="btnHelp"`, `title="Choose an empty directory."`, `<div class="help-text" role="tooltip">Choose an empty directory.</div>`, "/api/help"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("page with help lacks %q", want)
+		}
+	}
+
+	out = r.RenderHelpDialog(config, "Installation Path", "Choose an empty directory.").Render()
+	for _, want := range []string{"Installation Path", "Choose an empty directory.", `id="btnCloseHelp"`, "installerCloseHelp"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("help dialog lacks %q", want)
+		}
+	}
+	if strings.Contains(out, `id="btnHelp"`) {
+		t.Error("help dialog should not offer help itself")
+	}
+}
+
+func TestSSRSummaryChangeLinks(t *testing.T) {
+	config := &core.Config{AppName: "ChangeApp", Version: "1.0.0"}
+	components := []core.Component{{ID: "core", Name: "Core", Required: true, Selected: true}}
+	r := NewSSRRenderer()
+
+	if out := r.RenderSummaryPage(config, components, "/opt/app").Render(); strings.Contains(out, `class="change-link"`) {
+		t.Error("summary without change links should not offer them")
+	}
+
+	r.SetChangeLinks([]ChangeLink{
+		{State: controller.StateComponents, Kind: ChangeComponents, Label: "Component Selection"},
+		{State: controller.StateInstallPath, Kind: ChangeInstallPath, Label: "Installation Path"},
+		{State: "db-config", Label: "Database Configuration"},
+	})
+	out := r.RenderSummaryPage(config, components, "/opt/app").Render()
+	for _, want := range []string{
+		`data-state="components"`, `title="Change Component Selection"`,
+		`data-state="install-path"`, `title="Change Installation Path"`,
+		"Other Settings", "Database Configuration", `data-state="db-config"`,
+		"installerJump", "/api/jump",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary lacks %q", want)
+		}
+	}
+	if strings.Contains(out, "<span>Installation Path</span>") {
+		t.Error("standard steps should not be listed under other settings")
+	}
+}
+
+func TestPasswordField(t *testing.T) {
+	out := PasswordField("dbPassword", "Password").Render()
+	for _, want := range []string{
+		`<label for="dbPassword">Password</label>`,
+		`type="password"`, `id="dbPassword"`, `autocomplete="off"`,
+		`class="password-toggle"`, `type="button"`, `aria-controls="dbPassword"`, `aria-pressed="false"`,
+		"input.type = show ? &#39;text&#39; : &#39;password&#39;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("password field lacks %q in %s", want, out)
+		}
+	}
+	if strings.Contains(out, "value=") {
+		t.Error("password field must not render a value")
+	}
+}
+
+func TestSSRErrorPage(t *testing.T) {
+	config := &core.Config{AppName: "FailApp", Version: "1.0.0"}
+	r := NewSSRRenderer()
+
+	installErr := core.NewInstallError(&fs.PathError{Op: "mkdir", Path: "/opt/app", Err: fs.ErrPermission}, core.PhaseFileCopy, "core")
+	installErr.RolledBack = true
+	out := r.RenderErrorPage(config, installErr).Render()
+	for _, want := range []string{
+		"Installation Failed", `class="error-hint"`, `role="alert"`, "What you can do",
+		"run the installer as administrator", `class="rollback-note"`, "mkdir /opt/app", "/api/finish",
+	} {
+		if !strings.Contains(strings.ToLower(out), strings.ToLower(want)) {
+			t.Errorf("error page lacks %q", want)
+		}
+	}
+
+	out = r.RenderErrorPage(config, errors.New("boom")).Render()
+	if strings.Contains(out, `class="error-hint"`) || strings.Contains(out, `class="rollback-note"`) {
+		t.Error("unclassified error should show neither hint nor rollback note")
+	}
+}
+
+func TestSSRSaveDiagnostics(t *testing.T) {
+	config := &core.Config{AppName: "DiagApp", Version: "1.0.0"}
+	r := NewSSRRenderer()
+
+	pages := map[string]string{
+		"completion": r.RenderCompletionPage(config, true).Render(),
+		"error":      r.RenderErrorPage(config, errors.New("boom")).Render(),
+	}
+	for name, out := range pages {
+		for _, want := range []string{
+			`id="btnDiagnostics"`, "Save diagnostics", `id="diagnosticsStatus"`, `role="status"`,
+			"installerSaveDiagnostics", "/api/diagnostics",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s page lacks %q", name, want)
+			}
+		}
+	}
+}
+
+func TestSSRSelectionPresets(t *testing.T) {
+	config := &core.Config{
+		AppName: "PresetApp",
+		Components: []core.Component{
+			{ID: "app", Name: "Application", Required: true},
+			{ID: "docs", Name: "Documentation", Selected: true},
+			{ID: "tools", Name: "Tools"},
+		},
+	}
+	r := NewSSRRenderer()
+	if out := r.RenderComponentsPage(config).Render(); strings.Contains(out, "preset-button\"") {
+		t.Error("page without presets should not offer them")
+	}
+
+	config.SelectionPresets = map[string][]string{"Minimal": {}, "Typical": {"docs"}, "Full": {"docs", "tools"}}
+	out := r.RenderComponentsPage(config).Render()
+	for _, want := range []string{
+		`data-preset="Full"`, `data-components="app,docs,tools"`,
+		`data-preset="Minimal"`, `data-components="app"`,
+		`data-preset="Typical"`, `data-components="app,docs"`,
+		`id="presetCurrent"`, `>Typical</span>`, "updatePreset",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("components page lacks %q", want)
+		}
+	}
+	if strings.Index(out, `data-preset="Full"`) > strings.Index(out, `data-preset="Typical"`) {
+		t.Error("presets should be sorted by name")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	source := "## Version 2.0\n\nA **bold** and *new* release\nwith `code`.\n\n- First\n- Second\n  continued\n\n1. One\n2. Two\n\n```\n<b>raw</b>\n```\n\nSee [docs](https://example.com) or [this](javascript:void). <script>"
+	got := Markdown(source).Render()
+	want := `<div class="markdown">` +
+		`<h2>Version 2.0</h2>` +
+		`<p>A <strong>bold</strong> and <em>new</em> release with <code>code</code>.</p>` +
+		`<ul><li>First</li><li>Second continued</li></ul>` +
+		`<ol><li>One</li><li>Two</li></ol>` +
+		`<pre><code>&lt;b&gt;raw&lt;/b&gt;</code></pre>` +
+		`<p>See <a href="https://example.com" target="_blank" rel="noopener">docs</a> or <span>this</span>. &lt;script&gt;</p>` +
+		`</div>`
+	if got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSSRReleaseNotesPage(t *testing.T) {
+	config := &core.Config{AppName: "NotesApp", Version: "2.0.0"}
+	r := NewSSRRenderer()
+
+	out := r.RenderReleaseNotesPage(config, "1.0.0", "## Version 2.0.0\n\n- New dashboard").Render()
+	for _, want := range []string{
+		"What&#39;s New", "Updating from version 1.0.0 to 2.0.0", `class="markdown release-notes"`,
+		"<h2>Version 2.0.0</h2>", "<li>New dashboard</li>", `id="btnBack"`, `id="btnNext"`, "navigate('next')",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("release notes page lacks %q", want)
+		}
+	}
+}
+
+func TestSSRForwardOnlyHidesBack(t *testing.T) {
+	config := &core.Config{AppName: "KioskApp"}
+	r := NewSSRRenderer()
+	hide := "#btnBack { display: none; }"
+
+	if out := r.RenderInstallPathPage(config, "/opt/kiosk").Render(); strings.Contains(out, hide) {
+		t.Error("the Back button should be shown by default")
+	}
+	config.ForwardOnly = true
+	if out := r.RenderInstallPathPage(config, "/opt/kiosk").Render(); !strings.Contains(out, hide) {
+		t.Error("forward-only pages should hide the Back button")
+	}
+}
+
+func TestSSRWelcomeRequirementWarnings(t *testing.T) {
+	config := &core.Config{AppName: "HeavyApp", Version: "1.0.0"}
+	r := NewSSRRenderer()
+
+	out := r.RenderWelcomePage(config).Render()
+	if !strings.Contains(out, `<div class="requirement-warnings"></div>`) {
+		t.Error("welcome page without warnings should render an empty warnings element")
+	}
+
+	r.SetWarnings([]string{"8.0 GB of memory recommended, 4.0 GB installed"})
+	out = r.RenderWelcomePage(config).Render()
+	for _, want := range []string{
+		`class="requirement-warnings" role="status"`, "below the recommended system requirements",
+		"<li>8.0 GB of memory recommended, 4.0 GB installed</li>", `id="btnNext"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("welcome page lacks %q", want)
+		}
+	}
+}
+
+func TestSSRLicensesPage(t *testing.T) {
+	config := &core.Config{AppName: "LicenseApp"}
+	r := NewSSRRenderer()
+
+	out := r.RenderLicensePage(config, "MIT License").Render()
+	if !strings.Contains(out, `id="acceptLicense" class="license-accept"`) || strings.Contains(out, "data-component") {
+		t.Error("a single license should keep its single checkbox")
+	}
+
+	out = r.RenderLicensesPage(config, []core.ApplicableLicense{
+		{Title: "LicenseApp License", Text: "MIT License"},
+		{ComponentID: "gpl-tools", Title: "GPL Tools License", Text: "GPL"},
+	}).Render()
+	for _, want := range []string{
+		`<h3 class="license-title">GPL Tools License</h3>`,
+		`id="acceptLicense-0" class="license-accept"`,
+		`id="acceptLicense-1" class="license-accept" style="margin-right: 10px;" data-component="gpl-tools"`,
+		"I accept the GPL Tools License", "every(box => box.checked)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("licenses page lacks %q", want)
+		}
+	}
+}
+
+func TestSSRLanguagePage(t *testing.T) {
+	config := &core.Config{AppName: "WorldApp"}
+	r := NewSSRRenderer()
+	r.SetLocalizer(core.NewLocalizer(config))
+
+	out := r.RenderLanguagePage(config, []string{"en", "de"}, "de").Render()
+	for _, want := range []string{
+		`<label class="language-option" lang="en">`, "English", "Deutsch",
+		`id="locale-de" name="locale" value="de" checked="checked"`, "/api/locale", "installerSetLocale",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("language page lacks %q", want)
+		}
+	}
+	if strings.Contains(out, `value="en" checked`) {
+		t.Error("only the current locale should be checked")
+	}
+}
+
+func TestSSRInstallScopePage(t *testing.T) {
+	config := &core.Config{AppName: "ScopeApp"}
+	r := NewSSRRenderer()
+
+	out := r.RenderInstallScopePage(config, core.ScopePerUser.String(), false).Render()
+	for _, want := range []string{
+		"Install ScopeApp for all users of this computer or just for you?", "All users", "Just me",
+		`id="install-scope-per-user" name="installScope" value="per-user" checked="checked"`,
+		"/api/install-scope", "installerSetInstallScope", `class="install-scope-elevation-note"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("install scope page lacks %q", want)
+		}
+	}
+	if strings.Contains(out, `value="per-machine" checked`) {
+		t.Error("only the chosen scope should be checked")
+	}
+	if out := r.RenderInstallScopePage(config, core.ScopePerMachine.String(), true).Render(); strings.Contains(out, "install-scope-elevation-note") {
+		t.Error("elevated installers need no elevation note")
+	}
+}
+
+func TestSSRPathScopePage(t *testing.T) {
+	config := &core.Config{
+		AppName:    "PathApp",
+		PathConfig: &core.PathConfiguration{Enabled: true, Dirs: []string{"/opt/pathapp/bin"}, AskScope: true},
+	}
+	r := NewSSRRenderer()
+
+	out := r.RenderPathScopePage(config, core.PathScopeNameSystem, false).Render()
+	for _, want := range []string{
+		"/opt/pathapp/bin", `id="path-scope-system" name="pathScope" value="system" checked="checked"`,
+		"/api/path-scope", "installerSetPathScope", `class="path-elevation-note"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("PATH page lacks %q", want)
+		}
+	}
+	if strings.Contains(out, `value="user" checked`) {
+		t.Error("only the chosen scope should be checked")
+	}
+
+	config.PathConfig.System = true
+	if out := r.RenderSummaryPage(config, nil, "/opt/pathapp").Render(); !strings.Contains(out, "/opt/pathapp/bin is added to the system PATH.") {
+		t.Error("summary should state which PATH changes")
+	}
+}
+
+func TestSSRWelcomePageInChosenLanguage(t *testing.T) {
+	config := &core.Config{
+		AppName:    "WorldApp",
+		Version:    "1.0.0",
+		Publisher:  "ACME",
+		Locales:    []string{"en", "de"},
+		Components: []core.Component{{ID: "core", Name: "Core", Required: true, Selected: true}},
+	}
+	ic := controller.NewInstallerController(config, core.New(config))
+	driver := controller.NewTestDriver(ic).Input(controller.StateLanguage, "locale", "de")
+	if err := driver.Run(wizard.ActionNext); err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.AssertStates(controller.StateLanguage, controller.StateWelcome); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewSSRRenderer()
+	r.SetLocalizer(ic.Localizer())
+	r.SetPrimaryLabel(ic.PrimaryLabelFor(controller.StateWelcome))
+	out := r.RenderWelcomePage(config).Render()
+	for _, want := range []string{
+		"<title>Installation von WorldApp</title>", "Herausgeber: ACME",
+		"Willkommen beim Installationsassistenten von WorldApp.",
+		`id="btnNext">Weiter</button>`, `id="btnCancel">Abbrechen</button>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("welcome page after choosing German lacks %q", want)
+		}
+	}
+	if strings.Contains(out, "installation wizard") {
+		t.Error("welcome page after choosing German still shows English text")
+	}
+}
+
+func TestSSRCompletionActions(t *testing.T) {
+	config := &core.Config{
+		AppName: "NextApp",
+		CompletionActions: []core.CompletionAction{
+			{ID: "launch", Label: "Launch NextApp", Kind: core.ActionLaunch, Target: "nextapp"},
+			{ID: "log", Label: "View log", Kind: core.ActionViewLog},
+			{ID: "diag", Label: "Save diagnostics", Kind: core.ActionExportDiagnostics},
+		},
+	}
+	r := NewSSRRenderer()
+
+	out := r.RenderCompletionPage(config, true).Render()
+	for _, want := range []string{
+		"Next steps", `data-action="launch"`, "Launch NextApp", `data-action="diag"`,
+		`id="actionStatus"`, "installerRunAction", "/api/action",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("completion page lacks %q", want)
+		}
+	}
+	if strings.Contains(out, `data-action="log"`) {
+		t.Error("view log action without log file should not be offered")
+	}
+	if strings.Contains(out, `id="btnDiagnostics"`) {
+		t.Error("diagnostics action should replace the diagnostics button")
+	}
+
+	out = r.RenderCompletionPage(config, false).Render()
+	if strings.Contains(out, "data-action") || !strings.Contains(out, `id="btnDiagnostics"`) {
+		t.Error("failed installation should offer diagnostics but no next steps")
+	}
+}
+
+func TestSSRViewLog(t *testing.T) {
+	config := &core.Config{AppName: "LogApp", Version: "1.0.0"}
+	r := NewSSRRenderer()
+
+	if out := r.RenderErrorPage(config, errors.New("boom")).Render(); strings.Contains(out, "btnViewLog") {
+		t.Error("error page without log file offers the log")
+	}
+
+	config.LogFile = "/tmp/logapp.log"
+	pages := map[string]string{
+		"error":      r.RenderErrorPage(config, errors.New("boom")).Render(),
+		"completion": r.RenderCompletionPage(config, true).Render(),
+		"failed":     r.RenderCompletionPage(config, false).Render(),
+	}
+	for name, out := range pages {
+		for _, want := range []string{`id="btnViewLog"`, "installerViewLog", "/api/log", `id="diagnosticsStatus"`} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s page lacks %q", name, want)
+			}
+		}
+	}
+
+	// A configured view log action replaces the button
+	config.CompletionActions = []core.CompletionAction{{ID: "log", Label: "View log", Kind: core.ActionViewLog}}
+	out := r.RenderCompletionPage(config, true).Render()
+	if strings.Contains(out, `id="btnViewLog"`) || !strings.Contains(out, `data-action="log"`) {
+		t.Error("view log action should replace the view log button")
+	}
+}
+
+func TestSSRCompletionWarnings(t *testing.T) {
+	config := &core.Config{AppName: "WarnApp", Version: "1.0.0"}
+	r := NewSSRRenderer()
+
+	if out := r.RenderCompletionPage(config, true).Render(); strings.Contains(out, "completion-warnings") {
+		t.Error("completion page without warnings shows them")
+	}
+	r.SetCompletionWarnings([]string{"Skipped Tools: the .NET 8 runtime is not installed"})
+	out := r.RenderCompletionPage(config, true).Render()
+	for _, want := range []string{`class="completion-warnings"`, "completed with warnings", "Skipped Tools: the .NET 8 runtime is not installed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("completion page lacks %q", want)
+		}
+	}
+}
+
+func TestSSRSummaryDownloadSize(t *testing.T) {
+	config := &core.Config{AppName: "SizeApp"}
+	r := NewSSRRenderer()
+	components := []core.Component{{ID: "core", Name: "Core", Required: true, Size: 1024, InstalledSize: 4096}}
+
+	out := r.RenderSummaryPage(config, components, "/opt/sizeapp").Render()
+	if !strings.Contains(out, formatSize(4096)) || !strings.Contains(out, `class="download-size">`+formatSize(1024)) {
+		t.Error("summary should show the size on disk and the download size separately")
+	}
+
+	components[0].InstalledSize = 0
+	if out := r.RenderSummaryPage(config, components, "/opt/sizeapp").Render(); strings.Contains(out, "download-size") {
+		t.Error("summary should not repeat a download size equal to the size on disk")
+	}
+}
+
+func TestSSRSummaryModifyPlan(t *testing.T) {
+	config := &core.Config{AppName: "ModifyApp"}
+	r := NewSSRRenderer()
+	components := []core.Component{{ID: "core", Name: "Core", Required: true}}
+
+	if out := r.RenderSummaryPage(config, components, "/opt/modifyapp").Render(); strings.Contains(out, "modify-plan") {
+		t.Error("the summary of a new installation should not list changes")
+	}
+
+	r.SetModifyPlan(&core.ModifyPlan{
+		Install: []core.Component{{ID: "sdk", Name: "SDK"}, {ID: "plugins", Name: "Plugins"}},
+		Remove:  []core.Component{{ID: "docs", Name: "Docs"}},
+	})
+	out := r.RenderSummaryPage(config, components, "/opt/modifyapp").Render()
+	for _, want := range []string{"modify-plan", "To install: SDK, Plugins", "To remove: Docs"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q", want)
+		}
+	}
+
+	r.SetModifyPlan(&core.ModifyPlan{})
+	if out := r.RenderSummaryPage(config, components, "/opt/modifyapp").Render(); !strings.Contains(out, "Nothing to change") {
+		t.Error("an unchanged selection should say there is nothing to change")
+	}
+}
+
+func TestSSRFormPage(t *testing.T) {
+	config := &core.Config{AppName: "FormApp", Version: "1.0.0"}
+	r := NewSSRRenderer()
+	form := &core.UIStateConfig{
+		Title:       "Database",
+		Description: "Connection to the database server",
+		Fields: []core.UIField{
+			{ID: "host", Label: "Host", Type: core.FieldTypeText, Required: true, Validation: `^[a-z.]+$`},
+			{ID: "port", Label: "Port", Type: core.FieldTypeNumber},
+			{ID: "ssl", Label: "Use TLS", Type: core.FieldTypeCheckbox, Help: "Encrypts the connection"},
+			{ID: "engine", Label: "Engine", Type: core.FieldTypeDropdown, Options: []core.FieldOption{
+				{ID: "postgres", Label: "PostgreSQL"}, {ID: "oracle", Label: "Oracle", Disabled: true},
+			}},
+			{ID: "mode", Label: "Mode", Type: core.FieldTypeRadio, Options: []core.FieldOption{{ID: "local"}, {ID: "remote"}}},
+			{ID: "schemas", Label: "Schemas", Type: core.FieldTypeList},
+			{ID: "password", Label: "Password", Type: core.FieldTypePassword},
+		},
+	}
+	values := controller.FormValues{
+		"host": "db.example", "port": 5432, "ssl": true, "engine": "postgres", "mode": "remote",
+		"schemas": []string{"app", "audit"}, "password": "secret",
+	}
+
+	out := r.RenderFormPage(config, form, values).Render()
+	for _, want := range []string{
+		"Database", "Connection to the database server", `id="stateForm"`,
+		`<label for="field-host">Host *</label>`, `name="host"`, `value="db.example"`, `pattern="^[a-z.]+$"`, "required",
+		`type="number"`, `value="5432"`,
+		`type="checkbox"`, "checked", `class="field-help"`, "Encrypts the connection",
+		"<select", `value="postgres"`, "selected", "disabled", "PostgreSQL",
+		`type="radio"`, `value="remote"`, "<legend>Mode</legend>",
+		"<textarea", "app\naudit",
+		`type="password"`, `id="error-host"`, "/api/form", "installerSetFormValues",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("form page lacks %q", want)
+		}
+	}
+	if strings.Contains(out, "secret") {
+		t.Error("form page must not render passwords")
+	}
+}
+
+func TestSSRRebootNotice(t *testing.T) {
+	config := &core.Config{AppName: "RebootApp"}
+	r := NewSSRRenderer()
+
+	if out := r.RenderCompletionPage(config, true).Render(); strings.Contains(out, "reboot-notice") {
+		t.Error("completion page offers a restart nobody needs")
+	}
+
+	r.SetReboot([]string{"Driver was installed"})
+	out := r.RenderCompletionPage(config, true).Render()
+	for _, want := range []string{
+		`class="reboot-notice"`, "A restart is required", "<li>Driver was installed</li>",
+		`id="btnReboot"`, `data-action="reboot"`, `data-kind="reboot"`, "confirm(", `id="actionStatus"`,
+		`id="btnFinish"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("completion page lacks %q", want)
+		}
+	}
+	if out := r.RenderCompletionPage(config, false).Render(); strings.Contains(out, "reboot-notice") {
+		t.Error("failed installation should not offer a restart")
+	}
+}
+
+func TestSSRInstallProgressPage(t *testing.T) {
+	config := &core.Config{AppName: "ProgressApp"}
+	r := NewSSRRenderer()
+	progress := &core.Progress{ComponentName: "Core", ComponentProgress: 0.4, OverallProgress: 0.65, Message: "Copying app.bin"}
+
+	out := r.RenderInstallProgressPage(config, progress).Render()
+	for _, want := range []string{
+		"Installing Core: 40% (overall 65%)", "Copying app.bin", "65% complete",
+		`aria-valuenow="65"`, `class="progress-bar" style="width: 65%;"`,
+		`class="progress component"`, `aria-valuenow="40"`, `style="width: 40%;"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("progress page lacks %q", want)
+		}
+	}
+
+	if out := r.RenderProgressPage(config, 50, "Installing...").Render(); strings.Contains(out, "component-progress") {
+		t.Error("progress page without a component shows a component bar")
+	}
+
+	// Cancelling is offered only where the installer allows it
+	if strings.Contains(out, "btnCancel") {
+		t.Error("progress page offers to cancel without AllowInstallCancel")
+	}
+	config.AllowInstallCancel = true
+	if out := r.RenderInstallProgressPage(config, progress).Render(); !strings.Contains(out, `id="btnCancel"`) {
+		t.Error("progress page lacks the cancel button")
+	}
+}
+
+func TestSSRInstallProgressPhase(t *testing.T) {
+	config := &core.Config{AppName: "PhaseApp"}
+	r := NewSSRRenderer()
+
+	progress := &core.Progress{Phase: core.PhaseRegistration, OverallProgress: 1.0, Message: "Registering components..."}
+	out := r.RenderInstallProgressPage(config, progress).Render()
+	if !strings.Contains(out, `<p class="phase">Phase 3 of 4: Registering components</p>`) {
+		t.Errorf("progress page lacks the phase: %s", out)
+	}
+	if strings.Contains(out, "component-progress") {
+		t.Error("phase without a component shows a component bar")
+	}
+
+	progress = &core.Progress{Phase: core.PhaseFileCopy, ComponentName: "Core", ComponentProgress: 0.5}
+	if out := r.RenderInstallProgressPage(config, progress).Render(); !strings.Contains(out, "Phase 2 of 4: Installing files") ||
+		!strings.Contains(out, "component-progress") {
+		t.Error("file copy phase lacks the phase or the component bar")
+	}
+}
+
+func TestSSRLicenseScrollRequired(t *testing.T) {
+	config := &core.Config{AppName: "CompliantApp"}
+	r := NewSSRRenderer()
+
+	out := r.RenderLicensePage(config, "Terms").Render()
+	if strings.Contains(out, `data-scroll="required"`) || strings.Contains(out, "license-scroll-hint") {
+		t.Error("the license should be accepted without scrolling by default")
+	}
+
+	config.RequireLicenseScroll = true
+	out = r.RenderLicensePage(config, "Terms").Render()
+	for _, want := range []string{
+		`id="acceptLicense-text"`,
+		`data-scroll="required"`,
+		"license-scroll-hint",
+		"addEventListener('scroll', scrolledToEnd)",
+		"text.scrollTop + text.clientHeight < text.scrollHeight - 2",
+		"acceptCheckbox.removeAttribute('disabled')",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("license page missing %q", want)
+		}
+	}
+	if !regexp.MustCompile(`<input[^>]*id="acceptLicense"[^>]*disabled="true"`).MatchString(out) {
+		t.Error("the accept checkbox should start disabled")
+	}
+}
+
+func TestSSRNoscriptForms(t *testing.T) {
+	config := &core.Config{AppName: "PlainApp"}
+	r := NewSSRRenderer()
+
+	out := r.RenderWelcomePage(config).Render()
+	for _, want := range []string{
+		"<noscript>",
+		`<form method="POST" action="/api/next"`,
+		`<form method="POST" action="/api/cancel"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("welcome page missing %q", want)
+		}
+	}
+	if strings.Contains(out, `action="/api/prev"`) {
+		t.Error("the first page should offer no Back form")
+	}
+
+	out = r.RenderLicensePage(config, "Terms").Render()
+	if !strings.Contains(out, `action="/api/license"`) ||
+		!regexp.MustCompile(`<input[^>]*name="accepted"[^>]*value="true"`).MatchString(out) {
+		t.Error("license page should post its acceptance to /api/license")
+	}
+	out = r.RenderLicensesPage(config, []core.ApplicableLicense{{Text: "Terms"}, {ComponentID: "gpl-tools", Title: "GPL", Text: "GPL"}}).Render()
+	if !regexp.MustCompile(`<input[^>]*name="component"[^>]*value="gpl-tools"`).MatchString(out) {
+		t.Error("the license form should post the components whose licenses it accepts")
+	}
+
+	for name, page := range map[string]*Document{
+		"locale":       r.RenderLanguagePage(config, []string{"en", "de"}, "en"),
+		"pathScope":    r.RenderPathScopePage(config, core.PathScopeNameUser, true),
+		"installScope": r.RenderInstallScopePage(config, core.ScopePerUser.String(), true),
+	} {
+		if !regexp.MustCompile(`<input[^>]*name="` + name + `"[^>]*form="noscriptNext"`).MatchString(page.Render()) {
+			t.Errorf("the %s choice should join the form posted with Next", name)
+		}
+	}
+	config.Components = []core.Component{{ID: "docs", Name: "Docs", Selected: true}}
+	out = r.RenderComponentsPage(config).Render()
+	if !regexp.MustCompile(`<input[^>]*name="component"[^>]*value="docs"[^>]*checked="checked"[^>]*form="noscriptNext"`).MatchString(out) ||
+		!regexp.MustCompile(`<input[^>]*name="components"[^>]*value="posted"`).MatchString(out) {
+		t.Error("the component checkboxes should join the form posted with Next")
+	}
+	config.Components = nil
+
+	out = r.RenderInstallPathPage(config, `C:\PlainApp`).Render()
+	if !strings.Contains(out, `action="/api/path"`) ||
+		!regexp.MustCompile(`<input[^>]*name="path"[^>]*form="noscriptNext"`).MatchString(out) {
+		t.Error("the path input should join the form posted to /api/path")
+	}
+	if !strings.Contains(out, `action="/api/prev"`) {
+		t.Error("install path page should offer a Back form")
+	}
+
+	config.ForwardOnly = true
+	if out = r.RenderInstallPathPage(config, `C:\PlainApp`).Render(); strings.Contains(out, `action="/api/prev"`) {
+		t.Error("forward-only installers should offer no Back form")
+	}
+
+	out = r.RenderConfirmDialog(config, "Cancel", "Really?").Render()
+	if !regexp.MustCompile(`<input[^>]*name="answer"[^>]*value="true"`).MatchString(out) {
+		t.Error("confirm dialog should post the answer as a form")
+	}
+}
+
+func TestSSRComponentSearch(t *testing.T) {
+	config := &core.Config{AppName: "BigApp"}
+	for i := 0; i < core.ComponentFilterThreshold; i++ {
+		config.Components = append(config.Components, core.Component{ID: fmt.Sprintf("driver%d", i), Name: fmt.Sprintf("Driver %d", i)})
+	}
+	config.Components[3].Name = "PostgreSQL Driver"
+	config.Components[7].Description = "Connects to MySQL"
+	r := NewSSRRenderer()
+
+	out := r.RenderComponentsPage(config).Render()
+	if !strings.Contains(out, `id="componentSearch"`) || !strings.Contains(out, "getAttribute('data-search').includes(term)") {
+		t.Fatal("large component lists should offer a filter")
+	}
+
+	// The page filters on the data attributes as MatchesFilter does
+	attrs := regexp.MustCompile(`data-search="([^"]*)"`).FindAllStringSubmatch(out, -1)
+	if len(attrs) != len(config.Components) {
+		t.Fatalf("found %d data-search attributes, want %d", len(attrs), len(config.Components))
+	}
+	for _, term := range []string{"sql", "DRIVER 1", "connects", "none"} {
+		for i, attr := range attrs {
+			shown := strings.Contains(attr[1], strings.ToLower(term))
+			if want := config.Components[i].MatchesFilter(term); shown != want {
+				t.Errorf("term %q shows %s: %v, want %v", term, config.Components[i].ID, shown, want)
+			}
+		}
+	}
+
+	config.Components = config.Components[:2]
+	if out = r.RenderComponentsPage(config).Render(); strings.Contains(out, `id="componentSearch"`) {
+		t.Error("short component lists need no filter")
+	}
+}
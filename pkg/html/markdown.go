@@ -0,0 +1,166 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the markdown subset used for release notes as a
+// <div class="markdown">: # headings, paragraphs, "-", "*" and "1." lists,
+// ``` code blocks, and inline **strong**, *emphasis*, `code` and
+// [links](https://...). All text is escaped, so HTML in the source shows as
+// written; links other than http, https and mailto render as their text.
+func Markdown(source string) *Element {
+	root := DIV().Class("markdown")
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	var paragraph []string
+	var list, item *Element
+	ordered := false
+	flush := func() {
+		if len(paragraph) > 0 {
+			root.Child(markdownInline(P(), strings.Join(paragraph, " ")))
+			paragraph = nil
+		}
+		if list != nil {
+			root.Child(list)
+			list, item = nil, nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "":
+			flush()
+
+		case strings.HasPrefix(line, "```"):
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			root.Child(NewElement("pre").Child(CODE(strings.Join(code, "\n"))))
+
+		case markdownHeadingLevel(line) > 0:
+			flush()
+			level := markdownHeadingLevel(line)
+			root.Child(markdownInline(NewElement(fmt.Sprintf("h%d", level)), strings.TrimSpace(line[level:])))
+
+		default:
+			text, isOrdered, isItem := markdownListItem(line)
+			switch {
+			case isItem:
+				if len(paragraph) > 0 || (list != nil && isOrdered != ordered) {
+					flush()
+				}
+				if list == nil {
+					list, ordered = UL(), isOrdered
+					if isOrdered {
+						list = OL()
+					}
+				}
+				item = markdownInline(LI(), text)
+				list.Child(item)
+			case item != nil:
+				// Continuation of the list item
+				markdownInline(item.Text(" "), line)
+			default:
+				paragraph = append(paragraph, line)
+			}
+		}
+	}
+	flush()
+	return root
+}
+
+// markdownHeadingLevel returns the level of a "# Heading" line, 0 for other lines
+func markdownHeadingLevel(line string) int {
+	level := 0
+	for level < len(line) && level < 6 && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level >= len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+// markdownListItem returns the text of a "- item", "* item" or "1. item" line
+func markdownListItem(line string) (text string, ordered, ok bool) {
+	if len(line) > 2 && (line[0] == '-' || line[0] == '*' || line[0] == '+') && line[1] == ' ' {
+		return strings.TrimSpace(line[2:]), false, true
+	}
+	digits := 0
+	for digits < len(line) && '0' <= line[digits] && line[digits] <= '9' {
+		digits++
+	}
+	if digits > 0 && strings.HasPrefix(line[digits:], ". ") {
+		return strings.TrimSpace(line[digits+2:]), true, true
+	}
+	return "", false, false
+}
+
+// markdownInline adds text with its inline styles to e
+func markdownInline(e *Element, text string) *Element {
+	for text != "" {
+		idx := strings.IndexAny(text, "`*[")
+		if idx < 0 {
+			e.Text(text)
+			break
+		}
+		if idx > 0 {
+			e.Text(text[:idx])
+			text = text[idx:]
+		}
+		if span, n := markdownSpan(text); n > 0 {
+			e.Child(span)
+			text = text[n:]
+			continue
+		}
+		e.Text(text[:1])
+		text = text[1:]
+	}
+	return e
+}
+
+// markdownSpan renders the inline style starting s and returns its length,
+// 0 if s does not start a complete one
+func markdownSpan(s string) (*Element, int) {
+	switch {
+	case s[0] == '`':
+		if end := strings.IndexByte(s[1:], '`'); end > 0 {
+			return CODE(s[1 : end+1]), end + 2
+		}
+	case strings.HasPrefix(s, "**"):
+		if end := strings.Index(s[2:], "**"); end > 0 {
+			return markdownInline(NewElement("strong"), s[2:end+2]), end + 4
+		}
+	case s[0] == '*':
+		if end := strings.IndexByte(s[1:], '*'); end > 0 {
+			return markdownInline(NewElement("em"), s[1:end+1]), end + 2
+		}
+	case s[0] == '[':
+		mid := strings.Index(s, "](")
+		if mid < 0 {
+			break
+		}
+		end := strings.IndexByte(s[mid+2:], ')')
+		if end < 0 {
+			break
+		}
+		label, href := s[1:mid], s[mid+2:mid+2+end]
+		n := mid + 3 + end
+		if !markdownSafeLink(href) {
+			return markdownInline(SPAN(), label), n
+		}
+		return markdownInline(A(href), label).Attr("target", "_blank").Attr("rel", "noopener"), n
+	}
+	return nil, 0
+}
+
+// markdownSafeLink reports whether href may be rendered as a link
+func markdownSafeLink(href string) bool {
+	lower := strings.ToLower(href)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "mailto:")
+}
@@ -2,16 +2,42 @@
 package html
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
 )
 
 // SSRRenderer provides server-side rendering using the HTML builder
 type SSRRenderer struct {
 	// Configuration for the renderer
-	theme string
+	theme        string
+	primaryLabel string
+	help         string
+	changeLinks  []ChangeLink
+	warnings     []string
+	reboot       []string
+	notices      []string
+	modifyPlan   *core.ModifyPlan
+	localizer    *core.Localizer
 }
 
+// ChangeLink offers to return from the summary page to the step where a
+// setting was entered
+type ChangeLink struct {
+	State wizard.State // Step the link jumps to
+	Kind  string       // Setting of the step, ChangeInstallPath or ChangeComponents; empty for other steps
+	Label string       // Name of the step
+}
+
+// Kinds of ChangeLink shown next to their settings on the summary page; links
+// of other kinds are listed under Other Settings
+const (
+	ChangeInstallPath = "install_path"
+	ChangeComponents  = "components"
+)
+
 // NewSSRRenderer creates a new SSR renderer
 func NewSSRRenderer() *SSRRenderer {
 	return &SSRRenderer{
@@ -24,10 +50,376 @@ func (r *SSRRenderer) SetTheme(theme string) {
 	r.theme = theme
 }
 
+// SetPrimaryLabel sets the label of the primary button on the next rendered
+// page, usually the controller's PrimaryLabel for the current state
+func (r *SSRRenderer) SetPrimaryLabel(label string) {
+	r.primaryLabel = label
+}
+
+// SetHelp sets the help text offered in the header of the next rendered
+// page, usually the controller's HelpFor the current state
+func (r *SSRRenderer) SetHelp(help string) {
+	r.help = help
+}
+
+// SetChangeLinks sets the steps the next rendered summary page offers to
+// change, usually from the controller's ChangeTargets
+func (r *SSRRenderer) SetChangeLinks(links []ChangeLink) {
+	r.changeLinks = links
+}
+
+// SetWarnings sets the warnings shown on the next rendered welcome page,
+// usually the controller's RequirementWarnings
+func (r *SSRRenderer) SetWarnings(warnings []string) {
+	r.warnings = warnings
+}
+
+// SetReboot sets why a restart is needed, shown with an offer to restart on
+// the next rendered completion page, usually the summary's RebootReasons
+func (r *SSRRenderer) SetReboot(reasons []string) {
+	r.reboot = reasons
+}
+
+// SetCompletionWarnings sets what did not go as planned, such as skipped
+// components, shown on the next rendered completion page, usually the
+// summary's Warnings
+func (r *SSRRenderer) SetCompletionWarnings(warnings []string) {
+	r.notices = warnings
+}
+
+// SetModifyPlan sets the changes to an existing installation shown on the
+// next rendered summary page, usually the controller's ModifyPlan
+func (r *SSRRenderer) SetModifyPlan(plan *core.ModifyPlan) {
+	r.modifyPlan = plan
+}
+
+// SetLocalizer sets the localizer that translates the rendered pages,
+// usually the controller's Localizer. Without one the pages are in
+// core.DefaultLocale.
+func (r *SSRRenderer) SetLocalizer(localizer *core.Localizer) {
+	r.localizer = localizer
+}
+
+// t translates the message key, see core.Localizer.T
+func (r *SSRRenderer) t(key string, args ...interface{}) string {
+	return r.localizer.T(key, args...)
+}
+
+// withChangeLink adds a "Change" button to item returning to the step of
+// the change link of kind, if there is one
+func (r *SSRRenderer) withChangeLink(item *Element, kind string) *Element {
+	for _, link := range r.changeLinks {
+		if link.Kind == kind {
+			return item.Child(changeButton(link))
+		}
+	}
+	return item
+}
+
+func changeButton(link ChangeLink) *Element {
+	return BUTTON("Change").Class("change-link").Data("state", string(link.State)).
+		Title("Change " + link.Label)
+}
+
+// nextLabel returns the label of the primary button
+func (r *SSRRenderer) nextLabel() string {
+	if r.primaryLabel == "" {
+		return r.t(core.MsgButtonNext)
+	}
+	return r.primaryLabel
+}
+
+// header renders the page header, starting with the configured logo and
+// offering the help of the page
+func (r *SSRRenderer) header(config *core.Config, content ...*Element) *Element {
+	return r.pageHeader(config, r.help, content...)
+}
+
+// pageHeader renders a page header with the given help, none if empty. The
+// help is shown as a tooltip and, on click, by the installer. Forward-only
+// installers hide the Back button of the page.
+func (r *SSRRenderer) pageHeader(config *core.Config, help string, content ...*Element) *Element {
+	header := HEADER().Class("header")
+	if config.ForwardOnly {
+		header.Child(STYLE("#btnBack { display: none; }"))
+	}
+	if config.Logo != nil {
+		header.Child(DIV().Class("logo").Child(IMG(config.Logo.DataURI(), config.AppName)))
+	}
+	if help != "" {
+		header.Child(DIV().Class("help").Children(
+			BUTTON("?").Class("help-button").ID("btnHelp").Title(help).AriaLabel("Help").
+				OnClick("typeof installerHelp === 'function' ? installerHelp() : fetch('/api/help', { method: 'POST' }).then(() => window.location.reload())"),
+			DIV().Class("help-text").Role("tooltip").Text(help),
+		))
+	}
+	return header.Children(content...)
+}
+
+// noscriptButtons renders the buttons of a page as forms posted to the
+// installer, which replace them in browsers with JavaScript disabled, where
+// buttons relying on fetch do nothing. next is the primary button, see
+// noscriptNext; back adds a Back button unless the installer is forward-only.
+func (r *SSRRenderer) noscriptButtons(config *core.Config, back bool, next *Element) *Element {
+	var forms []*Element
+	if back && !config.ForwardOnly {
+		forms = append(forms, postForm("/api/prev", BUTTON("Back").Class("button")))
+	}
+	return noscriptForms(append(forms, next, postForm("/api/cancel", BUTTON(r.t(core.MsgButtonCancel)).Class("button")))...)
+}
+
+// noscriptForms renders forms in place of the buttons of a page for browsers
+// with JavaScript disabled
+func noscriptForms(forms ...*Element) *Element {
+	return NOSCRIPT().Children(
+		STYLE(".buttons:not(.noscript-buttons) { display: none; }"),
+		DIV().Class("buttons noscript-buttons").Style("text-align: center; margin-top: 40px;").Children(forms...),
+	)
+}
+
+// noscriptNext renders the primary button posting fields to action, in the
+// form "noscriptNext" inputs of the page can join
+func (r *SSRRenderer) noscriptNext(action string, fields ...*Element) *Element {
+	return postForm(action, append(fields, BUTTON(r.nextLabel()).Class("button primary"))...).ID("noscriptNext")
+}
+
+// postForm renders a form posted to action, shown inline with the buttons
+func postForm(action string, children ...*Element) *Element {
+	return FORM().Attr("method", "POST").Attr("action", action).Style("display: inline;").Children(children...)
+}
+
+// passwordToggle switches the password input next to the button between
+// hidden and shown
+const passwordToggle = `const input = this.parentElement.querySelector('input');
+const show = input.type === 'password';
+input.type = show ? 'text' : 'password';
+this.textContent = show ? 'Hide' : 'Show';
+this.setAttribute('aria-pressed', show);
+this.setAttribute('aria-label', show ? 'Hide password' : 'Show password');`
+
+// saveDiagnostics asks the installer to save a diagnostics archive and shows
+// where it was written
+const saveDiagnostics = `const status = document.getElementById('diagnosticsStatus');
+const show = path => { status.textContent = 'Diagnostics saved to ' + path; };
+const failed = () => { status.textContent = 'The diagnostics could not be saved.'; };
+if (typeof installerSaveDiagnostics === 'function') {
+	installerSaveDiagnostics().then(show, failed);
+	return;
+}
+fetch('/api/diagnostics', { method: 'POST' })
+	.then(response => response.ok ? response.json() : Promise.reject())
+	.then(data => show(data.path), failed);`
+
+// viewLog opens the installer log: in the desktop window with the default
+// application, in the browser from the /api/log endpoint
+const viewLog = `if (typeof installerViewLog === 'function') {
+	installerViewLog().then(message => {
+		document.getElementById('diagnosticsStatus').textContent = message;
+	}, error => {
+		document.getElementById('diagnosticsStatus').textContent = 'The log could not be opened' + (error ? ': ' + error : '.');
+	});
+	return;
+}
+window.open('/api/log', '_blank');`
+
+// runCompletionAction asks the installer to perform the completion action of
+// the button and shows the result in the actionStatus element
+const runCompletionAction = `const status = document.getElementById('actionStatus');
+const show = message => { status.textContent = message; };
+const failed = error => { status.textContent = 'The action failed' + (error ? ': ' + error : '.'); };
+const id = this.dataset.action;
+if (this.dataset.kind === 'reboot' && !confirm('Restart the computer now? Save your work in other programs first.')) {
+	return;
+}
+if (typeof installerRunAction === 'function') {
+	installerRunAction(id).then(show, failed);
+	return;
+}
+fetch('/api/action', {
+	method: 'POST',
+	headers: {'Content-Type': 'application/x-www-form-urlencoded'},
+	body: 'id=' + encodeURIComponent(id)
+})
+	.then(response => response.ok ? response.json() : response.text().then(text => Promise.reject(text)))
+	.then(data => show(data.message), failed);`
+
+// completionActions renders a button for each next step config offers on
+// the completion page, nil if there are none
+func completionActions(config *core.Config) *Element {
+	actions := core.CompletionActions(config)
+	if len(actions) == 0 {
+		return nil
+	}
+	buttons := DIV().Class("completion-actions")
+	for _, action := range actions {
+		buttons.Child(BUTTON(action.Label).Attr("type", "button").Class("button completion-action").
+			Attr("data-action", action.ID).Attr("data-kind", string(action.Kind)).
+			OnClick(runCompletionAction))
+	}
+	return DIV().Children(
+		H3("Next steps"),
+		buttons,
+		P().Class("action-status").ID("actionStatus").Role("status"),
+	)
+}
+
+// rebootNotice renders why a restart is needed with a button restarting the
+// computer after confirmation; the finish button leaves it for later
+func rebootNotice(reasons []string) *Element {
+	list := UL()
+	for _, reason := range reasons {
+		list.Child(LI(reason))
+	}
+	return DIV().Class("reboot-notice").Role("alert").Children(
+		STRONG("A restart is required to complete the installation."),
+		list,
+		BUTTON("Restart now").Attr("type", "button").Class("button completion-action").ID("btnReboot").
+			Attr("data-action", core.RebootActionID).Attr("data-kind", string(core.ActionReboot)).
+			OnClick(runCompletionAction),
+	)
+}
+
+// hasCompletionAction reports whether config offers an action of kind
+func hasCompletionAction(config *core.Config, kind core.CompletionActionKind) bool {
+	for _, action := range core.CompletionActions(config) {
+		if action.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// diagnosticsButton renders the button saving a diagnostics archive for
+// reporting a problem; the path is shown in the diagnosticsStatus element
+func diagnosticsButton() *Element {
+	return BUTTON("Save diagnostics").Attr("type", "button").Class("button").ID("btnDiagnostics").
+		OnClick(saveDiagnostics)
+}
+
+// viewLogButton renders the button opening the installer log; messages are
+// shown in the diagnosticsStatus element
+func viewLogButton() *Element {
+	return BUTTON("View log").Attr("type", "button").Class("button").ID("btnViewLog").
+		OnClick(viewLog)
+}
+
+// finishForms renders the forms of a completion or error page for browsers
+// without JavaScript: a link to the log, if config has one, and finish
+func finishForms(config *core.Config, finish *Element) *Element {
+	if config.LogFile == "" {
+		return noscriptForms(postForm("/api/finish", finish))
+	}
+	return noscriptForms(A("/api/log", "View log").Attr("target", "_blank").Class("button"), postForm("/api/finish", finish))
+}
+
+// diagnosticsStatus renders where the result of diagnosticsButton is shown
+func diagnosticsStatus() *Element {
+	return P().Class("diagnostics-status").ID("diagnosticsStatus").Role("status")
+}
+
+// PasswordField renders a labelled password input with a button that shows
+// and hides what was typed. No value is rendered, so a stored password never
+// ends up in the page source.
+func PasswordField(id, label string) *Element {
+	return DIV().Class("password-field").Children(
+		LABEL(label).Attr("for", id),
+		DIV().Class("password-control").Children(
+			INPUT("password").ID(id).Name(id).Attr("autocomplete", "off"),
+			BUTTON("Show").Attr("type", "button").Class("password-toggle").
+				AriaControls(id).AriaPressed(false).AriaLabel("Show password").
+				OnClick(passwordToggle),
+		),
+	)
+}
+
+// RenderLanguagePage renders the language selection page. Choosing a locale
+// switches the installer to it and shows the page again in that language.
+func (r *SSRRenderer) RenderLanguagePage(config *core.Config, locales []string, current string) *Document {
+	doc := NewDocument().
+		SetTitle(config.AppName + " - " + r.t(core.MsgLanguageTitle)).
+		SetCharset("utf-8").
+		SetViewport("").
+		AddDefaultSetupKitStyles()
+
+	choices := DIV().Class("language-list").Role("radiogroup").AriaLabel(r.t(core.MsgLanguageTitle))
+	for _, locale := range locales {
+		radio := INPUT("radio").ID("locale-" + locale).Name("locale").Value(locale)
+		if locale == current {
+			radio.Checked()
+		}
+		radio.Attr("form", "noscriptNext")
+		choices.Child(LABEL("").Class("language-option").Attr("lang", locale).Children(
+			radio,
+			SPAN(r.localizer.Name(locale)),
+		))
+	}
+
+	container := DIV().Class("container").Children(
+		r.header(config,
+			DIV().Class("title").Text(r.t(core.MsgLanguageTitle)),
+			DIV().Class("subtitle").Text(r.t(core.MsgLanguagePrompt)),
+		),
+
+		MAIN().Child(choices),
+
+		DIV().Class("buttons").Style("text-align: center; margin-top: 40px;").Children(
+			BUTTON(r.t(core.MsgButtonCancel)).Class("button").ID("btnCancel"),
+			BUTTON(r.nextLabel()).Class("button primary").ID("btnNext"),
+		),
+	)
+
+	container.Child(r.noscriptButtons(config, false, r.noscriptNext("/api/next")))
+	doc.AddToBody(container)
+
+	js := `
+		document.addEventListener('DOMContentLoaded', function() {
+			document.querySelectorAll('input[name="locale"]').forEach(function(radio) {
+				radio.addEventListener('change', function() {
+					// The installer switches the language and shows the page again
+					if (typeof installerSetLocale === 'function') {
+						installerSetLocale(radio.value);
+						return;
+					}
+					fetch('/api/locale', {
+						method: 'POST',
+						headers: {'Content-Type': 'application/x-www-form-urlencoded'},
+						body: 'locale=' + encodeURIComponent(radio.value)
+					}).then(() => window.location.reload());
+				});
+			});
+
+			document.getElementById('btnNext').addEventListener('click', function() {
+				fetch('/api/next', { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'ok') {
+							window.location.reload();
+						}
+					});
+			});
+			document.getElementById('btnCancel').addEventListener('click', function() {
+				// The installer asks for confirmation in its own dialog
+				fetch('/api/cancel', { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'cancelled') {
+							window.close();
+						} else {
+							window.location.reload();
+						}
+					});
+			});
+		});
+	`
+
+	doc.AddJS(js)
+	return doc
+}
+
 // RenderWelcomePage renders the welcome/start page
 func (r *SSRRenderer) RenderWelcomePage(config *core.Config) *Document {
 	doc := NewDocument().
-		SetTitle(config.AppName + " Setup").
+		SetTitle(r.t(core.MsgWelcomeTitle, config.AppName)).
 		SetCharset("utf-8").
 		SetViewport("").
 		AddDefaultSetupKitStyles()
@@ -35,27 +427,29 @@ func (r *SSRRenderer) RenderWelcomePage(config *core.Config) *Document {
 	// Main container
 	container := DIV().Class("container").Children(
 		// Header section
-		HEADER().Class("header").Children(
-			DIV().Class("title").Text(config.AppName + " Setup"),
-			DIV().Class("subtitle").Text("Version: " + config.Version),
-			DIV().Class("version").Text("Publisher: " + config.Publisher),
+		r.header(config,
+			DIV().Class("title").Text(r.t(core.MsgWelcomeTitle, config.AppName)),
+			DIV().Class("subtitle").Text(r.t(core.MsgWelcomeVersion, config.Version)),
+			DIV().Class("version").Text(r.t(core.MsgWelcomePublisher, config.Publisher)),
 		),
 
 		// Welcome content
 		MAIN().Children(
-			P("Welcome to the " + config.AppName + " installation wizard."),
-			P("This wizard will guide you through the installation process."),
+			P(r.t(core.MsgWelcomeIntro, config.AppName)),
+			P(r.t(core.MsgWelcomeGuide)),
+			r.requirementWarnings(),
 			BR(),
-			P("Click Next to continue or Cancel to exit the installer."),
+			P(r.t(core.MsgWelcomeContinue, r.nextLabel(), r.t(core.MsgButtonCancel))),
 		),
 
 		// Button section
 		DIV().Class("buttons").Style("text-align: center; margin-top: 40px;").Children(
-			BUTTON("Cancel").Class("button").ID("btnCancel"),
-			BUTTON("Next").Class("button primary").ID("btnNext"),
+			BUTTON(r.t(core.MsgButtonCancel)).Class("button").ID("btnCancel"),
+			BUTTON(r.nextLabel()).Class("button primary").ID("btnNext"),
 		),
 	)
 
+	container.Child(r.noscriptButtons(config, false, r.noscriptNext("/api/next")))
 	doc.AddToBody(container)
 	
 	// Add JavaScript for button interactions
@@ -78,13 +472,16 @@ func (r *SSRRenderer) RenderWelcomePage(config *core.Config) *Document {
 			
 			if (btnCancel) {
 				btnCancel.addEventListener('click', function() {
-					if (confirm('Are you sure you want to cancel the installation?')) {
-						fetch('/api/cancel', { method: 'POST' })
-							.then(response => response.json())
-							.then(data => {
+					// The installer asks for confirmation in its own dialog
+					fetch('/api/cancel', { method: 'POST' })
+						.then(response => response.json())
+						.then(data => {
+							if (data.status === 'cancelled') {
 								window.close();
-							});
-					}
+							} else {
+								window.location.reload();
+							}
+						});
 				});
 			}
 		});
@@ -94,61 +491,122 @@ func (r *SSRRenderer) RenderWelcomePage(config *core.Config) *Document {
 	return doc
 }
 
+// requirementWarnings renders the warnings set with SetWarnings, an empty
+// element if there are none
+func (r *SSRRenderer) requirementWarnings() *Element {
+	warnings := DIV().Class("requirement-warnings")
+	if len(r.warnings) == 0 {
+		return warnings
+	}
+	list := UL()
+	for _, warning := range r.warnings {
+		list.Child(LI(warning))
+	}
+	return warnings.Role("status").Children(
+		STRONG("This computer is below the recommended system requirements"),
+		list,
+		P("You can install anyway, but the application may run slowly."),
+	)
+}
+
 // RenderLicensePage renders the license agreement page
 func (r *SSRRenderer) RenderLicensePage(config *core.Config, license string) *Document {
+	return r.RenderLicensesPage(config, []core.ApplicableLicense{{Text: license}})
+}
+
+// RenderLicensesPage renders the license agreement page with a checkbox for
+// each license, e.g. the application license and those of selected
+// components. Next is enabled once all are checked.
+func (r *SSRRenderer) RenderLicensesPage(config *core.Config, licenses []core.ApplicableLicense) *Document {
 	doc := NewDocument().
 		SetTitle(config.AppName + " - License Agreement").
 		SetCharset("utf-8").
 		SetViewport("").
 		AddDefaultSetupKitStyles()
 
-	// License text container
-	licenseDiv := DIV().Class("license-text").Style("height: 300px; overflow-y: scroll; padding: 15px; background: rgba(255,255,255,0.1); border-radius: 10px; margin: 20px 0;").Child(
-		PRE(license).Style("white-space: pre-wrap; font-family: monospace; color: #333;"),
-	)
+	content := DIV().Class("licenses")
+	for i, l := range licenses {
+		id, label := "acceptLicense", "I accept the terms of the license agreement"
+		if len(licenses) > 1 || l.ComponentID != "" {
+			id, label = fmt.Sprintf("acceptLicense-%d", i), "I accept the "+l.Title
+			content.Child(H3(l.Title).Class("license-title"))
+		}
 
-	// Acceptance checkbox
-	checkboxDiv := DIV().Class("license-acceptance").Style("margin: 20px 0; text-align: center;").Children(
-		INPUT("checkbox").ID("acceptLicense").Style("margin-right: 10px;"),
-		LABEL("I accept the terms of the license agreement").Attr("for", "acceptLicense"),
-	)
+		// License text container
+		content.Child(DIV().Class("license-text").ID(id+"-text").Style("height: 300px; overflow-y: scroll; padding: 15px; background: rgba(255,255,255,0.1); border-radius: 10px; margin: 20px 0;").Child(
+			PRE(l.Text).Style("white-space: pre-wrap; font-family: monospace; color: #333;"),
+		))
+
+		// Acceptance checkbox
+		checkbox := INPUT("checkbox").ID(id).Class("license-accept").Style("margin-right: 10px;")
+		if l.ComponentID != "" {
+			checkbox.Data("component", l.ComponentID)
+		}
+		if config.RequireLicenseScroll {
+			// Enabled by the script once the text is scrolled to the end
+			checkbox.Attr("disabled", "true").Data("scroll", "required")
+			content.Child(DIV().Class("license-scroll-hint").Style("text-align: center; font-size: 0.9em;").Text("Please scroll to the end of the license to accept it"))
+		}
+		content.Child(DIV().Class("license-acceptance").Style("margin: 20px 0; text-align: center;").Children(
+			checkbox,
+			LABEL(label).Attr("for", id),
+		))
+	}
 
 	// Main container
 	container := DIV().Class("container").Children(
 		// Header
-		HEADER().Class("header").Children(
+		r.header(config,
 			DIV().Class("title").Text("License Agreement"),
 			DIV().Class("subtitle").Text("Please read and accept the license"),
 		),
 
-		// License content
-		licenseDiv,
-
-		// Acceptance
-		checkboxDiv,
+		// License content and acceptance
+		content,
 
 		// Buttons
 		DIV().Class("buttons").Style("text-align: center; margin-top: 40px;").Children(
 			BUTTON("Back").Class("button").ID("btnBack"),
-			BUTTON("Next").Class("button primary").ID("btnNext").Attr("disabled", "true"),
+			BUTTON(r.nextLabel()).Class("button primary").ID("btnNext").Attr("disabled", "true"),
 			BUTTON("Cancel").Class("button").ID("btnCancel"),
 		),
 	)
 
+	container.Child(r.noscriptButtons(config, true, r.noscriptNext("/api/license", noscriptAccept(licenses))))
 	doc.AddToBody(container)
 
 	// Add JavaScript for license acceptance and navigation
 	js := `
 		document.addEventListener('DOMContentLoaded', function() {
-			const acceptCheckbox = document.getElementById('acceptLicense');
+			const acceptCheckboxes = Array.from(document.querySelectorAll('.license-accept'));
 			const btnNext = document.getElementById('btnNext');
 			const btnBack = document.getElementById('btnBack');
 			const btnCancel = document.getElementById('btnCancel');
+			const allAccepted = () => acceptCheckboxes.length > 0 && acceptCheckboxes.every(box => box.checked);
+
+			// Licenses that must be read to the end enable their checkbox
+			// once the text is scrolled to the bottom, or fits without scrolling
+			acceptCheckboxes.filter(box => box.dataset.scroll === 'required').forEach(function(acceptCheckbox) {
+				const text = document.getElementById(acceptCheckbox.id + '-text');
+				const scrolledToEnd = function() {
+					if (text && text.scrollTop + text.clientHeight < text.scrollHeight - 2) {
+						return;
+					}
+					acceptCheckbox.removeAttribute('disabled');
+					if (text) {
+						text.removeEventListener('scroll', scrolledToEnd);
+					}
+				};
+				if (text) {
+					text.addEventListener('scroll', scrolledToEnd);
+				}
+				scrolledToEnd();
+			});
 
 			// Enable/disable Next button based on license acceptance
-			if (acceptCheckbox && btnNext) {
+			acceptCheckboxes.forEach(function(acceptCheckbox) {
 				acceptCheckbox.addEventListener('change', function() {
-					if (this.checked) {
+					if (allAccepted()) {
 						btnNext.removeAttribute('disabled');
 						btnNext.classList.add('primary');
 					} else {
@@ -156,16 +614,18 @@ func (r *SSRRenderer) RenderLicensePage(config *core.Config, license string) *Do
 						btnNext.classList.remove('primary');
 					}
 				});
-			}
+			});
 
 			if (btnNext) {
 				btnNext.addEventListener('click', function() {
-					const accepted = acceptCheckbox && acceptCheckbox.checked;
-					if (accepted) {
+					if (allAccepted()) {
+						const components = acceptCheckboxes
+							.filter(box => box.dataset.component)
+							.map(box => '&component=' + encodeURIComponent(box.dataset.component));
 						fetch('/api/license', {
 							method: 'POST',
 							headers: {'Content-Type': 'application/x-www-form-urlencoded'},
-							body: 'accepted=true'
+							body: 'accepted=true' + components.join('')
 						})
 						.then(response => response.json())
 						.then(data => {
@@ -191,13 +651,16 @@ func (r *SSRRenderer) RenderLicensePage(config *core.Config, license string) *Do
 
 			if (btnCancel) {
 				btnCancel.addEventListener('click', function() {
-					if (confirm('Are you sure you want to cancel the installation?')) {
-						fetch('/api/cancel', { method: 'POST' })
-							.then(response => response.json())
-							.then(data => {
+					// The installer asks for confirmation in its own dialog
+					fetch('/api/cancel', { method: 'POST' })
+						.then(response => response.json())
+						.then(data => {
+							if (data.status === 'cancelled') {
 								window.close();
-							});
-					}
+							} else {
+								window.location.reload();
+							}
+						});
 				});
 			}
 		});
@@ -207,6 +670,303 @@ func (r *SSRRenderer) RenderLicensePage(config *core.Config, license string) *Do
 	return doc
 }
 
+// noscriptAccept renders the acceptance of the licenses for browsers with
+// JavaScript disabled, which cannot enable Next from the checkboxes of the page
+func noscriptAccept(licenses []core.ApplicableLicense) *Element {
+	label := "I accept the terms of the license agreement"
+	if len(licenses) > 1 {
+		label = "I accept the license agreements"
+	}
+	accept := DIV().Class("license-acceptance-noscript").Style("margin-bottom: 20px;").Children(
+		STYLE(".licenses > div:not(.license-text) { display: none; }"),
+		INPUT("checkbox").ID("noscriptAccept").Name("accepted").Value("true").Required().Style("margin-right: 10px;"),
+		LABEL(label).Attr("for", "noscriptAccept"),
+	)
+	// The one checkbox accepts the licenses of the components as well
+	for _, l := range licenses {
+		if l.ComponentID != "" {
+			accept.Child(INPUT("hidden").Name("component").Value(l.ComponentID))
+		}
+	}
+	return accept
+}
+
+// RenderReleaseNotesPage renders what changed since the installed version,
+// given as markdown, when an older installation is updated
+func (r *SSRRenderer) RenderReleaseNotesPage(config *core.Config, installedVersion, notes string) *Document {
+	doc := NewDocument().
+		SetTitle(config.AppName + " - What's New").
+		SetCharset("utf-8").
+		SetViewport("").
+		AddDefaultSetupKitStyles()
+
+	container := DIV().Class("container").Children(
+		r.header(config,
+			DIV().Class("title").Text("What's New"),
+			DIV().Class("subtitle").Text("Updating from version "+installedVersion+" to "+config.Version),
+		),
+
+		Markdown(notes).Class("markdown release-notes"),
+
+		DIV().Class("buttons").Style("text-align: center; margin-top: 40px;").Children(
+			BUTTON("Back").Class("button").ID("btnBack"),
+			BUTTON(r.nextLabel()).Class("button primary").ID("btnNext"),
+			BUTTON("Cancel").Class("button").ID("btnCancel"),
+		),
+	)
+
+	container.Child(r.noscriptButtons(config, true, r.noscriptNext("/api/next")))
+	doc.AddToBody(container)
+
+	js := `
+		document.addEventListener('DOMContentLoaded', function() {
+			const navigate = function(action) {
+				fetch('/api/' + action, { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'ok') {
+							window.location.reload();
+						}
+					});
+			};
+
+			document.getElementById('btnNext').addEventListener('click', function() {
+				navigate('next');
+			});
+			document.getElementById('btnBack').addEventListener('click', function() {
+				navigate('prev');
+			});
+			document.getElementById('btnCancel').addEventListener('click', function() {
+				// The installer asks for confirmation in its own dialog
+				fetch('/api/cancel', { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'cancelled') {
+							window.close();
+						} else {
+							window.location.reload();
+						}
+					});
+			});
+		});
+	`
+
+	doc.AddJS(js)
+	return doc
+}
+
+// RenderPathScopePage renders the choice between the user and the system
+// PATH for the directories in config.PathConfig, with scope preselected
+func (r *SSRRenderer) RenderPathScopePage(config *core.Config, scope string, elevated bool) *Document {
+	doc := NewDocument().
+		SetTitle(config.AppName + " - PATH").
+		SetCharset("utf-8").
+		SetViewport("").
+		AddDefaultSetupKitStyles()
+
+	var dirs []string
+	if config.PathConfig != nil {
+		dirs = config.PathConfig.Dirs
+	}
+
+	choices := DIV().Class("path-scope-list").Role("radiogroup").AriaLabel("PATH")
+	for _, option := range []struct{ scope, label, note string }{
+		{core.PathScopeNameUser, "User PATH", "Only for your account"},
+		{core.PathScopeNameSystem, "System PATH", "For all users, needs administrator rights"},
+	} {
+		radio := INPUT("radio").ID("path-scope-" + option.scope).Name("pathScope").Value(option.scope)
+		if option.scope == scope {
+			radio.Checked()
+		}
+		radio.Attr("form", "noscriptNext")
+		choices.Child(LABEL("").Class("path-scope-option").Children(
+			radio,
+			SPAN(option.label).Style("font-weight: bold;"),
+			SPAN(" - "+option.note),
+		))
+	}
+
+	content := MAIN().Children(
+		P("The installer adds "+strings.Join(dirs, ", ")+" to PATH.").Class("path-dirs"),
+		choices,
+	)
+	if !elevated {
+		content.Child(P("You will be asked to allow elevation if you choose the system PATH.").Class("path-elevation-note"))
+	}
+
+	container := DIV().Class("container").Children(
+		r.header(config,
+			DIV().Class("title").Text("PATH"),
+			DIV().Class("subtitle").Text("Choose the PATH to add the application to"),
+		),
+
+		content,
+
+		DIV().Class("buttons").Style("text-align: center; margin-top: 40px;").Children(
+			BUTTON("Back").Class("button").ID("btnBack"),
+			BUTTON(r.nextLabel()).Class("button primary").ID("btnNext"),
+			BUTTON("Cancel").Class("button").ID("btnCancel"),
+		),
+	)
+
+	container.Child(r.noscriptButtons(config, true, r.noscriptNext("/api/next")))
+	doc.AddToBody(container)
+
+	js := `
+		document.addEventListener('DOMContentLoaded', function() {
+			const navigate = function(action) {
+				fetch('/api/' + action, { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'ok') {
+							window.location.reload();
+						}
+					});
+			};
+
+			document.querySelectorAll('input[name="pathScope"]').forEach(function(radio) {
+				radio.addEventListener('change', function() {
+					if (typeof installerSetPathScope === 'function') {
+						installerSetPathScope(radio.value);
+						return;
+					}
+					fetch('/api/path-scope', {
+						method: 'POST',
+						headers: {'Content-Type': 'application/x-www-form-urlencoded'},
+						body: 'scope=' + encodeURIComponent(radio.value)
+					});
+				});
+			});
+
+			document.getElementById('btnNext').addEventListener('click', function() {
+				navigate('next');
+			});
+			document.getElementById('btnBack').addEventListener('click', function() {
+				navigate('prev');
+			});
+			document.getElementById('btnCancel').addEventListener('click', function() {
+				// The installer asks for confirmation in its own dialog
+				fetch('/api/cancel', { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'cancelled') {
+							window.close();
+						} else {
+							window.location.reload();
+						}
+					});
+			});
+		});
+	`
+
+	doc.AddJS(js)
+	return doc
+}
+
+// RenderInstallScopePage renders the choice between installing for all users
+// and just for the current one, with scope, "per-user" or "per-machine",
+// preselected
+func (r *SSRRenderer) RenderInstallScopePage(config *core.Config, scope string, elevated bool) *Document {
+	doc := NewDocument().
+		SetTitle(config.AppName + " - Installation Scope").
+		SetCharset("utf-8").
+		SetViewport("").
+		AddDefaultSetupKitStyles()
+
+	choices := DIV().Class("install-scope-list").Role("radiogroup").AriaLabel("Installation scope")
+	for _, option := range []struct{ scope, label, note string }{
+		{core.ScopePerMachine.String(), "All users", "Everyone using this computer, needs administrator rights"},
+		{core.ScopePerUser.String(), "Just me", "Only for your account"},
+	} {
+		radio := INPUT("radio").ID("install-scope-" + option.scope).Name("installScope").Value(option.scope)
+		if option.scope == scope {
+			radio.Checked()
+		}
+		radio.Attr("form", "noscriptNext")
+		choices.Child(LABEL("").Class("install-scope-option").Children(
+			radio,
+			SPAN(option.label).Style("font-weight: bold;"),
+			SPAN(" - "+option.note),
+		))
+	}
+
+	content := MAIN().Children(
+		P("Install "+config.AppName+" for all users of this computer or just for you?"),
+		choices,
+	)
+	if !elevated {
+		content.Child(P("You will be asked to allow elevation if you install for all users.").Class("install-scope-elevation-note"))
+	}
+
+	container := DIV().Class("container").Children(
+		r.header(config,
+			DIV().Class("title").Text("Installation Scope"),
+			DIV().Class("subtitle").Text("Choose who the application is installed for"),
+		),
+
+		content,
+
+		DIV().Class("buttons").Style("text-align: center; margin-top: 40px;").Children(
+			BUTTON("Back").Class("button").ID("btnBack"),
+			BUTTON(r.nextLabel()).Class("button primary").ID("btnNext"),
+			BUTTON("Cancel").Class("button").ID("btnCancel"),
+		),
+	)
+
+	container.Child(r.noscriptButtons(config, true, r.noscriptNext("/api/next")))
+	doc.AddToBody(container)
+
+	js := `
+		document.addEventListener('DOMContentLoaded', function() {
+			const navigate = function(action) {
+				fetch('/api/' + action, { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'ok') {
+							window.location.reload();
+						}
+					});
+			};
+
+			document.querySelectorAll('input[name="installScope"]').forEach(function(radio) {
+				radio.addEventListener('change', function() {
+					if (typeof installerSetInstallScope === 'function') {
+						installerSetInstallScope(radio.value);
+						return;
+					}
+					fetch('/api/install-scope', {
+						method: 'POST',
+						headers: {'Content-Type': 'application/x-www-form-urlencoded'},
+						body: 'scope=' + encodeURIComponent(radio.value)
+					});
+				});
+			});
+
+			document.getElementById('btnNext').addEventListener('click', function() {
+				navigate('next');
+			});
+			document.getElementById('btnBack').addEventListener('click', function() {
+				navigate('prev');
+			});
+			document.getElementById('btnCancel').addEventListener('click', function() {
+				// The installer asks for confirmation in its own dialog
+				fetch('/api/cancel', { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'cancelled') {
+							window.close();
+						} else {
+							window.location.reload();
+						}
+					});
+			});
+		});
+	`
+
+	doc.AddJS(js)
+	return doc
+}
+
 // RenderInstallPathPage renders the installation path selection page
 func (r *SSRRenderer) RenderInstallPathPage(config *core.Config, defaultPath string) *Document {
 	doc := NewDocument().
@@ -219,7 +979,7 @@ func (r *SSRRenderer) RenderInstallPathPage(config *core.Config, defaultPath str
 	pathDiv := DIV().Class("path-selection").Style("margin: 30px 0;").Children(
 		DIV().Class("form-group").Children(
 			LABEL("Installation directory:").Attr("for", "installPath").Style("display: block; margin-bottom: 10px; font-weight: bold;"),
-			INPUT("text").ID("installPath").Attr("value", defaultPath).Style("width: 100%; padding: 10px; border: 1px solid #ccc; border-radius: 5px; font-size: 1rem;"),
+			INPUT("text").ID("installPath").Name("path").Attr("form", "noscriptNext").Attr("value", defaultPath).Style("width: 100%; padding: 10px; border: 1px solid #ccc; border-radius: 5px; font-size: 1rem;"),
 		),
 		DIV().Class("browse-button").Style("margin-top: 10px;").Child(
 			BUTTON("Browse...").Class("button").ID("btnBrowse").Style("padding: 8px 16px;"),
@@ -236,7 +996,7 @@ func (r *SSRRenderer) RenderInstallPathPage(config *core.Config, defaultPath str
 	// Main container
 	container := DIV().Class("container").Children(
 		// Header
-		HEADER().Class("header").Children(
+		r.header(config,
 			DIV().Class("title").Text("Installation Directory"),
 			DIV().Class("subtitle").Text("Choose where to install " + config.AppName),
 		),
@@ -250,11 +1010,12 @@ func (r *SSRRenderer) RenderInstallPathPage(config *core.Config, defaultPath str
 		// Buttons
 		DIV().Class("buttons").Style("text-align: center; margin-top: 40px;").Children(
 			BUTTON("Back").Class("button").ID("btnBack"),
-			BUTTON("Next").Class("button primary").ID("btnNext"),
+			BUTTON(r.nextLabel()).Class("button primary").ID("btnNext"),
 			BUTTON("Cancel").Class("button").ID("btnCancel"),
 		),
 	)
 
+	container.Child(r.noscriptButtons(config, true, r.noscriptNext("/api/path")))
 	doc.AddToBody(container)
 
 	// Add JavaScript for path selection and navigation
@@ -308,13 +1069,16 @@ func (r *SSRRenderer) RenderInstallPathPage(config *core.Config, defaultPath str
 
 			if (btnCancel) {
 				btnCancel.addEventListener('click', function() {
-					if (confirm('Are you sure you want to cancel the installation?')) {
-						fetch('/api/cancel', { method: 'POST' })
-							.then(response => response.json())
-							.then(data => {
+					// The installer asks for confirmation in its own dialog
+					fetch('/api/cancel', { method: 'POST' })
+						.then(response => response.json())
+						.then(data => {
+							if (data.status === 'cancelled') {
 								window.close();
-							});
-					}
+							} else {
+								window.location.reload();
+							}
+						});
 				});
 			}
 		});
@@ -335,7 +1099,7 @@ func (r *SSRRenderer) RenderSummaryPage(config *core.Config, selectedComponents
 	// Calculate totals
 	var totalSize int64
 	for _, comp := range selectedComponents {
-		totalSize += comp.Size
+		totalSize += comp.DiskSize()
 	}
 
 	// Summary sections
@@ -349,13 +1113,13 @@ func (r *SSRRenderer) RenderSummaryPage(config *core.Config, selectedComponents
 			SPAN("Publisher:").Style("font-weight: bold;"),
 			SPAN(config.Publisher),
 			SPAN("Install Path:").Style("font-weight: bold;"),
-			SPAN(installPath),
+			r.withChangeLink(SPAN(installPath), ChangeInstallPath),
 		),
 	)
 
 	// Components list
 	componentsDiv := DIV().Class("summary-section").Style("margin: 20px 0; padding: 20px; background: rgba(255,255,255,0.1); border-radius: 10px;").Children(
-		H3(fmt.Sprintf("Selected Components (%d)", len(selectedComponents))),
+		r.withChangeLink(H3(fmt.Sprintf("Selected Components (%d)", len(selectedComponents))), ChangeComponents),
 	)
 
 	componentsList := UL().Style("list-style: none; padding: 0;")
@@ -367,49 +1131,120 @@ func (r *SSRRenderer) RenderSummaryPage(config *core.Config, selectedComponents
 
 		listItem := LI().Style("margin: 5px 0; display: flex; justify-content: space-between;").Children(
 			SPAN(marker + " " + comp.Name),
-			SPAN(formatSize(comp.Size)).Style("opacity: 0.8;"),
+			SPAN(formatSize(comp.DiskSize())).Style("opacity: 0.8;"),
 		)
 		componentsList.Child(listItem)
 	}
 	componentsDiv.Child(componentsList)
 
+	sections := []*Element{appInfoDiv, componentsDiv}
+
+	// What modifying an existing installation changes
+	if r.modifyPlan != nil {
+		changesDiv := DIV().Class("summary-section modify-plan").Style("margin: 20px 0; padding: 20px; background: rgba(255,255,255,0.1); border-radius: 10px;").Children(
+			H3("Changes"),
+		)
+		for _, line := range r.modifyPlan.Describe() {
+			changesDiv.Child(P(line).Style("margin: 5px 0;"))
+		}
+		sections = append(sections, changesDiv)
+	}
+
+	// Steps of custom states, whose settings the summary does not show
+	var settingsDiv *Element
+	for _, link := range r.changeLinks {
+		if link.Kind == ChangeInstallPath || link.Kind == ChangeComponents {
+			continue
+		}
+		if settingsDiv == nil {
+			settingsDiv = DIV().Class("summary-section").Style("margin: 20px 0; padding: 20px; background: rgba(255,255,255,0.1); border-radius: 10px;").Children(
+				H3("Other Settings"),
+			)
+		}
+		settingsDiv.Child(DIV().Style("margin: 5px 0; display: flex; justify-content: space-between;").Children(
+			SPAN(link.Label),
+			changeButton(link),
+		))
+	}
+	if settingsDiv != nil {
+		sections = append(sections, settingsDiv)
+	}
+
 	// Space requirements
+	spaceGrid := DIV().Style("display: grid; grid-template-columns: 200px 1fr; gap: 10px;").Children(
+		SPAN("Total size required:").Style("font-weight: bold;"),
+		SPAN(formatSize(totalSize)),
+	)
+	// Compressed payloads are smaller than what they expand to
+	if downloadSize := core.SelectedDownloadSize(selectedComponents); downloadSize != totalSize {
+		spaceGrid.Children(
+			SPAN("Download size:").Style("font-weight: bold;"),
+			SPAN(formatSize(downloadSize)).Class("download-size"),
+		)
+	}
+	spaceGrid.Children(
+		SPAN("Available space:").Style("font-weight: bold;"),
+		SPAN("2.5 GB").Style("color: #4CAF50;"), // Simplified - would be dynamic
+	)
 	spaceDiv := DIV().Class("summary-section").Style("margin: 20px 0; padding: 20px; background: rgba(255,255,255,0.1); border-radius: 10px;").Children(
 		H3("Disk Space Requirements"),
-		DIV().Style("display: grid; grid-template-columns: 200px 1fr; gap: 10px;").Children(
-			SPAN("Total size required:").Style("font-weight: bold;"),
-			SPAN(formatSize(totalSize)),
-			SPAN("Available space:").Style("font-weight: bold;"),
-			SPAN("2.5 GB").Style("color: #4CAF50;"), // Simplified - would be dynamic
-		),
+		spaceGrid,
+	)
+
+	// Confirmation message
+	confirmDiv := DIV().Class("confirmation").Style("margin: 30px 0; text-align: center; padding: 15px; background: rgba(255, 193, 7, 0.2); border-radius: 10px;")
+	if config.CreateRestorePoint && !config.Portable && core.RestorePointSupported() {
+		confirmDiv.Child(
+			P("A system restore point will be created before installation.").Class("restore-point-note").Style("margin: 0 0 10px 0;"),
+		)
+	}
+	if config.Portable {
+		confirmDiv.Child(
+			P("Portable installation: nothing outside "+installPath+" is changed.").Class("portable-note").Style("margin: 0 0 10px 0;"),
+		)
+	} else if change := config.PathConfig.Describe(); change != "" {
+		confirmDiv.Child(
+			P(change+".").Class("path-note").Style("margin: 0 0 10px 0;"),
+		)
+	}
+	if privileges := core.PrivilegesFor(config, selectedComponents, installPath); len(privileges) > 0 {
+		privilegeList := UL().Class("privileges").Style("text-align: left; margin: 5px 0 10px 0;")
+		for _, p := range privileges {
+			privilegeList.Child(LI(p.Description))
+		}
+		confirmDiv.Children(
+			P(core.PrivilegesHeading).Style("margin: 0;"),
+			privilegeList,
+			P(core.ElevationNotice).Style("margin: 0 0 10px 0;"),
+		)
+	}
+	confirmDiv.Child(
+		P("Click "+r.nextLabel()+" to begin the installation process.").Style("margin: 0; font-weight: bold;"),
 	)
 
 	// Main container
 	container := DIV().Class("container").Children(
 		// Header
-		HEADER().Class("header").Children(
+		r.header(config,
 			DIV().Class("title").Text("Ready to Install"),
 			DIV().Class("subtitle").Text("Review your installation settings"),
 		),
-
+	).Children(
 		// Summary sections
-		appInfoDiv,
-		componentsDiv,
-		spaceDiv,
-
+		append(sections, spaceDiv)...,
+	).Children(
 		// Warning/confirmation
-		DIV().Class("confirmation").Style("margin: 30px 0; text-align: center; padding: 15px; background: rgba(255, 193, 7, 0.2); border-radius: 10px;").Child(
-			P("Click Install to begin the installation process.").Style("margin: 0; font-weight: bold;"),
-		),
+		confirmDiv,
 
 		// Buttons
 		DIV().Class("buttons").Style("text-align: center; margin-top: 40px;").Children(
 			BUTTON("Back").Class("button").ID("btnBack"),
-			BUTTON("Install").Class("button primary").ID("btnInstall").Style("font-size: 1.2rem; padding: 12px 30px;"),
+			BUTTON(r.nextLabel()).Class("button primary").ID("btnInstall").Style("font-size: 1.2rem; padding: 12px 30px;"),
 			BUTTON("Cancel").Class("button").ID("btnCancel"),
 		),
 	)
 
+	container.Child(r.noscriptButtons(config, true, r.noscriptNext("/api/next")))
 	doc.AddToBody(container)
 
 	// Add JavaScript for summary actions and navigation
@@ -450,15 +1285,34 @@ func (r *SSRRenderer) RenderSummaryPage(config *core.Config, selectedComponents
 
 			if (btnCancel) {
 				btnCancel.addEventListener('click', function() {
-					if (confirm('Are you sure you want to cancel the installation?')) {
-						fetch('/api/cancel', { method: 'POST' })
-							.then(response => response.json())
-							.then(data => {
+					// The installer asks for confirmation in its own dialog
+					fetch('/api/cancel', { method: 'POST' })
+						.then(response => response.json())
+						.then(data => {
+							if (data.status === 'cancelled') {
 								window.close();
-							});
-					}
+							} else {
+								window.location.reload();
+							}
+						});
 				});
 			}
+
+			// Change returns to the step and, on Next, back here
+			document.querySelectorAll('.change-link').forEach(function(link) {
+				link.addEventListener('click', function() {
+					const state = link.dataset.state;
+					if (typeof installerJump === 'function') {
+						installerJump(state);
+						return;
+					}
+					fetch('/api/jump', {
+						method: 'POST',
+						headers: { 'Content-Type': 'application/json' },
+						body: JSON.stringify({ state: state })
+					}).then(() => window.location.reload());
+				});
+			});
 		});
 	`
 
@@ -481,7 +1335,7 @@ func (r *SSRRenderer) RenderComponentsPage(config *core.Config) *Document {
 	selectedCount := 0
 	
 	for i, comp := range config.Components {
-		totalSize += comp.Size
+		totalSize += comp.DiskSize()
 		if comp.Selected {
 			selectedCount++
 		}
@@ -504,11 +1358,22 @@ func (r *SSRRenderer) RenderComponentsPage(config *core.Config) *Document {
 		compHeader := DIV().Class("component-header").Children(
 			SPAN(checkmark).Style("margin-right: 15px; font-size: 1.2rem;"),
 			DIV().Class("component-name").Text(comp.Name),
-			DIV().Class("component-size").Text(formatSize(comp.Size)),
+			DIV().Class("component-size").Text(formatSize(comp.DiskSize())),
 		)
 		
 		compDiv.Child(compHeader)
 		
+		// Without JavaScript a checkbox posts the choice with Next
+		box := INPUT("checkbox").ID("noscript-component-"+comp.ID).Name("component").Value(comp.ID)
+		if comp.Selected || comp.Required {
+			box.Checked()
+		}
+		if comp.Required {
+			box.Attr("disabled", "true")
+		}
+		box.Attr("form", "noscriptNext")
+		compDiv.Child(NOSCRIPT().Child(LABEL("").Attr("for", "noscript-component-"+comp.ID).Children(box, SPAN("Install "+comp.Name))))
+		
 		// Component description
 		if comp.Description != "" {
 			compDiv.Child(
@@ -519,6 +1384,7 @@ func (r *SSRRenderer) RenderComponentsPage(config *core.Config) *Document {
 		// Add data attributes for JavaScript interaction
 		compDiv.Attr("data-component-id", comp.ID).
 			Attr("data-component-index", fmt.Sprintf("%d", i)).
+			Attr("data-search", componentSearchText(comp)).
 			Attr("data-selected", boolToString(comp.Selected)).
 			Attr("data-required", boolToString(comp.Required))
 		
@@ -535,11 +1401,17 @@ func (r *SSRRenderer) RenderComponentsPage(config *core.Config) *Document {
 	// Main container
 	container := DIV().Class("container").Children(
 		// Header
-		HEADER().Class("header").Children(
+		r.header(config,
 			DIV().Class("title").Text("Component Selection"),
 			DIV().Class("subtitle").Text("Choose components to install"),
 		),
 
+		// Quick selections
+		presetButtons(config),
+
+		// Filter for long lists
+		componentSearch(config),
+
 		// Components list
 		componentsDiv,
 		
@@ -555,11 +1427,12 @@ func (r *SSRRenderer) RenderComponentsPage(config *core.Config) *Document {
 		// Buttons
 		DIV().Class("buttons").Style("text-align: center; margin-top: 40px;").Children(
 			BUTTON("Back").Class("button").ID("btnBack"),
-			BUTTON("Next").Class("button primary").ID("btnNext"),
+			BUTTON(r.nextLabel()).Class("button primary").ID("btnNext"),
 			BUTTON("Cancel").Class("button").ID("btnCancel"),
 		),
 	)
 
+	container.Child(r.noscriptButtons(config, true, r.noscriptNext("/api/next", INPUT("hidden").Name("components").Value("posted"))))
 	doc.AddToBody(container)
 	
 	// Add JavaScript for component interaction and navigation
@@ -580,11 +1453,43 @@ func (r *SSRRenderer) RenderComponentsPage(config *core.Config) *Document {
 					
 					// Update summary (simplified)
 					updateSummary();
+					updatePreset();
 				});
 				
 				comp.style.cursor = 'pointer';
 			});
 			
+			// The filter hides the components whose name and description lack the term
+			const search = document.getElementById('componentSearch');
+			if (search) {
+				search.addEventListener('input', function() {
+					const term = this.value.trim().toLowerCase();
+					let shown = 0;
+					document.querySelectorAll('.component').forEach(comp => {
+						const match = comp.getAttribute('data-search').includes(term);
+						comp.style.display = match ? '' : 'none';
+						if (match) {
+							shown++;
+						}
+					});
+					document.getElementById('componentSearchEmpty').hidden = shown > 0;
+				});
+			}
+			
+			// Presets select exactly their components
+			document.querySelectorAll('.preset-button').forEach(button => {
+				button.addEventListener('click', function() {
+					const ids = this.getAttribute('data-components').split(',');
+					document.querySelectorAll('.component:not(.required)').forEach(comp => {
+						const selected = ids.includes(comp.getAttribute('data-component-id'));
+						comp.setAttribute('data-selected', selected.toString());
+						comp.querySelector('span').textContent = selected ? '☑' : '☐';
+					});
+					updateSummary();
+					updatePreset();
+				});
+			});
+			
 			// Button navigation logic
 			const btnNext = document.getElementById('btnNext');
 			const btnBack = document.getElementById('btnBack');
@@ -616,17 +1521,43 @@ func (r *SSRRenderer) RenderComponentsPage(config *core.Config) *Document {
 			
 			if (btnCancel) {
 				btnCancel.addEventListener('click', function() {
-					if (confirm('Are you sure you want to cancel the installation?')) {
-						fetch('/api/cancel', { method: 'POST' })
-							.then(response => response.json())
-							.then(data => {
+					// The installer asks for confirmation in its own dialog
+					fetch('/api/cancel', { method: 'POST' })
+						.then(response => response.json())
+						.then(data => {
+							if (data.status === 'cancelled') {
 								window.close();
-							});
-					}
+							} else {
+								window.location.reload();
+							}
+						});
 				});
 			}
 		});
 		
+		// updatePreset shows the preset matching the selection, Custom if none
+		function updatePreset() {
+			const current = document.getElementById('presetCurrent');
+			if (!current) {
+				return;
+			}
+			const selected = [];
+			document.querySelectorAll('.component').forEach(comp => {
+				if (comp.getAttribute('data-selected') === 'true' || comp.getAttribute('data-required') === 'true') {
+					selected.push(comp.getAttribute('data-component-id'));
+				}
+			});
+			let name = '` + core.CustomPreset + `';
+			document.querySelectorAll('.preset-button').forEach(button => {
+				const match = name === '` + core.CustomPreset + `' && button.getAttribute('data-components') === selected.join(',');
+				if (match) {
+					name = button.getAttribute('data-preset');
+				}
+				button.setAttribute('aria-pressed', match.toString());
+			});
+			current.textContent = name;
+		}
+		
 		function updateSummary() {
 			// This would update the summary section with new totals
 			// Implementation depends on the specific needs
@@ -637,8 +1568,74 @@ func (r *SSRRenderer) RenderComponentsPage(config *core.Config) *Document {
 	return doc
 }
 
+// presetButtons renders a quick-select button per selection preset and the
+// name of the preset the selection matches; nothing if there are no presets
+func presetButtons(config *core.Config) *Element {
+	presets := DIV().Class("presets")
+	if len(config.SelectionPresets) == 0 {
+		return presets
+	}
+	current := core.MatchingPreset(config.Components, config.SelectionPresets)
+	presets.Child(SPAN("Presets:"))
+	for _, name := range core.PresetNames(config.SelectionPresets) {
+		ids := core.PresetSelection(config.Components, config.SelectionPresets[name])
+		presets.Child(BUTTON(name).Attr("type", "button").Class("button preset-button").
+			Data("preset", name).Data("components", strings.Join(ids, ",")).
+			AriaPressed(name == current))
+	}
+	return presets.Child(SPAN(current).Class("preset-current").ID("presetCurrent").Role("status"))
+}
+
+// componentSearchText returns the lowercase name and description the
+// component filter of the page searches, as core.Component.MatchesFilter does
+func componentSearchText(comp core.Component) string {
+	return strings.ToLower(comp.Name + "\n" + comp.Description)
+}
+
+// componentSearch renders the filter of the component list, for lists of
+// core.ComponentFilterThreshold components or more
+func componentSearch(config *core.Config) *Element {
+	search := DIV().Class("component-search")
+	if len(config.Components) < core.ComponentFilterThreshold {
+		return search
+	}
+	return search.Style("margin: 20px 0;").Children(
+		INPUT("search").ID("componentSearch").Placeholder("Filter components").AriaLabel("Filter components").
+			Style("width: 100%; padding: 10px; border: 1px solid #ccc; border-radius: 5px; font-size: 1rem;"),
+		P("No components match the filter.").ID("componentSearchEmpty").Hidden(),
+	)
+}
+
 // RenderProgressPage renders the installation progress page
 func (r *SSRRenderer) RenderProgressPage(config *core.Config, progress int, status string) *Document {
+	return r.progressPage(config, progress, "", status, nil)
+}
+
+// RenderInstallProgressPage renders the progress of a running installation:
+// its phase such as "Phase 2 of 4: Installing files", the overall bar and below
+// it a smaller one for the current component, described as "Installing Core:
+// 40% (overall 65%)"
+func (r *SSRRenderer) RenderInstallProgressPage(config *core.Config, progress *core.Progress) *Document {
+	var component *Element
+	if progress.ComponentName != "" || progress.Phase == core.PhaseNone {
+		component = DIV().Class("component-progress").Children(
+			P(progress.Status()).Class("component-status"),
+			progressBar(progress.ComponentPercent(), progress.ComponentName).AddClass("component"),
+		)
+	}
+	return r.progressPage(config, progress.OverallPercent(), progress.PhaseStatus(), progress.Message, component)
+}
+
+// progressBar renders a bar filled to percent
+func progressBar(percent int, label string) *Element {
+	return DIV().Class("progress").Role("progressbar").AriaLabel(label).
+		Attr("aria-valuemin", "0").Attr("aria-valuemax", "100").Attr("aria-valuenow", fmt.Sprint(percent)).
+		Child(DIV().Class("progress-bar").Style(fmt.Sprintf("width: %d%%;", percent)))
+}
+
+// progressPage renders the progress page with the phase, if known, and the
+// overall bar, followed by the progress of the current component if there is one
+func (r *SSRRenderer) progressPage(config *core.Config, progress int, phase, status string, component *Element) *Document {
 	doc := NewDocument().
 		SetTitle(config.AppName + " - Installing").
 		SetCharset("utf-8").
@@ -646,29 +1643,159 @@ func (r *SSRRenderer) RenderProgressPage(config *core.Config, progress int, stat
 		AddDefaultSetupKitStyles()
 
 	// Progress bar
-	progressDiv := DIV().Class("progress").Child(
-		DIV().Class("progress-bar").Style(fmt.Sprintf("width: %d%%;", progress)),
-	)
+	progressDiv := progressBar(progress, "Overall progress")
 
 	// Status text
-	statusDiv := DIV().Class("status").Style("text-align: center; margin: 20px 0;").Children(
+	statusDiv := DIV().Class("status").Style("text-align: center; margin: 20px 0;").Child(
 		H3("Installing " + config.AppName),
+	)
+	if phase != "" {
+		statusDiv.Child(P(phase).Class("phase"))
+	}
+	statusDiv.Children(
 		P(status),
 		P(fmt.Sprintf("%d%% complete", progress)),
 	)
 
 	container := DIV().Class("container").Children(
-		HEADER().Class("header").Children(
+		r.header(config,
 			DIV().Class("title").Text("Installation in Progress"),
 		),
 		statusDiv,
 		progressDiv,
-		DIV().Style("text-align: center; margin-top: 40px;").Child(
-			P("Please wait while the installation completes..."),
+	)
+	if component != nil {
+		container.Child(component)
+	}
+	container.Child(DIV().Style("text-align: center; margin-top: 40px;").Child(
+		P("Please wait while the installation completes..."),
+	))
+
+	// Cancelling a running installation rolls it back, the installer asks first
+	if config.AllowInstallCancel {
+		container.Child(DIV().Class("buttons").Style("text-align: center;").Child(
+			BUTTON(r.t(core.MsgButtonCancel)).Class("button").ID("btnCancel"),
+		))
+		doc.AddJS(`
+		document.addEventListener('DOMContentLoaded', function() {
+			document.getElementById('btnCancel').addEventListener('click', function() {
+				// The installer asks for confirmation in its own dialog
+				if (typeof installerCancel === 'function') {
+					installerCancel();
+					return;
+				}
+				fetch('/api/cancel', { method: 'POST' })
+					.then(response => response.json())
+					.then(data => {
+						if (data.status === 'cancelled') {
+							window.close();
+						} else {
+							window.location.reload();
+						}
+					});
+			});
+		});
+	`)
+	}
+
+	// Without JavaScript the page reloads itself to follow the installation
+	doc.AddToHead(NOSCRIPT().Child(META().HttpEquiv("refresh").Content("2")))
+	if config.AllowInstallCancel {
+		container.Child(noscriptForms(postForm("/api/cancel", BUTTON(r.t(core.MsgButtonCancel)).Class("button"))))
+	}
+
+	doc.AddToBody(container)
+	return doc
+}
+
+// RenderConfirmDialog renders a yes/no question of the installer. The answer
+// is posted to /api/confirm as {"answer": true|false}, as the form value
+// answer without JavaScript, or passed to the installerConfirm binding where
+// the page runs in a webview.
+func (r *SSRRenderer) RenderConfirmDialog(config *core.Config, title, message string) *Document {
+	doc := NewDocument().
+		SetTitle(config.AppName + " - " + title).
+		SetCharset("utf-8").
+		SetViewport("").
+		AddDefaultSetupKitStyles()
+
+	container := DIV().Class("container").Children(
+		r.pageHeader(config, "",
+			DIV().Class("title").Text(title),
+		),
+		MAIN().Style("text-align: center;").Children(
+			P(message).Style("font-size: 1.2rem; margin-bottom: 30px;"),
+		),
+		DIV().Class("buttons").Style("text-align: center;").Children(
+			BUTTON("No").Class("button").ID("btnNo"),
+			BUTTON("Yes").Class("button primary").ID("btnYes"),
 		),
 	)
 
+	container.Child(noscriptForms(
+		postForm("/api/confirm", INPUT("hidden").Name("answer").Value("false"), BUTTON("No").Class("button")),
+		postForm("/api/confirm", INPUT("hidden").Name("answer").Value("true"), BUTTON("Yes").Class("button primary")),
+	))
 	doc.AddToBody(container)
+
+	js := `
+		document.addEventListener('DOMContentLoaded', function() {
+			function answer(yes) {
+				if (typeof installerConfirm === 'function') {
+					installerConfirm(yes);
+					return;
+				}
+				fetch('/api/confirm', {
+					method: 'POST',
+					headers: { 'Content-Type': 'application/json' },
+					body: JSON.stringify({ answer: yes })
+				}).then(() => window.location.reload());
+			}
+
+			document.getElementById('btnYes').addEventListener('click', function() { answer(true); });
+			document.getElementById('btnNo').addEventListener('click', function() { answer(false); });
+		});
+	`
+
+	doc.AddJS(js)
+	return doc
+}
+
+// RenderHelpDialog renders the help of a page with a button back to the page
+func (r *SSRRenderer) RenderHelpDialog(config *core.Config, title, help string) *Document {
+	doc := NewDocument().
+		SetTitle(config.AppName + " - " + title).
+		SetCharset("utf-8").
+		SetViewport("").
+		AddDefaultSetupKitStyles()
+
+	container := DIV().Class("container").Children(
+		r.pageHeader(config, "",
+			DIV().Class("title").Text(title),
+		),
+		MAIN().Children(
+			P(help).Class("help-dialog-text").Style("font-size: 1.1rem; margin-bottom: 30px;"),
+		),
+		DIV().Class("buttons").Style("text-align: center;").Children(
+			BUTTON("Close").Class("button primary").ID("btnCloseHelp"),
+		),
+	)
+
+	doc.AddToBody(container)
+
+	js := `
+		document.addEventListener('DOMContentLoaded', function() {
+			document.getElementById('btnCloseHelp').addEventListener('click', function() {
+				if (typeof installerCloseHelp === 'function') {
+					installerCloseHelp();
+					return;
+				}
+				fetch('/api/help', { method: 'DELETE' }).then(() => window.location.reload());
+			});
+		});
+	`
+
+	doc.AddJS(js)
 	return doc
 }
 
@@ -694,19 +1821,55 @@ func (r *SSRRenderer) RenderCompletionPage(config *core.Config, success bool) *D
 		icon = "❌"
 	}
 
+	content := MAIN().Style("text-align: center;").Child(
+		P(message).Style("font-size: 1.2rem; margin-bottom: 30px;"),
+	)
+	actions := completionActions(config)
+	if success && len(r.notices) > 0 {
+		list := UL()
+		for _, notice := range r.notices {
+			list.Child(LI(notice))
+		}
+		content.Child(DIV().Class("completion-warnings").Role("status").Children(
+			STRONG("The installation completed with warnings:"),
+			list,
+		))
+	}
+	if success && len(r.reboot) > 0 {
+		content.Child(rebootNotice(r.reboot))
+		// The restart reports its result where the actions do
+		if actions == nil {
+			content.Child(P().Class("action-status").ID("actionStatus").Role("status"))
+		}
+	}
+	if success && actions != nil {
+		content.Child(actions)
+	}
+	buttons := DIV().Class("buttons").Style("text-align: center;")
+	// Configured diagnostics and view log actions replace the buttons
+	showDiagnostics := !success || !hasCompletionAction(config, core.ActionExportDiagnostics)
+	showLog := config.LogFile != "" && (!success || !hasCompletionAction(config, core.ActionViewLog))
+	if showDiagnostics || showLog {
+		content.Child(diagnosticsStatus())
+	}
+	if showDiagnostics {
+		buttons.Child(diagnosticsButton())
+	}
+	if showLog {
+		buttons.Child(viewLogButton())
+	}
+	buttons.Child(BUTTON(r.nextLabel()).Class("button primary").ID("btnFinish"))
+
 	container := DIV().Class("container").Children(
-		HEADER().Class("header").Children(
+		r.header(config,
 			DIV().Style("font-size: 4rem; margin-bottom: 20px;").Text(icon),
 			DIV().Class("title").Text(title),
 		),
-		MAIN().Style("text-align: center;").Children(
-			P(message).Style("font-size: 1.2rem; margin-bottom: 30px;"),
-		),
-		DIV().Class("buttons").Style("text-align: center;").Child(
-			BUTTON("Finish").Class("button primary").ID("btnFinish"),
-		),
+		content,
+		buttons,
 	)
 
+	container.Child(finishForms(config, BUTTON(r.nextLabel()).Class("button primary")))
 	doc.AddToBody(container)
 	
 	// Add JavaScript for finish button
@@ -730,6 +1893,60 @@ func (r *SSRRenderer) RenderCompletionPage(config *core.Config, success bool) *D
 	return doc
 }
 
+// RenderErrorPage renders a failed installation with the error, whether the
+// changes were undone and, prominently, what the user can do about it
+func (r *SSRRenderer) RenderErrorPage(config *core.Config, err error) *Document {
+	doc := NewDocument().
+		SetTitle(config.AppName + " - Installation Failed").
+		SetCharset("utf-8").
+		SetViewport("").
+		AddDefaultSetupKitStyles()
+
+	content := MAIN().Style("text-align: center;")
+	if hint := core.ErrorHint(err); hint != "" {
+		content.Child(DIV().Class("error-hint").Role("alert").Children(
+			STRONG("What you can do"),
+			P(hint),
+		))
+	}
+	content.Child(P("The installation of " + config.AppName + " was not completed.").Style("font-size: 1.2rem;"))
+	var installErr *core.InstallError
+	if errors.As(err, &installErr) && installErr.RolledBack {
+		content.Child(P("The changes made so far have been undone.").Class("rollback-note"))
+	}
+	content.Child(PRE(err.Error()).Class("error-detail"))
+	content.Child(diagnosticsStatus())
+
+	buttons := DIV().Class("buttons").Style("text-align: center;").Child(diagnosticsButton())
+	if config.LogFile != "" {
+		buttons.Child(viewLogButton())
+	}
+	buttons.Child(BUTTON("Close").Class("button primary").ID("btnFinish"))
+
+	container := DIV().Class("container").Children(
+		r.header(config,
+			DIV().Style("font-size: 4rem; margin-bottom: 20px;").Text("❌"),
+			DIV().Class("title").Text("Installation Failed"),
+		),
+		content,
+		buttons,
+	)
+
+	container.Child(finishForms(config, BUTTON("Close").Class("button primary")))
+	doc.AddToBody(container)
+
+	js := `
+		document.addEventListener('DOMContentLoaded', function() {
+			document.getElementById('btnFinish').addEventListener('click', function() {
+				fetch('/api/finish', { method: 'POST' }).then(() => window.close());
+			});
+		});
+	`
+
+	doc.AddJS(js)
+	return doc
+}
+
 // Helper functions
 
 func formatSize(bytes int64) string {
@@ -184,6 +184,45 @@ func TestPathComponentUninstall(t *testing.T) {
 	}
 }
 
+// TestPathComponentChosenScope tests that an automatic scope follows the PATH scope chosen by the user
+func TestPathComponentChosenScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		elevated bool
+		system   bool
+	}{
+		{"system chosen", true, true},
+		{"user chosen while elevated", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPlatform := NewMockPlatformInstaller()
+			mockPlatform.elevated = tt.elevated
+			config := &installer.Config{
+				PathConfig: &installer.PathConfiguration{Enabled: true, System: tt.system, AskScope: true},
+			}
+
+			pc := components.NewPathComponent("/test/bin", components.PathScopeAuto)
+
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, "platform", mockPlatform)
+			ctx = context.WithValue(ctx, "logger", core.NewLogger("info", ""))
+			ctx = context.WithValue(ctx, "config", config)
+
+			if err := pc.Installer(ctx); err != nil {
+				t.Fatalf("Installer() error = %v", err)
+			}
+			if !mockPlatform.IsInPath("/test/bin", tt.system) {
+				t.Errorf("directory not added to the PATH chosen (system = %v)", tt.system)
+			}
+			if mockPlatform.IsInPath("/test/bin", !tt.system) {
+				t.Errorf("directory added to the PATH not chosen (system = %v)", !tt.system)
+			}
+		})
+	}
+}
+
 // TestBinaryComponent tests binary component
 func TestBinaryComponent(t *testing.T) {
 	bc := components.NewBinaryComponent(
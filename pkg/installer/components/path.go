@@ -7,6 +7,7 @@ import (
 	"runtime"
 	
 	"github.com/mmso2016/setupkit/pkg/installer"
+	"github.com/mmso2016/setupkit/pkg/installer/core"
 )
 
 // PathScope defines the scope for PATH modifications
@@ -21,7 +22,9 @@ const (
 	PathScopeSystem
 )
 
-// PathComponent handles PATH environment variable modifications
+// PathComponent handles PATH environment variable modifications. With
+// PathScopeAuto it follows the scope the user chose when the installer's
+// PathConfig has AskScope set.
 type PathComponent struct {
 	installer.Component
 	Directory     string
@@ -75,8 +78,8 @@ func (pc *PathComponent) validate() error {
 // install adds the directory to PATH
 func (pc *PathComponent) install(ctx context.Context) error {
 	// Get platform installer from context
-	platform, ok := ctx.Value("platform").(installer.PlatformInstaller)
-	if !ok {
+	platform := platformFrom(ctx)
+	if platform == nil {
 		return fmt.Errorf("platform installer not available in context")
 	}
 	
@@ -88,7 +91,7 @@ func (pc *PathComponent) install(ctx context.Context) error {
 	}
 	
 	// Determine actual scope based on configuration and privileges
-	useSystemPath := pc.shouldUseSystemPath(platform)
+	useSystemPath := pc.shouldUseSystemPath(platform, configFrom(ctx))
 	
 	logger.Verbose("Installing PATH component", 
 		"directory", pc.Directory,
@@ -134,8 +137,8 @@ func (pc *PathComponent) install(ctx context.Context) error {
 // uninstall removes the directory from PATH
 func (pc *PathComponent) uninstall(ctx context.Context) error {
 	// Get platform installer from context
-	platform, ok := ctx.Value("platform").(installer.PlatformInstaller)
-	if !ok {
+	platform := platformFrom(ctx)
+	if platform == nil {
 		return fmt.Errorf("platform installer not available in context")
 	}
 	
@@ -179,7 +182,12 @@ func (pc *PathComponent) uninstall(ctx context.Context) error {
 }
 
 // shouldUseSystemPath determines whether to use system or user PATH
-func (pc *PathComponent) shouldUseSystemPath(platform installer.PlatformInstaller) bool {
+func (pc *PathComponent) shouldUseSystemPath(platform installer.PlatformInstaller, config *installer.Config) bool {
+	// The scope chosen by the user replaces the automatic selection
+	if pc.Scope == PathScopeAuto && config != nil && config.PathConfig != nil && config.PathConfig.AskScope {
+		return config.PathConfig.System
+	}
+
 	switch pc.Scope {
 	case PathScopeSystem:
 		// Explicitly requested system PATH
@@ -200,6 +208,22 @@ func (pc *PathComponent) shouldUseSystemPath(platform installer.PlatformInstalle
 	}
 }
 
+// platformFrom returns the platform installer of an installation context
+func platformFrom(ctx context.Context) installer.PlatformInstaller {
+	if platform, ok := ctx.Value("platform").(installer.PlatformInstaller); ok {
+		return platform
+	}
+	return core.PlatformFromContext(ctx)
+}
+
+// configFrom returns the installer configuration of an installation context
+func configFrom(ctx context.Context) *installer.Config {
+	if config, ok := ctx.Value("config").(*installer.Config); ok {
+		return config
+	}
+	return core.ConfigFromContext(ctx)
+}
+
 // getScopeString returns a string representation of the scope
 func (pc *PathComponent) getScopeString() string {
 	switch pc.Scope {
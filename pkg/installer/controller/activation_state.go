@@ -0,0 +1,221 @@
+// Package controller provides the license activation custom state
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+const (
+	StateActivation wizard.State = "activation"
+)
+
+// Activation failures reported to the user
+var (
+	ErrInvalidLicenseKey     = errors.New("the license key is not valid")
+	ErrSeatLimitReached      = errors.New("all seats of this license are in use")
+	ErrActivationUnreachable = errors.New("the activation server cannot be reached")
+)
+
+// DefaultActivationTimeout limits an activation unless
+// ActivationOptions.Timeout says otherwise
+const DefaultActivationTimeout = 2 * time.Minute
+
+// ActivationOptions configures the license activation state.
+//
+// The endpoint receives a POST with {"license_key", "product", "version"} and
+// answers 200 with {"token": "..."}. 400, 403 and 404 mean an invalid key, 409
+// a reached seat limit; other failures are retried and then reported as
+// unreachable.
+type ActivationOptions struct {
+	Endpoint    string
+	Product     string           // Sent to the server, defaults to the application name
+	RetryPolicy core.RetryPolicy // Zero values use core.DefaultRetryPolicy
+	Timeout     time.Duration    // Limits the activation with its retries, defaults to DefaultActivationTimeout
+	Client      *http.Client     // Defaults to core.HTTPClient with the installer's proxy settings
+
+	// VerifyOffline checks an offline activation code for a key and returns
+	// the activation token. If set, the user may enter a code instead of
+	// contacting the server.
+	VerifyOffline func(licenseKey, offlineCode string) (token string, err error)
+
+	InsertPoint InsertionPoint // Defaults to InsertAfterLicense
+}
+
+// ActivationStateHandler collects a license key and activates it. The token
+// is stored in the state data as "activation_token"; Next is blocked until
+// activation succeeds.
+type ActivationStateHandler struct {
+	*BaseCustomStateHandler
+	opts ActivationOptions
+}
+
+// NewActivationStateHandler creates a license activation handler
+func NewActivationStateHandler(opts ActivationOptions) *ActivationStateHandler {
+	insertPoint := opts.InsertPoint
+	if insertPoint.After == "" {
+		insertPoint = InsertAfterLicense
+	}
+	return &ActivationStateHandler{
+		BaseCustomStateHandler: &BaseCustomStateHandler{
+			StateID:     StateActivation,
+			Name:        "Product Activation",
+			Description: "Enter your license key to activate the product",
+			InsertPoint: insertPoint,
+			CanGoNext:   true,
+			CanGoBack:   true,
+			CanCancel:   true,
+		},
+		opts: opts,
+	}
+}
+
+// HandleEnter implements CustomStateHandler
+func (h *ActivationStateHandler) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	view, ok := controller.view.(ExtendedInstallerView)
+	if !ok {
+		return fmt.Errorf("view does not support custom states")
+	}
+	key, _ := wizard.DataAs[string](data, "license_key")
+	result, err := view.ShowCustomState(StateActivation, CustomStateData{
+		"license_key":       key,
+		"offline_available": h.opts.VerifyOffline != nil,
+	})
+	if err != nil {
+		return err
+	}
+	for _, field := range []string{"license_key", "offline_code"} {
+		if value, ok := result[field].(string); ok {
+			data[field] = strings.TrimSpace(value)
+		}
+	}
+	return nil
+}
+
+// Validate implements CustomStateHandler by activating the entered key
+func (h *ActivationStateHandler) Validate(controller *InstallerController, data map[string]interface{}) error {
+	var result ValidationResult
+	key, _ := wizard.DataAs[string](data, "license_key")
+	if key == "" {
+		result.Add("license_key", "please enter a license key")
+		return result
+	}
+
+	var token string
+	var err error
+	if code, _ := wizard.DataAs[string](data, "offline_code"); code != "" && h.opts.VerifyOffline != nil {
+		if token, err = h.opts.VerifyOffline(key, code); err != nil {
+			result.AddError("offline_code", fmt.Errorf("offline activation failed: %w", err))
+			return result
+		}
+	} else if token, err = h.activate(controller, key); err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidLicenseKey), errors.Is(err, ErrSeatLimitReached):
+			result.AddError("license_key", err)
+			return result
+		case errors.Is(err, ErrActivationUnreachable) && h.opts.VerifyOffline != nil:
+			return fmt.Errorf("%w; enter an offline activation code instead", err)
+		}
+		return err
+	}
+
+	// Validate works on a copy of the data, so store the token directly
+	controller.stateData["activation_token"] = token
+	return nil
+}
+
+// activate activates key until the user cancels the installation or the
+// timeout runs out
+func (h *ActivationStateHandler) activate(controller *InstallerController, key string) (string, error) {
+	timeout := h.opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultActivationTimeout
+	}
+	ctx, cancel := context.WithTimeout(controller.ctx, timeout)
+	defer cancel()
+	return h.Activate(ctx, controller.config, key)
+}
+
+// activationRequest is the body sent to the activation endpoint
+type activationRequest struct {
+	LicenseKey string `json:"license_key"`
+	Product    string `json:"product"`
+	Version    string `json:"version"`
+}
+
+// Activate sends key to the activation endpoint and returns the token
+func (h *ActivationStateHandler) Activate(ctx context.Context, config *core.Config, key string) (string, error) {
+	client := h.opts.Client
+	if client == nil {
+		client = core.HTTPClient(config)
+	}
+	product := h.opts.Product
+	if product == "" {
+		product = config.AppName
+	}
+	body, err := json.Marshal(activationRequest{LicenseKey: key, Product: product, Version: config.Version})
+	if err != nil {
+		return "", err
+	}
+
+	policy := h.opts.RetryPolicy
+	policy.IsRetryable = func(err error) bool {
+		return !errors.Is(err, ErrInvalidLicenseKey) && !errors.Is(err, ErrSeatLimitReached)
+	}
+
+	var token string
+	err = core.Retry(ctx, policy, func(ctx context.Context) error {
+		var attemptErr error
+		token, attemptErr = h.activateOnce(ctx, client, body)
+		return attemptErr
+	})
+	switch {
+	case err == nil:
+		return token, nil
+	case errors.Is(err, ErrInvalidLicenseKey), errors.Is(err, ErrSeatLimitReached):
+		return "", err
+	default:
+		return "", fmt.Errorf("%w: %v", ErrActivationUnreachable, err)
+	}
+}
+
+// activateOnce makes a single activation request
+func (h *ActivationStateHandler) activateOnce(ctx context.Context, client *http.Client, body []byte) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.opts.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound:
+		return "", ErrInvalidLicenseKey
+	case http.StatusConflict:
+		return "", ErrSeatLimitReached
+	default:
+		return "", fmt.Errorf("activation server returned %s", resp.Status)
+	}
+
+	var result struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.Token == "" {
+		return "", fmt.Errorf("invalid activation response")
+	}
+	return result.Token, nil
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+// activationServer answers like a license server: VALID activates, FULL has no seats left
+func activationServer(t *testing.T) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req activationRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "ActivationApp", req.Product)
+		switch req.LicenseKey {
+		case "VALID":
+			json.NewEncoder(w).Encode(map[string]string{"token": "token-123"})
+		case "FULL":
+			w.WriteHeader(http.StatusConflict)
+		case "DOWN":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newActivationTest(t *testing.T, opts ActivationOptions) (*InstallerController, *ActivationStateHandler) {
+	config := &core.Config{AppName: "ActivationApp", Version: "1.0.0"}
+	controller := NewInstallerController(config, core.New(config))
+	controller.SetView(NewMockExtendedInstallerView())
+	opts.RetryPolicy = core.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	return controller, NewActivationStateHandler(opts)
+}
+
+func TestActivationSuccess(t *testing.T) {
+	server, _ := activationServer(t)
+	controller, handler := newActivationTest(t, ActivationOptions{Endpoint: server.URL})
+
+	require.NoError(t, handler.Validate(controller, map[string]interface{}{"license_key": "VALID"}))
+	assert.Equal(t, "token-123", controller.GetStateData()["activation_token"])
+}
+
+func TestActivationFailures(t *testing.T) {
+	server, requests := activationServer(t)
+	controller, handler := newActivationTest(t, ActivationOptions{Endpoint: server.URL})
+
+	tests := []struct {
+		key      string
+		want     error
+		attempts int
+	}{
+		{"WRONG", ErrInvalidLicenseKey, 1},
+		{"FULL", ErrSeatLimitReached, 1},
+		{"DOWN", ErrActivationUnreachable, 3},
+	}
+	for _, tt := range tests {
+		*requests = 0
+		err := handler.Validate(controller, map[string]interface{}{"license_key": tt.key})
+		assert.True(t, errors.Is(err, tt.want), "key %s: got %v, want %v", tt.key, err, tt.want)
+		assert.Equal(t, tt.attempts, *requests, "key %s: attempts", tt.key)
+	}
+	assert.NotContains(t, controller.GetStateData(), "activation_token")
+
+	// No server listening at all
+	server.Close()
+	err := handler.Validate(controller, map[string]interface{}{"license_key": "VALID"})
+	assert.ErrorIs(t, err, ErrActivationUnreachable)
+
+	assert.Error(t, handler.Validate(controller, map[string]interface{}{}), "empty key must block Next")
+}
+
+func TestActivationOfflineFallback(t *testing.T) {
+	server, requests := activationServer(t)
+	server.Close()
+	controller, handler := newActivationTest(t, ActivationOptions{
+		Endpoint: server.URL,
+		VerifyOffline: func(key, code string) (string, error) {
+			if code != "OFFLINE-"+key {
+				return "", errors.New("code does not match key")
+			}
+			return "offline-token", nil
+		},
+	})
+
+	err := handler.Validate(controller, map[string]interface{}{"license_key": "VALID"})
+	require.ErrorIs(t, err, ErrActivationUnreachable)
+	assert.Contains(t, err.Error(), "offline activation code")
+
+	assert.Error(t, handler.Validate(controller, map[string]interface{}{"license_key": "VALID", "offline_code": "WRONG"}))
+	require.NoError(t, handler.Validate(controller, map[string]interface{}{"license_key": "VALID", "offline_code": "OFFLINE-VALID"}))
+	assert.Equal(t, "offline-token", controller.GetStateData()["activation_token"])
+	assert.Zero(t, *requests)
+}
+
+// hangingServer accepts activation requests and never answers them
+func hangingServer(t *testing.T) *httptest.Server {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+	return server
+}
+
+func TestActivationTimeout(t *testing.T) {
+	controller, handler := newActivationTest(t, ActivationOptions{Endpoint: hangingServer(t).URL, Timeout: 20 * time.Millisecond})
+
+	err := handler.Validate(controller, map[string]interface{}{"license_key": "VALID"})
+	assert.ErrorIs(t, err, ErrActivationUnreachable)
+}
+
+func TestActivationCancelled(t *testing.T) {
+	config := &core.Config{AppName: "ActivationApp", Version: "1.0.0", License: "License text"}
+	controller := NewInstallerController(config, core.New(config))
+	require.NoError(t, controller.RegisterCustomState(NewActivationStateHandler(ActivationOptions{Endpoint: hangingServer(t).URL})))
+	driver := NewTestDriver(controller).Input(StateActivation, "license_key", "VALID")
+	require.NoError(t, driver.Run(wizard.ActionNext, wizard.ActionNext))
+	require.Equal(t, StateActivation, controller.GetCurrentState())
+
+	done := make(chan error, 1)
+	go func() { done <- controller.Next() }()
+	require.Eventually(t, controller.validating.Load, time.Second, time.Millisecond)
+
+	require.NoError(t, controller.Cancel())
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, ErrActivationUnreachable)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the activation went on after the installation was cancelled")
+	}
+}
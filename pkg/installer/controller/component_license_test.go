@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gplText = "GNU GENERAL PUBLIC LICENSE\nVersion 3"
+
+// licenseRecordingView records license texts and returns a configurable component selection
+type licenseRecordingView struct {
+	*MockExtendedInstallerView
+	licenses  []string
+	selectGPL bool
+}
+
+func (v *licenseRecordingView) ShowLicense(license string) (bool, error) {
+	v.licenses = append(v.licenses, license)
+	return true, nil
+}
+
+func (v *licenseRecordingView) ShowComponents(components []core.Component) ([]core.Component, error) {
+	var selected []core.Component
+	for _, c := range components {
+		if c.ID == "gpl-tools" {
+			c.Selected = v.selectGPL
+		}
+		if c.Selected || c.Required {
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+// TestComponentLicenseAcceptance tests that component licenses join and leave the acceptance set with the selection
+func TestComponentLicenseAcceptance(t *testing.T) {
+	config := &core.Config{
+		AppName:    "LicenseApp",
+		Version:    "1.0.0",
+		InstallDir: filepath.Join(t.TempDir(), "install"),
+		License:    "MIT License",
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Selected: true},
+			{ID: "gpl-tools", Name: "GPL Tools", License: gplText},
+		},
+	}
+	installer := core.New(config)
+	installer.SetContext(&core.Context{
+		Config:   config,
+		Logger:   core.NewLogger("info", ""),
+		Metadata: make(map[string]interface{}),
+	})
+
+	view := &licenseRecordingView{MockExtendedInstallerView: NewMockExtendedInstallerView(), selectGPL: true}
+	ctrl := NewInstallerController(config, installer)
+	ctrl.SetView(view)
+
+	require.NoError(t, ctrl.Start())
+	require.NoError(t, ctrl.Next()) // Welcome -> License
+	require.Len(t, view.licenses, 1)
+	assert.Equal(t, "MIT License", view.licenses[0], "Only the global license applies to the default selection")
+	assert.False(t, ctrl.IsLicenseAccepted(gplText))
+
+	require.NoError(t, ctrl.Next()) // License -> Components, GPL selected
+	require.Len(t, view.licenses, 2, "Selecting the GPL component should require another acceptance")
+	assert.True(t, strings.Contains(view.licenses[1], gplText))
+	assert.False(t, strings.Contains(view.licenses[1], "MIT License"), "Already accepted licenses are not shown again")
+	assert.True(t, ctrl.IsLicenseAccepted(gplText))
+	assert.True(t, ctrl.IsLicenseAccepted("MIT License"))
+
+	record := installer.GetLicenseAcceptance()
+	require.NotNil(t, record)
+	assert.Equal(t, core.HashLicense(core.CombineLicenses(core.ApplicableLicenses(config, config.Components))), record.LicenseHash)
+
+	// Go back and deselect the GPL component
+	view.selectGPL = false
+	require.NoError(t, ctrl.Back())
+	require.NoError(t, ctrl.Next())
+	assert.False(t, ctrl.IsLicenseAccepted(gplText), "Deselected component license should be dropped")
+	assert.True(t, ctrl.IsLicenseAccepted("MIT License"))
+}
+
+// TestComponentLicenseDeclined tests that declining a component license blocks the component state
+func TestComponentLicenseDeclined(t *testing.T) {
+	config := &core.Config{
+		AppName:    "LicenseApp",
+		InstallDir: filepath.Join(t.TempDir(), "install"),
+		License:    "MIT License",
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Selected: true},
+			{ID: "gpl-tools", Name: "GPL Tools", License: gplText},
+		},
+	}
+	ctrl := NewInstallerController(config, core.New(config))
+	view := &decliningView{licenseRecordingView{MockExtendedInstallerView: NewMockExtendedInstallerView(), selectGPL: true}}
+	ctrl.SetView(view)
+
+	require.NoError(t, ctrl.Start())
+	require.NoError(t, ctrl.Next()) // Welcome -> License
+	err := ctrl.Next()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GPL Tools License")
+	assert.False(t, ctrl.IsLicenseAccepted(gplText))
+}
+
+// decliningView accepts the global license but declines component licenses
+type decliningView struct {
+	licenseRecordingView
+}
+
+func (v *decliningView) ShowLicense(license string) (bool, error) {
+	v.licenses = append(v.licenses, license)
+	return len(v.licenses) == 1, nil
+}
+
+const apacheText = "Apache License\nVersion 2.0"
+
+func newTwoLicenseController(t *testing.T) *InstallerController {
+	config := &core.Config{
+		AppName:    "LicenseApp",
+		Version:    "1.0.0",
+		InstallDir: filepath.Join(t.TempDir(), "install"),
+		License:    "MIT License",
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Selected: true},
+			{ID: "gpl-tools", Name: "GPL Tools", License: gplText},
+			{ID: "apache-lib", Name: "Apache Lib", License: apacheText},
+		},
+	}
+	return NewInstallerController(config, core.New(config))
+}
+
+// TestComponentLicensesAcceptedSeparately tests that two components with
+// distinct licenses need two acceptances
+func TestComponentLicensesAcceptedSeparately(t *testing.T) {
+	ctrl := newTwoLicenseController(t)
+	driver := NewTestDriver(ctrl).Input(StateComponents, InputComponents, []string{"gpl-tools", "apache-lib"})
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next))
+
+	assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense, StateComponents, StateInstallPath))
+	assert.Equal(t, []string{"LicenseApp License", "GPL Tools License", "Apache Lib License"}, driver.Licenses(),
+		"each component license is asked for on its own")
+	assert.True(t, ctrl.IsLicenseAccepted(gplText))
+	assert.True(t, ctrl.IsLicenseAccepted(apacheText))
+}
+
+// TestComponentLicenseDeclinedSeparately tests that declining one of two
+// component licenses blocks the selection until the component is deselected
+func TestComponentLicenseDeclinedSeparately(t *testing.T) {
+	ctrl := newTwoLicenseController(t)
+	driver := NewTestDriver(ctrl).
+		Input(StateComponents, InputComponents, []string{"gpl-tools", "apache-lib"}).
+		Input(StateLicense, InputDeclineLicenses, []string{"apache-lib"})
+
+	next := wizard.ActionNext
+	err := driver.Run(next, next)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Apache Lib License")
+	assert.NotContains(t, err.Error(), "GPL Tools License")
+	assert.True(t, ctrl.IsLicenseAccepted(gplText), "the accepted license is kept")
+	assert.False(t, ctrl.IsLicenseAccepted(apacheText))
+
+	// The validation of the selection names the missing acceptance too
+	err = ctrl.validateComponents(map[string]interface{}{"selected_components": []core.Component{
+		{ID: "core", Required: true, Selected: true},
+		{ID: "apache-lib", Name: "Apache Lib", License: apacheText, Selected: true},
+	}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Apache Lib License")
+
+	// Deselecting the component clears the requirement
+	require.NoError(t, ctrl.requireComponentLicenses([]core.Component{
+		{ID: "core", Required: true, Selected: true},
+		{ID: "gpl-tools", Name: "GPL Tools", License: gplText, Selected: true},
+	}))
+	assert.NoError(t, ctrl.validateComponents(map[string]interface{}{"selected_components": []core.Component{
+		{ID: "core", Required: true, Selected: true},
+		{ID: "gpl-tools", Name: "GPL Tools", License: gplText, Selected: true},
+	}}))
+}
+
+// laterLicenseView shows licenses without answering, like the GUI, whose
+// page accepts them afterwards with AcceptLicenses
+type laterLicenseView struct {
+	*MockExtendedInstallerView
+}
+
+func (v *laterLicenseView) ShowLicenses(licenses []core.ApplicableLicense) ([]bool, error) {
+	return make([]bool, len(licenses)), nil
+}
+
+// TestAcceptLicensesLater tests that licenses accepted after the view
+// returned unblock the license state only for the components accepted
+func TestAcceptLicensesLater(t *testing.T) {
+	ctrl := newTwoLicenseController(t)
+	for idx := range ctrl.config.Components {
+		ctrl.config.Components[idx].Selected = true
+	}
+	ctrl.SetView(&laterLicenseView{NewMockExtendedInstallerView()})
+
+	require.NoError(t, ctrl.Start())
+	require.NoError(t, ctrl.Next()) // Welcome -> License
+	require.Error(t, ctrl.Next(), "nothing has been accepted yet")
+
+	require.NoError(t, ctrl.AcceptLicenses([]string{"gpl-tools"}))
+	err := ctrl.Next()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Apache Lib License")
+	assert.True(t, ctrl.IsLicenseAccepted("MIT License"))
+	assert.True(t, ctrl.IsLicenseAccepted(gplText))
+	assert.False(t, ctrl.IsLicenseAccepted(apacheText))
+
+	require.NoError(t, ctrl.AcceptLicenses([]string{"gpl-tools", "apache-lib"}))
+	require.NoError(t, ctrl.Next())
+	assert.Equal(t, StateComponents, ctrl.GetCurrentState())
+}
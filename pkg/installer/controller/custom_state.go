@@ -3,7 +3,9 @@ package controller
 
 import (
 	"fmt"
+	"slices"
 
+	"github.com/mmso2016/setupkit/pkg/installer/core"
 	"github.com/mmso2016/setupkit/pkg/wizard"
 )
 
@@ -28,6 +30,22 @@ type CustomStateHandler interface {
 	GetInsertionPoint() InsertionPoint
 }
 
+// SessionStateHandler is implemented by custom states that work on the
+// typed installation session instead of the data map. The controller calls
+// these methods in place of HandleEnter, HandleLeave and Validate.
+type SessionStateHandler interface {
+	CustomStateHandler
+
+	// HandleEnterSession is called when entering this state
+	HandleEnterSession(controller *InstallerController, session *core.InstallSession) error
+
+	// HandleLeaveSession is called when leaving this state
+	HandleLeaveSession(controller *InstallerController, session *core.InstallSession) error
+
+	// ValidateSession is called to validate the session before proceeding
+	ValidateSession(controller *InstallerController, session *core.InstallSession) error
+}
+
 // InsertionPoint defines where a custom state should be inserted in the flow
 type InsertionPoint struct {
 	After  wizard.State // Insert after this state
@@ -37,8 +55,10 @@ type InsertionPoint struct {
 // CustomStateData holds data for custom states
 type CustomStateData map[string]interface{}
 
-// Common insertion points
+// Common insertion points. States inserted before the welcome screen start
+// the flow and cannot go back.
 var (
+	InsertBeforeWelcome    = InsertionPoint{Before: StateWelcome}
 	InsertAfterWelcome     = InsertionPoint{After: StateWelcome, Before: StateLicense}
 	InsertAfterLicense     = InsertionPoint{After: StateLicense, Before: StateComponents}
 	InsertAfterComponents  = InsertionPoint{After: StateComponents, Before: StateInstallPath}
@@ -72,6 +92,12 @@ func (r *CustomStateRegistry) Register(handler CustomStateHandler) error {
 	return nil
 }
 
+// unregister removes the custom state handler with stateID from the registry
+func (r *CustomStateRegistry) unregister(stateID wizard.State) {
+	delete(r.handlers, stateID)
+	r.order = slices.DeleteFunc(r.order, func(s wizard.State) bool { return s == stateID })
+}
+
 // GetHandler retrieves a custom state handler by ID
 func (r *CustomStateRegistry) GetHandler(stateID wizard.State) (CustomStateHandler, bool) {
 	handler, exists := r.handlers[stateID]
@@ -102,8 +128,11 @@ type BaseCustomStateHandler struct {
 	StateID       wizard.State
 	Name          string
 	Description   string
+	PrimaryLabel  string // Label of the Next button, wizard.DefaultPrimaryLabel if empty
+	HelpText      string // Shown on request, see InstallerController.Help
 	InsertPoint   InsertionPoint
 	ValidateFunc  func(*InstallerController, map[string]interface{}) error
+	ValidateFieldsFunc func(*InstallerController, map[string]interface{}) ValidationResult
 	CanGoNext     bool
 	CanGoBack     bool
 	CanCancel     bool
@@ -119,6 +148,8 @@ func (b *BaseCustomStateHandler) GetConfig() *wizard.StateConfig {
 	config := &wizard.StateConfig{
 		Name:        b.Name,
 		Description: b.Description,
+		PrimaryLabel: b.PrimaryLabel,
+		HelpText:    b.HelpText,
 		CanGoNext:   b.CanGoNext,
 		CanGoBack:   b.CanGoBack,
 		CanCancel:   b.CanCancel,
@@ -150,10 +181,43 @@ func (b *BaseCustomStateHandler) HandleLeave(controller *InstallerController, da
 	return nil
 }
 
-// Validate provides default implementation
+// Validate runs ValidateFunc, then ValidateFieldsFunc
 func (b *BaseCustomStateHandler) Validate(controller *InstallerController, data map[string]interface{}) error {
 	if b.ValidateFunc != nil {
-		return b.ValidateFunc(controller, data)
+		if err := b.ValidateFunc(controller, data); err != nil {
+			return err
+		}
+	}
+	if b.ValidateFieldsFunc != nil {
+		return b.ValidateFieldsFunc(controller, data).Err()
 	}
 	return nil
-}
\ No newline at end of file
+}
+// componentStateHandler limits a custom state to flows where a component is
+// selected, see InstallerController.RegisterComponentState
+type componentStateHandler struct {
+	CustomStateHandler
+	componentID string
+}
+
+// GetConfig implements CustomStateHandler
+func (h *componentStateHandler) GetConfig() *wizard.StateConfig {
+	config := h.CustomStateHandler.GetConfig()
+	config.Optional = true
+	config.CanEnterFunc = func(data map[string]interface{}) bool {
+		return ComponentSelected(data, h.componentID)
+	}
+	return config
+}
+
+// ComponentSelected reports whether the component with id is among the
+// selected components in the flow data
+func ComponentSelected(data map[string]interface{}, id string) bool {
+	components, _ := wizard.DataAs[[]core.Component](data, "selected_components")
+	for _, c := range components {
+		if c.ID == id && c.Selected {
+			return true
+		}
+	}
+	return false
+}
@@ -165,4 +165,33 @@ func TestCustomStateHandlerProperties(t *testing.T) {
 	assert.Equal(t, "localhost", defaultDB.Host, "Default host should be localhost")
 	assert.Equal(t, 3306, defaultDB.Port, "Default port should be 3306")
 	assert.Equal(t, "myapp", defaultDB.Database, "Default database should be myapp")
-}
\ No newline at end of file
+}
+// TestOrphanedCustomStateReported tests that a custom state inserted after a
+// state that does not exist is reported and left out of the flow
+func TestOrphanedCustomStateReported(t *testing.T) {
+	config := &core.Config{
+		AppName:    "TestApp",
+		Version:    "1.0.0",
+		InstallDir: filepath.Join(t.TempDir(), "install"),
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Selected: true},
+		},
+	}
+	controller := NewInstallerController(config, core.New(config))
+
+	orphan := &BaseCustomStateHandler{
+		StateID:     "orphan",
+		Name:        "Orphan",
+		InsertPoint: InsertionPoint{After: "no-such-state"},
+		CanGoNext:   true,
+	}
+	err := controller.RegisterCustomState(orphan)
+	if assert.Error(t, err, "Should report the orphaned state") {
+		assert.Contains(t, err.Error(), "orphan")
+	}
+	assert.Empty(t, controller.GetCustomStates(), "Orphaned state should not stay registered")
+
+	// The flow is unchanged, further states can still be registered
+	assert.NoError(t, controller.RegisterCustomState(NewDatabaseConfigHandler()))
+	assert.Len(t, controller.GetCustomStates(), 1)
+}
@@ -26,6 +26,8 @@ type MockExtendedInstallerView struct {
 	recordedCalls    []string
 	customStateData  map[wizard.State]CustomStateData
 	shouldReturnData CustomStateData
+	fieldErrors      ValidationResult
+	declineConfirm   bool
 }
 
 func NewMockExtendedInstallerView() *MockExtendedInstallerView {
@@ -76,6 +78,22 @@ func (m *MockExtendedInstallerView) ShowErrorMessage(err error) error {
 	return nil
 }
 
+func (m *MockExtendedInstallerView) ShowFieldErrors(result ValidationResult) error {
+	m.recordedCalls = append(m.recordedCalls, "ShowFieldErrors")
+	m.fieldErrors = result
+	return nil
+}
+
+func (m *MockExtendedInstallerView) Confirm(title, message string) (bool, error) {
+	m.recordedCalls = append(m.recordedCalls, "Confirm")
+	return !m.declineConfirm, nil
+}
+
+func (m *MockExtendedInstallerView) ShowHelp(title, help string) error {
+	m.recordedCalls = append(m.recordedCalls, "ShowHelp")
+	return nil
+}
+
 func (m *MockExtendedInstallerView) OnStateChanged(oldState, newState wizard.State) error {
 	m.recordedCalls = append(m.recordedCalls, "OnStateChanged")
 	return nil
@@ -275,9 +293,35 @@ func (suite *CustomStateTestSuite) TestCustomStateIntegration() {
 	suite.NoError(err, "Should transition from custom state to summary")
 
 	// Verify summary was called
+	calls = suite.mockView.GetRecordedCalls()
 	suite.Contains(calls, "ShowSummary", "Summary should be called after custom state")
 }
 
+// TestCustomStateFieldErrors tests that invalid fields reach the view
+func (suite *CustomStateTestSuite) TestCustomStateFieldErrors() {
+	suite.Require().NoError(suite.controller.RegisterCustomState(NewDatabaseConfigHandler()))
+	suite.mockView.SetReturnData(CustomStateData{"config": &DatabaseConfig{
+		Type:     "mysql",
+		Host:     "",
+		Port:     70000,
+		Database: "testdb",
+	}})
+
+	suite.Require().NoError(suite.controller.Start())
+	for i := 0; i < 4; i++ { // Welcome -> ... -> DB Config
+		suite.Require().NoError(suite.controller.Next())
+	}
+	suite.Equal(StateDBConfig, suite.controller.GetCurrentState())
+
+	err := suite.controller.Next()
+	suite.Error(err, "Invalid fields should block Next")
+	suite.Equal(StateDBConfig, suite.controller.GetCurrentState())
+	suite.Contains(suite.mockView.GetRecordedCalls(), "ShowFieldErrors")
+	suite.Len(suite.mockView.fieldErrors, 2)
+	suite.Equal("database host cannot be empty", suite.mockView.fieldErrors.Field("host"))
+	suite.Equal("database port must be between 1 and 65535", suite.mockView.fieldErrors.Field("port"))
+}
+
 // TestCustomStateFlow tests the complete flow with custom states
 func (suite *CustomStateTestSuite) TestCustomStateFlow() {
 	// Register custom handler
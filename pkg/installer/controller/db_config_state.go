@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -123,19 +124,39 @@ func (h *DatabaseConfigHandler) Validate(controller *InstallerController, data m
 		return fmt.Errorf("invalid database configuration type")
 	}
 
+	if result := h.ValidateFields(dbConfig); len(result) > 0 {
+		return result
+	}
+
+	// For non-sqlite databases, validate connection (skip in test environment or demo mode)
+	if dbConfig.Type != "sqlite" && !h.isTestEnvironment() && !h.isDemoMode() {
+		if err := h.validateConnection(dbConfig); err != nil {
+			var result ValidationResult
+			result.AddError("host", fmt.Errorf("database connection validation failed: %w", err))
+			return result
+		}
+	}
+
+	return nil
+}
+
+// ValidateFields checks each field of dbConfig without connecting to the database
+func (h *DatabaseConfigHandler) ValidateFields(dbConfig *DatabaseConfig) ValidationResult {
+	var result ValidationResult
+
 	// Validate host (not required for SQLite)
 	if dbConfig.Type != "sqlite" && strings.TrimSpace(dbConfig.Host) == "" {
-		return fmt.Errorf("database host cannot be empty")
+		result.Add("host", "database host cannot be empty")
 	}
 
 	// Validate port (not required for SQLite)
 	if dbConfig.Type != "sqlite" && (dbConfig.Port <= 0 || dbConfig.Port > 65535) {
-		return fmt.Errorf("database port must be between 1 and 65535")
+		result.Add("port", "database port must be between 1 and 65535")
 	}
 
 	// Validate database name
 	if strings.TrimSpace(dbConfig.Database) == "" {
-		return fmt.Errorf("database name cannot be empty")
+		result.Add("database", "database name cannot be empty")
 	}
 
 	// Validate database type
@@ -146,17 +167,10 @@ func (h *DatabaseConfigHandler) Validate(controller *InstallerController, data m
 		"sqlserver":  true,
 	}
 	if !supportedTypes[dbConfig.Type] {
-		return fmt.Errorf("unsupported database type: %s", dbConfig.Type)
+		result.Add("type", "unsupported database type: %s", dbConfig.Type)
 	}
 
-	// For non-sqlite databases, validate connection (skip in test environment or demo mode)
-	if dbConfig.Type != "sqlite" && !h.isTestEnvironment() && !h.isDemoMode() {
-		if err := h.validateConnection(dbConfig); err != nil {
-			return fmt.Errorf("database connection validation failed: %w", err)
-		}
-	}
-
-	return nil
+	return result
 }
 
 // isTestEnvironment checks if we're running in a test environment
@@ -192,7 +206,7 @@ func (h *DatabaseConfigHandler) isDemoMode() bool {
 // validateConnection tests if we can reach the database host/port
 func (h *DatabaseConfigHandler) validateConnection(config *DatabaseConfig) error {
 	// Simple TCP connection test
-	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	address := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
 	conn, err := net.DialTimeout("tcp", address, 5*time.Second)
 	if err != nil {
 		return fmt.Errorf("cannot connect to %s: %w", address, err)
@@ -0,0 +1,59 @@
+// Package controller provides the feature flags custom state
+package controller
+
+import (
+	"errors"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+const (
+	StateFeatures wizard.State = "features"
+)
+
+// RegisterFeaturesState adds a form after the component selection with a
+// checkbox for each feature flag of the configuration, checked as
+// core.Config.Features sets it. Going on stores the choices in Features,
+// where component installers read them with core.FeatureEnabled.
+func (ic *InstallerController) RegisterFeaturesState() error {
+	names := core.FeatureNames(ic.config.Features)
+	if len(names) == 0 {
+		return errors.New("no feature flags configured")
+	}
+
+	ui := &core.UIStateConfig{
+		Title:       "Features",
+		Description: "Choose the features to enable",
+		Type:        core.UIStateTypeInput,
+	}
+	for _, name := range names {
+		ui.Fields = append(ui.Fields, core.UIField{
+			ID:    name,
+			Label: name,
+			Type:  core.FieldTypeCheckbox,
+			Value: ic.config.Features[name],
+		})
+	}
+
+	return ic.RegisterCustomState(&featuresState{NewFormState(StateFeatures, InsertAfterComponents, ui)})
+}
+
+// featuresState is the form of RegisterFeaturesState
+type featuresState struct {
+	*FormState
+}
+
+// HandleLeave implements CustomStateHandler, storing the choices in Features
+// once the form has been validated, so validating has no side effects
+func (s *featuresState) HandleLeave(controller *InstallerController, data map[string]interface{}) error {
+	if err := s.FormState.HandleLeave(controller, data); err != nil {
+		return err
+	}
+	for name, value := range controller.FormValues(StateFeatures) {
+		if enabled, ok := value.(bool); ok {
+			controller.config.Features[name] = enabled
+		}
+	}
+	return nil
+}
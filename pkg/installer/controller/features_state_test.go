@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+func TestFeaturesStateTogglesFlags(t *testing.T) {
+	controller, _ := newDriverController(t)
+	assert.Error(t, controller.RegisterFeaturesState(), "a form without features")
+
+	controller.config.Features = map[string]bool{"telemetry": false, "dev-mode": true}
+	require.NoError(t, controller.RegisterFeaturesState())
+	driver := NewTestDriver(controller)
+	driver.Input(StateFeatures, "values", map[string]string{"telemetry": "true", "dev-mode": "false"})
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next))
+	require.NoError(t, controller.dfa.ValidateCurrentState())
+	assert.Equal(t, map[string]bool{"telemetry": false, "dev-mode": true}, controller.config.Features,
+		"validating must not change the flags")
+
+	require.NoError(t, driver.Continue(next))
+	assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense, StateComponents, StateFeatures, StateInstallPath))
+	assert.Equal(t, map[string]bool{"telemetry": true, "dev-mode": false}, controller.config.Features)
+}
@@ -0,0 +1,289 @@
+// Package controller provides custom states declared as forms
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+// FormValues holds the values of a form state by field ID, typed by the
+// field: bool for checkboxes, int for numbers, []string for lists, entered
+// separated by commas or lines, and string for all other fields, the option
+// ID for radios and dropdowns
+type FormValues map[string]interface{}
+
+// FormStateHandler is a custom state declared as a form. Views render the
+// fields of its UIConfig without code for the state: ShowCustomState
+// receives "form", the *core.UIStateConfig, and "values", the current
+// FormValues, and returns the entered "values", typed or as strings. Views
+// that return before the user has entered anything call
+// InstallerController.SetFormValues instead.
+type FormStateHandler interface {
+	CustomStateHandler
+	UIConfig() *core.UIStateConfig
+}
+
+// FormState is a custom state made of the fields of UI. The entered values
+// are checked against the field declarations before ValidateFunc and
+// ValidateFieldsFunc run, and are read with InstallerController.FormValues.
+type FormState struct {
+	*BaseCustomStateHandler
+	UI *core.UIStateConfig
+}
+
+// NewFormState creates a form state inserted at insert, named after the title of ui
+func NewFormState(stateID wizard.State, insert InsertionPoint, ui *core.UIStateConfig) *FormState {
+	return &FormState{
+		BaseCustomStateHandler: &BaseCustomStateHandler{
+			StateID:     stateID,
+			Name:        ui.Title,
+			Description: ui.Description,
+			HelpText:    ui.Help,
+			InsertPoint: insert,
+			CanGoNext:   true,
+			CanGoBack:   true,
+			CanCancel:   true,
+		},
+		UI: ui,
+	}
+}
+
+// UIConfig implements FormStateHandler
+func (s *FormState) UIConfig() *core.UIStateConfig {
+	return s.UI
+}
+
+// HandleEnter implements CustomStateHandler
+func (s *FormState) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	view, ok := controller.view.(ExtendedInstallerView)
+	if !ok {
+		return fmt.Errorf("view does not support custom states")
+	}
+	result, err := view.ShowCustomState(s.StateID, CustomStateData{
+		"form":   s.UI,
+		"values": controller.FormValues(s.StateID),
+	})
+	if err != nil {
+		return err
+	}
+	if values, ok := result["values"]; ok {
+		return controller.SetFormValues(s.StateID, values)
+	}
+	return nil
+}
+
+// Validate checks the values against the field declarations, then runs
+// ValidateFunc and ValidateFieldsFunc
+func (s *FormState) Validate(controller *InstallerController, data map[string]interface{}) error {
+	if result := ValidateFormValues(s.UI, controller.FormValues(s.StateID)); len(result) > 0 {
+		return result
+	}
+	return s.BaseCustomStateHandler.Validate(controller, data)
+}
+
+// FormDefaults returns the Value of each field of ui converted to its type
+func FormDefaults(ui *core.UIStateConfig) FormValues {
+	values := make(FormValues, len(ui.Fields))
+	for _, field := range ui.Fields {
+		value, err := ParseFieldValue(field, field.Value)
+		if err != nil {
+			value, _ = ParseFieldValue(field, nil)
+		}
+		values[field.ID] = value
+	}
+	return values
+}
+
+// ParseFormValues converts raw, a map of field IDs to typed or string values,
+// to the types of the fields of ui and merges it over current. Values that
+// cannot be converted are reported per field.
+func ParseFormValues(ui *core.UIStateConfig, current FormValues, raw interface{}) (FormValues, ValidationResult) {
+	entered := make(map[string]interface{})
+	switch raw := raw.(type) {
+	case FormValues:
+		entered = raw
+	case map[string]interface{}:
+		entered = raw
+	case map[string]string:
+		for id, value := range raw {
+			entered[id] = value
+		}
+	case CustomStateData:
+		entered = raw
+	}
+
+	values := make(FormValues, len(ui.Fields))
+	var result ValidationResult
+	for _, field := range ui.Fields {
+		values[field.ID] = current[field.ID]
+		value, ok := entered[field.ID]
+		if !ok {
+			continue
+		}
+		parsed, err := ParseFieldValue(field, value)
+		if err != nil {
+			result.AddError(field.ID, err)
+			continue
+		}
+		values[field.ID] = parsed
+	}
+	return values, result
+}
+
+// ParseFieldValue converts value, typed or a string, to the type of field.
+// nil gives the zero value of the type.
+func ParseFieldValue(field core.UIField, value interface{}) (interface{}, error) {
+	switch field.Type {
+	case core.FieldTypeCheckbox:
+		switch v := value.(type) {
+		case nil:
+			return false, nil
+		case bool:
+			return v, nil
+		case string:
+			switch strings.ToLower(strings.TrimSpace(v)) {
+			case "", "n", "no", "off", "false", "0":
+				return false, nil
+			case "y", "yes", "on", "true", "1":
+				return true, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be yes or no", fieldName(field))
+
+	case core.FieldTypeNumber:
+		switch v := value.(type) {
+		case nil:
+			return 0, nil
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), nil
+			}
+		case string:
+			if strings.TrimSpace(v) == "" {
+				return 0, nil
+			}
+			if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return n, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be a whole number", fieldName(field))
+
+	case core.FieldTypeList:
+		switch v := value.(type) {
+		case nil:
+			return []string{}, nil
+		case []string:
+			return v, nil
+		case []interface{}:
+			list := make([]string, 0, len(v))
+			for _, item := range v {
+				list = append(list, fmt.Sprint(item))
+			}
+			return list, nil
+		case string:
+			list := []string{}
+			items := strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == '\n' })
+			for _, item := range items {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			return list, nil
+		}
+		return nil, fmt.Errorf("%s must be a list", fieldName(field))
+
+	default:
+		if value == nil {
+			return "", nil
+		}
+		if s, ok := value.(string); ok {
+			if field.Type == core.FieldTypePassword || field.Type == core.FieldTypeTextArea {
+				return s, nil
+			}
+			return strings.TrimSpace(s), nil
+		}
+		return fmt.Sprint(value), nil
+	}
+}
+
+// ValidateFormValues checks values against the declarations of ui: required
+// fields must be set, text must match the Validation pattern and choices
+// must be one of the enabled options
+func ValidateFormValues(ui *core.UIStateConfig, values FormValues) ValidationResult {
+	var result ValidationResult
+	for _, field := range ui.Fields {
+		value := values[field.ID]
+		if field.Required && isEmptyFieldValue(value) {
+			result.Add(field.ID, "%s is required", fieldName(field))
+			continue
+		}
+
+		s, isString := value.(string)
+		if !isString || s == "" {
+			continue
+		}
+		if len(field.Options) > 0 && !hasFieldOption(field, s) {
+			result.Add(field.ID, "%s must be one of %s", fieldName(field), strings.Join(fieldOptionIDs(field), ", "))
+			continue
+		}
+		if field.Validation != "" {
+			pattern, err := regexp.Compile(field.Validation)
+			if err != nil {
+				result.Add(field.ID, "invalid validation pattern for %s: %v", fieldName(field), err)
+			} else if !pattern.MatchString(s) {
+				result.Add(field.ID, "%s is not valid", fieldName(field))
+			}
+		}
+	}
+	return result
+}
+
+// fieldName returns the label of field, or its ID without one
+func fieldName(field core.UIField) string {
+	if field.Label != "" {
+		return field.Label
+	}
+	return field.ID
+}
+
+func isEmptyFieldValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []string:
+		return len(v) == 0
+	case bool:
+		return !v
+	}
+	return false
+}
+
+func hasFieldOption(field core.UIField, id string) bool {
+	for _, option := range field.Options {
+		if option.ID == id && !option.Disabled {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldOptionIDs(field core.UIField) []string {
+	var ids []string
+	for _, option := range field.Options {
+		if !option.Disabled {
+			ids = append(ids, option.ID)
+		}
+	}
+	return ids
+}
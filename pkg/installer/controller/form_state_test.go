@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+const stateDBForm wizard.State = "db_form"
+
+func dbForm() *core.UIStateConfig {
+	return &core.UIStateConfig{
+		Title:       "Database",
+		Description: "Connection to the database server",
+		Fields: []core.UIField{
+			{ID: "host", Label: "Host", Type: core.FieldTypeText, Required: true},
+			{ID: "port", Label: "Port", Type: core.FieldTypeNumber, Value: "5432"},
+			{ID: "ssl", Label: "Use TLS", Type: core.FieldTypeCheckbox, Value: true},
+			{ID: "engine", Label: "Engine", Type: core.FieldTypeDropdown, Value: "postgres", Options: []core.FieldOption{
+				{ID: "postgres", Label: "PostgreSQL"},
+				{ID: "mysql", Label: "MySQL"},
+				{ID: "oracle", Label: "Oracle", Disabled: true},
+			}},
+			{ID: "schemas", Label: "Schemas", Type: core.FieldTypeList},
+			{ID: "user", Label: "User", Type: core.FieldTypeText, Validation: `^[a-z]+$`},
+		},
+	}
+}
+
+func TestFormStateCollectsTypedValues(t *testing.T) {
+	controller, _ := newDriverController(t)
+	require.NoError(t, controller.RegisterCustomState(NewFormState(stateDBForm, InsertAfterInstallPath, dbForm())))
+	assert.Equal(t, FormValues{
+		"host": "", "port": 5432, "ssl": true, "engine": "postgres", "schemas": []string{}, "user": "",
+	}, controller.FormValues(stateDBForm))
+
+	driver := NewTestDriver(controller)
+	driver.Input(stateDBForm, "values", map[string]string{
+		"host":    " db.example ",
+		"port":    "6543",
+		"ssl":     "no",
+		"engine":  "mysql",
+		"schemas": "app, audit\nreports",
+	})
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, next))
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath, stateDBForm, StateSummary))
+	assert.Equal(t, FormValues{
+		"host":    "db.example",
+		"port":    6543,
+		"ssl":     false,
+		"engine":  "mysql",
+		"schemas": []string{"app", "audit", "reports"},
+		"user":    "",
+	}, controller.FormValues(stateDBForm))
+	assert.Nil(t, controller.FormValues(StateLicense), "only form states have values")
+}
+
+func TestFormStateRejectsInvalidValues(t *testing.T) {
+	controller, _ := newDriverController(t)
+	require.NoError(t, controller.RegisterCustomState(NewFormState(stateDBForm, InsertAfterInstallPath, dbForm())))
+	driver := NewTestDriver(controller)
+	driver.Input(stateDBForm, "values", map[string]string{
+		"engine": "oracle",
+		"user":   "Admin",
+	})
+
+	next := wizard.ActionNext
+	err := driver.Run(next, next, next, next, next)
+	require.Error(t, err)
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath, stateDBForm))
+
+	var fields []string
+	for _, e := range driver.FieldErrors() {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"host", "engine", "user"}, fields)
+}
+
+func TestSetFormValuesReportsConversionErrors(t *testing.T) {
+	controller, _ := newDriverController(t)
+	require.NoError(t, controller.RegisterCustomState(NewFormState(stateDBForm, InsertAfterInstallPath, dbForm())))
+
+	err := controller.SetFormValues(stateDBForm, map[string]string{"port": "54x", "ssl": "maybe", "host": "db"})
+	require.Error(t, err)
+	result, ok := err.(ValidationResult)
+	require.True(t, ok)
+	require.Len(t, result, 2)
+	assert.Equal(t, "port", result[0].Field)
+	assert.Equal(t, "ssl", result[1].Field)
+	assert.Equal(t, "", controller.FormValues(stateDBForm)["host"], "rejected input must not be stored")
+
+	require.NoError(t, controller.SetFormValues(stateDBForm, map[string]interface{}{"port": 3306.0, "schemas": []interface{}{"app"}}))
+	values := controller.FormValues(stateDBForm)
+	assert.Equal(t, 3306, values["port"])
+	assert.Equal(t, []string{"app"}, values["schemas"])
+
+	assert.Error(t, controller.SetFormValues(StateLicense, map[string]string{"host": "db"}))
+}
+
+func TestParseFieldValue(t *testing.T) {
+	number := core.UIField{ID: "port", Type: core.FieldTypeNumber}
+	value, err := ParseFieldValue(number, " 80 ")
+	require.NoError(t, err)
+	assert.Equal(t, 80, value)
+	_, err = ParseFieldValue(number, 1.5)
+	assert.EqualError(t, err, "port must be a whole number")
+
+	password := core.UIField{ID: "secret", Type: core.FieldTypePassword}
+	value, err = ParseFieldValue(password, " pw ")
+	require.NoError(t, err)
+	assert.Equal(t, " pw ", value, "passwords are kept as entered")
+
+	checkbox := core.UIField{ID: "ssl", Label: "Use TLS", Type: core.FieldTypeCheckbox}
+	value, err = ParseFieldValue(checkbox, "Yes")
+	require.NoError(t, err)
+	assert.Equal(t, true, value)
+	_, err = ParseFieldValue(checkbox, 1)
+	assert.EqualError(t, err, "Use TLS must be yes or no")
+}
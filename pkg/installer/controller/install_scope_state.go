@@ -0,0 +1,104 @@
+// Package controller provides the install scope custom state
+package controller
+
+import (
+	"fmt"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+const (
+	StateInstallScope wizard.State = "install-scope"
+)
+
+// InstallScopeHandler lets the user choose between installing for all users
+// and just for themselves. NewInstallerController adds it after the welcome
+// screen when Config.AskInstallScope is set and core.Config.InstallScopeChoice
+// allows it, that is unless the configuration or the platform forces the
+// scope.
+//
+// The view receives "scope", the default choice by name, "per-user" or
+// "per-machine" (see core.InstallScope.String), and "elevated", whether the
+// installer runs with administrator rights, and returns the chosen "scope".
+// Views that return before the user has chosen call
+// InstallerController.SetInstallScope instead. The choice goes to
+// core.Config.SetInstallScope, which sets the default installation directory
+// and PATH scope, and is stored in the state data and the ResponseSettings
+// as "install_scope".
+type InstallScopeHandler struct {
+	*BaseCustomStateHandler
+	config *core.Config
+
+	// elevated reports whether the installer runs elevated, which makes
+	// installing for all users the default
+	elevated func() bool
+}
+
+// NewInstallScopeHandler creates an install scope handler shown after the welcome screen
+func NewInstallScopeHandler(config *core.Config) *InstallScopeHandler {
+	return &InstallScopeHandler{
+		BaseCustomStateHandler: &BaseCustomStateHandler{
+			StateID:     StateInstallScope,
+			Name:        "Installation Scope",
+			Description: "Choose who the application is installed for",
+			InsertPoint: InsertAfterWelcome,
+			CanGoNext:   true,
+			CanGoBack:   true,
+			CanCancel:   true,
+		},
+		config:   config,
+		elevated: core.DefaultPathSystem,
+	}
+}
+
+// GetConfig implements CustomStateHandler
+func (h *InstallScopeHandler) GetConfig() *wizard.StateConfig {
+	config := h.BaseCustomStateHandler.GetConfig()
+	config.Optional = true
+	config.CanEnterFunc = func(data map[string]interface{}) bool {
+		return !h.config.Portable
+	}
+	return config
+}
+
+// HandleEnter implements CustomStateHandler
+func (h *InstallScopeHandler) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	view, ok := controller.view.(ExtendedInstallerView)
+	if !ok {
+		return fmt.Errorf("view does not support custom states")
+	}
+	elevated := h.elevated()
+
+	// The first visit defaults by elevation, later ones keep the choice
+	scope := h.config.InstallScope
+	if scope == core.ScopeAuto {
+		scope = core.ScopePerUser
+		if elevated {
+			scope = core.ScopePerMachine
+		}
+	}
+
+	result, err := view.ShowCustomState(StateInstallScope, CustomStateData{
+		"scope":    scope.String(),
+		"elevated": elevated,
+	})
+	if err != nil {
+		return err
+	}
+	name := scope.String()
+	if chosen, _ := result["scope"].(string); chosen != "" {
+		name = chosen
+	}
+	if err := controller.SetInstallScope(name); err != nil {
+		return err
+	}
+	data["install_scope"] = h.config.InstallScope.String()
+	return nil
+}
+
+// HandleLeave implements CustomStateHandler by recording the scope chosen
+func (h *InstallScopeHandler) HandleLeave(controller *InstallerController, data map[string]interface{}) error {
+	data["install_scope"] = h.config.InstallScope.String()
+	return nil
+}
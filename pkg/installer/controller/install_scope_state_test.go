@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+func newInstallScopeController(t *testing.T, elevated bool) *InstallerController {
+	controller, _ := newDriverController(t)
+	controller.config.InstallDir = ""
+	controller.config.AskInstallScope = true
+	controller.config.PathConfig = &core.PathConfiguration{Enabled: true, Dirs: []string{"bin"}}
+	if !controller.config.InstallScopeChoice() {
+		t.Skip("the installer can neither elevate nor ask for elevation here")
+	}
+	controller = NewInstallerController(controller.config, controller.installer)
+	handler, exists := controller.customStates.GetHandler(StateInstallScope)
+	require.True(t, exists)
+	handler.(*InstallScopeHandler).elevated = func() bool { return elevated }
+	return controller
+}
+
+func TestInstallScopeDefaultsByElevation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		elevated bool
+		scope    core.InstallScope
+	}{
+		"elevated":     {elevated: true, scope: core.ScopePerMachine},
+		"not elevated": {elevated: false, scope: core.ScopePerUser},
+	} {
+		t.Run(name, func(t *testing.T) {
+			controller := newInstallScopeController(t, tc.elevated)
+			driver := NewTestDriver(controller)
+
+			next := wizard.ActionNext
+			require.NoError(t, driver.Run(next, next, next, next, next))
+
+			assert.NoError(t, driver.AssertStates(
+				StateWelcome, StateInstallScope, StateLicense, StateComponents,
+				StateInstallPath, StateSummary))
+			assert.Equal(t, tc.scope, controller.config.InstallScope)
+			assert.Equal(t, tc.scope.String(), driver.Data()["install_scope"])
+			assert.Equal(t, core.DefaultInstallDir("DriverApp", tc.scope), driver.Data()["install_path"])
+			assert.Equal(t, tc.scope == core.ScopePerMachine, controller.config.PathConfig.System)
+		})
+	}
+}
+
+func TestInstallScopeChoiceSetsDefaults(t *testing.T) {
+	controller := newInstallScopeController(t, false)
+	driver := NewTestDriver(controller).Input(StateInstallScope, "scope", core.ScopePerMachine.String())
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, next))
+
+	assert.Equal(t, core.DefaultInstallDir("DriverApp", core.ScopePerMachine), controller.config.InstallDir)
+	assert.Equal(t, core.PathScopeNameSystem, controller.config.PathConfig.Scope())
+	assert.Equal(t, core.ScopePerMachine.String(), controller.ResponseSettings()[core.SettingInstallScope])
+
+	kinds := make([]core.PrivilegeKind, 0)
+	for _, p := range core.PrivilegesFor(controller.config, nil, controller.config.InstallDir) {
+		kinds = append(kinds, p.Kind)
+	}
+	assert.Contains(t, kinds, core.PrivilegeSystemPath)
+
+	// Going back to just me moves the proposed directory and PATH along
+	require.NoError(t, controller.SetInstallScope(core.ScopePerUser.String()))
+	assert.Equal(t, core.DefaultInstallDir("DriverApp", core.ScopePerUser), controller.config.InstallDir)
+	assert.False(t, controller.config.PathConfig.System)
+	assert.Error(t, controller.SetInstallScope("everyone"))
+}
+
+func TestInstallScopeStateSkippedWhenForced(t *testing.T) {
+	controller, _ := newDriverController(t)
+	controller.config.AskInstallScope = true
+	controller.config.InstallScope = core.ScopePerUser
+	controller = NewInstallerController(controller.config, controller.installer)
+
+	_, exists := controller.customStates.GetHandler(StateInstallScope)
+	assert.False(t, exists, "a configured scope is not asked for")
+
+	controller = newInstallScopeController(t, true)
+	controller.config.Portable = true
+	driver := NewTestDriver(controller)
+	require.NoError(t, driver.Run(wizard.ActionNext))
+	assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense))
+	assert.NotContains(t, driver.Data(), "install_scope")
+}
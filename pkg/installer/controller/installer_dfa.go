@@ -2,7 +2,15 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	
 	"github.com/mmso2016/setupkit/pkg/installer/core"
 	"github.com/mmso2016/setupkit/pkg/wizard"
@@ -20,6 +28,9 @@ const (
 	StateCancelled   wizard.State = "cancelled"
 )
 
+// ErrCancelDeclined is returned by Cancel if the user chose to continue
+var ErrCancelDeclined = errors.New("cancel declined, the installation continues")
+
 // InstallerController manages the installation flow using DFA
 type InstallerController struct {
 	dfa        *wizard.DFA
@@ -29,9 +40,50 @@ type InstallerController struct {
 	// View interface - both CLI and GUI implement this
 	view       InstallerView
 
-	// Custom state support
+	// Custom state support; stateData holds the custom values of the session
 	customStates *CustomStateRegistry
+	session      *core.InstallSession
 	stateData    map[string]interface{}
+
+	// Hashes of accepted license texts
+	acceptedLicenses map[string]bool
+
+	// Non-empty installation directory the user agreed to install into
+	overwriteConfirmed string
+
+	// Operations a dry run would have performed
+	plannedOperations []string
+
+	// Set by the TestDriver: the progress state records the plan and moves
+	// on without running the installation and its progress handlers
+	planOnly bool
+
+	// State configurations, readable while the DFA is locked by a transition
+	stateConfigs map[wizard.State]*wizard.StateConfig
+
+	// States entered so far, in order, for diagnostics
+	historyMu sync.Mutex
+	history   []wizard.State
+
+	// Recommended system requirements the computer falls short of
+	requirementsOnce    sync.Once
+	requirementWarnings []string
+
+	// Translates the screens into the chosen locale
+	localizer *core.Localizer
+
+	// Values entered into form states, which views may set from their own goroutines
+	formMu     sync.Mutex
+	formValues map[wizard.State]FormValues
+
+	// Closed when the installation started by the progress state has ended
+	installDone chan struct{}
+
+	// Ends work of the states, such as an activation request, once the
+	// user cancels; validating is set while a custom state validates
+	ctx        context.Context
+	stop       context.CancelFunc
+	validating atomic.Bool
 }
 
 // InstallerView interface that both CLI and GUI must implement
@@ -50,17 +102,78 @@ type InstallerView interface {
 	OnStateChanged(oldState, newState wizard.State) error
 }
 
+// LicenseAcceptanceView is implemented by views that let the user accept
+// each applicable license on its own, e.g. with a checkbox per component
+// license. Other views show the licenses combined for a single acceptance.
+type LicenseAcceptanceView interface {
+	// ShowLicenses shows licenses and returns, in the same order, whether
+	// each was accepted
+	ShowLicenses(licenses []core.ApplicableLicense) (accepted []bool, err error)
+}
+
+// ConfirmView is implemented by views that can ask a yes/no question, e.g.
+// before cancelling. Without it the controller takes the answer that does
+// not put the user's files at risk, see Cancel and confirmOverwrite.
+type ConfirmView interface {
+	// Confirm asks a yes/no question
+	Confirm(title, message string) (bool, error)
+}
+
+// FieldErrorsView is implemented by views that mark the invalid fields of a
+// form. Other views only see the ValidationResult as the error of the
+// transition, whose message names each field.
+type FieldErrorsView interface {
+	// ShowFieldErrors shows the errors next to their fields
+	ShowFieldErrors(result ValidationResult) error
+}
+
+// HelpView is implemented by views that can show the help of a state, e.g.
+// in a dialog. Other views show CurrentHelp themselves.
+type HelpView interface {
+	// ShowHelp shows the help of the current state without leaving it
+	ShowHelp(title, help string) error
+}
+
 // NewInstallerController creates a new DFA-based installer controller
 func NewInstallerController(config *core.Config, installer *core.Installer) *InstallerController {
+	// The views and the installer share the session of a running installer
+	var session *core.InstallSession
+	if installer != nil {
+		session = installer.Session()
+	}
+	if session == nil {
+		session = core.NewInstallSession(config, installer)
+	}
 	controller := &InstallerController{
 		dfa:          wizard.New(),
 		config:       config,
 		installer:    installer,
 		customStates: NewCustomStateRegistry(),
-		stateData:    make(map[string]interface{}),
+		session:      session,
+		stateData:    session.Values(),
+		acceptedLicenses: make(map[string]bool),
+		localizer:    core.NewLocalizer(config),
 	}
+	controller.ctx, controller.stop = context.WithCancel(context.Background())
 
+	if locales := core.AvailableLocales(config); config.Locale == "" && len(locales) > 1 {
+		controller.customStates.Register(NewLanguageHandler(config))
+	} else if config.Locale == "" && len(locales) == 1 {
+		controller.localizer.SetLocale(locales[0])
+	}
+	if len(config.ReleaseNotes) > 0 {
+		controller.customStates.Register(NewReleaseNotesHandler(config))
+	}
+	if config.AskInstallScope && config.InstallScopeChoice() {
+		controller.customStates.Register(NewInstallScopeHandler(config))
+	}
+	if pc := config.PathConfig; pc != nil && pc.Enabled && pc.AskScope {
+		controller.customStates.Register(NewPathScopeHandler(config))
+	}
 	controller.setupDFA()
+	if installer != nil {
+		installer.SetStateHistory(controller.stateHistory)
+	}
 	return controller
 }
 
@@ -69,7 +182,9 @@ func (ic *InstallerController) SetView(view InstallerView) {
 	ic.view = view
 }
 
-// RegisterCustomState adds a custom state to the installation flow
+// RegisterCustomState adds a custom state to the installation flow. A state
+// its insertion point leaves unreachable, such as one inserted after a state
+// that does not exist, is left out and reported as an error.
 func (ic *InstallerController) RegisterCustomState(handler CustomStateHandler) error {
 	if err := ic.customStates.Register(handler); err != nil {
 		return err
@@ -77,9 +192,37 @@ func (ic *InstallerController) RegisterCustomState(handler CustomStateHandler) e
 
 	// Rebuild DFA with custom states included
 	ic.setupDFA()
+	if err := ic.checkCustomStatesReachable(); err != nil {
+		ic.customStates.unregister(handler.GetStateID())
+		ic.setupDFA()
+		return err
+	}
 	return nil
 }
 
+// RegisterComponentState adds a configuration state for the component with
+// componentID. The flow enters it only while the component is selected and
+// passes over it otherwise, so it must come after the components state.
+func (ic *InstallerController) RegisterComponentState(componentID string, handler CustomStateHandler) error {
+	found := false
+	for _, c := range ic.config.Components {
+		if c.ID == componentID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown component %s", componentID)
+	}
+	switch after := handler.GetInsertionPoint().After; after {
+	case StateWelcome, StateLicense:
+		return fmt.Errorf("state %s for component %s must come after the components state, not %s",
+			handler.GetStateID(), componentID, after)
+	}
+
+	return ic.RegisterCustomState(&componentStateHandler{CustomStateHandler: handler, componentID: componentID})
+}
+
 // GetCustomStates returns all registered custom states
 func (ic *InstallerController) GetCustomStates() []CustomStateHandler {
 	return ic.customStates.GetAll()
@@ -90,27 +233,75 @@ func (ic *InstallerController) GetStateData() map[string]interface{} {
 	return ic.stateData
 }
 
+// Session returns the installation session, which has the installation
+// path and the selected components once their states are done and the
+// values of the custom states
+func (ic *InstallerController) Session() *core.InstallSession {
+	return ic.session
+}
+
+// SetSelectedComponents selects the components with ids, for hosts that
+// drive the selection themselves; the components step then shows it. The
+// dependencies of the components are selected as well and required ones stay
+// selected. An unknown component or dependency leaves the selection unchanged.
+func (ic *InstallerController) SetSelectedComponents(ids []string) error {
+	components := slices.Clone(ic.config.Components)
+	if err := core.SelectComponents(components, ids); err != nil {
+		return fmt.Errorf("invalid component selection: %w", err)
+	}
+	for idx := range ic.config.Components {
+		ic.config.Components[idx].Selected = components[idx].Selected
+	}
+
+	selected := selectedOnly(components)
+	for idx := range selected {
+		selected[idx].Selected = true
+	}
+	ic.session.SetSelectedComponents(selected)
+	return ic.dfa.SetData("selected_components", selected)
+}
+
+// GetSelectedComponents returns the components selected so far, required
+// components included
+func (ic *InstallerController) GetSelectedComponents() []core.Component {
+	if selected, ok := ic.dfa.GetData("selected_components"); ok {
+		if components, ok := selected.([]core.Component); ok {
+			return selectedOnly(components)
+		}
+	}
+	return ic.defaultSelection()
+}
+
 // setupDFA configures the DFA states and transitions
 func (ic *InstallerController) setupDFA() {
 	// Clear existing DFA and create a new one to avoid duplicate states
 	ic.dfa = wizard.New()
+	ic.stateConfigs = make(map[wizard.State]*wizard.StateConfig)
 
 	// Configure DFA callbacks
 	callbacks := &wizard.Callbacks{
 		OnEnter: func(state wizard.State, data map[string]interface{}) error {
+			ic.historyMu.Lock()
+			ic.history = append(ic.history, state)
+			ic.historyMu.Unlock()
 			return ic.handleStateEnter(state, data)
 		},
 		OnLeave: func(state wizard.State, data map[string]interface{}) error {
 			return ic.handleStateLeave(state, data)
 		},
+		BeforeTransition: ic.beforeTransition,
 		OnTransition: func(from, to wizard.State, action wizard.Action) error {
 			if ic.view != nil {
 				return ic.view.OnStateChanged(from, to)
 			}
 			return nil
 		},
+		OnPanic: func(state wizard.State, err *wizard.PanicError) {
+			ic.logPanic(err)
+		},
 	}
 	ic.dfa.SetCallbacks(callbacks)
+	ic.dfa.SetForwardOnly(ic.config.ForwardOnly)
 	
 	// Add states with their configurations
 	ic.addState(StateWelcome, &wizard.StateConfig{
@@ -141,6 +332,7 @@ func (ic *InstallerController) setupDFA() {
 	ic.addState(StateComponents, &wizard.StateConfig{
 		Name:        "Component Selection",
 		Description: "Select components to install",
+		HelpText:    "Required components are always installed. Select the optional components you want; the summary shows the disk space they need.",
 		CanGoNext:   true,
 		CanGoBack:   true,
 		CanCancel:   true,
@@ -155,6 +347,7 @@ func (ic *InstallerController) setupDFA() {
 	ic.addState(StateInstallPath, &wizard.StateConfig{
 		Name:        "Installation Path",
 		Description: "Select installation directory",
+		HelpText:    "The directory receives the application files. Installing into a directory that already contains other files asks before overwriting them; an existing installation of the application is updated.",
 		CanGoNext:   true,
 		CanGoBack:   true,
 		CanCancel:   true,
@@ -169,6 +362,7 @@ func (ic *InstallerController) setupDFA() {
 	ic.addState(StateSummary, &wizard.StateConfig{
 		Name:        "Installation Summary",
 		Description: "Review installation settings",
+		PrimaryLabel: "Install",
 		CanGoNext:   true,
 		CanGoBack:   true,
 		CanCancel:   true,
@@ -179,20 +373,26 @@ func (ic *InstallerController) setupDFA() {
 		},
 	})
 	
-	ic.addState(StateProgress, &wizard.StateConfig{
+	progress := &wizard.StateConfig{
 		Name:        "Installing",
 		Description: "Installation in progress",
 		CanGoNext:   false,
 		CanGoBack:   false,
-		CanCancel:   false,
+		CanCancel:   ic.config.AllowInstallCancel,
 		Transitions: map[wizard.Action]wizard.State{
 			wizard.ActionNext: StateComplete, // Automatic transition after install
 		},
-	})
+	}
+	if ic.config.AllowInstallCancel {
+		// Taken once the cancelled installation has rolled back, see Cancel
+		progress.Transitions[wizard.ActionCancel] = StateCancelled
+	}
+	ic.addState(StateProgress, progress)
 	
 	ic.addState(StateComplete, &wizard.StateConfig{
 		Name:        "Installation Complete",
 		Description: "Installation finished successfully",
+		PrimaryLabel: "Finish",
 		CanGoNext:   false,
 		CanGoBack:   false,
 		CanCancel:   false,
@@ -210,11 +410,22 @@ func (ic *InstallerController) setupDFA() {
 	ic.integrateCustomStates()
 
 	// Set initial state
-	ic.dfa.SetInitialState(StateWelcome)
+	ic.dfa.SetInitialState(ic.initialState())
 	ic.dfa.AddFinalState(StateComplete)
 	ic.dfa.AddFinalState(StateCancelled)
 }
 
+// initialState returns the first custom state inserted before the welcome
+// screen, StateWelcome if there is none
+func (ic *InstallerController) initialState() wizard.State {
+	for _, handler := range ic.customStates.GetAll() {
+		if handler.GetInsertionPoint().After == "" {
+			return handler.GetStateID()
+		}
+	}
+	return StateWelcome
+}
+
 // Helper methods for conditional next states
 func (ic *InstallerController) getNextStateAfterWelcome() wizard.State {
 	if ic.config.License != "" {
@@ -252,11 +463,14 @@ func (ic *InstallerController) integrateCustomStates() {
 		if config.ValidateFunc != nil {
 			originalValidate := config.ValidateFunc
 			config.ValidateFunc = func(data map[string]interface{}) error {
-				return originalValidate(data)
+				return ic.showFieldErrors(originalValidate(data))
 			}
 		} else {
 			// Use the handler's validation method - merge with persistent state data
 			config.ValidateFunc = func(data map[string]interface{}) error {
+				if sessionHandler, ok := handler.(SessionStateHandler); ok {
+					return ic.showFieldErrors(sessionHandler.ValidateSession(ic, ic.session))
+				}
 				// Merge global state data with current data (same as HandleEnter/HandleLeave)
 				mergedData := make(map[string]interface{})
 				for k, v := range ic.stateData {
@@ -265,10 +479,18 @@ func (ic *InstallerController) integrateCustomStates() {
 				for k, v := range data {
 					mergedData[k] = v
 				}
-				return handler.Validate(ic, mergedData)
+				return ic.showFieldErrors(handler.Validate(ic, mergedData))
 			}
 		}
 
+		// Cancel does not wait for a validation holding the flow, see Cancel
+		validate := config.ValidateFunc
+		config.ValidateFunc = func(data map[string]interface{}) error {
+			ic.validating.Store(true)
+			defer ic.validating.Store(false)
+			return validate(data)
+		}
+
 		ic.addState(handler.GetStateID(), config)
 	}
 
@@ -276,6 +498,32 @@ func (ic *InstallerController) integrateCustomStates() {
 	ic.rebuildTransitions(insertionGroups)
 }
 
+// checkCustomStatesReachable returns an error listing the custom states no
+// transition leads to from the initial state, such as those inserted after a
+// state that does not exist
+func (ic *InstallerController) checkCustomStatesReachable() error {
+	var orphaned []string
+	for _, state := range ic.dfa.Unreachable() {
+		if _, custom := ic.customStates.GetHandler(state); custom {
+			orphaned = append(orphaned, string(state))
+		}
+	}
+	if len(orphaned) > 0 {
+		return fmt.Errorf("custom states not reachable from %s: %s", ic.initialState(), strings.Join(orphaned, ", "))
+	}
+	return nil
+}
+
+// showFieldErrors passes a ValidationResult returned by validation to a
+// FieldErrorsView, so it can mark the fields, and returns err unchanged
+func (ic *InstallerController) showFieldErrors(err error) error {
+	var result ValidationResult
+	if view, ok := ic.view.(FieldErrorsView); ok && errors.As(err, &result) {
+		view.ShowFieldErrors(result)
+	}
+	return err
+}
+
 // rebuildTransitions updates state transitions to include custom states
 func (ic *InstallerController) rebuildTransitions(insertionGroups map[wizard.State][]CustomStateHandler) {
 	// Standard flow: Welcome -> [License] -> Components -> InstallPath -> Summary -> Progress -> Complete
@@ -286,6 +534,11 @@ func (ic *InstallerController) rebuildTransitions(insertionGroups map[wizard.Sta
 			continue
 		}
 
+		if afterState == "" {
+			ic.chainBeforeWelcome(handlers)
+			continue
+		}
+
 		// Get the original next state for the insertion point
 		originalNext := ic.getOriginalNextState(afterState)
 
@@ -316,6 +569,31 @@ func (ic *InstallerController) rebuildTransitions(insertionGroups map[wizard.Sta
 	}
 }
 
+// chainBeforeWelcome chains the custom states inserted before the welcome
+// screen, which start the flow, and lets the welcome screen go back to them
+func (ic *InstallerController) chainBeforeWelcome(handlers []CustomStateHandler) {
+	var prevState wizard.State
+	for i, handler := range handlers {
+		stateID := handler.GetStateID()
+		nextState := StateWelcome
+		if i < len(handlers)-1 {
+			nextState = handlers[i+1].GetStateID()
+		}
+		if prevState == "" {
+			ic.updateStateTransition(stateID, wizard.ActionNext, nextState)
+			ic.updateStateTransition(stateID, wizard.ActionCancel, StateCancelled)
+		} else {
+			ic.updateCustomStateTransitions(stateID, nextState, prevState)
+		}
+		prevState = stateID
+	}
+
+	if welcome, err := ic.dfa.GetStateConfig(StateWelcome); err == nil {
+		welcome.CanGoBack = true
+	}
+	ic.updateStateTransition(StateWelcome, wizard.ActionBack, prevState)
+}
+
 // getOriginalNextState returns what the next state should be for a given state
 func (ic *InstallerController) getOriginalNextState(state wizard.State) wizard.State {
 	switch state {
@@ -367,36 +645,254 @@ func (ic *InstallerController) addState(state wizard.State, config *wizard.State
 	if err := ic.dfa.AddState(state, config); err != nil {
 		panic(fmt.Sprintf("Failed to add state %s: %v", state, err))
 	}
+	ic.stateConfigs[state] = config
 }
 
 // Validation functions
 func (ic *InstallerController) validateLicense(data map[string]interface{}) error {
-	if accepted, ok := data["license_accepted"].(bool); !ok || !accepted {
-		return fmt.Errorf("license must be accepted to continue")
+	if accepted, _ := wizard.DataAs[bool](data, "license_accepted"); !accepted {
+		return licenseError(ic.pendingLicenses(ic.defaultSelection()))
 	}
 	return nil
 }
 
 func (ic *InstallerController) validateComponents(data map[string]interface{}) error {
-	if components, ok := data["selected_components"].([]core.Component); ok {
-		// Ensure at least one required component is selected
-		for _, comp := range components {
-			if comp.Required && comp.Selected {
-				return nil
-			}
+	components, ok := wizard.DataAs[[]core.Component](data, "selected_components")
+	if !ok {
+		return fmt.Errorf("no components selected")
+	}
+	// Ensure at least one required component is selected
+	required := false
+	for _, comp := range components {
+		if comp.Required && comp.Selected {
+			required = true
+			break
 		}
+	}
+	if !required {
 		return fmt.Errorf("at least one required component must be selected")
 	}
-	return fmt.Errorf("no components selected")
+	// The license of every selected component must have been accepted
+	if pending := ic.pendingLicenses(components); len(pending) > 0 {
+		return licenseError(pending)
+	}
+	return nil
 }
 
 func (ic *InstallerController) validateInstallPath(data map[string]interface{}) error {
-	if path, ok := data["install_path"].(string); !ok || path == "" {
+	path, _ := wizard.DataAs[string](data, "install_path")
+	if path == "" {
 		return fmt.Errorf("installation path cannot be empty")
 	}
 	return nil
 }
 
+// beforeTransition asks what going on from a state with valid values
+// raises, such as whether to install into a non-empty directory
+func (ic *InstallerController) beforeTransition(from, to wizard.State, action wizard.Action) error {
+	if action != wizard.ActionNext {
+		return nil
+	}
+	switch from {
+	case StateInstallPath:
+		return ic.confirmOverwrite(ic.session.InstallPath())
+	case StateReview:
+		if handler, ok := ic.customStates.GetHandler(StateReview); ok {
+			if review, ok := handler.(*ReviewHandler); ok {
+				return review.confirm()
+			}
+		}
+	}
+	return nil
+}
+
+// confirmOverwrite asks before installing into a directory that already holds
+// files of something other than this application. The installer's own files
+// do not count, and AllowOverwriteNonEmpty installs there without asking. A
+// directory the user agreed to is not asked about again; views that cannot
+// ask have to choose another one.
+func (ic *InstallerController) confirmOverwrite(path string) error {
+	if ic.config.AllowOverwriteNonEmpty || ic.view == nil || path == ic.overwriteConfirmed {
+		return nil
+	}
+	if foreign, err := core.HasForeignFiles(path); err != nil || !foreign {
+		return nil
+	}
+	if _, err := os.Stat(core.ManifestPath(path)); err == nil {
+		// Updating, modifying or resuming an installation
+		return nil
+	}
+	overwrite, err := ic.confirm(false, "Overwrite files",
+		fmt.Sprintf("%s already contains files, which the installation may overwrite. Install there anyway? Answer no to choose another directory.", path))
+	if err != nil {
+		return err
+	}
+	if !overwrite {
+		return fmt.Errorf("%s is not empty, choose another installation directory", path)
+	}
+	ic.overwriteConfirmed = path
+	return nil
+}
+
+// defaultSelection returns the components selected before the user changes anything
+func (ic *InstallerController) defaultSelection() []core.Component {
+	var selected []core.Component
+	for _, c := range ic.config.Components {
+		if c.Selected || c.Required {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
+// acceptLicenses marks the accepted licenses and, once all applicable
+// licenses are accepted, records the acceptance
+func (ic *InstallerController) acceptLicenses(applicable, accepted []core.ApplicableLicense) {
+	for _, l := range accepted {
+		ic.acceptedLicenses[l.Hash()] = true
+	}
+	for _, l := range applicable {
+		if !ic.acceptedLicenses[l.Hash()] {
+			return
+		}
+	}
+	ic.installer.RecordLicenseAcceptance(core.CombineLicenses(applicable), ic.isUnattended())
+}
+
+// askLicenses shows licenses and returns those the user accepted. Views
+// implementing LicenseAcceptanceView ask for each license on its own.
+func (ic *InstallerController) askLicenses(licenses []core.ApplicableLicense) ([]core.ApplicableLicense, error) {
+	if view, ok := ic.view.(LicenseAcceptanceView); ok {
+		answers, err := view.ShowLicenses(licenses)
+		if err != nil {
+			return nil, err
+		}
+		var accepted []core.ApplicableLicense
+		for i, l := range licenses {
+			if i < len(answers) && answers[i] {
+				accepted = append(accepted, l)
+			}
+		}
+		return accepted, nil
+	}
+	accepted, err := ic.view.ShowLicense(core.CombineLicenses(licenses))
+	if err != nil || !accepted {
+		return nil, err
+	}
+	return licenses, nil
+}
+
+// selectedOnly returns the components of selected that are selected or required
+func selectedOnly(selected []core.Component) []core.Component {
+	var chosen []core.Component
+	for _, c := range selected {
+		if c.Selected || c.Required {
+			chosen = append(chosen, c)
+		}
+	}
+	return chosen
+}
+
+// pendingLicenses returns the licenses applying to selected that have not
+// been accepted
+func (ic *InstallerController) pendingLicenses(selected []core.Component) []core.ApplicableLicense {
+	var pending []core.ApplicableLicense
+	for _, l := range core.ApplicableLicenses(ic.config, selectedOnly(selected)) {
+		if !ic.acceptedLicenses[l.Hash()] {
+			pending = append(pending, l)
+		}
+	}
+	return pending
+}
+
+// licenseError reports that the pending licenses must be accepted
+func licenseError(pending []core.ApplicableLicense) error {
+	if len(pending) == 0 {
+		return fmt.Errorf("license must be accepted to continue")
+	}
+	var titles []string
+	for _, l := range pending {
+		titles = append(titles, l.Title)
+	}
+	return fmt.Errorf("license must be accepted to continue: %s", strings.Join(titles, ", "))
+}
+
+// requireComponentLicenses asks for acceptance of licenses added by the
+// component selection, each on its own if the view supports it, and drops
+// licenses of deselected components from the accepted set.
+func (ic *InstallerController) requireComponentLicenses(selected []core.Component) error {
+	licenses := core.ApplicableLicenses(ic.config, selectedOnly(selected))
+	applicable := make(map[string]bool, len(licenses))
+	for _, l := range licenses {
+		applicable[l.Hash()] = true
+	}
+	for hash := range ic.acceptedLicenses {
+		if !applicable[hash] {
+			delete(ic.acceptedLicenses, hash)
+		}
+	}
+
+	pending := ic.pendingLicenses(selected)
+	if len(pending) == 0 {
+		return nil
+	}
+	accepted, err := ic.askLicenses(pending)
+	if err != nil {
+		return err
+	}
+	ic.acceptLicenses(licenses, accepted)
+	if pending := ic.pendingLicenses(selected); len(pending) > 0 {
+		return licenseError(pending)
+	}
+	return nil
+}
+
+// withSelection returns a copy of components with those in selected marked
+// as selected and the others not
+func withSelection(components, selected []core.Component) []core.Component {
+	result := make([]core.Component, len(components))
+	for i, c := range components {
+		c.Selected = false
+		for _, s := range selected {
+			if s.ID == c.ID {
+				c.Selected = true
+				break
+			}
+		}
+		result[i] = c
+	}
+	return result
+}
+
+// AcceptLicenses accepts the global license and the licenses of the
+// components with ids, for views whose license page is answered after
+// ShowLicenses returned, such as the GUI. The licenses of other components
+// stay pending.
+func (ic *InstallerController) AcceptLicenses(ids []string) error {
+	licenses := core.ApplicableLicenses(ic.config, ic.GetSelectedComponents())
+	var accepted []core.ApplicableLicense
+	for _, l := range licenses {
+		if l.ComponentID == "" || slices.Contains(ids, l.ComponentID) {
+			accepted = append(accepted, l)
+		}
+	}
+	ic.acceptLicenses(licenses, accepted)
+	if ic.GetCurrentState() != StateLicense {
+		return nil
+	}
+	return ic.dfa.SetData("license_accepted", len(ic.pendingLicenses(ic.defaultSelection())) == 0)
+}
+
+// IsLicenseAccepted reports whether the given license text has been accepted
+func (ic *InstallerController) IsLicenseAccepted(license string) bool {
+	return ic.acceptedLicenses[core.HashLicense(license)]
+}
+
+// isUnattended reports whether user decisions come from configuration rather than the user
+func (ic *InstallerController) isUnattended() bool {
+	return ic.config.Unattended || ic.config.Mode == core.ModeSilent
+}
+
 // State enter handlers
 func (ic *InstallerController) handleStateEnter(state wizard.State, data map[string]interface{}) error {
 	if ic.view == nil {
@@ -408,42 +904,59 @@ func (ic *InstallerController) handleStateEnter(state wizard.State, data map[str
 		return ic.view.ShowWelcome()
 		
 	case StateLicense:
-		accepted, err := ic.view.ShowLicense(ic.config.License)
+		licenses := core.ApplicableLicenses(ic.config, ic.defaultSelection())
+		accepted, err := ic.askLicenses(licenses)
 		if err != nil {
 			return err
 		}
-		data["license_accepted"] = accepted
+		ic.acceptLicenses(licenses, accepted)
+		data["license_accepted"] = len(accepted) == len(licenses)
 		return nil
 		
 	case StateComponents:
-		selected, err := ic.view.ShowComponents(ic.config.Components)
+		// Returning to the step shows the selection made before
+		components := ic.config.Components
+		if previous, ok := wizard.DataAs[[]core.Component](data, "selected_components"); ok {
+			components = withSelection(components, previous)
+		}
+		// Portable installations leave out components with services
+		components = core.PortableComponents(components, ic.config.Portable)
+		selected, err := ic.view.ShowComponents(components)
 		if err != nil {
 			return err
 		}
 		data["selected_components"] = selected
-		// Update installer with selected components
-		ic.installer.SetSelectedComponents(selected)
-		return nil
+		// Update the session and the installer with selected components
+		ic.session.SetSelectedComponents(selected)
+		return ic.requireComponentLicenses(selected)
 		
 	case StateInstallPath:
+		// The configured directory follows a changed install scope, see SetInstallScope
 		defaultPath := ic.config.InstallDir
+		if path, _ := wizard.DataAs[string](data, "install_path"); path != "" && defaultPath == "" {
+			defaultPath = path
+		}
 		if defaultPath == "" {
-			// Determine default path based on platform
-			defaultPath = "/opt/" + ic.config.AppName // Simplified
+			defaultPath = ic.config.DefaultInstallDir()
 		}
 		
 		path, err := ic.view.ShowInstallPath(defaultPath)
 		if err != nil {
 			return err
 		}
+		if path, err = core.ExpandPath(path); err != nil {
+			return err
+		}
 		data["install_path"] = path
-		// Update installer with selected path
-		ic.installer.SetInstallPath(path)
+		// Update the session and the installer with selected path
+		ic.session.SetInstallPath(path)
 		return nil
 		
 	case StateSummary:
-		components, _ := data["selected_components"].([]core.Component)
-		installPath, _ := data["install_path"].(string)
+		components, _ := wizard.DataAs[[]core.Component](data, "selected_components")
+		components = core.PortableComponents(components, ic.config.Portable)
+		installPath, _ := wizard.DataAs[string](data, "install_path")
+		data["create_restore_point"] = ic.config.CreateRestorePoint && core.RestorePointSupported()
 		
 		proceed, err := ic.view.ShowSummary(ic.config, components, installPath)
 		if err != nil {
@@ -455,10 +968,28 @@ func (ic *InstallerController) handleStateEnter(state wizard.State, data map[str
 		return nil
 		
 	case StateProgress:
+		// A dry run records the plan, the installer then skips the changes
+		if ic.config.DryRun {
+			ic.plannedOperations = ic.installer.PlannedOperations()
+		}
+		if ic.planOnly {
+			// Progress disallows Next for the user, so take the transition directly
+			go ic.dfa.Transition(wizard.ActionNext)
+			return nil
+		}
+
 		// Start installation in background
+		done := make(chan struct{})
+		ic.installDone = done
 		go func() {
+			defer close(done)
 			ic.installer.SetUI(&controllerUIAdapter{controller: ic})
-			err := ic.installer.ExecuteInstallation()
+			err := ic.installer.Execute()
+			if errors.Is(err, core.ErrInstallCancelled) {
+				// Rolled back, record the cancelled installation
+				ic.dfa.Transition(wizard.ActionCancel)
+				return
+			}
 			if err != nil {
 				ic.view.ShowErrorMessage(err)
 				return
@@ -472,9 +1003,17 @@ func (ic *InstallerController) handleStateEnter(state wizard.State, data map[str
 		summary := ic.installer.CreateSummary()
 		return ic.view.ShowComplete(summary)
 
+	case StateCancelled:
+		// Entered once a cancelled installation has rolled back, see Cancel
+		return nil
+
 	default:
 		// Check if this is a custom state
 		if handler, exists := ic.customStates.GetHandler(state); exists {
+			if sessionHandler, ok := handler.(SessionStateHandler); ok {
+				return sessionHandler.HandleEnterSession(ic, ic.session)
+			}
+
 			// Merge global state data with current data
 			mergedData := make(map[string]interface{})
 			for k, v := range ic.stateData {
@@ -505,6 +1044,17 @@ func (ic *InstallerController) handleStateEnter(state wizard.State, data map[str
 func (ic *InstallerController) handleStateLeave(state wizard.State, data map[string]interface{}) error {
 	// Handle custom states
 	if handler, exists := ic.customStates.GetHandler(state); exists {
+		// The review writes the values it confirmed to the flow data itself
+		if review, ok := handler.(*ReviewHandler); ok {
+			if err := review.apply(data); err != nil {
+				return err
+			}
+		}
+
+		if sessionHandler, ok := handler.(SessionStateHandler); ok {
+			return sessionHandler.HandleLeaveSession(ic, ic.session)
+		}
+
 		// Merge global state data with current data
 		mergedData := make(map[string]interface{})
 		for k, v := range ic.stateData {
@@ -535,26 +1085,457 @@ func (ic *InstallerController) Start() error {
 }
 
 func (ic *InstallerController) Next() error {
+	if turned, err := ic.turnPage(1); turned {
+		return err
+	}
 	return ic.dfa.Next()
 }
 
 func (ic *InstallerController) Back() error {
+	if turned, err := ic.turnPage(-1); turned {
+		return err
+	}
 	return ic.dfa.Back()
 }
 
+// turnPage moves by offset between the pages of a MultiPageConfigState and
+// shows the new page. It reports false outside such a state and at its first
+// and last page, where Back and Next leave the state.
+func (ic *InstallerController) turnPage(offset int) (bool, error) {
+	state := ic.dfa.CurrentState()
+	handler, _ := ic.customStates.GetHandler(state)
+	pages, ok := handler.(*MultiPageConfigState)
+	if !ok {
+		return false, nil
+	}
+	if offset < 0 && ic.dfa.IsForwardOnly() {
+		return false, nil
+	}
+	turned, err := pages.turnPage(offset)
+	if !turned {
+		return false, nil
+	}
+	if err != nil {
+		return true, ic.showFieldErrors(err)
+	}
+	return true, ic.handleStateEnter(state, ic.dfa.GetAllData())
+}
+
+// Jump returns from the summary, or any later state, to a step visited
+// before, keeping what was entered. Next then leads back to where the jump
+// started, see wizard.DFA.Jump.
+func (ic *InstallerController) Jump(state wizard.State) error {
+	return ic.dfa.Jump(state)
+}
+
+// SaveDiagnostics writes a diagnostics archive for reporting a problem to a
+// new file in the temporary directory and returns its path. Like HelpFor it
+// can be called from the view methods.
+func (ic *InstallerController) SaveDiagnostics() (string, error) {
+	path := core.DiagnosticsPath(ic.config)
+	if err := ic.installer.ExportDiagnostics(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// OpenLog opens the installer log with the default application and returns
+// a message for the user, offered as "View log" on the completion and error
+// screens. Like HelpFor it can be called from the view methods.
+func (ic *InstallerController) OpenLog() (string, error) {
+	return ic.installer.OpenLog()
+}
+
+// ReadLog returns the installer log with secrets redacted, for views that
+// show it themselves, see core.RedactLog
+func (ic *InstallerController) ReadLog() ([]byte, error) {
+	return ic.installer.ReadLog()
+}
+
+// RunCompletionAction performs the next step with id offered on the
+// completion screen and returns a message for the user. Like HelpFor it can
+// be called from the view methods.
+func (ic *InstallerController) RunCompletionAction(id string) (string, error) {
+	return ic.installer.RunCompletionAction(id)
+}
+
+// logPanic writes a panic in a step, with its stack, to the installer log.
+// The DFA has undone the transition, so the installer can continue.
+func (ic *InstallerController) logPanic(err *wizard.PanicError) {
+	logger := ic.session.Logger
+	if ic.installer != nil && ic.installer.GetLogger() != nil {
+		logger = ic.installer.GetLogger()
+	}
+	if logger == nil {
+		return
+	}
+	logger.Error("Recovered from a panic in an installer step", "state", err.State, "panic", err.Value, "stack", string(err.Stack))
+}
+
+// stateHistory returns the names of the states entered so far, including
+// those left again with Back
+func (ic *InstallerController) stateHistory() []string {
+	ic.historyMu.Lock()
+	defer ic.historyMu.Unlock()
+	names := make([]string, len(ic.history))
+	for i, state := range ic.history {
+		names[i] = string(state)
+	}
+	return names
+}
+
+// RequirementWarnings returns the recommended system requirements this
+// computer falls short of, for the welcome screen. Like HelpFor it can be
+// called from the view methods.
+func (ic *InstallerController) RequirementWarnings() []string {
+	ic.requirementsOnce.Do(func() {
+		ic.requirementWarnings = core.RequirementWarnings(ic.config)
+	})
+	return ic.requirementWarnings
+}
+
+// ModifyPlan returns the components the summary confirms installing and
+// removing when an existing installation is modified, nil for a new
+// installation. Like HelpFor it can be called from the view methods.
+func (ic *InstallerController) ModifyPlan() (*core.ModifyPlan, error) {
+	if !ic.installer.IsModify() {
+		return nil, nil
+	}
+	return ic.installer.PlanModification()
+}
+
+// Localizer returns the localizer of the installer screens, switched by
+// SetLocale. Like HelpFor it can be called from the view methods.
+func (ic *InstallerController) Localizer() *core.Localizer {
+	return ic.localizer
+}
+
+// SetLocale switches the installer screens to locale, e.g. when the user
+// picks it on the language screen. Screens shown afterwards are translated;
+// the language state records the locale when it is left. Like HelpFor it
+// can be called from the view methods.
+func (ic *InstallerController) SetLocale(locale string) error {
+	return ic.localizer.SetLocale(locale)
+}
+
+// SetPathScope chooses the PATH the installation changes by scope name,
+// e.g. when the user picks it on the PATH screen. Like HelpFor it can be
+// called from the view methods.
+func (ic *InstallerController) SetPathScope(scope string) error {
+	if pc := ic.config.PathConfig; pc != nil && pc.Enabled {
+		return pc.SetScope(scope)
+	}
+	return fmt.Errorf("the installation does not change PATH")
+}
+
+// SetInstallScope installs for all users or just the current one by scope
+// name, "per-user" or "per-machine", e.g. when the user picks it on the
+// install scope screen. The installation path and PATH follow unless they
+// were changed, see core.Config.SetInstallScope; choosing the scope in
+// effect changes nothing. Like HelpFor it can be called from the view
+// methods.
+func (ic *InstallerController) SetInstallScope(scope string) error {
+	parsed, err := core.ParseInstallScope(scope)
+	if err != nil {
+		return err
+	}
+	if parsed == core.ScopeAuto {
+		return fmt.Errorf("choose per-user or per-machine")
+	}
+	if parsed != ic.config.InstallScope {
+		ic.config.SetInstallScope(parsed)
+	}
+	return nil
+}
+
+// FormFor returns the declared fields of a form state, nil for other states
+func (ic *InstallerController) FormFor(state wizard.State) *core.UIStateConfig {
+	handler, _ := ic.customStates.GetHandler(state)
+	if form, ok := handler.(FormStateHandler); ok {
+		return form.UIConfig()
+	}
+	return nil
+}
+
+// FormValues returns a copy of the values of a form state, the defaults of
+// its fields until values are set. It is nil for other states.
+func (ic *InstallerController) FormValues(state wizard.State) FormValues {
+	handler, ok := ic.customStates.GetHandler(state)
+	form, isForm := handler.(FormStateHandler)
+	if !ok || !isForm {
+		return nil
+	}
+	if pages, ok := handler.(*MultiPageConfigState); ok {
+		return pages.formValues()
+	}
+	ic.formMu.Lock()
+	defer ic.formMu.Unlock()
+	current, ok := ic.formValues[state]
+	if !ok {
+		return FormDefaults(form.UIConfig())
+	}
+	values := make(FormValues, len(current))
+	for id, value := range current {
+		values[id] = value
+	}
+	return values
+}
+
+// SetFormValues converts values, a map of field IDs to typed or string
+// values, to the field types of a form state and stores them. Fields not in
+// values keep their value. Values that cannot be converted are returned as
+// ValidationResult and nothing is stored.
+func (ic *InstallerController) SetFormValues(state wizard.State, values interface{}) error {
+	handler, _ := ic.customStates.GetHandler(state)
+	form, ok := handler.(FormStateHandler)
+	if !ok {
+		return fmt.Errorf("%s is not a form state", state)
+	}
+	parsed, result := ParseFormValues(form.UIConfig(), ic.FormValues(state), values)
+	if len(result) > 0 {
+		return result
+	}
+	if pages, ok := form.(*MultiPageConfigState); ok {
+		pages.setFormValues(parsed)
+		return nil
+	}
+	ic.formMu.Lock()
+	defer ic.formMu.Unlock()
+	if ic.formValues == nil {
+		ic.formValues = make(map[wizard.State]FormValues)
+	}
+	ic.formValues[state] = parsed
+	return nil
+}
+
+// ResponseSettings returns the choices made so far as settings. The
+// controller does not write them; applications that want to replay the
+// choices in an unattended installation save them with
+// core.WriteResponseFile.
+func (ic *InstallerController) ResponseSettings() core.Settings {
+	data := ic.dfa.GetAllData()
+	settings := core.Settings{core.SettingUnattended: "true"}
+	if path, _ := wizard.DataAs[string](data, "install_path"); path != "" {
+		settings[core.SettingInstallDir] = path
+	}
+	if accepted, ok := wizard.DataAs[bool](data, "license_accepted"); ok {
+		settings[core.SettingAcceptLicense] = strconv.FormatBool(accepted)
+	}
+	if locale, _ := wizard.DataAs[string](ic.stateData, "locale"); locale != "" {
+		settings[core.SettingLocale] = locale
+	}
+	if scope, _ := wizard.DataAs[string](ic.stateData, "install_scope"); scope != "" {
+		settings[core.SettingInstallScope] = scope
+	}
+	if scope, _ := wizard.DataAs[string](ic.stateData, "path_scope"); scope != "" {
+		settings[core.SettingPathScope] = scope
+	}
+	if ic.config.Profile != "" {
+		settings[core.SettingProfile] = ic.config.Profile
+	}
+	return settings
+}
+
+// ChangeTarget is a step the summary offers to change
+type ChangeTarget struct {
+	State wizard.State
+	Name  string
+}
+
+// ChangeTargets returns the steps before the summary whose settings can be
+// changed with Jump, in flow order, given the selected components. Like
+// HelpFor it can be called from the view methods.
+func (ic *InstallerController) ChangeTargets(selected []core.Component) []ChangeTarget {
+	data := map[string]interface{}{"selected_components": selected}
+	var targets []ChangeTarget
+	seen := make(map[wizard.State]bool)
+	for state := StateWelcome; state != StateSummary && !seen[state]; {
+		seen[state] = true
+		config, ok := ic.stateConfigs[state]
+		if !ok {
+			break
+		}
+		_, custom := ic.customStates.GetHandler(state)
+		enterable := !config.Optional || config.CanEnterFunc == nil || config.CanEnterFunc(data)
+		if (custom || state == StateComponents || state == StateInstallPath) && enterable {
+			targets = append(targets, ChangeTarget{State: state, Name: config.Name})
+		}
+		state = config.Transitions[wizard.ActionNext]
+	}
+	return targets
+}
+
+// Cancel asks the user to confirm, then cancels the installation. It returns
+// ErrCancelDeclined if the user chose to continue. Views that cannot ask
+// cancel right away.
+//
+// Before the progress state nothing has been installed and the flow simply
+// ends; a custom state still validating, such as an activation waiting for
+// its server, gives up once the user confirms. During it, with Config.AllowInstallCancel, the running installation
+// is stopped and rolled back or kept for a resume as Config.CancelPolicy
+// says; with CancelPrompt a second question lets the user choose. Cancel
+// returns once that is done.
 func (ic *InstallerController) Cancel() error {
+	// A custom state validating, e.g. waiting for the activation server,
+	// holds the flow until it returns; once confirmed it gives up
+	if ic.validating.Load() {
+		cancel, err := ic.confirm(true, "Cancel installation", ic.cancelMessage(false))
+		if err != nil {
+			return err
+		}
+		if !cancel {
+			return ErrCancelDeclined
+		}
+		ic.stop()
+		return ic.dfa.Cancel()
+	}
+
+	installing := ic.dfa.CurrentState() == StateProgress
+	keep := ic.config.CancelPolicy == core.CancelKeepForResume
+	if ic.view != nil && ic.dfa.CanTransition(wizard.ActionCancel) {
+		cancel, err := ic.confirm(true, "Cancel installation", ic.cancelMessage(installing))
+		if err != nil {
+			return err
+		}
+		if !cancel {
+			return ErrCancelDeclined
+		}
+		if installing && ic.promptsKeep() {
+			keep, err = ic.confirm(keep, "Keep installed components", fmt.Sprintf(
+				"Keep the parts of %s installed so far, so running the installer again can resume the installation? "+
+					"Choose No to roll them back.", ic.config.AppName))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if installing && ic.dfa.CanTransition(wizard.ActionCancel) && ic.installDone != nil {
+		return ic.cancelInstallation(keep)
+	}
+	// A state waiting for a server, such as the activation, gives up
+	ic.stop()
 	return ic.dfa.Cancel()
 }
 
+// confirm asks the view a yes/no question and returns fallback if the view
+// does not implement ConfirmView
+func (ic *InstallerController) confirm(fallback bool, title, message string) (bool, error) {
+	if view, ok := ic.view.(ConfirmView); ok {
+		return view.Confirm(title, message)
+	}
+	return fallback, nil
+}
+
+// promptsKeep reports whether cancelling the installation asks whether to
+// keep what has been installed; there is no choice without a rollback
+func (ic *InstallerController) promptsKeep() bool {
+	return ic.config.CancelPolicy == core.CancelPrompt && ic.config.Rollback != core.RollbackNone && !ic.installer.IsModify()
+}
+
+// cancelMessage asks whether to cancel, warning about what is left behind
+// once the installation is running
+func (ic *InstallerController) cancelMessage(installing bool) string {
+	if !installing {
+		return fmt.Sprintf("Are you sure you want to cancel the installation of %s? Nothing has been installed yet.", ic.config.AppName)
+	}
+	if ic.config.Rollback == core.RollbackNone || ic.installer.IsModify() {
+		return fmt.Sprintf("%s is being installed. Cancelling stops the installation and leaves what has been installed so far in place, "+
+			"so the installation will be incomplete. Do you want to cancel?", ic.config.AppName)
+	}
+	switch ic.config.CancelPolicy {
+	case core.CancelKeepForResume:
+		return fmt.Sprintf("%s is being installed. Cancelling stops the installation and keeps what has been installed so far, "+
+			"so running the installer again can resume it. Do you want to cancel?", ic.config.AppName)
+	case core.CancelPrompt:
+		return fmt.Sprintf("%s is being installed. Cancelling stops the installation; you can then keep what has been installed "+
+			"so far or roll it back. Do you want to cancel?", ic.config.AppName)
+	}
+	return fmt.Sprintf("%s is being installed. Cancelling stops the installation and rolls back the changes made so far. "+
+		"Do you want to cancel?", ic.config.AppName)
+}
+
+// cancelInstallation stops the running installation, keeping what has been
+// installed if keep is set, and waits for it to roll back and enter
+// StateCancelled
+func (ic *InstallerController) cancelInstallation(keep bool) error {
+	ic.installer.CancelInstallationKeeping(keep)
+	<-ic.installDone
+	state := ic.dfa.CurrentState()
+	if state == StateProgress {
+		// The installation had failed already, there is nothing to roll back
+		return ic.dfa.Cancel()
+	}
+	if state != StateCancelled {
+		return fmt.Errorf("the installation ended before it could be cancelled, state %s", state)
+	}
+	return nil
+}
+
+// Help shows the help of the current state through a HelpView. The state
+// does not change; states without help text return an error.
+func (ic *InstallerController) Help() error {
+	state := ic.dfa.CurrentState()
+	if err := ic.dfa.Transition(wizard.ActionHelp); err != nil {
+		return err
+	}
+	view, ok := ic.view.(HelpView)
+	if !ok {
+		return nil
+	}
+	title := string(state)
+	if config, err := ic.dfa.GetStateConfig(state); err == nil && config.Name != "" {
+		title = config.Name
+	}
+	return view.ShowHelp(title, ic.HelpFor(state))
+}
+
+// CurrentHelp returns the help text of the current state, empty if it has none
+func (ic *InstallerController) CurrentHelp() string {
+	return ic.HelpFor(ic.dfa.CurrentState())
+}
+
+// HelpFor returns the help text of state. Unlike CurrentHelp it can be called
+// from the view methods, which run while the DFA changes state.
+func (ic *InstallerController) HelpFor(state wizard.State) string {
+	if config, ok := ic.stateConfigs[state]; ok {
+		return config.HelpText
+	}
+	return ""
+}
+
 func (ic *InstallerController) GetCurrentState() wizard.State {
 	return ic.dfa.CurrentState()
 }
 
+// PlannedOperations returns what a dry run would have installed, once it
+// passed the progress state
+func (ic *InstallerController) PlannedOperations() []string {
+	return ic.plannedOperations
+}
+
+// PrimaryLabel returns the label of the primary button in the current state
+func (ic *InstallerController) PrimaryLabel() string {
+	return ic.PrimaryLabelFor(ic.dfa.CurrentState())
+}
+
+// PrimaryLabelFor returns the label of the primary button in state. Like
+// HelpFor it can be called from the view methods.
+func (ic *InstallerController) PrimaryLabelFor(state wizard.State) string {
+	if config, ok := ic.stateConfigs[state]; ok && config.PrimaryLabel != "" {
+		return config.PrimaryLabel
+	}
+	return ic.localizer.T(core.MsgButtonNext)
+}
+
 func (ic *InstallerController) CanGoNext() bool {
 	return ic.dfa.CanTransition(wizard.ActionNext)
 }
 
 func (ic *InstallerController) CanGoBack() bool {
+	handler, _ := ic.customStates.GetHandler(ic.dfa.CurrentState())
+	if pages, ok := handler.(*MultiPageConfigState); ok && pages.page() > 0 && !ic.dfa.IsForwardOnly() {
+		return true
+	}
 	return ic.dfa.CanTransition(wizard.ActionBack)
 }
 
@@ -0,0 +1,679 @@
+package controller
+
+import (
+	"context"
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+func TestPrimaryLabelPerState(t *testing.T) {
+	config := &core.Config{
+		AppName:    "LabelApp",
+		Version:    "1.0.0",
+		License:    "License text",
+		InstallDir: filepath.Join(t.TempDir(), "install"),
+		Components: []core.Component{{ID: "core", Name: "Core", Required: true, Selected: true}},
+	}
+	controller := NewInstallerController(config, core.New(config))
+	controller.SetView(NewMockExtendedInstallerView())
+
+	require.NoError(t, controller.Start())
+	flow := []struct {
+		state wizard.State
+		label string
+	}{
+		{StateWelcome, "Next"},
+		{StateLicense, "Next"},
+		{StateComponents, "Next"},
+		{StateInstallPath, "Next"},
+		{StateSummary, "Install"},
+	}
+	for i, step := range flow {
+		if i > 0 {
+			require.NoError(t, controller.Next())
+		}
+		require.Equal(t, step.state, controller.GetCurrentState())
+		assert.Equal(t, step.label, controller.PrimaryLabel(), "state %s", step.state)
+	}
+	assert.Equal(t, "Finish", controller.PrimaryLabelFor(StateComplete))
+}
+
+func TestPrimaryLabelCustomState(t *testing.T) {
+	config := &core.Config{AppName: "LabelApp", InstallDir: filepath.Join(t.TempDir(), "install")}
+	controller := NewInstallerController(config, core.New(config))
+	require.NoError(t, controller.RegisterCustomState(&BaseCustomStateHandler{
+		StateID:      "activate",
+		PrimaryLabel: "Activate",
+		InsertPoint:  InsertAfterWelcome,
+		CanGoNext:    true,
+	}))
+	require.NoError(t, controller.RegisterCustomState(&BaseCustomStateHandler{
+		StateID:     "options",
+		InsertPoint: InsertAfterWelcome,
+		CanGoNext:   true,
+	}))
+	controller.SetView(NewMockExtendedInstallerView())
+	require.NoError(t, controller.Start())
+
+	assert.Equal(t, "Activate", controller.PrimaryLabelFor("activate"))
+	assert.Equal(t, wizard.DefaultPrimaryLabel, controller.PrimaryLabelFor("options"))
+}
+
+func TestCancelAsksForConfirmation(t *testing.T) {
+	config := &core.Config{AppName: "ConfirmApp", InstallDir: filepath.Join(t.TempDir(), "install")}
+	controller := NewInstallerController(config, core.New(config))
+	view := NewMockExtendedInstallerView()
+	view.declineConfirm = true
+	controller.SetView(view)
+	require.NoError(t, controller.Start())
+
+	assert.ErrorIs(t, controller.Cancel(), ErrCancelDeclined)
+	assert.Equal(t, StateWelcome, controller.GetCurrentState())
+	assert.Contains(t, view.recordedCalls, "Confirm")
+
+	view.declineConfirm = false
+	assert.NoError(t, controller.Cancel())
+}
+
+// basicView hides the optional methods of the view it wraps
+type basicView struct {
+	InstallerView
+}
+
+func TestViewWithoutConfirm(t *testing.T) {
+	occupied := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(occupied, "other.txt"), []byte("x"), 0644))
+	config := &core.Config{AppName: "ConfirmApp", InstallDir: filepath.Join(t.TempDir(), "install")}
+	controller := NewInstallerController(config, core.New(config))
+	controller.SetView(basicView{NewMockExtendedInstallerView()})
+	require.NoError(t, controller.Start())
+
+	assert.ErrorContains(t, controller.confirmOverwrite(occupied), "choose another installation directory",
+		"a view that cannot ask must not overwrite")
+	assert.NoError(t, controller.Cancel(), "a view that cannot ask cancels right away")
+}
+
+func TestInstallPathOverwriteConfirmation(t *testing.T) {
+	empty := t.TempDir()
+	ownFiles := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(ownFiles, core.InstallerFilePrefix+"manifest.json.bak"), []byte("{}"), 0644))
+	occupied := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(occupied, "other.txt"), []byte("x"), 0644))
+	next := wizard.ActionNext
+
+	tests := []struct {
+		name     string
+		path     string
+		allow    bool
+		answer   bool
+		confirms []string
+		proceeds bool
+	}{
+		{name: "empty", path: empty, proceeds: true},
+		{name: "missing", path: filepath.Join(empty, "new"), proceeds: true},
+		{name: "installer files only", path: ownFiles, proceeds: true},
+		{name: "non-empty confirmed", path: occupied, answer: true, confirms: []string{"Overwrite files"}, proceeds: true},
+		{name: "non-empty declined", path: occupied, answer: false, confirms: []string{"Overwrite files"}},
+		{name: "non-empty allowed", path: occupied, allow: true, proceeds: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &core.Config{
+				AppName:                "ConfirmApp",
+				License:                "License text",
+				Components:             []core.Component{{ID: "core", Name: "Core", Required: true, Selected: true}},
+				AllowOverwriteNonEmpty: tt.allow,
+			}
+			driver := NewTestDriver(NewInstallerController(config, core.New(config)))
+			driver.Input(StateInstallPath, InputInstallPath, tt.path).Answer("Overwrite files", tt.answer)
+
+			err := driver.Run(next, next, next, next)
+			if tt.confirms == nil {
+				assert.Empty(t, driver.Confirmations())
+			} else {
+				assert.Equal(t, tt.confirms, driver.Confirmations())
+			}
+			if tt.proceeds {
+				assert.NoError(t, err)
+				assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense, StateComponents, StateInstallPath, StateSummary))
+			} else {
+				assert.ErrorContains(t, err, "choose another installation directory")
+				assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense, StateComponents, StateInstallPath))
+			}
+		})
+	}
+}
+
+func TestInstallPathOverwriteAskedOnce(t *testing.T) {
+	occupied := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(occupied, "other.txt"), []byte("x"), 0644))
+	config := &core.Config{
+		AppName:    "ConfirmApp",
+		License:    "License text",
+		Components: []core.Component{{ID: "core", Name: "Core", Required: true, Selected: true}},
+	}
+	controller := NewInstallerController(config, core.New(config))
+	driver := NewTestDriver(controller)
+	driver.Input(StateInstallPath, InputInstallPath, occupied).Answer("Overwrite files", true)
+
+	next, back := wizard.ActionNext, wizard.ActionBack
+	require.NoError(t, driver.Run(next, next, next))
+	require.NoError(t, controller.dfa.ValidateCurrentState())
+	assert.Empty(t, driver.Confirmations(), "validating the path must not ask")
+
+	require.NoError(t, driver.Continue(next, back, next))
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath, StateSummary, StateInstallPath, StateSummary))
+	assert.Equal(t, []string{"Overwrite files"}, driver.Confirmations(), "the agreed directory is asked about once")
+}
+
+func TestHelpKeepsState(t *testing.T) {
+	config := &core.Config{
+		AppName:    "HelpApp",
+		License:    "License text",
+		Components: []core.Component{{ID: "core", Name: "Core", Required: true, Selected: true}},
+	}
+	controller := NewInstallerController(config, core.New(config))
+	require.NoError(t, controller.RegisterCustomState(&BaseCustomStateHandler{
+		StateID:     "database",
+		Name:        "Database",
+		HelpText:    "The port of the database server, 5432 for a default PostgreSQL installation.",
+		InsertPoint: InsertAfterWelcome,
+		CanGoNext:   true,
+	}))
+	driver := NewTestDriver(controller)
+
+	require.NoError(t, driver.Run(wizard.ActionNext, wizard.ActionHelp, wizard.ActionHelp))
+	assert.NoError(t, driver.AssertStates(StateWelcome, "database"))
+	assert.Equal(t, wizard.State("database"), controller.GetCurrentState())
+	assert.Equal(t, controller.HelpFor("database"), controller.CurrentHelp())
+	assert.Equal(t, []string{controller.CurrentHelp(), controller.CurrentHelp()}, driver.Helps())
+	assert.Contains(t, controller.CurrentHelp(), "5432")
+
+	assert.NotEmpty(t, controller.HelpFor(StateInstallPath), "standard states explain themselves")
+	assert.Empty(t, controller.HelpFor(StateWelcome))
+}
+
+func TestHelpWithoutHelpView(t *testing.T) {
+	config := &core.Config{AppName: "HelpApp", InstallDir: filepath.Join(t.TempDir(), "install")}
+	controller := NewInstallerController(config, core.New(config))
+	require.NoError(t, controller.RegisterCustomState(&BaseCustomStateHandler{
+		StateID:     "database",
+		HelpText:    "The port of the database server.",
+		InsertPoint: InsertAfterWelcome,
+		CanGoNext:   true,
+	}))
+	controller.SetView(basicView{NewMockExtendedInstallerView()})
+	require.NoError(t, controller.Start())
+	require.NoError(t, controller.Next())
+
+	assert.NoError(t, controller.Help())
+	assert.Equal(t, wizard.State("database"), controller.GetCurrentState())
+}
+
+func TestHelpWithoutHelpText(t *testing.T) {
+	config := &core.Config{AppName: "HelpApp"}
+	driver := NewTestDriver(NewInstallerController(config, core.New(config)))
+
+	assert.Error(t, driver.Run(wizard.ActionHelp))
+	assert.Empty(t, driver.Helps())
+	assert.NoError(t, driver.AssertStates(StateWelcome))
+}
+
+func TestComponentStateFollowsSelection(t *testing.T) {
+	next := wizard.ActionNext
+	newController := func() *InstallerController {
+		config := &core.Config{
+			AppName:    "BranchApp",
+			License:    "License text",
+			InstallDir: filepath.Join(t.TempDir(), "install"),
+			Components: []core.Component{
+				{ID: "client", Name: "Client", Required: true, Selected: true},
+				{ID: "server", Name: "Server"},
+			},
+		}
+		controller := NewInstallerController(config, core.New(config))
+		require.NoError(t, controller.RegisterComponentState("server", &BaseCustomStateHandler{
+			StateID:     "server_config",
+			Name:        "Server Configuration",
+			InsertPoint: InsertAfterComponents,
+			CanGoNext:   true,
+			CanGoBack:   true,
+		}))
+		return controller
+	}
+
+	t.Run("selected", func(t *testing.T) {
+		driver := NewTestDriver(newController())
+		driver.Input(StateComponents, InputComponents, []string{"server"})
+
+		require.NoError(t, driver.Run(next, next, next, next))
+		assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense, StateComponents, "server_config", StateInstallPath))
+	})
+
+	t.Run("deselected", func(t *testing.T) {
+		driver := NewTestDriver(newController())
+		driver.Input(StateComponents, InputComponents, []string{})
+
+		require.NoError(t, driver.Run(next, next, next, wizard.ActionBack))
+		assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense, StateComponents, StateInstallPath, StateComponents))
+	})
+}
+
+func TestRegisterComponentStateErrors(t *testing.T) {
+	config := &core.Config{
+		AppName:    "BranchApp",
+		Components: []core.Component{{ID: "server", Name: "Server"}},
+	}
+	controller := NewInstallerController(config, core.New(config))
+
+	err := controller.RegisterComponentState("missing", &BaseCustomStateHandler{StateID: "missing_config", InsertPoint: InsertAfterComponents})
+	assert.ErrorContains(t, err, "unknown component missing")
+
+	err = controller.RegisterComponentState("server", &BaseCustomStateHandler{StateID: "server_config", InsertPoint: InsertAfterWelcome})
+	assert.ErrorContains(t, err, "after the components state")
+	assert.Empty(t, controller.GetCustomStates())
+}
+
+// panickingStateHandler is a custom state whose HandleEnter has a bug
+type panickingStateHandler struct {
+	BaseCustomStateHandler
+}
+
+func (h *panickingStateHandler) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	var settings map[string]string
+	settings["mode"] = "broken"
+	return nil
+}
+
+func TestPanicInCustomStateIsRecovered(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "install.log")
+	config := &core.Config{AppName: "PanicApp", InstallDir: filepath.Join(t.TempDir(), "install"), LogFile: logFile}
+	installer := core.New(config)
+	logger := core.NewLogger("info", logFile)
+	t.Cleanup(func() { logger.Close() })
+	installer.SetContext(&core.Context{Config: config, Logger: logger, Metadata: map[string]interface{}{}})
+	controller := NewInstallerController(config, installer)
+	require.NoError(t, controller.RegisterCustomState(&panickingStateHandler{BaseCustomStateHandler{
+		StateID:     "broken",
+		InsertPoint: InsertAfterWelcome,
+		CanGoNext:   true,
+	}}))
+	controller.SetView(NewMockExtendedInstallerView())
+	require.NoError(t, controller.Start())
+
+	err := controller.Next()
+	var panicErr *wizard.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, wizard.State("broken"), panicErr.State)
+	assert.Equal(t, StateWelcome, controller.GetCurrentState())
+
+	// Written through the installer's logger, which keeps the file open
+	logger.Info("Installation continues")
+	log, readErr := os.ReadFile(logFile)
+	require.NoError(t, readErr)
+	assert.Contains(t, string(log), "Recovered from a panic in an installer step")
+	assert.Contains(t, string(log), "state=broken")
+	assert.Contains(t, string(log), "goroutine")
+	assert.Contains(t, string(log), "Installation continues")
+}
+
+func TestRequirementWarnings(t *testing.T) {
+	controller, _ := newDriverController(t)
+	assert.Empty(t, controller.RequirementWarnings())
+
+	controller, _ = newDriverController(t)
+	controller.config.SystemRequirements = core.SystemRequirements{RecommendedCPUs: 1 << 20}
+	warnings := controller.RequirementWarnings()
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "processor cores recommended")
+
+	// The flow is not blocked by soft requirements
+	driver := NewTestDriver(controller)
+	require.NoError(t, driver.Run(wizard.ActionNext))
+	assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense))
+}
+
+// cancelView answers confirmations with yes and records their messages. Progress
+// is shown from the installation's goroutine, so it is not recorded.
+type cancelView struct {
+	*MockExtendedInstallerView
+	mu       sync.Mutex
+	messages []string
+}
+
+func (v *cancelView) Confirm(title, message string) (bool, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.messages = append(v.messages, message)
+	return true, nil
+}
+
+func (v *cancelView) ShowProgress(progress *core.Progress) error { return nil }
+
+// newCancelController returns a controller running a real installation of
+// components, with the user allowed to cancel it
+func newCancelController(t *testing.T, components []core.Component) (*InstallerController, *cancelView) {
+	config := &core.Config{
+		AppName:            "CancelApp",
+		InstallDir:         filepath.Join(t.TempDir(), "install"),
+		Rollback:           core.RollbackFull,
+		AllowInstallCancel: true,
+		Components:         components,
+	}
+	installer := core.New(config)
+	installer.SetContext(&core.Context{Config: config, Logger: core.NewLogger("error", ""), Metadata: map[string]interface{}{}})
+	controller := NewInstallerController(config, installer)
+	view := &cancelView{MockExtendedInstallerView: NewMockExtendedInstallerView()}
+	controller.SetView(view)
+	require.NoError(t, controller.Start())
+	return controller, view
+}
+
+// errorView records the error the view is asked to show
+type errorView struct {
+	*MockExtendedInstallerView
+	err error
+}
+
+func (v *errorView) ShowErrorMessage(err error) error {
+	v.err = err
+	return nil
+}
+
+func TestTamperedAssetsAbortControllerInstallation(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	assets := fstest.MapFS{"app.exe": {Data: []byte("binary")}}
+	manifest, err := core.GenerateAssetManifest(assets, private)
+	require.NoError(t, err)
+	assets[core.AssetManifestFile] = &fstest.MapFile{Data: manifest}
+	assets["app.exe"] = &fstest.MapFile{Data: []byte("patched")}
+
+	installed := false
+	config := &core.Config{
+		AppName:    "SignedApp",
+		InstallDir: filepath.Join(t.TempDir(), "install"),
+		Assets:     assets,
+		AssetKey:   public,
+		Components: []core.Component{{ID: "app", Name: "App", Required: true, Installer: func(ctx context.Context) error {
+			installed = true
+			return nil
+		}}},
+	}
+	installer := core.New(config)
+	installer.SetContext(&core.Context{Config: config, Logger: core.NewLogger("error", ""), Metadata: map[string]interface{}{}})
+	controller := NewInstallerController(config, installer)
+	view := &errorView{MockExtendedInstallerView: NewMockExtendedInstallerView()}
+	controller.SetView(view)
+
+	require.NoError(t, controller.Start())
+	for controller.GetCurrentState() != StateProgress {
+		require.NoError(t, controller.Next())
+	}
+	<-controller.installDone
+	assert.ErrorIs(t, view.err, core.ErrAssetsTampered)
+	assert.False(t, installed, "nothing may be installed from tampered assets")
+}
+
+func TestCancelBeforeInstall(t *testing.T) {
+	installed := false
+	controller, view := newCancelController(t, []core.Component{
+		{ID: "core", Name: "Core", Required: true, Installer: func(ctx context.Context) error {
+			installed = true
+			return nil
+		}},
+	})
+	require.NoError(t, controller.Next())
+
+	// The view ends the flow, nothing is left to undo
+	require.NoError(t, controller.Cancel())
+	require.Len(t, view.messages, 1)
+	assert.Contains(t, view.messages[0], "Nothing has been installed yet")
+	assert.False(t, installed)
+	assert.NoDirExists(t, controller.config.InstallDir)
+}
+
+func TestCancelDuringInstallRollsBack(t *testing.T) {
+	started := make(chan struct{})
+	var rolledBack []string
+	controller, view := newCancelController(t, []core.Component{
+		{ID: "core", Name: "Core", Required: true,
+			Installer:   func(ctx context.Context) error { return nil },
+			Uninstaller: func(ctx context.Context) error { rolledBack = append(rolledBack, "core"); return nil }},
+		{ID: "service", Name: "Service", Required: true,
+			Installer: func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			Uninstaller: func(ctx context.Context) error { rolledBack = append(rolledBack, "service"); return nil }},
+	})
+	for controller.GetCurrentState() != StateProgress {
+		require.NoError(t, controller.Next())
+	}
+	assert.True(t, controller.CanCancel())
+	<-started
+
+	require.NoError(t, controller.Cancel())
+	assert.Equal(t, StateCancelled, controller.GetCurrentState())
+	assert.Equal(t, []string{"service", "core"}, rolledBack)
+	require.Len(t, view.messages, 1)
+	assert.Contains(t, view.messages[0], "rolls back the changes made so far")
+	assert.NotContains(t, view.GetRecordedCalls(), "ShowErrorMessage")
+}
+
+func TestCancelDuringInstallPromptKeeps(t *testing.T) {
+	started := make(chan struct{})
+	var rolledBack []string
+	controller, view := newCancelController(t, []core.Component{
+		{ID: "core", Name: "Core", Required: true,
+			Installer:   func(ctx context.Context) error { return nil },
+			Uninstaller: func(ctx context.Context) error { rolledBack = append(rolledBack, "core"); return nil }},
+		{ID: "service", Name: "Service", Required: true,
+			Installer: func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			Uninstaller: func(ctx context.Context) error { rolledBack = append(rolledBack, "service"); return nil }},
+	})
+	controller.config.CancelPolicy = core.CancelPrompt
+	for controller.GetCurrentState() != StateProgress {
+		require.NoError(t, controller.Next())
+	}
+	<-started
+
+	// The view answers yes to keeping the installed components
+	require.NoError(t, controller.Cancel())
+	assert.Equal(t, StateCancelled, controller.GetCurrentState())
+	assert.Empty(t, rolledBack)
+	require.Len(t, view.messages, 2)
+	assert.Contains(t, view.messages[0], "keep what has been installed so far or roll it back")
+	assert.Contains(t, view.messages[1], "can resume the installation")
+
+	checkpoint := core.FindCheckpoint(controller.config.InstallDir)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, []string{"core"}, checkpoint.Completed)
+	assert.Equal(t, []string{"service"}, checkpoint.Remaining())
+}
+
+func TestCancelMessageFollowsPolicy(t *testing.T) {
+	controller, _ := newCancelController(t, nil)
+	controller.config.CancelPolicy = core.CancelKeepForResume
+	assert.Contains(t, controller.cancelMessage(true), "running the installer again can resume it")
+	assert.False(t, controller.promptsKeep())
+
+	controller.config.CancelPolicy = core.CancelPrompt
+	assert.True(t, controller.promptsKeep())
+	controller.config.Rollback = core.RollbackNone
+	assert.False(t, controller.promptsKeep(), "nothing to choose without a rollback")
+	assert.Contains(t, controller.cancelMessage(true), "the installation will be incomplete")
+}
+
+func TestDryRunGoesThroughProgress(t *testing.T) {
+	var mu sync.Mutex
+	var phases []core.Phase
+	config := &core.Config{
+		AppName:    "DryRunApp",
+		InstallDir: filepath.Join(t.TempDir(), "install"),
+		DryRun:     true,
+		Components: []core.Component{{ID: "app", Name: "App", Required: true, Selected: true}},
+		OnPhase: func(event core.PhaseEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			phases = append(phases, event.Phase)
+		},
+	}
+	installer := core.New(config)
+	installer.SetContext(&core.Context{Config: config, Logger: core.NewLogger("error", ""), Metadata: map[string]interface{}{}})
+	controller := NewInstallerController(config, installer)
+	controller.SetView(&cancelView{MockExtendedInstallerView: NewMockExtendedInstallerView()})
+	require.NoError(t, controller.Start())
+	for controller.GetCurrentState() != StateProgress {
+		require.NoError(t, controller.Next())
+	}
+
+	select {
+	case <-controller.installDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the dry run did not end")
+	}
+	assert.NotEmpty(t, controller.PlannedOperations())
+	mu.Lock()
+	assert.NotEmpty(t, phases, "the progress handlers run as without a dry run")
+	mu.Unlock()
+}
+
+func TestCancelDuringInstallNeedsPermission(t *testing.T) {
+	config := &core.Config{AppName: "NoCancelApp", InstallDir: filepath.Join(t.TempDir(), "install")}
+	controller := NewInstallerController(config, core.New(config))
+	config.DryRun = true
+	controller.SetView(NewMockExtendedInstallerView())
+
+	progress, err := controller.dfa.GetStateConfig(StateProgress)
+	require.NoError(t, err)
+	assert.False(t, progress.CanCancel)
+	assert.NotContains(t, progress.Transitions, wizard.ActionCancel)
+}
+
+func TestForwardOnlyFlow(t *testing.T) {
+	controller, _ := newDriverController(t)
+	controller.config.ForwardOnly = true
+	controller = NewInstallerController(controller.config, controller.installer)
+	driver := NewTestDriver(controller)
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next))
+	assert.False(t, controller.CanGoBack())
+	err := driver.Continue(wizard.ActionBack)
+	assert.ErrorIs(t, err, wizard.ErrForwardOnly)
+	assert.Equal(t, StateLicense, controller.GetCurrentState())
+	assert.Equal(t, []wizard.State{StateLicense}, controller.dfa.GetHistory())
+
+	require.NoError(t, driver.Continue(next, next, next, next))
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath, StateSummary, StateProgress, StateComplete))
+	assert.Equal(t, []wizard.State{StateComplete}, controller.dfa.GetHistory())
+	assert.Equal(t, controller.config.InstallDir, driver.Data()["install_path"], "data survives without history")
+}
+
+func TestSetSelectedComponents(t *testing.T) {
+	newController := func() *InstallerController {
+		config := &core.Config{
+			AppName:    "SelectApp",
+			InstallDir: filepath.Join(t.TempDir(), "install"),
+			Components: []core.Component{
+				{ID: "core", Name: "Core", Required: true},
+				{ID: "runtime", Name: "Runtime"},
+				{ID: "app", Name: "App", Dependencies: []string{"runtime"}},
+				{ID: "docs", Name: "Docs", Selected: true},
+				{ID: "plugin", Name: "Plugin", Dependencies: []string{"sdk"}},
+			},
+		}
+		return NewInstallerController(config, core.New(config))
+	}
+	ids := func(components []core.Component) []string {
+		var ids []string
+		for _, c := range components {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	t.Run("default", func(t *testing.T) {
+		assert.Equal(t, []string{"core", "docs"}, ids(newController().GetSelectedComponents()))
+	})
+
+	t.Run("missing dependency is selected", func(t *testing.T) {
+		controller := newController()
+		require.NoError(t, controller.SetSelectedComponents([]string{"app"}))
+		assert.Equal(t, []string{"core", "runtime", "app"}, ids(controller.GetSelectedComponents()))
+
+		// The components step shows the selection and keeps it
+		driver := NewTestDriver(controller)
+		require.NoError(t, driver.Run(wizard.ActionNext))
+		assert.NoError(t, driver.AssertStates(StateWelcome, StateComponents))
+		assert.Equal(t, []string{"core", "runtime", "app"}, ids(controller.GetSelectedComponents()))
+	})
+
+	t.Run("unknown dependency is rejected", func(t *testing.T) {
+		controller := newController()
+		err := controller.SetSelectedComponents([]string{"plugin"})
+		assert.ErrorContains(t, err, "unknown dependency sdk of plugin")
+		assert.Equal(t, []string{"core", "docs"}, ids(controller.GetSelectedComponents()))
+	})
+
+	t.Run("unknown component is rejected", func(t *testing.T) {
+		controller := newController()
+		assert.ErrorContains(t, controller.SetSelectedComponents([]string{"extras"}), "unknown component extras")
+		assert.Equal(t, []string{"core", "docs"}, ids(controller.GetSelectedComponents()))
+	})
+}
+
+// summaryView records the components shown by the summary
+type summaryView struct {
+	*MockExtendedInstallerView
+	shown      []core.Component
+	summarized []core.Component
+}
+
+func (v *summaryView) ShowComponents(components []core.Component) ([]core.Component, error) {
+	v.shown = components
+	return components, nil
+}
+
+func (v *summaryView) ShowSummary(config *core.Config, selected []core.Component, installPath string) (bool, error) {
+	v.summarized = selected
+	return true, nil
+}
+
+func TestPortableSummaryLeavesOutServices(t *testing.T) {
+	controller, _ := newDriverController(t)
+	controller.config.Portable = true
+	controller.config.Components[2].Selected = true
+	view := &summaryView{MockExtendedInstallerView: NewMockExtendedInstallerView()}
+	controller.SetView(view)
+
+	require.NoError(t, controller.Start())
+	for controller.GetCurrentState() != StateSummary {
+		require.NoError(t, controller.Next())
+	}
+	for _, components := range [][]core.Component{view.shown, view.summarized} {
+		for _, c := range components {
+			assert.NotEqual(t, "agent", c.ID, "a portable installation leaves out the service component")
+		}
+	}
+	assert.NotEmpty(t, view.summarized)
+}
@@ -0,0 +1,71 @@
+// Package controller provides the language selection custom state
+package controller
+
+import (
+	"fmt"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+const (
+	StateLanguage wizard.State = "language"
+)
+
+// LanguageHandler lets the user pick the locale of the installer screens
+// before the welcome screen. NewInstallerController adds it when more than
+// one locale is available and Config.Locale does not force one.
+//
+// The view receives "locale", the current locale, and "locales", the
+// available ones, and returns the chosen "locale". Views that return before
+// the user has chosen call InstallerController.SetLocale instead. The welcome
+// screen and the button labels switch to the locale at once, see
+// core.Localizer; it is stored in the state data as "locale" and in the
+// ResponseSettings.
+type LanguageHandler struct {
+	*BaseCustomStateHandler
+	config *core.Config
+}
+
+// NewLanguageHandler creates a language selection handler that starts the flow
+func NewLanguageHandler(config *core.Config) *LanguageHandler {
+	return &LanguageHandler{
+		BaseCustomStateHandler: &BaseCustomStateHandler{
+			StateID:     StateLanguage,
+			Name:        "Language",
+			Description: "Choose the language of the installer",
+			InsertPoint: InsertBeforeWelcome,
+			CanGoNext:   true,
+			CanCancel:   true,
+		},
+		config: config,
+	}
+}
+
+// HandleEnter implements CustomStateHandler
+func (h *LanguageHandler) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	view, ok := controller.view.(ExtendedInstallerView)
+	if !ok {
+		return fmt.Errorf("view does not support custom states")
+	}
+	result, err := view.ShowCustomState(StateLanguage, CustomStateData{
+		"locale":  controller.Localizer().Locale(),
+		"locales": core.AvailableLocales(h.config),
+	})
+	if err != nil {
+		return err
+	}
+	if locale, _ := result["locale"].(string); locale != "" {
+		if err := controller.SetLocale(locale); err != nil {
+			return err
+		}
+	}
+	data["locale"] = controller.Localizer().Locale()
+	return nil
+}
+
+// HandleLeave implements CustomStateHandler by recording the locale chosen
+func (h *LanguageHandler) HandleLeave(controller *InstallerController, data map[string]interface{}) error {
+	data["locale"] = controller.Localizer().Locale()
+	return nil
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+func newLanguageController(t *testing.T, locale string, locales ...string) *InstallerController {
+	controller, _ := newDriverController(t)
+	controller.config.Locale = locale
+	controller.config.Locales = locales
+	return NewInstallerController(controller.config, controller.installer)
+}
+
+func TestLanguageSwitchesLocale(t *testing.T) {
+	controller := newLanguageController(t, "", "en", "de")
+	driver := NewTestDriver(controller).Input(StateLanguage, "locale", "de")
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next))
+
+	assert.NoError(t, driver.AssertStates(StateLanguage, StateWelcome, StateLicense))
+	assert.Equal(t, "de", driver.Data()["locale"])
+	assert.Equal(t, "de", controller.Localizer().Locale())
+	assert.Equal(t, "Weiter", controller.PrimaryLabelFor(StateLicense))
+	assert.Equal(t, "Install", controller.PrimaryLabelFor(StateSummary), "configured labels are kept")
+	assert.Equal(t, "de", controller.ResponseSettings()[core.SettingLocale])
+}
+
+func TestLanguageBackFromWelcome(t *testing.T) {
+	driver := NewTestDriver(newLanguageController(t, "", "en", "de"))
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, wizard.ActionBack, next))
+
+	assert.NoError(t, driver.AssertStates(StateLanguage, StateWelcome, StateLanguage, StateWelcome))
+}
+
+func TestLanguageSkipped(t *testing.T) {
+	for name, tc := range map[string]struct {
+		locale  string
+		locales []string
+		want    string
+	}{
+		"single locale": {locales: []string{"de"}, want: "de"},
+		"no locales":    {want: core.DefaultLocale},
+		"forced locale": {locale: "de", locales: []string{"en", "de"}, want: "de"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			controller := newLanguageController(t, tc.locale, tc.locales...)
+			_, exists := controller.customStates.GetHandler(StateLanguage)
+			assert.False(t, exists)
+
+			driver := NewTestDriver(controller)
+			require.NoError(t, driver.Run(wizard.ActionNext))
+			assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense))
+			assert.Equal(t, tc.want, controller.Localizer().Locale())
+		})
+	}
+}
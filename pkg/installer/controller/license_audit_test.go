@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runToLicense starts a controller for config and advances past the license state
+func runToLicense(t *testing.T, config *core.Config) *core.Installer {
+	installer := core.New(config)
+	installer.SetContext(&core.Context{
+		Config:   config,
+		Logger:   core.NewLogger("info", ""),
+		Metadata: make(map[string]interface{}),
+	})
+
+	ctrl := NewInstallerController(config, installer)
+	ctrl.SetView(NewMockExtendedInstallerView())
+
+	require.NoError(t, ctrl.Start())
+	require.NoError(t, ctrl.Next()) // Welcome -> License
+	require.Equal(t, StateLicense, ctrl.GetCurrentState())
+	return installer
+}
+
+// TestLicenseAcceptanceInteractive tests the audit record for a user accepting the license
+func TestLicenseAcceptanceInteractive(t *testing.T) {
+	config := &core.Config{
+		AppName:    "AuditApp",
+		Version:    "1.0.0",
+		InstallDir: filepath.Join(t.TempDir(), "install"),
+		License:    "MIT License\n\nPermission is hereby granted...",
+		Components: []core.Component{{ID: "core", Name: "Core", Required: true, Selected: true}},
+	}
+
+	installer := runToLicense(t, config)
+
+	record := installer.GetLicenseAcceptance()
+	require.NotNil(t, record, "License acceptance should be recorded")
+	assert.Equal(t, core.HashLicense(config.License), record.LicenseHash)
+	assert.Len(t, record.LicenseHash, 64, "Hash should be hex encoded SHA-256")
+	assert.False(t, record.AutoAccepted, "Interactive acceptance should not be marked automatic")
+	assert.NotEmpty(t, record.User)
+	assert.False(t, record.AcceptedAt.IsZero())
+
+	summary := installer.CreateSummary()
+	assert.Same(t, record, summary.LicenseAcceptance, "Summary should expose the acceptance record")
+}
+
+// TestLicenseAcceptanceUnattended tests the audit record for configuration-based acceptance
+func TestLicenseAcceptanceUnattended(t *testing.T) {
+	config := &core.Config{
+		AppName:       "AuditApp",
+		Version:       "1.0.0",
+		InstallDir:    filepath.Join(t.TempDir(), "install"),
+		License:       "Proprietary License v2",
+		Unattended:    true,
+		AcceptLicense: true,
+		Components:    []core.Component{{ID: "core", Name: "Core", Required: true, Selected: true}},
+	}
+
+	installer := runToLicense(t, config)
+
+	record := installer.GetLicenseAcceptance()
+	require.NotNil(t, record, "License acceptance should be recorded")
+	assert.Equal(t, core.HashLicense("Proprietary License v2"), record.LicenseHash)
+	assert.True(t, record.AutoAccepted, "Unattended acceptance should be marked automatic")
+	assert.NotNil(t, installer.CreateSummary().LicenseAcceptance)
+}
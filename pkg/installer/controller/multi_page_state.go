@@ -0,0 +1,221 @@
+// Package controller provides multi-page configuration states
+package controller
+
+import (
+	"fmt"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+const (
+	StateMultiPageConfig wizard.State = "config"
+)
+
+// ConfigPage is one page of a MultiPageConfigState, declared as a form
+type ConfigPage struct {
+	ID       string                        // Unique within the state
+	UI       *core.UIStateConfig           // Fields of the page
+	Validate func(values FormValues) error // Checks the page after the field declarations, optional
+}
+
+// MultiPageConfigState is a custom state made of several form pages, such as
+// database, network and administrator settings, shown one after the other.
+// The install flow sees a single state; a wizard.HierarchicalDFA with a
+// sub-state per page keeps track of the page shown and of its values.
+//
+// InstallerController.Next validates the current page and turns to the next
+// one, Back turns to the previous one; only on the last page Next leaves the
+// state, and only on the first page Back does. Views render the current page
+// like a FormState, through FormFor, FormValues and SetFormValues, and
+// receive "page", the ID of the page, "page_index" and "page_count" as well.
+// Leaving the state stores the values of all pages in the state data under
+// DataKey, as map[string]FormValues by page ID.
+type MultiPageConfigState struct {
+	*BaseCustomStateHandler
+	Pages   []ConfigPage
+	DataKey string // string(StateID) if empty
+
+	pages *wizard.HierarchicalDFA
+	err   error // Declaration error, reported when the state is entered
+}
+
+// NewMultiPageConfigState creates a configuration state with pages, shown
+// after the installation path. Set StateID before registering it to add
+// more than one.
+func NewMultiPageConfigState(pages []ConfigPage) *MultiPageConfigState {
+	s := &MultiPageConfigState{
+		BaseCustomStateHandler: &BaseCustomStateHandler{
+			StateID:     StateMultiPageConfig,
+			Name:        "Configuration",
+			Description: "Configure the application",
+			InsertPoint: InsertAfterInstallPath,
+			CanGoNext:   true,
+			CanGoBack:   true,
+			CanCancel:   true,
+		},
+		Pages: pages,
+		pages: wizard.NewHierarchical(),
+	}
+	if len(pages) == 0 {
+		s.err = fmt.Errorf("configuration state without pages")
+		return s
+	}
+
+	main := wizard.MainState(StateMultiPageConfig)
+	s.err = s.pages.AddMainState(main, &wizard.MainStateConfig{InitialSubState: wizard.SubState(pages[0].ID)})
+	for _, page := range pages {
+		if s.err != nil {
+			break
+		}
+		if page.UI == nil {
+			s.err = fmt.Errorf("configuration page %s has no fields", page.ID)
+			break
+		}
+		s.err = s.pages.AddSubState(main, wizard.SubState(page.ID), &wizard.SubStateConfig{
+			Name:        page.UI.Title,
+			Description: page.UI.Description,
+		})
+	}
+	return s
+}
+
+// page returns the index of the page shown
+func (s *MultiPageConfigState) page() int {
+	current := string(s.pages.GetCurrentState().Sub)
+	for idx, page := range s.Pages {
+		if page.ID == current {
+			return idx
+		}
+	}
+	return 0
+}
+
+// UIConfig implements FormStateHandler with the fields of the page shown
+func (s *MultiPageConfigState) UIConfig() *core.UIStateConfig {
+	if len(s.Pages) == 0 {
+		return &core.UIStateConfig{}
+	}
+	return s.Pages[s.page()].UI
+}
+
+// pageValues returns a copy of the values of a page, its defaults until set
+func (s *MultiPageConfigState) pageValues(idx int) FormValues {
+	page := s.Pages[idx]
+	current, ok := wizard.DataAs[FormValues](s.pages.GetAllData(), page.ID)
+	if !ok {
+		return FormDefaults(page.UI)
+	}
+	values := make(FormValues, len(current))
+	for id, value := range current {
+		values[id] = value
+	}
+	return values
+}
+
+// formValues returns the values of the page shown
+func (s *MultiPageConfigState) formValues() FormValues {
+	if len(s.Pages) == 0 {
+		return FormValues{}
+	}
+	return s.pageValues(s.page())
+}
+
+// setFormValues stores the values of the page shown
+func (s *MultiPageConfigState) setFormValues(values FormValues) {
+	if len(s.Pages) > 0 {
+		s.pages.SetData(s.Pages[s.page()].ID, values)
+	}
+}
+
+// Results returns the values of all pages by page ID
+func (s *MultiPageConfigState) Results() map[string]FormValues {
+	results := make(map[string]FormValues, len(s.Pages))
+	for idx, page := range s.Pages {
+		results[page.ID] = s.pageValues(idx)
+	}
+	return results
+}
+
+// validatePage checks a page against its field declarations, then runs its Validate
+func (s *MultiPageConfigState) validatePage(idx int) error {
+	page := s.Pages[idx]
+	values := s.pageValues(idx)
+	if result := ValidateFormValues(page.UI, values); len(result) > 0 {
+		return result
+	}
+	if page.Validate != nil {
+		return page.Validate(values)
+	}
+	return nil
+}
+
+// turnPage validates the page shown when going forward and shows the page
+// by offset; it reports false if there is none, so the state is left
+func (s *MultiPageConfigState) turnPage(offset int) (bool, error) {
+	idx := s.page()
+	target := idx + offset
+	if s.err != nil || target < 0 || target >= len(s.Pages) {
+		return false, nil
+	}
+	if offset > 0 {
+		if err := s.validatePage(idx); err != nil {
+			return true, err
+		}
+	}
+	return true, s.pages.NavigateToSubState(wizard.SubState(s.Pages[target].ID))
+}
+
+// HandleEnter implements CustomStateHandler by showing the current page
+func (s *MultiPageConfigState) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	if s.err != nil {
+		return s.err
+	}
+	view, ok := controller.view.(ExtendedInstallerView)
+	if !ok {
+		return fmt.Errorf("view does not support custom states")
+	}
+	idx := s.page()
+	result, err := view.ShowCustomState(s.StateID, CustomStateData{
+		"form":       s.Pages[idx].UI,
+		"values":     s.pageValues(idx),
+		"page":       s.Pages[idx].ID,
+		"page_index": idx,
+		"page_count": len(s.Pages),
+	})
+	if err != nil {
+		return err
+	}
+	if values, ok := result["values"]; ok {
+		return controller.SetFormValues(s.StateID, values)
+	}
+	return nil
+}
+
+// Validate checks every page, so values entered before going back are
+// checked as well, then runs ValidateFunc and ValidateFieldsFunc
+func (s *MultiPageConfigState) Validate(controller *InstallerController, data map[string]interface{}) error {
+	if s.err != nil {
+		return s.err
+	}
+	current := s.page()
+	for idx, page := range s.Pages {
+		if err := s.validatePage(idx); err != nil {
+			if idx == current {
+				return err
+			}
+			return fmt.Errorf("%s: %w", page.UI.Title, err)
+		}
+	}
+	return s.BaseCustomStateHandler.Validate(controller, data)
+}
+
+// HandleLeave implements CustomStateHandler by storing the values of all pages
+func (s *MultiPageConfigState) HandleLeave(controller *InstallerController, data map[string]interface{}) error {
+	key := s.DataKey
+	if key == "" {
+		key = string(s.StateID)
+	}
+	data[key] = s.Results()
+	return nil
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+func configPages() []ConfigPage {
+	return []ConfigPage{
+		{ID: "db", UI: &core.UIStateConfig{Title: "Database", Fields: []core.UIField{
+			{ID: "db_host", Label: "Host", Type: core.FieldTypeText, Required: true},
+			{ID: "db_port", Label: "Port", Type: core.FieldTypeNumber, Value: "5432"},
+		}}},
+		{ID: "network", UI: &core.UIStateConfig{Title: "Network", Fields: []core.UIField{
+			{ID: "listen_port", Label: "Listen port", Type: core.FieldTypeNumber, Value: "8080"},
+		}}, Validate: func(values FormValues) error {
+			if port, _ := values["listen_port"].(int); port < 1024 {
+				return errors.New("the listen port must not be privileged")
+			}
+			return nil
+		}},
+		{ID: "admin", UI: &core.UIStateConfig{Title: "Administrator", Fields: []core.UIField{
+			{ID: "admin_user", Label: "User", Type: core.FieldTypeText, Value: "admin"},
+		}}},
+	}
+}
+
+func newMultiPageController(t *testing.T) (*InstallerController, *TestDriver) {
+	controller, _ := newDriverController(t)
+	require.NoError(t, controller.RegisterCustomState(NewMultiPageConfigState(configPages())))
+	return controller, NewTestDriver(controller)
+}
+
+func TestMultiPageConfigWalksPages(t *testing.T) {
+	controller, driver := newMultiPageController(t)
+	driver.Input(StateMultiPageConfig, "values", map[string]string{"db_host": "db.example", "admin_user": "root"})
+
+	next, back := wizard.ActionNext, wizard.ActionBack
+	require.NoError(t, driver.Run(next, next, next, next))
+	assert.Equal(t, "Database", controller.FormFor(StateMultiPageConfig).Title)
+
+	var pages []string
+	for _, action := range []wizard.Action{next, next, back, back, next} {
+		require.NoError(t, driver.Continue(action))
+		pages = append(pages, controller.FormFor(StateMultiPageConfig).Title)
+	}
+	assert.Equal(t, []string{"Network", "Administrator", "Network", "Database", "Network"}, pages)
+	assert.True(t, controller.CanGoBack())
+
+	require.NoError(t, controller.SetFormValues(StateMultiPageConfig, map[string]string{"listen_port": "9090"}))
+	require.NoError(t, driver.Continue(next, next))
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath, StateMultiPageConfig, StateSummary))
+	assert.Equal(t, map[string]FormValues{
+		"db":      {"db_host": "db.example", "db_port": 5432},
+		"network": {"listen_port": 9090},
+		"admin":   {"admin_user": "root"},
+	}, driver.Data()[string(StateMultiPageConfig)])
+
+	// Coming back from the summary shows the last page again
+	require.NoError(t, driver.Continue(back))
+	assert.Equal(t, StateMultiPageConfig, controller.GetCurrentState())
+	assert.Equal(t, "Administrator", controller.FormFor(StateMultiPageConfig).Title)
+}
+
+func TestMultiPageConfigValidatesPageOnLeave(t *testing.T) {
+	controller, driver := newMultiPageController(t)
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next))
+
+	// The database host is required before the network page
+	require.Error(t, driver.Continue(next))
+	assert.Equal(t, "Database", controller.FormFor(StateMultiPageConfig).Title)
+	assert.Equal(t, "db_host", driver.FieldErrors()[0].Field)
+
+	require.NoError(t, controller.SetFormValues(StateMultiPageConfig, map[string]string{"db_host": "db.example"}))
+	require.NoError(t, driver.Continue(next))
+	require.NoError(t, controller.SetFormValues(StateMultiPageConfig, map[string]string{"listen_port": "80"}))
+	err := driver.Continue(next)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "privileged")
+	assert.Equal(t, "Network", controller.FormFor(StateMultiPageConfig).Title)
+}
+
+func TestMultiPageConfigBackLeavesFirstPage(t *testing.T) {
+	controller, driver := newMultiPageController(t)
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, wizard.ActionBack))
+	assert.Equal(t, StateInstallPath, controller.GetCurrentState())
+	results, _ := driver.Data()[string(StateMultiPageConfig)].(map[string]FormValues)
+	assert.Equal(t, FormValues{"listen_port": 8080}, results["network"], "pages not shown keep their defaults")
+}
+
+func TestMultiPageConfigRejectsBadDeclarations(t *testing.T) {
+	for name, pages := range map[string][]ConfigPage{
+		"no pages":       nil,
+		"duplicate page": {configPages()[0], configPages()[0]},
+		"no fields":      {{ID: "empty"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			controller, _ := newDriverController(t)
+			require.NoError(t, controller.RegisterCustomState(NewMultiPageConfigState(pages)))
+			driver := NewTestDriver(controller)
+
+			next := wizard.ActionNext
+			assert.Error(t, driver.Run(next, next, next, next))
+		})
+	}
+}
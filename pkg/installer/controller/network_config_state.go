@@ -0,0 +1,77 @@
+// Package controller provides the network configuration custom state
+package controller
+
+import (
+	"fmt"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+const (
+	StateNetworkConfig wizard.State = "network-config"
+)
+
+// NetworkConfigHandler lets the user review and edit the proxy settings used
+// for downloads and telemetry. Register it with RegisterCustomState.
+type NetworkConfigHandler struct {
+	*BaseCustomStateHandler
+}
+
+// NewNetworkConfigHandler creates a network configuration handler shown after the welcome screen
+func NewNetworkConfigHandler() *NetworkConfigHandler {
+	return &NetworkConfigHandler{
+		BaseCustomStateHandler: &BaseCustomStateHandler{
+			StateID:     StateNetworkConfig,
+			Name:        "Network Configuration",
+			Description: "Configure proxy servers for downloads",
+			InsertPoint: InsertAfterWelcome,
+			CanGoNext:   true,
+			CanGoBack:   true,
+			CanCancel:   true,
+		},
+	}
+}
+
+// HandleEnter implements CustomStateHandler
+func (h *NetworkConfigHandler) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	// Start from the configured values, completed from the environment
+	if _, exists := data["network_config"]; !exists {
+		settings := core.EffectiveProxySettings(controller.config)
+		data["network_config"] = &settings
+	}
+
+	view, ok := controller.view.(ExtendedInstallerView)
+	if !ok {
+		return fmt.Errorf("view does not support custom states")
+	}
+	result, err := view.ShowCustomState(StateNetworkConfig, CustomStateData{"config": data["network_config"]})
+	if err != nil {
+		return err
+	}
+	if settings, ok := result["config"].(*core.ProxySettings); ok {
+		data["network_config"] = settings
+	}
+	return nil
+}
+
+// HandleLeave implements CustomStateHandler
+func (h *NetworkConfigHandler) HandleLeave(controller *InstallerController, data map[string]interface{}) error {
+	settings, ok := data["network_config"].(*core.ProxySettings)
+	if !ok {
+		return nil
+	}
+	controller.config.HTTPProxy = settings.HTTPProxy
+	controller.config.HTTPSProxy = settings.HTTPSProxy
+	controller.config.NoProxy = settings.NoProxy
+	return nil
+}
+
+// Validate implements CustomStateHandler
+func (h *NetworkConfigHandler) Validate(controller *InstallerController, data map[string]interface{}) error {
+	settings, ok := data["network_config"].(*core.ProxySettings)
+	if !ok {
+		return fmt.Errorf("network configuration not found")
+	}
+	return settings.Validate()
+}
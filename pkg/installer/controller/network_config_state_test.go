@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func TestNetworkConfigHandlerUpdatesProxySettings(t *testing.T) {
+	config := &core.Config{AppName: "ProxyApp", HTTPProxy: "http://proxy.corp:3128"}
+	controller := NewInstallerController(config, core.New(config))
+	view := NewMockExtendedInstallerView()
+	controller.SetView(view)
+
+	// The user adds an HTTPS proxy and an exception
+	edited := &core.ProxySettings{HTTPProxy: "http://proxy.corp:3128", HTTPSProxy: "http://user:pw@proxy.corp:3129", NoProxy: ".corp.lan"}
+	view.SetReturnData(CustomStateData{"config": edited})
+
+	handler := NewNetworkConfigHandler()
+	data := make(map[string]interface{})
+	require.NoError(t, handler.HandleEnter(controller, data))
+
+	shown, ok := view.GetCustomStateData(StateNetworkConfig)
+	require.True(t, ok)
+	assert.Equal(t, "http://proxy.corp:3128", shown["config"].(*core.ProxySettings).HTTPProxy)
+
+	require.NoError(t, handler.Validate(controller, data))
+	require.NoError(t, handler.HandleLeave(controller, data))
+	assert.Equal(t, "http://user:pw@proxy.corp:3129", config.HTTPSProxy)
+	assert.Equal(t, ".corp.lan", config.NoProxy)
+
+	data["network_config"] = &core.ProxySettings{HTTPProxy: "http://:bad"}
+	assert.Error(t, handler.Validate(controller, data))
+}
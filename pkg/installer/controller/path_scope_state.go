@@ -0,0 +1,106 @@
+// Package controller provides the PATH scope custom state
+package controller
+
+import (
+	"fmt"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+const (
+	StatePathScope wizard.State = "path-scope"
+)
+
+// PathScopeHandler lets the user choose whether the installation changes
+// the user or the system PATH. NewInstallerController adds it when
+// Config.PathConfig is enabled with AskScope set; portable installations,
+// which leave PATH alone, pass it over.
+//
+// The view receives "scope", the default choice, "dirs", the directories
+// added, and "elevated", whether the installer runs with administrator
+// rights, and returns the chosen "scope", core.PathScopeNameUser or
+// core.PathScopeNameSystem. Views that return before the user has chosen
+// call InstallerController.SetPathScope instead. The choice is written to
+// Config.PathConfig and stored in the state data as "path_scope".
+type PathScopeHandler struct {
+	*BaseCustomStateHandler
+	config *core.Config
+
+	// elevated reports whether the installer runs elevated, which makes the
+	// system PATH the default
+	elevated func() bool
+}
+
+// NewPathScopeHandler creates a PATH scope handler shown after the installation path
+func NewPathScopeHandler(config *core.Config) *PathScopeHandler {
+	return &PathScopeHandler{
+		BaseCustomStateHandler: &BaseCustomStateHandler{
+			StateID:     StatePathScope,
+			Name:        "PATH",
+			Description: "Choose the PATH to add the application to",
+			InsertPoint: InsertAfterInstallPath,
+			CanGoNext:   true,
+			CanGoBack:   true,
+			CanCancel:   true,
+		},
+		config:   config,
+		elevated: core.DefaultPathSystem,
+	}
+}
+
+// GetConfig implements CustomStateHandler
+func (h *PathScopeHandler) GetConfig() *wizard.StateConfig {
+	config := h.BaseCustomStateHandler.GetConfig()
+	config.Optional = true
+	config.CanEnterFunc = func(data map[string]interface{}) bool {
+		return !h.config.Portable
+	}
+	return config
+}
+
+// HandleEnter implements CustomStateHandler
+func (h *PathScopeHandler) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	view, ok := controller.view.(ExtendedInstallerView)
+	if !ok {
+		return fmt.Errorf("view does not support custom states")
+	}
+	pc := h.config.PathConfig
+	elevated := h.elevated()
+
+	// The first visit defaults to the install scope chosen or by elevation,
+	// later ones keep the choice
+	scope, _ := wizard.DataAs[string](data, "path_scope")
+	if _, chosen := data["install_scope"]; scope == "" && chosen {
+		scope = pc.Scope()
+	}
+	if scope == "" {
+		scope = core.PathScopeNameUser
+		if elevated {
+			scope = core.PathScopeNameSystem
+		}
+	}
+
+	result, err := view.ShowCustomState(StatePathScope, CustomStateData{
+		"scope":    scope,
+		"dirs":     pc.Dirs,
+		"elevated": elevated,
+	})
+	if err != nil {
+		return err
+	}
+	if chosen, _ := result["scope"].(string); chosen != "" {
+		scope = chosen
+	}
+	if err := pc.SetScope(scope); err != nil {
+		return err
+	}
+	data["path_scope"] = pc.Scope()
+	return nil
+}
+
+// HandleLeave implements CustomStateHandler by recording the scope chosen
+func (h *PathScopeHandler) HandleLeave(controller *InstallerController, data map[string]interface{}) error {
+	data["path_scope"] = h.config.PathConfig.Scope()
+	return nil
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+func newPathScopeController(t *testing.T, elevated bool) *InstallerController {
+	controller, installDir := newDriverController(t)
+	controller.config.PathConfig = &core.PathConfiguration{
+		Enabled:  true,
+		Dirs:     []string{installDir + "/bin"},
+		AskScope: true,
+	}
+	controller = NewInstallerController(controller.config, controller.installer)
+	handler, exists := controller.customStates.GetHandler(StatePathScope)
+	require.True(t, exists)
+	handler.(*PathScopeHandler).elevated = func() bool { return elevated }
+	return controller
+}
+
+func TestPathScopeDefaultsByElevation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		elevated bool
+		want     string
+	}{
+		"elevated":     {elevated: true, want: core.PathScopeNameSystem},
+		"not elevated": {elevated: false, want: core.PathScopeNameUser},
+	} {
+		t.Run(name, func(t *testing.T) {
+			controller := newPathScopeController(t, tc.elevated)
+			driver := NewTestDriver(controller)
+
+			next := wizard.ActionNext
+			require.NoError(t, driver.Run(next, next, next, next, next))
+
+			assert.NoError(t, driver.AssertStates(
+				StateWelcome, StateLicense, StateComponents, StateInstallPath,
+				StatePathScope, StateSummary))
+			assert.Equal(t, tc.want, controller.config.PathConfig.Scope())
+			assert.Equal(t, tc.want, driver.Data()["path_scope"])
+		})
+	}
+}
+
+func TestPathScopeChoiceUpdatesConfig(t *testing.T) {
+	controller := newPathScopeController(t, false)
+	driver := NewTestDriver(controller).Input(StatePathScope, "scope", core.PathScopeNameSystem)
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, next))
+
+	assert.True(t, controller.config.PathConfig.System)
+	assert.Equal(t, core.PathScopeNameSystem, controller.ResponseSettings()[core.SettingPathScope])
+	assert.Contains(t, controller.config.PathConfig.Describe(), "system PATH")
+
+	kinds := make([]core.PrivilegeKind, 0)
+	for _, p := range core.PrivilegesFor(controller.config, nil, controller.config.InstallDir) {
+		kinds = append(kinds, p.Kind)
+	}
+	assert.Contains(t, kinds, core.PrivilegeSystemPath)
+}
+
+func TestPathScopeKeptWhenRevisited(t *testing.T) {
+	controller := newPathScopeController(t, true)
+	driver := NewTestDriver(controller)
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next))
+	require.NoError(t, controller.SetPathScope(core.PathScopeNameUser))
+	require.NoError(t, driver.Continue(wizard.ActionBack, next))
+
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath,
+		StatePathScope, StateInstallPath, StatePathScope))
+	assert.Equal(t, core.PathScopeNameUser, controller.config.PathConfig.Scope())
+}
+
+func TestPathScopeStateNotAddedWithoutAskScope(t *testing.T) {
+	controller, _ := newDriverController(t)
+	controller.config.PathConfig = &core.PathConfiguration{Enabled: true, Dirs: []string{"/opt/app/bin"}}
+	controller = NewInstallerController(controller.config, controller.installer)
+
+	_, exists := controller.customStates.GetHandler(StatePathScope)
+	assert.False(t, exists)
+	assert.Error(t, NewInstallerController(&core.Config{}, nil).SetPathScope(core.PathScopeNameUser))
+}
+
+func TestPathScopeSkippedWhenPortable(t *testing.T) {
+	controller := newPathScopeController(t, true)
+	controller.config.Portable = true
+	driver := NewTestDriver(controller)
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, wizard.ActionBack))
+
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath,
+		StateSummary, StateInstallPath))
+	assert.False(t, controller.config.PathConfig.System, "the PATH scope is left alone")
+	assert.NotContains(t, driver.Data(), "path_scope")
+}
@@ -0,0 +1,73 @@
+// Package controller provides the release notes custom state
+package controller
+
+import (
+	"fmt"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+const (
+	StateReleaseNotes wizard.State = "release-notes"
+)
+
+// ReleaseNotesHandler shows what changed since the installed version when an
+// older installation is updated, and is passed over otherwise.
+// NewInstallerController adds it when Config.ReleaseNotes is set.
+//
+// The view receives "installed_version", "version" and "notes", the
+// release notes as markdown.
+type ReleaseNotesHandler struct {
+	*BaseCustomStateHandler
+	config *core.Config
+}
+
+// NewReleaseNotesHandler creates a release notes handler shown after the installation path
+func NewReleaseNotesHandler(config *core.Config) *ReleaseNotesHandler {
+	return &ReleaseNotesHandler{
+		BaseCustomStateHandler: &BaseCustomStateHandler{
+			StateID:     StateReleaseNotes,
+			Name:        "What's New",
+			Description: "Changes since the installed version",
+			InsertPoint: InsertAfterInstallPath,
+			CanGoNext:   true,
+			CanGoBack:   true,
+			CanCancel:   true,
+		},
+		config: config,
+	}
+}
+
+// GetConfig implements CustomStateHandler
+func (h *ReleaseNotesHandler) GetConfig() *wizard.StateConfig {
+	config := h.BaseCustomStateHandler.GetConfig()
+	config.Optional = true
+	config.CanEnterFunc = func(data map[string]interface{}) bool {
+		_, notes := h.notes(data)
+		return notes != ""
+	}
+	return config
+}
+
+// HandleEnter implements CustomStateHandler
+func (h *ReleaseNotesHandler) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	view, ok := controller.view.(ExtendedInstallerView)
+	if !ok {
+		return fmt.Errorf("view does not support custom states")
+	}
+	installed, notes := h.notes(data)
+	_, err := view.ShowCustomState(StateReleaseNotes, CustomStateData{
+		"installed_version": installed,
+		"version":           h.config.Version,
+		"notes":             notes,
+	})
+	return err
+}
+
+// notes returns the installed version and the release notes for the chosen
+// installation path, empty unless an older installation is updated
+func (h *ReleaseNotesHandler) notes(data map[string]interface{}) (installed, notes string) {
+	path, _ := wizard.DataAs[string](data, "install_path")
+	return core.UpgradeNotes(h.config, path)
+}
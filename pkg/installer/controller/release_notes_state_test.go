@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+func newReleaseNotesController(t *testing.T, installedVersion string) *InstallerController {
+	controller, installDir := newDriverController(t)
+	controller.config.ReleaseNotes = []core.ReleaseNote{
+		{Version: "1.0.0", Text: "- New dashboard"},
+		{Version: "0.9.0", Text: "- Old change"},
+	}
+	if installedVersion != "" {
+		manifest := &core.Manifest{AppName: "DriverApp", Version: installedVersion, InstallDir: installDir}
+		require.NoError(t, manifest.Save())
+	}
+	return NewInstallerController(controller.config, controller.installer)
+}
+
+func TestReleaseNotesShownOnUpgrade(t *testing.T) {
+	driver := NewTestDriver(newReleaseNotesController(t, "0.9.0"))
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, wizard.ActionBack, next, next))
+
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath,
+		StateReleaseNotes, StateInstallPath, StateReleaseNotes, StateSummary))
+}
+
+func TestReleaseNotesSkipped(t *testing.T) {
+	for name, installed := range map[string]string{
+		"fresh install": "",
+		"same version":  "1.0.0",
+		"downgrade":     "2.0.0",
+	} {
+		t.Run(name, func(t *testing.T) {
+			driver := NewTestDriver(newReleaseNotesController(t, installed))
+
+			next := wizard.ActionNext
+			require.NoError(t, driver.Run(next, next, next, next, wizard.ActionBack))
+
+			assert.NoError(t, driver.AssertStates(
+				StateWelcome, StateLicense, StateComponents, StateInstallPath,
+				StateSummary, StateInstallPath))
+		})
+	}
+}
+
+func TestReleaseNotesStateNotAddedWithoutNotes(t *testing.T) {
+	controller, _ := newDriverController(t)
+	_, exists := controller.customStates.GetHandler(StateReleaseNotes)
+	assert.False(t, exists)
+}
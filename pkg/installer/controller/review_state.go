@@ -0,0 +1,327 @@
+// Package controller provides the review custom state
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+const (
+	StateReview wizard.State = "review"
+)
+
+// Fields of the review form for the values of the standard states
+const (
+	ReviewFieldInstallPath = "install_path"
+	ReviewFieldComponents  = "components" // IDs of the selected components
+)
+
+// ReviewHandler shows all values collected so far on one form, so that the
+// user can change them without going back: the installation path, the
+// selected components, the database configuration of the DatabaseConfigHandler
+// and the fields of all form states, credentials as password fields. Add it
+// with InstallerController.RegisterReviewState after the states whose values
+// it shows.
+//
+// Like a FormState, the view receives "form" and "values" and returns the
+// edited "values". The fields of the database configuration are "db.host",
+// "db.port" and so on, those of form states are "<state>.<field>". Going on
+// checks the values like the states they come from did and only then writes
+// them back to the flow data, the installer and the form states.
+type ReviewHandler struct {
+	*BaseCustomStateHandler
+	controller *InstallerController
+	ui         *core.UIStateConfig
+	confirmed  *reviewEdit // Values to write back on leaving, see confirm
+}
+
+// RegisterReviewState adds a ReviewHandler after the installation path and
+// the custom states registered there before it
+func (ic *InstallerController) RegisterReviewState() error {
+	return ic.RegisterCustomState(&ReviewHandler{
+		BaseCustomStateHandler: &BaseCustomStateHandler{
+			StateID:     StateReview,
+			Name:        "Review",
+			Description: "Check and change the settings before installing",
+			InsertPoint: InsertAfterInstallPath,
+			CanGoNext:   true,
+			CanGoBack:   true,
+			CanCancel:   true,
+		},
+		controller: ic,
+		ui:         &core.UIStateConfig{Title: "Review", Type: core.UIStateTypeInput},
+	})
+}
+
+// UIConfig implements FormStateHandler with the fields of the last review
+func (h *ReviewHandler) UIConfig() *core.UIStateConfig {
+	return h.ui
+}
+
+// GetConfig implements CustomStateHandler with the checks of the reviewed
+// values as validation
+func (h *ReviewHandler) GetConfig() *wizard.StateConfig {
+	config := h.BaseCustomStateHandler.GetConfig()
+	config.ValidateFunc = h.validate
+	return config
+}
+
+// HandleEnter implements CustomStateHandler
+func (h *ReviewHandler) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	view, ok := controller.view.(ExtendedInstallerView)
+	if !ok {
+		return fmt.Errorf("view does not support custom states")
+	}
+
+	// Each visit shows the values as they are now
+	values := h.review()
+	if err := controller.SetFormValues(StateReview, values); err != nil {
+		return err
+	}
+	result, err := view.ShowCustomState(StateReview, CustomStateData{
+		"form":   h.ui,
+		"values": controller.FormValues(StateReview),
+	})
+	if err != nil {
+		return err
+	}
+	if values, ok := result["values"]; ok {
+		return controller.SetFormValues(StateReview, values)
+	}
+	return nil
+}
+
+// review builds the form from the values collected so far and returns them
+func (h *ReviewHandler) review() FormValues {
+	ic := h.controller
+	var ids, available []string
+	for _, c := range selectedOnly(ic.session.SelectedComponents()) {
+		ids = append(ids, c.ID)
+	}
+	for _, c := range ic.config.Components {
+		available = append(available, c.ID)
+	}
+
+	ui := &core.UIStateConfig{
+		Title:       "Review",
+		Description: "Check the settings and change any of them before installing",
+		Type:        core.UIStateTypeInput,
+		Fields: []core.UIField{
+			{ID: ReviewFieldInstallPath, Label: "Installation directory", Type: core.FieldTypePath, Required: true},
+			{ID: ReviewFieldComponents, Label: "Components", Type: core.FieldTypeList,
+				Help: "Components to install, separated by commas: " + strings.Join(available, ", ")},
+		},
+	}
+	values := FormValues{ReviewFieldInstallPath: ic.session.InstallPath(), ReviewFieldComponents: ids}
+
+	if db, ok := ic.stateData["db_config"].(*DatabaseConfig); ok {
+		ui.Fields = append(ui.Fields, databaseFields()...)
+		for id, value := range databaseValues(db) {
+			values[id] = value
+		}
+	}
+
+	for _, form := range h.forms() {
+		state := form.GetStateID()
+		current := ic.FormValues(state)
+		for _, field := range form.UIConfig().Fields {
+			field.ID = reviewFieldID(state, field.ID)
+			if field.Label != "" {
+				field.Label = form.UIConfig().Title + ": " + field.Label
+			}
+			ui.Fields = append(ui.Fields, field)
+		}
+		for id, value := range current {
+			values[reviewFieldID(state, id)] = value
+		}
+	}
+
+	h.ui = ui
+	return values
+}
+
+// reviewEdit holds the reviewed values once they are checked
+type reviewEdit struct {
+	path       string
+	components []core.Component // All components with the reviewed selection
+	selected   []core.Component
+	db         *DatabaseConfig // nil without a database configuration
+	forms      map[wizard.State]FormValues
+}
+
+// validate checks the reviewed values like the states they come from
+func (h *ReviewHandler) validate(data map[string]interface{}) error {
+	_, err := h.check()
+	return err
+}
+
+// check returns the reviewed values if all are valid. It changes nothing.
+func (h *ReviewHandler) check() (*reviewEdit, error) {
+	ic := h.controller
+	values := ic.FormValues(StateReview)
+	result := ValidateFormValues(h.ui, values)
+	edit := &reviewEdit{forms: make(map[wizard.State]FormValues)}
+
+	path, _ := values[ReviewFieldInstallPath].(string)
+	if expanded, err := core.ExpandPath(path); err != nil {
+		result.AddError(ReviewFieldInstallPath, err)
+	} else {
+		edit.path = expanded
+	}
+
+	edit.components = slices.Clone(ic.config.Components)
+	ids, _ := values[ReviewFieldComponents].([]string)
+	if err := core.SelectComponents(edit.components, ids); err != nil {
+		result.AddError(ReviewFieldComponents, err)
+	}
+
+	if db, ok := ic.stateData["db_config"].(*DatabaseConfig); ok {
+		edit.db = editedDatabase(db, values)
+		if handler, ok := ic.customStates.GetHandler(StateDBConfig); ok {
+			err := handler.Validate(ic, map[string]interface{}{"db_config": edit.db})
+			var fields ValidationResult
+			if errors.As(err, &fields) {
+				for _, e := range fields {
+					result = append(result, FieldError{Field: "db." + e.Field, Message: e.Message, Err: e.Err})
+				}
+			} else if err != nil {
+				result.AddError("db.host", err)
+			}
+		}
+	}
+
+	for _, form := range h.forms() {
+		state := form.GetStateID()
+		edited := ic.FormValues(state)
+		for _, field := range form.UIConfig().Fields {
+			edited[field.ID] = values[reviewFieldID(state, field.ID)]
+		}
+		for _, e := range ValidateFormValues(form.UIConfig(), edited) {
+			result = append(result, FieldError{Field: reviewFieldID(state, e.Field), Message: e.Message, Err: e.Err})
+		}
+		edit.forms[state] = edited
+	}
+	if len(result) > 0 {
+		return nil, result
+	}
+
+	edit.selected = selectedOnly(edit.components)
+	for idx := range edit.selected {
+		edit.selected[idx].Selected = true
+	}
+	return edit, nil
+}
+
+// confirm asks what going on with the valid reviewed values raises, a
+// changed directory or selection like on its own screen. The values are
+// written back once the review is left, see apply.
+func (h *ReviewHandler) confirm() error {
+	ic := h.controller
+	edit, err := h.check()
+	if err != nil {
+		return err
+	}
+	if edit.path != ic.session.InstallPath() {
+		if err := ic.confirmOverwrite(edit.path); err != nil {
+			return err
+		}
+	}
+	if err := ic.requireComponentLicenses(edit.selected); err != nil {
+		return err
+	}
+	h.confirmed = edit
+	return nil
+}
+
+// apply writes the confirmed values back to the flow data, the installer and
+// the form states when the review is left. It does nothing on going back.
+func (h *ReviewHandler) apply(data map[string]interface{}) error {
+	edit := h.confirmed
+	h.confirmed = nil
+	if edit == nil {
+		return nil
+	}
+
+	ic := h.controller
+	data["install_path"] = edit.path
+	data["selected_components"] = edit.selected
+	for idx := range ic.config.Components {
+		ic.config.Components[idx].Selected = edit.components[idx].Selected
+	}
+	ic.session.SetInstallPath(edit.path)
+	ic.session.SetSelectedComponents(edit.selected)
+	if edit.db != nil {
+		ic.stateData["db_config"] = edit.db
+		ic.stateData["database_config"] = edit.db
+	}
+	for state, edited := range edit.forms {
+		if err := ic.SetFormValues(state, edited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forms returns the form states whose fields the review shows
+func (h *ReviewHandler) forms() []FormStateHandler {
+	var forms []FormStateHandler
+	for _, handler := range h.controller.customStates.GetAll() {
+		if form, ok := handler.(FormStateHandler); ok && handler.GetStateID() != StateReview {
+			forms = append(forms, form)
+		}
+	}
+	return forms
+}
+
+// reviewFieldID returns the ID of the review field for field of a form state
+func reviewFieldID(state wizard.State, field string) string {
+	return string(state) + "." + field
+}
+
+// databaseFields returns the review fields of a DatabaseConfig
+func databaseFields() []core.UIField {
+	var types []core.FieldOption
+	for _, t := range []string{"mysql", "postgresql", "sqlite", "sqlserver"} {
+		types = append(types, core.FieldOption{ID: t, Label: t})
+	}
+	return []core.UIField{
+		{ID: "db.type", Label: "Database type", Type: core.FieldTypeDropdown, Options: types},
+		{ID: "db.host", Label: "Database host", Type: core.FieldTypeText},
+		{ID: "db.port", Label: "Database port", Type: core.FieldTypeNumber},
+		{ID: "db.database", Label: "Database name", Type: core.FieldTypeText},
+		{ID: "db.username", Label: "Database user", Type: core.FieldTypeText},
+		{ID: "db.password", Label: "Database password", Type: core.FieldTypePassword},
+		{ID: "db.ssl", Label: "Use SSL", Type: core.FieldTypeCheckbox},
+	}
+}
+
+// databaseValues returns the review values of db
+func databaseValues(db *DatabaseConfig) FormValues {
+	return FormValues{
+		"db.type":     db.Type,
+		"db.host":     db.Host,
+		"db.port":     db.Port,
+		"db.database": db.Database,
+		"db.username": db.Username,
+		"db.password": db.Password,
+		"db.ssl":      db.UseSSL,
+	}
+}
+
+// editedDatabase returns a copy of db with the reviewed values
+func editedDatabase(db *DatabaseConfig, values FormValues) *DatabaseConfig {
+	edited := *db
+	edited.Type, _ = values["db.type"].(string)
+	edited.Host, _ = values["db.host"].(string)
+	edited.Database, _ = values["db.database"].(string)
+	edited.Username, _ = values["db.username"].(string)
+	edited.Password, _ = values["db.password"].(string)
+	edited.Port, _ = values["db.port"].(int)
+	edited.UseSSL, _ = values["db.ssl"].(bool)
+	return &edited
+}
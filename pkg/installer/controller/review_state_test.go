@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+// newReviewController returns a driver controller with a database state, a
+// form with a password and the review after them
+func newReviewController(t *testing.T) (*InstallerController, *TestDriver) {
+	controller, _ := newDriverController(t)
+	require.NoError(t, controller.RegisterCustomState(NewDatabaseConfigHandler()))
+	require.NoError(t, controller.RegisterCustomState(NewFormState("service", InsertAfterInstallPath, &core.UIStateConfig{
+		Title: "Service account",
+		Fields: []core.UIField{
+			{ID: "user", Label: "User", Type: core.FieldTypeText, Required: true, Value: "svc"},
+			{ID: "password", Label: "Password", Type: core.FieldTypePassword, Value: "secret"},
+		},
+	})))
+	require.NoError(t, controller.RegisterReviewState())
+
+	driver := NewTestDriver(controller)
+	driver.Input(StateDBConfig, "config", &DatabaseConfig{Type: "sqlite", Database: "app.db"})
+	return controller, driver
+}
+
+func TestReviewShowsCollectedValues(t *testing.T) {
+	controller, driver := newReviewController(t)
+	installDir := controller.config.InstallDir
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, next, next))
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath, StateDBConfig, "service", StateReview))
+
+	values := controller.FormValues(StateReview)
+	assert.Equal(t, installDir, values[ReviewFieldInstallPath])
+	assert.Equal(t, []string{"core", "docs"}, values[ReviewFieldComponents])
+	assert.Equal(t, "app.db", values["db.database"])
+	assert.Equal(t, "secret", values["service.password"])
+
+	var password core.UIField
+	for _, field := range controller.GetCustomStates()[len(controller.GetCustomStates())-1].(FormStateHandler).UIConfig().Fields {
+		if field.ID == "service.password" {
+			password = field
+		}
+	}
+	assert.Equal(t, core.FieldTypePassword, password.Type, "credentials must stay masked")
+}
+
+func TestReviewEditsAreUsedForInstall(t *testing.T) {
+	controller, driver := newReviewController(t)
+	edited := filepath.Join(t.TempDir(), "edited")
+	driver.Input(StateReview, "values", map[string]string{
+		ReviewFieldInstallPath: edited,
+		ReviewFieldComponents:  "agent",
+		"db.database":          "edited.db",
+		"service.user":         "admin",
+	})
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, next, next, next, next))
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath, StateDBConfig, "service", StateReview,
+		StateSummary, StateProgress, StateComplete))
+
+	assert.NoError(t, driver.AssertData(map[string]interface{}{"install_path": edited}))
+	assert.NoError(t, driver.AssertOperations(
+		"install to "+edited,
+		"install component core",
+		"install component agent",
+		"run post-install actions of agent",
+		"install service driver-agent"))
+	db, ok := controller.GetStateData()["database_config"].(*DatabaseConfig)
+	require.True(t, ok, "database configuration missing")
+	assert.Equal(t, "edited.db", db.Database)
+	assert.Equal(t, "admin", controller.FormValues("service")["user"])
+}
+
+func TestReviewRejectsInvalidEdits(t *testing.T) {
+	controller, driver := newReviewController(t)
+	driver.Input(StateReview, "values", map[string]string{
+		ReviewFieldInstallPath: "",
+		ReviewFieldComponents:  "extras",
+		"db.type":              "mysql",
+		"db.host":              "",
+		"service.user":         "",
+	})
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, next, next))
+	err := controller.Next()
+	var result ValidationResult
+	require.ErrorAs(t, err, &result)
+	for _, field := range []string{ReviewFieldInstallPath, ReviewFieldComponents, "db.host", "service.user"} {
+		assert.NotEmpty(t, result.Field(field), "no error for %s", field)
+	}
+	assert.Equal(t, StateReview, controller.GetCurrentState())
+
+	// Nothing was written back
+	db := controller.GetStateData()["database_config"].(*DatabaseConfig)
+	assert.Equal(t, "sqlite", db.Type)
+	assert.Equal(t, "svc", controller.FormValues("service")["user"])
+}
+
+func TestReviewValidationOnlyChecks(t *testing.T) {
+	controller, driver := newReviewController(t)
+	installDir := controller.config.InstallDir
+	occupied := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(occupied, "other.txt"), []byte("x"), 0644))
+	driver.Input(StateReview, "values", map[string]string{
+		ReviewFieldInstallPath: occupied,
+		ReviewFieldComponents:  "core",
+		"service.user":         "admin",
+	}).Answer("Overwrite files", true)
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, next, next))
+	require.NoError(t, controller.dfa.ValidateCurrentState())
+	assert.Empty(t, driver.Confirmations(), "validating the review must not ask")
+	assert.Equal(t, installDir, controller.Session().InstallPath())
+	assert.Equal(t, "svc", controller.FormValues("service")["user"])
+	assert.True(t, controller.config.Components[1].Selected, "the selection changed on validation")
+
+	require.NoError(t, controller.Next())
+	assert.Equal(t, StateSummary, controller.GetCurrentState())
+	assert.Equal(t, []string{"Overwrite files"}, driver.Confirmations())
+	assert.Equal(t, occupied, controller.Session().InstallPath())
+	assert.Equal(t, "admin", controller.FormValues("service")["user"])
+	assert.False(t, controller.config.Components[1].Selected)
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+// selectedIDs returns the IDs of components
+func selectedIDs(components []core.Component) []string {
+	var ids []string
+	for _, c := range components {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestSessionHasPathAndSelectionAfterTheirStates(t *testing.T) {
+	controller, installDir := newDriverController(t)
+	session := controller.Session()
+	require.NotNil(t, session)
+	assert.Equal(t, installDir, session.InstallPath(), "before the path state the configured one")
+	assert.Equal(t, []string{"core", "docs"}, selectedIDs(session.SelectedComponents()))
+
+	chosen := filepath.Join(t.TempDir(), "chosen")
+	driver := NewTestDriver(controller)
+	driver.Input(StateComponents, InputComponents, []string{"agent"})
+	driver.Input(StateInstallPath, InputInstallPath, chosen)
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next))
+	assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense, StateComponents))
+	assert.Equal(t, []string{"core", "agent"}, selectedIDs(session.SelectedComponents()))
+	assert.True(t, session.IsSelected("agent"))
+	assert.False(t, session.IsSelected("docs"))
+	assert.Equal(t, installDir, session.InstallPath(), "the path state has not been entered")
+
+	require.NoError(t, driver.Continue(next))
+	assert.Equal(t, chosen, session.InstallPath())
+	assert.Equal(t, chosen, controller.installer.GetConfig().InstallDir, "the session updates the installer")
+}
+
+// portStateHandler is a custom state that stores a port in the state data
+type portStateHandler struct {
+	BaseCustomStateHandler
+}
+
+func (h *portStateHandler) HandleEnter(controller *InstallerController, data map[string]interface{}) error {
+	data["port"] = 8080
+	return nil
+}
+
+func TestSessionKeepsCustomValues(t *testing.T) {
+	controller, _ := newDriverController(t)
+	require.NoError(t, controller.RegisterCustomState(&portStateHandler{BaseCustomStateHandler{
+		StateID:     "custom",
+		InsertPoint: InsertAfterWelcome,
+		CanGoNext:   true,
+		CanGoBack:   true,
+	}}))
+	driver := NewTestDriver(controller)
+
+	require.NoError(t, driver.Run(wizard.ActionNext))
+	assert.NoError(t, driver.AssertStates(StateWelcome, "custom"))
+
+	port, ok := core.SessionValue[int](controller.Session(), "port")
+	assert.True(t, ok)
+	assert.Equal(t, 8080, port)
+	_, ok = core.SessionValue[string](controller.Session(), "port")
+	assert.False(t, ok, "a value of another type")
+
+	controller.Session().Set("mode", "server")
+	assert.Equal(t, "server", controller.GetStateData()["mode"], "the map shows the session's values")
+}
+
+// accountStateHandler is a custom state working on the session
+type accountStateHandler struct {
+	BaseCustomStateHandler
+	left bool
+}
+
+func (h *accountStateHandler) HandleEnterSession(controller *InstallerController, session *core.InstallSession) error {
+	if _, ok := session.Value("user"); !ok {
+		session.Set("user", "")
+	}
+	return nil
+}
+
+func (h *accountStateHandler) HandleLeaveSession(controller *InstallerController, session *core.InstallSession) error {
+	h.left = true
+	return nil
+}
+
+func (h *accountStateHandler) ValidateSession(controller *InstallerController, session *core.InstallSession) error {
+	if user, _ := core.SessionValue[string](session, "user"); user == "" {
+		return errors.New("enter a user")
+	}
+	return nil
+}
+
+func TestSessionStateHandler(t *testing.T) {
+	controller, _ := newDriverController(t)
+	handler := &accountStateHandler{BaseCustomStateHandler: BaseCustomStateHandler{
+		StateID:     "account",
+		InsertPoint: InsertAfterWelcome,
+		CanGoNext:   true,
+		CanGoBack:   true,
+	}}
+	require.NoError(t, controller.RegisterCustomState(handler))
+	driver := NewTestDriver(controller)
+
+	require.NoError(t, driver.Run(wizard.ActionNext))
+	assert.NoError(t, driver.AssertStates(StateWelcome, "account"))
+	assert.Contains(t, controller.GetStateData(), "user", "entered with the session")
+	assert.EqualError(t, controller.Next(), "enter a user")
+
+	controller.Session().Set("user", "alice")
+	require.NoError(t, controller.Next())
+	assert.True(t, handler.left)
+	assert.Equal(t, StateLicense, controller.GetCurrentState())
+}
@@ -0,0 +1,424 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+// Input fields understood by the TestDriver for the standard states
+const (
+	InputAcceptLicense   = "accept"           // bool, default true
+	InputDeclineLicenses = "decline_licenses" // []string of component IDs whose license is declined, set for StateLicense
+	InputComponents      = "components"       // []string of component IDs, default the preselected ones
+	InputInstallPath     = "install_path"     // string, default the proposed path
+	InputProceed         = "proceed"          // bool answer on the summary, default true
+)
+
+// driverTimeout bounds the wait for the dry run to reach the complete state
+const driverTimeout = 5 * time.Second
+
+// TestDriver runs an installer flow against scripted answers, so flows with
+// custom states can be tested without a UI. It puts the controller's
+// configuration into dry-run mode, and the progress state only records the
+// planned operations instead of running the installation.
+//
+//	d := controller.NewTestDriver(ic)
+//	d.Input(StateDBConfig, "config", dbConfig)
+//	if err := d.Run(wizard.ActionNext, wizard.ActionNext); err != nil { ... }
+//	if err := d.AssertStates(StateWelcome, StateLicense, StateDBConfig); err != nil { t.Error(err) }
+type TestDriver struct {
+	controller *InstallerController
+	inputs     map[wizard.State]CustomStateData
+	answers    map[string]bool // Confirmation answers by title
+
+	mu            sync.Mutex
+	visited       []wizard.State
+	confirmations []string
+	licenses      []string
+	helps         []string
+	errors        []error
+	fieldErrors   ValidationResult
+	complete      chan struct{}
+}
+
+// NewTestDriver attaches a scripted view to ic
+func NewTestDriver(ic *InstallerController) *TestDriver {
+	ic.config.DryRun = true
+	ic.planOnly = true
+	d := &TestDriver{
+		controller: ic,
+		inputs:     make(map[wizard.State]CustomStateData),
+		answers:    make(map[string]bool),
+		complete:   make(chan struct{}, 1),
+	}
+	ic.SetView(&driverView{driver: d})
+	return d
+}
+
+// Input sets the answer the view gives for field when state is shown. For
+// custom states the fields are those of the returned CustomStateData.
+func (d *TestDriver) Input(state wizard.State, field string, value interface{}) *TestDriver {
+	if d.inputs[state] == nil {
+		d.inputs[state] = make(CustomStateData)
+	}
+	d.inputs[state][field] = value
+	return d
+}
+
+// Answer sets the answer to the confirmation with title; unscripted
+// confirmations are answered with yes
+func (d *TestDriver) Answer(title string, yes bool) *TestDriver {
+	d.answers[title] = yes
+	return d
+}
+
+// Run starts the flow and performs actions in order. It stops at the first
+// action that fails and returns its error; what happened until then can still
+// be asserted. Entering the progress state waits for the dry run to complete.
+func (d *TestDriver) Run(actions ...wizard.Action) error {
+	if err := d.controller.Start(); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	return d.Continue(actions...)
+}
+
+// Continue performs actions in the running flow, like Run without starting it,
+// e.g. after a Jump.
+func (d *TestDriver) Continue(actions ...wizard.Action) error {
+	for idx, action := range actions {
+		var err error
+		switch action {
+		case wizard.ActionNext:
+			err = d.controller.Next()
+		case wizard.ActionBack:
+			err = d.controller.Back()
+		case wizard.ActionCancel:
+			err = d.controller.Cancel()
+		case wizard.ActionHelp:
+			err = d.controller.Help()
+		default:
+			err = fmt.Errorf("unsupported action")
+		}
+		if err != nil {
+			return fmt.Errorf("action %d (%s) in state %s: %w", idx+1, action, d.controller.GetCurrentState(), err)
+		}
+		if d.controller.GetCurrentState() == StateProgress {
+			if err := d.waitComplete(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Jump returns to state, see InstallerController.Jump
+func (d *TestDriver) Jump(state wizard.State) error {
+	if err := d.controller.Jump(state); err != nil {
+		return fmt.Errorf("jump from %s to %s: %w", d.controller.GetCurrentState(), state, err)
+	}
+	return nil
+}
+
+// waitComplete waits until the dry run left the progress state
+func (d *TestDriver) waitComplete() error {
+	select {
+	case <-d.complete:
+		return nil
+	case <-time.After(driverTimeout):
+		return fmt.Errorf("dry run did not complete within %v", driverTimeout)
+	}
+}
+
+// record notes that state was entered
+func (d *TestDriver) record(state wizard.State) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.visited = append(d.visited, state)
+}
+
+// Visited returns the states entered, in order, including the initial one
+func (d *TestDriver) Visited() []wizard.State {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]wizard.State{}, d.visited...)
+}
+
+// Data returns the flow data, from the standard and the custom states. Like
+// in the custom state handlers, the standard states' data wins.
+func (d *TestDriver) Data() map[string]interface{} {
+	data := make(map[string]interface{})
+	for k, v := range d.controller.GetStateData() {
+		data[k] = v
+	}
+	for k, v := range d.controller.dfa.GetAllData() {
+		data[k] = v
+	}
+	return data
+}
+
+// Errors returns the errors shown to the user
+func (d *TestDriver) Errors() []error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]error{}, d.errors...)
+}
+
+// Confirmations returns the titles of the confirmations asked, in order
+func (d *TestDriver) Confirmations() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string{}, d.confirmations...)
+}
+
+// Licenses returns the titles of the licenses asked for, in order; each
+// is accepted on its own
+func (d *TestDriver) Licenses() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.licenses...)
+}
+
+// Helps returns the help texts shown, in order
+func (d *TestDriver) Helps() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string{}, d.helps...)
+}
+
+// FieldErrors returns the last field errors shown to the user
+func (d *TestDriver) FieldErrors() ValidationResult {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.fieldErrors
+}
+
+// AssertStates compares the visited states with want
+func (d *TestDriver) AssertStates(want ...wizard.State) error {
+	return diffError("visited states", statesToStrings(want), statesToStrings(d.Visited()))
+}
+
+// AssertData compares the keys of want with the flow data; other keys are ignored
+func (d *TestDriver) AssertData(want map[string]interface{}) error {
+	data := d.Data()
+	keys := make([]string, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var wantLines, gotLines []string
+	for _, k := range keys {
+		wantLines = append(wantLines, fmt.Sprintf("%s = %#v", k, want[k]))
+		got, ok := data[k]
+		switch {
+		case !ok:
+			gotLines = append(gotLines, fmt.Sprintf("%s missing", k))
+		case reflect.DeepEqual(got, want[k]):
+			gotLines = append(gotLines, wantLines[len(wantLines)-1])
+		default:
+			gotLines = append(gotLines, fmt.Sprintf("%s = %#v", k, got))
+		}
+	}
+	return diffError("data", wantLines, gotLines)
+}
+
+// AssertOperations compares the operations planned by the dry run with want
+func (d *TestDriver) AssertOperations(want ...string) error {
+	return diffError("planned operations", want, d.controller.PlannedOperations())
+}
+
+func statesToStrings(states []wizard.State) []string {
+	lines := make([]string, len(states))
+	for i, s := range states {
+		lines[i] = string(s)
+	}
+	return lines
+}
+
+// diffError returns nil if want and got are equal, or an error listing the
+// lines as a unified diff: "-" only wanted, "+" only got
+func diffError(what string, want, got []string) error {
+	if reflect.DeepEqual(want, got) || (len(want) == 0 && len(got) == 0) {
+		return nil
+	}
+	return fmt.Errorf("%s differ (-want +got):\n%s", what, diffLines(want, got))
+}
+
+// diffLines diffs two line lists using their longest common subsequence
+func diffLines(a, b []string) string {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&out, "  %s\n", a[i])
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "- %s\n", a[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+ %s\n", b[j])
+			j++
+		}
+	}
+	return out.String()
+}
+
+// driverView answers the controller with the driver's inputs
+type driverView struct {
+	driver *TestDriver
+}
+
+// input returns the scripted value of field in state
+func (v *driverView) input(state wizard.State, field string) (interface{}, bool) {
+	value, ok := v.driver.inputs[state][field]
+	return value, ok
+}
+
+func (v *driverView) ShowWelcome() error { return nil }
+
+func (v *driverView) ShowLicense(license string) (bool, error) {
+	if accept, ok := v.input(StateLicense, InputAcceptLicense); ok {
+		return accept.(bool), nil
+	}
+	return true, nil
+}
+
+// ShowLicenses accepts each license unless InputAcceptLicense is false or
+// its component is among InputDeclineLicenses
+func (v *driverView) ShowLicenses(licenses []core.ApplicableLicense) ([]bool, error) {
+	accept := true
+	if a, ok := v.input(StateLicense, InputAcceptLicense); ok {
+		accept = a.(bool)
+	}
+	declined, _ := v.input(StateLicense, InputDeclineLicenses)
+	answers := make([]bool, len(licenses))
+	v.driver.mu.Lock()
+	defer v.driver.mu.Unlock()
+	for i, l := range licenses {
+		v.driver.licenses = append(v.driver.licenses, l.Title)
+		answers[i] = accept
+		if l.ComponentID != "" && declined != nil && containsString(declined.([]string), l.ComponentID) {
+			answers[i] = false
+		}
+	}
+	return answers, nil
+}
+
+func (v *driverView) ShowComponents(components []core.Component) ([]core.Component, error) {
+	ids, scripted := v.input(StateComponents, InputComponents)
+	var selected []core.Component
+	for _, c := range components {
+		want := c.Selected || c.Required
+		if scripted {
+			want = c.Required || containsString(ids.([]string), c.ID)
+		}
+		if want {
+			c.Selected = true
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+func (v *driverView) ShowInstallPath(defaultPath string) (string, error) {
+	if path, ok := v.input(StateInstallPath, InputInstallPath); ok {
+		return path.(string), nil
+	}
+	return defaultPath, nil
+}
+
+func (v *driverView) ShowSummary(config *core.Config, selected []core.Component, installPath string) (bool, error) {
+	if proceed, ok := v.input(StateSummary, InputProceed); ok {
+		return proceed.(bool), nil
+	}
+	return true, nil
+}
+
+func (v *driverView) ShowProgress(progress *core.Progress) error { return nil }
+
+func (v *driverView) ShowComplete(summary *core.InstallSummary) error {
+	select {
+	case v.driver.complete <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (v *driverView) ShowErrorMessage(err error) error {
+	v.driver.mu.Lock()
+	defer v.driver.mu.Unlock()
+	v.driver.errors = append(v.driver.errors, err)
+	return nil
+}
+
+func (v *driverView) ShowFieldErrors(result ValidationResult) error {
+	v.driver.mu.Lock()
+	defer v.driver.mu.Unlock()
+	v.driver.fieldErrors = result
+	return nil
+}
+
+func (v *driverView) Confirm(title, message string) (bool, error) {
+	v.driver.mu.Lock()
+	defer v.driver.mu.Unlock()
+	v.driver.confirmations = append(v.driver.confirmations, title)
+	if answer, ok := v.driver.answers[title]; ok {
+		return answer, nil
+	}
+	return true, nil
+}
+
+func (v *driverView) ShowHelp(title, help string) error {
+	v.driver.mu.Lock()
+	defer v.driver.mu.Unlock()
+	v.driver.helps = append(v.driver.helps, help)
+	return nil
+}
+
+func (v *driverView) OnStateChanged(oldState, newState wizard.State) error {
+	v.driver.record(newState)
+	return nil
+}
+
+func (v *driverView) ShowCustomState(stateID wizard.State, data CustomStateData) (CustomStateData, error) {
+	result := make(CustomStateData, len(data))
+	for k, val := range data {
+		result[k] = val
+	}
+	for k, val := range v.driver.inputs[stateID] {
+		result[k] = val
+	}
+	return result, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
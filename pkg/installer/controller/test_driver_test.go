@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+func newDriverController(t *testing.T) (*InstallerController, string) {
+	installDir := filepath.Join(t.TempDir(), "install")
+	config := &core.Config{
+		AppName:    "DriverApp",
+		Version:    "1.0.0",
+		License:    "License text",
+		InstallDir: installDir,
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Selected: true},
+			{ID: "docs", Name: "Docs", Selected: true},
+			{ID: "agent", Name: "Agent", Services: []string{"driver-agent"},
+				PostInstall: func(ctx context.Context, dir string) error { return nil }},
+		},
+	}
+	return NewInstallerController(config, core.New(config)), installDir
+}
+
+func TestTestDriverStandardFlow(t *testing.T) {
+	controller, installDir := newDriverController(t)
+	driver := NewTestDriver(controller)
+	driver.Input(StateComponents, InputComponents, []string{"agent"})
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, next))
+
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath,
+		StateSummary, StateProgress, StateComplete))
+	assert.NoError(t, driver.AssertData(map[string]interface{}{
+		"license_accepted": true,
+		"install_path":     installDir,
+	}))
+	assert.NoError(t, driver.AssertOperations(
+		"install to "+installDir,
+		"install component core",
+		"install component agent",
+		"run post-install actions of agent",
+		"install service driver-agent"))
+	assert.NoDirExists(t, installDir, "a dry run must not install")
+}
+
+func TestTestDriverCustomState(t *testing.T) {
+	controller, _ := newDriverController(t)
+	require.NoError(t, controller.RegisterCustomState(NewActivationStateHandler(ActivationOptions{
+		VerifyOffline: func(key, code string) (string, error) {
+			if code != "OK" {
+				return "", errors.New("code rejected")
+			}
+			return "token-" + key, nil
+		},
+	})))
+	driver := NewTestDriver(controller)
+	driver.Input(StateActivation, "license_key", " KEY-1 ").
+		Input(StateActivation, "offline_code", "OK").
+		Input(StateInstallPath, InputInstallPath, filepath.Join(t.TempDir(), "custom"))
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, wizard.ActionBack, next, next))
+
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateActivation, StateComponents,
+		StateActivation, StateComponents, StateInstallPath))
+	assert.NoError(t, driver.AssertData(map[string]interface{}{
+		"license_key":      "KEY-1",
+		"activation_token": "token-KEY-1",
+	}))
+}
+
+func TestTestDriverFieldErrors(t *testing.T) {
+	controller, _ := newDriverController(t)
+	require.NoError(t, controller.RegisterCustomState(NewActivationStateHandler(ActivationOptions{
+		VerifyOffline: func(key, code string) (string, error) {
+			return "", errors.New("code rejected")
+		},
+	})))
+	driver := NewTestDriver(controller)
+	driver.Input(StateActivation, "license_key", "KEY-1").
+		Input(StateActivation, "offline_code", "WRONG")
+
+	next := wizard.ActionNext
+	err := driver.Run(next, next, next)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "action 3")
+	assert.NoError(t, driver.AssertStates(StateWelcome, StateLicense, StateActivation))
+	require.Len(t, driver.FieldErrors(), 1)
+	assert.Equal(t, "offline_code", driver.FieldErrors()[0].Field)
+}
+
+func TestTestDriverDiff(t *testing.T) {
+	controller, _ := newDriverController(t)
+	driver := NewTestDriver(controller)
+	require.NoError(t, driver.Run(wizard.ActionNext))
+
+	err := driver.AssertStates(StateWelcome, StateComponents)
+	require.Error(t, err)
+	assert.Equal(t, "visited states differ (-want +got):\n"+
+		"  welcome\n"+
+		"- components\n"+
+		"+ license\n", err.Error())
+
+	err = driver.AssertData(map[string]interface{}{"license_accepted": false, "missing": 1})
+	require.Error(t, err)
+	assert.Equal(t, "data differ (-want +got):\n"+
+		"- license_accepted = false\n"+
+		"- missing = 1\n"+
+		"+ license_accepted = true\n"+
+		"+ missing missing\n", err.Error())
+}
+
+func TestTestDriverChangeFromSummary(t *testing.T) {
+	controller, installDir := newDriverController(t)
+	require.NoError(t, controller.RegisterCustomState(NewDatabaseConfigHandler()))
+	db := DefaultDatabaseConfig()
+	db.Host = "db.example.com"
+	driver := NewTestDriver(controller)
+	driver.Input(StateComponents, InputComponents, []string{"agent"}).
+		Input(StateDBConfig, "config", db)
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, next))
+	require.Equal(t, StateSummary, controller.GetCurrentState())
+	selected, _ := wizard.DataAs[[]core.Component](driver.Data(), "selected_components")
+	assert.Equal(t, []ChangeTarget{
+		{State: StateComponents, Name: "Component Selection"},
+		{State: StateInstallPath, Name: "Installation Path"},
+		{State: StateDBConfig, Name: "Database Configuration"},
+	}, controller.ChangeTargets(selected))
+
+	assert.Error(t, driver.Jump(StateProgress), "only visited steps can be changed")
+
+	changed := filepath.Join(t.TempDir(), "changed")
+	driver.Input(StateInstallPath, InputInstallPath, changed)
+	require.NoError(t, driver.Jump(StateInstallPath))
+	require.NoError(t, driver.Continue(next))
+
+	assert.NoError(t, driver.AssertStates(
+		StateWelcome, StateLicense, StateComponents, StateInstallPath, StateDBConfig,
+		StateSummary, StateInstallPath, StateSummary))
+	assert.NoError(t, driver.AssertData(map[string]interface{}{
+		"install_path":        changed,
+		"selected_components": selected,
+		"db_config":           db,
+	}))
+
+	require.NoError(t, driver.Continue(next))
+	assert.Contains(t, controller.PlannedOperations(), "install to "+changed)
+	assert.NotContains(t, controller.PlannedOperations(), "install to "+installDir)
+}
+
+func TestTestDriverSaveDiagnostics(t *testing.T) {
+	controller, _ := newDriverController(t)
+	driver := NewTestDriver(controller)
+
+	next := wizard.ActionNext
+	require.NoError(t, driver.Run(next, next, next, next, next))
+	require.Equal(t, StateComplete, controller.GetCurrentState())
+
+	path, err := controller.SaveDiagnostics()
+	require.NoError(t, err)
+	t.Cleanup(func() { os.Remove(path) })
+
+	archive, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer archive.Close()
+	history, err := archive.Open(core.DiagnosticsHistory)
+	require.NoError(t, err)
+	defer history.Close()
+	data, err := io.ReadAll(history)
+	require.NoError(t, err)
+	assert.Equal(t, "welcome\nlicense\ncomponents\ninstall-path\nsummary\nprogress\ncomplete\n", string(data))
+}
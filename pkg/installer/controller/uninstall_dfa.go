@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+// Uninstall flow states
+const (
+	StateUninstallPreview  wizard.State = "uninstall-preview"
+	StateUninstallProgress wizard.State = "uninstall-progress"
+	StateUninstallComplete wizard.State = "uninstall-complete"
+)
+
+// UninstallView is implemented by UIs that drive the uninstall flow
+type UninstallView interface {
+	// ShowRemovalPlan presents what will be removed and asks whether to keep
+	// modified files and whether to remove user data, which should default to no.
+	ShowRemovalPlan(plan *core.RemovalPlan) (proceed bool, opts core.UninstallOptions, err error)
+	ShowUninstallComplete(plan *core.RemovalPlan) error
+	ShowErrorMessage(err error) error
+}
+
+// UninstallController manages the uninstall flow using DFA
+type UninstallController struct {
+	dfa         *wizard.DFA
+	uninstaller *core.Uninstaller
+	view        UninstallView
+	plan        *core.RemovalPlan
+}
+
+// NewUninstallController creates a DFA-based uninstall controller
+func NewUninstallController(uninstaller *core.Uninstaller) *UninstallController {
+	uc := &UninstallController{
+		dfa:         wizard.New(),
+		uninstaller: uninstaller,
+	}
+	uc.setupDFA()
+	return uc
+}
+
+// SetView sets the view implementation
+func (uc *UninstallController) SetView(view UninstallView) {
+	uc.view = view
+}
+
+// Plan returns the removal plan shown to the user
+func (uc *UninstallController) Plan() *core.RemovalPlan {
+	return uc.plan
+}
+
+// setupDFA configures the uninstall states and transitions
+func (uc *UninstallController) setupDFA() {
+	uc.dfa.SetCallbacks(&wizard.Callbacks{
+		OnEnter: func(state wizard.State, data map[string]interface{}) error {
+			return uc.handleStateEnter(state, data)
+		},
+	})
+
+	uc.addState(StateUninstallPreview, &wizard.StateConfig{
+		Name:        "Uninstall",
+		Description: "Review what will be removed",
+		CanGoNext:   true,
+		CanCancel:   true,
+		Transitions: map[wizard.Action]wizard.State{
+			wizard.ActionNext:   StateUninstallProgress,
+			wizard.ActionCancel: StateCancelled,
+		},
+	})
+
+	uc.addState(StateUninstallProgress, &wizard.StateConfig{
+		Name:        "Uninstalling",
+		Description: "Removal in progress",
+		CanGoNext:   true,
+		Transitions: map[wizard.Action]wizard.State{
+			wizard.ActionNext: StateUninstallComplete, // Automatic transition after removal
+		},
+	})
+
+	uc.addState(StateUninstallComplete, &wizard.StateConfig{
+		Name:        "Uninstall Complete",
+		Description: "Removal finished successfully",
+	})
+
+	uc.addState(StateCancelled, &wizard.StateConfig{
+		Name:        "Uninstall Cancelled",
+		Description: "Uninstall was cancelled",
+	})
+
+	uc.dfa.SetInitialState(StateUninstallPreview)
+	uc.dfa.AddFinalState(StateUninstallComplete)
+	uc.dfa.AddFinalState(StateCancelled)
+}
+
+// addState adds a state to the DFA
+func (uc *UninstallController) addState(state wizard.State, config *wizard.StateConfig) {
+	if err := uc.dfa.AddState(state, config); err != nil {
+		panic(fmt.Sprintf("Failed to add state %s: %v", state, err))
+	}
+}
+
+// handleStateEnter shows the plan, runs the removal and reports the result
+func (uc *UninstallController) handleStateEnter(state wizard.State, data map[string]interface{}) error {
+	if uc.view == nil {
+		return fmt.Errorf("no view set")
+	}
+
+	switch state {
+	case StateUninstallPreview:
+		plan, err := uc.uninstaller.PreviewRemoval()
+		if err != nil {
+			return err
+		}
+		uc.plan = plan
+
+		proceed, opts, err := uc.view.ShowRemovalPlan(plan)
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			return fmt.Errorf("uninstall cancelled by user")
+		}
+		data["uninstall_options"] = opts
+		return nil
+
+	case StateUninstallProgress:
+		opts, _ := wizard.DataAs[core.UninstallOptions](data, "uninstall_options")
+		go func() {
+			if err := uc.uninstaller.Uninstall(uc.plan, opts); err != nil {
+				uc.view.ShowErrorMessage(err)
+				return
+			}
+			// Auto-transition to complete when done
+			uc.dfa.Next()
+		}()
+		return nil
+
+	case StateUninstallComplete:
+		return uc.view.ShowUninstallComplete(uc.plan)
+
+	case StateCancelled:
+		return nil
+	}
+	return fmt.Errorf("unknown state: %s", state)
+}
+
+// Start begins the uninstall flow
+func (uc *UninstallController) Start() error {
+	return uc.dfa.Start()
+}
+
+// Next confirms the removal plan
+func (uc *UninstallController) Next() error {
+	return uc.dfa.Next()
+}
+
+// Cancel aborts the uninstall before removal starts
+func (uc *UninstallController) Cancel() error {
+	return uc.dfa.Cancel()
+}
+
+// GetCurrentState returns the current uninstall state
+func (uc *UninstallController) GetCurrentState() wizard.State {
+	return uc.dfa.CurrentState()
+}
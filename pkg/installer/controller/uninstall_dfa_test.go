@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// uninstallTestView keeps modified files and reports completion on a channel
+type uninstallTestView struct {
+	shownPlan *core.RemovalPlan
+	done      chan error
+}
+
+func (v *uninstallTestView) ShowRemovalPlan(plan *core.RemovalPlan) (bool, core.UninstallOptions, error) {
+	v.shownPlan = plan
+	return true, core.UninstallOptions{KeepModified: len(plan.ModifiedFiles()) > 0}, nil
+}
+
+func (v *uninstallTestView) ShowUninstallComplete(plan *core.RemovalPlan) error {
+	v.done <- nil
+	return nil
+}
+
+func (v *uninstallTestView) ShowErrorMessage(err error) error {
+	v.done <- err
+	return nil
+}
+
+func TestUninstallControllerShowsPlan(t *testing.T) {
+	installDir := t.TempDir()
+	for name, content := range map[string]string{"app.bin": "binary", "app.conf": "default"} {
+		require.NoError(t, os.WriteFile(filepath.Join(installDir, name), []byte(content), 0644))
+	}
+	manifest := &core.Manifest{AppName: "UninstallApp", InstallDir: installDir}
+	manifest.SetComponent(core.NewManifestComponent(installDir, core.Component{ID: "core", Files: []string{"app.bin", "app.conf"}}))
+	require.NoError(t, manifest.Save())
+
+	// The user edited the config file after installation
+	require.NoError(t, os.WriteFile(filepath.Join(installDir, "app.conf"), []byte("custom"), 0644))
+
+	uninstaller, err := core.NewUninstaller(&core.Config{AppName: "UninstallApp", InstallDir: installDir, LogLevel: "error"})
+	require.NoError(t, err)
+
+	view := &uninstallTestView{done: make(chan error, 1)}
+	uc := NewUninstallController(uninstaller)
+	uc.SetView(view)
+
+	require.NoError(t, uc.Start())
+	require.NotNil(t, view.shownPlan)
+	assert.Len(t, view.shownPlan.ModifiedFiles(), 1)
+
+	require.NoError(t, uc.Next())
+	select {
+	case err := <-view.done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Uninstall did not complete")
+	}
+
+	assert.Equal(t, StateUninstallComplete, uc.GetCurrentState())
+	assert.NoFileExists(t, filepath.Join(installDir, "app.bin"))
+	assert.FileExists(t, filepath.Join(installDir, "app.conf"))
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError reports a problem with a single form field of a state
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Err     error  `json:"-"` // Underlying error, if any, reachable through errors.Is
+}
+
+// ValidationResult collects the field errors of a state. A non-empty result
+// returned from Validate is passed to FieldErrorsView.ShowFieldErrors.
+type ValidationResult []FieldError
+
+// Add records a message for field
+func (r *ValidationResult) Add(field, format string, args ...interface{}) {
+	*r = append(*r, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// AddError records err for field
+func (r *ValidationResult) AddError(field string, err error) {
+	*r = append(*r, FieldError{Field: field, Message: err.Error(), Err: err})
+}
+
+// Err returns the result as error, or nil if no field is invalid
+func (r ValidationResult) Err() error {
+	if len(r) == 0 {
+		return nil
+	}
+	return r
+}
+
+// Field returns the first error message for field, or ""
+func (r ValidationResult) Field(field string) string {
+	for _, e := range r {
+		if e.Field == field {
+			return e.Message
+		}
+	}
+	return ""
+}
+
+// Error implements error
+func (r ValidationResult) Error() string {
+	msgs := make([]string, len(r))
+	for i, e := range r {
+		msgs[i] = e.Field + ": " + e.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the underlying errors of the fields
+func (r ValidationResult) Unwrap() []error {
+	var errs []error
+	for _, e := range r {
+		if e.Err != nil {
+			errs = append(errs, e.Err)
+		}
+	}
+	return errs
+}
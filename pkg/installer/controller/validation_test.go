@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+func TestValidationResult(t *testing.T) {
+	var result ValidationResult
+	assert.NoError(t, result.Err())
+
+	cause := errors.New("taken")
+	result.Add("name", "must not be empty")
+	result.AddError("email", cause)
+
+	err := result.Err()
+	require.Error(t, err)
+	assert.Equal(t, "name: must not be empty; email: taken", err.Error())
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "must not be empty", result.Field("name"))
+	assert.Empty(t, result.Field("other"))
+}
+
+func TestValidateFieldsFuncReachesView(t *testing.T) {
+	config := &core.Config{
+		AppName:    "FieldApp",
+		Version:    "1.0.0",
+		InstallDir: filepath.Join(t.TempDir(), "install"),
+		Components: []core.Component{{ID: "core", Name: "Core", Required: true, Selected: true}},
+	}
+	controller := NewInstallerController(config, core.New(config))
+	view := NewMockExtendedInstallerView()
+	controller.SetView(view)
+
+	const stateAccount wizard.State = "account"
+	require.NoError(t, controller.RegisterCustomState(&BaseCustomStateHandler{
+		StateID:     stateAccount,
+		Name:        "Account",
+		InsertPoint: InsertAfterWelcome,
+		CanGoNext:   true,
+		ValidateFieldsFunc: func(_ *InstallerController, data map[string]interface{}) ValidationResult {
+			var result ValidationResult
+			if name, _ := wizard.DataAs[string](data, "username"); name == "" {
+				result.Add("username", "enter a user name")
+			}
+			return result
+		},
+	}))
+
+	require.NoError(t, controller.Start())
+	require.NoError(t, controller.Next()) // Welcome -> Account
+	assert.Error(t, controller.Next())
+	assert.Equal(t, stateAccount, controller.GetCurrentState())
+	assert.Equal(t, ValidationResult{{Field: "username", Message: "enter a user name"}}, view.fieldErrors)
+
+	controller.GetStateData()["username"] = "alice"
+	view.fieldErrors = nil
+	require.NoError(t, controller.Next())
+	assert.Nil(t, view.fieldErrors)
+}
+
+func TestFieldErrorsWithoutFieldErrorsView(t *testing.T) {
+	config := &core.Config{AppName: "FieldApp", InstallDir: filepath.Join(t.TempDir(), "install")}
+	controller := NewInstallerController(config, core.New(config))
+	view := NewMockExtendedInstallerView()
+	controller.SetView(basicView{view})
+	require.NoError(t, controller.RegisterCustomState(&BaseCustomStateHandler{
+		StateID:     "account",
+		InsertPoint: InsertAfterWelcome,
+		CanGoNext:   true,
+		ValidateFieldsFunc: func(_ *InstallerController, data map[string]interface{}) ValidationResult {
+			var result ValidationResult
+			result.Add("username", "enter a user name")
+			return result
+		},
+	}))
+
+	require.NoError(t, controller.Start())
+	require.NoError(t, controller.Next())
+	var result ValidationResult
+	require.ErrorAs(t, controller.Next(), &result, "the view gets the fields through the error")
+	assert.Equal(t, "enter a user name", result.Field("username"))
+	assert.Nil(t, view.fieldErrors)
+}
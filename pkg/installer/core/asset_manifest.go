@@ -0,0 +1,195 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// AssetManifestFile is the signed checksum manifest at the root of an
+// embedded file system, see GenerateAssetManifest
+const AssetManifestFile = "setupkit-assets.json"
+
+// ErrAssetsTampered is returned when embedded assets do not match their
+// signed manifest
+var ErrAssetsTampered = errors.New("embedded assets have been tampered with")
+
+// AssetManifest lists the SHA-256 of every file of an embedded file system,
+// signed with Ed25519
+type AssetManifest struct {
+	Files     map[string]string `json:"files"`     // Hex encoded SHA-256 by slash-separated path
+	Signature string            `json:"signature"` // Base64 Ed25519 signature of the JSON encoded Files
+}
+
+// AssetTamperError lists the files that differ from the asset manifest
+type AssetTamperError struct {
+	Modified []string // Files whose checksum differs
+	Missing  []string // Files in the manifest that are gone
+	Added    []string // Files that are not in the manifest
+}
+
+func (e *AssetTamperError) Error() string {
+	var b strings.Builder
+	b.WriteString(ErrAssetsTampered.Error())
+	for _, name := range e.Modified {
+		fmt.Fprintf(&b, "\n  modified: %s", name)
+	}
+	for _, name := range e.Missing {
+		fmt.Fprintf(&b, "\n  missing: %s", name)
+	}
+	for _, name := range e.Added {
+		fmt.Fprintf(&b, "\n  added: %s", name)
+	}
+	return b.String()
+}
+
+// Unwrap makes errors.Is(err, ErrAssetsTampered) hold
+func (e *AssetTamperError) Unwrap() error {
+	return ErrAssetsTampered
+}
+
+// GenerateAssetManifest walks fsys and returns the manifest of its files,
+// signed with key, for writing to AssetManifestFile before the directory is
+// embedded. An existing AssetManifestFile is left out.
+func GenerateAssetManifest(fsys fs.FS, key ed25519.PrivateKey) ([]byte, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid asset signing key")
+	}
+	files, err := assetChecksums(fsys)
+	if err != nil {
+		return nil, err
+	}
+	signed, _ := json.Marshal(files)
+	manifest := AssetManifest{
+		Files:     files,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, signed)),
+	}
+	return json.MarshalIndent(manifest, "", "  ")
+}
+
+// VerifyAssetManifest checks the signature of the AssetManifestFile in fsys
+// with key, then the files of fsys against it. Errors satisfy
+// errors.Is(err, ErrAssetsTampered); differing files are listed by an
+// *AssetTamperError.
+func VerifyAssetManifest(fsys fs.FS, key ed25519.PublicKey) error {
+	data, err := fs.ReadFile(fsys, AssetManifestFile)
+	if err != nil {
+		return fmt.Errorf("%w: %s cannot be read: %v", ErrAssetsTampered, AssetManifestFile, err)
+	}
+	var manifest AssetManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("%w: invalid %s: %v", ErrAssetsTampered, AssetManifestFile, err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(manifest.Signature)
+	signed, _ := json.Marshal(manifest.Files)
+	if err != nil || len(key) != ed25519.PublicKeySize || !ed25519.Verify(key, signed, signature) {
+		return fmt.Errorf("%w: the signature of %s does not match", ErrAssetsTampered, AssetManifestFile)
+	}
+
+	files, err := assetChecksums(fsys)
+	if err != nil {
+		return err
+	}
+	result := &AssetTamperError{}
+	for name, sum := range files {
+		want, ok := manifest.Files[name]
+		switch {
+		case !ok:
+			result.Added = append(result.Added, name)
+		case want != sum:
+			result.Modified = append(result.Modified, name)
+		}
+	}
+	for name := range manifest.Files {
+		if _, ok := files[name]; !ok {
+			result.Missing = append(result.Missing, name)
+		}
+	}
+	if len(result.Modified) > 0 || len(result.Missing) > 0 || len(result.Added) > 0 {
+		sort.Strings(result.Modified)
+		sort.Strings(result.Missing)
+		sort.Strings(result.Added)
+		return result
+	}
+	return nil
+}
+
+// ParseAssetKey decodes a hex encoded Ed25519 public key, as printed by
+// cmd/gen-manifest
+func ParseAssetKey(s string) (ed25519.PublicKey, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid asset verification key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// assetChecksums returns the SHA-256 of every file in fsys but the manifest
+func assetChecksums(fsys fs.FS) (map[string]string, error) {
+	files := make(map[string]string)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || name == AssetManifestFile {
+			return nil
+		}
+		f, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		sum, err := readerChecksum(f)
+		if err != nil {
+			return err
+		}
+		files[name] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read assets: %w", err)
+	}
+	return files, nil
+}
+
+// assetRoot returns the part of fsys the manifest describes: fsys itself,
+// or its only directory, as embedding a directory with //go:embed assets
+// gives a file system with nothing but "assets" at the root
+func assetRoot(fsys fs.FS) fs.FS {
+	if _, err := fs.Stat(fsys, AssetManifestFile); err == nil {
+		return fsys
+	}
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return fsys
+	}
+	sub, err := fs.Sub(fsys, entries[0].Name())
+	if err != nil {
+		return fsys
+	}
+	return sub
+}
+
+// verifyAssets checks Assets and Source against their signed manifests when
+// AssetKey is set, before anything of them is used
+func (i *Installer) verifyAssets() error {
+	if i.config.AssetKey == nil {
+		return nil
+	}
+	for _, fsys := range []fs.FS{i.config.Assets, i.config.Source} {
+		if fsys == nil {
+			continue
+		}
+		if err := VerifyAssetManifest(assetRoot(fsys), i.config.AssetKey); err != nil {
+			i.context.Logger.Error("Installer assets failed verification, the installer may have been tampered with", "error", err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package core_test
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// signedAssets returns assets with their signed manifest and the key to verify them
+func signedAssets(t *testing.T) (fstest.MapFS, ed25519.PublicKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assets := fstest.MapFS{
+		"app.exe":         {Data: []byte("binary")},
+		"docs/readme.txt": {Data: []byte("read me")},
+	}
+	manifest, err := core.GenerateAssetManifest(assets, private)
+	if err != nil {
+		t.Fatalf("GenerateAssetManifest() error = %v", err)
+	}
+	assets[core.AssetManifestFile] = &fstest.MapFile{Data: manifest}
+	return assets, public
+}
+
+func TestAssetManifest(t *testing.T) {
+	assets, key := signedAssets(t)
+	if err := core.VerifyAssetManifest(assets, key); err != nil {
+		t.Fatalf("VerifyAssetManifest() error = %v", err)
+	}
+
+	assets["app.exe"] = &fstest.MapFile{Data: []byte("patched")}
+	assets["payload.dll"] = &fstest.MapFile{Data: []byte("injected")}
+	delete(assets, "docs/readme.txt")
+	err := core.VerifyAssetManifest(assets, key)
+	var tampered *core.AssetTamperError
+	if !errors.As(err, &tampered) || !errors.Is(err, core.ErrAssetsTampered) {
+		t.Fatalf("VerifyAssetManifest() error = %v, want an AssetTamperError", err)
+	}
+	want := &core.AssetTamperError{
+		Modified: []string{"app.exe"},
+		Missing:  []string{"docs/readme.txt"},
+		Added:    []string{"payload.dll"},
+	}
+	if !reflect.DeepEqual(tampered, want) {
+		t.Errorf("tampered files = %+v, want %+v", tampered, want)
+	}
+}
+
+func TestAssetManifestSignature(t *testing.T) {
+	assets, key := signedAssets(t)
+
+	// A manifest updated to match modified assets needs a new signature
+	assets["app.exe"] = &fstest.MapFile{Data: []byte("patched")}
+	manifest := string(assets[core.AssetManifestFile].Data)
+	manifest = strings.Replace(manifest, `"app.exe": "`, `"app.exe": "0`, 1)
+	assets[core.AssetManifestFile] = &fstest.MapFile{Data: []byte(manifest)}
+	if err := core.VerifyAssetManifest(assets, key); !errors.Is(err, core.ErrAssetsTampered) || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("VerifyAssetManifest() error = %v, want a signature mismatch", err)
+	}
+
+	assets, _ = signedAssets(t)
+	if err := core.VerifyAssetManifest(assets, key); !errors.Is(err, core.ErrAssetsTampered) {
+		t.Errorf("VerifyAssetManifest() error = %v with another key, want a tamper error", err)
+	}
+
+	delete(assets, core.AssetManifestFile)
+	if err := core.VerifyAssetManifest(assets, key); !errors.Is(err, core.ErrAssetsTampered) {
+		t.Errorf("VerifyAssetManifest() error = %v without a manifest, want a tamper error", err)
+	}
+}
+
+func TestTamperedAssetsAbortInstaller(t *testing.T) {
+	useMocks(t)
+	assets, key := signedAssets(t)
+	// As embedded with //go:embed assets
+	embedded := fstest.MapFS{}
+	for name, file := range assets {
+		embedded["assets/"+name] = file
+	}
+	config := &core.Config{
+		AppName:    "SignedApp",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackNone,
+		Assets:     embedded,
+		AssetKey:   key,
+		Components: []core.Component{{ID: "app", Name: "App", Required: true}},
+	}
+	if err := runInstaller(t, config, &runUI{}); err != nil {
+		t.Fatalf("Run() error = %v with intact assets", err)
+	}
+
+	embedded["assets/app.exe"] = &fstest.MapFile{Data: []byte("patched")}
+	config.InstallDir = t.TempDir()
+	if err := runInstaller(t, config, &runUI{}); !errors.Is(err, core.ErrAssetsTampered) {
+		t.Errorf("Run() error = %v, want the installer aborted for tampered assets", err)
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// BundleError lists the payloads an offline bundle lacks
+type BundleError struct {
+	Missing    []string // "component: file" entries that were not found
+	Mismatched []string // "component: file" entries whose checksum differs
+}
+
+func (e *BundleError) Error() string {
+	var b strings.Builder
+	b.WriteString("installation bundle is incomplete")
+	for _, item := range e.Missing {
+		fmt.Fprintf(&b, "\n  missing: %s", item)
+	}
+	for _, item := range e.Mismatched {
+		fmt.Fprintf(&b, "\n  checksum mismatch: %s", item)
+	}
+	return b.String()
+}
+
+// VerifyBundle checks that every file of the components to install is
+// available in Config.Source or Config.BundleDir and matches its checksum, if
+// the component declares one. It returns a *BundleError listing all problems.
+func (i *Installer) VerifyBundle() error {
+	result := &BundleError{}
+	for _, c := range i.getComponentsToInstall() {
+		for _, name := range c.Files {
+			item := c.ID + ": " + name
+			sum, err := i.payloadChecksum(name)
+			if err != nil {
+				result.Missing = append(result.Missing, item)
+				continue
+			}
+			if want, ok := c.Checksums[name]; ok && !strings.EqualFold(want, sum) {
+				result.Mismatched = append(result.Mismatched, item)
+			}
+		}
+	}
+	if len(result.Missing) > 0 || len(result.Mismatched) > 0 {
+		return result
+	}
+	return nil
+}
+
+// payloadChecksum returns the SHA-256 of a payload, looking in the payload
+// source first and in the bundle directory second
+func (i *Installer) payloadChecksum(name string) (string, error) {
+	if source := i.config.PayloadSource(); source != nil {
+		if f, err := source.Open(path.Clean(filepath.ToSlash(name))); err == nil {
+			defer f.Close()
+			return readerChecksum(f)
+		}
+	}
+	if i.config.BundleDir == "" {
+		return "", fs.ErrNotExist
+	}
+	return fileChecksum(filepath.Join(i.config.BundleDir, filepath.FromSlash(name)))
+}
+
+// verifyOfflineBundle runs VerifyBundle when the configuration asks for it
+func (i *Installer) verifyOfflineBundle() error {
+	if !i.config.OfflineBundle {
+		return nil
+	}
+	if err := i.VerifyBundle(); err != nil {
+		return err
+	}
+	i.context.Logger.Info("Installation bundle verified")
+	return nil
+}
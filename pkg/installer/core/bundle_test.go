@@ -0,0 +1,78 @@
+package core_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// bundleConfig returns a config with embedded and side-by-side payloads
+func bundleConfig(t *testing.T) *core.Config {
+	bundleDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(bundleDir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bundleDir, "docs", "manual.pdf"), []byte("manual"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("binary"))
+
+	return &core.Config{
+		AppName:    "BundleApp",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		Rollback:   core.RollbackNone,
+		Assets:     fstest.MapFS{"bin/app": {Data: []byte("binary")}},
+		BundleDir:  bundleDir,
+		Components: []core.Component{
+			{ID: "core", Required: true, Files: []string{"bin/app"},
+				Checksums: map[string]string{"bin/app": hex.EncodeToString(sum[:])}},
+			{ID: "docs", Selected: true, Files: []string{"docs/manual.pdf"}},
+			{ID: "extras", Files: []string{"extras/not-shipped.zip"}}, // Not selected
+		},
+	}
+}
+
+// TestVerifyBundleComplete tests a bundle with all selected payloads present
+func TestVerifyBundleComplete(t *testing.T) {
+	if err := newTestInstaller(bundleConfig(t)).VerifyBundle(); err != nil {
+		t.Errorf("VerifyBundle() error = %v", err)
+	}
+}
+
+// TestVerifyBundleMissingFile tests that installation stops before any change when a payload is missing
+func TestVerifyBundleMissingFile(t *testing.T) {
+	config := bundleConfig(t)
+	config.Components[1].Files = append(config.Components[1].Files, "docs/tutorial.pdf")
+	config.Assets = fstest.MapFS{"bin/app": {Data: []byte("tampered")}}
+	config.OfflineBundle = true
+	installed := false
+	config.Components[0].Installer = func(ctx context.Context) error {
+		installed = true
+		return nil
+	}
+
+	err := newTestInstaller(config).ExecuteInstallation()
+	var bundleErr *core.BundleError
+	if !errors.As(err, &bundleErr) {
+		t.Fatalf("ExecuteInstallation() error = %v, want *BundleError", err)
+	}
+	if len(bundleErr.Missing) != 1 || bundleErr.Missing[0] != "docs: docs/tutorial.pdf" {
+		t.Errorf("Missing = %v, want [docs: docs/tutorial.pdf]", bundleErr.Missing)
+	}
+	if len(bundleErr.Mismatched) != 1 || bundleErr.Mismatched[0] != "core: bin/app" {
+		t.Errorf("Mismatched = %v, want [core: bin/app]", bundleErr.Mismatched)
+	}
+	if installed {
+		t.Error("Component installed despite incomplete bundle")
+	}
+	if _, err := os.Stat(config.InstallDir); !os.IsNotExist(err) {
+		t.Error("Install directory created despite incomplete bundle")
+	}
+}
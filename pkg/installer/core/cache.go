@@ -0,0 +1,180 @@
+package core
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultCacheMaxSize limits the download cache when Config.CacheMaxSize is zero
+const DefaultCacheMaxSize int64 = 1 << 30
+
+// cacheEntriesDir is the subdirectory of Cache.Dir that holds the entries.
+// CacheDir may be shared with other files, so the cache never touches
+// anything outside of it.
+const cacheEntriesDir = "setupkit-payloads"
+
+// Cache stores downloaded payloads under their SHA-256 checksum. Entries are
+// verified when read, and the least recently used ones are evicted once the
+// cache grows beyond MaxSize.
+type Cache struct {
+	Dir     string
+	MaxSize int64
+}
+
+// NewCache returns the download cache of config, or nil if CacheDir is not set
+func NewCache(config *Config) *Cache {
+	if config == nil || config.CacheDir == "" {
+		return nil
+	}
+	maxSize := config.CacheMaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultCacheMaxSize
+	}
+	return &Cache{Dir: config.CacheDir, MaxSize: maxSize}
+}
+
+// entryPath returns where the payload with checksum is stored
+func (c *Cache) entryPath(checksum string) (string, error) {
+	checksum = strings.ToLower(checksum)
+	if b, err := hex.DecodeString(checksum); err != nil || len(b) != 32 {
+		return "", fmt.Errorf("invalid SHA-256 checksum %q", checksum)
+	}
+	return filepath.Join(c.entriesDir(), checksum[:2], checksum), nil
+}
+
+// entriesDir returns the directory owned by the cache
+func (c *Cache) entriesDir() string {
+	return filepath.Join(c.Dir, cacheEntriesDir)
+}
+
+// isEntry reports whether path is an entry written by the cache, that is a
+// file named by its checksum in the directory of its first two digits
+func (c *Cache) isEntry(path string) bool {
+	entry, err := c.entryPath(filepath.Base(path))
+	return err == nil && entry == path
+}
+
+// Get copies the cached payload with checksum to dest and reports whether it
+// was found. An entry whose content no longer matches is removed and reported
+// as a miss.
+func (c *Cache) Get(checksum, dest string) (bool, error) {
+	entry, err := c.entryPath(checksum)
+	if err != nil {
+		return false, err
+	}
+	sum, err := fileChecksum(entry)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil || !strings.EqualFold(sum, checksum) {
+		os.Remove(entry)
+		return false, nil
+	}
+	if err := copyLocalFile(entry, dest); err != nil {
+		return false, err
+	}
+	// Mark as recently used for eviction
+	now := time.Now()
+	os.Chtimes(entry, now, now)
+	return true, nil
+}
+
+// Put stores the file src, whose content has checksum, and evicts old entries
+func (c *Cache) Put(checksum, src string) error {
+	entry, err := c.entryPath(checksum)
+	if err != nil {
+		return err
+	}
+	if err := copyLocalFile(src, entry); err != nil {
+		return fmt.Errorf("failed to cache %s: %w", filepath.Base(src), err)
+	}
+	return c.Evict()
+}
+
+// Evict removes the least recently used entries until the cache fits MaxSize
+func (c *Cache) Evict() error {
+	type cacheEntry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var entries []cacheEntry
+	var total int64
+	err := filepath.WalkDir(c.entriesDir(), func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !c.isEntry(path) {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, cacheEntry{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to scan cache: %w", err)
+	}
+
+	sort.Slice(entries, func(a, b int) bool { return entries[a].modTime.Before(entries[b].modTime) })
+	for _, e := range entries {
+		if total <= c.MaxSize {
+			break
+		}
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= e.size
+	}
+	return nil
+}
+
+// Clear removes all cached payloads. Dir itself and any other files in it
+// are left alone.
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.entriesDir())
+}
+
+// ClearCache removes all payloads from the download cache in Config.CacheDir
+func (i *Installer) ClearCache() error {
+	cache := NewCache(i.config)
+	if cache == nil {
+		return nil
+	}
+	return cache.Clear()
+}
+
+// copyLocalFile copies src to dest through a temporary file, so dest is
+// never left half written
+func copyLocalFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp := dest + ".part"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
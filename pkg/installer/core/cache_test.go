@@ -0,0 +1,167 @@
+package core_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// cacheServer serves payload and counts the requests
+func cacheServer(t *testing.T, payload string) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(payload))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// TestDownloadComponentCache tests a miss followed by a hit
+func TestDownloadComponentCache(t *testing.T) {
+	server, requests := cacheServer(t, "payload")
+	config := &core.Config{CacheDir: t.TempDir()}
+	checksum := sha256Hex("payload")
+
+	for run := 1; run <= 2; run++ {
+		dest := filepath.Join(t.TempDir(), "payload.bin")
+		if err := core.DownloadComponent(context.Background(), config, server.URL, checksum, dest); err != nil {
+			t.Fatalf("run %d: DownloadComponent() error = %v", run, err)
+		}
+		if data, _ := os.ReadFile(dest); string(data) != "payload" {
+			t.Errorf("run %d: dest = %q, want payload", run, data)
+		}
+	}
+	if *requests != 1 {
+		t.Errorf("requests = %d, want 1", *requests)
+	}
+}
+
+// TestDownloadComponentCorruptedCache tests that a damaged entry is replaced by a fresh download
+func TestDownloadComponentCorruptedCache(t *testing.T) {
+	server, requests := cacheServer(t, "payload")
+	config := &core.Config{CacheDir: t.TempDir()}
+	checksum := sha256Hex("payload")
+	entry := filepath.Join(config.CacheDir, "setupkit-payloads", checksum[:2], checksum)
+	if err := os.MkdirAll(filepath.Dir(entry), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(entry, []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "payload.bin")
+	if err := core.DownloadComponent(context.Background(), config, server.URL, checksum, dest); err != nil {
+		t.Fatalf("DownloadComponent() error = %v", err)
+	}
+	if *requests != 1 {
+		t.Errorf("requests = %d, want 1", *requests)
+	}
+	if data, _ := os.ReadFile(entry); string(data) != "payload" {
+		t.Errorf("cache entry = %q, want payload", data)
+	}
+}
+
+// TestDownloadComponentChecksumMismatch tests that a wrong download is rejected and not cached
+func TestDownloadComponentChecksumMismatch(t *testing.T) {
+	server, _ := cacheServer(t, "tampered")
+	config := &core.Config{CacheDir: t.TempDir()}
+	dest := filepath.Join(t.TempDir(), "payload.bin")
+
+	if err := core.DownloadComponent(context.Background(), config, server.URL, sha256Hex("payload"), dest); err == nil {
+		t.Fatal("DownloadComponent() accepted a payload with the wrong checksum")
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("Payload with the wrong checksum left at dest")
+	}
+	if hit, _ := core.NewCache(config).Get(sha256Hex("payload"), dest); hit {
+		t.Error("Payload with the wrong checksum was cached")
+	}
+}
+
+// TestCacheEviction tests that the least recently used entries go first
+func TestCacheEviction(t *testing.T) {
+	cache := &core.Cache{Dir: t.TempDir(), MaxSize: 10}
+	src := t.TempDir()
+	for _, data := range []string{"first", "second"} {
+		path := filepath.Join(src, data)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.Put(sha256Hex(data), path); err != nil {
+			t.Fatal(err)
+		}
+		// Age the entry so the order does not depend on timestamp resolution
+		checksum := sha256Hex(data)
+		old := time.Now().Add(-time.Hour)
+		os.Chtimes(filepath.Join(cache.Dir, "setupkit-payloads", checksum[:2], checksum), old, old)
+	}
+	dest := filepath.Join(t.TempDir(), "out")
+	if hit, _ := cache.Get(sha256Hex("first"), dest); hit {
+		t.Error("Oldest entry was not evicted")
+	}
+	if hit, _ := cache.Get(sha256Hex("second"), dest); !hit {
+		t.Error("Newest entry was evicted")
+	}
+}
+
+// TestCacheEvictionKeepsForeignFiles tests that eviction only counts and
+// removes entries the cache wrote
+func TestCacheEvictionKeepsForeignFiles(t *testing.T) {
+	cache := &core.Cache{Dir: t.TempDir(), MaxSize: 10}
+	foreign := filepath.Join(cache.Dir, "settings.json")
+	if err := os.WriteFile(foreign, []byte("a file larger than the cache"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "payload")
+	if err := os.WriteFile(path, []byte("payload"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Put(sha256Hex("payload"), path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Errorf("Foreign file was evicted: %v", err)
+	}
+	if hit, _ := cache.Get(sha256Hex("payload"), filepath.Join(t.TempDir(), "out")); !hit {
+		t.Error("Entry was evicted for a foreign file")
+	}
+}
+
+// TestClearCache tests removing all cached payloads
+func TestClearCache(t *testing.T) {
+	config := &core.Config{CacheDir: t.TempDir()}
+	foreign := filepath.Join(config.CacheDir, "settings.json")
+	if err := os.WriteFile(foreign, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "payload")
+	if err := os.WriteFile(path, []byte("payload"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.NewCache(config).Put(sha256Hex("payload"), path); err != nil {
+		t.Fatal(err)
+	}
+	if err := newTestInstaller(config).ClearCache(); err != nil {
+		t.Fatalf("ClearCache() error = %v", err)
+	}
+	if hit, _ := core.NewCache(config).Get(sha256Hex("payload"), filepath.Join(t.TempDir(), "out")); hit {
+		t.Error("Payload still cached")
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Errorf("Foreign file in the cache directory was removed: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+// CancelPolicy decides what cancelling a running installation does with the
+// components installed so far, see Config.CancelPolicy
+type CancelPolicy int
+
+const (
+	// CancelRollback rolls back the changes made so far as Rollback says
+	CancelRollback CancelPolicy = iota
+	// CancelKeepForResume keeps the completed components and the checkpoint,
+	// so running the installer again with Resume continues where it stopped
+	CancelKeepForResume
+	// CancelPrompt lets the user choose when cancelling, see
+	// InstallerController.Cancel; without a view to ask it rolls back
+	CancelPrompt
+)
+
+// ErrInstallCancelled is returned by ExecuteInstallation and ExecuteModify
+// when the installation was cancelled while running, see CancelInstallation
+var ErrInstallCancelled = errors.New("installation cancelled")
+
+// CancelInstallation stops the running installation: the component being
+// installed sees its context cancelled and no further component is started.
+// ExecuteInstallation then rolls back the changes made so far, unless
+// Rollback is RollbackNone or CancelPolicy keeps them. It reports whether an
+// installation was running.
+func (i *Installer) CancelInstallation() bool {
+	return i.cancel(i.config.CancelPolicy)
+}
+
+// CancelInstallationKeeping stops the running installation like
+// CancelInstallation, keeping the components installed so far for a later
+// resume if keep is set and rolling them back otherwise, whatever
+// CancelPolicy says. Views answer CancelPrompt with it.
+func (i *Installer) CancelInstallationKeeping(keep bool) bool {
+	if keep {
+		return i.cancel(CancelKeepForResume)
+	}
+	return i.cancel(CancelRollback)
+}
+
+// cancel stops the running installation, handling it by policy
+func (i *Installer) cancel(policy CancelPolicy) bool {
+	i.cancelMu.Lock()
+	defer i.cancelMu.Unlock()
+	if i.cancelInstall == nil {
+		return false
+	}
+	i.cancelPolicy = policy
+	i.cancelInstall()
+	return true
+}
+
+// keepsCancelled reports whether err is a cancel that keeps the components
+// installed so far, with the checkpoint to resume from
+func (i *Installer) keepsCancelled(err error) bool {
+	if !errors.Is(err, ErrInstallCancelled) {
+		return false
+	}
+	i.cancelMu.Lock()
+	defer i.cancelMu.Unlock()
+	return i.cancelPolicy == CancelKeepForResume
+}
+
+// startCancelable derives the context of an installation from the run
+// context, so CancelInstallation can stop it. The returned function ends it.
+func (i *Installer) startCancelable() func() {
+	ctx, cancel := context.WithCancel(i.runContext())
+	i.cancelMu.Lock()
+	i.installCtx, i.cancelInstall = ctx, cancel
+	// Cancelling the run rather than the installation follows the policy as well
+	i.cancelPolicy = i.config.CancelPolicy
+	i.cancelMu.Unlock()
+	return func() {
+		i.cancelMu.Lock()
+		i.installCtx, i.cancelInstall = nil, nil
+		i.cancelMu.Unlock()
+		cancel()
+	}
+}
+
+// checkCancelled returns ErrInstallCancelled once the installation or the
+// run was cancelled
+func (i *Installer) checkCancelled() error {
+	if i.runContext().Err() != nil {
+		return ErrInstallCancelled
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestCancelInstallationRollsBack tests that cancelling a running
+// installation stops it and rolls back the components installed so far
+func TestCancelInstallationRollsBack(t *testing.T) {
+	var calls []string
+	config := &core.Config{
+		AppName:    "CancelApp",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackFull,
+	}
+	inst := newTestInstaller(config)
+	config.Components = []core.Component{
+		{ID: "core", Name: "Core", Required: true,
+			Installer:   func(ctx context.Context) error { calls = append(calls, "install core"); return nil },
+			Uninstaller: func(ctx context.Context) error { calls = append(calls, "uninstall core"); return nil }},
+		{ID: "service", Name: "Service", Required: true,
+			Installer: func(ctx context.Context) error {
+				calls = append(calls, "install service")
+				inst.CancelInstallation()
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			Uninstaller: func(ctx context.Context) error { calls = append(calls, "uninstall service"); return nil }},
+		{ID: "docs", Name: "Docs", Required: true,
+			Installer: func(ctx context.Context) error { calls = append(calls, "install docs"); return nil }},
+	}
+
+	err := inst.ExecuteInstallation()
+	if !errors.Is(err, core.ErrInstallCancelled) {
+		t.Fatalf("ExecuteInstallation() error = %v, want ErrInstallCancelled", err)
+	}
+	var installErr *core.InstallError
+	if !errors.As(err, &installErr) || !installErr.RolledBack {
+		t.Errorf("error = %#v, want a rolled back InstallError", err)
+	}
+	assertCalls(t, "component", calls, []string{"install core", "install service", "uninstall service", "uninstall core"})
+
+	// Nothing is running any more
+	if inst.CancelInstallation() {
+		t.Error("CancelInstallation() = true after the installation ended")
+	}
+}
+
+// TestCancelPolicy tests what cancelling a running installation does with
+// the components installed so far under each policy
+func TestCancelPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy core.CancelPolicy
+		keep   *bool // Answer to CancelPrompt, nil to cancel with CancelInstallation
+		kept   bool
+	}{
+		{"rollback", core.CancelRollback, nil, false},
+		{"keep for resume", core.CancelKeepForResume, nil, true},
+		{"prompt without a view", core.CancelPrompt, nil, false},
+		{"prompt answered keep", core.CancelPrompt, &[]bool{true}[0], true},
+		{"prompt answered roll back", core.CancelPrompt, &[]bool{false}[0], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			config := &core.Config{
+				AppName:      "CancelApp",
+				InstallDir:   t.TempDir(),
+				Rollback:     core.RollbackFull,
+				CancelPolicy: tt.policy,
+			}
+			inst := newTestInstaller(config)
+			component := func(id string, cancel bool) core.Component {
+				return core.Component{ID: id, Name: id, Required: true,
+					Installer: func(ctx context.Context) error {
+						calls = append(calls, "install "+id)
+						if !cancel {
+							return nil
+						}
+						if tt.keep != nil {
+							inst.CancelInstallationKeeping(*tt.keep)
+						} else {
+							inst.CancelInstallation()
+						}
+						<-ctx.Done()
+						return ctx.Err()
+					},
+					Uninstaller: func(ctx context.Context) error { calls = append(calls, "uninstall "+id); return nil }}
+			}
+			config.Components = []core.Component{component("core", false), component("service", true), component("docs", false)}
+
+			err := inst.ExecuteInstallation()
+			if !errors.Is(err, core.ErrInstallCancelled) {
+				t.Fatalf("ExecuteInstallation() error = %v, want ErrInstallCancelled", err)
+			}
+			var installErr *core.InstallError
+			if !errors.As(err, &installErr) || installErr.RolledBack == tt.kept {
+				t.Errorf("error = %#v, want RolledBack %v", err, !tt.kept)
+			}
+
+			checkpoint := core.FindCheckpoint(config.InstallDir)
+			if !tt.kept {
+				assertCalls(t, "component", calls, []string{"install core", "install service", "uninstall service", "uninstall core"})
+				if checkpoint != nil {
+					t.Errorf("checkpoint %+v left after the rollback", checkpoint)
+				}
+				return
+			}
+			assertCalls(t, "component", calls, []string{"install core", "install service"})
+			if checkpoint == nil || !reflect.DeepEqual(checkpoint.Completed, []string{"core"}) {
+				t.Fatalf("checkpoint = %+v, want core completed", checkpoint)
+			}
+
+			// Running again with Resume continues with the cancelled component
+			calls = nil
+			config.Components[1] = component("service", false)
+			config.Resume = true
+			if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+				t.Fatalf("resumed ExecuteInstallation() error = %v", err)
+			}
+			assertCalls(t, "component", calls, []string{"install service", "install docs"})
+		})
+	}
+}
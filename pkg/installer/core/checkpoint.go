@@ -0,0 +1,142 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// InstallCheckpoint records how far an installation got. It is written to the
+// manifest after each component, so an installation that crashed can be
+// resumed with the next incomplete component, see WithResume.
+type InstallCheckpoint struct {
+	Components []string  `json:"components"` // Components of the run, in install order
+	Completed  []string  `json:"completed"`  // Components the run finished
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Remaining returns the components the run did not finish
+func (c *InstallCheckpoint) Remaining() []string {
+	var remaining []string
+	for _, id := range c.Components {
+		if !slices.Contains(c.Completed, id) {
+			remaining = append(remaining, id)
+		}
+	}
+	return remaining
+}
+
+// FindCheckpoint returns the checkpoint of an interrupted installation in
+// installDir, or nil if there is none
+func FindCheckpoint(installDir string) *InstallCheckpoint {
+	manifest, err := LoadManifest(installDir)
+	if err != nil {
+		return nil
+	}
+	return manifest.Checkpoint
+}
+
+// startCheckpoint writes a checkpoint for installing components and returns
+// the components still to install. When resuming, components the interrupted
+// run completed are skipped if their files still match the manifest.
+func (i *Installer) startCheckpoint(components []Component) []Component {
+	manifest, err := LoadManifest(i.config.InstallDir)
+	if err != nil {
+		manifest = &Manifest{
+			InstallDir:  i.config.InstallDir,
+			InstalledAt: time.Now().UTC(),
+		}
+	}
+
+	var completed []string
+	if i.config.Resume && manifest.Checkpoint != nil {
+		var remaining []Component
+		for _, c := range components {
+			entry, ok := manifest.Component(c.ID)
+			if !ok || !slices.Contains(manifest.Checkpoint.Completed, c.ID) {
+				remaining = append(remaining, c)
+				continue
+			}
+			if err := verifyManifestComponent(i.config.InstallDir, entry); err != nil {
+				i.context.Logger.Warn("Reinstalling component", "component", c.ID, "reason", err)
+				remaining = append(remaining, c)
+				continue
+			}
+			i.context.Logger.Info("Skipping component installed before the interruption", "component", c.ID)
+			completed = append(completed, c.ID)
+		}
+		components = remaining
+	}
+
+	ids := make([]string, 0, len(components)+len(completed))
+	ids = append(ids, completed...)
+	for _, c := range components {
+		ids = append(ids, c.ID)
+	}
+	manifest.AppName = i.config.AppName
+	manifest.Version = i.config.Version
+	manifest.Checkpoint = &InstallCheckpoint{Components: ids, Completed: completed}
+	i.checkpoint = manifest
+	i.saveCheckpoint()
+	return components
+}
+
+// recordCheckpoint marks component as completed in the checkpoint
+func (i *Installer) recordCheckpoint(component Component) {
+	if i.checkpoint == nil {
+		return
+	}
+	i.checkpoint.SetComponent(NewManifestComponent(i.config.InstallDir, component))
+	i.checkpoint.Checkpoint.Completed = append(i.checkpoint.Checkpoint.Completed, component.ID)
+	i.saveCheckpoint()
+}
+
+// saveCheckpoint writes the checkpoint; failures only cost the ability to resume
+func (i *Installer) saveCheckpoint() {
+	i.checkpoint.UpdatedAt = time.Now().UTC()
+	i.checkpoint.Checkpoint.UpdatedAt = i.checkpoint.UpdatedAt
+	if err := i.checkpoint.Save(); err != nil {
+		i.context.Logger.Warn("Failed to write install checkpoint", "error", err)
+	}
+}
+
+// discardCheckpoint forgets the components a rollback removed. Components
+// the rollback left in place, such as those resumed from an interrupted run,
+// stay recorded as completed.
+func (i *Installer) discardCheckpoint(removed []string) {
+	if i.checkpoint == nil {
+		return
+	}
+	for _, id := range removed {
+		i.checkpoint.RemoveComponent(id)
+	}
+	i.checkpoint.Checkpoint.Completed = slices.DeleteFunc(i.checkpoint.Checkpoint.Completed, func(id string) bool {
+		return slices.Contains(removed, id)
+	})
+	if len(i.checkpoint.Components) == 0 {
+		// Nothing left from this or an earlier installation
+		os.Remove(ManifestPath(i.config.InstallDir))
+	} else {
+		i.saveCheckpoint()
+	}
+	i.checkpoint = nil
+}
+
+// verifyManifestComponent checks that the files of an installed component are unchanged
+func verifyManifestComponent(installDir string, entry *ManifestComponent) error {
+	for _, f := range entry.Files {
+		sum, err := fileChecksum(filepath.Join(installDir, filepath.FromSlash(f.Path)))
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s is missing", f.Path)
+		}
+		if err != nil {
+			return err
+		}
+		if sum != f.SHA256 {
+			return fmt.Errorf("%s was modified", f.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// checkpointConfig returns four components writing and removing one file each; installed
+// collects the IDs of the components whose installer ran, and crashAt makes
+// that component fail
+func checkpointConfig(t *testing.T, installDir string, installed *[]string, crashAt string) *core.Config {
+	t.Helper()
+	var components []core.Component
+	for _, id := range []string{"one", "two", "three", "four"} {
+		id := id
+		components = append(components, core.Component{
+			ID: id, Name: id, Required: true, Files: []string{id + ".txt"},
+			Installer: func(ctx context.Context) error {
+				if id == crashAt {
+					return errors.New("simulated crash")
+				}
+				*installed = append(*installed, id)
+				return os.WriteFile(filepath.Join(installDir, id+".txt"), []byte(id), 0644)
+			},
+			Uninstaller: func(ctx context.Context) error {
+				if err := os.Remove(filepath.Join(installDir, id+".txt")); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				return nil
+			},
+		})
+	}
+	return &core.Config{
+		AppName:    "ResumeApp",
+		Version:    "1.0.0",
+		InstallDir: installDir,
+		Rollback:   core.RollbackNone,
+		Components: components,
+	}
+}
+
+// TestResumeAfterCrash tests that a resumed run installs only the components the crashed run did not finish
+func TestResumeAfterCrash(t *testing.T) {
+	installDir := t.TempDir()
+	var installed []string
+	if err := newTestInstaller(checkpointConfig(t, installDir, &installed, "three")).ExecuteInstallation(); err == nil {
+		t.Fatal("ExecuteInstallation() succeeded despite the crash")
+	}
+
+	checkpoint := core.FindCheckpoint(installDir)
+	if checkpoint == nil {
+		t.Fatal("no checkpoint after the crash")
+	}
+	if want := []string{"one", "two"}; !reflect.DeepEqual(checkpoint.Completed, want) {
+		t.Errorf("Completed = %q, want %q", checkpoint.Completed, want)
+	}
+	if want := []string{"three", "four"}; !reflect.DeepEqual(checkpoint.Remaining(), want) {
+		t.Errorf("Remaining() = %q, want %q", checkpoint.Remaining(), want)
+	}
+
+	installed = nil
+	config := checkpointConfig(t, installDir, &installed, "")
+	config.Resume = true
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("resumed ExecuteInstallation() error = %v", err)
+	}
+	if want := []string{"three", "four"}; !reflect.DeepEqual(installed, want) {
+		t.Errorf("resumed run installed %q, want %q", installed, want)
+	}
+
+	if core.FindCheckpoint(installDir) != nil {
+		t.Error("checkpoint left after the installation finished")
+	}
+	manifest, err := core.LoadManifest(installDir)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if want := []string{"one", "two", "three", "four"}; !reflect.DeepEqual(manifest.ComponentIDs(), want) {
+		t.Errorf("manifest components = %q, want %q", manifest.ComponentIDs(), want)
+	}
+}
+
+// TestResumeReinstallsModifiedComponents tests that completed components are re-verified before being skipped
+func TestResumeReinstallsModifiedComponents(t *testing.T) {
+	installDir := t.TempDir()
+	var installed []string
+	newTestInstaller(checkpointConfig(t, installDir, &installed, "three")).ExecuteInstallation()
+
+	if err := os.WriteFile(filepath.Join(installDir, "one.txt"), []byte("damaged"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	installed = nil
+	config := checkpointConfig(t, installDir, &installed, "")
+	config.Resume = true
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("resumed ExecuteInstallation() error = %v", err)
+	}
+	if want := []string{"one", "three", "four"}; !reflect.DeepEqual(installed, want) {
+		t.Errorf("resumed run installed %q, want %q", installed, want)
+	}
+}
+
+// TestRunWithoutResumeStartsOver tests that a checkpoint is ignored unless resuming
+func TestRunWithoutResumeStartsOver(t *testing.T) {
+	installDir := t.TempDir()
+	var installed []string
+	newTestInstaller(checkpointConfig(t, installDir, &installed, "three")).ExecuteInstallation()
+
+	installed = nil
+	if err := newTestInstaller(checkpointConfig(t, installDir, &installed, "")).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	if want := []string{"one", "two", "three", "four"}; !reflect.DeepEqual(installed, want) {
+		t.Errorf("installed %q, want %q", installed, want)
+	}
+}
+
+// TestRollbackDiscardsCheckpoint tests that nothing is left to resume after a rollback
+func TestRollbackDiscardsCheckpoint(t *testing.T) {
+	installDir := t.TempDir()
+	var installed []string
+	config := checkpointConfig(t, installDir, &installed, "three")
+	config.Rollback = core.RollbackFull
+	newTestInstaller(config).ExecuteInstallation()
+
+	if core.FindCheckpoint(installDir) != nil {
+		t.Error("checkpoint left after rollback")
+	}
+}
+
+// TestRollbackKeepsResumedComponents tests that a rollback of a resumed run
+// keeps the components of the interrupted run, which it did not remove, in
+// the manifest
+func TestRollbackKeepsResumedComponents(t *testing.T) {
+	installDir := t.TempDir()
+	var installed []string
+	newTestInstaller(checkpointConfig(t, installDir, &installed, "three")).ExecuteInstallation()
+
+	installed = nil
+	config := checkpointConfig(t, installDir, &installed, "four")
+	config.Resume = true
+	config.Rollback = core.RollbackFull
+	if err := newTestInstaller(config).ExecuteInstallation(); err == nil {
+		t.Fatal("resumed ExecuteInstallation() succeeded despite the crash")
+	}
+	if want := []string{"three"}; !reflect.DeepEqual(installed, want) {
+		t.Errorf("resumed run installed %q, want %q", installed, want)
+	}
+
+	manifest, err := core.LoadManifest(installDir)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if want := []string{"one", "two"}; !reflect.DeepEqual(manifest.ComponentIDs(), want) {
+		t.Errorf("manifest components = %q, want the resumed %q", manifest.ComponentIDs(), want)
+	}
+	for _, id := range manifest.ComponentIDs() {
+		if _, err := os.Stat(filepath.Join(installDir, id+".txt")); err != nil {
+			t.Errorf("manifest lists %s, whose file is gone: %v", id, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "three.txt")); !os.IsNotExist(err) {
+		t.Error("the rollback did not remove three")
+	}
+	if checkpoint := manifest.Checkpoint; checkpoint == nil || !reflect.DeepEqual(checkpoint.Completed, []string{"one", "two"}) {
+		t.Errorf("checkpoint = %+v, want one and two completed", checkpoint)
+	}
+}
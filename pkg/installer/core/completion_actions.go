@@ -0,0 +1,208 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// CompletionActionKind is what a CompletionAction does
+type CompletionActionKind string
+
+const (
+	// ActionLaunch starts the program Target, relative to the install directory
+	ActionLaunch CompletionActionKind = "launch"
+	// ActionOpenURL opens Target, such as the documentation, in the browser
+	ActionOpenURL CompletionActionKind = "open-url"
+	// ActionViewLog opens the log file; offered only if Config.LogFile is set
+	ActionViewLog CompletionActionKind = "view-log"
+	// ActionDesktopShortcut creates a desktop shortcut to the program Target
+	ActionDesktopShortcut CompletionActionKind = "desktop-shortcut"
+	// ActionExportDiagnostics saves a diagnostics archive, see ExportDiagnostics
+	ActionExportDiagnostics CompletionActionKind = "export-diagnostics"
+	// ActionReboot restarts the computer. It cannot be configured; it is
+	// offered as RebootActionID when the installation requires a restart.
+	ActionReboot CompletionActionKind = "reboot"
+)
+
+// CompletionAction is a next step the views offer once the installation has
+// finished, as a button or prompt
+type CompletionAction struct {
+	ID     string // Identifies the action in RunCompletionAction
+	Label  string // Button text, such as "Launch MyApp"
+	Kind   CompletionActionKind
+	Target string // Program for ActionLaunch and ActionDesktopShortcut, URL for ActionOpenURL
+}
+
+// CompletionHandler performs a completion action and returns a message for
+// the user, such as where a file was written
+type CompletionHandler func(i *Installer, action CompletionAction) (string, error)
+
+// CompletionActions returns the actions of config that can be offered:
+// ActionViewLog needs a log file, and actions of unknown kind are dropped
+func CompletionActions(config *Config) []CompletionAction {
+	var actions []CompletionAction
+	for _, action := range config.CompletionActions {
+		switch action.Kind {
+		case ActionViewLog:
+			if config.LogFile == "" {
+				continue
+			}
+		case ActionLaunch, ActionOpenURL, ActionDesktopShortcut, ActionExportDiagnostics:
+		default:
+			continue
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// SetCompletionHandler replaces how actions of kind are performed, for
+// example to launch the application with arguments
+func (i *Installer) SetCompletionHandler(kind CompletionActionKind, handler CompletionHandler) {
+	if i.completionHandlers == nil {
+		i.completionHandlers = make(map[CompletionActionKind]CompletionHandler)
+	}
+	i.completionHandlers[kind] = handler
+}
+
+// RunCompletionAction performs the completion action with id and returns a
+// message for the user
+func (i *Installer) RunCompletionAction(id string) (string, error) {
+	actions := CompletionActions(i.config)
+	if i.RebootRequired() {
+		actions = append(actions, rebootAction())
+	}
+	for _, action := range actions {
+		if action.ID != id {
+			continue
+		}
+		return i.completionHandler(action.Kind)(i, action)
+	}
+	return "", fmt.Errorf("unknown completion action %s", id)
+}
+
+// completionHandler returns the handler set for kind, or the default one
+func (i *Installer) completionHandler(kind CompletionActionKind) CompletionHandler {
+	if handler, ok := i.completionHandlers[kind]; ok {
+		return handler
+	}
+	return defaultCompletionHandlers[kind]
+}
+
+var defaultCompletionHandlers = map[CompletionActionKind]CompletionHandler{
+	ActionLaunch:            launchProgram,
+	ActionOpenURL:           openTarget,
+	ActionViewLog:           openTarget,
+	ActionDesktopShortcut:   createDesktopShortcut,
+	ActionExportDiagnostics: exportDiagnostics,
+	ActionReboot:            rebootComputer,
+}
+
+// launchProgram starts the program of action from the install directory
+// without waiting for it
+func launchProgram(i *Installer, action CompletionAction) (string, error) {
+	program := i.programPath(action.Target)
+	cmd := exec.Command(program)
+	cmd.Dir = i.config.InstallDir
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start %s: %w", program, err)
+	}
+	go cmd.Wait()
+	return fmt.Sprintf("Started %s", filepath.Base(program)), nil
+}
+
+// openTarget opens the URL of action, or the log file, with the default
+// application of the desktop
+func openTarget(i *Installer, action CompletionAction) (string, error) {
+	target := action.Target
+	if action.Kind == ActionViewLog {
+		target = i.config.LogFile
+	}
+	if err := OpenWithDefault(target); err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", target, err)
+	}
+	return fmt.Sprintf("Opened %s", target), nil
+}
+
+// exportDiagnostics saves a diagnostics archive to the temporary directory
+func exportDiagnostics(i *Installer, action CompletionAction) (string, error) {
+	path := DiagnosticsPath(i.config)
+	if err := i.ExportDiagnostics(path); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Diagnostics saved to %s", path), nil
+}
+
+// createDesktopShortcut creates a shortcut to the program of action on the
+// desktop of the install scope, see Config.ShortcutDirs, and records it in
+// the manifest so uninstalling removes it
+func createDesktopShortcut(i *Installer, action CompletionAction) (string, error) {
+	_, desktop := i.config.ShortcutDirs()
+	if desktop == "" {
+		return "", errors.New("there is no desktop for installations for all users on this platform")
+	}
+	if err := os.MkdirAll(desktop, 0755); err != nil {
+		return "", fmt.Errorf("failed to create desktop directory: %w", err)
+	}
+	path, err := writeDesktopShortcut(desktop, i.config.AppName, i.programPath(action.Target), i.config.InstallDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to create desktop shortcut: %w", err)
+	}
+	if err := i.recordShortcut(path); err != nil {
+		return "", fmt.Errorf("created shortcut %s, but failed to record it for uninstalling: %w", path, err)
+	}
+	return fmt.Sprintf("Created shortcut %s", path), nil
+}
+
+// writeDesktopShortcut writes a shortcut named name to program into dir: a
+// .lnk file on Windows, a symbolic link on macOS and a .desktop entry elsewhere
+func writeDesktopShortcut(dir, name, program, workDir string) (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		path := filepath.Join(dir, name+".lnk")
+		return path, createWindowsShortcut(path, program, workDir)
+	case "darwin":
+		path := filepath.Join(dir, name)
+		os.Remove(path)
+		return path, os.Symlink(program, path)
+	default:
+		path := filepath.Join(dir, strings.ReplaceAll(strings.ToLower(name), " ", "-")+".desktop")
+		entry := fmt.Sprintf("[Desktop Entry]\nType=Application\nName=%s\nExec=\"%s\"\nPath=%s\nTerminal=false\n", name, program, workDir)
+		if err := os.WriteFile(path, []byte(entry), 0755); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+}
+
+// programPath returns the absolute path of a program given relative to the
+// install directory
+func (i *Installer) programPath(program string) string {
+	if filepath.IsAbs(program) {
+		return program
+	}
+	return filepath.Join(i.config.InstallDir, filepath.FromSlash(program))
+}
+
+// OpenWithDefault opens a URL or file with the default application of the desktop
+func OpenWithDefault(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	case "darwin":
+		cmd = exec.Command("open", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
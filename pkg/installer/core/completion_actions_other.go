@@ -0,0 +1,11 @@
+//go:build !windows
+// +build !windows
+
+package core
+
+import "errors"
+
+// createWindowsShortcut is only available on Windows
+func createWindowsShortcut(path, program, workDir string) error {
+	return errors.New("windows shortcuts are not supported on this platform")
+}
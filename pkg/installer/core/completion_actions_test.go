@@ -0,0 +1,152 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// completionConfig returns a configuration offering every kind of completion action
+func completionConfig(t *testing.T) *core.Config {
+	return &core.Config{
+		AppName:    "Next App",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		CompletionActions: []core.CompletionAction{
+			{ID: "launch", Label: "Launch Next App", Kind: core.ActionLaunch, Target: "bin/run.sh"},
+			{ID: "docs", Label: "Open documentation", Kind: core.ActionOpenURL, Target: "https://example.com/docs"},
+			{ID: "log", Label: "View log", Kind: core.ActionViewLog},
+			{ID: "shortcut", Label: "Create desktop shortcut", Kind: core.ActionDesktopShortcut, Target: "bin/run.sh"},
+			{ID: "diagnostics", Label: "Save diagnostics", Kind: core.ActionExportDiagnostics},
+			{ID: "bogus", Label: "Unknown", Kind: "teleport"},
+		},
+	}
+}
+
+// actionIDs returns the IDs of actions
+func actionIDs(actions []core.CompletionAction) string {
+	var ids []string
+	for _, a := range actions {
+		ids = append(ids, a.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+// TestCompletionActions tests which configured actions are offered
+func TestCompletionActions(t *testing.T) {
+	config := completionConfig(t)
+	if got := actionIDs(core.CompletionActions(config)); got != "launch,docs,shortcut,diagnostics" {
+		t.Errorf("CompletionActions() without log file = %s", got)
+	}
+
+	config.LogFile = filepath.Join(t.TempDir(), "install.log")
+	if got := actionIDs(core.CompletionActions(config)); got != "launch,docs,log,shortcut,diagnostics" {
+		t.Errorf("CompletionActions() with log file = %s", got)
+	}
+	if got := actionIDs(core.New(config).CreateSummary().Actions); got != "launch,docs,log,shortcut,diagnostics" {
+		t.Errorf("InstallSummary.Actions = %s", got)
+	}
+}
+
+// TestRunCompletionActionHandlers tests that actions invoke the handler of their kind
+func TestRunCompletionActionHandlers(t *testing.T) {
+	config := completionConfig(t)
+	config.LogFile = filepath.Join(t.TempDir(), "install.log")
+	inst := core.New(config)
+
+	var invoked []string
+	for _, kind := range []core.CompletionActionKind{core.ActionLaunch, core.ActionOpenURL, core.ActionViewLog} {
+		kind := kind
+		inst.SetCompletionHandler(kind, func(i *core.Installer, action core.CompletionAction) (string, error) {
+			invoked = append(invoked, string(kind)+":"+action.ID+":"+action.Target)
+			return "done " + action.ID, nil
+		})
+	}
+
+	for _, id := range []string{"launch", "docs", "log"} {
+		message, err := inst.RunCompletionAction(id)
+		if err != nil || message != "done "+id {
+			t.Errorf("RunCompletionAction(%s) = %q, %v", id, message, err)
+		}
+	}
+	want := "launch:launch:bin/run.sh open-url:docs:https://example.com/docs view-log:log:"
+	if got := strings.Join(invoked, " "); got != want {
+		t.Errorf("invoked %s, want %s", got, want)
+	}
+
+	if _, err := inst.RunCompletionAction("bogus"); err == nil {
+		t.Error("RunCompletionAction() of an action of unknown kind should fail")
+	}
+	if _, err := inst.RunCompletionAction("missing"); err == nil {
+		t.Error("RunCompletionAction() of an unknown action should fail")
+	}
+}
+
+// TestCompletionActionDefaults tests the default launch, shortcut and diagnostics handlers
+func TestCompletionActionDefaults(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the application")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := completionConfig(t)
+	config.InstallScope = core.ScopePerUser
+	marker := filepath.Join(config.InstallDir, "started")
+	program := filepath.Join(config.InstallDir, "bin", "run.sh")
+	if err := os.MkdirAll(filepath.Dir(program), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(program, []byte("#!/bin/sh\ntouch started\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&core.Manifest{AppName: config.AppName, InstallDir: config.InstallDir}).Save(); err != nil {
+		t.Fatal(err)
+	}
+	inst := core.New(config)
+
+	if _, err := inst.RunCompletionAction("launch"); err != nil {
+		t.Fatalf("launch: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(marker); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("launched program did not run in the install directory")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	message, err := inst.RunCompletionAction("shortcut")
+	if err != nil {
+		t.Fatalf("shortcut: %v", err)
+	}
+	shortcuts, _ := filepath.Glob(filepath.Join(home, "Desktop", "*"))
+	if len(shortcuts) != 1 || !strings.Contains(message, shortcuts[0]) {
+		t.Errorf("shortcut message %q, desktop holds %v", message, shortcuts)
+	}
+	manifest, err := core.LoadManifest(config.InstallDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(manifest.Shortcuts, shortcuts) {
+		t.Errorf("manifest shortcuts = %v, want %v so uninstalling removes them", manifest.Shortcuts, shortcuts)
+	}
+
+	message, err = inst.RunCompletionAction("diagnostics")
+	if err != nil {
+		t.Fatalf("diagnostics: %v", err)
+	}
+	path := strings.TrimPrefix(message, "Diagnostics saved to ")
+	defer os.Remove(path)
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("diagnostics archive %s: %v", path, err)
+	}
+}
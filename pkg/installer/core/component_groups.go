@@ -0,0 +1,135 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultCategory is the group of components without a Category
+const DefaultCategory = "Other"
+
+// ComponentFilterThreshold is the number of components from which the
+// selection screens offer to filter the list
+const ComponentFilterThreshold = 10
+
+// ComponentGroup is a category of components in the selection screens
+type ComponentGroup struct {
+	Category string
+	Indices  []int // Positions of the group's components in the component list
+}
+
+// MatchesFilter reports whether the name or description of the component
+// contains term, ignoring case; an empty term matches every component
+func (c Component) MatchesFilter(term string) bool {
+	term = strings.ToLower(strings.TrimSpace(term))
+	return strings.Contains(strings.ToLower(c.Name), term) ||
+		strings.Contains(strings.ToLower(c.Description), term)
+}
+
+// HasCategories reports whether any component declares a category
+func HasCategories(components []Component) bool {
+	for _, c := range components {
+		if c.Category != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupComponents groups components by category in order of first
+// appearance; uncategorized components form a DefaultCategory group at the end
+func GroupComponents(components []Component) []ComponentGroup {
+	var groups []ComponentGroup
+	position := make(map[string]int)
+	var uncategorized []int
+	for idx, c := range components {
+		if c.Category == "" {
+			uncategorized = append(uncategorized, idx)
+			continue
+		}
+		pos, ok := position[c.Category]
+		if !ok {
+			pos = len(groups)
+			position[c.Category] = pos
+			groups = append(groups, ComponentGroup{Category: c.Category})
+		}
+		groups[pos].Indices = append(groups[pos].Indices, idx)
+	}
+	if len(uncategorized) > 0 {
+		groups = append(groups, ComponentGroup{Category: DefaultCategory, Indices: uncategorized})
+	}
+	return groups
+}
+
+// CategorySelected reports whether all components of a group are selected
+func CategorySelected(components []Component, group ComponentGroup) bool {
+	for _, idx := range group.Indices {
+		if !components[idx].Selected && !components[idx].Required {
+			return false
+		}
+	}
+	return true
+}
+
+// ToggleCategory selects all components of a group or, if all are selected
+// already, deselects them. Required components stay selected, dependencies of
+// selected components are selected as well and a component that another
+// selected component depends on is not deselected.
+func ToggleCategory(components []Component, group ComponentGroup) {
+	selected := !CategorySelected(components, group)
+	for _, idx := range group.Indices {
+		if !components[idx].Required {
+			components[idx].Selected = selected
+		}
+	}
+	selectDependencies(components)
+}
+
+// SelectComponents selects the components with ids and deselects all others,
+// keeping required components and selecting dependencies like ApplyPreset.
+// It fails and leaves components unchanged if an ID names no component or a
+// selected component depends on a component that does not exist or on itself.
+func SelectComponents(components []Component, ids []string) error {
+	wanted := make(map[string]bool, len(ids))
+	for _, c := range components {
+		if c.Required {
+			wanted[c.ID] = true
+		}
+	}
+	for _, id := range ids {
+		if !slices.ContainsFunc(components, func(c Component) bool { return c.ID == id }) {
+			return fmt.Errorf("unknown component %s", id)
+		}
+		wanted[id] = true
+	}
+	if _, err := orderByDependencies(components, wanted); err != nil {
+		return err
+	}
+	ApplyPreset(components, ids)
+	return nil
+}
+
+// selectDependencies marks the transitive dependencies of all selected
+// components as selected; unknown dependencies and cycles are left alone
+func selectDependencies(components []Component) {
+	ids := make(map[string]bool)
+	for _, c := range components {
+		if c.Selected || c.Required {
+			ids[c.ID] = true
+		}
+	}
+	needed, err := orderByDependencies(components, ids)
+	if err != nil {
+		return
+	}
+	wanted := make(map[string]bool, len(needed))
+	for _, c := range needed {
+		wanted[c.ID] = true
+	}
+	for idx := range components {
+		if wanted[components[idx].ID] {
+			components[idx].Selected = true
+		}
+	}
+}
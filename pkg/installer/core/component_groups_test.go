@@ -0,0 +1,108 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func categorizedComponents() []core.Component {
+	return []core.Component{
+		{ID: "app", Category: "Application", Required: true, Selected: true},
+		{ID: "cli", Category: "Tools", Dependencies: []string{"runtime"}},
+		{ID: "readme"},
+		{ID: "profiler", Category: "Tools"},
+		{ID: "runtime", Category: "Application"},
+		{ID: "samples"},
+	}
+}
+
+// TestGroupComponents tests grouping by category with uncategorized components last
+func TestGroupComponents(t *testing.T) {
+	groups := core.GroupComponents(categorizedComponents())
+	want := []core.ComponentGroup{
+		{Category: "Application", Indices: []int{0, 4}},
+		{Category: "Tools", Indices: []int{1, 3}},
+		{Category: core.DefaultCategory, Indices: []int{2, 5}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("GroupComponents() = %+v, want %+v", groups, want)
+	}
+	if core.HasCategories([]core.Component{{ID: "a"}, {ID: "b"}}) {
+		t.Error("HasCategories() = true for uncategorized components")
+	}
+}
+
+// TestToggleCategory tests select-all and deselect-all with required components and dependencies
+func TestToggleCategory(t *testing.T) {
+	components := categorizedComponents()
+	groups := core.GroupComponents(components)
+	application, tools := groups[0], groups[1]
+
+	// Selecting all tools pulls in the runtime they depend on
+	core.ToggleCategory(components, tools)
+	if !components[1].Selected || !components[3].Selected {
+		t.Error("Tools not selected")
+	}
+	if !components[4].Selected {
+		t.Error("Dependency runtime of cli not selected")
+	}
+	if !core.CategorySelected(components, application) {
+		t.Error("Application category should count as fully selected")
+	}
+
+	// Deselecting the application group keeps the required app and the runtime cli needs
+	core.ToggleCategory(components, application)
+	if !components[0].Selected || !components[4].Selected {
+		t.Errorf("Required or needed components deselected: app=%v runtime=%v", components[0].Selected, components[4].Selected)
+	}
+
+	// Deselecting tools leaves the other groups alone
+	core.ToggleCategory(components, tools)
+	if components[1].Selected || components[3].Selected {
+		t.Error("Tools still selected after deselect-all")
+	}
+	if components[2].Selected || components[5].Selected {
+		t.Error("Uncategorized components changed")
+	}
+}
+
+// TestSelectComponents tests selecting by ID with dependencies and rejecting unknown ones
+func TestSelectComponents(t *testing.T) {
+	components := categorizedComponents()
+	if err := core.SelectComponents(components, []string{"cli", "readme"}); err != nil {
+		t.Fatalf("SelectComponents() error = %v", err)
+	}
+	var selected []string
+	for _, c := range components {
+		if c.Selected || c.Required {
+			selected = append(selected, c.ID)
+		}
+	}
+	if want := []string{"app", "cli", "readme", "runtime"}; !reflect.DeepEqual(selected, want) {
+		t.Errorf("selected %v, want %v", selected, want)
+	}
+
+	components = append(components, core.Component{ID: "plugin", Dependencies: []string{"sdk"}})
+	before := append([]core.Component(nil), components...)
+	if err := core.SelectComponents(components, []string{"plugin"}); err == nil {
+		t.Error("SelectComponents() accepted a component with an unknown dependency")
+	}
+	if err := core.SelectComponents(components, []string{"extras"}); err == nil {
+		t.Error("SelectComponents() accepted an unknown component")
+	}
+	if !reflect.DeepEqual(components, before) {
+		t.Error("rejected selection changed the components")
+	}
+}
+
+// TestMatchesFilter tests filtering by name and description, ignoring case
+func TestMatchesFilter(t *testing.T) {
+	c := core.Component{Name: "PostgreSQL Driver", Description: "Connects to the database"}
+	for term, want := range map[string]bool{"": true, "sql": true, " DATABASE ": true, "mysql": false} {
+		if got := c.MatchesFilter(term); got != want {
+			t.Errorf("MatchesFilter(%q) = %v, want %v", term, got, want)
+		}
+	}
+}
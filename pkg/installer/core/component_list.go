@@ -0,0 +1,65 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// ComponentInfo is the machine-readable description of a component
+type ComponentInfo struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description,omitempty"`
+	Size          int64    `json:"size"`
+	InstalledSize int64    `json:"installed_size"`
+	Required      bool     `json:"required"`
+	Selected      bool     `json:"selected"`
+	Dependencies  []string `json:"dependencies"`
+}
+
+// NewComponentInfo describes a component
+func NewComponentInfo(c Component) ComponentInfo {
+	deps := append([]string{}, c.Dependencies...)
+	return ComponentInfo{
+		ID:            c.ID,
+		Name:          c.Name,
+		Description:   c.Description,
+		Size:          c.Size,
+		InstalledSize: c.DiskSize(),
+		Required:      c.Required,
+		Selected:      c.Selected || c.Required,
+		Dependencies:  deps,
+	}
+}
+
+// ListComponents describes all configured components
+func (i *Installer) ListComponents() []ComponentInfo {
+	infos := make([]ComponentInfo, 0, len(i.config.Components))
+	for _, c := range i.config.Components {
+		infos = append(infos, NewComponentInfo(c))
+	}
+	return infos
+}
+
+// WriteComponentList writes components as an aligned text table or as JSON
+func WriteComponentList(w io.Writer, components []ComponentInfo, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(components)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tSIZE\tREQUIRED\tDEPENDS ON\tDESCRIPTION")
+	for _, c := range components {
+		deps := strings.Join(c.Dependencies, ",")
+		if deps == "" {
+			deps = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%t\t%s\t%s\n", c.ID, c.Name, c.Size, c.Required, deps, c.Description)
+	}
+	return tw.Flush()
+}
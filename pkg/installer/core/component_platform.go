@@ -0,0 +1,90 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MatchesPlatform reports whether platforms, entries like "windows" or
+// "linux/arm64", include goos/goarch. An empty list matches every platform.
+func MatchesPlatform(platforms []string, goos, goarch string) bool {
+	if len(platforms) == 0 {
+		return true
+	}
+	for _, p := range platforms {
+		platformOS, arch, hasArch := strings.Cut(p, "/")
+		if platformOS == goos && (!hasArch || arch == goarch) {
+			return true
+		}
+	}
+	return false
+}
+
+// ForPlatform returns the components available on goos/goarch, with the files
+// tagged for other platforms removed. A component is left out if its
+// Platforms exclude goos/goarch, if all its files are for other platforms, or
+// if it depends on a component that is left out.
+func ForPlatform(components []Component, goos, goarch string) []Component {
+	available := make([]Component, 0, len(components))
+	hidden := make(map[string]bool)
+	for _, c := range components {
+		if !MatchesPlatform(c.Platforms, goos, goarch) {
+			hidden[c.ID] = true
+			continue
+		}
+		if len(c.FilePlatforms) > 0 {
+			files := make([]string, 0, len(c.Files))
+			for _, name := range c.Files {
+				if MatchesPlatform(c.FilePlatforms[name], goos, goarch) {
+					files = append(files, name)
+				}
+			}
+			if len(files) == 0 && len(c.Files) > 0 {
+				hidden[c.ID] = true
+				continue
+			}
+			c.Files = files
+		}
+		available = append(available, c)
+	}
+
+	// Hiding a component hides the components depending on it
+	for changed := len(hidden) > 0; changed; {
+		changed = false
+		available = slices.DeleteFunc(available, func(c Component) bool {
+			for _, dep := range c.Dependencies {
+				if hidden[dep] {
+					hidden[c.ID] = true
+					changed = true
+					return true
+				}
+			}
+			return false
+		})
+	}
+	return available
+}
+
+// CheckPlatform reports required components that ForPlatform hides on
+// goos/goarch although their Platforms include it, because all their files
+// are for other platforms or they depend on a hidden component. Such a
+// component is meant to be installed there but would silently be left out.
+func CheckPlatform(components []Component, goos, goarch string) error {
+	visible := make(map[string]bool, len(components))
+	for _, c := range ForPlatform(components, goos, goarch) {
+		visible[c.ID] = true
+	}
+	for _, c := range components {
+		if !c.Required || visible[c.ID] || !MatchesPlatform(c.Platforms, goos, goarch) {
+			continue
+		}
+		for _, dep := range c.Dependencies {
+			if !visible[dep] {
+				return fmt.Errorf("required component %s is not available on %s/%s: it depends on %s, which is not", c.ID, goos, goarch, dep)
+			}
+		}
+		return fmt.Errorf("required component %s is not available on %s/%s: all its files are for other platforms", c.ID, goos, goarch)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// multiPlatformComponents returns a component set with artifacts for several platforms
+func multiPlatformComponents() []core.Component {
+	return []core.Component{
+		{ID: "core", Required: true, Selected: true,
+			Files: []string{"app.exe", "app", "app-arm64", "README.txt"},
+			FilePlatforms: map[string][]string{
+				"app.exe":   {"windows"},
+				"app":       {"linux/amd64", "darwin"},
+				"app-arm64": {"linux/arm64"},
+			}},
+		{ID: "shell-ext", Selected: true, Platforms: []string{"windows/amd64", "windows/arm64"}, Files: []string{"shellext.dll"}},
+		{ID: "shell-ext-docs", Dependencies: []string{"shell-ext"}, Files: []string{"shellext.txt"}},
+		{ID: "launchd", Selected: true, Files: []string{"agent.plist"},
+			FilePlatforms: map[string][]string{"agent.plist": {"darwin"}}},
+		{ID: "plugins", Selected: true},
+	}
+}
+
+func TestMatchesPlatform(t *testing.T) {
+	tests := []struct {
+		platforms    []string
+		goos, goarch string
+		want         bool
+	}{
+		{nil, "linux", "amd64", true},
+		{[]string{"windows"}, "windows", "arm64", true},
+		{[]string{"windows/amd64"}, "windows", "arm64", false},
+		{[]string{"linux/arm64", "darwin"}, "darwin", "amd64", true},
+		{[]string{"linux/arm64", "darwin"}, "linux", "amd64", false},
+	}
+	for _, tt := range tests {
+		if got := core.MatchesPlatform(tt.platforms, tt.goos, tt.goarch); got != tt.want {
+			t.Errorf("MatchesPlatform(%v, %s, %s) = %v, want %v", tt.platforms, tt.goos, tt.goarch, got, tt.want)
+		}
+	}
+}
+
+// TestForPlatform tests which components and files each platform gets
+func TestForPlatform(t *testing.T) {
+	tests := []struct {
+		goos, goarch string
+		components   []string
+		coreFiles    []string
+	}{
+		{"windows", "amd64", []string{"core", "shell-ext", "shell-ext-docs", "plugins"}, []string{"app.exe", "README.txt"}},
+		{"windows", "386", []string{"core", "plugins"}, []string{"app.exe", "README.txt"}},
+		{"linux", "amd64", []string{"core", "plugins"}, []string{"app", "README.txt"}},
+		{"linux", "arm64", []string{"core", "plugins"}, []string{"app-arm64", "README.txt"}},
+		{"darwin", "arm64", []string{"core", "launchd", "plugins"}, []string{"app", "README.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos+"/"+tt.goarch, func(t *testing.T) {
+			components := core.ForPlatform(multiPlatformComponents(), tt.goos, tt.goarch)
+			var ids []string
+			for _, c := range components {
+				ids = append(ids, c.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.components) {
+				t.Errorf("components = %v, want %v", ids, tt.components)
+			}
+			if !reflect.DeepEqual(components[0].Files, tt.coreFiles) {
+				t.Errorf("core files = %v, want %v", components[0].Files, tt.coreFiles)
+			}
+		})
+	}
+}
+
+// TestCheckPlatform tests that required components must not vanish on a platform they are offered on
+func TestCheckPlatform(t *testing.T) {
+	if err := core.CheckPlatform(multiPlatformComponents(), "linux", "arm64"); err != nil {
+		t.Errorf("CheckPlatform() = %v", err)
+	}
+
+	components := []core.Component{
+		{ID: "driver-win", Platforms: []string{"windows"}},
+		{ID: "driver", Required: true, Dependencies: []string{"driver-win"}},
+		{ID: "tray", Required: true, Files: []string{"tray.exe"}, FilePlatforms: map[string][]string{"tray.exe": {"windows"}}},
+		{ID: "service", Required: true, Platforms: []string{"windows"}},
+	}
+	if err := core.CheckPlatform(components, "windows", "amd64"); err != nil {
+		t.Errorf("CheckPlatform(windows) = %v", err)
+	}
+	err := core.CheckPlatform(components, "linux", "amd64")
+	if err == nil || !strings.Contains(err.Error(), "required component driver is not available on linux/amd64: it depends on driver-win") {
+		t.Errorf("CheckPlatform(linux) = %v, want the hidden dependency of driver", err)
+	}
+	err = core.CheckPlatform(components[2:], "linux", "amd64")
+	if err == nil || !strings.Contains(err.Error(), "tray is not available on linux/amd64: all its files are for other platforms") {
+		t.Errorf("CheckPlatform(linux) = %v, want the files of tray", err)
+	}
+	if err := core.CheckPlatform(components[3:], "linux", "amd64"); err != nil {
+		t.Errorf("a required component for other platforms only should be hidden, got %v", err)
+	}
+}
+
+// TestValidateComponentsHiddenRequired tests that a required component whose
+// only dependency is hidden on this OS is rejected, even without StrictComponents
+func TestValidateComponentsHiddenRequired(t *testing.T) {
+	otherOS := "windows"
+	if runtime.GOOS == otherOS {
+		otherOS = "linux"
+	}
+	config := &core.Config{Components: []core.Component{
+		{ID: "backend", Platforms: []string{otherOS}},
+		{ID: "app", Required: true, Selected: true, Dependencies: []string{"backend"}},
+	}}
+	if _, err := core.ValidateComponents(config); err == nil || !strings.Contains(err.Error(), "required component app") {
+		t.Errorf("ValidateComponents() = %v, want an error for app", err)
+	}
+
+	config.Components[0].Platforms = append(config.Components[0].Platforms, runtime.GOOS)
+	if _, err := core.ValidateComponents(config); err != nil || len(config.Components) != 2 {
+		t.Errorf("ValidateComponents() = %v with %d components, want both kept", err, len(config.Components))
+	}
+}
+
+// TestInstallForPlatform tests that only the artifacts of the platform are copied
+func TestInstallForPlatform(t *testing.T) {
+	source := fstest.MapFS{}
+	for _, name := range []string{"app.exe", "app", "app-arm64", "README.txt", "shellext.dll", "shellext.txt", "agent.plist"} {
+		source[name] = &fstest.MapFile{Data: []byte(name)}
+	}
+	config := &core.Config{
+		AppName:    "PlatformApp",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		Rollback:   core.RollbackNone,
+		Source:     source,
+		Components: core.ForPlatform(multiPlatformComponents(), "linux", "arm64"),
+	}
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(config.InstallDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var installed []string
+	for _, e := range entries {
+		if e.Name() != core.ManifestFileName {
+			installed = append(installed, e.Name())
+		}
+	}
+	if want := []string{"README.txt", "app-arm64"}; !reflect.DeepEqual(installed, want) {
+		t.Errorf("installed %v, want %v", installed, want)
+	}
+}
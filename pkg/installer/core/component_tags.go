@@ -0,0 +1,90 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TagPrefix marks a preset entry that names a tag instead of a component ID,
+// such as "tag:recommended", see ExpandPreset
+const TagPrefix = "tag:"
+
+// HasTag reports whether the component carries tag
+func (c Component) HasTag(tag string) bool {
+	return slices.Contains(c.Tags, tag)
+}
+
+// TaggedIDs returns the IDs of the components carrying tag, in component order
+func TaggedIDs(components []Component, tag string) []string {
+	var ids []string
+	for _, c := range components {
+		if c.HasTag(tag) {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
+
+// ExpandPreset resolves the entries of a preset to component IDs: entries
+// with TagPrefix stand for all components carrying the tag, others are IDs
+func ExpandPreset(components []Component, entries []string) []string {
+	var ids []string
+	for _, entry := range entries {
+		if tag, ok := strings.CutPrefix(entry, TagPrefix); ok {
+			ids = append(ids, TaggedIDs(components, tag)...)
+		} else {
+			ids = append(ids, entry)
+		}
+	}
+	return ids
+}
+
+// SelectByTag selects the components carrying tag along with their
+// dependencies and returns how many carry it
+func SelectByTag(components []Component, tag string) int {
+	tagged := 0
+	for idx := range components {
+		if components[idx].HasTag(tag) {
+			components[idx].Selected = true
+			tagged++
+		}
+	}
+	selectDependencies(components)
+	return tagged
+}
+
+// DeselectByTag deselects the components carrying tag and returns how many
+// carry it. Required components stay selected, and so does a component that
+// another selected component depends on, like ToggleCategory does.
+func DeselectByTag(components []Component, tag string) int {
+	tagged := 0
+	for idx := range components {
+		if components[idx].HasTag(tag) {
+			if !components[idx].Required {
+				components[idx].Selected = false
+			}
+			tagged++
+		}
+	}
+	selectDependencies(components)
+	return tagged
+}
+
+// SelectByTag selects the components carrying tag, see the function of the
+// same name. It fails if no component carries the tag.
+func (i *Installer) SelectByTag(tag string) error {
+	if SelectByTag(i.config.Components, tag) == 0 {
+		return fmt.Errorf("no component is tagged %q", tag)
+	}
+	return nil
+}
+
+// DeselectByTag deselects the components carrying tag, see the function of
+// the same name. It fails if no component carries the tag.
+func (i *Installer) DeselectByTag(tag string) error {
+	if DeselectByTag(i.config.Components, tag) == 0 {
+		return fmt.Errorf("no component is tagged %q", tag)
+	}
+	return nil
+}
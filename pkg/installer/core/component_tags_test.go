@@ -0,0 +1,100 @@
+package core_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// taggedComponents returns components tagged "recommended" and "dev", with a
+// required component and a dependency
+func taggedComponents() []core.Component {
+	return []core.Component{
+		{ID: "core", Name: "Core", Required: true, Tags: []string{"recommended", "dev"}},
+		{ID: "app", Name: "Application", Tags: []string{"recommended"}},
+		{ID: "runtime", Name: "Runtime"},
+		{ID: "debugger", Name: "Debugger", Tags: []string{"dev"}, Dependencies: []string{"runtime"}},
+		{ID: "profiler", Name: "Profiler", Tags: []string{"dev"}, Selected: true},
+		{ID: "samples", Name: "Samples", Selected: true, Dependencies: []string{"profiler"}},
+	}
+}
+
+// selection returns the IDs of the selected and required components
+func selection(components []core.Component) string {
+	var ids []string
+	for _, c := range components {
+		if c.Selected || c.Required {
+			ids = append(ids, c.ID)
+		}
+	}
+	return strings.Join(ids, ",")
+}
+
+// TestSelectByTag tests that selecting a tag selects its components with their dependencies
+func TestSelectByTag(t *testing.T) {
+	components := taggedComponents()
+	if n := core.SelectByTag(components, "dev"); n != 3 {
+		t.Errorf("SelectByTag() = %d, want 3 tagged components", n)
+	}
+	if got, want := selection(components), "core,runtime,debugger,profiler,samples"; got != want {
+		t.Errorf("selection = %s, want %s", got, want)
+	}
+}
+
+// TestDeselectByTag tests that deselecting a tag keeps required components
+// and those a selected component depends on
+func TestDeselectByTag(t *testing.T) {
+	components := taggedComponents()
+	core.SelectByTag(components, "recommended")
+	core.DeselectByTag(components, "dev")
+	// core is required and samples still needs the profiler
+	if got, want := selection(components), "core,app,profiler,samples"; got != want {
+		t.Errorf("selection = %s, want %s", got, want)
+	}
+}
+
+// TestPresetByTag tests a preset defined by tags instead of component IDs
+func TestPresetByTag(t *testing.T) {
+	presets := map[string][]string{
+		"Recommended": {"tag:recommended"},
+		"Developer":   {"tag:recommended", "tag:dev"},
+	}
+	if got, want := core.ExpandPreset(taggedComponents(), presets["Recommended"]), []string{"core", "app"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandPreset() = %v, want %v", got, want)
+	}
+
+	components := taggedComponents()
+	core.ApplyPreset(components, presets["Developer"])
+	if got, want := selection(components), "core,app,runtime,debugger,profiler"; got != want {
+		t.Errorf("Developer selection = %s, want %s", got, want)
+	}
+	if got := core.MatchingPreset(components, presets); got != "Developer" {
+		t.Errorf("MatchingPreset() = %s, want Developer", got)
+	}
+}
+
+// TestInstallerSelectByTag tests the installer methods and a tag no component carries
+func TestInstallerSelectByTag(t *testing.T) {
+	config := &core.Config{AppName: "TagApp", Components: taggedComponents()}
+	inst := newTestInstaller(config)
+	if err := inst.SelectByTag("recommended"); err != nil {
+		t.Fatalf("SelectByTag() error = %v", err)
+	}
+	if err := inst.DeselectByTag("dev"); err != nil {
+		t.Fatalf("DeselectByTag() error = %v", err)
+	}
+	if got, want := selection(config.Components), "core,app,profiler,samples"; got != want {
+		t.Errorf("selection = %s, want %s", got, want)
+	}
+	if err := inst.SelectByTag("enterprise"); err == nil {
+		t.Error("SelectByTag() of an unused tag should fail")
+	}
+
+	config.SelectionPresets = map[string][]string{"Enterprise": {"tag:enterprise"}}
+	warnings, err := core.ValidateComponents(config)
+	if err != nil || len(warnings) != 1 || !strings.Contains(warnings[0], "tag enterprise, which no component carries") {
+		t.Errorf("ValidateComponents() = %q, %v, want a warning about the unused tag", warnings, err)
+	}
+}
@@ -3,6 +3,7 @@ package core
 
 import (
 	"context"
+	"crypto/ed25519"
 	"io/fs"
 	"time"
 
@@ -55,20 +56,51 @@ type Component struct {
 	ID          string
 	Name        string
 	Description string
+	Category    string // Group shown in the selection screens; empty means DefaultCategory
+	Tags        []string // Labels such as "recommended" to select by, see SelectByTag and TagPrefix
 	Required    bool
-	Size        int64
+	Recommended bool // Selected by DefaultSelectionRecommendedOnly
+	Size        int64 // Payload size, what is downloaded or unpacked
+	Staged      bool  // The Installer downloads or unpacks the payload in Config.TempDir, e.g. with DownloadComponent; Files are copied in place
+	InstalledSize int64 // Size on disk after installation; Size when zero, see DiskSize
 	Selected    bool
 	Files       []string // List of files belonging to this component
+	License     string   // Additional license that must be accepted when selected
+	Dependencies []string // IDs of components this component requires
+	Order       int // Lower installs first, after the dependencies; see InstallOrder
+	Checksums   map[string]string // Expected SHA-256 (hex) per file, checked by VerifyBundle
+	Services    []string // Names of system services the component installs; removed on uninstall
+	Platforms   []string // Platforms such as "windows" or "linux/arm64" the component is offered on; empty means all
+	FilePlatforms map[string][]string // Platforms per file in Files; files without an entry are installed on all
+	RebootRequired bool // Installing the component needs a restart, such as a driver; see RequireReboot
 	Validator   func() error
+	// Precondition checks what the component needs before it is installed,
+	// such as another application. If it fails, an optional component is
+	// skipped with the error as the reason, see Installer.SkippedComponents,
+	// and a required one stops the installation.
+	Precondition func(ctx context.Context) error
 	Installer   func(ctx context.Context) error
 	Uninstaller func(ctx context.Context) error
+	Retry       *RetryPolicy // Repeats a failing installation of the component's files, once if nil
+
+	// PostInstall runs after the component's files are in place, e.g. to
+	// register a DLL or build an index. A failure fails the component.
+	PostInstall func(ctx context.Context, installDir string) error
+	// PostInstallRetry repeats a failing PostInstall, such as a service
+	// install while the service manager is busy, see TransientRetryPolicy
+	PostInstallRetry *RetryPolicy
+	// PostUninstall undoes PostInstall; it runs before the component's files
+	// are removed, on uninstall and on rollback
+	PostUninstall func(ctx context.Context, installDir string) error
 }
 
 // PathConfiguration holds PATH-related configuration
 type PathConfiguration struct {
-	Enabled bool
-	System  bool
-	Dirs    []string
+	Enabled  bool
+	System   bool // Change the system PATH instead of the user PATH
+	Dirs     []string
+	AskScope bool // Let the user choose between the user and the system PATH
+	EnvFile  bool // Unix: also write EnvFilePath, which puts Dirs in PATH of the current shell when sourced
 }
 
 // Config holds the installer configuration
@@ -81,17 +113,46 @@ type Config struct {
 	
 	// Installation
 	Mode             Mode
+	DisableUIFallback bool // Fail instead of falling back to the CLI when the GUI or browser UI cannot start
 	InstallDir       string
+	InstallScope     InstallScope // Per-user or per-machine; drives the default InstallDir
+	AskInstallScope  bool // Let the user choose between all users and just them, see InstallScopeChoice
+	UsePublisherInPath bool // Put the default InstallDir in a folder named after the Publisher
 	Components       []Component
+	StrictComponents bool // Reject contradictory component definitions instead of correcting them
+	DefaultSelection DefaultSelection // Which optional components start selected, see ApplyDefaultSelection
+	Features         map[string]bool // Switches such as "telemetry" passed to component installers, see FeatureEnabled
+	SelectionPresets map[string][]string // Preset name -> component IDs or "tag:" entries, offered as quick selections on the component screen
 	RequiredSpace    int64 // Required disk space in bytes
+	SpaceCheckInterval time.Duration // How often free space is checked again while installing, before components and large files; DefaultSpaceCheckInterval if zero, never if negative
+	SystemRequirements SystemRequirements // Memory and processors, checked before installing
 	
 	// Resources
 	Assets       fs.FS
+	Source       fs.FS  // Provides Component.Files; see EmbedSource, DirSource and OpenZipSource
+	BundleDir    string // Directory with payloads shipped alongside the installer, searched after Source
+	AssetKey     ed25519.PublicKey // Verify Assets and Source against their signed AssetManifestFile at startup, see GenerateAssetManifest
+	OfflineBundle bool  // Verify that all payloads are present with VerifyBundle before installing
 	License      string
+	RequireLicenseScroll bool // Accepting a license needs it read to the end: scrolled in the SSR and GUI pages, paged through in the CLI
+	ReleaseNotes []ReleaseNote // Shown when an older installation is updated, see UpgradeNotes
 	Icon         []byte
+	CLIBanner    string // Text or ASCII art shown on the CLI welcome screen
+	CLILogo      string // Image file drawn on the CLI welcome screen in truecolor terminals
+	Logo         *Logo  // Shown in the GUI and SSR page headers; PNG logos are also drawn by the CLI
+	
+	// Localization, see Localizer
+	Locale       string             // Locale of the welcome screen and button labels; setting it skips the language selection
+	Locales      []string           // Locales offered for selection, e.g. "en", "de"; see AvailableLocales
+	Catalogs     map[string]Catalog // Messages by locale, added to and overriding the built-in English and German ones
 	
 	// UI Configuration
 	UIConfig     *config.UIConfig
+	WindowWidth  int  // Initial size of the GUI window in pixels; 800x600 if zero
+	WindowHeight int
+	FixedSize    bool // Keep the user from resizing the GUI window
+	MinWidth     int  // Smallest size the user can resize the GUI window to, unless FixedSize
+	MinHeight    int
 	Theme        themes.Theme
 	ConfigFile   string
 	
@@ -99,16 +160,42 @@ type Config struct {
 	WizardProvider   string            // Name of the wizard provider to use
 	WizardOptions    map[string]interface{} // Options for the wizard provider
 	EnableThemeSelection bool          // Enable theme selection in wizard
+	ForwardOnly      bool              // No Back button and no state history, e.g. for kiosk and silent flows
 	
 	// Behavior
 	Rollback     RollbackStrategy
+	TreatPartialFailuresAsError bool // Fail and roll back the installation when PATH, registry or shortcut steps fail, see PartialFailures
+	AllowInstallCancel bool // Let the user cancel while installing, rolling back as Rollback says, see Installer.CancelInstallation
+	CancelPolicy CancelPolicy // Whether cancelling while installing rolls back, keeps the components for a resume or asks
 	DryRun       bool
 	Force        bool
+	Resume       bool // Continue an interrupted installation, see FindCheckpoint
+	Modify       bool // Change the components of the installation in InstallDir, see Installer.LoadExistingInstall
+	AllowOverwriteNonEmpty bool // Install into a directory holding other files without asking
+	CreateRestorePoint bool // Create a System Restore point before installing (Windows only)
+	Portable     bool // Change nothing outside InstallDir: no PATH, registry, shortcuts or services, see PortableConfigFile
+	PreserveOnUninstall []string // Glob patterns relative to InstallDir that uninstall keeps, e.g. "data/*"
+	CompletionActions []CompletionAction // Next steps offered when the installation has finished
 	
 	// Unattended
 	Unattended   bool
 	AcceptLicense bool
 	ResponseFile  string
+	Profile       string // Installation profile chosen by the user; interpreted by the application
+	Provenance    map[string]string // Setting key -> source of its value, see ApplySettingsLayers
+	
+	// Network; empty proxy settings fall back to HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	HTTPProxy  string // Proxy URL for http requests, may include user:password
+	HTTPSProxy string // Proxy URL for https requests, may include user:password
+	NoProxy    string // Comma-separated hosts, domains and CIDR ranges reached directly
+	CacheDir     string // Download cache keyed by checksum, see DownloadComponent; empty disables caching
+	CacheMaxSize int64  // Cache size limit in bytes, DefaultCacheMaxSize if zero
+	TempDir      string // Staging for downloads and archives, the system temp directory if empty
+	
+	// Telemetry
+	EnableTelemetry     bool          // Opt-in; nothing is recorded unless set, usually after asking the user
+	TelemetrySink       TelemetrySink // Receives lifecycle events when EnableTelemetry is set
+	TelemetryAllowedPII []string      // Property names such as "install_dir" that may be sent anyway
 	
 	// Logging
 	LogFile      string
@@ -124,10 +211,19 @@ type Config struct {
 	// Platform specific
 	Platform     PlatformConfig
 	
+	// Scripts
+	PreInstallScript  *Script // Run before installation; a nonzero exit aborts
+	PostInstallScript *Script // Run after a successful installation in InstallDir
+	
 	// Installation callbacks
 	BeforeInstall func() error                              // Called before installation starts
 	OnProgress    func(progress float64, message string)    // Called during installation progress
 	AfterInstall  func() error                              // Called after installation completes
+	OnPhase       func(event PhaseEvent)                    // Called when a phase of the installation starts or ends
+
+	// ConfirmWebView2Install asks whether to install the WebView2 runtime the
+	// native GUI needs; nil asks with a message box on Windows
+	ConfirmWebView2Install func() bool
 }
 
 // PlatformConfig holds platform-specific configuration
@@ -143,8 +239,9 @@ type Context struct {
 	Progress     ProgressReporter
 	StartTime    time.Time
 	Checkpoints  []Checkpoint
-	Metadata     map[string]interface{}
+	Metadata     map[string]interface{} // Custom values, see Session for the typed ones
 	UI           UI
+	Session      *InstallSession // Set by Installer.Run
 }
 
 // Checkpoint represents a rollback point
@@ -197,6 +294,7 @@ type Progress struct {
 	OverallProgress   float64
 	Message          string
 	IsError          bool
+	Phase            Phase // Of the installation, see PhaseStatus
 }
 
 // InstallSummary contains the installation summary
@@ -207,4 +305,12 @@ type InstallSummary struct {
 	InstallPath      string
 	Warnings         []string
 	NextSteps        []string
+	Actions          []CompletionAction // Offered by the completion screen, see RunCompletionAction
+	LicenseAcceptance *LicenseAcceptance // Audit record of the license acceptance, if any
+	UpToDate         bool // An identical installation was in place, nothing was installed
+	RebootRequired   bool // The installation takes full effect after a restart, see Installer.RequireReboot
+	RebootReasons    []string // Why the restart is needed, such as "Driver was installed"
+	PartialFailures  PartialFailures // Steps that failed without stopping the installation, also listed in Warnings
+	Features         map[string]bool // The feature flags the components were installed with, see Config.Features
+	ComponentsSkipped []SkippedComponent // Optional components whose Precondition failed, also listed in Warnings
 }
@@ -0,0 +1,46 @@
+package core_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestRunLogsConfigWarnings tests that Run logs the configuration warnings through the logger
+func TestRunLogsConfigWarnings(t *testing.T) {
+	config := &core.Config{
+		AppName:    "WarnApp",
+		Version:    "1.0.0",
+		InstallDir: t.TempDir(),
+		LogFile:    filepath.Join(t.TempDir(), "install.log"),
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Installer: func(context.Context) error { return nil }},
+		},
+	}
+	warnings, err := core.ValidateComponents(config)
+	if err != nil || len(warnings) != 1 {
+		t.Fatalf("ValidateComponents() = %v, %v, want one warning", warnings, err)
+	}
+	ui := &runUI{}
+	core.RegisterUIFactory(func(core.Mode) (core.UI, error) { return ui, nil })
+	t.Cleanup(func() { core.RegisterUIFactory(nil) })
+
+	inst := core.New(config)
+	inst.AddConfigWarnings(warnings)
+	if err := inst.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	log, err := os.ReadFile(config.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), warnings[0]) {
+		t.Errorf("log lacks the warning %q:\n%s", warnings[0], log)
+	}
+}
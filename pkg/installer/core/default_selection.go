@@ -0,0 +1,53 @@
+package core
+
+// DefaultSelection decides which optional components are selected when the
+// installer starts, instead of the Selected value of each component
+type DefaultSelection int
+
+const (
+	// DefaultSelectionDeclared keeps the Selected value of each component
+	DefaultSelectionDeclared DefaultSelection = iota
+	// DefaultSelectionAllOptional selects all optional components
+	DefaultSelectionAllOptional
+	// DefaultSelectionNoneOptional selects no optional component
+	DefaultSelectionNoneOptional
+	// DefaultSelectionRecommendedOnly selects the optional components that
+	// are Recommended
+	DefaultSelectionRecommendedOnly
+)
+
+// String returns the name of the strategy
+func (s DefaultSelection) String() string {
+	switch s {
+	case DefaultSelectionAllOptional:
+		return "all optional"
+	case DefaultSelectionNoneOptional:
+		return "none optional"
+	case DefaultSelectionRecommendedOnly:
+		return "recommended only"
+	}
+	return "declared"
+}
+
+// ApplyDefaultSelection selects the optional components as strategy says.
+// Required components stay selected and dependencies of selected components
+// are selected as well, like ApplyPreset.
+func ApplyDefaultSelection(components []Component, strategy DefaultSelection) {
+	if strategy == DefaultSelectionDeclared {
+		return
+	}
+	for idx := range components {
+		c := &components[idx]
+		switch {
+		case c.Required:
+			c.Selected = true
+		case strategy == DefaultSelectionAllOptional:
+			c.Selected = true
+		case strategy == DefaultSelectionNoneOptional:
+			c.Selected = false
+		case strategy == DefaultSelectionRecommendedOnly:
+			c.Selected = c.Recommended
+		}
+	}
+	selectDependencies(components)
+}
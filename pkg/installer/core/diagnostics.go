@@ -0,0 +1,202 @@
+package core
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Entries of a diagnostics archive, see ExportDiagnostics
+const (
+	DiagnosticsLog      = "install.log"
+	DiagnosticsManifest = "manifest.json"
+	DiagnosticsConfig   = "config.json"
+	DiagnosticsSystem   = "system.json"
+	DiagnosticsHistory  = "history.txt"
+)
+
+// DiagnosticsPath returns a new file name in the temporary directory for the
+// diagnostics archive of config's application
+func DiagnosticsPath(config *Config) string {
+	name := fmt.Sprintf("%s-diagnostics-%s.zip", config.AppName, time.Now().Format("20060102-150405"))
+	return filepath.Join(os.TempDir(), strings.ReplaceAll(name, " ", "-"))
+}
+
+// SetStateHistory sets the function returning the wizard states visited so
+// far, which ExportDiagnostics includes in the archive
+func (i *Installer) SetStateHistory(history func() []string) {
+	i.stateHistory = history
+}
+
+// ExportDiagnostics writes a zip archive for reporting an installation problem
+// to path. It holds the log file, the manifest of the running or existing
+// installation, the configuration with secrets redacted, information about
+// the system and the wizard states visited. Entries without data, such as the
+// manifest before anything was installed, are left out.
+func (i *Installer) ExportDiagnostics(path string) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create diagnostics archive: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write diagnostics archive: %w", closeErr)
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	archive := zip.NewWriter(file)
+	if i.config.LogFile != "" {
+		if log, err := os.ReadFile(i.config.LogFile); err == nil {
+			if err := writeDiagnosticsEntry(archive, DiagnosticsLog, log); err != nil {
+				return err
+			}
+		}
+	}
+	if manifest := i.diagnosticsManifest(); manifest != nil {
+		if err := writeDiagnosticsJSON(archive, DiagnosticsManifest, manifest); err != nil {
+			return err
+		}
+	}
+	if err := writeDiagnosticsJSON(archive, DiagnosticsConfig, redactedConfig(i.config)); err != nil {
+		return err
+	}
+	if err := writeDiagnosticsJSON(archive, DiagnosticsSystem, systemInfo(i.config.InstallDir)); err != nil {
+		return err
+	}
+	if i.stateHistory != nil {
+		history := strings.Join(i.stateHistory(), "\n") + "\n"
+		if err := writeDiagnosticsEntry(archive, DiagnosticsHistory, []byte(history)); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write diagnostics archive: %w", err)
+	}
+	return nil
+}
+
+// diagnosticsManifest returns the manifest of the running installation, which
+// may be partial, or else of an existing one, nil if there is none
+func (i *Installer) diagnosticsManifest() *Manifest {
+	if i.checkpoint != nil {
+		return i.checkpoint
+	}
+	if i.config.InstallDir == "" {
+		return nil
+	}
+	manifest, err := LoadManifest(i.config.InstallDir)
+	if err != nil {
+		return nil
+	}
+	return manifest
+}
+
+// redactedConfig returns the settings of config that help diagnose a problem,
+// with secrets such as proxy passwords replaced
+func redactedConfig(config *Config) map[string]interface{} {
+	components := make([]map[string]interface{}, 0, len(config.Components))
+	for _, c := range config.Components {
+		components = append(components, map[string]interface{}{
+			"id":       c.ID,
+			"name":     c.Name,
+			"required": c.Required,
+			"selected": c.Selected,
+		})
+	}
+
+	options := make(map[string]interface{}, len(config.WizardOptions))
+	for key, value := range config.WizardOptions {
+		if isSecretKey(key) {
+			value = redacted
+		}
+		options[key] = value
+	}
+
+	return map[string]interface{}{
+		"app_name":        config.AppName,
+		"version":         config.Version,
+		"publisher":       config.Publisher,
+		"mode":            config.Mode,
+		"install_dir":     config.InstallDir,
+		"install_scope":   config.InstallScope.String(),
+		"components":      components,
+		"required_space":  config.RequiredSpace,
+		"rollback":        config.Rollback,
+		"dry_run":         config.DryRun,
+		"force":           config.Force,
+		"resume":          config.Resume,
+		"unattended":      config.Unattended,
+		"profile":         config.Profile,
+		"provenance":      config.Provenance,
+		"http_proxy":      redactURL(config.HTTPProxy),
+		"https_proxy":     redactURL(config.HTTPSProxy),
+		"no_proxy":        config.NoProxy,
+		"cache_dir":       config.CacheDir,
+		"log_file":        config.LogFile,
+		"log_level":       config.LogLevel,
+		"wizard_provider": config.WizardProvider,
+		"wizard_options":  options,
+	}
+}
+
+// redactURL replaces the password in a URL such as a proxy setting
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		// Cannot tell whether it holds a password
+		return redacted
+	}
+	return u.Redacted()
+}
+
+// systemInfo describes the system the installer runs on
+func systemInfo(installDir string) map[string]interface{} {
+	info := map[string]interface{}{
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"go_version": runtime.Version(),
+		"cpus":       runtime.NumCPU(),
+		"time":       time.Now().UTC(),
+	}
+	if installDir != "" {
+		if free, err := AvailableSpace(installDir); err == nil {
+			info["free_space"] = free
+		} else {
+			info["free_space_error"] = err.Error()
+		}
+	}
+	return info
+}
+
+// writeDiagnosticsJSON adds value as an indented JSON entry to archive
+func writeDiagnosticsJSON(archive *zip.Writer, name string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return writeDiagnosticsEntry(archive, name, data)
+}
+
+// writeDiagnosticsEntry adds a file entry to archive
+func writeDiagnosticsEntry(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	return nil
+}
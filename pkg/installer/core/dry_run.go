@@ -0,0 +1,158 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// plannedStep is one action of ExecuteInstallation. op summarizes it for
+// PlannedOperations and is empty for actions dry runs do not report; details
+// spell it out for ExplainPlan, which shows op when there are none.
+type plannedStep struct {
+	op      string
+	details []string
+}
+
+// plannedSteps lists, in the order ExecuteInstallation takes them, the
+// actions it would take with the current selection
+func (i *Installer) plannedSteps() []plannedStep {
+	installDir := i.config.InstallDir
+	var steps []plannedStep
+	if script := i.config.PreInstallScript; script != nil {
+		steps = append(steps, plannedStep{"run pre-install script", []string{"run pre-install script " + scriptName(script)}})
+	}
+	if i.config.CreateRestorePoint && !i.config.Portable {
+		steps = append(steps, plannedStep{details: []string{"create a System Restore point"}})
+	}
+	steps = append(steps, plannedStep{fmt.Sprintf("install to %s", installDir), []string{fmt.Sprintf("create directory %s", installDir)}})
+
+	// Components go in the order they are installed; a broken order is
+	// refused by ExecuteInstallation, the plan keeps the selection order then
+	components, err := InstallOrder(i.getComponentsToInstall())
+	if err != nil {
+		components = i.getComponentsToInstall()
+	}
+	sources := i.config.payloadSources()
+	for _, c := range components {
+		step := plannedStep{op: fmt.Sprintf("install component %s", c.ID)}
+		switch {
+		case c.Installer != nil:
+			step.details = []string{fmt.Sprintf("run the installer of component %s", c.ID)}
+		case len(i.installHandlers) > 0:
+			step.details = []string{fmt.Sprintf("run the install handlers for component %s", c.ID)}
+		case len(sources) > 0:
+			for _, name := range c.Files {
+				dest := filepath.Join(installDir, filepath.FromSlash(name))
+				step.details = append(step.details, fmt.Sprintf("copy %s -> %s", name, dest))
+			}
+		}
+		steps = append(steps, step)
+		if c.PostInstall != nil {
+			steps = append(steps, plannedStep{op: fmt.Sprintf("run post-install actions of %s", c.ID)})
+		}
+		for _, service := range c.Services {
+			steps = append(steps, plannedStep{op: fmt.Sprintf("install service %s", service)})
+		}
+	}
+	steps = append(steps, plannedStep{details: []string{fmt.Sprintf("write install manifest %s", ManifestPath(installDir))}})
+
+	// Portable installations register nothing; otherwise OS registration,
+	// PATH, shortcuts and the uninstaller need a platform, which is only
+	// created once the installer runs
+	if i.config.Portable {
+		steps = append(steps, plannedStep{"write portable configuration", []string{fmt.Sprintf("write portable configuration %s", PortableConfigPath(installDir))}})
+	} else if i.platform != nil || CreatePlatformInstaller(i.config) != nil {
+		steps = append(steps, plannedStep{details: i.registrationSteps()})
+		if pc := i.config.PathConfig; pc != nil && pc.Enabled {
+			scope := "user"
+			if pc.System {
+				scope = "system"
+			}
+			for _, dir := range pc.Dirs {
+				steps = append(steps, plannedStep{fmt.Sprintf("add %s to the %s PATH", dir, scope),
+					[]string{fmt.Sprintf("add PATH entry %s to the %s PATH", dir, scope)}})
+			}
+		}
+		steps = append(steps, plannedStep{details: []string{"create shortcuts", "register the uninstaller"}})
+	}
+
+	if script := i.config.PostInstallScript; script != nil {
+		steps = append(steps, plannedStep{"run post-install script", []string{"run post-install script " + scriptName(script)}})
+	}
+	return steps
+}
+
+// PlannedOperations describes, in order, what ExecuteInstallation would do
+// with the current selection. Dry runs report these instead of installing.
+func (i *Installer) PlannedOperations() []string {
+	var ops []string
+	for _, step := range i.plannedSteps() {
+		if step.op != "" {
+			ops = append(ops, step.op)
+		}
+	}
+	return ops
+}
+
+// ExplainPlan writes, numbered and in order, every action ExecuteInstallation
+// would take with the current selection, down to single file copies and
+// registry values, without executing any of them. It spells out the
+// PlannedOperations.
+func (i *Installer) ExplainPlan(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Installation plan for %s %s:\n", i.config.AppName, i.config.Version); err != nil {
+		return err
+	}
+	n := 0
+	for _, step := range i.plannedSteps() {
+		details := step.details
+		if details == nil {
+			details = []string{step.op}
+		}
+		for _, detail := range details {
+			n++
+			if _, err := fmt.Fprintf(w, "%3d. %s\n", n, detail); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// registrationSteps lists what RegisterWithOS writes: the Add/Remove Programs
+// values on Windows, under HKCU for installations just for the current user
+// and under HKLM otherwise, which falls back to HKCU without administrator
+// rights
+func (i *Installer) registrationSteps() []string {
+	key := uninstallRegistryKey(i.config.AppName)
+	if key == "" {
+		return []string{"register with the operating system"}
+	}
+	root := "HKLM"
+	if i.config.InstallScope == ScopePerUser {
+		root = "HKCU"
+	}
+	installDir := i.config.InstallDir
+	values := []struct{ name, value string }{
+		{"DisplayName", i.config.AppName},
+		{"DisplayVersion", i.config.Version},
+		{"Publisher", i.config.Publisher},
+		{"InstallLocation", installDir},
+		{"UninstallString", filepath.Join(installDir, "uninstall.exe")},
+		{"InstallDate", "<date of installation>"},
+		{"EstimatedSize", "<size of the installed files in KB>"},
+	}
+	steps := make([]string, 0, len(values))
+	for _, v := range values {
+		steps = append(steps, fmt.Sprintf(`write registry value %s\%s\%s = %s`, root, key, v.name, v.value))
+	}
+	return steps
+}
+
+// scriptName identifies a script in the plan: its path, or that it is embedded
+func scriptName(script *Script) string {
+	if script.Path != "" {
+		return script.Path
+	}
+	return "(embedded)"
+}
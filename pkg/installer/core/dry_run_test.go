@@ -0,0 +1,102 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestExplainPlan tests that the plan lists file copies, services and PATH entries in order
+func TestExplainPlan(t *testing.T) {
+	installDir := filepath.Join(t.TempDir(), "app")
+	config := &core.Config{
+		AppName:    "ExplainApp",
+		Version:    "2.0.0",
+		InstallDir: installDir,
+		Source: fstest.MapFS{
+			"bin/app":    {Data: []byte("binary")},
+			"README.txt": {Data: []byte("readme")},
+		},
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Files: []string{"bin/app"}, Services: []string{"explaind"}},
+			{ID: "docs", Name: "Docs", Selected: true, Files: []string{"README.txt"}},
+			{ID: "extras", Name: "Extras", Files: []string{"extras.txt"}},
+		},
+		PathConfig: &core.PathConfiguration{Enabled: true, Dirs: []string{filepath.Join(installDir, "bin")}},
+	}
+
+	var out strings.Builder
+	if err := core.New(config).ExplainPlan(&out); err != nil {
+		t.Fatalf("ExplainPlan() error = %v", err)
+	}
+	plan := out.String()
+
+	want := []string{
+		"Installation plan for ExplainApp 2.0.0:",
+		"create directory " + installDir,
+		"copy bin/app -> " + filepath.Join(installDir, "bin", "app"),
+		"install service explaind",
+		"copy README.txt -> " + filepath.Join(installDir, "README.txt"),
+		"write install manifest " + core.ManifestPath(installDir),
+		"add PATH entry " + filepath.Join(installDir, "bin") + " to the user PATH",
+	}
+	last := -1
+	for _, step := range want {
+		idx := strings.Index(plan, step)
+		if idx < 0 {
+			t.Fatalf("plan lacks %q:\n%s", step, plan)
+		}
+		if idx < last {
+			t.Errorf("%q is out of order:\n%s", step, plan)
+		}
+		last = idx
+	}
+	if strings.Contains(plan, "extras.txt") {
+		t.Errorf("plan lists a file of an unselected component:\n%s", plan)
+	}
+	if !strings.Contains(plan, "  1. ") {
+		t.Errorf("plan steps are not numbered:\n%s", plan)
+	}
+	if _, err := os.Stat(installDir); !os.IsNotExist(err) {
+		t.Errorf("ExplainPlan created %s", installDir)
+	}
+}
+
+// TestExplainPlanInstallOrder tests that the plan and the planned operations
+// follow the install order and name the registry scope of the installation
+func TestExplainPlanInstallOrder(t *testing.T) {
+	config := &core.Config{
+		AppName:      "OrderApp",
+		InstallDir:   filepath.Join(t.TempDir(), "app"),
+		InstallScope: core.ScopePerUser,
+		Components: []core.Component{
+			{ID: "plugin", Name: "Plugin", Required: true, Dependencies: []string{"base"}},
+			{ID: "base", Name: "Base", Required: true},
+		},
+	}
+	installer := core.New(config)
+
+	ops := installer.PlannedOperations()
+	assertCalls(t, "planned", ops[:3], []string{"install to " + config.InstallDir, "install component base", "install component plugin"})
+
+	var out strings.Builder
+	if err := installer.ExplainPlan(&out); err != nil {
+		t.Fatalf("ExplainPlan() error = %v", err)
+	}
+	plan := out.String()
+	for _, op := range ops[1:] {
+		if !strings.Contains(plan, op) {
+			t.Errorf("plan lacks planned operation %q:\n%s", op, plan)
+		}
+	}
+	if strings.Index(plan, "component base") > strings.Index(plan, "component plugin") {
+		t.Errorf("plan installs plugin before its dependency:\n%s", plan)
+	}
+	if strings.Contains(plan, `HKLM\`) {
+		t.Errorf("plan of a per-user installation writes to HKLM:\n%s", plan)
+	}
+}
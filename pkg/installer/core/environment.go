@@ -0,0 +1,82 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// EnvFile is the shell script with the PATH changes that
+// PathConfiguration.EnvFile writes into the install directory
+const EnvFile = "env.sh"
+
+// EnvFilePath returns the shell script with the PATH changes of an install directory
+func EnvFilePath(installDir string) string {
+	return filepath.Join(installDir, EnvFile)
+}
+
+// SettingChange is a WM_SETTINGCHANGE broadcast, which tells running
+// programs such as Explorer that a system setting changed
+type SettingChange struct {
+	Window  uintptr       // Receiver, HWND_BROADCAST for all top-level windows
+	Message uint32        // WM_SETTINGCHANGE
+	Area    string        // The setting that changed, "Environment" for environment variables
+	Flags   uint32        // SendMessageTimeout flags
+	Timeout time.Duration // How long to wait for each window
+}
+
+const (
+	hwndBroadcast        = 0xFFFF
+	wmSettingChange      = 0x001A
+	smtoAbortIfHung      = 0x0002
+	settingChangeTimeout = 5 * time.Second
+)
+
+// settingChangeBackend sends a SettingChange; tests replace it
+var settingChangeBackend = sendSettingChange
+
+// BroadcastEnvironmentChange tells running programs that environment
+// variables changed, so that programs started from Explorer see a new PATH
+// without logging off, and reloads PATH of the installer process, so that
+// the programs it starts afterwards, such as ActionLaunch, see it as well.
+// On Unix there is nothing to notify: only shells started after the
+// installation read the new PATH, see PathConfiguration.EnvFile.
+func BroadcastEnvironmentChange() error {
+	if err := settingChangeBackend(SettingChange{
+		Window:  hwndBroadcast,
+		Message: wmSettingChange,
+		Area:    "Environment",
+		Flags:   smtoAbortIfHung,
+		Timeout: settingChangeTimeout,
+	}); err != nil {
+		return fmt.Errorf("failed to broadcast the environment change: %w", err)
+	}
+	return reloadProcessPath()
+}
+
+// writeEnvFile writes EnvFilePath on Unix, a script that puts the PATH
+// directories in front of PATH when sourced, e.g. ". /opt/app/env.sh"
+func (i *Installer) writeEnvFile() error {
+	pc := i.config.PathConfig
+	if pc == nil || !pc.Enabled || !pc.EnvFile || runtime.GOOS == "windows" {
+		return nil
+	}
+
+	quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'" }
+	path := EnvFilePath(i.config.InstallDir)
+	var content strings.Builder
+	fmt.Fprintf(&content, "# Use %s in the current shell:\n#   . %s\n", i.config.AppName, quote(path))
+	for _, dir := range pc.Dirs {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(i.config.InstallDir, dir)
+		}
+		fmt.Fprintf(&content, "export PATH=%s:\"$PATH\"\n", quote(dir))
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", EnvFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBroadcastEnvironmentChange(t *testing.T) {
+	var sent []SettingChange
+	settingChangeBackend = func(change SettingChange) error {
+		sent = append(sent, change)
+		return nil
+	}
+	t.Cleanup(func() { settingChangeBackend = sendSettingChange })
+
+	if err := BroadcastEnvironmentChange(); err != nil {
+		t.Fatalf("BroadcastEnvironmentChange() error = %v", err)
+	}
+	want := SettingChange{Window: 0xFFFF, Message: 0x001A, Area: "Environment", Flags: 0x0002, Timeout: 5 * time.Second}
+	if len(sent) != 1 || sent[0] != want {
+		t.Errorf("sent %+v, want %+v", sent, want)
+	}
+
+	hung := errors.New("timeout")
+	settingChangeBackend = func(SettingChange) error { return hung }
+	if err := BroadcastEnvironmentChange(); !errors.Is(err, hung) {
+		t.Errorf("BroadcastEnvironmentChange() error = %v, want %v", err, hung)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package core
+
+// sendSettingChange does nothing: Unix programs read the environment only
+// when they start
+func sendSettingChange(SettingChange) error {
+	return nil
+}
+
+// reloadProcessPath does nothing: the PATH changes on Unix are in shell
+// configuration files, which the installer process does not read
+func reloadProcessPath() error {
+	return nil
+}
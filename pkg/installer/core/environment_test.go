@@ -0,0 +1,52 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func TestEnvFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the environment file is written on Unix only")
+	}
+	useMocks(t)
+	installDir := t.TempDir()
+	config := &core.Config{
+		AppName:    "EnvApp",
+		Version:    "1.0.0",
+		InstallDir: installDir,
+		Rollback:   core.RollbackNone,
+		PathConfig: &core.PathConfiguration{Enabled: true, EnvFile: true, Dirs: []string{"bin", "/opt/it's tools"}},
+		Components: []core.Component{{ID: "app", Name: "App", Required: true}},
+	}
+	if err := runInstaller(t, config, &runUI{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	content, err := os.ReadFile(core.EnvFilePath(installDir))
+	if err != nil {
+		t.Fatalf("environment file not written: %v", err)
+	}
+	for _, want := range []string{
+		"export PATH='" + filepath.Join(installDir, "bin") + "':\"$PATH\"\n",
+		`export PATH='/opt/it'\''s tools':"$PATH"` + "\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("environment file %q misses %q", content, want)
+		}
+	}
+
+	config.InstallDir = t.TempDir()
+	config.PathConfig.EnvFile = false
+	if err := runInstaller(t, config, &runUI{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if _, err := os.Stat(core.EnvFilePath(config.InstallDir)); !os.IsNotExist(err) {
+		t.Error("environment file written without PathConfiguration.EnvFile")
+	}
+}
@@ -0,0 +1,67 @@
+//go:build windows
+// +build windows
+
+package core
+
+import (
+	"os"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// sendSettingChange sends change with SendMessageTimeout, so that windows
+// which do not respond cannot block the installer
+func sendSettingChange(change SettingChange) error {
+	area, err := windows.UTF16PtrFromString(change.Area)
+	if err != nil {
+		return err
+	}
+	var result uintptr
+	ret, _, err := windows.NewLazySystemDLL("user32.dll").NewProc("SendMessageTimeoutW").Call(
+		change.Window,
+		uintptr(change.Message),
+		0,
+		uintptr(unsafe.Pointer(area)),
+		uintptr(change.Flags),
+		uintptr(change.Timeout.Milliseconds()),
+		uintptr(unsafe.Pointer(&result)),
+	)
+	if ret == 0 {
+		return err
+	}
+	return nil
+}
+
+// reloadProcessPath sets PATH of the installer process to the system PATH
+// followed by the user PATH, as a newly started program gets it
+func reloadProcessPath() error {
+	var parts []string
+	for _, location := range []struct {
+		root registry.Key
+		path string
+	}{
+		{registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Control\Session Manager\Environment`},
+		{registry.CURRENT_USER, `Environment`},
+	} {
+		key, err := registry.OpenKey(location.root, location.path, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+		value, _, err := key.GetStringValue("Path")
+		key.Close()
+		if err != nil || value == "" {
+			continue
+		}
+		if expanded, err := registry.ExpandString(value); err == nil {
+			value = expanded
+		}
+		parts = append(parts, value)
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return os.Setenv("PATH", strings.Join(parts, ";"))
+}
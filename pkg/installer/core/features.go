@@ -0,0 +1,34 @@
+package core
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// FeaturesFromContext returns the feature flags of the installation, see
+// Config.Features, in component installers and their rollback. The map is a
+// copy; it is nil outside an installation or without features.
+func FeaturesFromContext(ctx context.Context) map[string]bool {
+	features, _ := ctx.Value(contextKey("features")).(map[string]bool)
+	return features
+}
+
+// FeatureEnabled reports whether the feature flag name is switched on for
+// the installation running ctx, such as "telemetry" or "dev-mode"
+func FeatureEnabled(ctx context.Context, name string) bool {
+	return FeaturesFromContext(ctx)[name]
+}
+
+// FeatureNames returns the names of features, sorted
+func FeatureNames(features map[string]bool) []string {
+	return slices.Sorted(maps.Keys(features))
+}
+
+// withFeatures adds a copy of features to ctx for FeaturesFromContext
+func withFeatures(ctx context.Context, features map[string]bool) context.Context {
+	if len(features) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey("features"), maps.Clone(features))
+}
@@ -0,0 +1,103 @@
+package core_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestFeaturesReachComponents tests that feature flags reach component installers and are recorded
+func TestFeaturesReachComponents(t *testing.T) {
+	var telemetry, devMode, missing bool
+	config := &core.Config{
+		AppName:    "FeatureApp",
+		Version:    "1.0.0",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackNone,
+		Features:   map[string]bool{"telemetry": true, "dev-mode": false},
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Installer: func(ctx context.Context) error {
+				telemetry = core.FeatureEnabled(ctx, "telemetry")
+				devMode = core.FeatureEnabled(ctx, "dev-mode")
+				missing = core.FeatureEnabled(ctx, "unknown")
+				// Installers get a copy they cannot change the installation's flags with
+				core.FeaturesFromContext(ctx)["dev-mode"] = true
+				return nil
+			}},
+		},
+	}
+	inst := newTestInstaller(config)
+	hashWithout := core.New(&core.Config{AppName: config.AppName, Version: config.Version, InstallDir: config.InstallDir}).ConfigHash()
+
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	if !telemetry || devMode || missing {
+		t.Errorf("component saw telemetry = %v, dev-mode = %v, unknown = %v", telemetry, devMode, missing)
+	}
+
+	want := map[string]bool{"telemetry": true, "dev-mode": false}
+	manifest, err := core.LoadManifest(config.InstallDir)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if !reflect.DeepEqual(manifest.Features, want) {
+		t.Errorf("manifest features = %v, want %v", manifest.Features, want)
+	}
+	if summary := inst.CreateSummary(); !reflect.DeepEqual(summary.Features, want) {
+		t.Errorf("summary features = %v, want %v", summary.Features, want)
+	}
+	// Running again with other flags is no identical installation
+	if inst.ConfigHash() == hashWithout {
+		t.Error("ConfigHash() ignores the feature flags")
+	}
+}
+
+// TestFeaturesReachUninstallers tests that component uninstallers see the flags the installation was made with
+func TestFeaturesReachUninstallers(t *testing.T) {
+	var telemetry bool
+	config := &core.Config{
+		AppName:    "FeatureApp",
+		Version:    "1.0.0",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackNone,
+		Features:   map[string]bool{"telemetry": true},
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true,
+				Installer: func(ctx context.Context) error { return nil },
+				Uninstaller: func(ctx context.Context) error {
+					telemetry = core.FeatureEnabled(ctx, "telemetry")
+					return nil
+				}},
+		},
+	}
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+
+	// The uninstaller starts from the defaults, not the user's choices
+	config.Features = map[string]bool{"telemetry": false}
+	uninstaller, err := core.NewUninstaller(config)
+	if err != nil {
+		t.Fatalf("NewUninstaller() error = %v", err)
+	}
+	plan, err := uninstaller.PreviewRemoval()
+	if err != nil {
+		t.Fatalf("PreviewRemoval() error = %v", err)
+	}
+	if err := uninstaller.Uninstall(plan, core.UninstallOptions{}); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if !telemetry {
+		t.Error("uninstaller saw the configured flags instead of the installed ones")
+	}
+}
+
+// TestFeaturesOutsideInstallation tests that no feature is enabled without an installation
+func TestFeaturesOutsideInstallation(t *testing.T) {
+	if core.FeatureEnabled(context.Background(), "telemetry") || core.FeaturesFromContext(context.Background()) != nil {
+		t.Error("feature enabled outside an installation")
+	}
+}
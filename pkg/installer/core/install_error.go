@@ -0,0 +1,96 @@
+package core
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// ErrInsufficientSpace reports that the target volume lacks the space the
+// installation needs
+var ErrInsufficientSpace = errors.New("insufficient disk space")
+
+// ErrChecksumMismatch reports a payload whose checksum differs from the
+// expected one; trying again does not help
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrorKind classifies the cause of an InstallError
+type ErrorKind string
+
+const (
+	ErrorKindUnknown          ErrorKind = ""
+	ErrorKindPermissionDenied ErrorKind = "permission-denied"
+	ErrorKindFileInUse        ErrorKind = "file-in-use"
+	ErrorKindDiskFull         ErrorKind = "disk-full"
+	ErrorKindRequirements     ErrorKind = "requirements-not-met"
+	ErrorKindPrecondition     ErrorKind = "precondition-not-met"
+)
+
+// Remediation hints for the classified error kinds
+const (
+	HintPermissionDenied = "Run the installer as administrator, or choose an installation directory you can write to."
+	HintFileInUse        = "Close the application and any program using its files, then try again."
+	HintDiskFull         = "Free up disk space on the target drive, or choose an installation directory on another drive."
+	HintRequirements     = "Install the application on a computer with more memory or processor cores."
+	HintPrecondition     = "Install what the component needs, as the message says, then run the installer again."
+)
+
+// InstallError describes a failed installation. It keeps the message of the
+// underlying error and adds what the views need to help the user.
+type InstallError struct {
+	Err        error
+	Phase      Phase  // Phase the installation failed in
+	Component  string // ID of the failing component, empty outside of PhaseFileCopy
+	RolledBack bool   // Whether a rollback undid the changes made so far
+	Kind       ErrorKind
+	Hint       string // What the user can do about it, empty if unknown
+}
+
+// NewInstallError wraps err and classifies it, see ClassifyError. An err that
+// already is an InstallError is returned as is.
+func NewInstallError(err error, phase Phase, component string) *InstallError {
+	var installErr *InstallError
+	if errors.As(err, &installErr) {
+		return installErr
+	}
+	kind, hint := ClassifyError(err)
+	return &InstallError{Err: err, Phase: phase, Component: component, Kind: kind, Hint: hint}
+}
+
+func (e *InstallError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InstallError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError recognizes common causes of failed installations and returns
+// their kind and a remediation hint, ErrorKindUnknown and no hint otherwise
+func ClassifyError(err error) (ErrorKind, string) {
+	switch {
+	case err == nil:
+		return ErrorKindUnknown, ""
+	case isFileInUse(err):
+		return ErrorKindFileInUse, HintFileInUse
+	case errors.Is(err, fs.ErrPermission) || isReadOnlyFS(err):
+		return ErrorKindPermissionDenied, HintPermissionDenied
+	case errors.Is(err, ErrInsufficientSpace) || isDiskFull(err):
+		return ErrorKindDiskFull, HintDiskFull
+	case errors.Is(err, ErrRequirementsNotMet):
+		return ErrorKindRequirements, HintRequirements
+	case errors.Is(err, ErrPreconditionNotMet):
+		return ErrorKindPrecondition, HintPrecondition
+	}
+	return ErrorKindUnknown, ""
+}
+
+// ErrorHint returns the remediation hint for err, from its InstallError if it
+// has one
+func ErrorHint(err error) string {
+	var installErr *InstallError
+	if errors.As(err, &installErr) {
+		return installErr.Hint
+	}
+	_, hint := ClassifyError(err)
+	return hint
+}
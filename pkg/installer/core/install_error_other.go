@@ -0,0 +1,37 @@
+//go:build !windows
+// +build !windows
+
+package core
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isDiskFull reports whether err is the system's disk full error
+func isDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC)
+}
+
+// isFileInUse reports whether err says a file is busy, such as a running
+// executable that cannot be written
+func isFileInUse(err error) bool {
+	return errors.Is(err, syscall.ETXTBSY) || errors.Is(err, syscall.EBUSY)
+}
+
+// isServiceBusy reports whether err says the service manager is busy. Only
+// the Windows service control manager reports that, so it is never the case.
+func isServiceBusy(err error) bool {
+	return false
+}
+
+// isReadOnlyFS reports whether err says the target file system is read-only
+func isReadOnlyFS(err error) bool {
+	return errors.Is(err, syscall.EROFS)
+}
+
+// clearReadOnly reports whether it made path writable. Only Windows marks
+// files read-only independently of their permissions, so it does nothing here.
+func clearReadOnly(path string) bool {
+	return false
+}
@@ -0,0 +1,175 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestClassifyError tests that common failures are recognized with a hint
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind core.ErrorKind
+		hint string
+	}{
+		{"permission denied", &fs.PathError{Op: "open", Path: "/opt/app", Err: fs.ErrPermission}, core.ErrorKindPermissionDenied, core.HintPermissionDenied},
+		{"wrapped permission", fmt.Errorf("copy failed: %w", os.ErrPermission), core.ErrorKindPermissionDenied, core.HintPermissionDenied},
+		{"insufficient space", fmt.Errorf("%w: required 10 bytes, available 5 bytes", core.ErrInsufficientSpace), core.ErrorKindDiskFull, core.HintDiskFull},
+		{"requirements", fmt.Errorf("%w: 4 processor cores required, 2 available", core.ErrRequirementsNotMet), core.ErrorKindRequirements, core.HintRequirements},
+		{"other", errors.New("boom"), core.ErrorKindUnknown, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, hint := core.ClassifyError(tt.err)
+			if kind != tt.kind || hint != tt.hint {
+				t.Errorf("ClassifyError() = %q, %q; want %q, %q", kind, hint, tt.kind, tt.hint)
+			}
+		})
+	}
+}
+
+// TestClassifyDiskFull tests that a full disk reported by the system is recognized
+func TestClassifyDiskFull(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows reports a full disk with its own error codes")
+	}
+	err := &fs.PathError{Op: "write", Path: "/opt/app/data", Err: syscall.ENOSPC}
+	if kind, _ := core.ClassifyError(err); kind != core.ErrorKindDiskFull {
+		t.Errorf("ClassifyError() = %q, want %q", kind, core.ErrorKindDiskFull)
+	}
+}
+
+// TestInstallError tests wrapping a failure into an InstallError
+func TestInstallError(t *testing.T) {
+	cause := &fs.PathError{Op: "mkdir", Path: "/opt/app", Err: fs.ErrPermission}
+	installErr := core.NewInstallError(cause, core.PhaseFileCopy, "core")
+
+	if installErr.Error() != cause.Error() {
+		t.Errorf("Error() = %q, want %q", installErr.Error(), cause.Error())
+	}
+	if !errors.Is(installErr, fs.ErrPermission) {
+		t.Error("InstallError should unwrap to its cause")
+	}
+	if installErr.Component != "core" || installErr.Phase != core.PhaseFileCopy {
+		t.Errorf("unexpected component %q or phase %q", installErr.Component, installErr.Phase)
+	}
+	if installErr.Kind != core.ErrorKindPermissionDenied {
+		t.Errorf("Kind = %q, want %q", installErr.Kind, core.ErrorKindPermissionDenied)
+	}
+
+	// Wrapping again keeps the original context
+	again := core.NewInstallError(fmt.Errorf("install: %w", installErr), core.PhasePostInstall, "")
+	if again != installErr {
+		t.Error("an existing InstallError should be returned as is")
+	}
+
+	if hint := core.ErrorHint(fmt.Errorf("failed: %w", installErr)); !strings.Contains(hint, "administrator") {
+		t.Errorf("ErrorHint() = %q, want the permission hint", hint)
+	}
+	if hint := core.ErrorHint(errors.New("boom")); hint != "" {
+		t.Errorf("ErrorHint() = %q, want none", hint)
+	}
+}
+
+// TestClassifyFileInUseAndReadOnly tests that busy files and read-only file
+// systems reported by the system are recognized
+func TestClassifyFileInUseAndReadOnly(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows reports these with its own error codes")
+	}
+	busy := &fs.PathError{Op: "open", Path: "/opt/app/bin/app", Err: syscall.ETXTBSY}
+	if kind, hint := core.ClassifyError(busy); kind != core.ErrorKindFileInUse || hint != core.HintFileInUse {
+		t.Errorf("ClassifyError(busy) = %q, %q; want %q", kind, hint, core.ErrorKindFileInUse)
+	}
+	readOnly := &fs.PathError{Op: "open", Path: "/opt/app/bin/app", Err: syscall.EROFS}
+	if kind, _ := core.ClassifyError(readOnly); kind != core.ErrorKindPermissionDenied {
+		t.Errorf("ClassifyError(read-only) = %q, want %q", kind, core.ErrorKindPermissionDenied)
+	}
+}
+
+// deniedConfig installs a component that may only be written elevated
+func deniedConfig(t *testing.T, platform *core.MockPlatformInstaller, attempts *int) *core.Config {
+	return &core.Config{
+		AppName:           "DeniedApp",
+		InstallDir:        t.TempDir(),
+		ElevationStrategy: core.ElevationAuto,
+		Components: []core.Component{{ID: "core", Name: "Core", Required: true,
+			Installer: func(ctx context.Context) error {
+				*attempts++
+				if !platform.IsElevated() {
+					return &fs.PathError{Op: "open", Path: "/opt/app/bin/app", Err: fs.ErrPermission}
+				}
+				return nil
+			}}},
+	}
+}
+
+// TestPermissionErrorDoesNotElevateMidInstall tests that a component denied
+// access fails with the permission hint instead of relaunching the
+// installer elevated, which would leave the installation half done
+func TestPermissionErrorDoesNotElevateMidInstall(t *testing.T) {
+	platform, _ := useMocks(t)
+	var attempts int
+	err := runInstaller(t, deniedConfig(t, platform, &attempts), &runUI{grantElevation: true})
+
+	var installErr *core.InstallError
+	if !errors.As(err, &installErr) {
+		t.Fatalf("Run() error = %v, want an InstallError", err)
+	}
+	if installErr.Kind != core.ErrorKindPermissionDenied || installErr.Hint != core.HintPermissionDenied {
+		t.Errorf("Kind = %q, Hint = %q; want the permission hint", installErr.Kind, installErr.Hint)
+	}
+	if attempts != 1 || platform.Elevated {
+		t.Errorf("attempts = %d, elevated = %v; want a single attempt without elevation", attempts, platform.Elevated)
+	}
+}
+
+// TestPermissionErrorWithoutElevation tests that a denied component fails
+// with the permission hint when the user declines to elevate
+func TestPermissionErrorWithoutElevation(t *testing.T) {
+	platform, _ := useMocks(t)
+	var attempts int
+	err := runInstaller(t, deniedConfig(t, platform, &attempts), &runUI{})
+
+	var installErr *core.InstallError
+	if !errors.As(err, &installErr) {
+		t.Fatalf("Run() error = %v, want an InstallError", err)
+	}
+	if installErr.Kind != core.ErrorKindPermissionDenied || installErr.Component != "core" {
+		t.Errorf("Kind = %q, Component = %q; want permission-denied for core", installErr.Kind, installErr.Component)
+	}
+	if attempts != 1 || platform.Elevated {
+		t.Errorf("attempts = %d, elevated = %v; want a single attempt without elevation", attempts, platform.Elevated)
+	}
+}
+
+// TestFileInUseDoesNotElevate tests that a busy file fails with its own
+// hint instead of asking for elevation
+func TestFileInUseDoesNotElevate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows reports files in use with its own error codes")
+	}
+	platform, _ := useMocks(t)
+	config := deniedConfig(t, platform, new(int))
+	config.Components[0].Installer = func(ctx context.Context) error {
+		return &fs.PathError{Op: "open", Path: "/opt/app/bin/app", Err: syscall.ETXTBSY}
+	}
+
+	err := runInstaller(t, config, &runUI{grantElevation: true})
+	if kind, hint := core.ClassifyError(err); kind != core.ErrorKindFileInUse || hint != core.HintFileInUse {
+		t.Errorf("ClassifyError() = %q, %q; want %q", kind, hint, core.ErrorKindFileInUse)
+	}
+	if platform.Elevated {
+		t.Error("a file in use should not ask for elevation")
+	}
+}
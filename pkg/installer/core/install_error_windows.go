@@ -0,0 +1,45 @@
+//go:build windows
+// +build windows
+
+package core
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// isDiskFull reports whether err is the system's disk full error
+func isDiskFull(err error) bool {
+	return errors.Is(err, windows.ERROR_DISK_FULL) || errors.Is(err, windows.ERROR_HANDLE_DISK_FULL)
+}
+
+// isFileInUse reports whether err says another process has a file open or
+// locked, such as a running executable
+func isFileInUse(err error) bool {
+	return errors.Is(err, windows.ERROR_SHARING_VIOLATION) || errors.Is(err, windows.ERROR_LOCK_VIOLATION) ||
+		errors.Is(err, windows.ERROR_USER_MAPPED_FILE)
+}
+
+// isServiceBusy reports whether err says the service control manager is
+// busy, such as a locked service database or a service still being deleted
+func isServiceBusy(err error) bool {
+	return errors.Is(err, windows.ERROR_SERVICE_DATABASE_LOCKED) || errors.Is(err, windows.ERROR_SERVICE_CANNOT_ACCEPT_CTRL) ||
+		errors.Is(err, windows.ERROR_SERVICE_MARKED_FOR_DELETE) || errors.Is(err, windows.ERROR_SERVICE_REQUEST_TIMEOUT)
+}
+
+// isReadOnlyFS reports whether err says the target medium is write-protected
+func isReadOnlyFS(err error) bool {
+	return errors.Is(err, windows.ERROR_WRITE_PROTECT)
+}
+
+// clearReadOnly clears the read-only attribute of path and reports whether
+// it had one
+func clearReadOnly(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Mode().Perm()&0200 != 0 {
+		return false
+	}
+	return os.Chmod(path, info.Mode().Perm()|0200) == nil
+}
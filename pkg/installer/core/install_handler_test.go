@@ -0,0 +1,97 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// recordingHandler appends its name and the installed components to a shared log
+type recordingHandler struct {
+	name string
+	log  *[]string
+	err  error
+}
+
+func (h recordingHandler) Install(ctx context.Context, installPath string, components []core.Component) error {
+	for _, c := range components {
+		*h.log = append(*h.log, h.name+":"+c.ID)
+	}
+	return h.err
+}
+
+func handlerConfig(t *testing.T) *core.Config {
+	return &core.Config{
+		AppName:    "HandlerApp",
+		Version:    "1.0.0",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true},
+			{ID: "docs", Name: "Docs", Selected: true},
+		},
+	}
+}
+
+// TestInstallHandlersRunInOrder tests that handlers run in registration order for each component
+func TestInstallHandlersRunInOrder(t *testing.T) {
+	var log []string
+	inst := newTestInstaller(handlerConfig(t))
+	inst.SetInstallHandler(func(installPath string, components []core.Component) error {
+		log = append(log, "func:"+components[0].ID)
+		return nil
+	})
+	inst.AddInstallHandler(recordingHandler{name: "copy", log: &log}, recordingHandler{name: "register", log: &log})
+
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	want := []string{
+		"func:core", "copy:core", "register:core",
+		"func:docs", "copy:docs", "register:docs",
+	}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("handler calls = %q, want %q", log, want)
+	}
+}
+
+// TestInstallHandlerErrorStopsSequence tests that a failing handler aborts the installation
+func TestInstallHandlerErrorStopsSequence(t *testing.T) {
+	var log []string
+	errCopy := errors.New("disk full")
+	inst := newTestInstaller(handlerConfig(t))
+	inst.AddInstallHandler(recordingHandler{name: "copy", log: &log, err: errCopy}, recordingHandler{name: "register", log: &log})
+
+	err := inst.ExecuteInstallation()
+	if !errors.Is(err, errCopy) {
+		t.Fatalf("ExecuteInstallation() error = %v, want %v", err, errCopy)
+	}
+	if !strings.Contains(err.Error(), "install handler 1") {
+		t.Errorf("error %q does not name the failing handler", err)
+	}
+	if want := []string{"copy:core"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("handler calls = %q, want %q", log, want)
+	}
+}
+
+// TestSetInstallHandlerReplaces tests that SetInstallHandler drops earlier handlers
+func TestSetInstallHandlerReplaces(t *testing.T) {
+	var log []string
+	inst := newTestInstaller(handlerConfig(t))
+	inst.AddInstallHandler(recordingHandler{name: "old", log: &log})
+	inst.SetInstallHandler(core.InstallHandlerFunc(func(installPath string, components []core.Component) error {
+		log = append(log, "new:"+components[0].ID)
+		return nil
+	}))
+
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	if want := []string{"new:core", "new:docs"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("handler calls = %q, want %q", log, want)
+	}
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// ErrNoLogFile is returned when the log is requested but Config.LogFile is not set
+var ErrNoLogFile = errors.New("no log file configured")
+
+var (
+	// secretValue matches "password=..." and "token: ..." in a log line
+	secretValue = regexp.MustCompile(`(?i)([\w.-]*(?:password|passwd|secret|token)[\w.-]*)(=|:\s*)("[^"]*"|\S+)`)
+	// urlPassword matches the password of a URL such as a proxy setting
+	urlPassword = regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`)
+)
+
+// RedactLog replaces secrets in log text: values of keys naming a password,
+// secret or token, which the logger redacts already but scripts and other
+// programs writing to the log may not, and passwords in URLs
+func RedactLog(log []byte) []byte {
+	log = secretValue.ReplaceAll(log, []byte("${1}${2}"+redacted))
+	return urlPassword.ReplaceAll(log, []byte("${1}xxxxx@"))
+}
+
+// ReadLog returns the content of the log file with secrets redacted, see RedactLog
+func (i *Installer) ReadLog() ([]byte, error) {
+	if i.config.LogFile == "" {
+		return nil, ErrNoLogFile
+	}
+	log, err := os.ReadFile(i.config.LogFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log file: %w", err)
+	}
+	return RedactLog(log), nil
+}
+
+// OpenLog opens the log file with the default application of the desktop,
+// or with the handler set for ActionViewLog, and returns a message for the
+// user. Unlike a configured ActionViewLog it is available on the error
+// screens as well.
+func (i *Installer) OpenLog() (string, error) {
+	if i.config.LogFile == "" {
+		return "", ErrNoLogFile
+	}
+	action := CompletionAction{ID: string(ActionViewLog), Label: "View log", Kind: ActionViewLog}
+	return i.completionHandler(ActionViewLog)(i, action)
+}
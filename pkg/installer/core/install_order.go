@@ -0,0 +1,79 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InstallOrder returns the components in the order they are installed:
+// each component after its dependencies, and otherwise by Component.Order,
+// lowest first, keeping the given order among equal Orders. Dependencies
+// that are not among components, e.g. because they are installed already,
+// are ignored. A component ordered before one of its dependencies, or a
+// dependency cycle, is an error.
+func InstallOrder(components []Component) ([]Component, error) {
+	if err := checkInstallOrder(components); err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(components))
+	for idx, c := range components {
+		index[c.ID] = idx
+	}
+	pending := make([]int, len(components)) // Dependencies not installed yet
+	dependents := make([][]int, len(components))
+	for idx, c := range components {
+		for _, dep := range c.Dependencies {
+			if d, ok := index[dep]; ok {
+				pending[idx]++
+				dependents[d] = append(dependents[d], idx)
+			}
+		}
+	}
+
+	ordered := make([]Component, 0, len(components))
+	placed := make([]bool, len(components))
+	for len(ordered) < len(components) {
+		next := -1
+		for idx, c := range components {
+			if placed[idx] || pending[idx] > 0 {
+				continue
+			}
+			if next < 0 || c.Order < components[next].Order {
+				next = idx
+			}
+		}
+		if next < 0 {
+			var cycle []string
+			for idx, c := range components {
+				if !placed[idx] {
+					cycle = append(cycle, c.ID)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+		}
+		placed[next] = true
+		ordered = append(ordered, components[next])
+		for _, d := range dependents[next] {
+			pending[d]--
+		}
+	}
+	return ordered, nil
+}
+
+// checkInstallOrder reports a component whose Order puts it before one of
+// its dependencies
+func checkInstallOrder(components []Component) error {
+	byID := make(map[string]Component, len(components))
+	for _, c := range components {
+		byID[c.ID] = c
+	}
+	for _, c := range components {
+		for _, id := range c.Dependencies {
+			if dep, ok := byID[id]; ok && c.Order < dep.Order {
+				return fmt.Errorf("component %s (order %d) is ordered before its dependency %s (order %d)", c.ID, c.Order, dep.ID, dep.Order)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func TestInstallOrder(t *testing.T) {
+	ordered, err := core.InstallOrder([]core.Component{
+		{ID: "plugins", Order: 20, Dependencies: []string{"runtime"}},
+		{ID: "docs", Order: 30},
+		{ID: "runtime", Order: 10, Dependencies: []string{"drivers"}},
+		{ID: "drivers"},
+		{ID: "samples", Order: 20},
+		{ID: "tools", Dependencies: []string{"unselected"}},
+	})
+	if err != nil {
+		t.Fatalf("InstallOrder() error = %v", err)
+	}
+	if got := componentIDs(ordered); got != "drivers,tools,runtime,plugins,samples,docs" {
+		t.Errorf("InstallOrder() = %s, want drivers,tools,runtime,plugins,samples,docs", got)
+	}
+
+	// Without Order, dependencies come first and the rest keeps its order
+	ordered, err = core.InstallOrder([]core.Component{
+		{ID: "app", Dependencies: []string{"lib"}},
+		{ID: "docs"},
+		{ID: "lib"},
+	})
+	if err != nil {
+		t.Fatalf("InstallOrder() error = %v", err)
+	}
+	if got := componentIDs(ordered); got != "docs,lib,app" {
+		t.Errorf("InstallOrder() = %s, want docs,lib,app", got)
+	}
+}
+
+func TestInstallOrderConflicts(t *testing.T) {
+	_, err := core.InstallOrder([]core.Component{
+		{ID: "app", Order: 1, Dependencies: []string{"lib"}},
+		{ID: "lib", Order: 2},
+	})
+	if err == nil || !strings.Contains(err.Error(), "app (order 1) is ordered before its dependency lib (order 2)") {
+		t.Errorf("InstallOrder() error = %v, want the order conflict", err)
+	}
+
+	_, err = core.InstallOrder([]core.Component{
+		{ID: "a", Dependencies: []string{"b"}},
+		{ID: "b", Dependencies: []string{"a"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Errorf("InstallOrder() error = %v, want the cycle", err)
+	}
+
+	config := &core.Config{Components: []core.Component{
+		{ID: "app", Order: 1, Dependencies: []string{"lib"}},
+		{ID: "lib", Order: 2},
+	}}
+	if _, err := core.ValidateComponents(config); err == nil {
+		t.Error("ValidateComponents() should reject the order conflict")
+	}
+}
+
+func TestInstallFollowsOrder(t *testing.T) {
+	var installed []string
+	component := func(id string, order int, deps ...string) core.Component {
+		return core.Component{ID: id, Name: id, Selected: true, Order: order, Dependencies: deps,
+			Installer: func(ctx context.Context) error {
+				installed = append(installed, id)
+				return nil
+			}}
+	}
+	config := &core.Config{
+		AppName:    "OrderApp",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{
+			component("app", 10, "database"),
+			component("database", 5),
+			component("migrations", 20, "database"),
+			component("service", 15),
+		},
+	}
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	assertCalls(t, "Install", installed, []string{"database", "app", "service", "migrations"})
+}
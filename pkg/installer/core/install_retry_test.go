@@ -0,0 +1,98 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// flakyStep returns a step that fails with err until it ran failures times
+func flakyStep(calls *int, failures int, err error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		*calls++
+		if *calls <= failures {
+			return err
+		}
+		return nil
+	}
+}
+
+// TestComponentRetry tests that a component step failing twice succeeds
+// under a 3-attempt policy, with every retry reported
+func TestComponentRetry(t *testing.T) {
+	installs, services := 0, 0
+	var retried []string
+	policy := &core.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			retried = append(retried, fmt.Sprintf("%d: %v", attempt, err))
+		},
+	}
+	config := &core.Config{AppName: "RetryApp", InstallDir: t.TempDir()}
+	config.Components = []core.Component{{
+		ID: "service", Name: "Service", Required: true,
+		Installer: flakyStep(&installs, 2, errors.New("files busy")),
+		Retry:     policy,
+		PostInstall: func(ctx context.Context, dir string) error {
+			return flakyStep(&services, 2, errors.New("service manager busy"))(ctx)
+		},
+		PostInstallRetry: policy,
+	}}
+
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	if installs != 3 || services != 3 {
+		t.Errorf("install ran %d times and post-install %d times, want 3 each", installs, services)
+	}
+	want := "1: files busy,2: files busy,1: service manager busy,2: service manager busy"
+	if got := strings.Join(retried, ","); got != want {
+		t.Errorf("retries = %s, want %s", got, want)
+	}
+}
+
+// TestComponentRetryExhausted tests that the last error surfaces once the attempts are used up
+func TestComponentRetryExhausted(t *testing.T) {
+	calls := 0
+	config := &core.Config{AppName: "RetryApp", InstallDir: t.TempDir()}
+	config.Components = []core.Component{{
+		ID: "core", Name: "Core", Required: true,
+		Installer: flakyStep(&calls, 5, errors.New("still busy")),
+		Retry:     &core.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}}
+
+	err := newTestInstaller(config).ExecuteInstallation()
+	if err == nil || !strings.Contains(err.Error(), "giving up after 3 attempts: still busy") {
+		t.Fatalf("ExecuteInstallation() error = %v, want the last error after 3 attempts", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+// TestTransientRetryPolicy tests that a checksum mismatch is not retried
+func TestTransientRetryPolicy(t *testing.T) {
+	calls := 0
+	policy := core.TransientRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	config := &core.Config{AppName: "RetryApp", InstallDir: t.TempDir()}
+	config.Components = []core.Component{{
+		ID: "core", Name: "Core", Required: true,
+		Installer: flakyStep(&calls, 1, fmt.Errorf("%w for core.zip", core.ErrChecksumMismatch)),
+		Retry:     &policy,
+	}}
+
+	err := newTestInstaller(config).ExecuteInstallation()
+	if !errors.Is(err, core.ErrChecksumMismatch) || calls != 1 {
+		t.Errorf("ExecuteInstallation() = %v after %d calls, want the checksum mismatch at once", err, calls)
+	}
+	if core.IsTransientError(core.ErrChecksumMismatch) || core.IsTransientError(errors.New("unknown")) {
+		t.Error("IsTransientError() should only accept busy files and services")
+	}
+}
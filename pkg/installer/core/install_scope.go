@@ -0,0 +1,255 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// InstallScope defines whether an installation targets the current user or the whole machine
+type InstallScope int
+
+const (
+	// ScopeAuto - per-machine when elevated, otherwise per-user (default)
+	ScopeAuto InstallScope = iota
+	// ScopePerUser - install into the user's profile, no elevation required
+	ScopePerUser
+	// ScopePerMachine - install system-wide, usually requires elevation
+	ScopePerMachine
+)
+
+// String returns the string representation of the scope
+func (s InstallScope) String() string {
+	switch s {
+	case ScopePerUser:
+		return "per-user"
+	case ScopePerMachine:
+		return "per-machine"
+	default:
+		return "auto"
+	}
+}
+
+// ParseInstallScope converts a scope name such as "per-user", see
+// InstallScope.String, to an InstallScope
+func ParseInstallScope(name string) (InstallScope, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "auto":
+		return ScopeAuto, nil
+	case "per-user":
+		return ScopePerUser, nil
+	case "per-machine":
+		return ScopePerMachine, nil
+	}
+	return ScopeAuto, fmt.Errorf("invalid install scope %q, want per-user or per-machine", name)
+}
+
+// scopeEnv describes the environment used to resolve default install directories
+type scopeEnv struct {
+	goos       string
+	getenv     func(string) string
+	homeDir    func() (string, error)
+	elevated   func() bool
+	canElevate func() bool // Whether the installer can ask for elevation; nil means it can
+}
+
+// currentScopeEnv returns the environment of the running process
+func currentScopeEnv() scopeEnv {
+	return scopeEnv{
+		goos:    runtime.GOOS,
+		getenv:  os.Getenv,
+		homeDir: os.UserHomeDir,
+		elevated: func() bool {
+			return CreatePlatformInstaller(&Config{}).IsElevated()
+		},
+		canElevate: func() bool {
+			// Windows asks through UAC, macOS through osascript, others need sudo or pkexec
+			if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+				return true
+			}
+			for _, tool := range []string{"sudo", "pkexec"} {
+				if _, err := exec.LookPath(tool); err == nil {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// resolveScope returns the scope ScopeAuto stands for: per-machine when
+// elevated, otherwise per-user
+func resolveScope(scope InstallScope, env scopeEnv) InstallScope {
+	if scope != ScopeAuto {
+		return scope
+	}
+	if env.elevated != nil && env.elevated() {
+		return ScopePerMachine
+	}
+	return ScopePerUser
+}
+
+// InstallScopeChoice reports whether the user can choose between installing
+// for all users and just for themselves. The configuration forces the scope
+// with InstallScope or Portable, the platform when the installer neither
+// runs elevated nor can ask for elevation.
+func (c *Config) InstallScopeChoice() bool {
+	return c.installScopeChoice(currentScopeEnv())
+}
+
+func (c *Config) installScopeChoice(env scopeEnv) bool {
+	if c.InstallScope != ScopeAuto || c.Portable {
+		return false
+	}
+	return (env.elevated != nil && env.elevated()) || env.canElevate == nil || env.canElevate()
+}
+
+// SetInstallScope installs for the current user or for all users and lets
+// what depends on it follow: an InstallDir that is empty or the default of
+// the previous scope becomes the default of the new one, and PATH becomes
+// the system PATH for all users and the user PATH otherwise. Elevation and
+// the shortcut folders follow from the scope, see PrivilegesFor and
+// ShortcutDirs.
+func (c *Config) SetInstallScope(scope InstallScope) {
+	c.setInstallScope(scope, currentScopeEnv())
+}
+
+func (c *Config) setInstallScope(scope InstallScope, env scopeEnv) {
+	followDir := c.InstallDir == "" || c.InstallDir == c.defaultInstallDir(env)
+	c.InstallScope = scope
+	if followDir {
+		c.InstallDir = c.defaultInstallDir(env)
+	}
+	if c.PathConfig != nil {
+		c.PathConfig.System = resolveScope(scope, env) == ScopePerMachine
+	}
+}
+
+// ShortcutDirs returns where the shortcuts to the application go for the
+// install scope: on Windows the Start Menu folder of the application and the
+// desktop, elsewhere the directory linking the executable and, installed just
+// for the user, the user's desktop
+func (c *Config) ShortcutDirs() (menu, desktop string) {
+	return c.shortcutDirs(currentScopeEnv())
+}
+
+func (c *Config) shortcutDirs(env scopeEnv) (menu, desktop string) {
+	perUser := resolveScope(c.InstallScope, env) == ScopePerUser
+	home := ""
+	if env.homeDir != nil {
+		home, _ = env.homeDir()
+	}
+
+	switch env.goos {
+	case "windows":
+		if perUser {
+			appData := env.getenv("APPDATA")
+			if appData == "" {
+				appData = home + `\AppData\Roaming`
+			}
+			profile := env.getenv("USERPROFILE")
+			if profile == "" {
+				profile = home
+			}
+			return windowsJoin(appData, `Microsoft\Windows\Start Menu\Programs`, c.AppName), windowsJoin(profile, "Desktop")
+		}
+		programData := env.getenv("ProgramData")
+		if programData == "" {
+			programData = `C:\ProgramData`
+		}
+		public := env.getenv("PUBLIC")
+		if public == "" {
+			public = `C:\Users\Public`
+		}
+		return windowsJoin(programData, `Microsoft\Windows\Start Menu\Programs`, c.AppName), windowsJoin(public, "Desktop")
+
+	default:
+		if perUser && home != "" {
+			return path.Join(home, "bin"), path.Join(home, "Desktop")
+		}
+		return "/usr/local/bin", ""
+	}
+}
+
+// DefaultInstallDir returns the default installation directory for appName
+// on the current platform and the given scope.
+func DefaultInstallDir(appName string, scope InstallScope) string {
+	return defaultInstallDir(appName, scope, currentScopeEnv())
+}
+
+// DefaultInstallDir returns the default installation directory for the
+// configuration: DefaultInstallDir of AppName and InstallScope, with a folder
+// for the Publisher in between when UsePublisherInPath is set, such as
+// C:\Program Files\Publisher\App.
+func (c *Config) DefaultInstallDir() string {
+	return c.defaultInstallDir(currentScopeEnv())
+}
+
+func (c *Config) defaultInstallDir(env scopeEnv) string {
+	appName := c.AppName
+	if c.UsePublisherInPath {
+		if publisher := publisherSegment(c.Publisher, appName); publisher != "" {
+			sep := "/"
+			if env.goos == "windows" {
+				sep = `\`
+			}
+			appName = publisher + sep + appName
+		}
+	}
+	return defaultInstallDir(appName, c.InstallScope, env)
+}
+
+func defaultInstallDir(appName string, scope InstallScope, env scopeEnv) string {
+	scope = resolveScope(scope, env)
+
+	home := ""
+	if env.homeDir != nil {
+		home, _ = env.homeDir()
+	}
+
+	switch env.goos {
+	case "windows":
+		if scope == ScopePerUser {
+			base := env.getenv("LOCALAPPDATA")
+			if base == "" && home != "" {
+				base = home + `\AppData\Local`
+			}
+			if base != "" {
+				return windowsJoin(base, appName)
+			}
+		}
+		base := env.getenv("ProgramFiles")
+		if base == "" {
+			base = `C:\Program Files`
+		}
+		return windowsJoin(base, appName)
+
+	case "darwin":
+		if scope == ScopePerUser && home != "" {
+			return path.Join(home, "Applications", appName)
+		}
+		return path.Join("/Applications", appName)
+
+	default:
+		name := strings.ToLower(appName)
+		if scope == ScopePerUser && home != "" {
+			return path.Join(home, ".local", name)
+		}
+		return path.Join("/opt", name)
+	}
+}
+
+// windowsJoin joins path elements with backslashes independent of the host OS
+func windowsJoin(elem ...string) string {
+	parts := make([]string, 0, len(elem))
+	for _, e := range elem {
+		e = strings.Trim(e, `\/`)
+		if e != "" {
+			parts = append(parts, e)
+		}
+	}
+	return strings.Join(parts, `\`)
+}
@@ -0,0 +1,247 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func testScopeEnv(goos string, vars map[string]string, home string, elevated bool) scopeEnv {
+	return scopeEnv{
+		goos:   goos,
+		getenv: func(key string) string { return vars[key] },
+		homeDir: func() (string, error) {
+			if home == "" {
+				return "", errors.New("no home")
+			}
+			return home, nil
+		},
+		elevated: func() bool { return elevated },
+	}
+}
+
+// TestDefaultInstallDir tests default install directories per platform and scope
+func TestDefaultInstallDir(t *testing.T) {
+	winVars := map[string]string{
+		"LOCALAPPDATA": `C:\Users\bob\AppData\Local`,
+		"ProgramFiles": `D:\Programs`,
+	}
+
+	tests := []struct {
+		name     string
+		goos     string
+		vars     map[string]string
+		home     string
+		elevated bool
+		scope    InstallScope
+		want     string
+	}{
+		{"windows per-user", "windows", winVars, `C:\Users\bob`, false, ScopePerUser, `C:\Users\bob\AppData\Local\MyApp`},
+		{"windows per-machine", "windows", winVars, `C:\Users\bob`, false, ScopePerMachine, `D:\Programs\MyApp`},
+		{"windows auto not elevated", "windows", winVars, `C:\Users\bob`, false, ScopeAuto, `C:\Users\bob\AppData\Local\MyApp`},
+		{"windows auto elevated", "windows", winVars, `C:\Users\bob`, true, ScopeAuto, `D:\Programs\MyApp`},
+		{"windows per-user without LOCALAPPDATA", "windows", nil, `C:\Users\bob`, false, ScopePerUser, `C:\Users\bob\AppData\Local\MyApp`},
+		{"windows per-machine without ProgramFiles", "windows", nil, "", true, ScopePerMachine, `C:\Program Files\MyApp`},
+
+		{"darwin per-user", "darwin", nil, "/Users/bob", false, ScopePerUser, "/Users/bob/Applications/MyApp"},
+		{"darwin per-machine", "darwin", nil, "/Users/bob", false, ScopePerMachine, "/Applications/MyApp"},
+		{"darwin auto not elevated", "darwin", nil, "/Users/bob", false, ScopeAuto, "/Users/bob/Applications/MyApp"},
+		{"darwin auto elevated", "darwin", nil, "/var/root", true, ScopeAuto, "/Applications/MyApp"},
+
+		{"linux per-user", "linux", nil, "/home/bob", false, ScopePerUser, "/home/bob/.local/myapp"},
+		{"linux per-machine", "linux", nil, "/home/bob", false, ScopePerMachine, "/opt/myapp"},
+		{"linux auto not elevated", "linux", nil, "/home/bob", false, ScopeAuto, "/home/bob/.local/myapp"},
+		{"linux auto elevated", "linux", nil, "/root", true, ScopeAuto, "/opt/myapp"},
+		{"linux per-user without home", "linux", nil, "", false, ScopePerUser, "/opt/myapp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := testScopeEnv(tt.goos, tt.vars, tt.home, tt.elevated)
+			got := defaultInstallDir("MyApp", tt.scope, env)
+			if got != tt.want {
+				t.Errorf("defaultInstallDir(%s) = %q, want %q", tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestInstallScopeString tests scope string representation
+func TestInstallScopeString(t *testing.T) {
+	tests := map[InstallScope]string{
+		ScopeAuto:       "auto",
+		ScopePerUser:    "per-user",
+		ScopePerMachine: "per-machine",
+	}
+	for scope, want := range tests {
+		if got := scope.String(); got != want {
+			t.Errorf("InstallScope(%d).String() = %q, want %q", scope, got, want)
+		}
+	}
+}
+
+// TestParseInstallScope tests parsing scope names back into scopes
+func TestParseInstallScope(t *testing.T) {
+	for _, scope := range []InstallScope{ScopeAuto, ScopePerUser, ScopePerMachine} {
+		if got, err := ParseInstallScope(scope.String()); err != nil || got != scope {
+			t.Errorf("ParseInstallScope(%q) = %v, %v, want %v", scope.String(), got, err, scope)
+		}
+	}
+	if _, err := ParseInstallScope("everyone"); err == nil {
+		t.Error("ParseInstallScope(everyone) should fail")
+	}
+}
+
+// TestSetInstallScope tests that the default install directory and PATH
+// scope follow the install scope, while a directory of the user's stays
+func TestSetInstallScope(t *testing.T) {
+	env := testScopeEnv("windows", map[string]string{
+		"LOCALAPPDATA": `C:\Users\bob\AppData\Local`,
+		"ProgramFiles": `C:\Program Files`,
+	}, `C:\Users\bob`, false)
+	config := &Config{AppName: "MyApp", PathConfig: &PathConfiguration{Enabled: true, Dirs: []string{"bin"}}}
+
+	config.setInstallScope(ScopePerMachine, env)
+	if config.InstallDir != `C:\Program Files\MyApp` || !config.PathConfig.System {
+		t.Errorf("per-machine: InstallDir %q, system PATH %v", config.InstallDir, config.PathConfig.System)
+	}
+	config.setInstallScope(ScopePerUser, env)
+	if config.InstallDir != `C:\Users\bob\AppData\Local\MyApp` || config.PathConfig.System {
+		t.Errorf("per-user: InstallDir %q, system PATH %v", config.InstallDir, config.PathConfig.System)
+	}
+
+	config.InstallDir = `D:\Tools\MyApp`
+	config.setInstallScope(ScopePerMachine, env)
+	if config.InstallDir != `D:\Tools\MyApp` {
+		t.Errorf("InstallDir = %q, want the chosen directory kept", config.InstallDir)
+	}
+	if config.InstallScope != ScopePerMachine || !config.PathConfig.System {
+		t.Errorf("scope %v, system PATH %v, want per-machine with the system PATH", config.InstallScope, config.PathConfig.System)
+	}
+}
+
+// TestInstallScopeChoice tests when the user can choose the install scope
+func TestInstallScopeChoice(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		elevated   bool
+		canElevate bool
+		want       bool
+	}{
+		{"can elevate", Config{}, false, true, true},
+		{"elevated", Config{}, true, false, true},
+		{"cannot elevate", Config{}, false, false, false},
+		{"scope configured", Config{InstallScope: ScopePerUser}, true, true, false},
+		{"portable", Config{Portable: true}, true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := testScopeEnv("linux", nil, "/home/bob", tt.elevated)
+			env.canElevate = func() bool { return tt.canElevate }
+			if got := tt.config.installScopeChoice(env); got != tt.want {
+				t.Errorf("installScopeChoice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestShortcutDirs tests where shortcuts go per platform and scope
+func TestShortcutDirs(t *testing.T) {
+	winVars := map[string]string{
+		"APPDATA":     `C:\Users\bob\AppData\Roaming`,
+		"USERPROFILE": `C:\Users\bob`,
+		"ProgramData": `C:\ProgramData`,
+		"PUBLIC":      `C:\Users\Public`,
+	}
+	tests := []struct {
+		name          string
+		goos          string
+		scope         InstallScope
+		menu, desktop string
+	}{
+		{"windows per-user", "windows", ScopePerUser,
+			`C:\Users\bob\AppData\Roaming\Microsoft\Windows\Start Menu\Programs\MyApp`, `C:\Users\bob\Desktop`},
+		{"windows per-machine", "windows", ScopePerMachine,
+			`C:\ProgramData\Microsoft\Windows\Start Menu\Programs\MyApp`, `C:\Users\Public\Desktop`},
+		{"linux per-user", "linux", ScopePerUser, "/home/bob/bin", "/home/bob/Desktop"},
+		{"linux per-machine", "linux", ScopePerMachine, "/usr/local/bin", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := "/home/bob"
+			if tt.goos == "windows" {
+				home = `C:\Users\bob`
+			}
+			config := &Config{AppName: "MyApp", InstallScope: tt.scope}
+			menu, desktop := config.shortcutDirs(testScopeEnv(tt.goos, winVars, home, false))
+			if menu != tt.menu || desktop != tt.desktop {
+				t.Errorf("shortcutDirs() = %q, %q, want %q, %q", menu, desktop, tt.menu, tt.desktop)
+			}
+		})
+	}
+}
+
+// TestDefaultInstallDirWithPublisher tests the publisher folder in default install directories
+func TestDefaultInstallDirWithPublisher(t *testing.T) {
+	winVars := map[string]string{"ProgramFiles": `C:\Program Files`}
+
+	tests := []struct {
+		name      string
+		goos      string
+		publisher string
+		appName   string
+		usePub    bool
+		want      string
+	}{
+		{"windows", "windows", "Acme Corp", "MyApp", true, `C:\Program Files\Acme Corp\MyApp`},
+		{"windows disabled", "windows", "Acme Corp", "MyApp", false, `C:\Program Files\MyApp`},
+		{"windows illegal characters", "windows", `Acme: "Tools" <EU>`, "MyApp", true, `C:\Program Files\Acme Tools EU\MyApp`},
+		{"windows trailing dot", "windows", "Acme Inc.", "MyApp", true, `C:\Program Files\Acme Inc\MyApp`},
+		{"windows reserved name", "windows", "con", "MyApp", true, `C:\Program Files\con_\MyApp`},
+		{"windows slash", "windows", "Acme/Widgets", "MyApp", true, `C:\Program Files\Acme Widgets\MyApp`},
+		{"windows empty publisher", "windows", "", "MyApp", true, `C:\Program Files\MyApp`},
+		{"windows unusable publisher", "windows", " ?*. ", "MyApp", true, `C:\Program Files\MyApp`},
+		{"windows publisher is app", "windows", "myapp", "MyApp", true, `C:\Program Files\MyApp`},
+		{"darwin", "darwin", "Acme Corp", "My App", true, "/Applications/Acme Corp/My App"},
+		{"linux", "linux", "Acme Corp", "MyApp", true, "/opt/acme corp/myapp"},
+		{"linux control characters", "linux", "Acme\tCorp\n", "MyApp", true, "/opt/acme corp/myapp"},
+		{"linux unicode", "linux", "Müller & Söhne", "MyApp", true, "/opt/müller & söhne/myapp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := testScopeEnv(tt.goos, winVars, "", true)
+			config := &Config{
+				AppName:            tt.appName,
+				Publisher:          tt.publisher,
+				UsePublisherInPath: tt.usePub,
+				InstallScope:       ScopePerMachine,
+			}
+			if got := config.defaultInstallDir(env); got != tt.want {
+				t.Errorf("defaultInstallDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSanitizePathSegment tests the sanitization rules for folder names
+func TestSanitizePathSegment(t *testing.T) {
+	tests := map[string]string{
+		"Acme":            "Acme",
+		"  Acme   Corp  ": "Acme Corp",
+		`a<b>c:d"e/f\g|h`: "a b c d e f g h",
+		"Who?*":           "Who",
+		"...hidden":       "hidden",
+		"Acme Inc. ":      "Acme Inc",
+		"AUX":             "AUX_",
+		"com1":            "com1_",
+		"COM10":           "COM10",
+		"\x00\x1f":        "",
+		"":                "",
+	}
+	for in, want := range tests {
+		if got := SanitizePathSegment(in); got != want {
+			t.Errorf("SanitizePathSegment(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -3,15 +3,29 @@ package core
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"sync"
 	"time"
 	// Import exitcodes from parent package
 	// Note: Adjust import path based on your module name
 	// "github.com/mmso2016/setupkit/pkg/installer"
 )
 
-// InstallHandler is a function type for custom installation logic
-type InstallHandler func(installPath string, components []Component) error
+// InstallHandler installs components that have no Installer of their own.
+// Installers run their handlers in sequence, e.g. a file copy followed by a
+// registration step.
+type InstallHandler interface {
+	Install(ctx context.Context, installPath string, components []Component) error
+}
+
+// InstallHandlerFunc adapts a plain function to InstallHandler
+type InstallHandlerFunc func(installPath string, components []Component) error
+
+// Install implements InstallHandler
+func (f InstallHandlerFunc) Install(ctx context.Context, installPath string, components []Component) error {
+	return f(installPath, components)
+}
 
 // Installer is the main installer implementation
 type Installer struct {
@@ -20,9 +34,57 @@ type Installer struct {
 	ui       UI
 	platform PlatformInstaller
 	rollback *RollbackManager
+	runCtx   context.Context // Cancellation context for the running installation
+
+	// Restore point begun before installing, ended when the installation ends
+	restorePoint *RestorePoint
+
+	// Problems in the configuration that were corrected, logged by Run
+	configWarnings []string
+	
+	// License acceptance audit record
+	licenseAcceptance *LicenseAcceptance
 	
-	// Custom installation handler
-	installHandler InstallHandler
+	// Manifest of an existing installation being modified
+	existing *Manifest
+
+	// Re-run state, see checkRerun
+	upToDate      bool
+	rerunManifest *Manifest
+	rerunRemove   []Component
+
+	// Manifest recording the progress of the running installation, nil if not checkpointing
+	checkpoint *Manifest
+
+	// Why the installation needs a restart, see RequireReboot
+	rebootMu      sync.Mutex
+	rebootReasons []string
+
+	// Steps that failed without stopping the installation, see PartialFailures
+	partialFailures PartialFailures
+
+	// Optional components whose precondition failed, see SkippedComponents
+	skipped []SkippedComponent
+
+	// Free space checks while installing, see recheckSpace
+	spaceProbe     SpaceProbe
+	lastSpaceCheck time.Time
+
+	// Context of the running installation and its cancellation, see CancelInstallation
+	cancelMu      sync.Mutex
+	installCtx    context.Context
+	cancelInstall context.CancelFunc
+	cancelPolicy  CancelPolicy // Of the current cancel, see CancelInstallationKeeping
+	phase         Phase        // Of the running installation, see beginPhase
+	
+	// Custom installation handlers, run in sequence
+	installHandlers []InstallHandler
+
+	// Wizard states visited so far, for diagnostics; nil without a wizard
+	stateHistory func() []string
+
+	// Handlers replacing the default ones, see SetCompletionHandler
+	completionHandlers map[CompletionActionKind]CompletionHandler
 	
 	// DFA-based wizard support (optional)
 	wizardProvider WizardProvider
@@ -123,12 +185,38 @@ func (i *Installer) IsUsingDFAWizard() bool {
 
 // Run executes the installer
 func (i *Installer) Run(ctx context.Context) error {
+	if err := i.config.LoadResponseFile(); err != nil {
+		return err
+	}
+	// A response file or the settings may have made the installation portable
+	if err := CheckPortable(i.config); err != nil {
+		return err
+	}
+
 	// Initialize context
 	if err := i.initializeContext(ctx); err != nil {
 		return fmt.Errorf("failed to initialize context: %w", err)
 	}
 
-	// Store installer reference in context for UI to use
+	for _, warning := range i.configWarnings {
+		i.context.Logger.Warn("Configuration corrected", "warning", warning)
+	}
+
+	// Nothing embedded is trusted before it is verified
+	if err := i.verifyAssets(); err != nil {
+		return err
+	}
+
+	// Modifying starts from the components of the existing installation
+	if i.config.Modify {
+		if _, err := i.LoadExistingInstall(); err != nil {
+			return fmt.Errorf("no installation to modify in %s: %w", i.config.InstallDir, err)
+		}
+	}
+
+	// Store installer reference in context for UI to use, Metadata["installer"]
+	// for UIs that predate the session
+	i.context.Session = NewInstallSession(i.config, i)
 	i.context.Metadata["installer"] = i
 
 	// Initialize DFA wizard if enabled
@@ -151,43 +239,120 @@ func (i *Installer) Run(ctx context.Context) error {
 		return fmt.Errorf("no UI factory registered - ensure UI package is imported")
 	}
 
-	ui, err := uiFactory(i.config.Mode)
+	if err := i.startUI(); err != nil {
+		return err
+	}
+	defer i.ui.Shutdown()
+
+	// Run the UI (which drives the installation flow)
+	return i.ui.Run()
+}
+
+// startUI creates and initializes the UI of the configured mode, after
+// offering to install WebView2 for the native GUI. Unless
+// DisableUIFallback is set, a GUI or browser UI that cannot start, e.g. for
+// lack of WebView2, is replaced by the CLI.
+func (i *Installer) startUI() error {
+	i.prepareWebView2(webView2Backend)
+	err := i.initUI(i.config.Mode)
+	if err == nil || i.config.DisableUIFallback || (i.config.Mode != ModeGUI && i.config.Mode != ModeBrowser) {
+		return err
+	}
+	i.context.Logger.Warn("Graphical UI unavailable, falling back to the CLI", "error", err)
+	if err := i.initUI(ModeCLI); err != nil {
+		return err
+	}
+	i.config.Mode = ModeCLI
+	return nil
+}
+
+// initUI creates the UI of mode and initializes it
+func (i *Installer) initUI(mode Mode) error {
+	ui, err := uiFactory(mode)
 	if err != nil {
 		return fmt.Errorf("failed to create UI: %w", err)
 	}
 	i.ui = ui
 	i.context.UI = ui
 
-	if err := i.ui.Initialize(i.context); err != nil {
+	if err := ui.Initialize(i.context); err != nil {
 		return fmt.Errorf("failed to initialize UI: %w", err)
 	}
-	defer i.ui.Shutdown()
-
-	// Run the UI (which drives the installation flow)
-	return i.ui.Run()
+	return nil
 }
 
 // ExecuteInstallation performs the actual installation (called by UI)
 func (i *Installer) ExecuteInstallation() error {
+	start := time.Now()
+	i.recordEvent(TelemetryInstallStarted, nil)
+	defer i.startCancelable()()
+	err := i.executeInstallation()
+	i.endRestorePoint(err)
+	i.recordResult(start, err)
+	return err
+}
+
+func (i *Installer) executeInstallation() error {
+	// Run verifies the assets before any UI starts; installations started
+	// by a controller come here without passing through Run
+	if err := i.verifyAssets(); err != nil {
+		return err
+	}
+
+	// Make sure an offline bundle is complete before changing anything
+	if err := i.verifyOfflineBundle(); err != nil {
+		return err
+	}
+
+	// An identical earlier installation leaves nothing to do
+	upToDate, err := i.checkRerun()
+	if err != nil {
+		return NewInstallError(err, PhasePreInstall, "")
+	}
+	if i.upToDate = upToDate; upToDate {
+		i.context.Logger.Info("Already installed, nothing to do", "version", i.config.Version)
+		return nil
+	}
+
 	// Pre-checks
+	i.beginPhase(PhasePreInstall)
 	if err := i.preCheck(); err != nil {
-		return fmt.Errorf("pre-check failed: %w", err)
+		return i.endPhase(NewInstallError(fmt.Errorf("pre-check failed: %w", err), PhasePreInstall, ""))
 	}
 
 	// Check elevation if needed
 	if err := i.checkElevation(); err != nil {
-		return err
+		return i.endPhase(NewInstallError(err, PhasePreInstall, ""))
+	}
+
+	// Create a restore point if requested; portable installations leave the system alone
+	if i.config.CreateRestorePoint && !i.config.Portable {
+		i.createRestorePoint()
 	}
+	i.endPhase(nil)
 
 	// Perform installation
+	i.beginPhase(PhaseFileCopy)
 	if err := i.performInstallation(); err != nil {
+		installErr := NewInstallError(err, PhaseFileCopy, "")
+		if i.keepsCancelled(err) {
+			// The checkpoint lets a run with Resume continue from here
+			i.context.Logger.Info("Installation cancelled, keeping the installed components to resume later")
+			return i.endPhase(installErr)
+		}
 		// Attempt rollback if configured
 		if i.config.Rollback != RollbackNone {
-			if rollbackErr := i.rollback.Execute(i.context); rollbackErr != nil {
-				i.context.Logger.Error("Rollback failed", "error", rollbackErr)
-			}
+			installErr.RolledBack = i.runRollback()
+			i.discardCheckpoint(i.rollback.RolledBack())
 		}
-		return err
+		return i.endPhase(installErr)
+	}
+	i.endPhase(nil)
+
+	// Record what was installed
+	i.beginPhase(PhaseRegistration)
+	if err := i.updateManifest(i.getComponentsToInstall(), i.rerunRemove); err != nil {
+		i.context.Logger.Warn("Failed to write install manifest", "error", err)
 	}
 
 	// Post-installation tasks
@@ -195,21 +360,105 @@ func (i *Installer) ExecuteInstallation() error {
 		i.context.Logger.Warn("Post-installation tasks failed", "error", err)
 		// Non-fatal, continue
 	}
+	if failures := i.PartialFailures(); len(failures) > 0 && i.config.TreatPartialFailuresAsError {
+		installErr := NewInstallError(failures, PhaseRegistration, "")
+		if i.config.Rollback != RollbackNone {
+			installErr.RolledBack = i.runRollback()
+		}
+		return i.endPhase(installErr)
+	}
+	i.endPhase(nil)
+
+	// Post-installation script
+	i.beginPhase(PhasePostInstall)
+	if err := i.runPostInstallScript(); err != nil {
+		installErr := NewInstallError(fmt.Errorf("post-install script failed: %w", err), PhasePostInstall, "")
+		if i.config.Rollback != RollbackNone {
+			installErr.RolledBack = i.runRollback()
+		}
+		return i.endPhase(installErr)
+	}
 
 	// Verification
 	if err := i.verify(); err != nil {
 		i.context.Logger.Warn("Verification failed", "error", err)
 		// Non-fatal, continue
 	}
+	i.endPhase(nil)
 
 	return nil
 }
 
+// runRollback undoes the changes made so far and reports whether it succeeded
+func (i *Installer) runRollback() bool {
+	if err := i.rollback.Execute(i.context); err != nil {
+		i.context.Logger.Error("Rollback failed", "error", err)
+		return false
+	}
+	return true
+}
+
+// createRestorePoint creates a system restore point; failures are logged but never abort
+func (i *Installer) createRestorePoint() {
+	if !RestorePointSupported() {
+		i.context.Logger.Debug("Restore points not supported on this platform")
+		return
+	}
+	description := fmt.Sprintf("Install %s %s", i.config.AppName, i.config.Version)
+	point, err := CreateRestorePoint(description)
+	if err != nil {
+		i.context.Logger.Warn("Failed to create restore point", "error", err)
+		return
+	}
+	i.restorePoint = point
+	i.context.Logger.Info("Restore point created", "description", description)
+}
+
+// endRestorePoint completes the restore point of the installation, which
+// System Restore cancels if the installation failed with err
+func (i *Installer) endRestorePoint(err error) {
+	point := i.restorePoint
+	if point == nil {
+		return
+	}
+	i.restorePoint = nil
+	end := point.End
+	if err != nil {
+		end = point.Cancel
+	}
+	if endErr := end(); endErr != nil {
+		i.context.Logger.Warn("Failed to end restore point", "error", endErr)
+	}
+}
+
+// AddConfigWarnings records problems found in the configuration and
+// corrected, e.g. by ValidateComponents, for Run to log
+func (i *Installer) AddConfigWarnings(warnings []string) {
+	i.configWarnings = append(i.configWarnings, warnings...)
+}
+
 // GetConfig returns the installer configuration
 func (i *Installer) GetConfig() *Config {
 	return i.config
 }
 
+// GetLogger returns the logger of the running installation, nil before Run
+func (i *Installer) GetLogger() Logger {
+	if i.context == nil {
+		return nil
+	}
+	return i.context.Logger
+}
+
+// ConfigProvenance returns, per setting key, the source that provided its value
+func (i *Installer) ConfigProvenance() map[string]string {
+	provenance := make(map[string]string, len(i.config.Provenance))
+	for key, source := range i.config.Provenance {
+		provenance[key] = source
+	}
+	return provenance
+}
+
 // GetComponents returns the available components
 func (i *Installer) GetComponents() []Component {
 	return i.config.Components
@@ -233,9 +482,14 @@ func (i *Installer) SetInstallPath(path string) {
 	i.config.InstallDir = path
 }
 
-// SetInstallHandler sets a custom installation handler
-func (i *Installer) SetInstallHandler(handler InstallHandler) {
-	i.installHandler = handler
+// SetInstallHandler replaces the installation handlers with handler
+func (i *Installer) SetInstallHandler(handler InstallHandlerFunc) {
+	i.installHandlers = []InstallHandler{handler}
+}
+
+// AddInstallHandler appends handlers that run after those already registered
+func (i *Installer) AddInstallHandler(handlers ...InstallHandler) {
+	i.installHandlers = append(i.installHandlers, handlers...)
 }
 
 // SetUI sets the UI for the installer
@@ -248,11 +502,18 @@ func (i *Installer) SetContext(ctx *Context) {
 	i.context = ctx
 }
 
+// SetRunContext sets the cancellation context used for the installation
+func (i *Installer) SetRunContext(ctx context.Context) {
+	i.runCtx = ctx
+}
+
 
 
 // Private methods
 
-func (i *Installer) initializeContext(_ context.Context) error {
+func (i *Installer) initializeContext(ctx context.Context) error {
+	i.runCtx = ctx
+
 	// Set up logging
 	logger := NewLogger(i.config.LogLevel, i.config.LogFile)
 	if i.config.Verbose {
@@ -287,6 +548,11 @@ func (i *Installer) preCheck() error {
 		}
 	}
 
+	// Execute pre-installation script
+	if err := i.runPreInstallScript(); err != nil {
+		return fmt.Errorf("pre-install script failed, installation aborted: %w", err)
+	}
+
 	// Platform-specific requirements
 	if i.platform != nil {
 		if err := i.platform.CheckRequirements(); err != nil {
@@ -294,12 +560,21 @@ func (i *Installer) preCheck() error {
 		}
 	}
 
+	if err := i.checkSystemRequirements(); err != nil {
+		return err
+	}
+
 	// Check disk space
 	requiredSpace := i.calculateRequiredSpace()
 	if err := CheckDiskSpace(i.config.InstallDir, requiredSpace); err != nil {
 		return err
 	}
 
+	// Payloads are staged in the temp directory before they are installed
+	if err := i.config.checkTempSpace(i.getComponentsToInstall()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -344,16 +619,36 @@ func (i *Installer) performInstallation() error {
 		return fmt.Errorf("failed to create install directory: %w", err)
 	}
 
-	// Calculate total components to install
-	componentsToInstall := i.getComponentsToInstall()
+	// Drop what a changed configuration no longer selects
+	if err := i.removeDeselected(); err != nil {
+		return err
+	}
+
+	components, err := InstallOrder(i.getComponentsToInstall())
+	if err != nil {
+		return NewInstallError(err, PhaseFileCopy, "")
+	}
+	if !i.config.DryRun {
+		components = i.startCheckpoint(components)
+	}
+	return i.installComponents(components)
+}
 
+// installComponents installs the given components in order
+func (i *Installer) installComponents(componentsToInstall []Component) error {
 	// Create progress tracker
 	progress := &Progress{
 		TotalComponents: len(componentsToInstall),
+		Phase:           i.phase,
 	}
 
 	// Install components
+	i.skipped = nil
 	for idx, component := range componentsToInstall {
+		if err := i.checkCancelled(); err != nil {
+			return NewInstallError(err, PhaseFileCopy, "")
+		}
+
 		progress.CurrentComponent = idx + 1
 		progress.ComponentName = component.Name
 		progress.ComponentProgress = 0
@@ -365,33 +660,47 @@ func (i *Installer) performInstallation() error {
 			i.context.Logger.Warn("Failed to update progress", "error", err)
 		}
 
+		// Components that cannot be installed here are skipped, or stop the installation
+		skip, err := i.checkPrecondition(i.componentContext(), component)
+		if err != nil {
+			return err
+		}
+		if skip {
+			progress.Message = fmt.Sprintf("Skipped %s: %s", component.Name, i.skipped[len(i.skipped)-1].Reason)
+			progress.OverallProgress = float64(idx+1) / float64(len(componentsToInstall))
+			i.ui.ShowProgress(progress)
+			continue
+		}
+
+		// Another program may have used the space checked before installing
+		if err := i.recheckSpace(SelectedSize(componentsToInstall[idx:]), component.ID); err != nil {
+			return err
+		}
+
 		// Validate component
 		if component.Validator != nil {
 			if err := component.Validator(); err != nil {
-				return fmt.Errorf("component validation failed for %s: %w", component.ID, err)
+				return NewInstallError(fmt.Errorf("component validation failed for %s: %w", component.ID, err),
+					PhaseFileCopy, component.ID)
 			}
 		}
 
 		// Add rollback checkpoint
-		i.rollback.AddCheckpoint(component.ID, component.Uninstaller)
-
-		// Install component
-		// Create a context with all necessary values for the component
-		compCtx := context.WithValue(context.Background(), contextKey("installer_context"), i.context)
-		compCtx = context.WithValue(compCtx, contextKey("logger"), i.context.Logger)
-		compCtx = context.WithValue(compCtx, contextKey("config"), i.config)
-		compCtx = context.WithValue(compCtx, contextKey("platform"), i.platform)
-		compCtx = context.WithValue(compCtx, contextKey("assets"), i.config.Assets)
-
-		// Install component using either component-specific installer or custom handler
-		var installErr error
-		if component.Installer != nil {
-			installErr = component.Installer(compCtx)
-		} else if i.installHandler != nil {
-			// Use custom install handler for single component
-			installErr = i.installHandler(i.config.InstallDir, []Component{component})
+		postInstalled := false
+		i.rollback.AddCheckpoint(component.ID, i.componentRollback(component, &postInstalled))
+
+		// Install component, letting it report its own progress
+		reporter := &componentProgress{installer: i, progress: progress, index: idx}
+		compCtx := context.WithValue(i.componentContext(), contextKey("progress"), reporter)
+
+		installErr := i.installComponent(compCtx, component, progress, reporter, &postInstalled)
+		if installErr != nil {
+			// A component failing because it was cancelled is no error to retry
+			if err := i.checkCancelled(); err != nil {
+				return NewInstallError(err, PhaseFileCopy, component.ID)
+			}
 		}
-		
+
 		if installErr != nil {
 			progress.IsError = true
 			progress.Message = fmt.Sprintf("Failed to install %s", component.Name)
@@ -400,15 +709,25 @@ func (i *Installer) performInstallation() error {
 			// Ask user if they want to retry
 			retry, _ := i.ui.ShowError(installErr, true)
 			if !retry {
-				return fmt.Errorf("component installation failed for %s: %w", component.ID, installErr)
+				return NewInstallError(fmt.Errorf("component installation failed for %s: %w", component.ID, installErr),
+					PhaseFileCopy, component.ID)
 			}
 			// TODO: Implement retry logic
+		} else {
+			if component.RebootRequired {
+				i.RequireReboot(fmt.Sprintf("%s was installed", component.Name))
+			}
+			i.recordEvent(TelemetryComponentInstalled, map[string]interface{}{"component": component.ID})
+			i.recordCheckpoint(component)
 		}
 
 		progress.ComponentProgress = 1.0
 		progress.OverallProgress = float64(idx+1) / float64(len(componentsToInstall))
 		i.ui.ShowProgress(progress)
 	}
+	if err := i.checkCancelled(); err != nil {
+		return NewInstallError(err, PhaseFileCopy, "")
+	}
 
 	progress.OverallProgress = 1.0
 	progress.Message = "Installation complete"
@@ -417,7 +736,127 @@ func (i *Installer) performInstallation() error {
 	return nil
 }
 
+// installComponent installs a component using its own installer, the custom
+// install handlers or the payload source, followed by its post-install actions
+func (i *Installer) installComponent(ctx context.Context, component Component, progress *Progress, reporter ProgressReporter, postInstalled *bool) error {
+	installErr := i.runStep(ctx, component, "install", component.Retry, func(ctx context.Context) error {
+		if component.Installer != nil {
+			return component.Installer(ctx)
+		} else if len(i.installHandlers) > 0 {
+			// Use the custom install handlers for single component
+			return i.runInstallHandlers(ctx, component)
+		} else if sources := i.config.payloadSources(); len(sources) > 0 {
+			// Copy the component's files from the payload source or bundle directory
+			return copyComponentFiles(sources, i.config.InstallDir, component, reporter, func(size int64) error {
+				if size < LargeFileSize {
+					return nil
+				}
+				return i.recheckSpace(size, component.ID)
+			})
+		}
+		return nil
+	})
+
+	// Post-install actions once the files are in place
+	if installErr == nil && component.PostInstall != nil {
+		progress.ComponentProgress = 0.9
+		progress.Message = fmt.Sprintf("Configuring %s...", component.Name)
+		i.ui.ShowProgress(progress)
+		i.context.Logger.Info("Running post-install actions", "component", component.ID)
+
+		*postInstalled = true
+		err := i.runStep(ctx, component, "post-install", component.PostInstallRetry, func(ctx context.Context) error {
+			return component.PostInstall(ctx, i.config.InstallDir)
+		})
+		if err != nil {
+			installErr = fmt.Errorf("post-install actions failed: %w", err)
+		}
+	}
+	return installErr
+}
+
+// runStep runs a step of installing component, repeated as policy says while
+// it fails; a nil policy runs it once. Every failed attempt is logged, and
+// once the attempts are used up the last error is returned.
+func (i *Installer) runStep(ctx context.Context, component Component, step string, policy *RetryPolicy, fn func(ctx context.Context) error) error {
+	if policy == nil {
+		return fn(ctx)
+	}
+	retried := *policy
+	retried.OnRetry = func(attempt int, err error, delay time.Duration) {
+		i.context.Logger.Warn("Install step failed, retrying", "component", component.ID, "step", step,
+			"attempt", attempt, "delay", delay, "error", err)
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, delay)
+		}
+	}
+	return Retry(ctx, retried, fn)
+}
+
+// componentRollback returns the rollback for a component: PostUninstall, if
+// its PostInstall ran, followed by the component's Uninstaller
+func (i *Installer) componentRollback(component Component, postInstalled *bool) func(ctx context.Context) error {
+	if component.Uninstaller == nil && component.PostUninstall == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		if *postInstalled && component.PostUninstall != nil {
+			if err := component.PostUninstall(ctx, i.config.InstallDir); err != nil {
+				return fmt.Errorf("post-uninstall actions failed: %w", err)
+			}
+		}
+		if component.Uninstaller != nil {
+			return component.Uninstaller(ctx)
+		}
+		return nil
+	}
+}
+
+// runInstallHandlers installs component with each handler in turn, stopping at the first failure
+func (i *Installer) runInstallHandlers(ctx context.Context, component Component) error {
+	for idx, handler := range i.installHandlers {
+		if err := handler.Install(ctx, i.config.InstallDir, []Component{component}); err != nil {
+			return fmt.Errorf("install handler %d failed: %w", idx+1, err)
+		}
+	}
+	return nil
+}
+
+// componentContext creates a context with all necessary values for component callbacks
+func (i *Installer) componentContext() context.Context {
+	compCtx := context.WithValue(i.runContext(), contextKey("installer_context"), i.context)
+	compCtx = context.WithValue(compCtx, contextKey("installer"), i)
+	compCtx = context.WithValue(compCtx, contextKey("logger"), i.context.Logger)
+	compCtx = context.WithValue(compCtx, contextKey("config"), i.config)
+	compCtx = context.WithValue(compCtx, contextKey("platform"), i.platform)
+	compCtx = context.WithValue(compCtx, contextKey("assets"), i.config.PayloadSource())
+	return withFeatures(compCtx, i.config.Features)
+}
+
+// ConfigFromContext returns the configuration passed to install handlers and
+// component installers, nil outside an installation
+func ConfigFromContext(ctx context.Context) *Config {
+	config, _ := ctx.Value(contextKey("config")).(*Config)
+	return config
+}
+
+// PlatformFromContext returns the platform installer passed to install
+// handlers and component installers, nil outside an installation
+func PlatformFromContext(ctx context.Context) PlatformInstaller {
+	platform, _ := ctx.Value(contextKey("platform")).(PlatformInstaller)
+	return platform
+}
+
 func (i *Installer) postInstall() error {
+	// Portable installations keep their settings in InstallDir instead of
+	// registering with the system
+	if i.config.Portable {
+		if err := i.writePortableConfig(); err != nil {
+			return err
+		}
+		return i.afterInstall()
+	}
+
 	if i.platform == nil {
 		return nil
 	}
@@ -427,33 +866,73 @@ func (i *Installer) postInstall() error {
 		return fmt.Errorf("OS registration failed: %w", err)
 	}
 
+	// Failures of the steps below leave the installation usable, they are
+	// recorded as PartialFailures
+	i.partialFailures = nil
+
 	// Update PATH if configured
 	if i.config.PathConfig != nil && i.config.PathConfig.Enabled {
-		if err := i.platform.UpdatePath(i.config.PathConfig.Dirs, i.config.PathConfig.System); err != nil {
-			i.context.Logger.Warn("Failed to update PATH", "error", err)
-		}
+		i.recordPartialFailure("update PATH", i.platform.UpdatePath(i.config.PathConfig.Dirs, i.config.PathConfig.System))
+		i.recordPartialFailure("write environment file", i.writeEnvFile())
 	}
 
 	// Create shortcuts
-	if err := i.platform.CreateShortcuts(); err != nil {
-		i.context.Logger.Warn("Failed to create shortcuts", "error", err)
-	}
+	i.recordPartialFailure("create shortcuts", i.platform.CreateShortcuts())
 
 	// Register uninstaller
-	if err := i.platform.RegisterUninstaller(); err != nil {
-		i.context.Logger.Warn("Failed to register uninstaller", "error", err)
-	}
+	i.recordPartialFailure("register uninstaller", i.platform.RegisterUninstaller())
+
+	i.collectRebootReasons()
+
+	return i.afterInstall()
+}
 
-	// Execute post-installation callback
+// afterInstall executes the post-installation callback
+func (i *Installer) afterInstall() error {
 	if i.config.AfterInstall != nil {
 		if err := i.config.AfterInstall(); err != nil {
 			return fmt.Errorf("post-installation callback failed: %w", err)
 		}
 	}
-
 	return nil
 }
 
+// runContext returns the cancellation context for the installation
+func (i *Installer) runContext() context.Context {
+	i.cancelMu.Lock()
+	defer i.cancelMu.Unlock()
+	if i.installCtx != nil {
+		return i.installCtx
+	}
+	if i.runCtx != nil {
+		return i.runCtx
+	}
+	return context.Background()
+}
+
+func (i *Installer) runPreInstallScript() error {
+	if i.config.PreInstallScript == nil || i.config.DryRun {
+		return nil
+	}
+	// The install directory usually doesn't exist yet
+	workDir := ""
+	if info, err := os.Stat(i.config.InstallDir); err == nil && info.IsDir() {
+		workDir = i.config.InstallDir
+	}
+	i.context.Logger.Info("Running pre-install script")
+	script := *i.config.PreInstallScript
+	script.IgnoreFailure = false // A failing pre-install script always aborts
+	return RunScript(i.runContext(), &script, workDir, i.scriptVariables(), i.context.Logger)
+}
+
+func (i *Installer) runPostInstallScript() error {
+	if i.config.PostInstallScript == nil || i.config.DryRun {
+		return nil
+	}
+	i.context.Logger.Info("Running post-install script")
+	return RunScript(i.runContext(), i.config.PostInstallScript, i.config.InstallDir, i.scriptVariables(), i.context.Logger)
+}
+
 func (i *Installer) verify() error {
 	// Basic verification - check if main files exist
 	// TODO: Implement verification logic
@@ -461,20 +940,13 @@ func (i *Installer) verify() error {
 }
 
 func (i *Installer) calculateRequiredSpace() int64 {
-	var total int64
-	for _, component := range i.config.Components {
-		if component.Selected || component.Required {
-			total += component.Size
-		}
-	}
-	// Add 20% buffer
-	return int64(float64(total) * 1.2)
+	return withSpaceReserve(SelectedSize(i.config.Components))
 }
 
 func (i *Installer) getComponentsToInstall() []Component {
 	var components []Component
 	for _, c := range i.config.Components {
-		if c.Selected || c.Required {
+		if (c.Selected || c.Required) && c.PortableCapable(i.config.Portable) {
 			components = append(components, c)
 		}
 	}
@@ -483,22 +955,49 @@ func (i *Installer) getComponentsToInstall() []Component {
 
 // CreateSummary creates an installation summary
 func (i *Installer) CreateSummary() *InstallSummary {
-	duration := time.Since(i.context.StartTime)
+	// Without a run, e.g. after a dry run, there is no duration
+	var duration time.Duration
+	if i.context != nil {
+		duration = time.Since(i.context.StartTime)
+	}
 
 	var installed []string
 	for _, c := range i.getComponentsToInstall() {
 		installed = append(installed, c.Name)
 	}
 
+	nextSteps := []string{
+		fmt.Sprintf("Application installed to: %s", i.config.InstallDir),
+		"You can now start using the application",
+	}
+	reasons := i.RebootReasons()
+	if len(reasons) > 0 {
+		nextSteps[1] = "Restart the computer to complete the installation"
+	}
+	failures := i.PartialFailures()
+	var warnings []string
+	for _, s := range i.skipped {
+		warnings = append(warnings, fmt.Sprintf("Skipped %s: %s", s.Name, s.Reason))
+	}
+	for _, failure := range failures {
+		warnings = append(warnings, "Failed to "+failure.Error())
+	}
+
 	return &InstallSummary{
 		Success:             true,
 		Duration:            duration,
 		ComponentsInstalled: installed,
 		InstallPath:         i.config.InstallDir,
-		NextSteps: []string{
-			fmt.Sprintf("Application installed to: %s", i.config.InstallDir),
-			"You can now start using the application",
-		},
+		LicenseAcceptance:   i.licenseAcceptance,
+		Actions:             CompletionActions(i.config),
+		UpToDate:            i.upToDate,
+		RebootRequired:      len(reasons) > 0,
+		RebootReasons:       reasons,
+		PartialFailures:     failures,
+		Features:            maps.Clone(i.config.Features),
+		ComponentsSkipped:   i.SkippedComponents(),
+		Warnings:            warnings,
+		NextSteps:           nextSteps,
 	}
 }
 
@@ -0,0 +1,53 @@
+package core
+
+import "strings"
+
+// ApplicableLicense is a license text that must be accepted for an installation
+type ApplicableLicense struct {
+	ComponentID string // Component the license belongs to; empty for the global license
+	Title       string
+	Text        string
+}
+
+// Hash returns the SHA-256 hash of the license text
+func (l ApplicableLicense) Hash() string {
+	return HashLicense(l.Text)
+}
+
+// ApplicableLicenses returns the global license followed by the licenses of the
+// given components. Identical license texts are only listed once.
+func ApplicableLicenses(config *Config, selected []Component) []ApplicableLicense {
+	var licenses []ApplicableLicense
+	seen := make(map[string]bool)
+
+	add := func(l ApplicableLicense) {
+		if strings.TrimSpace(l.Text) == "" || seen[l.Text] {
+			return
+		}
+		seen[l.Text] = true
+		licenses = append(licenses, l)
+	}
+
+	add(ApplicableLicense{Title: config.AppName + " License", Text: config.License})
+	for _, c := range selected {
+		add(ApplicableLicense{ComponentID: c.ID, Title: c.Name + " License", Text: c.License})
+	}
+	return licenses
+}
+
+// CombineLicenses concatenates licenses for display. A lone global license is
+// returned unchanged.
+func CombineLicenses(licenses []ApplicableLicense) string {
+	if len(licenses) == 1 && licenses[0].ComponentID == "" {
+		return licenses[0].Text
+	}
+	var b strings.Builder
+	for i, l := range licenses {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString("=== " + l.Title + " ===\n\n")
+		b.WriteString(strings.TrimRight(l.Text, "\n"))
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/user"
+	"time"
+)
+
+// LicenseAcceptance is the audit record of a license acceptance
+type LicenseAcceptance struct {
+	LicenseHash  string    // SHA-256 of the license text, hex encoded
+	AcceptedAt   time.Time // When the license was accepted
+	User         string    // OS user who accepted the license
+	AutoAccepted bool      // Accepted through configuration (silent/unattended) instead of by the user
+}
+
+// NewLicenseAcceptance creates an acceptance record for the given license text
+func NewLicenseAcceptance(license string, autoAccepted bool) *LicenseAcceptance {
+	return &LicenseAcceptance{
+		LicenseHash:  HashLicense(license),
+		AcceptedAt:   time.Now().UTC(),
+		User:         currentUserName(),
+		AutoAccepted: autoAccepted,
+	}
+}
+
+// HashLicense returns the hex encoded SHA-256 hash of a license text
+func HashLicense(license string) string {
+	sum := sha256.Sum256([]byte(license))
+	return hex.EncodeToString(sum[:])
+}
+
+// currentUserName returns the name of the OS user running the installer
+func currentUserName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	for _, key := range []string{"USER", "USERNAME", "LOGNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// RecordLicenseAcceptance records that the license text was accepted. autoAccepted
+// marks acceptance through configuration rather than by the user.
+func (i *Installer) RecordLicenseAcceptance(license string, autoAccepted bool) {
+	i.licenseAcceptance = NewLicenseAcceptance(license, autoAccepted)
+	if i.context != nil && i.context.Logger != nil {
+		i.context.Logger.Info("License accepted",
+			"hash", i.licenseAcceptance.LicenseHash,
+			"user", i.licenseAcceptance.User,
+			"auto", autoAccepted)
+	}
+}
+
+// GetLicenseAcceptance returns the license acceptance record, or nil if none was recorded
+func (i *Installer) GetLicenseAcceptance() *LicenseAcceptance {
+	return i.licenseAcceptance
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultLocale is the locale of the installer screens unless another is
+// chosen, and the fallback for messages missing from a catalog
+const DefaultLocale = "en"
+
+// Message keys of the built-in catalogs. Messages with a %s verb receive
+// the arguments noted.
+const (
+	MsgLanguageName     = "language.name" // Name of the language in itself, e.g. "Deutsch"
+	MsgLanguageTitle    = "language.title"
+	MsgLanguagePrompt   = "language.prompt"
+	MsgWelcomeTitle     = "welcome.title"     // Application name
+	MsgWelcomeHeading   = "welcome.heading"   // Application name
+	MsgWelcomeVersion   = "welcome.version"   // Version
+	MsgWelcomePublisher = "welcome.publisher" // Publisher
+	MsgWelcomeIntro     = "welcome.intro"     // Application name
+	MsgWelcomeGuide     = "welcome.guide"
+	MsgWelcomeContinue  = "welcome.continue" // Next and Cancel button labels
+	MsgPromptContinue   = "prompt.continue"  // Next button label
+	MsgButtonNext       = "button.next"
+	MsgButtonBack       = "button.back"
+	MsgButtonCancel     = "button.cancel"
+)
+
+// Catalog maps message keys to the messages of one locale
+type Catalog map[string]string
+
+// builtinCatalogs holds the messages shipped with the installer
+var builtinCatalogs = map[string]Catalog{
+	"en": {
+		MsgLanguageName:     "English",
+		MsgLanguageTitle:    "Language",
+		MsgLanguagePrompt:   "Choose the language of the installer.",
+		MsgWelcomeTitle:     "%s Setup",
+		MsgWelcomeHeading:   "Welcome to %s Setup",
+		MsgWelcomeVersion:   "Version: %s",
+		MsgWelcomePublisher: "Publisher: %s",
+		MsgWelcomeIntro:     "Welcome to the %s installation wizard.",
+		MsgWelcomeGuide:     "This wizard will guide you through the installation process.",
+		MsgWelcomeContinue:  "Click %s to continue or %s to exit the installer.",
+		MsgPromptContinue:   "Press Enter for %s or 'q' to quit...",
+		MsgButtonNext:       "Next",
+		MsgButtonBack:       "Back",
+		MsgButtonCancel:     "Cancel",
+	},
+	"de": {
+		MsgLanguageName:     "Deutsch",
+		MsgLanguageTitle:    "Sprache",
+		MsgLanguagePrompt:   "Wählen Sie die Sprache des Installationsprogramms.",
+		MsgWelcomeTitle:     "Installation von %s",
+		MsgWelcomeHeading:   "Willkommen bei der Installation von %s",
+		MsgWelcomeVersion:   "Version: %s",
+		MsgWelcomePublisher: "Herausgeber: %s",
+		MsgWelcomeIntro:     "Willkommen beim Installationsassistenten von %s.",
+		MsgWelcomeGuide:     "Der Assistent führt Sie durch die Installation.",
+		MsgWelcomeContinue:  "Klicken Sie auf %s, um fortzufahren, oder auf %s, um das Installationsprogramm zu beenden.",
+		MsgPromptContinue:   "Drücken Sie die Eingabetaste für %s oder 'q' zum Beenden...",
+		MsgButtonNext:       "Weiter",
+		MsgButtonBack:       "Zurück",
+		MsgButtonCancel:     "Abbrechen",
+	},
+}
+
+// Localizer translates the messages of the installer screens into the
+// current locale. The built-in catalogs cover the language selection, the
+// welcome screen and the button labels; the other screens are English. It is safe for concurrent use; a nil Localizer translates
+// into DefaultLocale.
+type Localizer struct {
+	mu       sync.RWMutex
+	catalogs map[string]Catalog
+	locale   string
+}
+
+// NewLocalizer creates a localizer for the built-in catalogs and
+// config.Catalogs, whose messages take precedence. It starts with
+// config.Locale, DefaultLocale if unset.
+func NewLocalizer(config *Config) *Localizer {
+	l := &Localizer{catalogs: make(map[string]Catalog), locale: DefaultLocale}
+	for locale, catalog := range builtinCatalogs {
+		l.add(locale, catalog)
+	}
+	if config == nil {
+		return l
+	}
+	for locale, catalog := range config.Catalogs {
+		l.add(locale, catalog)
+	}
+	if config.Locale != "" {
+		l.locale = config.Locale
+	}
+	return l
+}
+
+// add merges catalog into the messages of locale
+func (l *Localizer) add(locale string, catalog Catalog) {
+	merged := l.catalogs[locale]
+	if merged == nil {
+		merged = make(Catalog, len(catalog))
+		l.catalogs[locale] = merged
+	}
+	for key, message := range catalog {
+		merged[key] = message
+	}
+}
+
+// Locale returns the current locale
+func (l *Localizer) Locale() string {
+	if l == nil {
+		return DefaultLocale
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.locale
+}
+
+// SetLocale switches to locale, which needs a catalog. Messages requested
+// afterwards are translated into it.
+func (l *Localizer) SetLocale(locale string) error {
+	if !l.HasLocale(locale) {
+		return fmt.Errorf("no message catalog for locale %q", locale)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.locale = locale
+	return nil
+}
+
+// HasLocale reports whether there is a catalog for locale
+func (l *Localizer) HasLocale(locale string) bool {
+	if l == nil {
+		_, ok := builtinCatalogs[locale]
+		return ok
+	}
+	_, ok := l.catalogs[locale]
+	return ok
+}
+
+// Name returns the name of the language of locale in that language, e.g.
+// "Deutsch" for "de", or the locale itself if its catalog has no name
+func (l *Localizer) Name(locale string) string {
+	if name := l.lookup(locale, MsgLanguageName); name != "" {
+		return name
+	}
+	return locale
+}
+
+// T returns the message for key in the current locale, formatted with args
+// if there are any. Messages missing from the catalog are taken from
+// DefaultLocale; unknown keys are returned as they are.
+func (l *Localizer) T(key string, args ...interface{}) string {
+	message := l.lookup(l.Locale(), key)
+	if message == "" {
+		message = l.lookup(DefaultLocale, key)
+	}
+	if message == "" {
+		message = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// lookup returns the message for key in the catalog of locale
+func (l *Localizer) lookup(locale, key string) string {
+	if l == nil {
+		return builtinCatalogs[locale][key]
+	}
+	return l.catalogs[locale][key]
+}
+
+// AvailableLocales returns the locales the installer offers: config.Locales
+// that have a built-in or configured catalog, or, if none are set,
+// DefaultLocale and the locales of config.Catalogs
+func AvailableLocales(config *Config) []string {
+	l := NewLocalizer(config)
+	var locales []string
+	seen := make(map[string]bool)
+	addLocale := func(locale string) {
+		if !seen[locale] && l.HasLocale(locale) {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+	if len(config.Locales) > 0 {
+		for _, locale := range config.Locales {
+			addLocale(locale)
+		}
+		return locales
+	}
+	addLocale(DefaultLocale)
+	configured := make([]string, 0, len(config.Catalogs))
+	for locale := range config.Catalogs {
+		configured = append(configured, locale)
+	}
+	sort.Strings(configured)
+	for _, locale := range configured {
+		addLocale(locale)
+	}
+	return locales
+}
@@ -0,0 +1,78 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func TestLocalizer(t *testing.T) {
+	l := core.NewLocalizer(&core.Config{
+		Catalogs: map[string]core.Catalog{
+			"de": {core.MsgButtonNext: "Vorwärts"},
+			"fr": {core.MsgWelcomeIntro: "Bienvenue dans l'assistant d'installation de %s."},
+		},
+	})
+	if got := l.T(core.MsgButtonNext); got != "Next" {
+		t.Errorf("T() = %q, want the English default", got)
+	}
+
+	if err := l.SetLocale("de"); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.T(core.MsgButtonNext); got != "Vorwärts" {
+		t.Errorf("T() = %q, want the configured message", got)
+	}
+	if got := l.T(core.MsgButtonCancel); got != "Abbrechen" {
+		t.Errorf("T() = %q, want the built-in German message", got)
+	}
+
+	if err := l.SetLocale("fr"); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.T(core.MsgWelcomeIntro, "App"); got != "Bienvenue dans l'assistant d'installation de App." {
+		t.Errorf("T() = %q, want the formatted French message", got)
+	}
+	if got := l.T(core.MsgButtonBack); got != "Back" {
+		t.Errorf("T() = %q, want the English fallback", got)
+	}
+	if got := l.T("no.such.key"); got != "no.such.key" {
+		t.Errorf("T() = %q, want the key", got)
+	}
+	if got := l.Name("fr"); got != "fr" {
+		t.Errorf("Name() = %q, want the locale for a catalog without a name", got)
+	}
+
+	if err := l.SetLocale("xx"); err == nil {
+		t.Error("SetLocale() should fail without a catalog")
+	}
+	if l.Locale() != "fr" {
+		t.Errorf("Locale() = %q after a failed switch, want fr", l.Locale())
+	}
+
+	var none *core.Localizer
+	if none.T(core.MsgButtonNext) != "Next" || none.Locale() != core.DefaultLocale {
+		t.Error("a nil localizer should translate into the default locale")
+	}
+}
+
+func TestAvailableLocales(t *testing.T) {
+	tests := []struct {
+		name   string
+		config core.Config
+		want   []string
+	}{
+		{"default", core.Config{}, []string{"en"}},
+		{"configured", core.Config{Locales: []string{"de", "en", "de"}}, []string{"de", "en"}},
+		{"without catalog", core.Config{Locales: []string{"en", "xx"}}, []string{"en"}},
+		{"from catalogs", core.Config{Catalogs: map[string]core.Catalog{"fr": {}, "de": {}}}, []string{"en", "de", "fr"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := core.AvailableLocales(&tt.config); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AvailableLocales() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -154,6 +154,9 @@ func (l *SimpleLogger) log(level string, msg string, keysAndValues ...interface{
 		for i := 0; i < len(keysAndValues)-1; i += 2 {
 			key := fmt.Sprintf("%v", keysAndValues[i])
 			value := fmt.Sprintf("%v", keysAndValues[i+1])
+			if isSecretKey(key) {
+				value = redacted
+			}
 			pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
 		}
 		if len(pairs) > 0 {
@@ -164,6 +167,21 @@ func (l *SimpleLogger) log(level string, msg string, keysAndValues ...interface{
 	l.logger.Printf("[%s] %s%s", level, msg, extras)
 }
 
+// redacted replaces logged values of secret keys
+const redacted = "[REDACTED]"
+
+// isSecretKey reports whether values logged under key are secrets, like
+// passwords, which must not be written to the log
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range []string{"password", "passwd", "secret", "token"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
 // NullLogger is a logger that discards all output
 type NullLogger struct{}
 
@@ -0,0 +1,34 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestLoggerRedactsSecrets tests that values of secret keys never reach the log
+func TestLoggerRedactsSecrets(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "install.log")
+	logger := core.NewLogger("debug", logFile)
+	logger.Info("Connecting", "host", "db.example.com", "password", "s3cret", "DB_Password", "s3cret", "apiToken", "s3cret")
+	if closer, ok := logger.(interface{ Close() error }); ok {
+		closer.Close()
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	log := string(data)
+	if strings.Contains(log, "s3cret") {
+		t.Errorf("log contains a secret: %s", log)
+	}
+	for _, want := range []string{"host=db.example.com", "password=[REDACTED]", "DB_Password=[REDACTED]", "apiToken=[REDACTED]"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log lacks %q: %s", want, log)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/png"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Limits for logos shown in installer headers
+const (
+	MaxLogoSize      = 1 << 20 // Bytes
+	MinLogoDimension = 16      // Pixels, per side
+	MaxLogoDimension = 4096    // Pixels, per side
+)
+
+// Logo is an image shown in the header of the GUI and SSR pages, see LoadLogo
+type Logo struct {
+	Data     []byte
+	MIMEType string // "image/png" or "image/svg+xml"
+	Width    int    // Intrinsic size in pixels, zero for SVGs without one
+	Height   int
+}
+
+// LoadLogo reads a PNG or SVG logo from fsys and checks its format and size
+func LoadLogo(fsys fs.FS, path string) (*Logo, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logo: %w", err)
+	}
+	if len(data) > MaxLogoSize {
+		return nil, fmt.Errorf("logo %s is %d bytes, at most %d are allowed", path, len(data), MaxLogoSize)
+	}
+
+	logo := &Logo{Data: data}
+	if http.DetectContentType(data) == "image/png" {
+		cfg, err := png.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid PNG logo %s: %w", path, err)
+		}
+		logo.MIMEType, logo.Width, logo.Height = "image/png", cfg.Width, cfg.Height
+	} else {
+		width, height, err := svgSize(data)
+		if err != nil {
+			return nil, fmt.Errorf("logo %s is neither PNG nor SVG: %w", path, err)
+		}
+		logo.MIMEType, logo.Width, logo.Height = "image/svg+xml", width, height
+		if width == 0 || height == 0 {
+			// Scales to the space the header gives it
+			return logo, nil
+		}
+	}
+
+	for _, side := range []int{logo.Width, logo.Height} {
+		if side < MinLogoDimension || side > MaxLogoDimension {
+			return nil, fmt.Errorf("logo %s is %dx%d pixels, each side must be between %d and %d",
+				path, logo.Width, logo.Height, MinLogoDimension, MaxLogoDimension)
+		}
+	}
+	return logo, nil
+}
+
+// DataURI returns the logo as a data URI for img elements
+func (l *Logo) DataURI() string {
+	return "data:" + l.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(l.Data)
+}
+
+// Image decodes a PNG logo; SVG logos cannot be decoded
+func (l *Logo) Image() (image.Image, error) {
+	if l.MIMEType != "image/png" {
+		return nil, fmt.Errorf("cannot decode %s logo", l.MIMEType)
+	}
+	return png.Decode(bytes.NewReader(l.Data))
+}
+
+// svgSize checks that data is an SVG document and returns its size from the
+// width and height attributes or the viewBox, zero if it has none
+func svgSize(data []byte) (width, height int, err error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, 0, fmt.Errorf("no svg element found")
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "svg" {
+			return 0, 0, fmt.Errorf("root element is %s, not svg", start.Name.Local)
+		}
+
+		var viewBox string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "width":
+				width = svgLength(attr.Value)
+			case "height":
+				height = svgLength(attr.Value)
+			case "viewBox":
+				viewBox = attr.Value
+			}
+		}
+		if (width == 0 || height == 0) && viewBox != "" {
+			if box := strings.Fields(strings.ReplaceAll(viewBox, ",", " ")); len(box) == 4 {
+				width, height = svgLength(box[2]), svgLength(box[3])
+			}
+		}
+		return width, height, nil
+	}
+}
+
+// svgLength converts a length in pixels, like "64" or "64px", to an int;
+// relative lengths such as "100%" give zero
+func svgLength(value string) int {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "px"), 64)
+	if err != nil {
+		return 0
+	}
+	return int(f + 0.5)
+}
@@ -0,0 +1,71 @@
+package core_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func pngData(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadLogo(t *testing.T) {
+	fsys := fstest.MapFS{
+		"logo.png":     {Data: pngData(t, 64, 32)},
+		"tiny.png":     {Data: pngData(t, 4, 4)},
+		"logo.svg":     {Data: []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 120 40"><rect width="120" height="40"/></svg>`)},
+		"scalable.svg": {Data: []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="100%"><circle r="5"/></svg>`)},
+		"huge.svg":     {Data: []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="8000px" height="100px"></svg>`)},
+		"page.html":    {Data: []byte(`<html><body>not a logo</body></html>`)},
+		"readme.txt":   {Data: []byte("not a logo")},
+	}
+
+	tests := []struct {
+		path    string
+		mime    string
+		width   int
+		height  int
+		wantErr string
+	}{
+		{path: "logo.png", mime: "image/png", width: 64, height: 32},
+		{path: "logo.svg", mime: "image/svg+xml", width: 120, height: 40},
+		{path: "scalable.svg", mime: "image/svg+xml"},
+		{path: "tiny.png", wantErr: "4x4 pixels"},
+		{path: "huge.svg", wantErr: "8000x100 pixels"},
+		{path: "page.html", wantErr: "neither PNG nor SVG"},
+		{path: "readme.txt", wantErr: "neither PNG nor SVG"},
+		{path: "missing.png", wantErr: "failed to read logo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			logo, err := core.LoadLogo(fsys, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadLogo() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadLogo() failed: %v", err)
+			}
+			if logo.MIMEType != tt.mime || logo.Width != tt.width || logo.Height != tt.height {
+				t.Errorf("LoadLogo() = %s %dx%d, want %s %dx%d",
+					logo.MIMEType, logo.Width, logo.Height, tt.mime, tt.width, tt.height)
+			}
+			if !strings.HasPrefix(logo.DataURI(), "data:"+tt.mime+";base64,") {
+				t.Errorf("DataURI() = %.40s...", logo.DataURI())
+			}
+		})
+	}
+}
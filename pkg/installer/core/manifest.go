@@ -0,0 +1,250 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ManifestFileName is the name of the install manifest inside the install directory
+const ManifestFileName = ".setupkit-manifest.json"
+
+// InstallerFilePrefix starts the names of the files the installer keeps in an
+// install directory, such as the manifest and backups of it
+const InstallerFilePrefix = ".setupkit-"
+
+// Manifest records what an installation put on disk
+type Manifest struct {
+	AppName     string              `json:"app_name"`
+	Version     string              `json:"version"`
+	InstallDir  string              `json:"install_dir"`
+	InstalledAt time.Time           `json:"installed_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	Components  []ManifestComponent `json:"components"`
+	ConfigHash  string              `json:"config_hash,omitempty"` // Installer.ConfigHash of the run that wrote it
+	Features    map[string]bool     `json:"features,omitempty"`    // Config.Features of the run that wrote it
+
+	// System changes outside the install directory
+	PathEntries  []string `json:"path_entries,omitempty"`
+	PathSystem   bool     `json:"path_system,omitempty"`
+	RegistryKeys []string `json:"registry_keys,omitempty"`
+	Shortcuts    []string `json:"shortcuts,omitempty"`
+	Services     []string `json:"services,omitempty"`
+
+	// Progress of an installation that did not finish, nil once it has
+	Checkpoint *InstallCheckpoint `json:"checkpoint,omitempty"`
+}
+
+// ManifestComponent records an installed component
+type ManifestComponent struct {
+	ID    string         `json:"id"`
+	Name  string         `json:"name"`
+	Files []ManifestFile `json:"files,omitempty"`
+}
+
+// ManifestFile records an installed file, relative to the install directory
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestPath returns the manifest location for an install directory
+func ManifestPath(installDir string) string {
+	return filepath.Join(installDir, ManifestFileName)
+}
+
+// HasForeignFiles reports whether dir contains anything but the installer's
+// own files. A missing directory has none.
+func HasForeignFiles(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), InstallerFilePrefix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// LoadManifest reads the manifest of an existing installation
+func LoadManifest(installDir string) (*Manifest, error) {
+	data, err := os.ReadFile(ManifestPath(installDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid install manifest: %w", err)
+	}
+	if manifest.InstallDir == "" {
+		manifest.InstallDir = installDir
+	}
+	return &manifest, nil
+}
+
+// Save writes the manifest into its install directory
+func (m *Manifest) Save() error {
+	if err := os.MkdirAll(m.InstallDir, 0755); err != nil {
+		return fmt.Errorf("failed to create install directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ManifestPath(m.InstallDir), data, 0644)
+}
+
+// Component returns the manifest entry for a component
+func (m *Manifest) Component(id string) (*ManifestComponent, bool) {
+	for idx := range m.Components {
+		if m.Components[idx].ID == id {
+			return &m.Components[idx], true
+		}
+	}
+	return nil, false
+}
+
+// HasComponent reports whether a component is installed
+func (m *Manifest) HasComponent(id string) bool {
+	_, ok := m.Component(id)
+	return ok
+}
+
+// ComponentIDs returns the IDs of all installed components
+func (m *Manifest) ComponentIDs() []string {
+	ids := make([]string, 0, len(m.Components))
+	for _, c := range m.Components {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+// SetComponent adds or replaces a component entry
+func (m *Manifest) SetComponent(entry ManifestComponent) {
+	if existing, ok := m.Component(entry.ID); ok {
+		*existing = entry
+		return
+	}
+	m.Components = append(m.Components, entry)
+}
+
+// RemoveComponent drops a component entry
+func (m *Manifest) RemoveComponent(id string) {
+	for idx, c := range m.Components {
+		if c.ID == id {
+			m.Components = append(m.Components[:idx], m.Components[idx+1:]...)
+			return
+		}
+	}
+}
+
+// NewManifestComponent records a component and the files it put into installDir
+func NewManifestComponent(installDir string, component Component) ManifestComponent {
+	entry := ManifestComponent{ID: component.ID, Name: component.Name}
+	for _, file := range component.Files {
+		path := filepath.Join(installDir, file)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		sum, err := fileChecksum(path)
+		if err != nil {
+			continue
+		}
+		entry.Files = append(entry.Files, ManifestFile{
+			Path:   filepath.ToSlash(file),
+			Size:   info.Size(),
+			SHA256: sum,
+		})
+	}
+	return entry
+}
+
+// fileChecksum returns the hex encoded SHA-256 of a file
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return readerChecksum(f)
+}
+
+// readerChecksum returns the hex encoded SHA-256 of everything read from r
+func readerChecksum(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// updateManifest records installed components and drops removed ones
+func (i *Installer) updateManifest(installed, removed []Component) error {
+	if i.config.DryRun {
+		return nil
+	}
+
+	manifest, err := LoadManifest(i.config.InstallDir)
+	if err != nil {
+		manifest = &Manifest{
+			InstallDir:  i.config.InstallDir,
+			InstalledAt: time.Now().UTC(),
+		}
+	}
+	manifest.AppName = i.config.AppName
+	manifest.Version = i.config.Version
+	manifest.ConfigHash = i.ConfigHash()
+	manifest.Features = maps.Clone(i.config.Features)
+	manifest.UpdatedAt = time.Now().UTC()
+
+	for _, c := range installed {
+		manifest.SetComponent(NewManifestComponent(i.config.InstallDir, c))
+		for _, service := range c.Services {
+			if !slices.Contains(manifest.Services, service) {
+				manifest.Services = append(manifest.Services, service)
+			}
+		}
+	}
+	if pc := i.config.PathConfig; pc != nil && pc.Enabled && !i.config.Portable {
+		manifest.PathEntries = append([]string{}, pc.Dirs...)
+		manifest.PathSystem = pc.System
+	}
+	if key := uninstallRegistryKey(i.config.AppName); key != "" && !i.config.Portable && !slices.Contains(manifest.RegistryKeys, key) {
+		manifest.RegistryKeys = append(manifest.RegistryKeys, key)
+	}
+	for _, c := range removed {
+		manifest.RemoveComponent(c.ID)
+	}
+	manifest.Checkpoint = nil
+	return manifest.Save()
+}
+
+// recordShortcut adds a shortcut created outside the installation, such as
+// by a completion action, to the manifest so uninstalling removes it
+func (i *Installer) recordShortcut(path string) error {
+	manifest, err := LoadManifest(i.config.InstallDir)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(manifest.Shortcuts, path) {
+		return nil
+	}
+	manifest.Shortcuts = append(manifest.Shortcuts, path)
+	return manifest.Save()
+}
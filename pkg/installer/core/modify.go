@@ -0,0 +1,282 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModifyPlan describes the changes that move an existing installation to a new component selection
+type ModifyPlan struct {
+	Install []Component // Components to install, dependencies first
+	Remove  []Component // Components to uninstall, dependents first
+}
+
+// IsEmpty reports whether the plan changes nothing
+func (p *ModifyPlan) IsEmpty() bool {
+	return len(p.Install) == 0 && len(p.Remove) == 0
+}
+
+// Describe returns the changes for the confirmation screen, such as
+// "To install: SDK, Plugins" and "To remove: Docs", or "Nothing to change"
+func (p *ModifyPlan) Describe() []string {
+	names := func(components []Component) string {
+		list := make([]string, len(components))
+		for idx, c := range components {
+			list[idx] = c.Name
+		}
+		return strings.Join(list, ", ")
+	}
+	var lines []string
+	if len(p.Install) > 0 {
+		lines = append(lines, "To install: "+names(p.Install))
+	}
+	if len(p.Remove) > 0 {
+		lines = append(lines, "To remove: "+names(p.Remove))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "Nothing to change")
+	}
+	return lines
+}
+
+// PlanModify compares the installed components in manifest with the selected
+// component IDs. Dependencies of selected components are added automatically;
+// removing a required component or a dependency of a kept component is an error.
+func PlanModify(components []Component, manifest *Manifest, selectedIDs []string) (*ModifyPlan, error) {
+	byID := make(map[string]Component, len(components))
+	for _, c := range components {
+		byID[c.ID] = c
+	}
+
+	selected := make(map[string]bool, len(selectedIDs))
+	for _, id := range selectedIDs {
+		if _, ok := byID[id]; !ok {
+			return nil, fmt.Errorf("unknown component: %s", id)
+		}
+		selected[id] = true
+	}
+	for _, c := range components {
+		if c.Required && !selected[c.ID] {
+			return nil, fmt.Errorf("required component %s cannot be deselected", c.ID)
+		}
+	}
+
+	// Order selected components so that dependencies come first
+	ordered, err := orderByDependencies(components, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(ordered))
+	for _, c := range ordered {
+		wanted[c.ID] = true
+	}
+
+	// A deselected, installed component that something else needs can't be removed
+	for _, c := range ordered {
+		if selected[c.ID] || !manifest.HasComponent(c.ID) {
+			continue
+		}
+		return nil, fmt.Errorf("cannot remove %s: %s", c.ID, strings.Join(dependentsOf(c.ID, ordered), ", ")+" depends on it")
+	}
+
+	plan := &ModifyPlan{}
+	for _, c := range ordered {
+		if !manifest.HasComponent(c.ID) {
+			plan.Install = append(plan.Install, c)
+		}
+	}
+
+	installed := make(map[string]bool)
+	var installedOrder []Component
+	for _, id := range manifest.ComponentIDs() {
+		if c, ok := byID[id]; ok {
+			installed[id] = true
+			installedOrder = append(installedOrder, c)
+		}
+	}
+	removeOrder, err := orderByDependencies(installedOrder, installed)
+	if err != nil {
+		return nil, err
+	}
+	for idx := len(removeOrder) - 1; idx >= 0; idx-- {
+		if c := removeOrder[idx]; !wanted[c.ID] {
+			plan.Remove = append(plan.Remove, c)
+		}
+	}
+
+	return plan, nil
+}
+
+// orderByDependencies returns the components in ids plus their transitive
+// dependencies, each component after the components it depends on
+func orderByDependencies(components []Component, ids map[string]bool) ([]Component, error) {
+	byID := make(map[string]Component, len(components))
+	for _, c := range components {
+		byID[c.ID] = c
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var ordered []Component
+
+	var visit func(id string, path []string) error
+	visit = func(id string, path []string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, id), " -> "))
+		}
+		c, ok := byID[id]
+		if !ok {
+			return fmt.Errorf("unknown dependency %s of %s", id, path[len(path)-1])
+		}
+		state[id] = visiting
+		for _, dep := range c.Dependencies {
+			if err := visit(dep, append(path, id)); err != nil {
+				return err
+			}
+		}
+		state[id] = done
+		ordered = append(ordered, c)
+		return nil
+	}
+
+	for _, c := range components {
+		if ids[c.ID] {
+			if err := visit(c.ID, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ordered, nil
+}
+
+// dependentsOf returns the IDs of components that directly depend on id
+func dependentsOf(id string, components []Component) []string {
+	var dependents []string
+	for _, c := range components {
+		for _, dep := range c.Dependencies {
+			if dep == id {
+				dependents = append(dependents, c.ID)
+			}
+		}
+	}
+	return dependents
+}
+
+// LoadExistingInstall loads the manifest of an installation in InstallDir and
+// pre-selects its installed components, switching the installer to modify mode.
+func (i *Installer) LoadExistingInstall() (*Manifest, error) {
+	manifest, err := LoadManifest(i.config.InstallDir)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range i.config.Components {
+		c := &i.config.Components[idx]
+		c.Selected = manifest.HasComponent(c.ID) || c.Required
+	}
+	i.existing = manifest
+	return manifest, nil
+}
+
+// PlanModification returns what ExecuteModify would change for the current
+// selection, for confirmation before modifying; nil without an existing
+// installation, see LoadExistingInstall
+func (i *Installer) PlanModification() (*ModifyPlan, error) {
+	if i.existing == nil {
+		return nil, nil
+	}
+	var selectedIDs []string
+	for _, c := range i.config.Components {
+		if c.Selected {
+			selectedIDs = append(selectedIDs, c.ID)
+		}
+	}
+	return PlanModify(i.config.Components, i.existing, selectedIDs)
+}
+
+// IsModify reports whether the installer modifies an existing installation
+func (i *Installer) IsModify() bool {
+	return i.existing != nil
+}
+
+// Execute modifies the existing installation loaded by LoadExistingInstall
+// with ExecuteModify, or else installs with ExecuteInstallation
+func (i *Installer) Execute() error {
+	if i.IsModify() {
+		_, err := i.ExecuteModify()
+		return err
+	}
+	return i.ExecuteInstallation()
+}
+
+// ExecuteModify installs newly selected components and uninstalls deselected
+// ones, then updates the manifest
+func (i *Installer) ExecuteModify() (*ModifyPlan, error) {
+	if i.existing == nil {
+		return nil, fmt.Errorf("no existing installation loaded")
+	}
+	defer i.startCancelable()()
+
+	plan, err := i.PlanModification()
+	if err != nil {
+		return nil, err
+	}
+	if plan.IsEmpty() {
+		i.context.Logger.Info("Installation already matches the selection")
+		return plan, nil
+	}
+
+	for _, c := range plan.Remove {
+		i.context.Logger.Info("Removing component", "id", c.ID)
+		if err := i.uninstallComponent(c, i.existing); err != nil {
+			return plan, fmt.Errorf("failed to remove component %s: %w", c.ID, err)
+		}
+	}
+
+	if len(plan.Install) > 0 {
+		if err := i.installComponents(plan.Install); err != nil {
+			return plan, err
+		}
+	}
+
+	if err := i.updateManifest(plan.Install, plan.Remove); err != nil {
+		return plan, fmt.Errorf("failed to update install manifest: %w", err)
+	}
+	if manifest, err := LoadManifest(i.config.InstallDir); err == nil {
+		i.existing = manifest
+	}
+	return plan, nil
+}
+
+// uninstallComponent runs the component's PostUninstall and removes it using
+// its Uninstaller, or by deleting the files recorded in manifest
+func (i *Installer) uninstallComponent(component Component, manifest *Manifest) error {
+	if component.PostUninstall != nil {
+		if err := component.PostUninstall(i.componentContext(), i.config.InstallDir); err != nil {
+			return fmt.Errorf("post-uninstall actions failed: %w", err)
+		}
+	}
+	if component.Uninstaller != nil {
+		return component.Uninstaller(i.componentContext())
+	}
+
+	entry, ok := manifest.Component(component.ID)
+	if !ok {
+		return nil
+	}
+	for _, file := range entry.Files {
+		path := filepath.Join(i.config.InstallDir, filepath.FromSlash(file.Path))
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package core_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// modifyFixture creates a fabricated installation of "core" and "docs"
+func modifyFixture(t *testing.T) (string, *[]string) {
+	t.Helper()
+	installDir := t.TempDir()
+	for _, name := range []string{"core.bin", "docs.txt"} {
+		if err := os.WriteFile(filepath.Join(installDir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manifest := &core.Manifest{
+		AppName:    "ModifyApp",
+		Version:    "1.0.0",
+		InstallDir: installDir,
+	}
+	manifest.SetComponent(core.NewManifestComponent(installDir, core.Component{ID: "core", Name: "Core", Files: []string{"core.bin"}}))
+	manifest.SetComponent(core.NewManifestComponent(installDir, core.Component{ID: "docs", Name: "Docs", Files: []string{"docs.txt"}}))
+	if err := manifest.Save(); err != nil {
+		t.Fatalf("Failed to save manifest: %v", err)
+	}
+
+	actions := &[]string{}
+	return installDir, actions
+}
+
+// modifyComponents returns components that record their install actions
+func modifyComponents(installDir string, actions *[]string) []core.Component {
+	installer := func(id, file string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			*actions = append(*actions, "install:"+id)
+			return os.WriteFile(filepath.Join(installDir, file), []byte(id), 0644)
+		}
+	}
+	return []core.Component{
+		{ID: "core", Name: "Core", Required: true, Files: []string{"core.bin"}, Installer: installer("core", "core.bin")},
+		{ID: "docs", Name: "Docs", Files: []string{"docs.txt"}, Installer: installer("docs", "docs.txt")},
+		{ID: "sdk", Name: "SDK", Files: []string{"sdk.lib"}, Installer: installer("sdk", "sdk.lib")},
+		{ID: "plugins", Name: "Plugins", Dependencies: []string{"sdk"}, Files: []string{"plugins.dat"}, Installer: installer("plugins", "plugins.dat")},
+	}
+}
+
+func selectOnly(components []core.Component, ids ...string) {
+	want := make(map[string]bool)
+	for _, id := range ids {
+		want[id] = true
+	}
+	for idx := range components {
+		components[idx].Selected = want[components[idx].ID]
+	}
+}
+
+// TestModifyAddAndRemove tests adding one component and removing another from an existing install
+func TestModifyAddAndRemove(t *testing.T) {
+	installDir, actions := modifyFixture(t)
+	config := &core.Config{
+		AppName:    "ModifyApp",
+		Version:    "1.0.0",
+		InstallDir: installDir,
+		Components: modifyComponents(installDir, actions),
+	}
+	inst := newTestInstaller(config)
+
+	if _, err := inst.LoadExistingInstall(); err != nil {
+		t.Fatalf("LoadExistingInstall() error = %v", err)
+	}
+	if !inst.IsModify() {
+		t.Fatal("Installer should be in modify mode")
+	}
+	if !config.Components[0].Selected || !config.Components[1].Selected || config.Components[2].Selected {
+		t.Errorf("Installed components should be pre-selected: %+v", config.Components)
+	}
+
+	// Add plugins (pulls in sdk), remove docs
+	selectOnly(config.Components, "core", "plugins")
+	plan, err := inst.ExecuteModify()
+	if err != nil {
+		t.Fatalf("ExecuteModify() error = %v", err)
+	}
+
+	if got := componentIDs(plan.Install); got != "sdk,plugins" {
+		t.Errorf("Install = %s, want sdk,plugins", got)
+	}
+	if got := componentIDs(plan.Remove); got != "docs" {
+		t.Errorf("Remove = %s, want docs", got)
+	}
+	if got := strings.Join(*actions, ","); got != "install:sdk,install:plugins" {
+		t.Errorf("Install actions = %s", got)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "docs.txt")); !os.IsNotExist(err) {
+		t.Error("Removed component's files should be deleted")
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "core.bin")); err != nil {
+		t.Error("Kept component's files should remain")
+	}
+
+	manifest, err := core.LoadManifest(installDir)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	ids := manifest.ComponentIDs()
+	sort.Strings(ids)
+	if got := strings.Join(ids, ","); got != "core,plugins,sdk" {
+		t.Errorf("Manifest components = %s, want core,plugins,sdk", got)
+	}
+}
+
+// selectingUI changes the selection when run, like the user on the components screen
+type selectingUI struct {
+	runUI
+	selection   []string
+	preselected string // The components selected before
+}
+
+func (u *selectingUI) Run() error {
+	config := u.installer.GetConfig()
+	var preselected []core.Component
+	for _, c := range config.Components {
+		if c.Selected {
+			preselected = append(preselected, c)
+		}
+	}
+	u.preselected = componentIDs(preselected)
+	selectOnly(config.Components, u.selection...)
+	return u.installer.Execute()
+}
+
+// TestRunModifiesExistingInstall tests that Run with Modify changes the existing installation
+func TestRunModifiesExistingInstall(t *testing.T) {
+	installDir, actions := modifyFixture(t)
+	config := &core.Config{
+		AppName:    "ModifyApp",
+		Version:    "1.0.0",
+		InstallDir: installDir,
+		Modify:     true,
+		Components: modifyComponents(installDir, actions),
+	}
+	ui := &selectingUI{selection: []string{"core", "sdk"}}
+	core.RegisterUIFactory(func(core.Mode) (core.UI, error) { return ui, nil })
+	t.Cleanup(func() { core.RegisterUIFactory(nil) })
+
+	if err := core.New(config).Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if ui.preselected != "core,docs" {
+		t.Errorf("preselected = %s, want the installed core,docs", ui.preselected)
+	}
+	if got := strings.Join(*actions, ","); got != "install:sdk" {
+		t.Errorf("install actions = %s, want only the added sdk", got)
+	}
+	manifest, err := core.LoadManifest(installDir)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	ids := manifest.ComponentIDs()
+	sort.Strings(ids)
+	if got := strings.Join(ids, ","); got != "core,sdk" {
+		t.Errorf("manifest components = %s, want core,sdk", got)
+	}
+}
+
+// TestRunModifyWithoutInstall tests that Run with Modify fails when nothing is installed
+func TestRunModifyWithoutInstall(t *testing.T) {
+	config := &core.Config{AppName: "ModifyApp", Version: "1.0.0", InstallDir: t.TempDir(), Modify: true}
+	err := runInstaller(t, config, &runUI{})
+	if err == nil || !strings.Contains(err.Error(), "no installation to modify") {
+		t.Errorf("Run() error = %v, want no installation to modify", err)
+	}
+}
+
+// TestPlanModification tests the changes confirmed before modifying an existing install
+func TestPlanModification(t *testing.T) {
+	installDir, actions := modifyFixture(t)
+	config := &core.Config{
+		AppName:    "ModifyApp",
+		Version:    "1.0.0",
+		InstallDir: installDir,
+		Components: modifyComponents(installDir, actions),
+	}
+	inst := newTestInstaller(config)
+	if plan, err := inst.PlanModification(); plan != nil || err != nil {
+		t.Fatalf("PlanModification() = %+v, %v without an existing install, want nil", plan, err)
+	}
+	if _, err := inst.LoadExistingInstall(); err != nil {
+		t.Fatalf("LoadExistingInstall() error = %v", err)
+	}
+
+	// The installed components are pre-selected, so nothing changes
+	plan, err := inst.PlanModification()
+	if err != nil {
+		t.Fatalf("PlanModification() error = %v", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("Unchanged selection should produce an empty plan, got %+v", plan)
+	}
+	if got := strings.Join(plan.Describe(), "; "); got != "Nothing to change" {
+		t.Errorf("Describe() = %q, want Nothing to change", got)
+	}
+
+	selectOnly(config.Components, "core", "plugins")
+	plan, err = inst.PlanModification()
+	if err != nil {
+		t.Fatalf("PlanModification() error = %v", err)
+	}
+	if got := strings.Join(plan.Describe(), "; "); got != "To install: SDK, Plugins; To remove: Docs" {
+		t.Errorf("Describe() = %q, want To install: SDK, Plugins; To remove: Docs", got)
+	}
+	if len(*actions) != 0 {
+		t.Errorf("Planning must not install anything, got %v", *actions)
+	}
+
+	// Only the delta is performed
+	if _, err := inst.ExecuteModify(); err != nil {
+		t.Fatalf("ExecuteModify() error = %v", err)
+	}
+	if got := strings.Join(*actions, ","); got != "install:sdk,install:plugins" {
+		t.Errorf("Install actions = %s", got)
+	}
+}
+
+// TestModifyBlocked tests that required components and needed dependencies cannot be removed
+func TestModifyBlocked(t *testing.T) {
+	installDir, actions := modifyFixture(t)
+	components := modifyComponents(installDir, actions)
+	manifest, err := core.LoadManifest(installDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := core.PlanModify(components, manifest, []string{"docs"}); err == nil || !strings.Contains(err.Error(), "required component core") {
+		t.Errorf("Expected required component error, got: %v", err)
+	}
+
+	manifest.SetComponent(core.ManifestComponent{ID: "sdk"})
+	manifest.SetComponent(core.ManifestComponent{ID: "plugins"})
+	if _, err := core.PlanModify(components, manifest, []string{"core", "plugins"}); err == nil || !strings.Contains(err.Error(), "cannot remove sdk") {
+		t.Errorf("Expected dependency error, got: %v", err)
+	}
+
+	plan, err := core.PlanModify(components, manifest, []string{"core", "docs", "sdk", "plugins"})
+	if err != nil {
+		t.Fatalf("PlanModify() error = %v", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("Unchanged selection should produce an empty plan, got %+v", plan)
+	}
+	if len(*actions) != 0 {
+		t.Errorf("Planning must not install anything, got %v", *actions)
+	}
+}
+
+func componentIDs(components []core.Component) string {
+	ids := make([]string, 0, len(components))
+	for _, c := range components {
+		ids = append(ids, c.ID)
+	}
+	return strings.Join(ids, ",")
+}
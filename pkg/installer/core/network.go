@@ -0,0 +1,272 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// httpTimeout bounds each step of a request made with HTTPClient: connecting,
+// the TLS handshake, waiting for the response headers and, while reading the
+// body, the time without receiving any data. The transfer as a whole has no
+// limit, so large payloads on slow links still complete.
+const httpTimeout = 30 * time.Second
+
+// ProxySettings are the proxy settings in effect for outbound requests
+type ProxySettings struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+// EffectiveProxySettings returns the proxy settings of config, filling unset
+// values from HTTP_PROXY, HTTPS_PROXY and NO_PROXY or their lowercase forms.
+// A nil config uses the environment only.
+func EffectiveProxySettings(config *Config) ProxySettings {
+	var settings ProxySettings
+	if config != nil {
+		settings = ProxySettings{HTTPProxy: config.HTTPProxy, HTTPSProxy: config.HTTPSProxy, NoProxy: config.NoProxy}
+	}
+	fromEnv := func(value *string, name string) {
+		if *value != "" {
+			return
+		}
+		if *value = os.Getenv(name); *value == "" {
+			*value = os.Getenv(strings.ToLower(name))
+		}
+	}
+	fromEnv(&settings.HTTPProxy, "HTTP_PROXY")
+	fromEnv(&settings.HTTPSProxy, "HTTPS_PROXY")
+	fromEnv(&settings.NoProxy, "NO_PROXY")
+	return settings
+}
+
+// HTTPClient returns the client all network operations of the installer use.
+// It routes requests through the proxies of config; credentials in a proxy URL
+// are sent to the proxy for authentication. See httpTimeout for the timeouts.
+func HTTPClient(config *Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = EffectiveProxySettings(config).ProxyFunc()
+	transport.DialContext = (&net.Dialer{Timeout: httpTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = httpTimeout
+	transport.ResponseHeaderTimeout = httpTimeout
+	return &http.Client{Transport: transport}
+}
+
+// Validate checks that the proxy URLs can be parsed
+func (s ProxySettings) Validate() error {
+	for _, proxy := range []string{s.HTTPProxy, s.HTTPSProxy} {
+		if _, err := parseProxyURL(proxy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProxyFunc returns a proxy selector for http.Transport. Requests to
+// loopback addresses and to hosts matching NoProxy are not proxied.
+func (s ProxySettings) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxy := s.HTTPProxy
+		if req.URL.Scheme == "https" {
+			proxy = s.HTTPSProxy
+		}
+		if proxy == "" || s.bypass(req.URL.Hostname()) {
+			return nil, nil
+		}
+		return parseProxyURL(proxy)
+	}
+}
+
+// bypass reports whether host is reached without a proxy
+func (s ProxySettings) bypass(host string) bool {
+	host = strings.ToLower(host)
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	for _, entry := range strings.Split(s.NoProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h // Ports are ignored
+		}
+		// "example.com" and ".example.com" both match the domain and its subdomains
+		domain := strings.TrimPrefix(entry, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseProxyURL parses a proxy URL; a missing scheme defaults to http
+func parseProxyURL(proxy string) (*url.URL, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q", proxy)
+	}
+	return u, nil
+}
+
+// Download fetches rawURL into dest using HTTPClient, retrying transient
+// failures with DefaultRetryPolicy
+func Download(ctx context.Context, config *Config, rawURL, dest string) error {
+	client := HTTPClient(config)
+	policy := DefaultRetryPolicy()
+	policy.IsRetryable = func(err error) bool {
+		// Client errors such as 404 will not go away by retrying
+		var status *httpStatusError
+		if errors.As(err, &status) {
+			return status.code >= 500
+		}
+		return true
+	}
+	return Retry(ctx, policy, func(ctx context.Context) error {
+		return downloadOnce(ctx, client, rawURL, dest)
+	})
+}
+
+// DownloadComponent downloads a component payload to dest and verifies its
+// SHA-256 checksum. With Config.CacheDir set, an unchanged payload is taken
+// from the cache instead of being downloaded again. The download is staged in
+// Config.TempDir, so dest only ever receives a verified payload.
+func DownloadComponent(ctx context.Context, config *Config, rawURL, checksum, dest string) error {
+	cache := NewCache(config)
+	if cache != nil && checksum != "" {
+		hit, err := cache.Get(checksum, dest)
+		if err != nil {
+			return err
+		}
+		if hit {
+			return nil
+		}
+	}
+
+	dir, err := config.newStagingDir("setupkit-download-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	staged := filepath.Join(dir, filepath.Base(dest))
+	if err := Download(ctx, config, rawURL, staged); err != nil {
+		return err
+	}
+	if checksum != "" {
+		sum, err := fileChecksum(staged)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(sum, checksum) {
+			return fmt.Errorf("%w for %s: got %s, want %s", ErrChecksumMismatch, rawURL, sum, checksum)
+		}
+	}
+	if err := moveStaged(staged, dest); err != nil {
+		return err
+	}
+	if checksum == "" {
+		return nil
+	}
+	if cache != nil {
+		// The cache only saves time, a failure to fill it is not an error
+		cache.Put(checksum, dest)
+	}
+	return nil
+}
+
+// httpStatusError is an unsuccessful HTTP response
+type httpStatusError struct {
+	code   int
+	status string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("server returned %s", e.status)
+}
+
+// idleReader cancels the request of its body when no data arrives within
+// httpTimeout
+type idleReader struct {
+	body  io.Reader
+	timer *time.Timer
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if n > 0 {
+		r.timer.Reset(httpTimeout)
+	}
+	return n, err
+}
+
+func downloadOnce(ctx context.Context, client *http.Client, rawURL, dest string) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &httpStatusError{code: resp.StatusCode, status: resp.Status}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp := dest + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	stalled := fmt.Errorf("no data received for %s", httpTimeout)
+	timer := time.AfterFunc(httpTimeout, func() { cancel(stalled) })
+	defer timer.Stop()
+	if _, err := io.Copy(f, &idleReader{body: resp.Body, timer: timer}); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		if cause := context.Cause(ctx); cause == stalled {
+			err = cause
+		}
+		return fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
@@ -0,0 +1,121 @@
+package core_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestDownloadThroughProxy tests that downloads go through an authenticated proxy
+func TestDownloadThroughProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+		if r.Header.Get("Proxy-Authorization") != want {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		proxied = append(proxied, r.URL.String())
+		w.Write([]byte("payload"))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	proxyURL.User = url.UserPassword("user", "secret")
+	config := &core.Config{HTTPProxy: proxyURL.String(), NoProxy: "internal.example"}
+
+	dest := filepath.Join(t.TempDir(), "downloads", "component.zip")
+	if err := core.Download(context.Background(), config, "http://downloads.example.test/component.zip", dest); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	data, err := os.ReadFile(dest)
+	if err != nil || string(data) != "payload" {
+		t.Fatalf("Downloaded content = %q, %v", data, err)
+	}
+	if len(proxied) != 1 || proxied[0] != "http://downloads.example.test/component.zip" {
+		t.Errorf("Proxy saw %v, want the download URL", proxied)
+	}
+}
+
+// TestHTTPClientNoProxy tests that hosts matching NoProxy bypass the proxy
+func TestHTTPClientNoProxy(t *testing.T) {
+	config := &core.Config{
+		HTTPProxy:  "http://proxy.corp:3128",
+		HTTPSProxy: "proxy.corp:3129",
+		NoProxy:    "internal.example, .corp.lan, 10.0.0.0/8",
+	}
+	proxyFor := core.HTTPClient(config).Transport.(*http.Transport).Proxy
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"http://downloads.example.com/a", "http://proxy.corp:3128"},
+		{"https://downloads.example.com/a", "http://proxy.corp:3129"},
+		{"https://internal.example/a", ""},
+		{"https://files.internal.example/a", ""},
+		{"http://build.corp.lan/a", ""},
+		{"http://10.1.2.3/a", ""},
+		{"http://localhost:8080/a", ""},
+		{"http://notinternal.example/a", "http://proxy.corp:3128"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+		got, err := proxyFor(req)
+		if err != nil {
+			t.Fatalf("Proxy(%s) error = %v", tt.url, err)
+		}
+		gotURL := ""
+		if got != nil {
+			gotURL = got.String()
+		}
+		if gotURL != tt.want {
+			t.Errorf("Proxy(%s) = %q, want %q", tt.url, gotURL, tt.want)
+		}
+	}
+}
+
+// TestHTTPClientTimeouts tests that only the connection steps are bounded,
+// not the whole transfer
+func TestHTTPClientTimeouts(t *testing.T) {
+	client := core.HTTPClient(nil)
+	if client.Timeout != 0 {
+		t.Errorf("Timeout = %v, a total limit cuts off large downloads", client.Timeout)
+	}
+	transport := client.Transport.(*http.Transport)
+	if transport.TLSHandshakeTimeout <= 0 || transport.ResponseHeaderTimeout <= 0 {
+		t.Errorf("TLSHandshakeTimeout = %v, ResponseHeaderTimeout = %v, want both set",
+			transport.TLSHandshakeTimeout, transport.ResponseHeaderTimeout)
+	}
+}
+
+// TestTelemetrySinkUsesProxy tests that the telemetry sink applies the proxy settings of config
+func TestTelemetrySinkUsesProxy(t *testing.T) {
+	config := &core.Config{HTTPSProxy: "http://proxy.corp:3128"}
+	sink := core.NewHTTPTelemetrySink(config, "https://telemetry.example.com/events", 10)
+	req, _ := http.NewRequest(http.MethodPost, sink.Endpoint, nil)
+	got, err := sink.Client.Transport.(*http.Transport).Proxy(req)
+	if err != nil || got == nil || got.String() != "http://proxy.corp:3128" {
+		t.Errorf("Proxy = %v, %v, want http://proxy.corp:3128", got, err)
+	}
+}
+
+// TestEffectiveProxySettingsEnvFallback tests that unset proxy settings come from the environment
+func TestEffectiveProxySettingsEnvFallback(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "http://env-proxy:8080")
+	t.Setenv("NO_PROXY", "env.example")
+	settings := core.EffectiveProxySettings(&core.Config{NoProxy: "config.example"})
+	if settings.HTTPProxy != "http://env-proxy:8080" {
+		t.Errorf("HTTPProxy = %q, want value from HTTP_PROXY", settings.HTTPProxy)
+	}
+	if settings.NoProxy != "config.example" {
+		t.Errorf("NoProxy = %q, config should take precedence", settings.NoProxy)
+	}
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PartialFailure is a step of registering the installation with the system
+// that failed without stopping the installation, such as adding to the PATH
+type PartialFailure struct {
+	Operation string // What failed, such as "update PATH"
+	Err       error
+}
+
+// Error implements error
+func (f PartialFailure) Error() string {
+	return fmt.Sprintf("%s: %v", f.Operation, f.Err)
+}
+
+// Unwrap returns the underlying error
+func (f PartialFailure) Unwrap() error {
+	return f.Err
+}
+
+// PartialFailures are the steps of an installation that failed without
+// stopping it. They are listed in the InstallSummary, or fail the
+// installation with Config.TreatPartialFailuresAsError.
+type PartialFailures []PartialFailure
+
+// Error implements error by listing the failures
+func (f PartialFailures) Error() string {
+	msgs := make([]string, len(f))
+	for idx, failure := range f {
+		msgs[idx] = failure.Error()
+	}
+	return "installation incomplete: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the underlying errors of the failures
+func (f PartialFailures) Unwrap() []error {
+	errs := make([]error, len(f))
+	for idx, failure := range f {
+		errs[idx] = failure.Err
+	}
+	return errs
+}
+
+// PartialFailures returns the steps of the installation that failed without stopping it
+func (i *Installer) PartialFailures() PartialFailures {
+	return append(PartialFailures(nil), i.partialFailures...)
+}
+
+// recordPartialFailure logs and records that operation failed with err, if it did
+func (i *Installer) recordPartialFailure(operation string, err error) {
+	if err == nil {
+		return
+	}
+	i.context.Logger.Warn("Failed to "+operation, "error", err)
+	i.partialFailures = append(i.partialFailures, PartialFailure{Operation: operation, Err: err})
+}
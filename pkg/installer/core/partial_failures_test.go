@@ -0,0 +1,72 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestPartialFailureReported tests that a failed PATH update is listed in the summary without failing the installation
+func TestPartialFailureReported(t *testing.T) {
+	platform, _ := useMocks(t)
+	platform.Errors["UpdatePath"] = errors.New("access denied")
+	ui := &runUI{}
+
+	if err := runInstaller(t, serviceConfig(t), ui); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	failures := ui.installer.PartialFailures()
+	if len(failures) != 1 || failures[0].Operation != "update PATH" || !errors.Is(failures[0], platform.Errors["UpdatePath"]) {
+		t.Fatalf("PartialFailures() = %v, want the PATH update", failures)
+	}
+	summary := ui.installer.CreateSummary()
+	if len(summary.PartialFailures) != 1 || len(summary.Warnings) != 1 || summary.Warnings[0] != "Failed to update PATH: access denied" {
+		t.Errorf("summary failures = %v, warnings = %q", summary.PartialFailures, summary.Warnings)
+	}
+	// The steps after the failed one still ran
+	assertCalls(t, "platform", platform.Calls()[4:], []string{"CreateShortcuts()", "RegisterUninstaller()"})
+}
+
+// TestPartialFailureAsError tests that TreatPartialFailuresAsError fails and rolls back the installation
+func TestPartialFailureAsError(t *testing.T) {
+	platform, _ := useMocks(t)
+	platform.Errors["UpdatePath"] = errors.New("access denied")
+	config := serviceConfig(t)
+	config.Rollback = core.RollbackFull
+	config.TreatPartialFailuresAsError = true
+
+	err := runInstaller(t, config, &runUI{})
+	if !errors.Is(err, platform.Errors["UpdatePath"]) {
+		t.Fatalf("Run() error = %v, want the PATH error", err)
+	}
+	var installErr *core.InstallError
+	if !errors.As(err, &installErr) || installErr.Phase != core.PhaseRegistration || !installErr.RolledBack {
+		t.Errorf("Run() error = %#v, want a rolled back system integration error", installErr)
+	}
+	var failures core.PartialFailures
+	if !errors.As(err, &failures) || len(failures) != 1 {
+		t.Errorf("Run() error = %v, want the partial failures", err)
+	}
+}
+
+// TestRegisterWithOSFailureIsNotPartial tests that a failed OS registration
+// ends the post-installation tasks instead of being listed as partial failure
+func TestRegisterWithOSFailureIsNotPartial(t *testing.T) {
+	platform, _ := useMocks(t)
+	platform.Errors["RegisterWithOS"] = errors.New("registry locked")
+	ui := &runUI{}
+
+	if err := runInstaller(t, serviceConfig(t), ui); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if failures := ui.installer.PartialFailures(); len(failures) != 0 {
+		t.Errorf("PartialFailures() = %v, want none", failures)
+	}
+	for _, call := range platform.Calls() {
+		if call == "CreateShortcuts()" || call == "RegisterUninstaller()" {
+			t.Errorf("%s ran after the OS registration failed", call)
+		}
+	}
+}
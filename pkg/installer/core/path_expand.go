@@ -0,0 +1,190 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Special folder tokens understood by ExpandPath
+const (
+	TokenProgramFiles = "{ProgramFiles}"
+	TokenAppData      = "{AppData}"
+	TokenHome         = "{Home}"
+)
+
+// ExpandPath expands an installation path as given in flags, config files or
+// response files. It replaces a leading ~ with the home directory,
+// environment variables written as $NAME, ${NAME} or %NAME%, and the special
+// folder tokens {ProgramFiles}, {AppData} and {Home} with the folders of the
+// current platform. Unknown tokens and unset variables are errors.
+func ExpandPath(raw string) (string, error) {
+	return expandPath(raw, currentScopeEnv())
+}
+
+func expandPath(raw string, env scopeEnv) (string, error) {
+	var b strings.Builder
+	rest := raw
+
+	if rest == "~" || strings.HasPrefix(rest, "~/") || strings.HasPrefix(rest, `~\`) {
+		home, err := env.home()
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(home)
+		rest = rest[1:]
+	}
+
+	for rest != "" {
+		idx := strings.IndexAny(rest, "${%")
+		if idx < 0 {
+			b.WriteString(rest)
+			break
+		}
+		b.WriteString(rest[:idx])
+		rest = rest[idx:]
+
+		var value string
+		var n int
+		var err error
+		switch rest[0] {
+		case '{':
+			value, n, err = expandToken(rest, env)
+		case '$':
+			value, n, err = expandDollar(rest, env)
+		case '%':
+			value, n, err = expandPercent(rest, env)
+		}
+		if err != nil {
+			return "", fmt.Errorf("cannot expand path %q: %w", raw, err)
+		}
+		b.WriteString(value)
+		rest = rest[n:]
+	}
+	return b.String(), nil
+}
+
+// expandToken expands the special folder token at the start of s and returns
+// its value and length
+func expandToken(s string, env scopeEnv) (string, int, error) {
+	end := strings.IndexByte(s, '}')
+	if end < 0 {
+		return "", 0, fmt.Errorf("unterminated token in %s", s)
+	}
+	token := s[:end+1]
+	switch token {
+	case TokenProgramFiles:
+		return env.programFiles(), len(token), nil
+	case TokenAppData:
+		dir, err := env.appData()
+		return dir, len(token), err
+	case TokenHome:
+		home, err := env.home()
+		return home, len(token), err
+	}
+	return "", 0, fmt.Errorf("unknown token %s, use %s, %s or %s", token, TokenProgramFiles, TokenAppData, TokenHome)
+}
+
+// expandDollar expands the $NAME or ${NAME} variable at the start of s and
+// returns its value and length; a $ not followed by a name is kept
+func expandDollar(s string, env scopeEnv) (string, int, error) {
+	if strings.HasPrefix(s, "${") {
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return "", 0, fmt.Errorf("unterminated variable in %s", s)
+		}
+		value, err := env.variable(s[2:end])
+		return value, end + 1, err
+	}
+	n := 1
+	for n < len(s) && isVariableChar(s[n]) {
+		n++
+	}
+	if n == 1 {
+		return "$", 1, nil
+	}
+	value, err := env.variable(s[1:n])
+	return value, n, err
+}
+
+// expandPercent expands the %NAME% variable at the start of s and returns its
+// value and length; a % not enclosing a name is kept
+func expandPercent(s string, env scopeEnv) (string, int, error) {
+	n := 1
+	for n < len(s) && isVariableChar(s[n]) {
+		n++
+	}
+	if n == 1 || n == len(s) || s[n] != '%' {
+		return "%", 1, nil
+	}
+	value, err := env.variable(s[1:n])
+	return value, n + 1, err
+}
+
+func isVariableChar(c byte) bool {
+	return c == '_' || c == '(' || c == ')' ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// variable returns the value of an environment variable, which must be set
+func (env scopeEnv) variable(name string) (string, error) {
+	if value := env.getenv(name); value != "" {
+		return value, nil
+	}
+	return "", fmt.Errorf("environment variable %s is not set", name)
+}
+
+// home returns the home directory
+func (env scopeEnv) home() (string, error) {
+	if env.homeDir != nil {
+		if home, err := env.homeDir(); err == nil && home != "" {
+			return home, nil
+		}
+	}
+	return "", fmt.Errorf("home directory is unknown")
+}
+
+// programFiles returns the directory for per-machine applications
+func (env scopeEnv) programFiles() string {
+	switch env.goos {
+	case "windows":
+		if dir := env.getenv("ProgramFiles"); dir != "" {
+			return dir
+		}
+		return `C:\Program Files`
+	case "darwin":
+		return "/Applications"
+	default:
+		return "/opt"
+	}
+}
+
+// appData returns the directory for per-user application data
+func (env scopeEnv) appData() (string, error) {
+	switch env.goos {
+	case "windows":
+		if dir := env.getenv("APPDATA"); dir != "" {
+			return dir, nil
+		}
+		home, err := env.home()
+		if err != nil {
+			return "", err
+		}
+		return windowsJoin(home, "AppData", "Roaming"), nil
+	case "darwin":
+		home, err := env.home()
+		if err != nil {
+			return "", err
+		}
+		return path.Join(home, "Library", "Application Support"), nil
+	default:
+		if dir := env.getenv("XDG_DATA_HOME"); dir != "" {
+			return dir, nil
+		}
+		home, err := env.home()
+		if err != nil {
+			return "", err
+		}
+		return path.Join(home, ".local", "share"), nil
+	}
+}
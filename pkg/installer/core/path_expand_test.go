@@ -0,0 +1,77 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestExpandPath tests variables, ~ and special folders per platform
+func TestExpandPath(t *testing.T) {
+	winVars := map[string]string{
+		"LOCALAPPDATA": `C:\Users\bob\AppData\Local`,
+		"APPDATA":      `C:\Users\bob\AppData\Roaming`,
+		"ProgramFiles": `D:\Programs`,
+	}
+	unixVars := map[string]string{"HOME": "/home/bob", "APP": "tool"}
+
+	tests := []struct {
+		name string
+		env  scopeEnv
+		raw  string
+		want string
+	}{
+		{"plain", testScopeEnv("linux", nil, "/home/bob", false), "/opt/app", "/opt/app"},
+		{"windows percent", testScopeEnv("windows", winVars, `C:\Users\bob`, false), `%LOCALAPPDATA%\App`, `C:\Users\bob\AppData\Local\App`},
+		{"windows tilde", testScopeEnv("windows", winVars, `C:\Users\bob`, false), `~\App`, `C:\Users\bob\App`},
+		{"windows program files", testScopeEnv("windows", winVars, `C:\Users\bob`, false), `{ProgramFiles}\App`, `D:\Programs\App`},
+		{"windows program files default", testScopeEnv("windows", nil, `C:\Users\bob`, false), `{ProgramFiles}\App`, `C:\Program Files\App`},
+		{"windows app data", testScopeEnv("windows", winVars, `C:\Users\bob`, false), `{AppData}\App`, `C:\Users\bob\AppData\Roaming\App`},
+		{"windows app data default", testScopeEnv("windows", nil, `C:\Users\bob`, false), `{AppData}\App`, `C:\Users\bob\AppData\Roaming\App`},
+		{"windows home", testScopeEnv("windows", nil, `C:\Users\bob`, false), `{Home}\App`, `C:\Users\bob\App`},
+		{"linux dollar", testScopeEnv("linux", unixVars, "/home/bob", false), "$HOME/.app", "/home/bob/.app"},
+		{"linux braces", testScopeEnv("linux", unixVars, "/home/bob", false), "/opt/${APP}-data", "/opt/tool-data"},
+		{"linux tilde", testScopeEnv("linux", nil, "/home/bob", false), "~/Applications", "/home/bob/Applications"},
+		{"linux program files", testScopeEnv("linux", nil, "/home/bob", false), "{ProgramFiles}/app", "/opt/app"},
+		{"linux app data", testScopeEnv("linux", nil, "/home/bob", false), "{AppData}/app", "/home/bob/.local/share/app"},
+		{"linux app data xdg", testScopeEnv("linux", map[string]string{"XDG_DATA_HOME": "/data"}, "/home/bob", false), "{AppData}/app", "/data/app"},
+		{"darwin program files", testScopeEnv("darwin", nil, "/Users/bob", false), "{ProgramFiles}/App.app", "/Applications/App.app"},
+		{"darwin app data", testScopeEnv("darwin", nil, "/Users/bob", false), "{AppData}/App", "/Users/bob/Library/Application Support/App"},
+		{"darwin home", testScopeEnv("darwin", nil, "/Users/bob", false), "{Home}/Applications/App", "/Users/bob/Applications/App"},
+		{"literal dollar and percent", testScopeEnv("linux", nil, "/home/bob", false), "/opt/$/100%", "/opt/$/100%"},
+		{"tilde inside", testScopeEnv("linux", nil, "/home/bob", false), "/opt/~app", "/opt/~app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandPath(tt.raw, tt.env)
+			if err != nil {
+				t.Fatalf("expandPath(%q) error = %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("expandPath(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExpandPathErrors tests that unknown tokens and unset variables are reported
+func TestExpandPathErrors(t *testing.T) {
+	env := testScopeEnv("linux", map[string]string{"HOME": "/home/bob"}, "", false)
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"{Desktop}/app", "unknown token {Desktop}"},
+		{"{ProgramFiles/app", "unterminated token"},
+		{"$MISSING/app", "environment variable MISSING is not set"},
+		{"${MISSING/app", "unterminated variable"},
+		{`%MISSING%\app`, "environment variable MISSING is not set"},
+		{"~/app", "home directory is unknown"},
+		{"{Home}/app", "home directory is unknown"},
+	}
+	for _, tt := range tests {
+		_, err := expandPath(tt.raw, env)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expandPath(%q) error = %v, want %q", tt.raw, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PATH scopes, the values of SettingPathScope
+const (
+	PathScopeNameUser   = "user"
+	PathScopeNameSystem = "system"
+)
+
+// Scope returns the PATH that is changed, PathScopeNameUser or PathScopeNameSystem
+func (pc *PathConfiguration) Scope() string {
+	if pc != nil && pc.System {
+		return PathScopeNameSystem
+	}
+	return PathScopeNameUser
+}
+
+// SetScope chooses the PATH to change by name
+func (pc *PathConfiguration) SetScope(scope string) error {
+	switch strings.ToLower(strings.TrimSpace(scope)) {
+	case PathScopeNameUser:
+		pc.System = false
+	case PathScopeNameSystem:
+		pc.System = true
+	default:
+		return fmt.Errorf("invalid PATH scope %q, want %s or %s", scope, PathScopeNameUser, PathScopeNameSystem)
+	}
+	return nil
+}
+
+// Describe states which PATH the installation changes, e.g. "/opt/app/bin
+// is added to the user PATH", and is empty when PATH is left alone
+func (pc *PathConfiguration) Describe() string {
+	if pc == nil || !pc.Enabled || len(pc.Dirs) == 0 {
+		return ""
+	}
+	if len(pc.Dirs) == 1 {
+		return fmt.Sprintf("%s is added to the %s PATH", pc.Dirs[0], pc.Scope())
+	}
+	return fmt.Sprintf("%s are added to the %s PATH", strings.Join(pc.Dirs, ", "), pc.Scope())
+}
+
+// DefaultPathSystem reports whether the system PATH is the default when the
+// user chooses the scope: it is when the installer runs elevated
+func DefaultPathSystem() bool {
+	return currentScopeEnv().elevated()
+}
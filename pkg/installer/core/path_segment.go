@@ -0,0 +1,52 @@
+package core
+
+import (
+	"strings"
+	"unicode"
+)
+
+// windowsReservedNames are file names Windows refuses regardless of extension
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SanitizePathSegment turns name, such as a publisher, into a single folder
+// name that is valid on every platform. The rules are applied in order:
+//
+//   - characters invalid in file names on Windows, macOS or Linux
+//     (< > : " / \ | ? * and control characters) become spaces
+//   - runs of white space collapse into a single space
+//   - leading and trailing spaces and dots are removed, as Windows drops
+//     trailing dots and a leading dot hides the folder elsewhere
+//   - names reserved on Windows (CON, NUL, COM1, ...) get a trailing "_"
+//
+// The result is empty if nothing usable remains. Letters keep their case;
+// the Linux defaults lowercase the whole path afterwards.
+func SanitizePathSegment(name string) string {
+	mapped := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return ' '
+		}
+		return r
+	}, name)
+
+	segment := strings.Trim(strings.Join(strings.Fields(mapped), " "), " .")
+	if windowsReservedNames[strings.ToUpper(segment)] {
+		segment += "_"
+	}
+	return segment
+}
+
+// publisherSegment returns the sanitized publisher folder for appName, empty
+// if the publisher is unusable or the same as the application name
+func publisherSegment(publisher, appName string) string {
+	segment := SanitizePathSegment(publisher)
+	if strings.EqualFold(segment, appName) {
+		return ""
+	}
+	return segment
+}
@@ -0,0 +1,80 @@
+package core
+
+import "fmt"
+
+// Phase is one of the steps an installation goes through, in order. The
+// progress screen shows it as "Phase 2 of 4: Registering components".
+type Phase int
+
+const (
+	PhaseNone         Phase = iota // Not installing
+	PhasePreInstall                // Pre-install scripts and checks
+	PhaseFileCopy                  // Installing the components' files
+	PhaseRegistration              // Registering with the system, PATH and shortcuts
+	PhasePostInstall               // Post-install script and verification
+)
+
+// PhaseCount is the number of phases of an installation
+const PhaseCount = int(PhasePostInstall)
+
+// String returns the label the progress screen shows for the phase
+func (p Phase) String() string {
+	switch p {
+	case PhasePreInstall:
+		return "Preparing installation"
+	case PhaseFileCopy:
+		return "Installing files"
+	case PhaseRegistration:
+		return "Registering components"
+	case PhasePostInstall:
+		return "Finishing installation"
+	}
+	return ""
+}
+
+// PhaseEvent reports the start or the end of a phase to Config.OnPhase
+type PhaseEvent struct {
+	Phase Phase
+	Ended bool  // False when the phase starts
+	Err   error // Why the phase failed, if it ended with an error
+}
+
+// PhaseStatus describes the phase of the installation, such as
+// "Phase 2 of 4: Installing files", or is empty outside the phases
+func (p *Progress) PhaseStatus() string {
+	if p.Phase == PhaseNone {
+		return ""
+	}
+	return fmt.Sprintf("Phase %d of %d: %s", int(p.Phase), PhaseCount, p.Phase)
+}
+
+// beginPhase starts phase, reports it to Config.OnPhase and shows it on the progress screen
+func (i *Installer) beginPhase(phase Phase) {
+	i.phase = phase
+	i.context.Logger.Debug("Installation phase started", "phase", phase.String())
+	if i.config.OnPhase != nil {
+		i.config.OnPhase(PhaseEvent{Phase: phase})
+	}
+
+	// The overall progress follows the components, which are installed in the file copy phase
+	progress := &Progress{Phase: phase, Message: phase.String() + "..."}
+	if phase > PhaseFileCopy {
+		progress.OverallProgress = 1.0
+	}
+	if i.ui != nil {
+		if err := i.ui.ShowProgress(progress); err != nil {
+			i.context.Logger.Warn("Failed to update progress", "error", err)
+		}
+	}
+}
+
+// endPhase ends the current phase with err, which it returns so that a
+// failing phase can end with "return i.endPhase(err)"
+func (i *Installer) endPhase(err error) error {
+	phase := i.phase
+	i.phase = PhaseNone
+	if i.config.OnPhase != nil {
+		i.config.OnPhase(PhaseEvent{Phase: phase, Ended: true, Err: err})
+	}
+	return err
+}
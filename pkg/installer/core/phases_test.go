@@ -0,0 +1,97 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// phaseUI records the phases the progress screen is shown
+type phaseUI struct {
+	testUI
+	phases []string
+}
+
+func (u *phaseUI) ShowProgress(progress *core.Progress) error {
+	if status := progress.PhaseStatus(); len(u.phases) == 0 || u.phases[len(u.phases)-1] != status {
+		u.phases = append(u.phases, status)
+	}
+	return nil
+}
+
+// recordPhases returns the phase events of config as "start <phase>" and "end <phase>"
+func recordPhases(config *core.Config) *[]string {
+	var events []string
+	config.OnPhase = func(event core.PhaseEvent) {
+		switch {
+		case !event.Ended:
+			events = append(events, "start "+event.Phase.String())
+		case event.Err != nil:
+			events = append(events, "fail "+event.Phase.String())
+		default:
+			events = append(events, "end "+event.Phase.String())
+		}
+	}
+	return &events
+}
+
+// TestInstallationPhases tests that an installation goes through its phases in order
+func TestInstallationPhases(t *testing.T) {
+	config := &core.Config{
+		AppName:           "PhaseApp",
+		Version:           "1.0.0",
+		InstallDir:        t.TempDir(),
+		Rollback:          core.RollbackNone,
+		PreInstallScript:  &core.Script{Content: markerScript()},
+		PostInstallScript: &core.Script{Content: markerScript()},
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Installer: func(context.Context) error { return nil }},
+		},
+	}
+	events := recordPhases(config)
+	ui := &phaseUI{}
+	inst := newTestInstaller(config)
+	inst.SetUI(ui)
+
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+
+	assertCalls(t, "phase", *events, []string{
+		"start Preparing installation", "end Preparing installation",
+		"start Installing files", "end Installing files",
+		"start Registering components", "end Registering components",
+		"start Finishing installation", "end Finishing installation",
+	})
+	assertCalls(t, "progress", ui.phases, []string{
+		"Phase 1 of 4: Preparing installation",
+		"Phase 2 of 4: Installing files",
+		"Phase 3 of 4: Registering components",
+		"Phase 4 of 4: Finishing installation",
+	})
+}
+
+// TestInstallationPhaseFails tests that a failing phase ends with its error and no later phase starts
+func TestInstallationPhaseFails(t *testing.T) {
+	config := &core.Config{
+		AppName:    "PhaseApp",
+		Version:    "1.0.0",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Installer: func(context.Context) error { return errors.New("disk full") }},
+		},
+	}
+	events := recordPhases(config)
+
+	if err := newTestInstaller(config).ExecuteInstallation(); err == nil {
+		t.Fatal("ExecuteInstallation() succeeded, want the component's error")
+	}
+
+	assertCalls(t, "phase", *events, []string{
+		"start Preparing installation", "end Preparing installation",
+		"start Installing files", "fail Installing files",
+	})
+}
@@ -69,12 +69,37 @@ const (
 	ServiceUnknown
 )
 
+// Injected replacements for the platform implementations, see SetPlatformInstaller
+var (
+	platformOverride       PlatformInstaller
+	serviceManagerOverride ServiceManager
+)
+
+// SetPlatformInstaller makes installers and uninstallers use p instead of the
+// platform implementation, e.g. a MockPlatformInstaller in tests. nil restores
+// the platform implementation.
+func SetPlatformInstaller(p PlatformInstaller) {
+	platformOverride = p
+}
+
+// SetServiceManager makes GetServiceManager return m. nil restores the
+// platform implementation.
+func SetServiceManager(m ServiceManager) {
+	serviceManagerOverride = m
+}
+
 // GetPlatformInstaller returns a platform-specific installer
 func GetPlatformInstaller() (PlatformInstaller, error) {
+	if platformOverride != nil {
+		return platformOverride, nil
+	}
 	return createPlatformInstaller()
 }
 
 // GetServiceManager returns a platform-specific service manager
 func GetServiceManager() (ServiceManager, error) {
+	if serviceManagerOverride != nil {
+		return serviceManagerOverride, nil
+	}
 	return newServiceManager()
 }
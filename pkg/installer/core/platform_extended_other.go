@@ -3,8 +3,6 @@
 
 package core
 
-import "fmt"
-
 // UnixExtendedInstaller is a stub for non-Windows platforms
 type UnixExtendedInstaller struct {
 	PlatformInstaller
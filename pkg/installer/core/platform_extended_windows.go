@@ -5,7 +5,6 @@ package core
 
 import (
 	"fmt"
-	"os"
 	
 	"golang.org/x/sys/windows/registry"
 )
@@ -81,7 +80,7 @@ func (w *WindowsExtendedInstaller) SetEnv(key, value string, system bool) error
 		}
 		
 		// Notify the system about the change
-		notifyEnvironmentChange()
+		BroadcastEnvironmentChange()
 	} else {
 		// User environment variable
 		regKey, err := registry.OpenKey(registry.CURRENT_USER,
@@ -97,7 +96,7 @@ func (w *WindowsExtendedInstaller) SetEnv(key, value string, system bool) error
 		}
 		
 		// Notify the system about the change
-		notifyEnvironmentChange()
+		BroadcastEnvironmentChange()
 	}
 	
 	return nil
@@ -120,7 +119,7 @@ func (w *WindowsExtendedInstaller) UnsetEnv(key string, system bool) error {
 		}
 		
 		// Notify the system about the change
-		notifyEnvironmentChange()
+		BroadcastEnvironmentChange()
 	} else {
 		// User environment variable
 		regKey, err := registry.OpenKey(registry.CURRENT_USER,
@@ -136,21 +135,12 @@ func (w *WindowsExtendedInstaller) UnsetEnv(key string, system bool) error {
 		}
 		
 		// Notify the system about the change
-		notifyEnvironmentChange()
+		BroadcastEnvironmentChange()
 	}
 	
 	return nil
 }
 
-// notifyEnvironmentChange broadcasts WM_SETTINGCHANGE to notify about environment changes
-func notifyEnvironmentChange() {
-	// This is a simplified version - full implementation would use SendMessageTimeout
-	// For now, we'll rely on the user logging out/in or restarting
-	
-	// Try to set in current process at least
-	os.Setenv("PATH", os.Getenv("PATH"))
-}
-
 // CreateExtendedPlatformInstaller creates an extended platform installer for Windows
 func CreateExtendedPlatformInstaller() (PlatformInstaller, error) {
 	config := &Config{}
@@ -5,6 +5,9 @@ import "runtime"
 // CreatePlatformInstaller creates the appropriate platform installer
 // This is separated from individual platform files to avoid build issues
 func CreatePlatformInstaller(config *Config) PlatformInstaller {
+	if platformOverride != nil {
+		return platformOverride
+	}
 	switch runtime.GOOS {
 	case "windows":
 		return createWindowsPlatformInstaller(config)
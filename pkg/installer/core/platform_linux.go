@@ -15,8 +15,12 @@ import (
 // LinuxPlatformInstaller implements PlatformInstaller for Linux
 type LinuxPlatformInstaller struct {
 	config *Config
+
+	systemPathChanged bool // See RebootReasons
 }
 
+var _ RebootReporter = (*LinuxPlatformInstaller)(nil)
+
 // createLinuxPlatformInstaller is the internal factory function
 func createLinuxPlatformInstaller(config *Config) PlatformInstaller {
 	return &LinuxPlatformInstaller{
@@ -191,19 +195,10 @@ Categories=Application;
 }
 
 func (l *LinuxPlatformInstaller) CreateShortcuts() error {
-	// Create symbolic link in /usr/local/bin or ~/bin
-	binPath := "/usr/local/bin"
-	if !l.IsElevated() {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return err
-		}
-		binPath = filepath.Join(home, "bin")
-		
-		// Create ~/bin if it doesn't exist
-		if err := os.MkdirAll(binPath, 0755); err != nil {
-			return err
-		}
+	// Create symbolic link in /usr/local/bin or, installed just for the user, ~/bin
+	binPath, _ := l.config.ShortcutDirs()
+	if err := os.MkdirAll(binPath, 0755); err != nil {
+		return err
 	}
 	
 	// Create symlink
@@ -300,6 +295,8 @@ func (l *LinuxPlatformInstaller) UpdatePath(dirs []string, system bool) error {
 		if err := l.updateRCFile(rcFile, pathLine); err != nil {
 			// Log warning - logger might not be available here
 			// l.config.Logger.Warn("Failed to update PATH in file", "file", rcFile, "error", err)
+		} else if system {
+			l.systemPathChanged = true
 		}
 	}
 	
@@ -377,7 +374,11 @@ func (l *LinuxPlatformInstaller) AddToPath(dir string, system bool) error {
 		scriptPath := filepath.Join("/etc/profile.d", scriptName)
 		scriptContent := fmt.Sprintf("#!/bin/sh\nexport PATH=\"%s:$PATH\"\n", dir)
 		
-		return os.WriteFile(scriptPath, []byte(scriptContent), 0644)
+		if err := os.WriteFile(scriptPath, []byte(scriptContent), 0644); err != nil {
+			return err
+		}
+		l.systemPathChanged = true
+		return nil
 	} else {
 		// Add to user's shell config
 		home, err := os.UserHomeDir()
@@ -412,6 +413,16 @@ func (l *LinuxPlatformInstaller) AddToPath(dir string, system bool) error {
 	}
 }
 
+// RebootReasons implements RebootReporter. The system PATH is set when a
+// session starts, so the running sessions only see a changed one after a
+// restart.
+func (l *LinuxPlatformInstaller) RebootReasons() []string {
+	if !l.systemPathChanged {
+		return nil
+	}
+	return []string{"The system PATH changes for new sessions after a restart"}
+}
+
 // RemoveFromPath removes a directory from the PATH environment variable
 func (l *LinuxPlatformInstaller) RemoveFromPath(dir string, system bool) error {
 	if system {
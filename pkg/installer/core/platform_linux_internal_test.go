@@ -0,0 +1,35 @@
+//go:build linux
+// +build linux
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLinuxPathRebootReasons tests that only a changed system PATH asks for a restart
+func TestLinuxPathRebootReasons(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.WriteFile(filepath.Join(home, ".profile"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	platform := NewLinuxPlatformInstaller(&Config{AppName: "PathApp", InstallDir: "/opt/pathapp"}).(*LinuxPlatformInstaller)
+
+	if err := platform.UpdatePath([]string{"bin"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := platform.AddToPath("/opt/pathapp/tools", false); err != nil {
+		t.Fatal(err)
+	}
+	if reasons := platform.RebootReasons(); reasons != nil {
+		t.Errorf("RebootReasons() after a user PATH change = %q, want none", reasons)
+	}
+
+	platform.systemPathChanged = true
+	if reasons := platform.RebootReasons(); len(reasons) != 1 {
+		t.Errorf("RebootReasons() after a system PATH change = %q, want one", reasons)
+	}
+}
@@ -0,0 +1,323 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MockPlatformInstaller records the platform operations of an installation
+// instead of performing them. Install it with SetPlatformInstaller.
+//
+// Calls are recorded as "Method(arg, ...)", e.g. "AddToPath(/opt/app/bin, false)".
+// Errors programs the result of a method by name; the PATH operations keep
+// track of the entries so IsInPath answers consistently.
+type MockPlatformInstaller struct {
+	Elevated       bool             // Result of IsElevated
+	NeedsElevation bool             // Result of RequiresElevation
+	Elevatable     bool             // Result of CanElevate
+	Errors         map[string]error // Result of methods by name, nil if missing
+	PendingReboots []string         // Result of RebootReasons
+
+	mu    sync.Mutex
+	calls []string
+	path  map[string]bool
+	env   map[string]string
+}
+
+// NewMockPlatformInstaller creates a mock that succeeds in everything
+func NewMockPlatformInstaller() *MockPlatformInstaller {
+	return &MockPlatformInstaller{Errors: make(map[string]error)}
+}
+
+// record notes a call and returns the programmed error of method
+func (m *MockPlatformInstaller) record(method string, args ...interface{}) error {
+	m.calls = append(m.calls, formatCall(method, args))
+	return m.Errors[method]
+}
+
+// Calls returns the recorded calls in order
+func (m *MockPlatformInstaller) Calls() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.calls...)
+}
+
+// Reset forgets the recorded calls and the PATH and environment state
+func (m *MockPlatformInstaller) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls, m.path, m.env = nil, nil, nil
+}
+
+// Env returns the value of an environment variable set through SetEnv
+func (m *MockPlatformInstaller) Env(key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.env[key]
+	return value, ok
+}
+
+func (m *MockPlatformInstaller) Initialize() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("Initialize")
+}
+
+func (m *MockPlatformInstaller) CheckRequirements() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("CheckRequirements")
+}
+
+func (m *MockPlatformInstaller) IsElevated() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.record("IsElevated")
+	return m.Elevated
+}
+
+func (m *MockPlatformInstaller) RequiresElevation() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.record("RequiresElevation")
+	return m.NeedsElevation
+}
+
+func (m *MockPlatformInstaller) RequestElevation() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("RequestElevation"); err != nil {
+		return err
+	}
+	m.Elevated = true
+	return nil
+}
+
+// RebootReasons implements RebootReporter. The query is not recorded as a call.
+func (m *MockPlatformInstaller) RebootReasons() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.PendingReboots
+}
+
+func (m *MockPlatformInstaller) RegisterWithOS() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("RegisterWithOS")
+}
+
+func (m *MockPlatformInstaller) CreateShortcuts() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("CreateShortcuts")
+}
+
+func (m *MockPlatformInstaller) RegisterUninstaller() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("RegisterUninstaller")
+}
+
+func (m *MockPlatformInstaller) UpdatePath(dirs []string, system bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("UpdatePath", strings.Join(dirs, ";"), system); err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		m.setPath(dir, system, true)
+	}
+	return nil
+}
+
+func (m *MockPlatformInstaller) AddToPath(dir string, system bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("AddToPath", dir, system); err != nil {
+		return err
+	}
+	m.setPath(dir, system, true)
+	return nil
+}
+
+func (m *MockPlatformInstaller) RemoveFromPath(dir string, system bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("RemoveFromPath", dir, system); err != nil {
+		return err
+	}
+	m.setPath(dir, system, false)
+	return nil
+}
+
+func (m *MockPlatformInstaller) IsInPath(dir string, system bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.record("IsInPath", dir, system)
+	return m.path[pathKey(dir, system)]
+}
+
+func (m *MockPlatformInstaller) CanElevate() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.record("CanElevate")
+	return m.Elevatable
+}
+
+func (m *MockPlatformInstaller) WriteRegistryString(key, valueName, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("WriteRegistryString", key, valueName, value)
+}
+
+func (m *MockPlatformInstaller) DeleteRegistryValue(key, valueName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("DeleteRegistryValue", key, valueName)
+}
+
+func (m *MockPlatformInstaller) SetEnv(key, value string, system bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("SetEnv", key, value, system); err != nil {
+		return err
+	}
+	if m.env == nil {
+		m.env = make(map[string]string)
+	}
+	m.env[key] = value
+	return nil
+}
+
+func (m *MockPlatformInstaller) UnsetEnv(key string, system bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("UnsetEnv", key, system); err != nil {
+		return err
+	}
+	delete(m.env, key)
+	return nil
+}
+
+// setPath adds or removes a PATH entry (assumes lock held)
+func (m *MockPlatformInstaller) setPath(dir string, system, present bool) {
+	if m.path == nil {
+		m.path = make(map[string]bool)
+	}
+	if present {
+		m.path[pathKey(dir, system)] = true
+	} else {
+		delete(m.path, pathKey(dir, system))
+	}
+}
+
+func pathKey(dir string, system bool) string {
+	return fmt.Sprintf("%t:%s", system, dir)
+}
+
+// MockServiceManager records service operations instead of performing them.
+// Install it with SetServiceManager. Calls are recorded like those of
+// MockPlatformInstaller; installed services and their status are tracked.
+type MockServiceManager struct {
+	Errors map[string]error // Result of methods by name, nil if missing
+
+	mu       sync.Mutex
+	calls    []string
+	services map[string]ServiceStatus
+}
+
+// NewMockServiceManager creates a mock that succeeds in everything
+func NewMockServiceManager() *MockServiceManager {
+	return &MockServiceManager{
+		Errors:   make(map[string]error),
+		services: make(map[string]ServiceStatus),
+	}
+}
+
+// record notes a call and returns the programmed error of method
+func (m *MockServiceManager) record(method string, args ...interface{}) error {
+	m.calls = append(m.calls, formatCall(method, args))
+	return m.Errors[method]
+}
+
+// Calls returns the recorded calls in order
+func (m *MockServiceManager) Calls() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.calls...)
+}
+
+// Installed reports whether a service is installed
+func (m *MockServiceManager) Installed(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.services[name]
+	return ok
+}
+
+func (m *MockServiceManager) Install(config *ServiceConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("Install", config.Name); err != nil {
+		return err
+	}
+	if _, exists := m.services[config.Name]; exists {
+		return fmt.Errorf("service %s already exists", config.Name)
+	}
+	m.services[config.Name] = ServiceStopped
+	return nil
+}
+
+func (m *MockServiceManager) Uninstall(name string) error {
+	return m.change("Uninstall", name, func() { delete(m.services, name) })
+}
+
+func (m *MockServiceManager) Start(name string) error {
+	return m.change("Start", name, func() { m.services[name] = ServiceRunning })
+}
+
+func (m *MockServiceManager) Stop(name string) error {
+	return m.change("Stop", name, func() { m.services[name] = ServiceStopped })
+}
+
+func (m *MockServiceManager) Status(name string) (ServiceStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("Status", name); err != nil {
+		return ServiceUnknown, err
+	}
+	status, exists := m.services[name]
+	if !exists {
+		return ServiceUnknown, fmt.Errorf("service %s does not exist", name)
+	}
+	return status, nil
+}
+
+// change records a call on an installed service and applies it
+func (m *MockServiceManager) change(method, name string, apply func()) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record(method, name); err != nil {
+		return err
+	}
+	if _, exists := m.services[name]; !exists {
+		return fmt.Errorf("service %s does not exist", name)
+	}
+	apply()
+	return nil
+}
+
+// formatCall renders a recorded call as "Method(arg, ...)"
+func formatCall(method string, args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprint(arg)
+	}
+	return method + "(" + strings.Join(parts, ", ") + ")"
+}
+
+var (
+	_ ExtendedPlatformInstaller = (*MockPlatformInstaller)(nil)
+	_ ServiceManager            = (*MockServiceManager)(nil)
+)
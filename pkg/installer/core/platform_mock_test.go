@@ -0,0 +1,155 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// useMocks installs mock platform implementations for the duration of the test
+func useMocks(t *testing.T) (*core.MockPlatformInstaller, *core.MockServiceManager) {
+	t.Helper()
+	platform := core.NewMockPlatformInstaller()
+	services := core.NewMockServiceManager()
+	core.SetPlatformInstaller(platform)
+	core.SetServiceManager(services)
+	t.Cleanup(func() {
+		core.SetPlatformInstaller(nil)
+		core.SetServiceManager(nil)
+	})
+	return platform, services
+}
+
+// serviceConfig installs the "agent" component, which registers a service
+func serviceConfig(t *testing.T) *core.Config {
+	installDir := t.TempDir()
+	binDir := filepath.Join(installDir, "bin")
+	return &core.Config{
+		AppName:    "MockApp",
+		Version:    "1.0.0",
+		InstallDir: installDir,
+		Rollback:   core.RollbackNone,
+		PathConfig: &core.PathConfiguration{Enabled: true, Dirs: []string{binDir}},
+		Components: []core.Component{
+			{ID: "agent", Name: "Agent", Required: true, Services: []string{"mock-agent"},
+				Installer: func(ctx context.Context) error {
+					manager, err := core.GetServiceManager()
+					if err != nil {
+						return err
+					}
+					if err := manager.Install(&core.ServiceConfig{Name: "mock-agent", Executable: filepath.Join(binDir, "agent")}); err != nil {
+						return err
+					}
+					return manager.Start("mock-agent")
+				}},
+		},
+	}
+}
+
+// runUI installs when run, like the real UIs
+type runUI struct {
+	testUI
+	installer      *core.Installer
+	grantElevation bool
+}
+
+func (u *runUI) Initialize(ctx *core.Context) error {
+	u.installer = ctx.Metadata["installer"].(*core.Installer)
+	return nil
+}
+
+func (u *runUI) Run() error                            { return u.installer.ExecuteInstallation() }
+func (u *runUI) RequestElevation(string) (bool, error) { return u.grantElevation, nil }
+
+// runInstaller installs config through Installer.Run, which sets up the platform
+func runInstaller(t *testing.T, config *core.Config, ui *runUI) error {
+	t.Helper()
+	core.RegisterUIFactory(func(core.Mode) (core.UI, error) { return ui, nil })
+	t.Cleanup(func() { core.RegisterUIFactory(nil) })
+	return core.New(config).Run(context.Background())
+}
+
+func assertCalls(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s calls = %q, want %q", what, got, want)
+	}
+}
+
+// TestMockPlatformInstallAndUninstall tests that install and uninstall reach the injected platform
+func TestMockPlatformInstallAndUninstall(t *testing.T) {
+	platform, services := useMocks(t)
+	config := serviceConfig(t)
+	binDir := config.PathConfig.Dirs[0]
+
+	if err := runInstaller(t, config, &runUI{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	assertCalls(t, "platform", platform.Calls(), []string{
+		"Initialize()",
+		"CheckRequirements()",
+		"RegisterWithOS()",
+		"UpdatePath(" + binDir + ", false)",
+		"CreateShortcuts()",
+		"RegisterUninstaller()",
+	})
+	assertCalls(t, "service", services.Calls(), []string{"Install(mock-agent)", "Start(mock-agent)"})
+	if !platform.IsInPath(binDir, false) {
+		t.Error("bin directory not added to the PATH")
+	}
+
+	platform.Reset()
+	uninstaller, err := core.NewUninstaller(config)
+	if err != nil {
+		t.Fatalf("NewUninstaller() error = %v", err)
+	}
+	plan, err := uninstaller.PreviewRemoval()
+	if err != nil {
+		t.Fatalf("PreviewRemoval() error = %v", err)
+	}
+	if err := uninstaller.Uninstall(plan, core.UninstallOptions{}); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	assertCalls(t, "platform", platform.Calls(), []string{"RemoveFromPath(" + binDir + ", false)"})
+	assertCalls(t, "service", services.Calls(), []string{
+		"Install(mock-agent)", "Start(mock-agent)", "Stop(mock-agent)", "Uninstall(mock-agent)",
+	})
+	if services.Installed("mock-agent") {
+		t.Error("service still installed after uninstall")
+	}
+}
+
+// TestMockPlatformProgrammedErrors tests that programmed results reach the installer
+func TestMockPlatformProgrammedErrors(t *testing.T) {
+	platform, _ := useMocks(t)
+	platform.Errors["CheckRequirements"] = errors.New("unsupported OS version")
+
+	err := runInstaller(t, serviceConfig(t), &runUI{})
+	if err == nil || !errors.Is(err, platform.Errors["CheckRequirements"]) {
+		t.Fatalf("Run() error = %v, want the requirements error", err)
+	}
+	assertCalls(t, "platform", platform.Calls(), []string{"Initialize()", "CheckRequirements()"})
+}
+
+// TestMockPlatformElevation tests that the elevation strategy consults the platform
+func TestMockPlatformElevation(t *testing.T) {
+	platform, _ := useMocks(t)
+	platform.NeedsElevation = true
+	config := serviceConfig(t)
+	config.ElevationStrategy = core.ElevationAuto
+
+	if err := runInstaller(t, config, &runUI{grantElevation: true}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	calls := platform.Calls()
+	if len(calls) < 4 || calls[2] != "RequiresElevation()" || calls[3] != "RequestElevation()" {
+		t.Errorf("platform calls = %q, want elevation after the requirements check", calls)
+	}
+	if !platform.Elevated {
+		t.Error("mock not elevated after RequestElevation")
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"strings"
 	"syscall"
 	"time"
-	"unsafe"
 
 	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/registry"
@@ -20,8 +19,12 @@ import (
 // WindowsPlatformInstaller implements PlatformInstaller for Windows
 type WindowsPlatformInstaller struct {
 	config *Config
+
+	systemPathChanged bool // See RebootReasons
 }
 
+var _ RebootReporter = (*WindowsPlatformInstaller)(nil)
+
 // createWindowsPlatformInstaller is the internal factory function
 func createWindowsPlatformInstaller(config *Config) PlatformInstaller {
 	return &WindowsPlatformInstaller{
@@ -153,8 +156,13 @@ func (w *WindowsPlatformInstaller) RegisterWithOS() error {
 	// Add to Windows registry for Add/Remove Programs
 	keyPath := fmt.Sprintf(`SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall\%s`, w.config.AppName)
 
-	key, _, err := registry.CreateKey(registry.LOCAL_MACHINE, keyPath, registry.ALL_ACCESS)
-	if err != nil {
+	// Installations just for the current user register with it only
+	root := registry.LOCAL_MACHINE
+	if w.config.InstallScope == ScopePerUser {
+		root = registry.CURRENT_USER
+	}
+	key, _, err := registry.CreateKey(root, keyPath, registry.ALL_ACCESS)
+	if err != nil && root == registry.LOCAL_MACHINE {
 		// Try current user if local machine fails
 		key, _, err = registry.CreateKey(registry.CURRENT_USER, keyPath, registry.ALL_ACCESS)
 		if err != nil {
@@ -177,7 +185,7 @@ func (w *WindowsPlatformInstaller) RegisterWithOS() error {
 	var totalSize int64
 	for _, comp := range w.config.Components {
 		if comp.Selected || comp.Required {
-			totalSize += comp.Size
+			totalSize += comp.DiskSize()
 		}
 	}
 	key.SetDWordValue("EstimatedSize", uint32(totalSize/1024))
@@ -186,58 +194,47 @@ func (w *WindowsPlatformInstaller) RegisterWithOS() error {
 }
 
 func (w *WindowsPlatformInstaller) CreateShortcuts() error {
-	// Create Start Menu shortcuts
-	startMenuPath := os.Getenv("APPDATA")
-	if startMenuPath == "" {
-		return fmt.Errorf("could not determine Start Menu path")
-	}
-
-	startMenuDir := filepath.Join(startMenuPath, "Microsoft", "Windows", "Start Menu", "Programs", w.config.AppName)
+	// Create Start Menu shortcuts, for all users or the current one by install scope
+	startMenuDir, desktopPath := w.config.ShortcutDirs()
 	if err := os.MkdirAll(startMenuDir, 0755); err != nil {
 		return fmt.Errorf("failed to create Start Menu directory: %w", err)
 	}
 
-	// Create shortcut using PowerShell
 	shortcutPath := filepath.Join(startMenuDir, w.config.AppName+".lnk")
 	targetPath := filepath.Join(w.config.InstallDir, w.config.AppName+".exe")
-
-	psScript := fmt.Sprintf(`
-		$WshShell = New-Object -comObject WScript.Shell
-		$Shortcut = $WshShell.CreateShortcut("%s")
-		$Shortcut.TargetPath = "%s"
-		$Shortcut.WorkingDirectory = "%s"
-		$Shortcut.IconLocation = "%s,0"
-		$Shortcut.Save()
-	`, shortcutPath, targetPath, w.config.InstallDir, targetPath)
-
-	cmd := exec.Command("powershell", "-Command", psScript)
-	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-
-	if err := cmd.Run(); err != nil {
+	if err := createWindowsShortcut(shortcutPath, targetPath, w.config.InstallDir); err != nil {
 		return fmt.Errorf("failed to create shortcut: %w", err)
 	}
 
 	// Optionally create Desktop shortcut
-	desktopPath := filepath.Join(os.Getenv("USERPROFILE"), "Desktop")
 	if desktopPath != "" {
 		desktopShortcut := filepath.Join(desktopPath, w.config.AppName+".lnk")
-		psScript = fmt.Sprintf(`
-			$WshShell = New-Object -comObject WScript.Shell
-			$Shortcut = $WshShell.CreateShortcut("%s")
-			$Shortcut.TargetPath = "%s"
-			$Shortcut.WorkingDirectory = "%s"
-			$Shortcut.IconLocation = "%s,0"
-			$Shortcut.Save()
-		`, desktopShortcut, targetPath, w.config.InstallDir, targetPath)
-
-		cmd = exec.Command("powershell", "-Command", psScript)
-		cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-		cmd.Run() // Ignore error for desktop shortcut
+		createWindowsShortcut(desktopShortcut, targetPath, w.config.InstallDir) // Ignore error for desktop shortcut
 	}
 
 	return nil
 }
 
+// createWindowsShortcut creates a .lnk file at path pointing to program
+func createWindowsShortcut(path, program, workDir string) error {
+	quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+	psScript := fmt.Sprintf(`
+		$WshShell = New-Object -comObject WScript.Shell
+		$Shortcut = $WshShell.CreateShortcut(%s)
+		$Shortcut.TargetPath = %s
+		$Shortcut.WorkingDirectory = %s
+		$Shortcut.IconLocation = %s
+		$Shortcut.Save()
+	`, quote(path), quote(program), quote(workDir), quote(program+",0"))
+
+	cmd := exec.Command("powershell", "-NoProfile", "-Command", psScript)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
 func (w *WindowsPlatformInstaller) RegisterUninstaller() error {
 	// Create uninstaller executable
 	// uninstallerPath := filepath.Join(w.config.InstallDir, "uninstall.exe")
@@ -310,8 +307,12 @@ func (w *WindowsPlatformInstaller) UpdatePath(dirs []string, system bool) error
 		return fmt.Errorf("failed to update PATH: %w", err)
 	}
 
-	// Broadcast environment change
-	broadcastEnvironmentChange()
+	// Broadcast environment change; a window that does not answer must
+	// not fail the PATH change
+	BroadcastEnvironmentChange()
+	if system {
+		w.systemPathChanged = true
+	}
 
 	return nil
 }
@@ -325,31 +326,6 @@ func isWindows10OrLater() bool {
 	return ver.MajorVersion >= 10
 }
 
-func broadcastEnvironmentChange() {
-	// Notify all windows about environment change
-	// Note: SendMessage is not available in syscall package
-	// We need to use Windows API directly
-
-	// Define constants
-	const (
-		HWND_BROADCAST   = 0xFFFF
-		WM_SETTINGCHANGE = 0x001A
-	)
-
-	// Load user32.dll
-	user32 := windows.NewLazySystemDLL("user32.dll")
-	sendMessageW := user32.NewProc("SendMessageW")
-
-	// Send the message
-	envPtr, _ := windows.UTF16PtrFromString("Environment")
-	sendMessageW.Call(
-		uintptr(HWND_BROADCAST),
-		uintptr(WM_SETTINGCHANGE),
-		0,
-		uintptr(unsafe.Pointer(envPtr)),
-	)
-}
-
 // AddToPath adds a directory to the PATH environment variable
 func (w *WindowsPlatformInstaller) AddToPath(dir string, system bool) error {
 	// Determine registry root
@@ -393,12 +369,36 @@ func (w *WindowsPlatformInstaller) AddToPath(dir string, system bool) error {
 		return fmt.Errorf("failed to update PATH: %w", err)
 	}
 
-	// Broadcast environment change
-	broadcastEnvironmentChange()
+	// Broadcast environment change; a window that does not answer must
+	// not fail the PATH change
+	BroadcastEnvironmentChange()
+	if system {
+		w.systemPathChanged = true
+	}
 
 	return nil
 }
 
+// RebootReasons implements RebootReporter. The broadcast reaches running
+// programs, but the service control manager reads the system PATH at boot:
+// the services of the installed components only see a changed one after a
+// restart.
+func (w *WindowsPlatformInstaller) RebootReasons() []string {
+	if !w.systemPathChanged {
+		return nil
+	}
+	var reasons []string
+	for _, c := range w.config.Components {
+		if !c.Selected && !c.Required {
+			continue
+		}
+		for _, service := range c.Services {
+			reasons = append(reasons, fmt.Sprintf("The %s service sees the changed system PATH after a restart", service))
+		}
+	}
+	return reasons
+}
+
 // RemoveFromPath removes a directory from the PATH environment variable
 func (w *WindowsPlatformInstaller) RemoveFromPath(dir string, system bool) error {
 	// Determine registry root
@@ -442,8 +442,9 @@ func (w *WindowsPlatformInstaller) RemoveFromPath(dir string, system bool) error
 		return fmt.Errorf("failed to update PATH: %w", err)
 	}
 
-	// Broadcast environment change
-	broadcastEnvironmentChange()
+	// Broadcast environment change; a window that does not answer must
+	// not fail the PATH change
+	BroadcastEnvironmentChange()
 
 	return nil
 }
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// PortableConfigFile is the settings file a portable installation writes
+// into its directory instead of registering with the system. It has the
+// format of a response file, see ReadResponseFile.
+const PortableConfigFile = "portable.cfg"
+
+// Settings written to PortableConfigFile besides the shared setting keys
+const (
+	PortableSettingAppName    = "app_name"
+	PortableSettingVersion    = "version"
+	PortableSettingComponents = "components" // Comma-separated IDs of the installed components
+)
+
+// PortableConfigPath returns the portable configuration of an install directory
+func PortableConfigPath(installDir string) string {
+	return filepath.Join(installDir, PortableConfigFile)
+}
+
+// PortableCapable reports whether the component can be installed in the
+// given mode: portable installations leave out components that install
+// system services
+func (c Component) PortableCapable(portable bool) bool {
+	return !portable || len(c.Services) == 0
+}
+
+// CheckPortable rejects required components that a portable installation
+// would leave out, see PortableCapable
+func CheckPortable(config *Config) error {
+	for _, c := range config.Components {
+		if c.Required && !c.PortableCapable(config.Portable) {
+			return fmt.Errorf("component %s is required but installs services, which a portable installation leaves out", c.ID)
+		}
+	}
+	return nil
+}
+
+// PortableComponents returns the components of a portable installation,
+// those of components that are PortableCapable, or all of them otherwise
+func PortableComponents(components []Component, portable bool) []Component {
+	if !portable {
+		return components
+	}
+	var capable []Component
+	for _, c := range components {
+		if c.PortableCapable(true) {
+			capable = append(capable, c)
+		}
+	}
+	return capable
+}
+
+// writePortableConfig records the installation in PortableConfigFile. The
+// install directory is left out so that the folder can be moved.
+func (i *Installer) writePortableConfig() error {
+	var ids []string
+	for _, c := range i.getComponentsToInstall() {
+		ids = append(ids, c.ID)
+	}
+	settings := Settings{
+		SettingPortable:           "true",
+		PortableSettingAppName:    i.config.AppName,
+		PortableSettingVersion:    i.config.Version,
+		PortableSettingComponents: strings.Join(ids, ","),
+	}
+	if i.config.Locale != "" {
+		settings[SettingLocale] = i.config.Locale
+	}
+	if i.config.Profile != "" {
+		settings[SettingProfile] = i.config.Profile
+	}
+	if err := WriteResponseFile(PortableConfigPath(i.config.InstallDir), settings); err != nil {
+		return fmt.Errorf("failed to write portable configuration: %w", err)
+	}
+	return nil
+}
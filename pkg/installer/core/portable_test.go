@@ -0,0 +1,98 @@
+package core_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestPortableInstall tests that a portable installation touches no PATH, registry, shortcuts or services
+func TestPortableInstall(t *testing.T) {
+	platform, services := useMocks(t)
+	config := serviceConfig(t)
+	config.Portable = true
+	config.CreateRestorePoint = true
+	// The service component is left out, which it may only be when optional
+	config.Components[0].Required, config.Components[0].Selected = false, true
+	config.Components = append(config.Components, core.Component{ID: "app", Name: "App", Required: true})
+
+	if err := runInstaller(t, config, &runUI{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	assertCalls(t, "platform", platform.Calls(), []string{"Initialize()", "CheckRequirements()"})
+	if calls := services.Calls(); len(calls) > 0 {
+		t.Errorf("service calls = %q, want none", calls)
+	}
+
+	settings, err := core.ReadResponseFile(core.PortableConfigPath(config.InstallDir))
+	if err != nil {
+		t.Fatalf("portable configuration not written: %v", err)
+	}
+	if settings[core.SettingPortable] != "true" || settings[core.PortableSettingComponents] != "app" {
+		t.Errorf("portable configuration = %v, want portable with component app only", settings)
+	}
+	if _, ok := settings[core.SettingInstallDir]; ok {
+		t.Error("portable configuration should not pin the install directory")
+	}
+
+	manifest, err := core.LoadManifest(config.InstallDir)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if len(manifest.PathEntries) > 0 || len(manifest.RegistryKeys) > 0 || len(manifest.Services) > 0 {
+		t.Errorf("manifest records system changes: path=%v registry=%v services=%v",
+			manifest.PathEntries, manifest.RegistryKeys, manifest.Services)
+	}
+	if reqs := core.PrivilegesFor(config, config.Components, config.InstallDir); len(reqs) > 0 {
+		t.Errorf("PrivilegesFor() = %v, want none for a portable installation", reqs)
+	}
+
+	uninstaller, err := core.NewUninstaller(config)
+	if err != nil {
+		t.Fatalf("NewUninstaller() error = %v", err)
+	}
+	plan, err := uninstaller.PreviewRemoval()
+	if err != nil {
+		t.Fatalf("PreviewRemoval() error = %v", err)
+	}
+	if err := uninstaller.Uninstall(plan, core.UninstallOptions{}); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if _, err := os.Stat(core.PortableConfigPath(config.InstallDir)); !os.IsNotExist(err) {
+		t.Error("portable configuration left behind by uninstall")
+	}
+}
+
+// TestPortablePlan tests that the plan of a portable installation leaves out system changes
+func TestPortablePlan(t *testing.T) {
+	config := serviceConfig(t)
+	config.Portable = true
+
+	ops := core.New(config).PlannedOperations()
+	want := []string{"install to " + config.InstallDir, "write portable configuration"}
+	assertCalls(t, "planned", ops, want)
+}
+
+// TestPortableRequiredServices tests that a required component with services
+// is rejected instead of left out of a portable installation
+func TestPortableRequiredServices(t *testing.T) {
+	useMocks(t)
+	config := serviceConfig(t)
+	config.Portable = true
+
+	if _, err := core.ValidateComponents(config); err == nil {
+		t.Error("ValidateComponents() accepted a required component with services")
+	}
+	if err := runInstaller(t, config, &runUI{}); err == nil {
+		t.Error("Run() installed without a required component")
+	}
+
+	config.Components[0].Required = false
+	if _, err := core.ValidateComponents(config); err != nil {
+		t.Errorf("ValidateComponents() error = %v for an optional component with services", err)
+	}
+	if got := core.PortableComponents(config.Components, true); len(got) != 0 {
+		t.Errorf("PortableComponents() = %v, want the service component left out", got)
+	}
+}
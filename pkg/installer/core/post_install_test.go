@@ -0,0 +1,98 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// markerComponent returns a component whose post-install actions create and remove a marker file
+func markerComponent(id string) core.Component {
+	return core.Component{
+		ID:       id,
+		Name:     id,
+		Selected: true,
+		PostInstall: func(ctx context.Context, installDir string) error {
+			if err := os.MkdirAll(installDir, 0755); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(installDir, id+".registered"), []byte(id), 0644)
+		},
+		PostUninstall: func(ctx context.Context, installDir string) error {
+			return os.Remove(filepath.Join(installDir, id+".registered"))
+		},
+	}
+}
+
+// TestPostInstall tests that post-install actions run on install and are undone on uninstall
+func TestPostInstall(t *testing.T) {
+	config := &core.Config{
+		AppName:    "PostInstallApp",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{markerComponent("index")},
+	}
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	marker := filepath.Join(config.InstallDir, "index.registered")
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatalf("PostInstall did not run: %v", err)
+	}
+
+	uninstaller, err := core.NewUninstaller(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uninstaller.SetLogger(core.NewNullLogger())
+	plan, err := uninstaller.PreviewRemoval()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := uninstaller.Uninstall(plan, core.UninstallOptions{}); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("PostUninstall did not run on uninstall")
+	}
+}
+
+// TestPostInstallFailureRollsBack tests that a failing post-install action fails
+// the installation and rolls back the components installed so far
+func TestPostInstallFailureRollsBack(t *testing.T) {
+	failure := errors.New("registration refused")
+	undone := false
+	register := core.Component{
+		ID:       "register",
+		Name:     "register",
+		Selected: true,
+		PostInstall: func(ctx context.Context, installDir string) error {
+			return failure
+		},
+		PostUninstall: func(ctx context.Context, installDir string) error {
+			undone = true
+			return nil
+		},
+	}
+	config := &core.Config{
+		AppName:    "PostInstallApp",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		Rollback:   core.RollbackFull,
+		Components: []core.Component{markerComponent("index"), register},
+	}
+
+	err := newTestInstaller(config).ExecuteInstallation()
+	if !errors.Is(err, failure) {
+		t.Fatalf("ExecuteInstallation() error = %v, want %v", err, failure)
+	}
+	if _, err := os.Stat(filepath.Join(config.InstallDir, "index.registered")); !os.IsNotExist(err) {
+		t.Error("Post-install work of the first component was not rolled back")
+	}
+	if !undone {
+		t.Error("PostUninstall of the failed component did not run")
+	}
+}
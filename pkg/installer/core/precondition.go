@@ -0,0 +1,57 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrPreconditionNotMet reports a required component whose Precondition failed
+var ErrPreconditionNotMet = errors.New("precondition not met")
+
+// SkippedComponent is an optional component that was not installed because
+// its Precondition failed, or one of its dependencies was skipped
+type SkippedComponent struct {
+	ID     string
+	Name   string
+	Reason string // Why it was skipped, such as "the .NET 8 runtime is not installed"
+}
+
+// SkippedComponents returns the components the last installation skipped
+func (i *Installer) SkippedComponents() []SkippedComponent {
+	return append([]SkippedComponent(nil), i.skipped...)
+}
+
+// checkPrecondition runs the Precondition of component and reports whether
+// to skip it. A component depending on a skipped one fails as well. A failed
+// optional component is deselected and recorded in SkippedComponents, a
+// failed required one stops the installation with ErrPreconditionNotMet.
+func (i *Installer) checkPrecondition(ctx context.Context, component Component) (skip bool, err error) {
+	var reason error
+	for _, dep := range component.Dependencies {
+		for _, s := range i.skipped {
+			if s.ID == dep {
+				reason = fmt.Errorf("needs %s, which was skipped", s.Name)
+			}
+		}
+	}
+	if reason == nil && component.Precondition != nil {
+		reason = component.Precondition(ctx)
+	}
+	if reason == nil {
+		return false, nil
+	}
+
+	if component.Required {
+		return false, NewInstallError(fmt.Errorf("%w for %s: %w", ErrPreconditionNotMet, component.Name, reason),
+			PhaseFileCopy, component.ID)
+	}
+	i.context.Logger.Warn("Skipping component, its precondition is not met", "component", component.ID, "reason", reason)
+	i.skipped = append(i.skipped, SkippedComponent{ID: component.ID, Name: component.Name, Reason: reason.Error()})
+	for idx := range i.config.Components {
+		if i.config.Components[idx].ID == component.ID {
+			i.config.Components[idx].Selected = false
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,105 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+var errNoRuntime = errors.New("the .NET 8 runtime is not installed")
+
+// preconditionConfig returns a configuration whose tools component needs a missing runtime;
+// the installed and uninstalled components are recorded in calls
+func preconditionConfig(t *testing.T, calls *[]string, toolsRequired bool) *core.Config {
+	record := func(what string) func(context.Context) error {
+		return func(context.Context) error {
+			*calls = append(*calls, what)
+			return nil
+		}
+	}
+	return &core.Config{
+		AppName:    "PreApp",
+		Version:    "1.0.0",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackFull,
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Installer: record("install core"), Uninstaller: record("uninstall core")},
+			{ID: "tools", Name: "Tools", Required: toolsRequired, Selected: true, Installer: record("install tools"),
+				Precondition: func(context.Context) error { return errNoRuntime }},
+			{ID: "plugins", Name: "Plugins", Selected: true, Dependencies: []string{"tools"}, Installer: record("install plugins")},
+			{ID: "docs", Name: "Docs", Selected: true, Installer: record("install docs"),
+				Precondition: func(context.Context) error { return nil }},
+		},
+	}
+}
+
+// TestPreconditionSkipsOptionalComponent tests that an optional component whose precondition
+// fails, and the components depending on it, are skipped with the reason
+func TestPreconditionSkipsOptionalComponent(t *testing.T) {
+	var calls []string
+	config := preconditionConfig(t, &calls, false)
+	inst := newTestInstaller(config)
+	ui := &progressUI{}
+	inst.SetUI(ui)
+
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	assertCalls(t, "component", calls, []string{"install core", "install docs"})
+
+	want := []core.SkippedComponent{
+		{ID: "tools", Name: "Tools", Reason: errNoRuntime.Error()},
+		{ID: "plugins", Name: "Plugins", Reason: "needs Tools, which was skipped"},
+	}
+	if got := inst.SkippedComponents(); !slices.Equal(got, want) {
+		t.Errorf("SkippedComponents() = %v, want %v", got, want)
+	}
+	summary := inst.CreateSummary()
+	if !slices.Equal(summary.ComponentsSkipped, want) || !slices.Equal(summary.ComponentsInstalled, []string{"Core", "Docs"}) {
+		t.Errorf("summary skipped %v, installed %v", summary.ComponentsSkipped, summary.ComponentsInstalled)
+	}
+	if len(summary.Warnings) != 2 || summary.Warnings[0] != "Skipped Tools: "+errNoRuntime.Error() {
+		t.Errorf("summary warnings = %q", summary.Warnings)
+	}
+
+	// The progress screen tells why
+	var shown bool
+	for _, p := range ui.updates {
+		shown = shown || p.Message == "Skipped Tools: "+errNoRuntime.Error()
+	}
+	if !shown {
+		t.Error("progress never showed the skipped component")
+	}
+
+	manifest, err := core.LoadManifest(config.InstallDir)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if _, ok := manifest.Component("tools"); ok {
+		t.Error("skipped component recorded as installed")
+	}
+}
+
+// TestPreconditionAbortsRequiredComponent tests that a required component whose precondition
+// fails stops and rolls back the installation
+func TestPreconditionAbortsRequiredComponent(t *testing.T) {
+	var calls []string
+	config := preconditionConfig(t, &calls, true)
+
+	err := newTestInstaller(config).ExecuteInstallation()
+	if !errors.Is(err, core.ErrPreconditionNotMet) || !errors.Is(err, errNoRuntime) {
+		t.Fatalf("ExecuteInstallation() error = %v, want the precondition's reason", err)
+	}
+	var installErr *core.InstallError
+	if !errors.As(err, &installErr) || installErr.Component != "tools" || installErr.Kind != core.ErrorKindPrecondition || !installErr.RolledBack {
+		t.Errorf("ExecuteInstallation() error = %#v, want a rolled back precondition error of tools", installErr)
+	}
+	if !strings.Contains(err.Error(), "Tools") {
+		t.Errorf("error %q does not name the component", err)
+	}
+	assertCalls(t, "component", calls, []string{"install core", "uninstall core"})
+}
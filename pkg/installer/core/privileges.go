@@ -0,0 +1,127 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrivilegeKind identifies an operation that needs administrative rights
+type PrivilegeKind string
+
+const (
+	PrivilegeProtectedDir    PrivilegeKind = "protected-dir"    // Install directory below Program Files, /usr, ...
+	PrivilegeSystemPath      PrivilegeKind = "system-path"      // Change the machine-wide PATH
+	PrivilegeService         PrivilegeKind = "service"          // Install a system service
+	PrivilegeMachineRegistry PrivilegeKind = "machine-registry" // Write below HKEY_LOCAL_MACHINE
+	PrivilegeRestorePoint    PrivilegeKind = "restore-point"    // Create a System Restore point
+)
+
+// The summary screens list the RequiredPrivileges under PrivilegesHeading and
+// follow them with ElevationNotice, as the elevation prompt only appears once
+// the installation starts
+const (
+	PrivilegesHeading = "Administrator rights are required to:"
+	ElevationNotice   = "You will be asked to allow elevation when the installation starts."
+)
+
+// PrivilegeRequirement is an operation of the installation that requires elevation
+type PrivilegeRequirement struct {
+	Kind        PrivilegeKind
+	Description string
+	Component   string // ID of the component that causes it, empty for installer options
+}
+
+// RequiredPrivileges lists the operations of the current selection that need
+// administrative rights, so the summary can announce the elevation prompt
+func (i *Installer) RequiredPrivileges() []PrivilegeRequirement {
+	return PrivilegesFor(i.config, i.getComponentsToInstall(), i.config.InstallDir)
+}
+
+// PrivilegesFor lists the operations that need administrative rights when
+// components are installed into installDir with config
+func PrivilegesFor(config *Config, components []Component, installDir string) []PrivilegeRequirement {
+	return privilegesFor(config, components, installDir, currentScopeEnv())
+}
+
+func privilegesFor(config *Config, components []Component, installDir string, env scopeEnv) []PrivilegeRequirement {
+	var reqs []PrivilegeRequirement
+
+	protected := isProtectedDir(installDir, env)
+	if protected {
+		reqs = append(reqs, PrivilegeRequirement{
+			Kind:        PrivilegeProtectedDir,
+			Description: fmt.Sprintf("Install into %s, which only administrators can change", installDir),
+		})
+	}
+	if config.Portable {
+		// Nothing outside installDir is changed
+		return reqs
+	}
+	if pc := config.PathConfig; pc != nil && pc.Enabled && pc.System {
+		reqs = append(reqs, PrivilegeRequirement{
+			Kind:        PrivilegeSystemPath,
+			Description: "Add the application to the system-wide PATH",
+		})
+	}
+	for _, c := range components {
+		for _, service := range c.Services {
+			reqs = append(reqs, PrivilegeRequirement{
+				Kind:        PrivilegeService,
+				Description: fmt.Sprintf("Install the %s service", service),
+				Component:   c.ID,
+			})
+		}
+	}
+	if env.goos == "windows" && (protected || config.InstallScope == ScopePerMachine) {
+		reqs = append(reqs, PrivilegeRequirement{
+			Kind:        PrivilegeMachineRegistry,
+			Description: "Register the application for all users in HKEY_LOCAL_MACHINE",
+		})
+	}
+	if config.CreateRestorePoint && env.goos == "windows" {
+		reqs = append(reqs, PrivilegeRequirement{
+			Kind:        PrivilegeRestorePoint,
+			Description: "Create a system restore point",
+		})
+	}
+	return reqs
+}
+
+// isProtectedDir reports whether dir lies below a system location that only
+// administrators may write to
+func isProtectedDir(dir string, env scopeEnv) bool {
+	if dir == "" {
+		return false
+	}
+	var roots []string
+	switch env.goos {
+	case "windows":
+		for _, name := range []string{"ProgramFiles", "ProgramFiles(x86)", "ProgramData", "SystemRoot"} {
+			roots = append(roots, env.getenv(name))
+		}
+	case "darwin":
+		roots = []string{"/Library", "/System", "/usr", "/opt"}
+	default:
+		roots = []string{"/usr", "/opt", "/etc", "/srv", "/var"}
+	}
+	for _, root := range roots {
+		if isBelow(dir, root, env.goos == "windows") {
+			return true
+		}
+	}
+	return false
+}
+
+// isBelow reports whether path equals root or lies below it
+func isBelow(path, root string, windows bool) bool {
+	if root == "" {
+		return false
+	}
+	if windows {
+		path = strings.ToLower(strings.ReplaceAll(path, `\`, "/"))
+		root = strings.ToLower(strings.ReplaceAll(root, `\`, "/"))
+	}
+	path = strings.TrimSuffix(path, "/")
+	root = strings.TrimSuffix(root, "/")
+	return path == root || strings.HasPrefix(path, root+"/")
+}
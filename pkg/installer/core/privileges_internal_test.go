@@ -0,0 +1,30 @@
+package core
+
+import "testing"
+
+func TestIsProtectedDir(t *testing.T) {
+	windows := scopeEnv{goos: "windows", getenv: func(name string) string {
+		return map[string]string{"ProgramFiles": `C:\Program Files`, "SystemRoot": `C:\Windows`}[name]
+	}}
+	linux := scopeEnv{goos: "linux", getenv: func(string) string { return "" }}
+
+	tests := []struct {
+		env  scopeEnv
+		dir  string
+		want bool
+	}{
+		{windows, `C:\Program Files\App`, true},
+		{windows, `c:\program files\app\`, true},
+		{windows, `C:\Program Files Extra\App`, false},
+		{windows, `C:\Users\someone\AppData\Local\App`, false},
+		{linux, "/opt/app", true},
+		{linux, "/usr/local/app", true},
+		{linux, "/home/someone/.local/app", false},
+		{linux, "/optional/app", false},
+	}
+	for _, tt := range tests {
+		if got := isProtectedDir(tt.dir, tt.env); got != tt.want {
+			t.Errorf("isProtectedDir(%q, %s) = %v, want %v", tt.dir, tt.env.goos, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package core_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// hasPrivilege reports whether reqs contain kind, caused by component if given
+func hasPrivilege(reqs []core.PrivilegeRequirement, kind core.PrivilegeKind, component string) bool {
+	for _, req := range reqs {
+		if req.Kind == kind && req.Component == component {
+			return true
+		}
+	}
+	return false
+}
+
+// TestRequiredPrivileges tests that a system PATH change and a service install need elevation
+func TestRequiredPrivileges(t *testing.T) {
+	config := &core.Config{
+		AppName:    "PrivilegedApp",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		PathConfig: &core.PathConfiguration{Enabled: true, System: true, Dirs: []string{"bin"}},
+		Components: []core.Component{
+			{ID: "core", Required: true},
+			{ID: "agent", Selected: true, Services: []string{"PrivilegedAgent"}},
+			{ID: "monitor", Services: []string{"PrivilegedMonitor"}}, // Not selected
+		},
+	}
+
+	reqs := newTestInstaller(config).RequiredPrivileges()
+	if !hasPrivilege(reqs, core.PrivilegeSystemPath, "") {
+		t.Errorf("RequiredPrivileges() = %v, want system PATH", reqs)
+	}
+	if !hasPrivilege(reqs, core.PrivilegeService, "agent") {
+		t.Errorf("RequiredPrivileges() = %v, want service of agent", reqs)
+	}
+	if hasPrivilege(reqs, core.PrivilegeService, "monitor") {
+		t.Error("Service of an unselected component listed")
+	}
+	if hasPrivilege(reqs, core.PrivilegeProtectedDir, "") {
+		t.Error("Temporary directory reported as protected")
+	}
+}
+
+// TestRequiredPrivilegesUserPath tests that a per-user installation needs no elevation
+func TestRequiredPrivilegesUserPath(t *testing.T) {
+	config := &core.Config{
+		AppName:    "UserApp",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		PathConfig: &core.PathConfiguration{Enabled: true, Dirs: []string{"bin"}},
+		Components: []core.Component{{ID: "core", Required: true}},
+	}
+	if reqs := newTestInstaller(config).RequiredPrivileges(); len(reqs) != 0 {
+		t.Errorf("RequiredPrivileges() = %v, want none", reqs)
+	}
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ProgressReporterFromContext returns the reporter passed to install handlers
+// and component installers, so that e.g. file copy loops can report the bytes
+// copied. Outside an installation the reporter discards the reports.
+func ProgressReporterFromContext(ctx context.Context) ProgressReporter {
+	if reporter, ok := ctx.Value(contextKey("progress")).(ProgressReporter); ok {
+		return reporter
+	}
+	return discardProgress{}
+}
+
+// progressReportReporter is implemented by reporters that take the amount done,
+// the total and the message in one update, so the UI is not refreshed with a
+// half-updated state
+type progressReportReporter interface {
+	Report(done, total int64, message string)
+}
+
+// ReportProgress sets the amount done out of total and, if not empty, the
+// message on reporter, in a single update where the reporter supports it
+func ReportProgress(reporter ProgressReporter, done, total int64, message string) {
+	if r, ok := reporter.(progressReportReporter); ok {
+		r.Report(done, total, message)
+		return
+	}
+	reporter.SetTotal(total)
+	reporter.SetCurrent(done)
+	if message != "" {
+		reporter.SetMessage(message)
+	}
+}
+
+// ComponentPercent returns the progress of the current component in percent
+func (p *Progress) ComponentPercent() int {
+	return int(p.ComponentProgress * 100)
+}
+
+// OverallPercent returns the progress of the whole installation in percent
+func (p *Progress) OverallPercent() int {
+	return int(p.OverallProgress * 100)
+}
+
+// Status describes both, such as "Installing Core: 40% (overall 65%)"
+func (p *Progress) Status() string {
+	if p.ComponentName == "" {
+		return fmt.Sprintf("Installing: %d%%", p.OverallPercent())
+	}
+	return fmt.Sprintf("Installing %s: %d%% (overall %d%%)", p.ComponentName, p.ComponentPercent(), p.OverallPercent())
+}
+
+// discardProgress ignores reports
+type discardProgress struct{}
+
+func (discardProgress) SetTotal(total int64)                     {}
+func (discardProgress) SetCurrent(current int64)                 {}
+func (discardProgress) SetMessage(message string)                {}
+func (discardProgress) Done()                                    {}
+func (discardProgress) Error(err error)                          {}
+func (discardProgress) Report(done, total int64, message string) {}
+
+// componentProgress maps reports onto the progress of the component being
+// installed and passes them to the installer's UI
+type componentProgress struct {
+	mu        sync.Mutex
+	installer *Installer
+	progress  *Progress
+	index     int // Index of the component among those being installed
+	done      int64
+	total     int64
+}
+
+func (c *componentProgress) SetTotal(total int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total = total
+	c.show()
+}
+
+func (c *componentProgress) SetCurrent(current int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done = current
+	c.show()
+}
+
+func (c *componentProgress) SetMessage(message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.Message = message
+	c.show()
+}
+
+func (c *componentProgress) Done() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.total <= 0 {
+		c.total = 1
+	}
+	c.done = c.total
+	c.show()
+}
+
+func (c *componentProgress) Error(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.IsError = true
+	c.progress.Message = err.Error()
+	c.show()
+}
+
+func (c *componentProgress) Report(done, total int64, message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done, c.total = done, total
+	if message != "" {
+		c.progress.Message = message
+	}
+	c.show()
+}
+
+// show updates the component and overall progress and passes them to the UI (assumes lock held)
+func (c *componentProgress) show() {
+	fraction := 0.0
+	if c.total > 0 {
+		fraction = min(max(float64(c.done)/float64(c.total), 0), 1)
+	}
+	c.progress.ComponentProgress = fraction
+	c.progress.OverallProgress = (float64(c.index) + fraction) / float64(c.progress.TotalComponents)
+	if err := c.installer.ui.ShowProgress(c.progress); err != nil {
+		c.installer.context.Logger.Warn("Failed to update progress", "error", err)
+	}
+}
@@ -0,0 +1,155 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// progressUI records the progress updates shown during an installation
+type progressUI struct {
+	testUI
+	updates []core.Progress
+}
+
+func (u *progressUI) ShowProgress(p *core.Progress) error {
+	u.updates = append(u.updates, *p)
+	return nil
+}
+
+// copyHandler reports its progress like a file copy loop
+type copyHandler struct{}
+
+func (copyHandler) Install(ctx context.Context, installPath string, components []core.Component) error {
+	reporter := core.ProgressReporterFromContext(ctx)
+	for done := int64(1); done <= 4; done++ {
+		core.ReportProgress(reporter, done*256, 1024, fmt.Sprintf("Copying %s (%d/4)", components[0].ID, done))
+	}
+	return nil
+}
+
+// TestHandlerProgressReachesUI tests that reports from an install handler show up as installer progress
+func TestHandlerProgressReachesUI(t *testing.T) {
+	config := &core.Config{
+		AppName:    "ProgressApp",
+		Version:    "1.0.0",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true},
+			{ID: "docs", Name: "Docs", Selected: true},
+		},
+	}
+	inst := newTestInstaller(config)
+	ui := &progressUI{}
+	inst.SetUI(ui)
+	inst.AddInstallHandler(copyHandler{})
+
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+
+	// The installer's own update after each component repeats the last message
+	var reported []core.Progress
+	for n, p := range ui.updates {
+		if strings.HasPrefix(p.Message, "Copying") && p.Message != ui.updates[n-1].Message {
+			reported = append(reported, p)
+		}
+	}
+	if len(reported) != 8 {
+		t.Fatalf("got %d handler updates, want 8: %+v", len(reported), ui.updates)
+	}
+	wantOverall := []float64{0.125, 0.25, 0.375, 0.5, 0.625, 0.75, 0.875, 1}
+	for n, p := range reported {
+		if want := float64(n%4+1) / 4; p.ComponentProgress != want {
+			t.Errorf("update %d: ComponentProgress = %v, want %v", n, p.ComponentProgress, want)
+		}
+		if p.OverallProgress != wantOverall[n] {
+			t.Errorf("update %d: OverallProgress = %v, want %v", n, p.OverallProgress, wantOverall[n])
+		}
+	}
+	if reported[0].ComponentName != "Core" || reported[4].ComponentName != "Docs" {
+		t.Errorf("updates attributed to %q and %q", reported[0].ComponentName, reported[4].ComponentName)
+	}
+}
+
+// TestCopyProgress tests that copying the payload reports the component-local
+// and overall progress of each component by bytes
+func TestCopyProgress(t *testing.T) {
+	config := &core.Config{
+		AppName:    "CopyApp",
+		Version:    "1.0.0",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackNone,
+		Source: fstest.MapFS{
+			"app.bin":   {Data: make([]byte, 300)},
+			"lib.bin":   {Data: make([]byte, 100)},
+			"guide.txt": {Data: make([]byte, 50)},
+		},
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Files: []string{"app.bin", "lib.bin"}},
+			{ID: "docs", Name: "Docs", Selected: true, Files: []string{"guide.txt"}},
+		},
+	}
+	inst := newTestInstaller(config)
+	ui := &progressUI{}
+	inst.SetUI(ui)
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+
+	var got []string
+	for _, p := range ui.updates {
+		if strings.HasPrefix(p.Message, "Copying") {
+			got = append(got, p.Message+": "+p.Status())
+		}
+	}
+	want := []string{
+		"Copying app.bin: Installing Core: 75% (overall 37%)",
+		"Copying lib.bin: Installing Core: 100% (overall 50%)",
+		"Copying guide.txt: Installing Docs: 100% (overall 100%)",
+	}
+	// The installer repeats the last message when a component is done
+	got = slices.Compact(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("copy progress =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestProgressReporterOutsideInstallation tests that the reporter is usable without an installation
+func TestProgressReporterOutsideInstallation(t *testing.T) {
+	reporter := core.ProgressReporterFromContext(context.Background())
+	core.ReportProgress(reporter, 1, 2, "ignored")
+	reporter.Done()
+}
+
+// setterReporter is a reporter that only has the ProgressReporter methods
+type setterReporter struct {
+	calls []string
+}
+
+func (r *setterReporter) SetTotal(total int64) {
+	r.calls = append(r.calls, fmt.Sprintf("total %d", total))
+}
+func (r *setterReporter) SetCurrent(current int64) {
+	r.calls = append(r.calls, fmt.Sprintf("current %d", current))
+}
+func (r *setterReporter) SetMessage(message string) { r.calls = append(r.calls, "message "+message) }
+func (r *setterReporter) Done()                     {}
+func (r *setterReporter) Error(err error)           {}
+
+// TestReportProgressFallback tests that reporters without Report receive the update through the setters
+func TestReportProgressFallback(t *testing.T) {
+	reporter := &setterReporter{}
+	core.ReportProgress(reporter, 1, 2, "Copying")
+	core.ReportProgress(reporter, 2, 2, "")
+	want := []string{"total 2", "current 1", "message Copying", "total 2", "current 2"}
+	if !slices.Equal(reporter.calls, want) {
+		t.Errorf("calls = %v, want %v", reporter.calls, want)
+	}
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// RebootActionID identifies the restart the completion screen offers when an
+// installation requires one, see RunCompletionAction
+const RebootActionID = "reboot"
+
+// RebootReporter is implemented by platform installers whose operations can
+// need a restart to take effect, such as replacing files in use. The
+// installer asks after the platform operations of an installation.
+type RebootReporter interface {
+	RebootReasons() []string
+}
+
+// RequireReboot records that the installation only takes full effect after a
+// restart, for example because a driver was installed. The reason is shown
+// on the completion screen; reasons are recorded once.
+func (i *Installer) RequireReboot(reason string) {
+	i.rebootMu.Lock()
+	defer i.rebootMu.Unlock()
+	if !slices.Contains(i.rebootReasons, reason) {
+		i.rebootReasons = append(i.rebootReasons, reason)
+	}
+}
+
+// RebootRequired reports whether the installation needs a restart
+func (i *Installer) RebootRequired() bool {
+	return len(i.RebootReasons()) > 0
+}
+
+// RebootReasons returns why the installation needs a restart, nil if it doesn't
+func (i *Installer) RebootReasons() []string {
+	i.rebootMu.Lock()
+	defer i.rebootMu.Unlock()
+	return slices.Clone(i.rebootReasons)
+}
+
+// RequireReboot records on the running installation that a restart is
+// needed, see Installer.RequireReboot. It is meant for Component.Installer,
+// PostInstall and install handlers, called with the context they are given;
+// outside an installation it does nothing.
+func RequireReboot(ctx context.Context, reason string) {
+	if i, ok := ctx.Value(contextKey("installer")).(*Installer); ok {
+		i.RequireReboot(reason)
+	}
+}
+
+// collectRebootReasons records the restarts the platform operations need
+func (i *Installer) collectRebootReasons() {
+	if reporter, ok := i.platform.(RebootReporter); ok {
+		for _, reason := range reporter.RebootReasons() {
+			i.RequireReboot(reason)
+		}
+	}
+}
+
+// rebootAction is the completion action restarting the computer
+func rebootAction() CompletionAction {
+	return CompletionAction{ID: RebootActionID, Label: "Restart now", Kind: ActionReboot}
+}
+
+// rebootComputer restarts the computer; the views ask for confirmation first
+func rebootComputer(i *Installer, action CompletionAction) (string, error) {
+	if err := Reboot(); err != nil {
+		return "", err
+	}
+	return "Restarting the computer", nil
+}
+
+// Reboot restarts the computer now with the command of the platform. It
+// needs the right to do so, usually elevation.
+func Reboot() error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("shutdown", "/r", "/t", "0")
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", `tell application "System Events" to restart`)
+	default:
+		cmd = exec.Command("systemctl", "reboot")
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restart the computer: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package core_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestRebootRequiredSummary tests that components and platform operations
+// requiring a restart are reported in the summary
+func TestRebootRequiredSummary(t *testing.T) {
+	platform, _ := useMocks(t)
+	platform.PendingReboots = []string{"The system PATH changes after a restart"}
+	config := &core.Config{
+		AppName:    "RebootApp",
+		Version:    "1.0.0",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{
+			{ID: "driver", Name: "Driver", Required: true, RebootRequired: true},
+			{ID: "filter", Name: "Filter", Selected: true,
+				Installer: func(ctx context.Context) error {
+					core.RequireReboot(ctx, "The filter is loaded at boot")
+					core.RequireReboot(ctx, "The filter is loaded at boot")
+					return nil
+				}},
+			{ID: "extras", Name: "Extras", RebootRequired: true},
+		},
+	}
+
+	ui := &runUI{}
+	if err := runInstaller(t, config, ui); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	summary := ui.installer.CreateSummary()
+	want := []string{"Driver was installed", "The filter is loaded at boot", "The system PATH changes after a restart"}
+	if !summary.RebootRequired || !reflect.DeepEqual(summary.RebootReasons, want) {
+		t.Errorf("summary reboot = %v %q, want %q", summary.RebootRequired, summary.RebootReasons, want)
+	}
+	if summary.NextSteps[1] != "Restart the computer to complete the installation" {
+		t.Errorf("next steps = %q, want the restart", summary.NextSteps)
+	}
+
+	// Restarting is offered as a completion action, performed by its handler
+	var restarted bool
+	ui.installer.SetCompletionHandler(core.ActionReboot, func(i *core.Installer, action core.CompletionAction) (string, error) {
+		restarted = true
+		return "restarting", nil
+	})
+	if _, err := ui.installer.RunCompletionAction(core.RebootActionID); err != nil || !restarted {
+		t.Errorf("RunCompletionAction(reboot) = %v, restarted %v", err, restarted)
+	}
+}
+
+// TestNoRebootRequired tests that installations without restarts neither report nor offer one
+func TestNoRebootRequired(t *testing.T) {
+	config := &core.Config{
+		AppName:    "PlainApp",
+		InstallDir: t.TempDir(),
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{{ID: "core", Name: "Core", Required: true}},
+	}
+	inst := newTestInstaller(config)
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	if summary := inst.CreateSummary(); summary.RebootRequired || summary.RebootReasons != nil {
+		t.Errorf("summary reboot = %v %q, want none", summary.RebootRequired, summary.RebootReasons)
+	}
+	if _, err := inst.RunCompletionAction(core.RebootActionID); err == nil {
+		t.Error("restart offered without a reason")
+	}
+
+	// Outside an installation, requiring a restart does nothing
+	core.RequireReboot(context.Background(), "ignored")
+}
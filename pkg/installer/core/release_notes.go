@@ -0,0 +1,106 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ReleaseNote describes what changed in a version, as plain text or markdown
+type ReleaseNote struct {
+	Version string // Version the note belongs to; empty to show it on every upgrade
+	Text    string
+}
+
+// UpgradeNotes returns the version of the installation in installDir and the
+// release notes of the versions after it as markdown. The notes are empty if
+// installDir holds no installation, one that is not older than
+// config.Version, or none of the notes apply.
+func UpgradeNotes(config *Config, installDir string) (installed, notes string) {
+	if len(config.ReleaseNotes) == 0 || installDir == "" {
+		return "", ""
+	}
+	manifest, err := LoadManifest(installDir)
+	if err != nil || manifest.Checkpoint != nil {
+		return "", ""
+	}
+	if CompareVersions(manifest.Version, config.Version) >= 0 {
+		return manifest.Version, ""
+	}
+	return manifest.Version, ReleaseNotesSince(config.ReleaseNotes, manifest.Version, config.Version)
+}
+
+// ReleaseNotesSince joins the notes for versions newer than installed up to
+// current, in the order given, as markdown. Notes with a version start with
+// a "Version x" heading; notes without one are always included.
+func ReleaseNotesSince(notes []ReleaseNote, installed, current string) string {
+	var parts []string
+	for _, note := range notes {
+		text := strings.TrimSpace(note.Text)
+		if text == "" {
+			continue
+		}
+		if note.Version == "" {
+			parts = append(parts, text)
+			continue
+		}
+		if CompareVersions(note.Version, installed) <= 0 || CompareVersions(note.Version, current) > 0 {
+			continue
+		}
+		parts = append(parts, "## Version "+note.Version+"\n\n"+text)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// CompareVersions compares two versions such as "1.10.2" and "v1.9", returning
+// -1, 0 or 1. Parts separated by dots are compared as numbers, missing parts
+// count as 0, and a pre-release suffix ("2.0-beta") sorts before the release.
+func CompareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	for i := 0; i < len(aCore) || i < len(bCore); i++ {
+		x, y := versionPart(aCore, i), versionPart(bCore, i)
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// splitVersion splits a version into its dotted parts and pre-release suffix
+func splitVersion(v string) ([]string, string) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if idx := strings.IndexByte(v, '+'); idx >= 0 {
+		// Build metadata does not order versions
+		v = v[:idx]
+	}
+	pre := ""
+	if idx := strings.IndexByte(v, '-'); idx >= 0 {
+		v, pre = v[:idx], v[idx+1:]
+	}
+	return strings.Split(v, "."), pre
+}
+
+// versionPart returns the numeric value of part i, 0 if it is missing or not a number
+func versionPart(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[i])
+	return n
+}
@@ -0,0 +1,74 @@
+package core_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"v1.2.0", "1.2.0", 0},
+		{"1.9.0", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"2.0.0-beta", "2.0.0", -1},
+		{"2.0.0-alpha", "2.0.0-beta", -1},
+		{"2.0.0+build.5", "2.0.0", 0},
+		{"", "0.1", -1},
+	}
+	for _, tt := range tests {
+		if got := core.CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestReleaseNotesSince(t *testing.T) {
+	notes := []core.ReleaseNote{
+		{Version: "2.1.0", Text: "Unreleased"},
+		{Version: "2.0.0", Text: "Big rewrite"},
+		{Version: "1.5.0", Text: "Faster startup"},
+		{Version: "1.0.0", Text: "First release"},
+		{Text: "See the website for details."},
+		{Version: "1.2.0", Text: "  "},
+	}
+
+	got := core.ReleaseNotesSince(notes, "1.0.0", "2.0.0")
+	want := "## Version 2.0.0\n\nBig rewrite\n\n## Version 1.5.0\n\nFaster startup\n\nSee the website for details."
+	if got != want {
+		t.Errorf("ReleaseNotesSince() = %q, want %q", got, want)
+	}
+}
+
+func TestUpgradeNotes(t *testing.T) {
+	config := &core.Config{
+		AppName:      "NotesApp",
+		Version:      "2.0.0",
+		ReleaseNotes: []core.ReleaseNote{{Version: "2.0.0", Text: "New"}},
+	}
+
+	dir := filepath.Join(t.TempDir(), "app")
+	if installed, notes := core.UpgradeNotes(config, dir); installed != "" || notes != "" {
+		t.Errorf("UpgradeNotes() without installation = %q, %q", installed, notes)
+	}
+
+	manifest := &core.Manifest{AppName: "NotesApp", Version: "1.0.0", InstallDir: dir}
+	if err := manifest.Save(); err != nil {
+		t.Fatal(err)
+	}
+	installed, notes := core.UpgradeNotes(config, dir)
+	if installed != "1.0.0" || notes != "## Version 2.0.0\n\nNew" {
+		t.Errorf("UpgradeNotes() = %q, %q", installed, notes)
+	}
+
+	config.Version = "1.0.0"
+	if _, notes := core.UpgradeNotes(config, dir); notes != "" {
+		t.Errorf("UpgradeNotes() for the same version = %q, want none", notes)
+	}
+}
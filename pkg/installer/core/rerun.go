@@ -0,0 +1,120 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// configFingerprint is the part of the configuration that decides what an
+// installation puts on disk
+type configFingerprint struct {
+	AppName    string                 `json:"app_name"`
+	Version    string                 `json:"version"`
+	Portable   bool                   `json:"portable,omitempty"`
+	Components []componentFingerprint `json:"components"`
+	PathDirs   []string               `json:"path_dirs,omitempty"`
+	PathSystem bool                   `json:"path_system,omitempty"`
+	Features   map[string]bool        `json:"features,omitempty"`
+}
+
+// componentFingerprint identifies a component and its payload
+type componentFingerprint struct {
+	ID        string            `json:"id"`
+	Files     []string          `json:"files,omitempty"`
+	Checksums map[string]string `json:"checksums,omitempty"`
+	Services  []string          `json:"services,omitempty"`
+}
+
+// ConfigHash returns the SHA-256 of the application, its version, the
+// components to install with their files, and the PATH and portable
+// settings. The manifest records it, so a re-run can tell whether it would
+// install the same thing again.
+func (i *Installer) ConfigHash() string {
+	fp := configFingerprint{
+		AppName:    i.config.AppName,
+		Version:    i.config.Version,
+		Portable:   i.config.Portable,
+		Components: []componentFingerprint{},
+		Features:   i.config.Features,
+	}
+	for _, c := range i.getComponentsToInstall() {
+		fp.Components = append(fp.Components, componentFingerprint{
+			ID:        c.ID,
+			Files:     c.Files,
+			Checksums: c.Checksums,
+			Services:  c.Services,
+		})
+	}
+	if pc := i.config.PathConfig; pc != nil && pc.Enabled && !i.config.Portable {
+		fp.PathDirs = pc.Dirs
+		fp.PathSystem = pc.System
+	}
+
+	// Maps marshal with sorted keys, so the encoding is stable
+	data, _ := json.Marshal(fp)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// UpToDate reports whether the last run found an identical, intact
+// installation and did nothing
+func (i *Installer) UpToDate() bool {
+	return i.upToDate
+}
+
+// checkRerun compares the installation already in InstallDir with the
+// configuration. An identical one with intact files makes the run a no-op
+// unless Force is set. A different one is modified: components it has that
+// are no longer selected are removed, the others are installed over it.
+func (i *Installer) checkRerun() (upToDate bool, err error) {
+	i.rerunRemove = nil
+	manifest, err := LoadManifest(i.config.InstallDir)
+	if err != nil || manifest.Checkpoint != nil || manifest.ConfigHash == "" {
+		// Nothing installed, an interrupted run, or an installation without a hash
+		return false, nil
+	}
+
+	if manifest.ConfigHash == i.ConfigHash() {
+		if i.config.Force {
+			i.context.Logger.Info("Reinstalling identical installation as requested")
+			return false, nil
+		}
+		for idx := range manifest.Components {
+			if err := verifyManifestComponent(i.config.InstallDir, &manifest.Components[idx]); err != nil {
+				i.context.Logger.Info("Repairing installation", "component", manifest.Components[idx].ID, "reason", err)
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	var selectedIDs []string
+	for _, c := range i.getComponentsToInstall() {
+		selectedIDs = append(selectedIDs, c.ID)
+	}
+	plan, err := PlanModify(i.config.Components, manifest, selectedIDs)
+	if err != nil {
+		return false, fmt.Errorf("cannot modify the existing installation: %w", err)
+	}
+	i.context.Logger.Info("Configuration changed, updating the existing installation",
+		"from", manifest.Version, "to", i.config.Version)
+	i.rerunManifest = manifest
+	i.rerunRemove = plan.Remove
+	return false, nil
+}
+
+// removeDeselected uninstalls the components checkRerun found deselected
+func (i *Installer) removeDeselected() error {
+	if i.config.DryRun {
+		return nil
+	}
+	for _, c := range i.rerunRemove {
+		i.context.Logger.Info("Removing component", "id", c.ID)
+		if err := i.uninstallComponent(c, i.rerunManifest); err != nil {
+			return NewInstallError(fmt.Errorf("failed to remove component %s: %w", c.ID, err), PhaseFileCopy, c.ID)
+		}
+	}
+	return nil
+}
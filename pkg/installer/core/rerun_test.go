@@ -0,0 +1,122 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// rerunConfig selects "core" and "docs" of the modify components
+func rerunConfig(installDir string, actions *[]string) *core.Config {
+	components := modifyComponents(installDir, actions)
+	selectOnly(components, "core", "docs")
+	return &core.Config{
+		AppName:    "RerunApp",
+		Version:    "1.0.0",
+		InstallDir: installDir,
+		Rollback:   core.RollbackNone,
+		Components: components,
+	}
+}
+
+// rerun installs config with a fresh installer, as a second run of the same installer would
+func rerun(t *testing.T, config *core.Config) *core.Installer {
+	t.Helper()
+	inst := newTestInstaller(config)
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	return inst
+}
+
+// TestRerunIdenticalIsNoop tests that an identical, intact installation is left alone
+func TestRerunIdenticalIsNoop(t *testing.T) {
+	installDir := t.TempDir()
+	actions := &[]string{}
+	first := rerun(t, rerunConfig(installDir, actions))
+	if first.UpToDate() {
+		t.Error("First run reported an existing installation")
+	}
+
+	manifest, err := core.LoadManifest(installDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.ConfigHash == "" || manifest.ConfigHash != first.ConfigHash() {
+		t.Errorf("Manifest hash = %q, want %q", manifest.ConfigHash, first.ConfigHash())
+	}
+
+	*actions = nil
+	second := rerun(t, rerunConfig(installDir, actions))
+	if !second.UpToDate() || !second.CreateSummary().UpToDate {
+		t.Error("Identical re-run should report the installation as up to date")
+	}
+	if len(*actions) != 0 {
+		t.Errorf("Identical re-run installed %v, want nothing", *actions)
+	}
+
+	// A damaged installation is repaired instead
+	if err := os.Remove(filepath.Join(installDir, "docs.txt")); err != nil {
+		t.Fatal(err)
+	}
+	repair := rerun(t, rerunConfig(installDir, actions))
+	if repair.UpToDate() || len(*actions) == 0 {
+		t.Errorf("Re-run with a missing file installed %v, want a reinstall", *actions)
+	}
+}
+
+// TestRerunForced tests that Force reinstalls an identical installation
+func TestRerunForced(t *testing.T) {
+	installDir := t.TempDir()
+	actions := &[]string{}
+	rerun(t, rerunConfig(installDir, actions))
+
+	*actions = nil
+	config := rerunConfig(installDir, actions)
+	config.Force = true
+	if inst := rerun(t, config); inst.UpToDate() {
+		t.Error("Forced re-run reported the installation as up to date")
+	}
+	if want := []string{"install:core", "install:docs"}; !reflect.DeepEqual(*actions, want) {
+		t.Errorf("Forced re-run installed %v, want %v", *actions, want)
+	}
+}
+
+// TestRerunChangedConfig tests that a changed configuration modifies the installation
+func TestRerunChangedConfig(t *testing.T) {
+	installDir := t.TempDir()
+	actions := &[]string{}
+	first := rerun(t, rerunConfig(installDir, actions))
+
+	*actions = nil
+	config := rerunConfig(installDir, actions)
+	config.Version = "1.1.0"
+	selectOnly(config.Components, "core", "sdk")
+	second := rerun(t, config)
+	if second.UpToDate() {
+		t.Error("Changed re-run reported the installation as up to date")
+	}
+	if second.ConfigHash() == first.ConfigHash() {
+		t.Error("Changed configuration kept the same hash")
+	}
+	if want := []string{"install:core", "install:sdk"}; !reflect.DeepEqual(*actions, want) {
+		t.Errorf("Changed re-run installed %v, want %v", *actions, want)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "docs.txt")); !os.IsNotExist(err) {
+		t.Error("Deselected docs component was not removed")
+	}
+
+	manifest, err := core.LoadManifest(installDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := manifest.ComponentIDs(); !reflect.DeepEqual(got, []string{"core", "sdk"}) {
+		t.Errorf("Manifest components = %v, want [core sdk]", got)
+	}
+	if manifest.Version != "1.1.0" || manifest.ConfigHash != second.ConfigHash() {
+		t.Errorf("Manifest = %s %q, want the new version and hash", manifest.Version, manifest.ConfigHash)
+	}
+}
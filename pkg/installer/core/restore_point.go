@@ -0,0 +1,101 @@
+package core
+
+import (
+	"fmt"
+	"unicode/utf16"
+)
+
+// maxRestorePointDescription is the size of the description buffer used by System Restore
+const maxRestorePointDescription = 256
+
+// Restore point event and type values as defined by the System Restore API
+const (
+	restorePointBeginSystemChange  uint32 = 100 // BEGIN_SYSTEM_CHANGE
+	restorePointEndSystemChange    uint32 = 101 // END_SYSTEM_CHANGE
+	restorePointApplicationInstall uint32 = 0   // APPLICATION_INSTALL
+	restorePointCancelledOperation uint32 = 13  // CANCELLED_OPERATION
+)
+
+// restorePointSpec describes a restore point request
+type restorePointSpec struct {
+	EventType      uint32
+	Type           uint32
+	SequenceNumber int64 // Of the restore point to end, set by END_SYSTEM_CHANGE requests
+	Description    string
+}
+
+// newRestorePointSpec builds the restore point request for the given description
+func newRestorePointSpec(description string) restorePointSpec {
+	if description == "" {
+		description = "SetupKit installation"
+	}
+	// Leave room for the terminating NUL in the UTF-16 buffer
+	for len(utf16.Encode([]rune(description))) > maxRestorePointDescription-1 {
+		runes := []rune(description)
+		description = string(runes[:len(runes)-1])
+	}
+	return restorePointSpec{
+		EventType:   restorePointBeginSystemChange,
+		Type:        restorePointApplicationInstall,
+		Description: description,
+	}
+}
+
+// RestorePoint is a restore point begun before an installation. System
+// Restore keeps it open until End or Cancel is called, or until it times out.
+type RestorePoint struct {
+	sequence int64
+	backend  func(restorePointSpec) (int64, error)
+}
+
+// CreateRestorePoint begins a system restore point before installation, to
+// be ended with End when the installation succeeded or with Cancel when it
+// failed. It is only supported on Windows; on other platforms it returns a
+// nil RestorePoint, whose methods do nothing.
+func CreateRestorePoint(description string) (*RestorePoint, error) {
+	return createRestorePoint(description, restorePointBackend)
+}
+
+// End completes the restore point of a finished installation
+func (r *RestorePoint) End() error {
+	return r.end(restorePointApplicationInstall)
+}
+
+// Cancel completes the restore point of a failed or rolled back
+// installation, which System Restore then discards
+func (r *RestorePoint) Cancel() error {
+	return r.end(restorePointCancelledOperation)
+}
+
+func (r *RestorePoint) end(restorePtType uint32) error {
+	if r == nil || r.backend == nil {
+		return nil
+	}
+	backend := r.backend
+	r.backend = nil // End the restore point only once
+	_, err := backend(restorePointSpec{
+		EventType:      restorePointEndSystemChange,
+		Type:           restorePtType,
+		SequenceNumber: r.sequence,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to end restore point %d: %w", r.sequence, err)
+	}
+	return nil
+}
+
+// RestorePointSupported reports whether restore points can be created on this platform
+func RestorePointSupported() bool {
+	return restorePointBackend != nil
+}
+
+func createRestorePoint(description string, backend func(restorePointSpec) (int64, error)) (*RestorePoint, error) {
+	if backend == nil {
+		return nil, nil
+	}
+	sequence, err := backend(newRestorePointSpec(description))
+	if err != nil {
+		return nil, err
+	}
+	return &RestorePoint{sequence: sequence, backend: backend}, nil
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+// +build !windows
+
+package core
+
+// restorePointBackend is nil on platforms without System Restore
+var restorePointBackend func(restorePointSpec) (int64, error)
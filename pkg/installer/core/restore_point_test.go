@@ -0,0 +1,147 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestCreateRestorePointBackend tests the restore point request passed to the backend
+func TestCreateRestorePointBackend(t *testing.T) {
+	var got restorePointSpec
+	calls := 0
+	backend := func(spec restorePointSpec) (int64, error) {
+		calls++
+		got = spec
+		return 42, nil
+	}
+
+	if _, err := createRestorePoint("Install MyApp 1.0", backend); err != nil {
+		t.Fatalf("createRestorePoint() error = %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("backend called %d times, want 1", calls)
+	}
+	if got.EventType != restorePointBeginSystemChange {
+		t.Errorf("EventType = %d, want %d", got.EventType, restorePointBeginSystemChange)
+	}
+	if got.Type != restorePointApplicationInstall {
+		t.Errorf("Type = %d, want %d", got.Type, restorePointApplicationInstall)
+	}
+	if got.Description != "Install MyApp 1.0" {
+		t.Errorf("Description = %q, want %q", got.Description, "Install MyApp 1.0")
+	}
+
+	wantErr := errors.New("service disabled")
+	_, err := createRestorePoint("x", func(restorePointSpec) (int64, error) { return 0, wantErr })
+	if !errors.Is(err, wantErr) {
+		t.Errorf("createRestorePoint() error = %v, want %v", err, wantErr)
+	}
+}
+
+// TestNewRestorePointSpec tests description defaults and truncation
+func TestNewRestorePointSpec(t *testing.T) {
+	if spec := newRestorePointSpec(""); spec.Description == "" {
+		t.Error("Expected default description for empty input")
+	}
+
+	long := strings.Repeat("ä", 400)
+	spec := newRestorePointSpec(long)
+	if n := len([]rune(spec.Description)); n != maxRestorePointDescription-1 {
+		t.Errorf("Description length = %d, want %d", n, maxRestorePointDescription-1)
+	}
+}
+
+// TestCreateRestorePointNoop tests the no-op path on platforms without System Restore
+func TestCreateRestorePointNoop(t *testing.T) {
+	if _, err := createRestorePoint("Install", nil); err != nil {
+		t.Errorf("createRestorePoint() with nil backend error = %v", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		t.Skip("System Restore is available on Windows")
+	}
+	if RestorePointSupported() {
+		t.Error("RestorePointSupported() = true on non-Windows platform")
+	}
+	point, err := CreateRestorePoint("Install")
+	if err != nil {
+		t.Errorf("CreateRestorePoint() error = %v", err)
+	}
+	if err := point.End(); err != nil {
+		t.Errorf("End() of the nil restore point error = %v", err)
+	}
+}
+
+// quietUI is a UI that shows nothing and accepts everything
+type quietUI struct{}
+
+func (quietUI) Initialize(*Context) error                           { return nil }
+func (quietUI) Run() error                                          { return nil }
+func (quietUI) Shutdown() error                                     { return nil }
+func (quietUI) ShowWelcome() error                                  { return nil }
+func (quietUI) ShowLicense(string) (bool, error)                    { return true, nil }
+func (quietUI) SelectComponents(c []Component) ([]Component, error) { return c, nil }
+func (quietUI) SelectInstallPath(path string) (string, error)       { return path, nil }
+func (quietUI) ShowProgress(*Progress) error                        { return nil }
+func (quietUI) ShowError(error, bool) (bool, error)                 { return false, nil }
+func (quietUI) ShowSuccess(*InstallSummary) error                   { return nil }
+func (quietUI) RequestElevation(string) (bool, error)               { return true, nil }
+
+// fakeRestorePoints replaces SRSetRestorePointW with a fake for the test and
+// returns the requests sent to it
+func fakeRestorePoints(t *testing.T) *[]restorePointSpec {
+	var requests []restorePointSpec
+	previous := restorePointBackend
+	restorePointBackend = func(spec restorePointSpec) (int64, error) {
+		requests = append(requests, spec)
+		return 7, nil
+	}
+	t.Cleanup(func() { restorePointBackend = previous })
+	return &requests
+}
+
+// TestRestorePointEndsWithInstallation tests that the restore point is ended
+// when the installation finishes and cancelled when it fails
+func TestRestorePointEndsWithInstallation(t *testing.T) {
+	tests := []struct {
+		name    string
+		install func(context.Context) error
+		want    uint32
+	}{
+		{"finished", func(context.Context) error { return nil }, restorePointApplicationInstall},
+		{"failed", func(context.Context) error { return errors.New("disk full") }, restorePointCancelledOperation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := fakeRestorePoints(t)
+			config := &Config{
+				AppName:            "RestoreApp",
+				Version:            "1.0.0",
+				InstallDir:         t.TempDir(),
+				CreateRestorePoint: true,
+				Rollback:           RollbackFull,
+				Components: []Component{
+					{ID: "core", Name: "Core", Required: true, Selected: true, Installer: tt.install},
+				},
+			}
+			installer := New(config)
+			installer.SetUI(quietUI{})
+			installer.SetContext(&Context{Config: config, Logger: NewLogger("error", ""), Metadata: map[string]interface{}{}})
+			installer.ExecuteInstallation()
+
+			if len(*requests) != 2 {
+				t.Fatalf("restore point requests = %+v, want begin and end", *requests)
+			}
+			begin, end := (*requests)[0], (*requests)[1]
+			if begin.EventType != restorePointBeginSystemChange {
+				t.Errorf("first request EventType = %d, want BEGIN_SYSTEM_CHANGE", begin.EventType)
+			}
+			if end.EventType != restorePointEndSystemChange || end.Type != tt.want || end.SequenceNumber != 7 {
+				t.Errorf("end request = %+v, want END_SYSTEM_CHANGE of type %d for sequence 7", end, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+//go:build windows
+// +build windows
+
+package core
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	srclient              = windows.NewLazySystemDLL("srclient.dll")
+	procSRSetRestorePoint = srclient.NewProc("SRSetRestorePointW")
+)
+
+// restorePointInfo mirrors RESTOREPOINTINFOW
+type restorePointInfo struct {
+	EventType      uint32
+	RestorePtType  uint32
+	SequenceNumber int64
+	Description    [maxRestorePointDescription]uint16
+}
+
+// stateMgrStatus mirrors STATEMGRSTATUS; the header packs it to 1 byte, so the
+// sequence number is split to avoid Go inserting alignment padding
+type stateMgrStatus struct {
+	Status         uint32
+	SequenceNumber [2]uint32
+}
+
+// restorePointBackend begins and ends restore points via SRSetRestorePointW
+var restorePointBackend = setRestorePoint
+
+// setRestorePoint sends spec to System Restore and returns the sequence
+// number of the restore point
+func setRestorePoint(spec restorePointSpec) (int64, error) {
+	if err := procSRSetRestorePoint.Find(); err != nil {
+		return 0, fmt.Errorf("system restore not available: %w", err)
+	}
+
+	desc, err := windows.UTF16FromString(spec.Description)
+	if err != nil {
+		return 0, fmt.Errorf("invalid restore point description: %w", err)
+	}
+
+	info := restorePointInfo{
+		EventType:      spec.EventType,
+		RestorePtType:  spec.Type,
+		SequenceNumber: spec.SequenceNumber,
+	}
+	copy(info.Description[:], desc)
+
+	var status stateMgrStatus
+	ret, _, _ := procSRSetRestorePoint.Call(
+		uintptr(unsafe.Pointer(&info)),
+		uintptr(unsafe.Pointer(&status)),
+	)
+	if ret == 0 {
+		return 0, fmt.Errorf("failed to set restore point (status %d)", status.Status)
+	}
+	return int64(status.SequenceNumber[0]) | int64(status.SequenceNumber[1])<<32, nil
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how Retry repeats a failing operation
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first
+	BaseDelay   time.Duration // Delay before the first retry
+	MaxDelay    time.Duration // Upper bound for any delay
+	Multiplier  float64       // Growth factor of the delay per attempt
+	Jitter      float64       // Random spread as a fraction of the delay, 0 to 1
+
+	// IsRetryable reports whether an error is transient. If nil, all errors
+	// except context cancellation are retried.
+	IsRetryable func(error) bool
+
+	// OnRetry, if set, is called with the failed attempt, counting from 1,
+	// its error and the delay before the next attempt
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// DefaultRetryPolicy returns the policy used for downloads and license checks:
+// 5 attempts, starting at 500ms and doubling up to 30s, with 20% jitter
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Multiplier:  2,
+		Jitter:      0.2,
+	}
+}
+
+// TransientRetryPolicy returns DefaultRetryPolicy retrying only errors
+// IsTransientError recognizes, for install steps such as registering a
+// service that fail while the system is busy
+func TransientRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.IsRetryable = IsTransientError
+	return policy
+}
+
+// IsTransientError reports whether err may go away by trying again: a file
+// in use, or on Windows a service control manager that is busy. Checksum
+// mismatches, denied access and a full disk are not transient.
+func IsTransientError(err error) bool {
+	if errors.Is(err, ErrChecksumMismatch) {
+		return false
+	}
+	return isFileInUse(err) || isServiceBusy(err)
+}
+
+// retryClock abstracts waiting and randomness so tests can run without delays
+type retryClock struct {
+	sleep  func(ctx context.Context, d time.Duration) error
+	random func() float64
+}
+
+var realRetryClock = retryClock{sleep: sleepContext, random: rand.Float64}
+
+// Retry calls fn until it succeeds, returns a permanent error or the policy's
+// attempts are used up. Between attempts it waits with exponential backoff and
+// stops early when ctx is done. Unset attempts, delays and multiplier take
+// their default values; a zero Jitter disables jitter.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	return retry(ctx, policy, fn, realRetryClock)
+}
+
+func retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error, clock retryClock) error {
+	policy = policy.withDefaults()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if !policy.retryable(err) {
+			return err
+		}
+		if attempt >= policy.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		delay := policy.delay(attempt, clock.random)
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, delay)
+		}
+		if sleepErr := clock.sleep(ctx, delay); sleepErr != nil {
+			return fmt.Errorf("retry aborted after %d attempts: %w (last error: %v)", attempt, sleepErr, err)
+		}
+	}
+}
+
+// withDefaults fills unset fields from DefaultRetryPolicy
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaults.Multiplier
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		p.Jitter = defaults.Jitter
+	}
+	return p
+}
+
+// retryable classifies err using IsRetryable; context errors are never retried
+func (p RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if p.IsRetryable == nil {
+		return true
+	}
+	return p.IsRetryable(err)
+}
+
+// delay returns the wait after the given failed attempt, counting from 1
+func (p RetryPolicy) delay(attempt int, random func() float64) time.Duration {
+	d := float64(p.BaseDelay)
+	for i := 1; i < attempt && d < float64(p.MaxDelay); i++ {
+		d *= p.Multiplier
+	}
+	// Spread evenly over [d-jitter, d+jitter]
+	d += d * p.Jitter * (2*random() - 1)
+	if d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	return time.Duration(d)
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
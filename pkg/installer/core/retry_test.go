@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeRetryClock records requested delays instead of sleeping
+type fakeRetryClock struct {
+	delays []time.Duration
+}
+
+func (c *fakeRetryClock) clock() retryClock {
+	return retryClock{
+		sleep: func(ctx context.Context, d time.Duration) error {
+			c.delays = append(c.delays, d)
+			return ctx.Err()
+		},
+		random: func() float64 { return 0.5 }, // No jitter offset
+	}
+}
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    250 * time.Millisecond,
+	Multiplier:  2,
+	Jitter:      0.5,
+}
+
+// TestRetrySucceedsAfterRetries tests backoff delays until the operation succeeds
+func TestRetrySucceedsAfterRetries(t *testing.T) {
+	fake := &fakeRetryClock{}
+	calls := 0
+	err := retry(context.Background(), testRetryPolicy, func(ctx context.Context) error {
+		calls++
+		if calls < 4 {
+			return errors.New("connection reset")
+		}
+		return nil
+	}, fake.clock())
+	if err != nil {
+		t.Fatalf("retry() error = %v", err)
+	}
+	if calls != 4 {
+		t.Errorf("calls = %d, want 4", calls)
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 250 * time.Millisecond}
+	if len(fake.delays) != len(want) {
+		t.Fatalf("delays = %v, want %v", fake.delays, want)
+	}
+	for i := range want {
+		if fake.delays[i] != want[i] {
+			t.Errorf("delay %d = %v, want %v", i, fake.delays[i], want[i])
+		}
+	}
+}
+
+// TestRetryExhaustsAttempts tests that the last error is returned after MaxAttempts
+func TestRetryExhaustsAttempts(t *testing.T) {
+	fake := &fakeRetryClock{}
+	lastErr := errors.New("timeout")
+	calls := 0
+	err := retry(context.Background(), testRetryPolicy, func(ctx context.Context) error {
+		calls++
+		return lastErr
+	}, fake.clock())
+	if !errors.Is(err, lastErr) {
+		t.Fatalf("retry() error = %v, want %v", err, lastErr)
+	}
+	if calls != testRetryPolicy.MaxAttempts {
+		t.Errorf("calls = %d, want %d", calls, testRetryPolicy.MaxAttempts)
+	}
+	if len(fake.delays) != testRetryPolicy.MaxAttempts-1 {
+		t.Errorf("slept %d times, want %d", len(fake.delays), testRetryPolicy.MaxAttempts-1)
+	}
+}
+
+// TestRetryPermanentError tests that non-retryable errors return immediately
+func TestRetryPermanentError(t *testing.T) {
+	fake := &fakeRetryClock{}
+	permanent := errors.New("404 not found")
+	policy := testRetryPolicy
+	policy.IsRetryable = func(err error) bool { return !errors.Is(err, permanent) }
+
+	calls := 0
+	err := retry(context.Background(), policy, func(ctx context.Context) error {
+		calls++
+		return permanent
+	}, fake.clock())
+	if err != permanent {
+		t.Fatalf("retry() error = %v, want %v", err, permanent)
+	}
+	if calls != 1 || len(fake.delays) != 0 {
+		t.Errorf("calls = %d, sleeps = %d, want 1 call and no sleep", calls, len(fake.delays))
+	}
+}
+
+// TestRetryContextCancelled tests that cancellation stops retrying between attempts
+func TestRetryContextCancelled(t *testing.T) {
+	fake := &fakeRetryClock{}
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := retry(ctx, testRetryPolicy, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return errors.New("connection refused")
+	}, fake.clock())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("retry() error = %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+// TestRetryPolicyDefaults tests that zero fields take the default values
+func TestRetryPolicyDefaults(t *testing.T) {
+	policy := RetryPolicy{}.withDefaults()
+	defaults := DefaultRetryPolicy()
+	if policy.MaxAttempts != defaults.MaxAttempts || policy.BaseDelay != defaults.BaseDelay ||
+		policy.MaxDelay != defaults.MaxDelay || policy.Multiplier != defaults.Multiplier {
+		t.Errorf("withDefaults() = %+v, want %+v", policy, defaults)
+	}
+
+	jittered := defaults.delay(1, func() float64 { return 1 })
+	if jittered != 600*time.Millisecond {
+		t.Errorf("delay with full jitter = %v, want 600ms", jittered)
+	}
+}
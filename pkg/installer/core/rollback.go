@@ -16,6 +16,7 @@ func (c contextKey) String() string {
 type RollbackManager struct {
 	strategy    RollbackStrategy
 	checkpoints []RollbackCheckpoint
+	rolledBack  []string // IDs of the checkpoints the last Execute undid
 	mu          sync.Mutex
 }
 
@@ -53,6 +54,7 @@ func (r *RollbackManager) Execute(ctx *Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.rolledBack = nil
 	if len(r.checkpoints) == 0 {
 		return nil // Nothing to rollback
 	}
@@ -63,6 +65,9 @@ func (r *RollbackManager) Execute(ctx *Context) error {
 	rollbackCtx := context.WithValue(context.Background(), contextKey("installer_context"), ctx)
 	rollbackCtx = context.WithValue(rollbackCtx, contextKey("logger"), ctx.Logger)
 	rollbackCtx = context.WithValue(rollbackCtx, contextKey("config"), ctx.Config)
+	if ctx.Config != nil {
+		rollbackCtx = withFeatures(rollbackCtx, ctx.Config.Features)
+	}
 
 	var errors []error
 
@@ -76,6 +81,8 @@ func (r *RollbackManager) Execute(ctx *Context) error {
 			if err := checkpoint.Rollback(rollbackCtx); err != nil {
 				ctx.Logger.Error("Rollback failed for component", "id", checkpoint.ID, "error", err)
 				errors = append(errors, fmt.Errorf("rollback %s: %w", checkpoint.ID, err))
+			} else {
+				r.rolledBack = append(r.rolledBack, checkpoint.ID)
 			}
 		}
 
@@ -88,6 +95,8 @@ func (r *RollbackManager) Execute(ctx *Context) error {
 			if err := checkpoint.Rollback(rollbackCtx); err != nil {
 				ctx.Logger.Error("Rollback failed", "id", checkpoint.ID, "error", err)
 				errors = append(errors, fmt.Errorf("rollback %s: %w", checkpoint.ID, err))
+			} else {
+				r.rolledBack = append(r.rolledBack, checkpoint.ID)
 			}
 		}
 
@@ -107,6 +116,13 @@ func (r *RollbackManager) Execute(ctx *Context) error {
 	return nil
 }
 
+// RolledBack returns the IDs of the checkpoints the last Execute undid
+func (r *RollbackManager) RolledBack() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.rolledBack...)
+}
+
 // Clear removes all checkpoints
 func (r *RollbackManager) Clear() {
 	r.mu.Lock()
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Script describes a script executed by the installer before or after installation
+type Script struct {
+	Path          string              // External script file
+	Content       string              // Embedded script content, used when Path is empty
+	Interpreter   []string            // Interpreter command line; defaults to sh (cmd /C on Windows)
+	Interpreters  map[string][]string // Per-GOOS interpreter overrides, e.g. "windows": {"powershell", "-File"}
+	Timeout       time.Duration       // Maximum run time; zero means no limit
+	Env           map[string]string   // Additional environment variables
+	IsolateEnv    bool                // Do not inherit the installer's environment
+	IgnoreFailure bool                // Log a nonzero exit instead of failing the installation
+}
+
+// defaultInterpreter returns the interpreter and temp file extension for the current platform
+func defaultInterpreter() ([]string, string) {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/C"}, ".cmd"
+	}
+	return []string{"sh"}, ".sh"
+}
+
+// interpreter returns the interpreter and temp file extension for goos
+func (s *Script) interpreter(goos string) ([]string, string) {
+	interpreter, ext := defaultInterpreter()
+	if custom, ok := s.Interpreters[goos]; ok && len(custom) > 0 {
+		return custom, scriptExtension(custom[0], ext)
+	}
+	if len(s.Interpreter) > 0 {
+		return s.Interpreter, scriptExtension(s.Interpreter[0], ext)
+	}
+	return interpreter, ext
+}
+
+// scriptExtension picks a temp file extension some interpreters insist on
+func scriptExtension(interpreter, fallback string) string {
+	name := strings.ToLower(filepath.Base(interpreter))
+	name = strings.TrimSuffix(name, ".exe")
+	switch name {
+	case "powershell", "pwsh":
+		return ".ps1"
+	case "cmd":
+		return ".cmd"
+	case "sh", "bash", "zsh":
+		return ".sh"
+	case "python", "python3":
+		return ".py"
+	}
+	return fallback
+}
+
+// RunScript executes script with workDir as working directory and vars added to its
+// environment. Output is written to the logger. The script is stopped when ctx is
+// cancelled or the script's timeout expires.
+func RunScript(ctx context.Context, script *Script, workDir string, vars map[string]string, logger Logger) error {
+	if script == nil {
+		return nil
+	}
+	if script.Path == "" && script.Content == "" {
+		return fmt.Errorf("script has neither path nor content")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	interpreter, ext := script.interpreter(runtime.GOOS)
+
+	scriptPath := script.Path
+	if scriptPath == "" {
+		tmp, err := os.CreateTemp("", "setupkit-script-*"+ext)
+		if err != nil {
+			return fmt.Errorf("failed to create script file: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		if _, err := tmp.WriteString(script.Content); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to write script file: %w", err)
+		}
+		if err := tmp.Close(); err != nil {
+			return fmt.Errorf("failed to write script file: %w", err)
+		}
+		scriptPath = tmp.Name()
+	}
+
+	if script.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, script.Timeout)
+		defer cancel()
+	}
+
+	args := append(append([]string{}, interpreter[1:]...), scriptPath)
+	cmd := exec.CommandContext(ctx, interpreter[0], args...)
+	cmd.Dir = workDir
+	// Don't wait forever on children that keep the output pipes open after a kill
+	cmd.WaitDelay = time.Second
+	cmd.Env = scriptEnv(script, vars)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+
+	if logger != nil {
+		logScriptOutput(logger, "stdout", &stdout)
+		logScriptOutput(logger, "stderr", &stderr)
+	}
+
+	if runErr == nil {
+		return nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("script %s timed out after %v", scriptPath, script.Timeout)
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("script %s cancelled: %w", scriptPath, ctx.Err())
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		err := fmt.Errorf("script exited with code %d", exitErr.ExitCode())
+		if script.IgnoreFailure {
+			if logger != nil {
+				logger.Warn("Script failed, continuing", "error", err)
+			}
+			return nil
+		}
+		return err
+	}
+	return fmt.Errorf("failed to run script: %w", runErr)
+}
+
+// scriptEnv builds the environment for a script
+func scriptEnv(script *Script, vars map[string]string) []string {
+	var env []string
+	if script.IsolateEnv {
+		// Keep only what is needed to locate executables
+		for _, key := range []string{"PATH", "SystemRoot", "ComSpec", "TEMP", "TMP"} {
+			if value, ok := os.LookupEnv(key); ok {
+				env = append(env, key+"="+value)
+			}
+		}
+	} else {
+		env = os.Environ()
+	}
+	for key, value := range vars {
+		env = append(env, key+"="+value)
+	}
+	for key, value := range script.Env {
+		env = append(env, key+"="+value)
+	}
+	return env
+}
+
+// logScriptOutput writes captured script output to the logger line by line
+func logScriptOutput(logger Logger, stream string, output *bytes.Buffer) {
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if stream == "stderr" {
+			logger.Warn("Script output", "stream", stream, "line", line)
+		} else {
+			logger.Info("Script output", "stream", stream, "line", line)
+		}
+	}
+}
+
+// scriptVariables returns the variables passed to installer scripts
+func (i *Installer) scriptVariables() map[string]string {
+	var ids []string
+	for _, c := range i.getComponentsToInstall() {
+		ids = append(ids, c.ID)
+	}
+	return map[string]string{
+		"SETUPKIT_APP_NAME":    i.config.AppName,
+		"SETUPKIT_APP_VERSION": i.config.Version,
+		"SETUPKIT_INSTALL_DIR": i.config.InstallDir,
+		"SETUPKIT_COMPONENTS":  strings.Join(ids, ","),
+	}
+}
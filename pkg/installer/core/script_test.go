@@ -0,0 +1,213 @@
+package core_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// testUI is a no-op core.UI used to drive ExecuteInstallation in tests
+type testUI struct{}
+
+func (testUI) Initialize(*core.Context) error                              { return nil }
+func (testUI) Run() error                                                  { return nil }
+func (testUI) Shutdown() error                                             { return nil }
+func (testUI) ShowWelcome() error                                          { return nil }
+func (testUI) ShowLicense(string) (bool, error)                            { return true, nil }
+func (testUI) SelectComponents([]core.Component) ([]core.Component, error) { return nil, nil }
+func (testUI) SelectInstallPath(path string) (string, error)               { return path, nil }
+func (testUI) ShowProgress(*core.Progress) error                           { return nil }
+func (testUI) ShowError(error, bool) (bool, error)                         { return false, nil }
+func (testUI) ShowSuccess(*core.InstallSummary) error                      { return nil }
+func (testUI) RequestElevation(string) (bool, error)                       { return false, nil }
+
+// newTestInstaller creates an installer with a logger and no-op UI
+func newTestInstaller(config *core.Config) *core.Installer {
+	inst := core.New(config)
+	inst.SetUI(testUI{})
+	inst.SetContext(&core.Context{
+		Config:   config,
+		Logger:   core.NewLogger("info", ""),
+		Metadata: make(map[string]interface{}),
+	})
+	return inst
+}
+
+// markerScript returns a script that writes the app name into a marker file in the working directory
+func markerScript() string {
+	if runtime.GOOS == "windows" {
+		return "@echo %SETUPKIT_APP_NAME%> marker.txt\r\n"
+	}
+	return "echo \"$SETUPKIT_APP_NAME\" > marker.txt\n"
+}
+
+// failingScript returns a script that exits with code 3
+func failingScript() string {
+	if runtime.GOOS == "windows" {
+		return "@exit /b 3\r\n"
+	}
+	return "exit 3\n"
+}
+
+// TestPostInstallScript tests that the post-install script runs in the install directory
+func TestPostInstallScript(t *testing.T) {
+	installDir := t.TempDir()
+	config := &core.Config{
+		AppName:           "ScriptApp",
+		Version:           "1.0.0",
+		InstallDir:        installDir,
+		Rollback:          core.RollbackNone,
+		PostInstallScript: &core.Script{Content: markerScript()},
+	}
+
+	inst := newTestInstaller(config)
+
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(installDir, "marker.txt"))
+	if err != nil {
+		t.Fatalf("Marker file not written: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "ScriptApp" {
+		t.Errorf("Marker content = %q, want %q", got, "ScriptApp")
+	}
+}
+
+// TestPostInstallScriptFailure tests that a nonzero exit fails the installation
+func TestPostInstallScriptFailure(t *testing.T) {
+	config := &core.Config{
+		AppName:           "ScriptApp",
+		InstallDir:        t.TempDir(),
+		Rollback:          core.RollbackNone,
+		PostInstallScript: &core.Script{Content: failingScript()},
+	}
+
+	inst := newTestInstaller(config)
+
+	err := inst.ExecuteInstallation()
+	if err == nil {
+		t.Fatal("Expected installation to fail on nonzero script exit")
+	}
+	if !strings.Contains(err.Error(), "code 3") {
+		t.Errorf("Error should report exit code, got: %v", err)
+	}
+
+	// Failure can be ignored
+	config.PostInstallScript.IgnoreFailure = true
+	if err := inst.ExecuteInstallation(); err != nil {
+		t.Errorf("ExecuteInstallation() with IgnoreFailure error = %v", err)
+	}
+}
+
+// TestRunScriptCancellation tests timeout and context cancellation
+func TestRunScriptCancellation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep script requires a POSIX shell")
+	}
+	logger := core.NewLogger("info", "")
+	script := &core.Script{Content: "sleep 5\n", Timeout: 100 * time.Millisecond}
+
+	start := time.Now()
+	err := core.RunScript(context.Background(), script, t.TempDir(), nil, logger)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected timeout error, got: %v", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("Script was not stopped by timeout")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	script.Timeout = 0
+	if err := core.RunScript(ctx, script, t.TempDir(), nil, logger); err == nil {
+		t.Error("Expected error for cancelled context")
+	}
+}
+
+// TestRunScriptValidation tests scripts without path or content
+func TestRunScriptValidation(t *testing.T) {
+	if err := core.RunScript(context.Background(), &core.Script{}, "", nil, nil); err == nil {
+		t.Error("Expected error for empty script")
+	}
+	if err := core.RunScript(context.Background(), nil, "", nil, nil); err != nil {
+		t.Errorf("Nil script should be a no-op, got: %v", err)
+	}
+}
+
+// TestPreInstallScriptAbort tests that a failing pre-install script prevents installation
+func TestPreInstallScriptAbort(t *testing.T) {
+	installDir := filepath.Join(t.TempDir(), "app")
+	installed := false
+	config := &core.Config{
+		AppName:    "ScriptApp",
+		InstallDir: installDir,
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{{
+			ID:       "core",
+			Name:     "Core",
+			Required: true,
+			Installer: func(ctx context.Context) error {
+				installed = true
+				return nil
+			},
+		}},
+		PreInstallScript: &core.Script{Content: failingScript(), IgnoreFailure: true},
+	}
+
+	err := newTestInstaller(config).ExecuteInstallation()
+	if err == nil {
+		t.Fatal("Expected installation to be aborted")
+	}
+	if !strings.Contains(err.Error(), "pre-install script failed") || !strings.Contains(err.Error(), "code 3") {
+		t.Errorf("Error should explain the abort, got: %v", err)
+	}
+	if installed {
+		t.Error("Component must not be installed after pre-install script failure")
+	}
+	if _, err := os.Stat(installDir); !os.IsNotExist(err) {
+		t.Error("Install directory must not be created after pre-install script failure")
+	}
+}
+
+// TestPreInstallScriptContinue tests that a successful pre-install script lets installation proceed
+func TestPreInstallScriptContinue(t *testing.T) {
+	installDir := t.TempDir()
+	installed := false
+	script := &core.Script{Content: markerScript()}
+	if runtime.GOOS != "windows" {
+		script.Interpreters = map[string][]string{runtime.GOOS: {"sh", "-e"}}
+	}
+	config := &core.Config{
+		AppName:    "ScriptApp",
+		InstallDir: installDir,
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{{
+			ID:       "core",
+			Name:     "Core",
+			Required: true,
+			Installer: func(ctx context.Context) error {
+				installed = true
+				return nil
+			},
+		}},
+		PreInstallScript: script,
+	}
+
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	if !installed {
+		t.Error("Component should be installed after successful pre-install script")
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "marker.txt")); err != nil {
+		t.Errorf("Pre-install script did not run: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"slices"
+	"sort"
+)
+
+// CustomPreset names a selection that matches none of the presets
+const CustomPreset = "Custom"
+
+// PresetNames returns the names of presets, sorted
+func PresetNames(presets map[string][]string) []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyPreset selects the components with the IDs of a preset, or carrying
+// its tags, and deselects all others. Required components stay selected and
+// dependencies of selected components are selected as well, like
+// ToggleCategory does.
+func ApplyPreset(components []Component, ids []string) {
+	ids = ExpandPreset(components, ids)
+	for idx := range components {
+		if !components[idx].Required {
+			components[idx].Selected = slices.Contains(ids, components[idx].ID)
+		}
+	}
+	selectDependencies(components)
+}
+
+// PresetSelection returns the IDs of the components that applying the preset
+// with ids selects, in component order
+func PresetSelection(components []Component, ids []string) []string {
+	applied := append([]Component(nil), components...)
+	ApplyPreset(applied, ids)
+	return selectedIDs(applied)
+}
+
+// MatchingPreset returns the name of the first preset, in PresetNames order,
+// whose selection equals the current one, or CustomPreset if the user chose
+// a different set of components
+func MatchingPreset(components []Component, presets map[string][]string) string {
+	current := selectedIDs(components)
+	for _, name := range PresetNames(presets) {
+		if slices.Equal(PresetSelection(components, presets[name]), current) {
+			return name
+		}
+	}
+	return CustomPreset
+}
+
+// selectedIDs returns the IDs of the selected and required components
+func selectedIDs(components []Component) []string {
+	var ids []string
+	for _, c := range components {
+		if c.Selected || c.Required {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
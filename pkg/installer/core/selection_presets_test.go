@@ -0,0 +1,88 @@
+package core_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// presetComponents returns a component set with a required component and a dependency
+func presetComponents() []core.Component {
+	return []core.Component{
+		{ID: "core", Name: "Core", Required: true},
+		{ID: "app", Name: "Application", Selected: true},
+		{ID: "docs", Name: "Documentation"},
+		{ID: "samples", Name: "Samples", Dependencies: []string{"docs"}},
+		{ID: "devtools", Name: "Developer Tools", Selected: true},
+	}
+}
+
+var testPresets = map[string][]string{
+	"Minimal": {},
+	"Typical": {"app", "samples"},
+	"Full":    {"app", "docs", "samples", "devtools"},
+}
+
+// TestApplyPreset tests the selection of each preset
+func TestApplyPreset(t *testing.T) {
+	want := map[string][]string{
+		"Minimal": {"core"},
+		"Typical": {"core", "app", "docs", "samples"},
+		"Full":    {"core", "app", "docs", "samples", "devtools"},
+	}
+	for _, name := range core.PresetNames(testPresets) {
+		t.Run(name, func(t *testing.T) {
+			components := presetComponents()
+			core.ApplyPreset(components, testPresets[name])
+
+			var selected []string
+			for _, c := range components {
+				if c.Selected || c.Required {
+					selected = append(selected, c.ID)
+				}
+			}
+			if !reflect.DeepEqual(selected, want[name]) {
+				t.Errorf("selection = %v, want %v", selected, want[name])
+			}
+			if got := core.MatchingPreset(components, testPresets); got != name {
+				t.Errorf("MatchingPreset() = %s, want %s", got, name)
+			}
+			if got := core.PresetSelection(presetComponents(), testPresets[name]); !reflect.DeepEqual(got, want[name]) {
+				t.Errorf("PresetSelection() = %v, want %v", got, want[name])
+			}
+		})
+	}
+}
+
+// TestMatchingPresetCustom tests that deviating from a preset makes the selection custom
+func TestMatchingPresetCustom(t *testing.T) {
+	components := presetComponents()
+	if got := core.MatchingPreset(components, testPresets); got != core.CustomPreset {
+		t.Errorf("initial selection matches %s, want %s", got, core.CustomPreset)
+	}
+	core.ApplyPreset(components, testPresets["Typical"])
+	components[2].Selected = false
+	if got := core.MatchingPreset(components, testPresets); got != core.CustomPreset {
+		t.Errorf("changed selection matches %s, want %s", got, core.CustomPreset)
+	}
+}
+
+// TestValidateSelectionPresets tests that presets naming unknown components are reported
+func TestValidateSelectionPresets(t *testing.T) {
+	config := &core.Config{
+		Components:       presetComponents(),
+		SelectionPresets: map[string][]string{"Typical": {"app", "plugins"}},
+	}
+	config.Components[0].Selected = true
+	warnings, err := core.ValidateComponents(config)
+	if err != nil || len(warnings) != 1 || !strings.Contains(warnings[0], "unknown component plugins") {
+		t.Errorf("ValidateComponents() = %v, %v", warnings, err)
+	}
+
+	config.StrictComponents = true
+	if _, err := core.ValidateComponents(config); err == nil {
+		t.Error("expected an error in strict mode")
+	}
+}
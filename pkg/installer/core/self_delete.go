@@ -0,0 +1,72 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// selfDeleteDelay is the number of pings, roughly seconds, the cleanup command
+// waits for the uninstaller process to exit
+const selfDeleteDelay = 3
+
+// selfDeleteRunner removes a running executable once it has exited
+type selfDeleteRunner interface {
+	// Start launches a hidden command line that outlives the current process
+	Start(cmdLine string) error
+	// DeleteOnReboot schedules a file or empty directory for deletion at the next reboot
+	DeleteOnReboot(path string) error
+}
+
+// SelfDelete schedules removal of the running uninstaller executable and, if
+// it becomes empty, its directory after the process exits. Windows keeps
+// running executables locked, so a detached command deletes them; if that
+// cannot be started the removal is delayed until the next reboot.
+// It is a no-op on other platforms, where the executable can be removed directly.
+func SelfDelete() error {
+	if selfDeleteBackend == nil {
+		return nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	return selfDelete(exe, selfDeleteBackend)
+}
+
+func selfDelete(exe string, runner selfDeleteRunner) error {
+	cmdLine, err := selfDeleteCommandLine(exe)
+	if err != nil {
+		return err
+	}
+	startErr := runner.Start(cmdLine)
+	if startErr == nil {
+		return nil
+	}
+
+	// Files have to be scheduled before the directory that contains them
+	for _, path := range []string{exe, parentDir(exe)} {
+		if err := runner.DeleteOnReboot(path); err != nil {
+			return fmt.Errorf("failed to schedule removal of %s: %w (cleanup command: %v)", path, err, startErr)
+		}
+	}
+	return nil
+}
+
+// selfDeleteCommandLine builds the cmd.exe command line that waits for the
+// uninstaller to exit, deletes it and removes its directory if empty
+func selfDeleteCommandLine(exe string) (string, error) {
+	if strings.ContainsAny(exe, "\"%\r\n") {
+		return "", fmt.Errorf("cannot schedule removal of %q: unsupported characters in path", exe)
+	}
+	return fmt.Sprintf(`cmd.exe /C ping 127.0.0.1 -n %d > nul & del /F /Q "%s" & rmdir "%s" 2> nul`,
+		selfDeleteDelay, exe, parentDir(exe)), nil
+}
+
+// parentDir returns the directory of a Windows or slash-separated path
+func parentDir(path string) string {
+	if idx := strings.LastIndexAny(path, `\/`); idx > 0 {
+		return path[:idx]
+	}
+	return path
+}
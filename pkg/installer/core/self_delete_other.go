@@ -0,0 +1,7 @@
+//go:build !windows
+// +build !windows
+
+package core
+
+// selfDeleteBackend is nil on platforms where a running executable can be deleted
+var selfDeleteBackend selfDeleteRunner
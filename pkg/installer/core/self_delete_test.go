@@ -0,0 +1,76 @@
+package core
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeSelfDeleteRunner records the calls made by selfDelete
+type fakeSelfDeleteRunner struct {
+	startErr  error
+	rebootErr error
+	cmdLines  []string
+	scheduled []string
+}
+
+func (r *fakeSelfDeleteRunner) Start(cmdLine string) error {
+	r.cmdLines = append(r.cmdLines, cmdLine)
+	return r.startErr
+}
+
+func (r *fakeSelfDeleteRunner) DeleteOnReboot(path string) error {
+	r.scheduled = append(r.scheduled, path)
+	return r.rebootErr
+}
+
+// TestSelfDeleteCommandLine tests the cleanup command started for the uninstaller
+func TestSelfDeleteCommandLine(t *testing.T) {
+	runner := &fakeSelfDeleteRunner{}
+	exe := `C:\Program Files\My App\uninstall.exe`
+	if err := selfDelete(exe, runner); err != nil {
+		t.Fatalf("selfDelete() error = %v", err)
+	}
+	if len(runner.cmdLines) != 1 {
+		t.Fatalf("Start called %d times, want 1", len(runner.cmdLines))
+	}
+	want := `cmd.exe /C ping 127.0.0.1 -n 3 > nul & del /F /Q "C:\Program Files\My App\uninstall.exe" & rmdir "C:\Program Files\My App" 2> nul`
+	if runner.cmdLines[0] != want {
+		t.Errorf("Command line = %s, want %s", runner.cmdLines[0], want)
+	}
+	if len(runner.scheduled) != 0 {
+		t.Errorf("Unexpected reboot removal: %v", runner.scheduled)
+	}
+
+	if _, err := selfDeleteCommandLine(`C:\App\"quoted".exe`); err == nil {
+		t.Error("Expected error for path with quotes")
+	}
+}
+
+// TestSelfDeleteRebootFallback tests delayed removal when the cleanup command cannot start
+func TestSelfDeleteRebootFallback(t *testing.T) {
+	runner := &fakeSelfDeleteRunner{startErr: errors.New("access denied")}
+	if err := selfDelete(`C:\App\uninstall.exe`, runner); err != nil {
+		t.Fatalf("selfDelete() error = %v", err)
+	}
+	if got := strings.Join(runner.scheduled, ";"); got != `C:\App\uninstall.exe;C:\App` {
+		t.Errorf("Scheduled = %s, want file before directory", got)
+	}
+
+	runner = &fakeSelfDeleteRunner{startErr: errors.New("access denied"), rebootErr: errors.New("not elevated")}
+	err := selfDelete(`C:\App\uninstall.exe`, runner)
+	if err == nil || !strings.Contains(err.Error(), "not elevated") {
+		t.Errorf("selfDelete() error = %v, want reboot scheduling error", err)
+	}
+}
+
+// TestSelfDeleteNoop tests that SelfDelete leaves the executable alone outside Windows
+func TestSelfDeleteNoop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SelfDelete would remove the test binary on Windows")
+	}
+	if err := SelfDelete(); err != nil {
+		t.Errorf("SelfDelete() error = %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+//go:build windows
+// +build windows
+
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// selfDeleteBackend deletes the uninstaller with a detached cmd.exe
+var selfDeleteBackend selfDeleteRunner = windowsSelfDelete{}
+
+// windowsSelfDelete implements selfDeleteRunner with cmd.exe and MoveFileEx
+type windowsSelfDelete struct{}
+
+func (windowsSelfDelete) Start(cmdLine string) error {
+	// The command line is passed verbatim; quoting each argument would break cmd.exe
+	cmd := exec.Command("cmd.exe")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CmdLine:       cmdLine,
+		HideWindow:    true,
+		CreationFlags: windows.CREATE_NO_WINDOW,
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start cleanup command: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+func (windowsSelfDelete) DeleteOnReboot(path string) error {
+	from, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+	return windows.MoveFileEx(from, nil, windows.MOVEFILE_DELAY_UNTIL_REBOOT)
+}
@@ -0,0 +1,129 @@
+package core
+
+// InstallSession holds what the steps of an installation collect, with
+// typed accessors for the installation path and the selected components.
+// The controller creates one and passes it to its states, and the installer
+// puts its own into Context.Session for the UIs. Values of custom states
+// are kept under their keys, see Value and Set.
+//
+// Like the controller, a session is not safe for concurrent use: it belongs
+// to the goroutine running the flow, and Values hands out its map itself.
+type InstallSession struct {
+	Config    *Config
+	Logger    Logger
+	Installer *Installer // Nil when the flow runs without an installer
+
+	installPath string
+	selected    []Component
+	hasSelected bool
+	values      map[string]interface{}
+}
+
+// NewInstallSession creates a session for config that updates installer,
+// which may be nil, as the path and the selection change
+func NewInstallSession(config *Config, installer *Installer) *InstallSession {
+	session := &InstallSession{
+		Config:    config,
+		Installer: installer,
+		values:    make(map[string]interface{}),
+	}
+	if installer != nil && installer.context != nil {
+		session.Logger = installer.context.Logger
+	}
+	return session
+}
+
+// InstallPath returns the installation directory chosen so far, or the
+// configured one before it was chosen
+func (s *InstallSession) InstallPath() string {
+	if s.installPath != "" || s.Config == nil {
+		return s.installPath
+	}
+	return s.Config.InstallDir
+}
+
+// SetInstallPath chooses the installation directory
+func (s *InstallSession) SetInstallPath(path string) {
+	s.installPath = path
+	if s.Installer != nil {
+		s.Installer.SetInstallPath(path)
+	}
+}
+
+// SelectedComponents returns the components selected so far, or before
+// the selection the configured ones that are selected or required
+func (s *InstallSession) SelectedComponents() []Component {
+	if s.hasSelected {
+		return append([]Component(nil), s.selected...)
+	}
+	var selected []Component
+	if s.Config != nil {
+		for _, c := range s.Config.Components {
+			if c.Selected || c.Required {
+				selected = append(selected, c)
+			}
+		}
+	}
+	return selected
+}
+
+// SetSelectedComponents records the selected components
+func (s *InstallSession) SetSelectedComponents(components []Component) {
+	s.selected = append([]Component(nil), components...)
+	s.hasSelected = true
+	if s.Installer != nil {
+		s.Installer.SetSelectedComponents(components)
+	}
+}
+
+// IsSelected reports whether the component with id is selected
+func (s *InstallSession) IsSelected(id string) bool {
+	for _, c := range s.SelectedComponents() {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns the value stored under key
+func (s *InstallSession) Value(key string) (interface{}, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores value under key
+func (s *InstallSession) Set(key string, value interface{}) {
+	s.values[key] = value
+}
+
+// Values returns the map of custom values itself, for code that still
+// works on the loose data map
+func (s *InstallSession) Values() map[string]interface{} {
+	return s.values
+}
+
+// SessionValue returns the value stored under key if it is a T
+func SessionValue[T any](s *InstallSession, key string) (T, bool) {
+	value, ok := s.Value(key)
+	typed, isT := value.(T)
+	return typed, ok && isT
+}
+
+// Session returns the session of the installation, created by Run
+func (i *Installer) Session() *InstallSession {
+	if i.context == nil {
+		return nil
+	}
+	return i.context.Session
+}
+
+// Installer returns the installer running with the context: the one of
+// its session or, for contexts created by hand, Metadata["installer"]
+func (c *Context) Installer() *Installer {
+	if c.Session != nil && c.Session.Installer != nil {
+		return c.Session.Installer
+	}
+	installer, _ := c.Metadata["installer"].(*Installer)
+	return installer
+}
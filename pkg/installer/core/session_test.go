@@ -0,0 +1,51 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestSessionUpdatesInstaller tests that the session's path and selection reach the installer
+func TestSessionUpdatesInstaller(t *testing.T) {
+	config := &core.Config{
+		AppName:    "SessionApp",
+		Version:    "1.0.0",
+		InstallDir: t.TempDir(),
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true},
+			{ID: "docs", Name: "Docs", Selected: true},
+		},
+	}
+	inst := newTestInstaller(config)
+	session := core.NewInstallSession(config, inst)
+
+	dir := t.TempDir()
+	session.SetInstallPath(dir)
+	session.SetSelectedComponents([]core.Component{config.Components[0]})
+
+	if got := inst.GetConfig().InstallDir; got != dir {
+		t.Errorf("installer directory = %q, want %q", got, dir)
+	}
+	if session.IsSelected("docs") || config.Components[1].Selected {
+		t.Error("docs is still selected")
+	}
+}
+
+// TestContextInstaller tests that a context finds its installer in the session or, without one, in Metadata
+func TestContextInstaller(t *testing.T) {
+	config := &core.Config{AppName: "SessionApp", Version: "1.0.0"}
+	inst := core.New(config)
+
+	ctx := &core.Context{Config: config, Metadata: map[string]interface{}{"installer": inst}}
+	if ctx.Installer() != inst {
+		t.Error("Installer() does not return the installer of Metadata")
+	}
+	ctx = &core.Context{Config: config, Session: core.NewInstallSession(config, inst)}
+	if ctx.Installer() != inst {
+		t.Error("Installer() does not return the installer of the session")
+	}
+	if (&core.Context{}).Installer() != nil {
+		t.Error("Installer() of an empty context is not nil")
+	}
+}
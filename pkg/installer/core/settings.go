@@ -0,0 +1,274 @@
+package core
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Setting keys shared by command-line flags, environment variables and config files
+const (
+	SettingInstallDir    = "install_dir"
+	SettingMode          = "mode"
+	SettingAcceptLicense = "accept_license"
+	SettingUnattended    = "unattended"
+	SettingProfile       = "profile"
+	SettingLogLevel      = "log_level"
+	SettingLocale        = "locale"
+	SettingPathScope     = "path_scope"
+	SettingPortable      = "portable"
+	SettingInstallScope  = "install_scope"
+)
+
+// Setting sources, in increasing order of precedence
+const (
+	SourceDefault  = "default"
+	SourceEmbedded = "embedded"
+	SourceFile     = "file"
+	SourceProfile  = "profile"
+	SourceEnv      = "env"
+	SourceFlags    = "flags"
+)
+
+// DefaultEnvPrefix is the prefix of environment variables read by EnvSettings
+const DefaultEnvPrefix = "SETUPKIT"
+
+// Settings holds configuration values by setting key
+type Settings map[string]string
+
+var settingKeys = []string{
+	SettingInstallDir,
+	SettingMode,
+	SettingAcceptLicense,
+	SettingUnattended,
+	SettingProfile,
+	SettingLogLevel,
+	SettingLocale,
+	SettingPathScope,
+	SettingPortable,
+	SettingInstallScope,
+}
+
+// EnvSettings reads settings from environment variables named
+// <prefix>_<KEY>, e.g. SETUPKIT_INSTALL_DIR. lookup is usually os.LookupEnv.
+func EnvSettings(prefix string, lookup func(string) (string, bool)) Settings {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	settings := make(Settings)
+	for _, key := range settingKeys {
+		if value, ok := lookup(prefix + "_" + strings.ToUpper(key)); ok {
+			settings[key] = value
+		}
+	}
+	return settings
+}
+
+// FlagSettings returns the settings of flags that were set explicitly on the
+// command line. names maps flag names to setting keys; flags not in names are
+// matched by their own name with dashes turned into underscores.
+func FlagSettings(fs *flag.FlagSet, names map[string]string) Settings {
+	settings := make(Settings)
+	fs.Visit(func(f *flag.Flag) {
+		key, ok := names[f.Name]
+		if !ok {
+			key = strings.ReplaceAll(f.Name, "-", "_")
+		}
+		settings[key] = f.Value.String()
+	})
+	return settings
+}
+
+// SettingsLayer is a set of settings together with the source it came from
+type SettingsLayer struct {
+	Source   string
+	Settings Settings
+}
+
+// ResolveSettings merges layers from lowest to highest precedence and reports,
+// per setting key, the source of the winning value
+func ResolveSettings(layers ...SettingsLayer) (Settings, map[string]string) {
+	merged := make(Settings)
+	provenance := make(map[string]string)
+	for _, layer := range layers {
+		for key, value := range layer.Settings {
+			merged[key] = value
+			provenance[key] = layer.Source
+		}
+	}
+	return merged, provenance
+}
+
+// MergeSettings combines settings layers from lowest to highest precedence,
+// e.g. MergeSettings(embedded, file, env, flags)
+func MergeSettings(layers ...Settings) Settings {
+	merged := make(Settings)
+	for _, layer := range layers {
+		for key, value := range layer {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// ApplySettings overrides the configuration with settings values. Unknown keys
+// are ignored so that applications can carry their own settings.
+func (c *Config) ApplySettings(settings Settings) error {
+	// The scope first, so that install_dir and path_scope override what it proposes
+	if value, ok := settings[SettingInstallScope]; ok {
+		scope, err := ParseInstallScope(value)
+		if err != nil {
+			return err
+		}
+		c.SetInstallScope(scope)
+	}
+	for key, value := range settings {
+		switch key {
+		case SettingInstallDir:
+			dir, err := ExpandPath(value)
+			if err != nil {
+				return err
+			}
+			c.InstallDir = dir
+		case SettingMode:
+			mode, err := ParseMode(value)
+			if err != nil {
+				return err
+			}
+			c.Mode = mode
+		case SettingAcceptLicense, SettingUnattended, SettingPortable:
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid value for %s: %q", key, value)
+			}
+			switch key {
+			case SettingAcceptLicense:
+				c.AcceptLicense = enabled
+			case SettingUnattended:
+				c.Unattended = enabled
+			default:
+				c.Portable = enabled
+			}
+		case SettingProfile:
+			c.Profile = value
+		case SettingLogLevel:
+			c.LogLevel = value
+		case SettingLocale:
+			c.Locale = value
+		case SettingPathScope:
+			if c.PathConfig == nil {
+				break
+			}
+			if err := c.PathConfig.SetScope(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ApplySettingsLayers resolves layers, applies the result and records the
+// winning source of each setting in c.Provenance
+func (c *Config) ApplySettingsLayers(layers ...SettingsLayer) error {
+	settings, provenance := ResolveSettings(layers...)
+	if err := c.ApplySettings(settings); err != nil {
+		return err
+	}
+	if c.Provenance == nil {
+		c.Provenance = make(map[string]string)
+	}
+	for key, source := range provenance {
+		c.Provenance[key] = source
+	}
+	return nil
+}
+
+// LoadResponseFile applies the settings of c.ResponseFile, if set, as a
+// SourceFile layer. Settings that a profile, the environment, flags or an
+// application source already provided keep their values.
+func (c *Config) LoadResponseFile() error {
+	if c.ResponseFile == "" {
+		return nil
+	}
+	settings, err := ReadResponseFile(c.ResponseFile)
+	if err != nil {
+		return err
+	}
+	for key := range settings {
+		switch c.Provenance[key] {
+		case "", SourceDefault, SourceEmbedded, SourceFile:
+		default:
+			delete(settings, key)
+		}
+	}
+	return c.ApplySettingsLayers(SettingsLayer{Source: SourceFile, Settings: settings})
+}
+
+// ParseMode converts a mode name such as "cli" or "silent" to a Mode
+func ParseMode(name string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "auto":
+		return ModeAuto, nil
+	case "gui":
+		return ModeGUI, nil
+	case "browser":
+		return ModeBrowser, nil
+	case "cli":
+		return ModeCLI, nil
+	case "silent":
+		return ModeSilent, nil
+	}
+	return ModeAuto, fmt.Errorf("unknown mode: %s", name)
+}
+
+// ReadResponseFile reads settings recorded by WriteResponseFile, one
+// key=value per line. Empty lines and lines starting with # are ignored.
+func ReadResponseFile(path string) (Settings, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response file: %w", err)
+	}
+	defer file.Close()
+
+	settings := make(Settings)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key=value", path, line)
+		}
+		settings[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read response file: %w", err)
+	}
+	return settings, nil
+}
+
+// WriteResponseFile writes settings to path, sorted by key, so that a later
+// unattended installation can replay them with ReadResponseFile
+func WriteResponseFile(path string, settings Settings) error {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("# Installer response file\n")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", key, settings[key])
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write response file: %w", err)
+	}
+	return nil
+}
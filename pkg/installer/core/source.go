@@ -0,0 +1,217 @@
+package core
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Payload sources provide the files listed in Component.Files. Any fs.FS
+// works; the adapters below cover embedded assets, directories and zip
+// archives, local or downloaded.
+
+// EmbedSource returns the directory root of embedded assets as a payload
+// source, e.g. EmbedSource(assets, "assets") for //go:embed assets/*
+func EmbedSource(assets fs.FS, root string) (fs.FS, error) {
+	if root == "" || root == "." {
+		return assets, nil
+	}
+	return fs.Sub(assets, root)
+}
+
+// DirSource returns a payload source reading from a directory on disk
+func DirSource(dir string) fs.FS {
+	return os.DirFS(dir)
+}
+
+// ZipSource is a payload source backed by a zip archive
+type ZipSource struct {
+	*zip.ReadCloser
+	temp string // Downloaded archive removed on Close
+}
+
+// OpenZipSource opens a zip archive as payload source
+func OpenZipSource(path string) (*ZipSource, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open payload archive: %w", err)
+	}
+	return &ZipSource{ReadCloser: r}, nil
+}
+
+// DownloadZipSource downloads a zip archive with Download, so proxy settings
+// and retries apply, into Config.TempDir and opens it as payload source
+func DownloadZipSource(ctx context.Context, config *Config, rawURL string) (*ZipSource, error) {
+	dir, err := config.newStagingDir("setupkit-payload-")
+	if err != nil {
+		return nil, err
+	}
+	archive := filepath.Join(dir, "payload.zip")
+	if err := Download(ctx, config, rawURL, archive); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	source, err := OpenZipSource(archive)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	source.temp = dir
+	return source, nil
+}
+
+// Close closes the archive and removes it if it was downloaded
+func (z *ZipSource) Close() error {
+	err := z.ReadCloser.Close()
+	if z.temp != "" {
+		os.RemoveAll(z.temp)
+	}
+	return err
+}
+
+// PayloadSource returns Source, or Assets for configurations that predate it
+func (c *Config) PayloadSource() fs.FS {
+	if c.Source != nil {
+		return c.Source
+	}
+	return c.Assets
+}
+
+// payloadSources returns where component files are looked up: the payload
+// source first, then the bundle directory
+func (c *Config) payloadSources() []fs.FS {
+	var sources []fs.FS
+	if source := c.PayloadSource(); source != nil {
+		sources = append(sources, source)
+	}
+	if c.BundleDir != "" {
+		sources = append(sources, DirSource(c.BundleDir))
+	}
+	return sources
+}
+
+// copyComponentFiles copies the files of a component into installDir, taking
+// each from the first source that has it. The bytes copied are reported to
+// reporter whenever another percent of the component's files is done. A file
+// that cannot be written fails with an InstallError telling the user what to
+// do about it; read-only files an earlier installation of the component put
+// there are overwritten. beforeWrite, if set, receives the size of each file
+// before it is copied and stops the copy with its error.
+func copyComponentFiles(sources []fs.FS, installDir string, component Component, reporter ProgressReporter, beforeWrite func(size int64) error) error {
+	managed := managedFiles(installDir, component.ID)
+	var total, done int64
+	for _, name := range component.Files {
+		total += sourceFileSize(sources, name)
+	}
+	lastPercent := int64(-1)
+	for _, name := range component.Files {
+		message := "Copying " + name
+		copied := func(n int64) {
+			done += n
+			if total <= 0 {
+				return
+			}
+			if percent := min(done, total) * 100 / total; percent != lastPercent {
+				lastPercent = percent
+				ReportProgress(reporter, done, total, message)
+			}
+		}
+
+		if beforeWrite != nil {
+			if err := beforeWrite(sourceFileSize(sources, name)); err != nil {
+				return err
+			}
+		}
+
+		dest := filepath.Join(installDir, filepath.FromSlash(name))
+		err := fs.ErrNotExist
+		for _, source := range sources {
+			if err = copyFromSource(source, name, dest, managed[filepath.ToSlash(name)], copied); !errors.Is(err, fs.ErrNotExist) {
+				break
+			}
+		}
+		if err != nil {
+			return NewInstallError(fmt.Errorf("failed to copy %s: %w", name, err), PhaseFileCopy, component.ID)
+		}
+	}
+	return nil
+}
+
+// managedFiles returns the files the installation in installDir recorded for
+// a component, none if there is no earlier installation
+func managedFiles(installDir, componentID string) map[string]bool {
+	files := make(map[string]bool)
+	manifest, err := LoadManifest(installDir)
+	if err != nil {
+		return files
+	}
+	if entry, ok := manifest.Component(componentID); ok {
+		for _, f := range entry.Files {
+			files[f.Path] = true
+		}
+	}
+	return files
+}
+
+// sourceFileSize returns the size of name in the first source that has it, 0 if none does
+func sourceFileSize(sources []fs.FS, name string) int64 {
+	for _, source := range sources {
+		if info, err := fs.Stat(source, path.Clean(filepath.ToSlash(name))); err == nil {
+			return info.Size()
+		}
+	}
+	return 0
+}
+
+// openFile opens the target of a copy, replaced in tests to inject failures
+var openFile = os.OpenFile
+
+// copyFromSource copies one file, keeping the executable bit of the source,
+// and passes the number of bytes written to copied as the copy goes on. A
+// managed target the installer may overwrite loses its read-only attribute.
+func copyFromSource(source fs.FS, name, dest string, managed bool, copied func(n int64)) error {
+	src, err := source.Open(path.Clean(filepath.ToSlash(name)))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	mode := os.FileMode(0644)
+	if info, err := src.Stat(); err == nil && info.Mode().Perm()&0111 != 0 {
+		mode = 0755
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	dst, err := openFile(dest, flags, mode)
+	if errors.Is(err, fs.ErrPermission) && managed && clearReadOnly(dest) {
+		dst, err = openFile(dest, flags, mode)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(&countingWriter{w: dst, written: copied}, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// countingWriter passes the number of bytes of each write to written
+type countingWriter struct {
+	w       io.Writer
+	written func(n int64)
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.written(int64(n))
+	return n, err
+}
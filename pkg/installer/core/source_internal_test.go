@@ -0,0 +1,84 @@
+package core
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+)
+
+// denyOpen makes opening copy targets fail with err for the rest of the test
+func denyOpen(t *testing.T, err error) {
+	t.Helper()
+	t.Cleanup(func() { openFile = os.OpenFile })
+	openFile = func(name string, flag int, perm os.FileMode) (*os.File, error) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+}
+
+// TestCopyPermissionError tests that a target the installer may not write
+// fails with an InstallError that tells the user to elevate
+func TestCopyPermissionError(t *testing.T) {
+	denyOpen(t, fs.ErrPermission)
+	source := fstest.MapFS{"bin/app": {Data: []byte("app")}}
+	component := Component{ID: "core", Files: []string{"bin/app"}}
+
+	err := copyComponentFiles([]fs.FS{source}, t.TempDir(), component, discardProgress{}, nil)
+	var installErr *InstallError
+	if !errors.As(err, &installErr) {
+		t.Fatalf("copyComponentFiles() error = %v, want an InstallError", err)
+	}
+	if installErr.Kind != ErrorKindPermissionDenied || installErr.Hint != HintPermissionDenied {
+		t.Errorf("Kind, Hint = %q, %q; want the permission hint", installErr.Kind, installErr.Hint)
+	}
+	if installErr.Component != "core" || installErr.Phase != PhaseFileCopy {
+		t.Errorf("unexpected component %q or phase %q", installErr.Component, installErr.Phase)
+	}
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Error("InstallError should unwrap to the permission error")
+	}
+}
+
+// TestCopyOverReadOnlyManagedFile tests that a read-only file an earlier
+// installation recorded is overwritten on Windows
+func TestCopyOverReadOnlyManagedFile(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("only Windows marks files read-only independently of their permissions")
+	}
+	installDir := t.TempDir()
+	target := filepath.Join(installDir, "app.exe")
+	if err := os.WriteFile(target, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	component := Component{ID: "core", Files: []string{"app.exe"}}
+	manifest := &Manifest{InstallDir: installDir}
+	manifest.SetComponent(NewManifestComponent(installDir, component))
+	if err := manifest.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(target, 0444); err != nil {
+		t.Fatal(err)
+	}
+
+	source := fstest.MapFS{"app.exe": {Data: []byte("new")}}
+	if err := copyComponentFiles([]fs.FS{source}, installDir, component, discardProgress{}, nil); err != nil {
+		t.Fatalf("copyComponentFiles() error = %v", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "new" {
+		t.Errorf("target = %q, want the new content", data)
+	}
+
+	// Read-only files the installer does not manage stay untouched
+	foreign := filepath.Join(installDir, "notes.txt")
+	if err := os.WriteFile(foreign, []byte("mine"), 0444); err != nil {
+		t.Fatal(err)
+	}
+	source["notes.txt"] = &fstest.MapFile{Data: []byte("theirs")}
+	err := copyComponentFiles([]fs.FS{source}, installDir, Component{ID: "core", Files: []string{"notes.txt"}}, discardProgress{}, nil)
+	if kind, _ := ClassifyError(err); kind != ErrorKindPermissionDenied {
+		t.Errorf("copy over a foreign read-only file: kind %q, want %q", kind, ErrorKindPermissionDenied)
+	}
+}
@@ -0,0 +1,97 @@
+package core_test
+
+import (
+	"archive/zip"
+	"embed"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+//go:embed testdata/payload
+var testPayload embed.FS
+
+// installFromSource installs two components from source and checks the copied files
+func installFromSource(t *testing.T, source fs.FS) {
+	t.Helper()
+	config := &core.Config{
+		AppName:    "SourceApp",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		Rollback:   core.RollbackNone,
+		Source:     source,
+		Components: []core.Component{
+			{ID: "core", Required: true, Files: []string{"bin/app.txt"}},
+			{ID: "docs", Selected: true, Files: []string{"docs/readme.txt"}},
+		},
+	}
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	for name, want := range map[string]string{"bin/app.txt": "app binary\n", "docs/readme.txt": "read me\n"} {
+		data, err := os.ReadFile(filepath.Join(config.InstallDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("%s not installed: %v", name, err)
+		} else if string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+}
+
+// TestInstallFromEmbedSource tests installing from embedded assets
+func TestInstallFromEmbedSource(t *testing.T) {
+	source, err := core.EmbedSource(testPayload, "testdata/payload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	installFromSource(t, source)
+}
+
+// TestInstallFromDirSource tests installing from a directory through the same path
+func TestInstallFromDirSource(t *testing.T) {
+	installFromSource(t, core.DirSource(filepath.Join("testdata", "payload")))
+}
+
+// TestInstallFromZipSource tests installing from a zip archive
+func TestInstallFromZipSource(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "payload.zip")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for name, content := range map[string]string{"bin/app.txt": "app binary\n", "docs/readme.txt": "read me\n"} {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	source, err := core.OpenZipSource(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+	installFromSource(t, source)
+}
+
+// TestInstallMissingSourceFile tests that a file absent from the source fails the install
+func TestInstallMissingSourceFile(t *testing.T) {
+	config := &core.Config{
+		AppName:    "SourceApp",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		Rollback:   core.RollbackNone,
+		Source:     core.DirSource(filepath.Join("testdata", "payload")),
+		Components: []core.Component{{ID: "core", Required: true, Files: []string{"bin/missing.txt"}}},
+	}
+	if err := newTestInstaller(config).ExecuteInstallation(); err == nil {
+		t.Error("ExecuteInstallation() succeeded with a missing payload file")
+	}
+}
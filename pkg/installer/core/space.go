@@ -0,0 +1,89 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// spaceReserve is the headroom required on top of the component sizes
+const spaceReserve = 1.2
+
+// SpaceEstimate compares the size of a component selection with the free
+// space on the target volume
+type SpaceEstimate struct {
+	Selected   int64 // Total size on disk of the selected and required components
+	Download   int64 // Total payload size of the selected and required components
+	Available  int64 // Free space on the target volume, -1 if unknown
+	Remaining  int64 // Free space left after installation, meaningless if Available is unknown
+	OverBudget bool  // The selection plus a 20% reserve does not fit
+}
+
+// DiskSize returns the space the component takes after installation:
+// InstalledSize, or Size if that is unset
+func (c Component) DiskSize() int64 {
+	if c.InstalledSize > 0 {
+		return c.InstalledSize
+	}
+	return c.Size
+}
+
+// SelectedSize returns the total size on disk of the selected and required
+// components, see Component.DiskSize
+func SelectedSize(components []Component) int64 {
+	var total int64
+	for _, c := range components {
+		if c.Selected || c.Required {
+			total += c.DiskSize()
+		}
+	}
+	return total
+}
+
+// SelectedDownloadSize returns the total payload size of the selected and
+// required components
+func SelectedDownloadSize(components []Component) int64 {
+	var total int64
+	for _, c := range components {
+		if c.Selected || c.Required {
+			total += c.Size
+		}
+	}
+	return total
+}
+
+// EstimateSpace computes the space estimate for components; pass -1 as
+// available if the free space is unknown
+func EstimateSpace(components []Component, available int64) SpaceEstimate {
+	estimate := SpaceEstimate{
+		Selected:  SelectedSize(components),
+		Download:  SelectedDownloadSize(components),
+		Available: available,
+	}
+	if available >= 0 {
+		estimate.Remaining = available - estimate.Selected
+		estimate.OverBudget = withSpaceReserve(estimate.Selected) > available
+	}
+	return estimate
+}
+
+// AvailableSpace returns the free space on the volume of path, which does not
+// need to exist yet
+func AvailableSpace(path string) (int64, error) {
+	dir := filepath.Clean(path)
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return getAvailableSpace(dir)
+}
+
+// withSpaceReserve adds the installation headroom to size
+func withSpaceReserve(size int64) int64 {
+	return int64(float64(size) * spaceReserve)
+}
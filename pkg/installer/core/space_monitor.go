@@ -0,0 +1,57 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultSpaceCheckInterval is how often free space is checked again while
+// installing if Config.SpaceCheckInterval is zero
+const DefaultSpaceCheckInterval = 2 * time.Second
+
+// LargeFileSize is the size from which a payload file is preceded by a check
+// of the free space, see Config.SpaceCheckInterval
+const LargeFileSize = 1 << 20
+
+// SpaceProbe returns the free space on the volume of path, like AvailableSpace
+type SpaceProbe func(path string) (int64, error)
+
+// SetSpaceProbe replaces how the free space is determined while installing,
+// for example to simulate another program filling the disk
+func (i *Installer) SetSpaceProbe(probe SpaceProbe) {
+	i.spaceProbe = probe
+}
+
+// recheckSpace fails with an InstallError of ErrorKindDiskFull if the free
+// space on the target volume dropped below need bytes since the pre-check,
+// because another program used it. It checks at most once per
+// Config.SpaceCheckInterval; a free space that cannot be determined is no error.
+func (i *Installer) recheckSpace(need int64, component string) error {
+	interval := i.config.SpaceCheckInterval
+	if interval == 0 {
+		interval = DefaultSpaceCheckInterval
+	}
+	if interval < 0 || i.config.DryRun || need <= 0 {
+		return nil
+	}
+	if !i.lastSpaceCheck.IsZero() && time.Since(i.lastSpaceCheck) < interval {
+		return nil
+	}
+	i.lastSpaceCheck = time.Now()
+
+	probe := i.spaceProbe
+	if probe == nil {
+		probe = AvailableSpace
+	}
+	available, err := probe(i.config.InstallDir)
+	if err != nil {
+		i.context.Logger.Debug("Failed to check free space", "error", err)
+		return nil
+	}
+	if available < need {
+		i.context.Logger.Error("Free space dropped while installing", "available", available, "required", need)
+		return NewInstallError(fmt.Errorf("%w: free space dropped to %d bytes while installing, %d bytes still needed",
+			ErrInsufficientSpace, available, need), PhaseFileCopy, component)
+	}
+	return nil
+}
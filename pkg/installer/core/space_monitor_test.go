@@ -0,0 +1,145 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+const mib = 1 << 20
+
+// shrinkingDisk simulates the free space of the target volume, which another program uses up
+type shrinkingDisk struct {
+	free   int64
+	probes int
+}
+
+func (d *shrinkingDisk) probe(string) (int64, error) {
+	d.probes++
+	return d.free, nil
+}
+
+// assertDiskFull checks that err is a rolled back disk full InstallError of component
+func assertDiskFull(t *testing.T, err error, component string) {
+	t.Helper()
+	if !errors.Is(err, core.ErrInsufficientSpace) {
+		t.Fatalf("ExecuteInstallation() error = %v, want insufficient space", err)
+	}
+	var installErr *core.InstallError
+	if !errors.As(err, &installErr) || installErr.Kind != core.ErrorKindDiskFull || installErr.Component != component || !installErr.RolledBack {
+		t.Errorf("ExecuteInstallation() error = %#v, want a rolled back disk full error of %s", installErr, component)
+	}
+}
+
+// TestSpaceDropsBetweenComponents tests that the installation stops and rolls back when the
+// free space no longer fits the remaining components
+func TestSpaceDropsBetweenComponents(t *testing.T) {
+	disk := &shrinkingDisk{free: 100 * mib}
+	var calls []string
+	config := &core.Config{
+		AppName:            "SpaceApp",
+		Version:            "1.0.0",
+		InstallDir:         t.TempDir(),
+		Rollback:           core.RollbackFull,
+		SpaceCheckInterval: time.Nanosecond,
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, InstalledSize: mib,
+				Installer: func(context.Context) error {
+					calls = append(calls, "install core")
+					disk.free = 2 * mib // Another program fills the disk
+					return nil
+				},
+				Uninstaller: func(context.Context) error {
+					calls = append(calls, "uninstall core")
+					return nil
+				}},
+			{ID: "data", Name: "Data", Selected: true, InstalledSize: 5 * mib,
+				Installer: func(context.Context) error {
+					calls = append(calls, "install data")
+					return nil
+				}},
+		},
+	}
+	inst := newTestInstaller(config)
+	inst.SetSpaceProbe(disk.probe)
+
+	err := inst.ExecuteInstallation()
+	assertDiskFull(t, err, "data")
+	assertCalls(t, "component", calls, []string{"install core", "uninstall core"})
+}
+
+// TestSpaceDropsBeforeLargeFile tests that a large payload file is not written when the
+// free space dropped below its size
+func TestSpaceDropsBeforeLargeFile(t *testing.T) {
+	disk := &shrinkingDisk{free: 10 * mib}
+	config := &core.Config{
+		AppName:            "SpaceApp",
+		Version:            "1.0.0",
+		InstallDir:         t.TempDir(),
+		Rollback:           core.RollbackFull,
+		SpaceCheckInterval: time.Nanosecond,
+		Source: fstest.MapFS{
+			"small.txt": {Data: []byte("small")},
+			"big.bin":   {Data: make([]byte, 2*mib)},
+		},
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, InstalledSize: 3 * mib, Files: []string{"small.txt", "big.bin"}},
+		},
+	}
+	inst := newTestInstaller(config)
+	inst.SetSpaceProbe(func(path string) (int64, error) {
+		if disk.probes == 1 {
+			disk.free = mib // Used up after the check before the component
+		}
+		return disk.probe(path)
+	})
+
+	err := inst.ExecuteInstallation()
+	assertDiskFull(t, err, "core")
+	// Small files are written without a check
+	if disk.probes != 2 {
+		t.Errorf("free space checked %d times, want before the component and before big.bin", disk.probes)
+	}
+	if _, err := os.Stat(filepath.Join(config.InstallDir, "big.bin")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("big.bin written although the space was gone: %v", err)
+	}
+}
+
+// TestSpaceCheckInterval tests that the free space is checked at most once per interval
+// and never with a negative interval
+func TestSpaceCheckInterval(t *testing.T) {
+	for _, tt := range []struct {
+		interval time.Duration
+		want     int
+	}{
+		{time.Hour, 1},
+		{-1, 0},
+	} {
+		disk := &shrinkingDisk{free: 100 * mib}
+		config := &core.Config{
+			AppName:            "SpaceApp",
+			Version:            "1.0.0",
+			InstallDir:         t.TempDir(),
+			Rollback:           core.RollbackNone,
+			SpaceCheckInterval: tt.interval,
+			Components: []core.Component{
+				{ID: "a", Name: "A", Required: true, InstalledSize: mib, Installer: func(context.Context) error { return nil }},
+				{ID: "b", Name: "B", Required: true, InstalledSize: mib, Installer: func(context.Context) error { return nil }},
+			},
+		}
+		inst := newTestInstaller(config)
+		inst.SetSpaceProbe(disk.probe)
+		if err := inst.ExecuteInstallation(); err != nil {
+			t.Fatalf("interval %v: ExecuteInstallation() error = %v", tt.interval, err)
+		}
+		if disk.probes != tt.want {
+			t.Errorf("interval %v: free space checked %d times, want %d", tt.interval, disk.probes, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestEstimateSpace tests size recomputation and the over-budget threshold
+func TestEstimateSpace(t *testing.T) {
+	components := []core.Component{
+		{ID: "core", Size: 600, Required: true},
+		{ID: "docs", Size: 200},
+		{ID: "extras", Size: 400, Selected: true},
+	}
+
+	estimate := core.EstimateSpace(components, 1200)
+	if estimate.Selected != 1000 || estimate.Remaining != 200 {
+		t.Errorf("EstimateSpace() = %+v, want 1000 selected and 200 remaining", estimate)
+	}
+	if estimate.OverBudget {
+		t.Error("1000 bytes plus 20% reserve fit into 1200")
+	}
+
+	// Toggling docs on changes the totals
+	components[1].Selected = true
+	estimate = core.EstimateSpace(components, 1200)
+	if estimate.Selected != 1200 || estimate.Remaining != 0 {
+		t.Errorf("EstimateSpace() = %+v, want 1200 selected and 0 remaining", estimate)
+	}
+	if !estimate.OverBudget {
+		t.Error("Selection that leaves no reserve should be over budget")
+	}
+
+	if estimate := core.EstimateSpace(components, -1); estimate.OverBudget || estimate.Available != -1 {
+		t.Errorf("Unknown free space must not warn: %+v", estimate)
+	}
+}
+
+// TestAvailableSpaceMissingDir tests free space detection for a directory that does not exist yet
+func TestAvailableSpaceMissingDir(t *testing.T) {
+	available, err := core.AvailableSpace(t.TempDir() + "/not/created/yet")
+	if err != nil || available <= 0 {
+		t.Errorf("AvailableSpace() = %d, %v", available, err)
+	}
+}
+
+// TestInstalledSize tests that the size on disk replaces the payload size where set
+func TestInstalledSize(t *testing.T) {
+	components := []core.Component{
+		{ID: "core", Size: 300, InstalledSize: 900, Required: true},
+		{ID: "docs", Size: 100, Selected: true},
+	}
+
+	estimate := core.EstimateSpace(components, 1100)
+	if estimate.Selected != 1000 || estimate.Download != 400 {
+		t.Errorf("EstimateSpace() = %+v, want 1000 on disk and 400 to download", estimate)
+	}
+	if !estimate.OverBudget {
+		t.Error("1000 bytes on disk plus 20% reserve do not fit into 1100")
+	}
+	if got := components[1].DiskSize(); got != 100 {
+		t.Errorf("DiskSize() = %d, want the payload size 100 when InstalledSize is unset", got)
+	}
+}
+
+// TestDiskSpaceCheckUsesInstalledSize tests that the pre-install check asks for the expanded size
+func TestDiskSpaceCheckUsesInstalledSize(t *testing.T) {
+	newConfig := func(installedSize int64) *core.Config {
+		return &core.Config{
+			AppName:    "SpaceApp",
+			InstallDir: t.TempDir(),
+			Rollback:   core.RollbackNone,
+			Components: []core.Component{
+				{ID: "core", Name: "Core", Required: true, Size: 1024, InstalledSize: installedSize},
+			},
+		}
+	}
+
+	if err := newTestInstaller(newConfig(0)).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v for a 1KB payload", err)
+	}
+	// A small download that expands to a petabyte does not fit
+	if err := newTestInstaller(newConfig(1 << 50)).ExecuteInstallation(); err == nil {
+		t.Error("ExecuteInstallation() should fail the disk space check for the installed size")
+	}
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ErrRequirementsNotMet reports that the computer lacks the memory or
+// processors the application needs, see SystemRequirements
+var ErrRequirementsNotMet = errors.New("system requirements not met")
+
+// SystemResources describes the memory and processors of the computer
+type SystemResources struct {
+	TotalMemory     int64 // Physical memory in bytes, 0 if unknown
+	AvailableMemory int64 // Memory in bytes available without swapping, 0 if unknown
+	CPUs            int   // Logical processors
+}
+
+// SystemRequirements are the memory and processors an application needs.
+// Minimums are hard requirements: a computer below them fails the check
+// before installing. Recommendations are soft requirements: falling short
+// of them only produces warnings. Zero values are not checked.
+type SystemRequirements struct {
+	MinMemory         int64 // Physical memory in bytes
+	RecommendedMemory int64
+	MinCPUs           int
+	RecommendedCPUs   int
+}
+
+// SystemInfo returns the memory and processors of the computer. Memory is
+// read from /proc/meminfo on Linux, sysctl on macOS and GlobalMemoryStatusEx
+// on Windows; the processors are the ones the Go runtime sees.
+func SystemInfo() (SystemResources, error) {
+	info := SystemResources{CPUs: runtime.NumCPU()}
+	total, available, err := systemMemory()
+	if err != nil {
+		return info, fmt.Errorf("failed to read system memory: %w", err)
+	}
+	info.TotalMemory, info.AvailableMemory = total, available
+	return info, nil
+}
+
+// CheckSystemRequirements compares info with req. It returns a warning for
+// each recommendation info falls short of and an error wrapping
+// ErrRequirementsNotMet if a minimum is not met. Unknown values in info
+// pass every check.
+func CheckSystemRequirements(req SystemRequirements, info SystemResources) (warnings []string, err error) {
+	var failures []string
+	if info.TotalMemory > 0 {
+		switch {
+		case req.MinMemory > 0 && info.TotalMemory < req.MinMemory:
+			failures = append(failures, fmt.Sprintf("%s of memory required, %s installed",
+				formatBytes(req.MinMemory), formatBytes(info.TotalMemory)))
+		case req.RecommendedMemory > 0 && info.TotalMemory < req.RecommendedMemory:
+			warnings = append(warnings, fmt.Sprintf("%s of memory recommended, %s installed",
+				formatBytes(req.RecommendedMemory), formatBytes(info.TotalMemory)))
+		}
+	}
+	if info.CPUs > 0 {
+		switch {
+		case req.MinCPUs > 0 && info.CPUs < req.MinCPUs:
+			failures = append(failures, fmt.Sprintf("%d processor cores required, %d available", req.MinCPUs, info.CPUs))
+		case req.RecommendedCPUs > 0 && info.CPUs < req.RecommendedCPUs:
+			warnings = append(warnings, fmt.Sprintf("%d processor cores recommended, %d available", req.RecommendedCPUs, info.CPUs))
+		}
+	}
+	if len(failures) > 0 {
+		return warnings, fmt.Errorf("%w: %s", ErrRequirementsNotMet, strings.Join(failures, "; "))
+	}
+	return warnings, nil
+}
+
+// RequirementWarnings returns the soft requirements of config the computer
+// does not meet, see CheckSystemRequirements. Hard requirements are checked
+// before installing.
+func RequirementWarnings(config *Config) []string {
+	if config.SystemRequirements == (SystemRequirements{}) {
+		return nil
+	}
+	info, _ := SystemInfo()
+	warnings, _ := CheckSystemRequirements(config.SystemRequirements, info)
+	return warnings
+}
+
+// checkSystemRequirements fails if the computer is below the minimums of the
+// configuration and logs recommendations it falls short of
+func (i *Installer) checkSystemRequirements() error {
+	if i.config.SystemRequirements == (SystemRequirements{}) {
+		return nil
+	}
+	info, err := SystemInfo()
+	if err != nil {
+		i.context.Logger.Warn("Cannot check memory requirements", "error", err)
+	}
+	warnings, err := CheckSystemRequirements(i.config.SystemRequirements, info)
+	for _, warning := range warnings {
+		i.context.Logger.Warn("System below recommended requirements", "detail", warning)
+	}
+	return err
+}
+
+// parseMeminfo reads the total and available memory from the contents of
+// /proc/meminfo
+func parseMeminfo(r io.Reader) (total, available int64, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 2 && fields[2] == "kB" {
+			value *= 1024
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = value
+		case "MemAvailable:":
+			available = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	if total == 0 {
+		return 0, 0, errors.New("no MemTotal in meminfo")
+	}
+	return total, available, nil
+}
+
+// formatBytes formats a size such as 8589934592 as "8.0 GB"
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
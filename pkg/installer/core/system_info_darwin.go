@@ -0,0 +1,22 @@
+//go:build darwin
+// +build darwin
+
+package core
+
+import "golang.org/x/sys/unix"
+
+// systemMemory reads the total memory and the free pages through sysctl
+func systemMemory() (total, available int64, err error) {
+	memsize, err := unix.SysctlUint64("hw.memsize")
+	if err != nil {
+		return 0, 0, err
+	}
+	// Free pages understate what can be used without swapping, but are the
+	// best sysctl offers
+	if free, err := unix.SysctlUint32("vm.page_free_count"); err == nil {
+		if pageSize, err := unix.SysctlUint32("hw.pagesize"); err == nil {
+			available = int64(free) * int64(pageSize)
+		}
+	}
+	return int64(memsize), available, nil
+}
@@ -0,0 +1,22 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseMeminfo tests reading memory sizes from /proc/meminfo
+func TestParseMeminfo(t *testing.T) {
+	meminfo := "MemTotal:       16318480 kB\nMemFree:         1024000 kB\nMemAvailable:    8159240 kB\nHugePages_Total:       0\n"
+	total, available, err := parseMeminfo(strings.NewReader(meminfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 16318480*1024 || available != 8159240*1024 {
+		t.Errorf("parseMeminfo() = %d, %d", total, available)
+	}
+
+	if _, _, err := parseMeminfo(strings.NewReader("MemFree: 10 kB\n")); err == nil {
+		t.Error("parseMeminfo() without MemTotal should fail")
+	}
+}
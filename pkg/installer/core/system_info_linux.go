@@ -0,0 +1,16 @@
+//go:build linux
+// +build linux
+
+package core
+
+import "os"
+
+// systemMemory reads the total and available memory from /proc/meminfo
+func systemMemory() (total, available int64, err error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	return parseMeminfo(f)
+}
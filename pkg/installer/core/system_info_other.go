@@ -0,0 +1,11 @@
+//go:build !windows && !linux && !darwin
+// +build !windows,!linux,!darwin
+
+package core
+
+import "fmt"
+
+// systemMemory is not supported on this platform
+func systemMemory() (total, available int64, err error) {
+	return 0, 0, fmt.Errorf("memory check not supported on this platform")
+}
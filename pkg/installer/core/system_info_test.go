@@ -0,0 +1,86 @@
+package core_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+const gib = 1 << 30
+
+// TestCheckSystemRequirements tests hard and soft requirements against injected system info
+func TestCheckSystemRequirements(t *testing.T) {
+	req := core.SystemRequirements{
+		MinMemory:         4 * gib,
+		RecommendedMemory: 8 * gib,
+		MinCPUs:           2,
+		RecommendedCPUs:   4,
+	}
+
+	tests := []struct {
+		name     string
+		info     core.SystemResources
+		warnings []string
+		fail     bool
+	}{
+		{"pass", core.SystemResources{TotalMemory: 16 * gib, CPUs: 8}, nil, false},
+		{"soft memory", core.SystemResources{TotalMemory: 6 * gib, CPUs: 8},
+			[]string{"8.0 GB of memory recommended, 6.0 GB installed"}, false},
+		{"soft memory and cpus", core.SystemResources{TotalMemory: 6 * gib, CPUs: 2},
+			[]string{"8.0 GB of memory recommended, 6.0 GB installed", "4 processor cores recommended, 2 available"}, false},
+		{"hard memory", core.SystemResources{TotalMemory: 2 * gib, CPUs: 8}, nil, true},
+		{"hard cpus with soft memory", core.SystemResources{TotalMemory: 6 * gib, CPUs: 1},
+			[]string{"8.0 GB of memory recommended, 6.0 GB installed"}, true},
+		{"unknown", core.SystemResources{}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := core.CheckSystemRequirements(req, tt.info)
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("warnings = %q, want %q", warnings, tt.warnings)
+			}
+			if tt.fail != errors.Is(err, core.ErrRequirementsNotMet) {
+				t.Errorf("error = %v, want failure %v", err, tt.fail)
+			}
+		})
+	}
+}
+
+// TestSystemInfo tests that the real system info is read
+func TestSystemInfo(t *testing.T) {
+	info, err := core.SystemInfo()
+	if info.CPUs != runtime.NumCPU() {
+		t.Errorf("CPUs = %d, want %d", info.CPUs, runtime.NumCPU())
+	}
+	switch runtime.GOOS {
+	case "linux", "darwin", "windows":
+		if err != nil {
+			t.Fatalf("SystemInfo() error = %v", err)
+		}
+		if info.TotalMemory <= 0 {
+			t.Errorf("TotalMemory = %d, want the installed memory", info.TotalMemory)
+		}
+	}
+}
+
+// TestRequirementsFailInstallation tests that an unmet minimum stops the installation before anything is installed
+func TestRequirementsFailInstallation(t *testing.T) {
+	config := &core.Config{
+		AppName:            "HeavyApp",
+		InstallDir:         filepath.Join(t.TempDir(), "app"),
+		Rollback:           core.RollbackNone,
+		SystemRequirements: core.SystemRequirements{MinCPUs: 1 << 20},
+		Components:         []core.Component{markerComponent("index")},
+	}
+	err := newTestInstaller(config).ExecuteInstallation()
+	if !errors.Is(err, core.ErrRequirementsNotMet) {
+		t.Fatalf("ExecuteInstallation() error = %v, want %v", err, core.ErrRequirementsNotMet)
+	}
+	if hint := core.ErrorHint(err); hint != core.HintRequirements {
+		t.Errorf("ErrorHint() = %q, want %q", hint, core.HintRequirements)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build windows
+// +build windows
+
+package core
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// memoryStatusEx is the MEMORYSTATUSEX structure
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// systemMemory reads the total and available memory with GlobalMemoryStatusEx
+func systemMemory() (total, available int64, err error) {
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	globalMemoryStatusEx := kernel32.NewProc("GlobalMemoryStatusEx")
+
+	status := memoryStatusEx{}
+	status.Length = uint32(unsafe.Sizeof(status))
+	ret, _, err := globalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status)))
+	if ret == 0 {
+		return 0, 0, err
+	}
+	return int64(status.TotalPhys), int64(status.AvailPhys), nil
+}
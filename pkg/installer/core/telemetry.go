@@ -0,0 +1,157 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Telemetry event names recorded during installation
+const (
+	TelemetryInstallStarted     = "install_started"
+	TelemetryComponentInstalled = "component_installed"
+	TelemetryInstallCompleted   = "install_completed"
+	TelemetryInstallFailed      = "install_failed"
+	TelemetryInstallCanceled    = "install_canceled"
+)
+
+// telemetryPII lists event properties that can identify a user or machine.
+// They are dropped unless named in Config.TelemetryAllowedPII.
+var telemetryPII = map[string]bool{
+	"install_dir": true,
+	"error":       true, // Messages often contain paths
+	"username":    true,
+	"hostname":    true,
+}
+
+// TelemetrySink receives anonymous installation events
+type TelemetrySink interface {
+	RecordEvent(name string, props map[string]interface{})
+}
+
+// NopTelemetrySink discards all events
+type NopTelemetrySink struct{}
+
+// RecordEvent implements TelemetrySink
+func (NopTelemetrySink) RecordEvent(name string, props map[string]interface{}) {}
+
+// recordEvent sends an event to the configured sink if the user opted in
+func (i *Installer) recordEvent(name string, props map[string]interface{}) {
+	if !i.config.EnableTelemetry || i.config.TelemetrySink == nil {
+		return
+	}
+	event := map[string]interface{}{
+		"app_name":    i.config.AppName,
+		"app_version": i.config.Version,
+		"os":          runtime.GOOS,
+		"arch":        runtime.GOARCH,
+	}
+	for key, value := range props {
+		event[key] = value
+	}
+	i.config.TelemetrySink.RecordEvent(name, filterTelemetryPII(event, i.config.TelemetryAllowedPII))
+}
+
+// recordResult records how an installation that started at start ended
+func (i *Installer) recordResult(start time.Time, err error) {
+	props := map[string]interface{}{"duration_ms": time.Since(start).Milliseconds()}
+	switch {
+	case err == nil:
+		i.recordEvent(TelemetryInstallCompleted, props)
+	case errors.Is(err, context.Canceled) || i.runContext().Err() != nil:
+		i.recordEvent(TelemetryInstallCanceled, props)
+	default:
+		props["error"] = err.Error()
+		i.recordEvent(TelemetryInstallFailed, props)
+	}
+}
+
+// filterTelemetryPII removes personal properties that are not explicitly allowed
+func filterTelemetryPII(props map[string]interface{}, allowed []string) map[string]interface{} {
+	allow := make(map[string]bool, len(allowed))
+	for _, key := range allowed {
+		allow[key] = true
+	}
+	filtered := make(map[string]interface{}, len(props))
+	for key, value := range props {
+		if telemetryPII[key] && !allow[key] {
+			continue
+		}
+		filtered[key] = value
+	}
+	return filtered
+}
+
+// TelemetryEvent is an event as sent by HTTPTelemetrySink
+type TelemetryEvent struct {
+	Name       string                 `json:"name"`
+	Time       time.Time              `json:"time"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// HTTPTelemetrySink posts events as a JSON array to an endpoint in batches.
+// Events of a batch that fails to send are dropped rather than retried later.
+type HTTPTelemetrySink struct {
+	Endpoint  string
+	BatchSize int
+	Client    *http.Client
+
+	mu      sync.Mutex
+	pending []TelemetryEvent
+}
+
+// NewHTTPTelemetrySink creates a sink that sends every batchSize events
+// through the proxies of config. A nil config uses the proxies from the
+// environment.
+func NewHTTPTelemetrySink(config *Config, endpoint string, batchSize int) *HTTPTelemetrySink {
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+	return &HTTPTelemetrySink{
+		Endpoint:  endpoint,
+		BatchSize: batchSize,
+		Client:    HTTPClient(config),
+	}
+}
+
+// RecordEvent queues an event and sends the batch once it is full
+func (s *HTTPTelemetrySink) RecordEvent(name string, props map[string]interface{}) {
+	s.mu.Lock()
+	s.pending = append(s.pending, TelemetryEvent{Name: name, Time: time.Now().UTC(), Properties: props})
+	full := len(s.pending) >= s.BatchSize
+	s.mu.Unlock()
+	if full {
+		s.Flush()
+	}
+}
+
+// Flush sends all queued events. Call it before the installer exits.
+func (s *HTTPTelemetrySink) Flush() error {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+	resp, err := s.Client.Post(s.Endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// recordingSink collects telemetry events
+type recordingSink struct {
+	mu     sync.Mutex
+	names  []string
+	events []map[string]interface{}
+}
+
+func (s *recordingSink) RecordEvent(name string, props map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = append(s.names, name)
+	s.events = append(s.events, props)
+}
+
+// telemetryConfig returns a config with two components and telemetry enabled
+func telemetryConfig(t *testing.T, sink core.TelemetrySink, install func(ctx context.Context) error) *core.Config {
+	return &core.Config{
+		AppName:         "TelemetryApp",
+		Version:         "2.0.0",
+		InstallDir:      t.TempDir(),
+		Rollback:        core.RollbackNone,
+		EnableTelemetry: true,
+		TelemetrySink:   sink,
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Installer: func(ctx context.Context) error { return nil }},
+			{ID: "docs", Name: "Docs", Selected: true, Installer: install},
+		},
+	}
+}
+
+// TestTelemetryLifecycleEvents tests that events fire at the lifecycle points without PII
+func TestTelemetryLifecycleEvents(t *testing.T) {
+	sink := &recordingSink{}
+	config := telemetryConfig(t, sink, func(ctx context.Context) error { return nil })
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+
+	want := "install_started,component_installed,component_installed,install_completed"
+	if got := strings.Join(sink.names, ","); got != want {
+		t.Errorf("Events = %s, want %s", got, want)
+	}
+	if sink.events[2]["component"] != "docs" {
+		t.Errorf("component_installed props = %v, want component docs", sink.events[2])
+	}
+	for idx, props := range sink.events {
+		if props["app_name"] != "TelemetryApp" {
+			t.Errorf("Event %d lacks app_name: %v", idx, props)
+		}
+		if _, ok := props["install_dir"]; ok {
+			t.Errorf("Event %d contains install_dir", idx)
+		}
+	}
+}
+
+// TestTelemetryFailureExcludesPII tests that error messages are only sent when allowed
+func TestTelemetryFailureExcludesPII(t *testing.T) {
+	failing := func(ctx context.Context) error { return errors.New("cannot write /home/alice/app") }
+
+	sink := &recordingSink{}
+	config := telemetryConfig(t, sink, failing)
+	if err := newTestInstaller(config).ExecuteInstallation(); err == nil {
+		t.Fatal("ExecuteInstallation() succeeded, want error")
+	}
+	last := len(sink.names) - 1
+	if sink.names[last] != core.TelemetryInstallFailed {
+		t.Fatalf("Last event = %s, want %s", sink.names[last], core.TelemetryInstallFailed)
+	}
+	if _, ok := sink.events[last]["error"]; ok {
+		t.Errorf("install_failed contains error message by default: %v", sink.events[last])
+	}
+
+	sink = &recordingSink{}
+	config = telemetryConfig(t, sink, failing)
+	config.TelemetryAllowedPII = []string{"error"}
+	newTestInstaller(config).ExecuteInstallation()
+	if msg, _ := sink.events[len(sink.events)-1]["error"].(string); !strings.Contains(msg, "/home/alice") {
+		t.Errorf("Allowed error property missing, got %v", sink.events[len(sink.events)-1])
+	}
+}
+
+// TestTelemetryCanceledAndDisabled tests cancellation events and the opt-in gate
+func TestTelemetryCanceledAndDisabled(t *testing.T) {
+	sink := &recordingSink{}
+	config := telemetryConfig(t, sink, func(ctx context.Context) error { return ctx.Err() })
+	inst := newTestInstaller(config)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	inst.SetRunContext(ctx)
+	inst.ExecuteInstallation()
+	if last := sink.names[len(sink.names)-1]; last != core.TelemetryInstallCanceled {
+		t.Errorf("Last event = %s, want %s", last, core.TelemetryInstallCanceled)
+	}
+
+	sink = &recordingSink{}
+	config = telemetryConfig(t, sink, func(ctx context.Context) error { return nil })
+	config.EnableTelemetry = false
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	if len(sink.names) != 0 {
+		t.Errorf("Events recorded without opt-in: %v", sink.names)
+	}
+}
+
+// TestHTTPTelemetrySinkBatching tests that events are posted in batches
+func TestHTTPTelemetrySinkBatching(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]core.TelemetryEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []core.TelemetryEvent
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("Invalid batch: %v", err)
+		}
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sink := core.NewHTTPTelemetrySink(nil, server.URL, 2)
+	sink.RecordEvent("a", nil)
+	sink.RecordEvent("b", map[string]interface{}{"component": "core"})
+	sink.RecordEvent("c", nil)
+	if len(batches) != 1 || len(batches[0]) != 2 || batches[0][1].Properties["component"] != "core" {
+		t.Fatalf("After 3 events batches = %+v, want one batch of 2", batches)
+	}
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(batches) != 2 || batches[1][0].Name != "c" {
+		t.Errorf("After Flush batches = %+v, want second batch with c", batches)
+	}
+}
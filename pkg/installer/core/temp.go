@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Downloads are staged in Config.TempDir and only moved to their destination
+// once complete and verified. Every staged item gets a directory of its own,
+// removed when the item is done with, whether it succeeded or not.
+
+// stagingDir returns Config.TempDir, or the system temp directory if unset
+func (c *Config) stagingDir() string {
+	if c != nil && c.TempDir != "" {
+		return c.TempDir
+	}
+	return os.TempDir()
+}
+
+// newStagingDir creates a directory for one staged item in the temp
+// directory; pattern is passed to os.MkdirTemp. The caller removes it.
+func (c *Config) newStagingDir(pattern string) (string, error) {
+	dir := c.stagingDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	return os.MkdirTemp(dir, pattern)
+}
+
+// checkTempSpace fails with ErrInsufficientSpace if the temp directory cannot
+// hold the largest staged payload of components, the most that is staged at
+// a time. Payloads that are not Staged, such as embedded files, never pass
+// through the temp directory.
+func (c *Config) checkTempSpace(components []Component) error {
+	var largest int64
+	for _, component := range components {
+		if component.Staged {
+			largest = max(largest, component.Size)
+		}
+	}
+	if largest == 0 {
+		return nil
+	}
+	dir := c.stagingDir()
+	available, err := AvailableSpace(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check space in temp directory %s: %w", dir, err)
+	}
+	if available < largest {
+		return fmt.Errorf("%w in temp directory %s: required %d bytes, available %d bytes",
+			ErrInsufficientSpace, dir, largest, available)
+	}
+	return nil
+}
+
+// moveStaged moves a staged file to dest, copying it if they are on
+// different volumes
+func moveStaged(staged, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(staged, dest); err == nil {
+		return nil
+	}
+	return copyLocalFile(staged, dest)
+}
@@ -0,0 +1,138 @@
+package core_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// assertEmptyDir fails if dir contains anything
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		t.Errorf("temp directory still contains %s", entry.Name())
+	}
+}
+
+// TestDownloadStagedInTempDir tests that a download is staged in TempDir and
+// leaves nothing there once it is in place
+func TestDownloadStagedInTempDir(t *testing.T) {
+	var staged []string
+	tempDir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entries, _ := os.ReadDir(tempDir)
+		for _, entry := range entries {
+			staged = append(staged, entry.Name())
+		}
+		w.Write([]byte("payload"))
+	}))
+	t.Cleanup(server.Close)
+
+	config := &core.Config{TempDir: tempDir}
+	dest := filepath.Join(t.TempDir(), "payload.bin")
+	if err := core.DownloadComponent(context.Background(), config, server.URL, sha256Hex("payload"), dest); err != nil {
+		t.Fatalf("DownloadComponent() error = %v", err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "payload" {
+		t.Errorf("dest = %q, want payload", data)
+	}
+	if len(staged) != 1 {
+		t.Errorf("staged items during the download = %q, want one", staged)
+	}
+	assertEmptyDir(t, tempDir)
+}
+
+// TestDownloadFailureCleansTempDir tests that failed downloads leave neither
+// staged files nor a destination behind
+func TestDownloadFailureCleansTempDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"checksum mismatch", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("tampered")) }},
+		{"server error", func(w http.ResponseWriter, r *http.Request) { http.Error(w, "gone", http.StatusNotFound) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			t.Cleanup(server.Close)
+			config := &core.Config{TempDir: t.TempDir()}
+			dest := filepath.Join(t.TempDir(), "payload.bin")
+
+			if err := core.DownloadComponent(context.Background(), config, server.URL, sha256Hex("payload"), dest); err == nil {
+				t.Fatal("DownloadComponent() succeeded")
+			}
+			if _, err := os.Stat(dest); !os.IsNotExist(err) {
+				t.Error("failed download left at dest")
+			}
+			assertEmptyDir(t, config.TempDir)
+		})
+	}
+}
+
+// TestDownloadZipSourceCleansTempDir tests that a downloaded payload archive
+// is removed from TempDir when the source is closed
+func TestDownloadZipSourceCleansTempDir(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	if entry, err := zw.Create("bin/app"); err == nil {
+		entry.Write([]byte("app"))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive.Bytes())
+	}))
+	t.Cleanup(server.Close)
+
+	config := &core.Config{TempDir: t.TempDir()}
+	source, err := core.DownloadZipSource(context.Background(), config, server.URL)
+	if err != nil {
+		t.Fatalf("DownloadZipSource() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(config.TempDir); len(entries) != 1 {
+		t.Errorf("staged items = %d, want the archive", len(entries))
+	}
+	if err := source.Close(); err != nil {
+		t.Fatal(err)
+	}
+	assertEmptyDir(t, config.TempDir)
+}
+
+// TestTempDirSpaceCheck tests that an installation whose largest staged
+// payload does not fit into the temp directory is refused before anything
+// changes, and that payloads copied in place are not counted
+func TestTempDirSpaceCheck(t *testing.T) {
+	config := &core.Config{
+		AppName:    "TempApp",
+		InstallDir: filepath.Join(t.TempDir(), "app"),
+		TempDir:    t.TempDir(),
+		Components: []core.Component{{ID: "core", Name: "Core", Required: true, Size: 1 << 62, InstalledSize: 1}},
+	}
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v for a payload that is not staged", err)
+	}
+
+	config.InstallDir = filepath.Join(t.TempDir(), "app")
+	config.Components[0].Staged = true
+	err := newTestInstaller(config).ExecuteInstallation()
+	if !errors.Is(err, core.ErrInsufficientSpace) {
+		t.Fatalf("ExecuteInstallation() error = %v, want ErrInsufficientSpace", err)
+	}
+	if _, err := os.Stat(config.InstallDir); !os.IsNotExist(err) {
+		t.Error("install directory created despite the failed space check")
+	}
+}
@@ -0,0 +1,71 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// failingUI cannot start, like a GUI without WebView2
+type failingUI struct{ testUI }
+
+func (failingUI) Initialize(*core.Context) error { return errors.New("WebView2 runtime not found") }
+
+// recordingUI notes that it ran
+type recordingUI struct {
+	testUI
+	ran *bool
+}
+
+func (u recordingUI) Run() error { *u.ran = true; return nil }
+
+// useFallbackFactory registers a factory whose GUI fails and whose CLI records its run
+func useFallbackFactory(t *testing.T) (created *[]core.Mode, cliRan *bool) {
+	t.Helper()
+	created, cliRan = new([]core.Mode), new(bool)
+	core.RegisterUIFactory(func(mode core.Mode) (core.UI, error) {
+		*created = append(*created, mode)
+		if mode == core.ModeCLI {
+			return recordingUI{ran: cliRan}, nil
+		}
+		return failingUI{}, nil
+	})
+	t.Cleanup(func() { core.RegisterUIFactory(nil) })
+	return created, cliRan
+}
+
+// TestUIFallbackToCLI tests that a GUI that cannot start is replaced by the CLI
+func TestUIFallbackToCLI(t *testing.T) {
+	useMocks(t)
+	created, cliRan := useFallbackFactory(t)
+	config := &core.Config{AppName: "FallbackApp", InstallDir: t.TempDir(), Mode: core.ModeGUI}
+
+	if err := core.New(config).Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v, want the CLI fallback", err)
+	}
+	if !*cliRan {
+		t.Error("CLI did not run after the GUI failed")
+	}
+	if len(*created) != 2 || (*created)[0] != core.ModeGUI || (*created)[1] != core.ModeCLI {
+		t.Errorf("UIs created = %v, want GUI then CLI", *created)
+	}
+	if config.Mode != core.ModeCLI {
+		t.Errorf("Mode = %v, want the CLI mode recorded", config.Mode)
+	}
+}
+
+// TestUIFallbackDisabled tests that the GUI error is returned with DisableUIFallback
+func TestUIFallbackDisabled(t *testing.T) {
+	useMocks(t)
+	created, cliRan := useFallbackFactory(t)
+	config := &core.Config{AppName: "FallbackApp", InstallDir: t.TempDir(), Mode: core.ModeGUI, DisableUIFallback: true}
+
+	if err := core.New(config).Run(context.Background()); err == nil {
+		t.Fatal("Run() succeeded, want the GUI error")
+	}
+	if *cliRan || len(*created) != 1 {
+		t.Errorf("UIs created = %v, want no fallback", *created)
+	}
+}
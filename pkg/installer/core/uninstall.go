@@ -0,0 +1,319 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RemovalFile is an installed file that an uninstall deletes
+type RemovalFile struct {
+	Path      string // Relative to the install directory
+	Component string
+	Modified  bool // Changed since installation, possibly user data
+	Missing   bool // Already deleted
+	Preserved bool // Matches Config.PreserveOnUninstall
+}
+
+// RemovalPlan lists everything an uninstall removes
+type RemovalPlan struct {
+	InstallDir   string
+	Components   []string
+	Files        []RemovalFile
+	PathEntries  []string
+	RegistryKeys []string
+	Shortcuts    []string
+	Services     []string
+	UserData     []string // Files in the install directory that were not installed, relative paths
+	Warnings     []string
+}
+
+// UninstallOptions are the user's choices for an uninstall
+type UninstallOptions struct {
+	KeepModified   bool // Leave files that changed since installation in place
+	RemoveUserData bool // Also remove preserved paths and files that were not installed
+}
+
+// ModifiedFiles returns the planned files that changed since installation
+func (p *RemovalPlan) ModifiedFiles() []RemovalFile {
+	var modified []RemovalFile
+	for _, f := range p.Files {
+		if f.Modified {
+			modified = append(modified, f)
+		}
+	}
+	return modified
+}
+
+// Partition splits the files of the plan into those Uninstall removes and
+// those it keeps with opts, both relative to the install directory
+func (p *RemovalPlan) Partition(opts UninstallOptions) (remove, keep []string) {
+	for _, f := range p.Files {
+		switch {
+		case f.Missing:
+		case (f.Modified && opts.KeepModified) || (f.Preserved && !opts.RemoveUserData):
+			keep = append(keep, f.Path)
+		default:
+			remove = append(remove, f.Path)
+		}
+	}
+	if opts.RemoveUserData {
+		remove = append(remove, p.UserData...)
+	} else {
+		keep = append(keep, p.UserData...)
+	}
+	return remove, keep
+}
+
+// Uninstaller removes an installation recorded in its install manifest
+type Uninstaller struct {
+	config   *Config
+	manifest *Manifest
+	logger   Logger
+	platform PlatformInstaller
+}
+
+// NewUninstaller loads the manifest of the installation in config.InstallDir
+func NewUninstaller(config *Config) (*Uninstaller, error) {
+	manifest, err := LoadManifest(config.InstallDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Uninstaller{
+		config:   config,
+		manifest: manifest,
+		logger:   NewLogger(config.LogLevel, config.LogFile),
+		platform: CreatePlatformInstaller(config),
+	}, nil
+}
+
+// SetLogger replaces the uninstaller's logger
+func (u *Uninstaller) SetLogger(logger Logger) {
+	u.logger = logger
+}
+
+// Manifest returns the manifest of the installation being removed
+func (u *Uninstaller) Manifest() *Manifest {
+	return u.manifest
+}
+
+// PreviewRemoval returns what Uninstall would remove without changing anything.
+// Files whose checksum no longer matches the manifest are flagged as modified.
+func (u *Uninstaller) PreviewRemoval() (*RemovalPlan, error) {
+	m := u.manifest
+	installed := make(map[string]bool)
+	plan := &RemovalPlan{
+		InstallDir:   m.InstallDir,
+		PathEntries:  append([]string{}, m.PathEntries...),
+		RegistryKeys: append([]string{}, m.RegistryKeys...),
+		Shortcuts:    append([]string{}, m.Shortcuts...),
+		Services:     append([]string{}, m.Services...),
+	}
+
+	for _, c := range m.Components {
+		plan.Components = append(plan.Components, c.ID)
+		for _, f := range c.Files {
+			entry := RemovalFile{Path: f.Path, Component: c.ID}
+			sum, err := fileChecksum(filepath.Join(m.InstallDir, filepath.FromSlash(f.Path)))
+			switch {
+			case os.IsNotExist(err):
+				entry.Missing = true
+			case err != nil:
+				return nil, fmt.Errorf("failed to check %s: %w", f.Path, err)
+			case sum != f.SHA256:
+				entry.Modified = true
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s was modified after installation and may contain user data", f.Path))
+			}
+			entry.Preserved = u.isPreserved(f.Path)
+			plan.Files = append(plan.Files, entry)
+			installed[f.Path] = true
+		}
+	}
+	sort.Slice(plan.Files, func(a, b int) bool { return plan.Files[a].Path < plan.Files[b].Path })
+
+	userData, err := findUserData(m.InstallDir, installed)
+	if err != nil {
+		return nil, err
+	}
+	plan.UserData = userData
+	return plan, nil
+}
+
+// isPreserved reports whether a path relative to the install directory, or
+// one of its parent directories, matches a PreserveOnUninstall pattern
+func (u *Uninstaller) isPreserved(rel string) bool {
+	for p := rel; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		for _, pattern := range u.config.PreserveOnUninstall {
+			if ok, _ := path.Match(filepath.ToSlash(pattern), p); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findUserData lists files below installDir that the installation did not create
+func findUserData(installDir string, installed map[string]bool) ([]string, error) {
+	var userData []string
+	err := filepath.WalkDir(installDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(installDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != ManifestFileName && rel != PortableConfigFile && !installed[rel] {
+			userData = append(userData, rel)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to scan install directory: %w", err)
+	}
+	return userData, nil
+}
+
+// Uninstall carries out plan. Only installed files are removed, except for
+// preserved paths; user data is removed only when opts.RemoveUserData is set.
+func (u *Uninstaller) Uninstall(plan *RemovalPlan, opts UninstallOptions) error {
+	if u.config.DryRun {
+		u.logger.Info("Dry run, nothing removed", "files", len(plan.Files))
+		return nil
+	}
+
+	u.removeServices(plan.Services)
+
+	// Run post-uninstall actions and component uninstallers, last installed first
+	byID := make(map[string]Component, len(u.config.Components))
+	for _, c := range u.config.Components {
+		byID[c.ID] = c
+	}
+	ctx := u.componentContext()
+	for idx := len(plan.Components) - 1; idx >= 0; idx-- {
+		c, ok := byID[plan.Components[idx]]
+		if !ok {
+			continue
+		}
+		if c.PostUninstall != nil {
+			u.logger.Info("Running post-uninstall actions", "component", c.ID)
+			if err := c.PostUninstall(ctx, plan.InstallDir); err != nil {
+				return fmt.Errorf("post-uninstall actions failed for %s: %w", c.ID, err)
+			}
+		}
+		if c.Uninstaller == nil {
+			continue
+		}
+		u.logger.Info("Removing component", "id", c.ID)
+		if err := c.Uninstaller(ctx); err != nil {
+			return fmt.Errorf("failed to remove component %s: %w", c.ID, err)
+		}
+	}
+
+	remove, keep := plan.Partition(opts)
+	for _, rel := range keep {
+		u.logger.Info("Keeping file", "path", rel)
+	}
+	for _, rel := range remove {
+		if err := removeInstalledFile(plan.InstallDir, rel); err != nil {
+			return err
+		}
+	}
+
+	for _, shortcut := range plan.Shortcuts {
+		if err := os.Remove(shortcut); err != nil && !os.IsNotExist(err) {
+			u.logger.Warn("Failed to remove shortcut", "path", shortcut, "error", err)
+		}
+	}
+	for _, dir := range plan.PathEntries {
+		if u.platform == nil {
+			break
+		}
+		if err := u.platform.RemoveFromPath(dir, u.manifest.PathSystem); err != nil {
+			u.logger.Warn("Failed to remove PATH entry", "dir", dir, "error", err)
+		}
+	}
+	for _, key := range plan.RegistryKeys {
+		if err := deleteRegistryKey(key); err != nil {
+			u.logger.Warn("Failed to remove registry key", "key", key, "error", err)
+		}
+	}
+
+	if err := os.Remove(PortableConfigPath(plan.InstallDir)); err != nil && !os.IsNotExist(err) {
+		u.logger.Warn("Failed to remove portable configuration", "error", err)
+	}
+	if err := os.Remove(EnvFilePath(plan.InstallDir)); err != nil && !os.IsNotExist(err) {
+		u.logger.Warn("Failed to remove environment file", "error", err)
+	}
+	if err := os.Remove(ManifestPath(plan.InstallDir)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove install manifest: %w", err)
+	}
+	removeEmptyDirs(plan.InstallDir)
+
+	u.logger.Info("Uninstall complete", "components", strings.Join(plan.Components, ","), "kept", len(keep))
+	return nil
+}
+
+// removeInstalledFile deletes a file given relative to the install directory
+func removeInstalledFile(installDir, rel string) error {
+	if err := os.Remove(filepath.Join(installDir, filepath.FromSlash(rel))); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", rel, err)
+	}
+	return nil
+}
+
+// removeServices stops and removes services recorded in the manifest
+func (u *Uninstaller) removeServices(services []string) {
+	if len(services) == 0 {
+		return
+	}
+	manager, err := GetServiceManager()
+	if err != nil {
+		u.logger.Warn("Cannot remove services", "error", err)
+		return
+	}
+	for _, name := range services {
+		manager.Stop(name)
+		if err := manager.Uninstall(name); err != nil {
+			u.logger.Warn("Failed to remove service", "name", name, "error", err)
+		}
+	}
+}
+
+// componentContext provides component uninstallers with the same values as
+// installers. The feature flags are those the installation was made with;
+// manifests that predate them fall back to the configuration's.
+func (u *Uninstaller) componentContext() context.Context {
+	ctx := context.WithValue(context.Background(), contextKey("logger"), u.logger)
+	ctx = context.WithValue(ctx, contextKey("config"), u.config)
+	ctx = context.WithValue(ctx, contextKey("platform"), u.platform)
+	ctx = context.WithValue(ctx, contextKey("assets"), u.config.PayloadSource())
+	features := u.manifest.Features
+	if features == nil {
+		features = u.config.Features
+	}
+	return withFeatures(ctx, features)
+}
+
+// removeEmptyDirs deletes empty directories below and including root
+func removeEmptyDirs(root string) {
+	var dirs []string
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	// Deepest first, so parents become empty before they are visited
+	for idx := len(dirs) - 1; idx >= 0; idx-- {
+		os.Remove(dirs[idx])
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+// +build !windows
+
+package core
+
+// uninstallRegistryKey returns the Add/Remove Programs key of an application;
+// there is none outside Windows
+func uninstallRegistryKey(appName string) string {
+	return ""
+}
+
+// deleteRegistryKey is a no-op outside Windows
+func deleteRegistryKey(path string) error {
+	return nil
+}
@@ -0,0 +1,169 @@
+package core_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// uninstallFixture installs two components through the installer so that a manifest is written
+func uninstallFixture(t *testing.T) *core.Config {
+	t.Helper()
+	installDir := t.TempDir()
+	write := func(file, content string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			path := filepath.Join(installDir, file)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			return os.WriteFile(path, []byte(content), 0644)
+		}
+	}
+	config := &core.Config{
+		AppName:    "UninstallApp",
+		Version:    "1.0.0",
+		InstallDir: installDir,
+		Rollback:   core.RollbackNone,
+		Components: []core.Component{
+			{ID: "core", Name: "Core", Required: true, Files: []string{"app.bin", "app.conf"}, Installer: func(ctx context.Context) error {
+				if err := write("app.bin", "binary")(ctx); err != nil {
+					return err
+				}
+				return write("app.conf", "setting=default")(ctx)
+			}},
+			{ID: "docs", Name: "Docs", Selected: true, Files: []string{"docs/guide.txt"}, Installer: write("docs/guide.txt", "guide")},
+		},
+	}
+	if err := newTestInstaller(config).ExecuteInstallation(); err != nil {
+		t.Fatalf("ExecuteInstallation() error = %v", err)
+	}
+	return config
+}
+
+// TestPreviewRemovalFlagsModifiedFiles tests that files changed after installation are flagged
+func TestPreviewRemovalFlagsModifiedFiles(t *testing.T) {
+	config := uninstallFixture(t)
+	if err := os.WriteFile(filepath.Join(config.InstallDir, "app.conf"), []byte("setting=custom"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(config.InstallDir, "docs", "guide.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := core.NewUninstaller(config)
+	if err != nil {
+		t.Fatalf("NewUninstaller() error = %v", err)
+	}
+	plan, err := u.PreviewRemoval()
+	if err != nil {
+		t.Fatalf("PreviewRemoval() error = %v", err)
+	}
+
+	if len(plan.Files) != 3 {
+		t.Fatalf("Expected 3 planned files, got %+v", plan.Files)
+	}
+	modified := plan.ModifiedFiles()
+	if len(modified) != 1 || modified[0].Path != "app.conf" || modified[0].Component != "core" {
+		t.Errorf("Expected app.conf to be flagged as modified, got %+v", modified)
+	}
+	if len(plan.Warnings) != 1 {
+		t.Errorf("Expected one warning, got %v", plan.Warnings)
+	}
+	for _, f := range plan.Files {
+		if f.Path == "docs/guide.txt" && !f.Missing {
+			t.Error("Deleted file should be reported as missing")
+		}
+	}
+
+	// Previewing changes nothing
+	if _, err := os.Stat(filepath.Join(config.InstallDir, "app.bin")); err != nil {
+		t.Error("PreviewRemoval must not remove files")
+	}
+}
+
+// TestUninstallKeepModified tests that modified files can be kept while installed files are removed
+func TestUninstallKeepModified(t *testing.T) {
+	config := uninstallFixture(t)
+	conf := filepath.Join(config.InstallDir, "app.conf")
+	if err := os.WriteFile(conf, []byte("setting=custom"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := core.NewUninstaller(config)
+	if err != nil {
+		t.Fatalf("NewUninstaller() error = %v", err)
+	}
+	u.SetLogger(core.NewLogger("error", ""))
+	plan, err := u.PreviewRemoval()
+	if err != nil {
+		t.Fatalf("PreviewRemoval() error = %v", err)
+	}
+	if err := u.Uninstall(plan, core.UninstallOptions{KeepModified: true}); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+
+	if _, err := os.Stat(conf); err != nil {
+		t.Error("Modified file should be kept")
+	}
+	for _, path := range []string{"app.bin", "docs", core.ManifestFileName} {
+		if _, err := os.Stat(filepath.Join(config.InstallDir, path)); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", path)
+		}
+	}
+}
+
+// TestUninstallPreservesUserData tests that preserved paths and files the installer
+// did not create survive uninstall unless removal is confirmed
+func TestUninstallPreservesUserData(t *testing.T) {
+	for _, removeUserData := range []bool{false, true} {
+		config := uninstallFixture(t)
+		config.PreserveOnUninstall = []string{"app.conf", "data"}
+
+		userFiles := []string{filepath.Join("data", "db.sqlite"), "notes.txt"}
+		for _, name := range userFiles {
+			path := filepath.Join(config.InstallDir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte("user"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		u, err := core.NewUninstaller(config)
+		if err != nil {
+			t.Fatalf("NewUninstaller() error = %v", err)
+		}
+		u.SetLogger(core.NewLogger("error", ""))
+		plan, err := u.PreviewRemoval()
+		if err != nil {
+			t.Fatalf("PreviewRemoval() error = %v", err)
+		}
+		if got := strings.Join(plan.UserData, ","); got != "data/db.sqlite,notes.txt" {
+			t.Errorf("UserData = %s", got)
+		}
+
+		if err := u.Uninstall(plan, core.UninstallOptions{RemoveUserData: removeUserData}); err != nil {
+			t.Fatalf("Uninstall() error = %v", err)
+		}
+
+		exists := func(name string) bool {
+			_, err := os.Stat(filepath.Join(config.InstallDir, name))
+			return err == nil
+		}
+		for _, name := range append(userFiles, "app.conf") {
+			if exists(name) == removeUserData {
+				t.Errorf("RemoveUserData=%v: %s exists = %v", removeUserData, name, exists(name))
+			}
+		}
+		for _, name := range []string{"app.bin", filepath.Join("docs", "guide.txt"), core.ManifestFileName} {
+			if exists(name) {
+				t.Errorf("RemoveUserData=%v: installed file %s should be removed", removeUserData, name)
+			}
+		}
+	}
+}
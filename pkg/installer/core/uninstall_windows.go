@@ -0,0 +1,30 @@
+//go:build windows
+// +build windows
+
+package core
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// uninstallRegistryKey returns the Add/Remove Programs key written by RegisterWithOS
+func uninstallRegistryKey(appName string) string {
+	return fmt.Sprintf(`SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall\%s`, appName)
+}
+
+// deleteRegistryKey removes a key from HKLM and from HKCU, where RegisterWithOS
+// falls back to when it lacks rights
+func deleteRegistryKey(path string) error {
+	var firstErr error
+	for _, root := range []registry.Key{registry.LOCAL_MACHINE, registry.CURRENT_USER} {
+		if err := registry.DeleteKey(root, path); err != nil && err != registry.ErrNotExist && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return fmt.Errorf("failed to delete registry key %s: %w", path, firstErr)
+	}
+	return nil
+}
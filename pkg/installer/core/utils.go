@@ -19,8 +19,8 @@ func CheckDiskSpace(path string, required int64) error {
 	}
 
 	if availableSpace < required {
-		return fmt.Errorf("insufficient disk space: required %d bytes, available %d bytes",
-			required, availableSpace)
+		return fmt.Errorf("%w: required %d bytes, available %d bytes",
+			ErrInsufficientSpace, required, availableSpace)
 	}
 
 	return nil
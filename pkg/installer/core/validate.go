@@ -0,0 +1,66 @@
+package core
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// ValidateComponents checks the component definitions for contradictions.
+// Required components that are not selected are selected and reported as
+// warnings, or rejected when config.StrictComponents is set, and so are
+// selection presets naming unknown components. Components and files for
+// other platforms are removed first, see ForPlatform; a required component
+// that would be removed although it is offered on this platform is an error,
+// see CheckPlatform. A component ordered before one of its dependencies is
+// an error as well, see InstallOrder, and so is a required component that a
+// portable installation leaves out, see CheckPortable. Finally the optional components are
+// selected as config.DefaultSelection says.
+func ValidateComponents(config *Config) (warnings []string, err error) {
+	known := make(map[string]bool, len(config.Components))
+	for _, c := range config.Components {
+		known[c.ID] = true
+	}
+	for _, name := range PresetNames(config.SelectionPresets) {
+		for _, id := range config.SelectionPresets[name] {
+			if tag, ok := strings.CutPrefix(id, TagPrefix); ok {
+				if len(TaggedIDs(config.Components, tag)) == 0 {
+					warnings = append(warnings, fmt.Sprintf("selection preset %s names tag %s, which no component carries", name, tag))
+				}
+				continue
+			}
+			if known[id] {
+				continue
+			}
+			if config.StrictComponents {
+				return warnings, fmt.Errorf("selection preset %s names unknown component %s", name, id)
+			}
+			warnings = append(warnings, fmt.Sprintf("selection preset %s names unknown component %s; ignoring it", name, id))
+		}
+	}
+
+	if err := checkInstallOrder(config.Components); err != nil {
+		return warnings, err
+	}
+
+	if err := CheckPortable(config); err != nil {
+		return warnings, err
+	}
+
+	if err := CheckPlatform(config.Components, runtime.GOOS, runtime.GOARCH); err != nil {
+		return warnings, err
+	}
+	config.Components = ForPlatform(config.Components, runtime.GOOS, runtime.GOARCH)
+	for idx := range config.Components {
+		c := &config.Components[idx]
+		if c.Required && !c.Selected {
+			if config.StrictComponents {
+				return warnings, fmt.Errorf("component %s is required but not selected", c.ID)
+			}
+			c.Selected = true
+			warnings = append(warnings, fmt.Sprintf("component %s is required but was not selected; selecting it", c.ID))
+		}
+	}
+	ApplyDefaultSelection(config.Components, config.DefaultSelection)
+	return warnings, nil
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WebView2BootstrapperURL is the evergreen bootstrapper, which downloads and
+// installs the current WebView2 runtime
+const WebView2BootstrapperURL = "https://go.microsoft.com/fwlink/p/?LinkId=2124703"
+
+// webView2ClientID is the EdgeUpdate client ID of the WebView2 runtime
+const webView2ClientID = "{F3017226-FE2A-4295-8BDF-00C3A9A7E4C5}"
+
+// webView2Location is a registry key that records the runtime version
+type webView2Location struct {
+	PerUser bool // HKCU instead of HKLM
+	Key     string
+}
+
+// webView2Locations are the keys the runtime installers write, in the order
+// Microsoft documents them
+var webView2Locations = []webView2Location{
+	{Key: `SOFTWARE\WOW6432Node\Microsoft\EdgeUpdate\Clients\` + webView2ClientID},
+	{Key: `SOFTWARE\Microsoft\EdgeUpdate\Clients\` + webView2ClientID},
+	{PerUser: true, Key: `Software\Microsoft\EdgeUpdate\Clients\` + webView2ClientID},
+}
+
+// webView2Runtime probes for the WebView2 runtime and installs it
+type webView2Runtime struct {
+	// version returns the pv value of a location; "" when the key is missing
+	version func(loc webView2Location) (string, error)
+	// fileExists reports whether a fixed-version runtime file is present
+	fileExists func(path string) bool
+	// ask offers to install the runtime when Config.ConfirmWebView2Install is nil
+	ask func(appName string) bool
+	// setupURL is where the bootstrapper is downloaded from
+	setupURL string
+	// bootstrap runs the downloaded bootstrapper
+	bootstrap func(path string) error
+}
+
+// DetectWebView2 reports whether the WebView2 runtime the native GUI needs is
+// installed. It is always false outside Windows.
+func DetectWebView2() (bool, error) {
+	return detectWebView2(webView2Backend)
+}
+
+func detectWebView2(rt *webView2Runtime) (bool, error) {
+	if rt == nil {
+		return false, nil
+	}
+
+	// A fixed-version runtime shipped with the application takes precedence
+	if folder := os.Getenv("WEBVIEW2_BROWSER_EXECUTABLE_FOLDER"); folder != "" {
+		if rt.fileExists(filepath.Join(folder, "msedgewebview2.exe")) {
+			return true, nil
+		}
+	}
+
+	var firstErr error
+	for _, loc := range webView2Locations {
+		version, err := rt.version(loc)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to read WebView2 version: %w", err)
+			}
+			continue
+		}
+		// The runtime leaves 0.0.0.0 behind when it is uninstalled
+		if version != "" && version != "0.0.0.0" {
+			return true, nil
+		}
+	}
+	return false, firstErr
+}
+
+// InstallWebView2 downloads the evergreen bootstrapper with the proxy
+// settings of config and runs it
+func InstallWebView2(ctx context.Context, config *Config) error {
+	return installWebView2(ctx, config, webView2Backend)
+}
+
+func installWebView2(ctx context.Context, config *Config, rt *webView2Runtime) error {
+	if rt == nil {
+		return fmt.Errorf("WebView2 is only available on Windows")
+	}
+	dir, err := config.newStagingDir("setupkit-webview2-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	setup := filepath.Join(dir, "MicrosoftEdgeWebview2Setup.exe")
+	if err := Download(ctx, config, rt.setupURL, setup); err != nil {
+		return fmt.Errorf("failed to download the WebView2 runtime: %w", err)
+	}
+	if err := rt.bootstrap(setup); err != nil {
+		return fmt.Errorf("failed to install the WebView2 runtime: %w", err)
+	}
+	if found, err := detectWebView2(rt); !found {
+		return fmt.Errorf("WebView2 runtime still missing after installation: %v", err)
+	}
+	return nil
+}
+
+// prepareWebView2 runs before the native GUI starts. When the runtime is
+// missing it offers to install it; if the user declines or the installation
+// fails, the browser UI is used instead unless DisableUIFallback is set.
+func (i *Installer) prepareWebView2(rt *webView2Runtime) {
+	if rt == nil || i.config.Mode != ModeGUI {
+		return
+	}
+	found, err := detectWebView2(rt)
+	if found {
+		return
+	}
+	if err != nil {
+		i.context.Logger.Warn("WebView2 detection failed", "error", err)
+	}
+
+	ask := i.config.ConfirmWebView2Install
+	if ask == nil {
+		ask = func() bool { return rt.ask(i.config.AppName) }
+	}
+	if ask() {
+		err := installWebView2(i.runContext(), i.config, rt)
+		if err == nil {
+			i.context.Logger.Info("WebView2 runtime installed")
+			return
+		}
+		i.context.Logger.Warn("WebView2 installation failed", "error", err)
+	}
+
+	if !i.config.DisableUIFallback {
+		i.context.Logger.Warn("WebView2 runtime missing, using the browser UI")
+		i.config.Mode = ModeBrowser
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+// +build !windows
+
+package core
+
+// webView2Backend is nil where WebView2 does not exist
+var webView2Backend *webView2Runtime
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+// fakeWebView2 is a runtime whose registry holds versions and whose
+// bootstrapper installs the given version
+type fakeWebView2 struct {
+	versions  map[webView2Location]string
+	readErr   error
+	files     map[string]bool
+	asked     int
+	accept    bool
+	installed string // Version the bootstrapper writes; "" fails the installation
+}
+
+func (f *fakeWebView2) runtime(setupURL string) *webView2Runtime {
+	return &webView2Runtime{
+		version: func(loc webView2Location) (string, error) {
+			if f.readErr != nil {
+				return "", f.readErr
+			}
+			return f.versions[loc], nil
+		},
+		fileExists: func(path string) bool { return f.files[path] },
+		ask:        func(string) bool { f.asked++; return f.accept },
+		setupURL:   setupURL,
+		bootstrap: func(string) error {
+			if f.installed == "" {
+				return errors.New("exit status 1")
+			}
+			f.versions = map[webView2Location]string{webView2Locations[0]: f.installed}
+			return nil
+		},
+	}
+}
+
+// TestDetectWebView2 tests the registry and file probes of the runtime detection
+func TestDetectWebView2(t *testing.T) {
+	fixed := filepath.Join(t.TempDir(), "runtime")
+	tests := []struct {
+		name    string
+		fake    fakeWebView2
+		env     string
+		want    bool
+		wantErr bool
+	}{
+		{name: "machine-wide", fake: fakeWebView2{versions: map[webView2Location]string{webView2Locations[0]: "120.0.2210.91"}}, want: true},
+		{name: "64-bit key", fake: fakeWebView2{versions: map[webView2Location]string{webView2Locations[1]: "120.0.2210.91"}}, want: true},
+		{name: "per-user", fake: fakeWebView2{versions: map[webView2Location]string{webView2Locations[2]: "120.0.2210.91"}}, want: true},
+		{name: "uninstalled", fake: fakeWebView2{versions: map[webView2Location]string{webView2Locations[0]: "0.0.0.0"}}},
+		{name: "missing"},
+		{name: "fixed version", env: fixed, fake: fakeWebView2{files: map[string]bool{filepath.Join(fixed, "msedgewebview2.exe"): true}}, want: true},
+		{name: "empty fixed folder", env: fixed},
+		{name: "registry error", fake: fakeWebView2{readErr: errors.New("access denied")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WEBVIEW2_BROWSER_EXECUTABLE_FOLDER", tt.env)
+			got, err := detectWebView2(tt.fake.runtime(""))
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("detectWebView2() = %v, %v; want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	if found, err := detectWebView2(nil); found || err != nil {
+		t.Errorf("detectWebView2(nil) = %v, %v; want false outside Windows", found, err)
+	}
+}
+
+// TestPrepareWebView2 tests the offer to install the runtime before the GUI starts
+func TestPrepareWebView2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("bootstrapper"))
+	}))
+	defer server.Close()
+	t.Setenv("WEBVIEW2_BROWSER_EXECUTABLE_FOLDER", "")
+
+	tests := []struct {
+		name      string
+		mode      Mode
+		fallback  bool
+		fake      fakeWebView2
+		wantMode  Mode
+		wantAsked int
+	}{
+		{name: "installed", mode: ModeGUI, fallback: true, fake: fakeWebView2{versions: map[webView2Location]string{webView2Locations[1]: "120.0.2210.91"}}, wantMode: ModeGUI},
+		{name: "declined", mode: ModeGUI, fallback: true, wantMode: ModeBrowser, wantAsked: 1},
+		{name: "bootstrapped", mode: ModeGUI, fallback: true, fake: fakeWebView2{accept: true, installed: "121.0.2277.83"}, wantMode: ModeGUI, wantAsked: 1},
+		{name: "bootstrap failed", mode: ModeGUI, fallback: true, fake: fakeWebView2{accept: true}, wantMode: ModeBrowser, wantAsked: 1},
+		{name: "declined without fallback", mode: ModeGUI, wantMode: ModeGUI, wantAsked: 1},
+		{name: "not the GUI", mode: ModeCLI, fallback: true, wantMode: ModeCLI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{AppName: "WebViewApp", Mode: tt.mode, DisableUIFallback: !tt.fallback}
+			inst := New(config)
+			inst.SetContext(&Context{Config: config, Logger: NewLogger("error", ""), Metadata: map[string]interface{}{}})
+
+			fake := tt.fake
+			inst.prepareWebView2(fake.runtime(server.URL))
+			if config.Mode != tt.wantMode {
+				t.Errorf("Mode = %v, want %v", config.Mode, tt.wantMode)
+			}
+			if fake.asked != tt.wantAsked {
+				t.Errorf("asked %d times, want %d", fake.asked, tt.wantAsked)
+			}
+		})
+	}
+}
+
+// TestPrepareWebView2ConfirmHook tests that Config.ConfirmWebView2Install replaces the message box
+func TestPrepareWebView2ConfirmHook(t *testing.T) {
+	t.Setenv("WEBVIEW2_BROWSER_EXECUTABLE_FOLDER", "")
+	hookCalls := 0
+	config := &Config{AppName: "WebViewApp", Mode: ModeGUI,
+		ConfirmWebView2Install: func() bool { hookCalls++; return false }}
+	inst := New(config)
+	inst.SetContext(&Context{Config: config, Logger: NewLogger("error", ""), Metadata: map[string]interface{}{}})
+
+	fake := &fakeWebView2{accept: true}
+	inst.prepareWebView2(fake.runtime(""))
+	if hookCalls != 1 || fake.asked != 0 {
+		t.Errorf("hook called %d times, message box %d times; want the hook only", hookCalls, fake.asked)
+	}
+	if config.Mode != ModeBrowser {
+		t.Errorf("Mode = %v, want the browser UI after declining", config.Mode)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build windows
+// +build windows
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// webView2Backend reads the EdgeUpdate registry keys and asks with a message box
+var webView2Backend = &webView2Runtime{
+	version:    readWebView2Version,
+	fileExists: func(path string) bool { _, err := os.Stat(path); return err == nil },
+	ask:        askWebView2Install,
+	setupURL:   WebView2BootstrapperURL,
+	bootstrap: func(path string) error {
+		return exec.Command(path, "/silent", "/install").Run()
+	},
+}
+
+func readWebView2Version(loc webView2Location) (string, error) {
+	root := registry.LOCAL_MACHINE
+	if loc.PerUser {
+		root = registry.CURRENT_USER
+	}
+	key, err := registry.OpenKey(root, loc.Key, registry.QUERY_VALUE)
+	if errors.Is(err, registry.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer key.Close()
+
+	version, _, err := key.GetStringValue("pv")
+	if errors.Is(err, registry.ErrNotExist) {
+		return "", nil
+	}
+	return version, err
+}
+
+// askWebView2Install asks with a Yes/No message box, as no UI is running yet
+func askWebView2Install(appName string) bool {
+	title, _ := windows.UTF16PtrFromString(appName + " Setup")
+	text, _ := windows.UTF16PtrFromString(fmt.Sprintf(
+		"The %s installer needs the Microsoft Edge WebView2 runtime, which is not installed.\n\n"+
+			"Download and install it now? Choose No to continue in your web browser.", appName))
+	answer, err := windows.MessageBox(0, text, title, windows.MB_YESNO|windows.MB_ICONQUESTION)
+	return err == nil && answer == 6 // IDYES
+}
@@ -123,6 +123,7 @@ type FieldType string
 
 const (
 	FieldTypeText     FieldType = "text"
+	FieldTypeNumber   FieldType = "number"
 	FieldTypePassword FieldType = "password"
 	FieldTypePath     FieldType = "path"
 	FieldTypeCheckbox FieldType = "checkbox"
@@ -4,8 +4,6 @@ package installer
 
 import (
 	"fmt"
-	"path/filepath"
-	"runtime"
 
 	"github.com/mmso2016/setupkit/pkg/installer/core"
 	"github.com/mmso2016/setupkit/pkg/wizard"
@@ -34,11 +32,7 @@ func NewDFAInstaller(config *core.Config) *DFAInstaller {
 	
 	// Set default install path if not specified
 	if config.InstallDir == "" {
-		if runtime.GOOS == "windows" {
-			config.InstallDir = filepath.Join("C:", "Program Files", config.AppName)
-		} else {
-			config.InstallDir = filepath.Join("/usr/local", config.AppName)
-		}
+		config.InstallDir = config.DefaultInstallDir()
 	}
 	installer.installPath = config.InstallDir
 	
@@ -283,7 +277,7 @@ func (d *DFAInstaller) onEnterComponentsCalculating(data map[string]interface{})
 	// Calculate total size
 	d.totalSize = 0
 	for _, comp := range d.selectedComponents {
-		d.totalSize += comp.Size
+		d.totalSize += comp.DiskSize()
 	}
 	data["total_size"] = d.totalSize
 	data["components_changed"] = false // Reset flag
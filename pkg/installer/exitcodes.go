@@ -1,6 +1,10 @@
 package installer
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
 
 // Exit codes for installer operations
 const (
@@ -26,6 +30,7 @@ const (
 	ExitIncompatibleVersion = 26
 	ExitAlreadyInstalled   = 27
 	ExitConflictingApp     = 28
+	ExitNotInstalled       = 29
 	
 	// Installation failures (40-59)
 	ExitExtractFailed        = 40
@@ -54,6 +59,9 @@ const (
 	ExitLicenseDeclined = 91
 	ExitTimeoutUser     = 92
 	ExitUserAbort       = 93
+
+	// Completed, restart required; the code of Windows Installer
+	ExitRebootRequired = 3010
 )
 
 // Error types for categorizing failures
@@ -99,6 +107,17 @@ func GetExitCodeForError(err error) int {
 	return ExitGeneralError
 }
 
+// ExitCodeFor returns the exit code for the result err of running inst.
+// An unattended installation that succeeded but needs a restart returns
+// ExitRebootRequired, so that deployment tools can schedule one.
+func ExitCodeFor(inst *core.Installer, err error) int {
+	config := inst.GetConfig()
+	if err == nil && inst.RebootRequired() && (config.Mode == core.ModeSilent || config.Unattended) {
+		return ExitRebootRequired
+	}
+	return GetExitCodeForError(err)
+}
+
 // ExitCodeDescription returns a human-readable description of an exit code
 func ExitCodeDescription(code int) string {
 	descriptions := map[int]string{
@@ -120,6 +139,7 @@ func ExitCodeDescription(code int) string {
 		ExitIncompatibleVersion:  "Incompatible version detected",
 		ExitAlreadyInstalled:     "Application is already installed",
 		ExitConflictingApp:       "Conflicting application detected",
+		ExitNotInstalled:         "Application is not installed",
 		
 		ExitExtractFailed:        "Failed to extract files",
 		ExitCopyFailed:           "Failed to copy files",
@@ -144,6 +164,7 @@ func ExitCodeDescription(code int) string {
 		ExitLicenseDeclined:      "License agreement declined",
 		ExitTimeoutUser:          "User response timeout",
 		ExitUserAbort:            "Installation aborted by user",
+		ExitRebootRequired:       "Installation completed, a restart is required",
 	}
 	
 	if desc, ok := descriptions[code]; ok {
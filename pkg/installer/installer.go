@@ -6,6 +6,8 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"io/fs"
+	"os"
 
 	"github.com/mmso2016/setupkit/pkg/installer/config"
 	"github.com/mmso2016/setupkit/pkg/installer/core"
@@ -14,16 +16,24 @@ import (
 
 // Re-export core types for backward compatibility
 type (
-	Mode              = core.Mode
-	RollbackStrategy  = core.RollbackStrategy
-	Component         = core.Component
-	Config            = core.Config
-	PathConfiguration = core.PathConfiguration
-	Context           = core.Context
-	Checkpoint        = core.Checkpoint
-	Logger            = core.Logger
-	ProgressReporter  = core.ProgressReporter
-	PlatformInstaller = core.PlatformInstaller
+	Mode               = core.Mode
+	InstallScope       = core.InstallScope
+	RollbackStrategy   = core.RollbackStrategy
+	Component          = core.Component
+	ReleaseNote        = core.ReleaseNote
+	SystemRequirements = core.SystemRequirements
+	CompletionAction   = core.CompletionAction
+	Catalog            = core.Catalog
+	ComponentInfo      = core.ComponentInfo
+	Settings           = core.Settings
+	TelemetrySink      = core.TelemetrySink
+	Config             = core.Config
+	PathConfiguration  = core.PathConfiguration
+	Context            = core.Context
+	Checkpoint         = core.Checkpoint
+	Logger             = core.Logger
+	ProgressReporter   = core.ProgressReporter
+	PlatformInstaller  = core.PlatformInstaller
 )
 
 // Re-export constants
@@ -36,11 +46,22 @@ const (
 	RollbackNone    = core.RollbackNone
 	RollbackPartial = core.RollbackPartial
 	RollbackFull    = core.RollbackFull
+
+	ScopeAuto       = core.ScopeAuto
+	ScopePerUser    = core.ScopePerUser
+	ScopePerMachine = core.ScopePerMachine
+
+	ActionLaunch            = core.ActionLaunch
+	ActionOpenURL           = core.ActionOpenURL
+	ActionViewLog           = core.ActionViewLog
+	ActionDesktopShortcut   = core.ActionDesktopShortcut
+	ActionExportDiagnostics = core.ActionExportDiagnostics
 )
 
 // Installer wraps the core installer for backward compatibility
 type Installer struct {
-	core *core.Installer
+	core     *core.Installer
+	warnings []string
 }
 
 // Option is a functional option for configuring the installer
@@ -70,10 +91,37 @@ func WithPathConfig(pathConfig *PathConfiguration) Option {
 	}
 }
 
-// WithAssets sets the embedded assets
+// WithAssets sets the embedded assets, which also serve as payload source
+// unless WithSource is given
 func WithAssets(assets embed.FS) Option {
 	return func(c *Config) error {
 		c.Assets = assets
+		if c.Source == nil {
+			c.Source = assets
+		}
+		return nil
+	}
+}
+
+// WithAssetVerification verifies the embedded assets and payload source
+// against their signed manifest at startup, given the hex encoded public key
+// printed by cmd/gen-manifest. Tampered assets abort the installer.
+func WithAssetVerification(publicKey string) Option {
+	return func(c *Config) error {
+		key, err := core.ParseAssetKey(publicKey)
+		if err != nil {
+			return err
+		}
+		c.AssetKey = key
+		return nil
+	}
+}
+
+// WithSource sets the file system component files are copied from, see
+// core.EmbedSource, core.DirSource and core.OpenZipSource
+func WithSource(source fs.FS) Option {
+	return func(c *Config) error {
+		c.Source = source
 		return nil
 	}
 }
@@ -105,6 +153,15 @@ func WithMode(mode Mode) Option {
 	}
 }
 
+// WithUIFallback controls whether the installer falls back to the CLI when
+// the GUI or browser UI cannot start (enabled by default)
+func WithUIFallback(allow bool) Option {
+	return func(c *Config) error {
+		c.DisableUIFallback = !allow
+		return nil
+	}
+}
+
 // WithComponents sets the installable components
 func WithComponents(components ...Component) Option {
 	return func(c *Config) error {
@@ -113,6 +170,140 @@ func WithComponents(components ...Component) Option {
 	}
 }
 
+// WithStrictComponents rejects required components that are not selected
+// instead of selecting them with a warning
+func WithStrictComponents() Option {
+	return func(c *Config) error {
+		c.StrictComponents = true
+		return nil
+	}
+}
+
+// WithDefaultSelection selects the optional components the installer starts
+// with by strategy, such as core.DefaultSelectionRecommendedOnly for the
+// components marked Recommended, instead of by each component's Selected
+func WithDefaultSelection(strategy core.DefaultSelection) Option {
+	return func(c *Config) error {
+		c.DefaultSelection = strategy
+		return nil
+	}
+}
+
+// WithFeatures sets feature flags such as "telemetry" or "dev-mode" that
+// component installers read with core.FeatureEnabled; later calls add to
+// and override earlier ones
+func WithFeatures(features map[string]bool) Option {
+	return func(c *Config) error {
+		if c.Features == nil {
+			c.Features = make(map[string]bool, len(features))
+		}
+		for name, enabled := range features {
+			c.Features[name] = enabled
+		}
+		return nil
+	}
+}
+
+// WithSelectionPresets offers quick selections such as "Typical" or "Full"
+// on the component screen, each naming the component IDs it selects or, as
+// "tag:recommended", the tags of the components, see core.TagPrefix
+func WithSelectionPresets(presets map[string][]string) Option {
+	return func(c *Config) error {
+		c.SelectionPresets = presets
+		return nil
+	}
+}
+
+// WithPreserveOnUninstall keeps paths matching the glob patterns, relative to
+// the install directory, when the application is uninstalled
+func WithPreserveOnUninstall(patterns ...string) Option {
+	return func(c *Config) error {
+		c.PreserveOnUninstall = append(c.PreserveOnUninstall, patterns...)
+		return nil
+	}
+}
+
+// WithOfflineBundle makes the installer check that all payloads of the
+// selected components are present in the assets or in dir before installing
+func WithOfflineBundle(dir string) Option {
+	return func(c *Config) error {
+		c.OfflineBundle = true
+		c.BundleDir = dir
+		return nil
+	}
+}
+
+// WithProxy sets the proxies for downloads and telemetry. Empty values fall
+// back to HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+func WithProxy(httpProxy, httpsProxy, noProxy string) Option {
+	return func(c *Config) error {
+		c.HTTPProxy = httpProxy
+		c.HTTPSProxy = httpsProxy
+		c.NoProxy = noProxy
+		return nil
+	}
+}
+
+// WithCache keeps downloaded component payloads in dir, keyed by checksum, so
+// they are not downloaded again. A maxSize of zero uses core.DefaultCacheMaxSize.
+func WithCache(dir string, maxSize int64) Option {
+	return func(c *Config) error {
+		c.CacheDir = dir
+		c.CacheMaxSize = maxSize
+		return nil
+	}
+}
+
+// WithTempDir stages downloads in dir instead of the system temp directory
+func WithTempDir(dir string) Option {
+	return func(c *Config) error {
+		c.TempDir = dir
+		return nil
+	}
+}
+
+// WithResume continues an interrupted installation in the install directory
+// instead of starting over. Components the interrupted run completed are
+// skipped if their files are unchanged.
+func WithResume() Option {
+	return func(c *Config) error {
+		c.Resume = true
+		return nil
+	}
+}
+
+// WithModify changes the components of the installation in the install
+// directory: its components start out selected, and deselecting one removes
+// it. Running fails if nothing is installed there.
+func WithModify() Option {
+	return func(c *Config) error {
+		c.Modify = true
+		return nil
+	}
+}
+
+// WithAllowOverwriteNonEmpty installs into a directory that already contains
+// other files without asking. Silent installations refuse such a directory
+// unless this is set.
+func WithAllowOverwriteNonEmpty() Option {
+	return func(c *Config) error {
+		c.AllowOverwriteNonEmpty = true
+		return nil
+	}
+}
+
+// WithTelemetry sends anonymous lifecycle events to sink. Only enable it after
+// the user agreed; properties in allowedPII, such as "install_dir", are sent
+// as well, all other personal data is removed.
+func WithTelemetry(sink TelemetrySink, allowedPII ...string) Option {
+	return func(c *Config) error {
+		c.EnableTelemetry = true
+		c.TelemetrySink = sink
+		c.TelemetryAllowedPII = append(c.TelemetryAllowedPII, allowedPII...)
+		return nil
+	}
+}
+
 // WithRollback sets the rollback strategy
 func WithRollback(strategy RollbackStrategy) Option {
 	return func(c *Config) error {
@@ -121,15 +312,108 @@ func WithRollback(strategy RollbackStrategy) Option {
 	}
 }
 
-// WithInstallDir sets the installation directory
+// WithInstallCancel lets the user cancel while the installation runs. The
+// components installed so far are rolled back as the rollback strategy says.
+func WithInstallCancel(allow bool) Option {
+	return func(c *Config) error {
+		c.AllowInstallCancel = allow
+		return nil
+	}
+}
+
+// WithCancelPolicy decides what cancelling while installing does with the
+// components installed so far: roll them back, keep them so running the
+// installer again resumes, or ask the user. See WithInstallCancel.
+func WithCancelPolicy(policy core.CancelPolicy) Option {
+	return func(c *Config) error {
+		c.CancelPolicy = policy
+		return nil
+	}
+}
+
+// WithWindowSize sets the initial size of the GUI window in pixels
+func WithWindowSize(width, height int) Option {
+	return func(c *Config) error {
+		if width <= 0 || height <= 0 {
+			return fmt.Errorf("invalid window size %dx%d", width, height)
+		}
+		c.WindowWidth, c.WindowHeight = width, height
+		return nil
+	}
+}
+
+// WithMinWindowSize sets the smallest size the user can resize the GUI
+// window to
+func WithMinWindowSize(width, height int) Option {
+	return func(c *Config) error {
+		if width <= 0 || height <= 0 {
+			return fmt.Errorf("invalid minimum window size %dx%d", width, height)
+		}
+		c.MinWidth, c.MinHeight = width, height
+		return nil
+	}
+}
+
+// WithResizable sets whether the user can resize the GUI window, which is
+// the default
+func WithResizable(resizable bool) Option {
+	return func(c *Config) error {
+		c.FixedSize = !resizable
+		return nil
+	}
+}
+
+// WithForwardOnly removes going back from the wizard, for kiosk and silent
+// installations that only ever move forward
+func WithForwardOnly() Option {
+	return func(c *Config) error {
+		c.ForwardOnly = true
+		return nil
+	}
+}
+
+// WithLicenseScroll makes the user read each license to the end before
+// accepting it, as some compliance rules require
+func WithLicenseScroll() Option {
+	return func(c *Config) error {
+		c.RequireLicenseScroll = true
+		return nil
+	}
+}
+
+// WithInstallDir sets the installation directory, expanding variables and
+// special folders, see core.ExpandPath
 func WithInstallDir(dir string) Option {
 	return func(c *Config) error {
-		c.InstallDir = dir
+		expanded, err := core.ExpandPath(dir)
+		if err != nil {
+			return err
+		}
+		c.InstallDir = expanded
 		return nil
 	}
 }
 
-// WithResponseFile sets the response file for unattended installation
+// WithInstallScope sets the installation scope (per-user or per-machine)
+func WithInstallScope(scope InstallScope) Option {
+	return func(c *Config) error {
+		c.InstallScope = scope
+		return nil
+	}
+}
+
+// WithPublisherInPath places the default installation directory in a folder
+// named after the publisher, e.g. C:\Program Files\Publisher\App
+func WithPublisherInPath() Option {
+	return func(c *Config) error {
+		c.UsePublisherInPath = true
+		return nil
+	}
+}
+
+// WithResponseFile replays the settings of a response file, see
+// core.WriteResponseFile, in an unattended installation. The file is read
+// when the installer runs, see Config.LoadResponseFile.
 func WithResponseFile(file string) Option {
 	return func(c *Config) error {
 		c.ResponseFile = file
@@ -138,6 +422,32 @@ func WithResponseFile(file string) Option {
 	}
 }
 
+// WithSettings applies a layer of settings from source, e.g. values read from a
+// config file or from command-line flags (see core.FlagSettings). The source is
+// recorded in ConfigProvenance.
+func WithSettings(source string, settings Settings) Option {
+	return func(c *Config) error {
+		return c.ApplySettingsLayers(core.SettingsLayer{Source: source, Settings: settings})
+	}
+}
+
+// WithEnvOverrides applies settings from <prefix>_* environment variables
+// (SETUPKIT_* when prefix is empty), such as SETUPKIT_INSTALL_DIR,
+// SETUPKIT_MODE, SETUPKIT_ACCEPT_LICENSE and SETUPKIT_PROFILE.
+//
+// Options are applied in order, so the documented precedence
+// flags > env > file > embedded is kept by passing WithEnvOverrides after the
+// options for embedded and file configuration and before WithSettings for flags.
+func WithEnvOverrides(prefix string) Option {
+	return func(c *Config) error {
+		env := core.EnvSettings(prefix, os.LookupEnv)
+		if err := c.ApplySettingsLayers(core.SettingsLayer{Source: core.SourceEnv, Settings: env}); err != nil {
+			return fmt.Errorf("invalid environment override: %w", err)
+		}
+		return nil
+	}
+}
+
 // WithVerbose enables or disables verbose logging
 func WithVerbose(verbose bool) Option {
 	return func(c *Config) error {
@@ -154,6 +464,89 @@ func WithLicense(license string) Option {
 	}
 }
 
+// WithSystemRequirements sets the memory and processors the application needs
+func WithSystemRequirements(req SystemRequirements) Option {
+	return func(c *Config) error {
+		c.SystemRequirements = req
+		return nil
+	}
+}
+
+// WithCompletionActions adds next steps offered when the installation has
+// finished, such as launching the application or opening its documentation
+func WithCompletionActions(actions ...CompletionAction) Option {
+	return func(c *Config) error {
+		c.CompletionActions = append(c.CompletionActions, actions...)
+		return nil
+	}
+}
+
+// WithReleaseNotes sets the notes shown when an older installation is updated
+func WithReleaseNotes(notes ...ReleaseNote) Option {
+	return func(c *Config) error {
+		c.ReleaseNotes = append(c.ReleaseNotes, notes...)
+		return nil
+	}
+}
+
+// WithLocales offers the installer screens in locales, e.g. "en" and "de",
+// from a language selection before the welcome screen. Locales need a
+// built-in catalog or one added with WithCatalog.
+func WithLocales(locales ...string) Option {
+	return func(c *Config) error {
+		c.Locales = append(c.Locales, locales...)
+		return nil
+	}
+}
+
+// WithLocale shows the installer screens in locale without asking
+func WithLocale(locale string) Option {
+	return func(c *Config) error {
+		c.Locale = locale
+		return nil
+	}
+}
+
+// WithCatalog adds the messages of a locale, overriding built-in ones
+func WithCatalog(locale string, catalog Catalog) Option {
+	return func(c *Config) error {
+		if c.Catalogs == nil {
+			c.Catalogs = make(map[string]Catalog)
+		}
+		if c.Catalogs[locale] == nil {
+			c.Catalogs[locale] = make(Catalog, len(catalog))
+		}
+		for key, message := range catalog {
+			c.Catalogs[locale][key] = message
+		}
+		return nil
+	}
+}
+
+// WithCLIBanner sets the CLI welcome banner and an optional logo image path.
+// The logo is only drawn in terminals with truecolor support.
+func WithCLIBanner(banner, logoPath string) Option {
+	return func(c *Config) error {
+		c.CLIBanner = banner
+		c.CLILogo = logoPath
+		return nil
+	}
+}
+
+// WithLogo loads a PNG or SVG logo from fsys and shows it in the header of
+// the GUI and SSR pages. The CLI draws PNG logos in truecolor terminals when
+// no CLI logo is set.
+func WithLogo(fsys fs.FS, path string) Option {
+	return func(c *Config) error {
+		logo, err := core.LoadLogo(fsys, path)
+		if err != nil {
+			return err
+		}
+		c.Logo = logo
+		return nil
+	}
+}
+
 // WithPathConfiguration enables PATH management with specified scope
 func WithPathConfiguration(enabled bool, system bool) Option {
 	return func(c *Config) error {
@@ -165,6 +558,59 @@ func WithPathConfiguration(enabled bool, system bool) Option {
 	}
 }
 
+// WithPathScopeChoice lets the user choose between the user and the system
+// PATH on a screen after the installation path, defaulting to the system
+// PATH when the installer runs elevated. Silent installations keep the
+// configured scope, which the path_scope setting can change. Apply it after
+// WithPathConfig or WithPathConfiguration.
+func WithPathScopeChoice() Option {
+	return func(c *Config) error {
+		if c.PathConfig == nil {
+			c.PathConfig = &PathConfiguration{Enabled: true}
+		}
+		c.PathConfig.AskScope = true
+		return nil
+	}
+}
+
+// WithEnvFile writes env.sh into the installation directory on Unix, which
+// puts the PATH directories in PATH of the current shell when sourced;
+// otherwise only new shells see them. Apply it after WithPathConfig or
+// WithPathConfiguration.
+func WithEnvFile() Option {
+	return func(c *Config) error {
+		if c.PathConfig == nil {
+			c.PathConfig = &PathConfiguration{Enabled: true}
+		}
+		c.PathConfig.EnvFile = true
+		return nil
+	}
+}
+
+// WithInstallScopeChoice lets the user choose on a screen after the welcome
+// screen whether to install for all users or just for themselves. The
+// choice sets the default installation directory, PATH scope, shortcut
+// folders and whether elevation is needed. It is not offered when
+// WithInstallScope or WithPortable forces the scope, or when the installer
+// cannot elevate; silent installations read it from the install_scope
+// setting.
+func WithInstallScopeChoice() Option {
+	return func(c *Config) error {
+		c.AskInstallScope = true
+		return nil
+	}
+}
+
+// WithPortable makes the installation portable: it changes nothing outside
+// the install directory, leaves out PATH, registry, shortcuts and
+// components with services, and writes core.PortableConfigFile instead
+func WithPortable() Option {
+	return func(c *Config) error {
+		c.Portable = true
+		return nil
+	}
+}
+
 // WithElevationStrategy sets the elevation strategy
 func WithElevationStrategy(strategy core.ElevationStrategy) Option {
 	return func(c *Config) error {
@@ -387,14 +833,27 @@ func New(opts ...Option) (*Installer, error) {
 		}
 	}
 
-	// Create core installer
+	// Check component definitions
+	warnings, err := core.ValidateComponents(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid components: %w", err)
+	}
+
+	// Create core installer, which logs the warnings once its logger is set up
 	coreInstaller := core.New(config)
+	coreInstaller.AddConfigWarnings(warnings)
 
 	return &Installer{
-		core: coreInstaller,
+		core:     coreInstaller,
+		warnings: warnings,
 	}, nil
 }
 
+// Warnings returns configuration problems that were corrected automatically
+func (i *Installer) Warnings() []string {
+	return i.warnings
+}
+
 // Run executes the installer
 func (i *Installer) Run() error {
 	return i.core.Run(context.Background())
@@ -405,6 +864,17 @@ func (i *Installer) RunWithContext(ctx context.Context) error {
 	return i.core.Run(ctx)
 }
 
+// ExitCode returns the exit code for the result err of Run, see ExitCodeFor
+func (i *Installer) ExitCode(err error) int {
+	return ExitCodeFor(i.core, err)
+}
+
+// RebootRequired reports whether the installation needs a restart to take
+// full effect
+func (i *Installer) RebootRequired() bool {
+	return i.core.RebootRequired()
+}
+
 // GetConfig returns the installer configuration
 func (i *Installer) GetConfig() *Config {
 	return i.core.GetConfig()
@@ -415,6 +885,16 @@ func (i *Installer) GetComponents() []Component {
 	return i.core.GetComponents()
 }
 
+// ConfigProvenance returns, per setting key, the source that provided its value
+func (i *Installer) ConfigProvenance() map[string]string {
+	return i.core.ConfigProvenance()
+}
+
+// ListComponents describes the available components for automation
+func (i *Installer) ListComponents() []ComponentInfo {
+	return i.core.ListComponents()
+}
+
 // SetSelectedComponents updates the selected state of components
 func (i *Installer) SetSelectedComponents(components []Component) {
 	i.core.SetSelectedComponents(components)
@@ -425,6 +905,17 @@ func (i *Installer) SetInstallPath(path string) {
 	i.core.SetInstallPath(path)
 }
 
+// ExportDiagnostics writes a zip archive for reporting an installation problem
+// to path, see core.Installer.ExportDiagnostics
+func (i *Installer) ExportDiagnostics(path string) error {
+	return i.core.ExportDiagnostics(path)
+}
+
+// ClearCache removes all payloads from the download cache, see WithCache
+func (i *Installer) ClearCache() error {
+	return i.core.ClearCache()
+}
+
 // IsUsingDFAWizard returns true if the installer is using the DFA-based wizard
 func (i *Installer) IsUsingDFAWizard() bool {
 	return i.core.IsUsingDFAWizard()
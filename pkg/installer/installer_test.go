@@ -1,12 +1,21 @@
 package installer_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"flag"
+	"image"
+	"image/png"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/mmso2016/setupkit/pkg/installer"
+	"github.com/mmso2016/setupkit/pkg/installer/core"
 )
 
 // TestNewInstaller tests installer creation with various options
@@ -145,6 +154,85 @@ func TestComponentSelection(t *testing.T) {
 	}
 }
 
+// TestRequiredComponentSelection tests handling of required but unselected components
+func TestRequiredComponentSelection(t *testing.T) {
+	components := []installer.Component{
+		{ID: "core", Name: "Core Files", Required: true, Selected: false},
+		{ID: "docs", Name: "Documentation", Required: false, Selected: false},
+	}
+
+	// Default: auto-correct with a warning
+	inst, err := installer.New(
+		installer.WithAppName("TestApp"),
+		installer.WithComponents(components...),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create installer: %v", err)
+	}
+	got := inst.GetComponents()
+	if !got[0].Selected {
+		t.Error("Required component should be selected automatically")
+	}
+	if got[1].Selected {
+		t.Error("Optional component selection should not change")
+	}
+	if len(inst.Warnings()) != 1 || !strings.Contains(inst.Warnings()[0], "core") {
+		t.Errorf("Expected one warning about core, got %v", inst.Warnings())
+	}
+
+	// Strict: reject
+	components[0].Selected = false
+	_, err = installer.New(
+		installer.WithAppName("TestApp"),
+		installer.WithComponents(components...),
+		installer.WithStrictComponents(),
+	)
+	if err == nil || !strings.Contains(err.Error(), "core is required but not selected") {
+		t.Errorf("Expected strict validation error, got %v", err)
+	}
+}
+
+// TestDefaultSelection tests that each default selection strategy selects the expected components
+func TestDefaultSelection(t *testing.T) {
+	components := []installer.Component{
+		{ID: "core", Name: "Core Files", Required: true, Selected: true},
+		{ID: "docs", Name: "Documentation", Selected: true},
+		{ID: "examples", Name: "Examples", Recommended: true, Dependencies: []string{"samples"}},
+		{ID: "samples", Name: "Sample Data"},
+	}
+
+	tests := []struct {
+		strategy core.DefaultSelection
+		want     []string
+	}{
+		{core.DefaultSelectionDeclared, []string{"core", "docs"}},
+		{core.DefaultSelectionAllOptional, []string{"core", "docs", "examples", "samples"}},
+		{core.DefaultSelectionNoneOptional, []string{"core"}},
+		{core.DefaultSelectionRecommendedOnly, []string{"core", "examples", "samples"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy.String(), func(t *testing.T) {
+			inst, err := installer.New(
+				installer.WithAppName("TestApp"),
+				installer.WithComponents(components...),
+				installer.WithDefaultSelection(tt.strategy),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create installer: %v", err)
+			}
+			var got []string
+			for _, c := range inst.GetComponents() {
+				if c.Selected {
+					got = append(got, c.ID)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selected = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestExitCodes tests exit code functionality
 func TestExitCodes(t *testing.T) {
 	tests := []struct {
@@ -169,6 +257,61 @@ func TestExitCodes(t *testing.T) {
 	}
 }
 
+// TestRebootExitCode tests that unattended installations needing a restart exit with ExitRebootRequired
+func TestRebootExitCode(t *testing.T) {
+	config := &core.Config{AppName: "RebootApp", Mode: core.ModeSilent}
+	inst := core.New(config)
+	if code := installer.ExitCodeFor(inst, nil); code != installer.ExitSuccess {
+		t.Errorf("ExitCodeFor() = %d without a restart, want %d", code, installer.ExitSuccess)
+	}
+
+	inst.RequireReboot("Driver was installed")
+	if code := installer.ExitCodeFor(inst, nil); code != installer.ExitRebootRequired {
+		t.Errorf("ExitCodeFor() = %d, want %d", code, installer.ExitRebootRequired)
+	}
+	failed := installer.NewError(installer.ExitCopyFailed, "copy failed", nil)
+	if code := installer.ExitCodeFor(inst, failed); code != installer.ExitCopyFailed {
+		t.Errorf("ExitCodeFor(error) = %d, want the error's code %d", code, installer.ExitCopyFailed)
+	}
+
+	// Interactive installations tell the user instead
+	config.Mode = core.ModeCLI
+	if code := installer.ExitCodeFor(inst, nil); code != installer.ExitSuccess {
+		t.Errorf("ExitCodeFor() = %d in CLI mode, want %d", code, installer.ExitSuccess)
+	}
+	config.Unattended = true
+	if code := installer.ExitCodeFor(inst, nil); code != installer.ExitRebootRequired {
+		t.Errorf("ExitCodeFor() = %d unattended, want %d", code, installer.ExitRebootRequired)
+	}
+}
+
+// TestClearCache tests clearing the cache configured with WithCache
+func TestClearCache(t *testing.T) {
+	dir := t.TempDir()
+	inst, err := installer.New(installer.WithAppName("CacheApp"), installer.WithCache(dir, 0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	src := filepath.Join(t.TempDir(), "payload")
+	if err := os.WriteFile(src, []byte("payload"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	checksum := "239f59ed55e737c77147cf55ad0c1b030b6d7ee748a7426952f9b852d5a935e5"
+	cache := core.NewCache(inst.GetConfig())
+	if err := cache.Put(checksum, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := inst.ClearCache(); err != nil {
+		t.Fatalf("ClearCache() error = %v", err)
+	}
+	if hit, _ := cache.Get(checksum, filepath.Join(t.TempDir(), "out")); hit {
+		t.Error("Payload still cached after ClearCache()")
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("Cache directory was removed: %v", err)
+	}
+}
+
 // TestInstallError tests custom error type
 func TestInstallError(t *testing.T) {
 	cause := os.ErrPermission
@@ -359,3 +502,473 @@ func TestRollbackStrategy(t *testing.T) {
 		seen[strategy] = true
 	}
 }
+
+// TestListComponents tests the machine-readable component listing
+func TestListComponents(t *testing.T) {
+	inst, err := installer.New(
+		installer.WithAppName("TestApp"),
+		installer.WithComponents(
+			installer.Component{ID: "core", Name: "Core Files", Size: 2048, Required: true, Selected: true},
+			installer.Component{ID: "plugins", Name: "Plugins", Description: "Extra plugins", Size: 512, Dependencies: []string{"core"}},
+		),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create installer: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := core.WriteComponentList(&buf, inst.ListComponents(), true); err != nil {
+		t.Fatalf("WriteComponentList() error = %v", err)
+	}
+
+	var listed []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &listed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(listed) != 2 {
+		t.Fatalf("Expected 2 components, got %d", len(listed))
+	}
+	if listed[0]["size"] != float64(2048) || listed[1]["size"] != float64(512) {
+		t.Errorf("Sizes missing from JSON: %s", buf.String())
+	}
+	deps, ok := listed[1]["dependencies"].([]interface{})
+	if !ok || len(deps) != 1 || deps[0] != "core" {
+		t.Errorf("Dependencies missing from JSON: %s", buf.String())
+	}
+	if deps, ok := listed[0]["dependencies"].([]interface{}); !ok || len(deps) != 0 {
+		t.Errorf("Components without dependencies should list an empty array: %s", buf.String())
+	}
+	if listed[1]["description"] != "Extra plugins" || listed[0]["required"] != true {
+		t.Errorf("Unexpected component fields: %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := core.WriteComponentList(&buf, inst.ListComponents(), false); err != nil {
+		t.Fatalf("WriteComponentList() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "plugins") || !strings.Contains(buf.String(), "DEPENDS ON") {
+		t.Errorf("Unexpected text listing:\n%s", buf.String())
+	}
+}
+
+// TestEnvOverridePrecedence tests flags > env > file > embedded resolution
+func TestEnvOverridePrecedence(t *testing.T) {
+	embedded := installer.Settings{
+		core.SettingInstallDir:    "/embedded",
+		core.SettingMode:          "gui",
+		core.SettingProfile:       "full",
+		core.SettingLogLevel:      "info",
+		core.SettingAcceptLicense: "false",
+	}
+	file := installer.Settings{
+		core.SettingInstallDir: "/file",
+		core.SettingMode:       "cli",
+		core.SettingProfile:    "developer",
+	}
+	t.Setenv("SETUPKIT_INSTALL_DIR", "/env")
+	t.Setenv("SETUPKIT_MODE", "silent")
+	t.Setenv("SETUPKIT_ACCEPT_LICENSE", "true")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", "/flag-default", "")
+	fs.String("mode", "auto", "")
+	if err := fs.Parse([]string{"-dir=/flag"}); err != nil {
+		t.Fatal(err)
+	}
+	flags := core.FlagSettings(fs, map[string]string{"dir": core.SettingInstallDir})
+	if _, ok := flags[core.SettingMode]; ok {
+		t.Fatal("Flags that were not set must not override other sources")
+	}
+
+	inst, err := installer.New(
+		installer.WithAppName("TestApp"),
+		installer.WithSettings(core.SourceEmbedded, embedded),
+		installer.WithSettings(core.SourceFile, file),
+		installer.WithEnvOverrides(""),
+		installer.WithSettings(core.SourceFlags, flags),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create installer: %v", err)
+	}
+
+	config := inst.GetConfig()
+	if config.InstallDir != "/flag" {
+		t.Errorf("InstallDir = %s, want /flag from flags", config.InstallDir)
+	}
+	if config.Mode != installer.ModeSilent {
+		t.Errorf("Mode = %v, want silent from env", config.Mode)
+	}
+	if !config.AcceptLicense {
+		t.Error("AcceptLicense should come from env")
+	}
+	if config.Profile != "developer" {
+		t.Errorf("Profile = %s, want developer from file", config.Profile)
+	}
+	if config.LogLevel != "info" {
+		t.Errorf("LogLevel = %s, want info from embedded", config.LogLevel)
+	}
+
+	provenance := inst.ConfigProvenance()
+	for key, want := range map[string]string{
+		core.SettingInstallDir:    core.SourceFlags,
+		core.SettingMode:          core.SourceEnv,
+		core.SettingAcceptLicense: core.SourceEnv,
+		core.SettingProfile:       core.SourceFile,
+		core.SettingLogLevel:      core.SourceEmbedded,
+	} {
+		if provenance[key] != want {
+			t.Errorf("Provenance of %s = %q, want %q", key, provenance[key], want)
+		}
+	}
+
+	// MergeSettings gives the same result
+	merged := core.MergeSettings(embedded, file, core.EnvSettings("SETUPKIT", func(key string) (string, bool) {
+		return map[string]string{"SETUPKIT_INSTALL_DIR": "/env", "SETUPKIT_MODE": "silent"}[key], key == "SETUPKIT_INSTALL_DIR" || key == "SETUPKIT_MODE"
+	}), flags)
+	if merged[core.SettingInstallDir] != "/flag" || merged[core.SettingMode] != "silent" || merged[core.SettingProfile] != "developer" {
+		t.Errorf("Unexpected merged settings: %v", merged)
+	}
+
+	// Invalid env values are reported
+	t.Setenv("SETUPKIT_MODE", "fancy")
+	if _, err := installer.New(installer.WithEnvOverrides("")); err == nil {
+		t.Error("Expected error for invalid SETUPKIT_MODE")
+	}
+}
+
+// TestInstallDirExpansion tests that install dirs from options and settings are expanded
+func TestInstallDirExpansion(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("SETUPKIT_TEST_BASE", base)
+
+	inst, err := installer.New(installer.WithAppName("TestApp"), installer.WithInstallDir("$SETUPKIT_TEST_BASE/app"))
+	if err != nil {
+		t.Fatalf("Failed to create installer: %v", err)
+	}
+	if want := base + "/app"; inst.GetConfig().InstallDir != want {
+		t.Errorf("InstallDir = %s, want %s", inst.GetConfig().InstallDir, want)
+	}
+
+	inst, err = installer.New(installer.WithAppName("TestApp"),
+		installer.WithSettings(core.SourceFile, installer.Settings{core.SettingInstallDir: "${SETUPKIT_TEST_BASE}/file"}))
+	if err != nil {
+		t.Fatalf("Failed to create installer: %v", err)
+	}
+	if want := base + "/file"; inst.GetConfig().InstallDir != want {
+		t.Errorf("InstallDir = %s, want %s", inst.GetConfig().InstallDir, want)
+	}
+
+	for _, dir := range []string{"{Desktop}/app", "$SETUPKIT_TEST_UNSET/app"} {
+		if _, err := installer.New(installer.WithAppName("TestApp"), installer.WithInstallDir(dir)); err == nil {
+			t.Errorf("Expected error for install dir %s", dir)
+		}
+	}
+}
+
+// TestSilentUninstall tests non-interactive removal against a fabricated manifest
+func TestSilentUninstall(t *testing.T) {
+	installDir := t.TempDir()
+	files := map[string]string{
+		"bin/app":        "binary",
+		"README.txt":     "readme",
+		"data/state.db":  "state",
+		"user-notes.txt": "notes",
+	}
+	for name, content := range files {
+		path := filepath.Join(installDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest := &core.Manifest{AppName: "TestApp", Version: "1.0.0", InstallDir: installDir}
+	manifest.SetComponent(core.NewManifestComponent(installDir, core.Component{ID: "core", Files: []string{"bin/app", "README.txt"}}))
+	manifest.SetComponent(core.NewManifestComponent(installDir, core.Component{ID: "data", Files: []string{"data/state.db"}}))
+	if err := manifest.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &installer.Config{
+		AppName:             "TestApp",
+		InstallDir:          installDir,
+		PreserveOnUninstall: []string{"data"},
+	}
+	var out bytes.Buffer
+	code := installer.SilentUninstall(config, &out)
+	if code != installer.ExitSuccess {
+		t.Fatalf("SilentUninstall() = %d, want %d\n%s", code, installer.ExitSuccess, out.String())
+	}
+
+	var summary installer.UninstallSummary
+	if err := json.Unmarshal(out.Bytes(), &summary); err != nil {
+		t.Fatalf("Summary is not valid JSON: %v\n%s", err, out.String())
+	}
+	if !summary.Success || summary.ExitCode != installer.ExitSuccess {
+		t.Errorf("Unexpected summary status: %+v", summary)
+	}
+	if got := strings.Join(summary.Removed, ","); got != "README.txt,bin/app" {
+		t.Errorf("Removed = %s, want README.txt,bin/app", got)
+	}
+	if got := strings.Join(summary.Kept, ","); got != "data/state.db,user-notes.txt" {
+		t.Errorf("Kept = %s, want data/state.db,user-notes.txt", got)
+	}
+	for _, name := range []string{"bin", "README.txt", core.ManifestFileName} {
+		if _, err := os.Stat(filepath.Join(installDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", name)
+		}
+	}
+	for _, name := range []string{"data/state.db", "user-notes.txt"} {
+		if _, err := os.Stat(filepath.Join(installDir, filepath.FromSlash(name))); err != nil {
+			t.Errorf("%s should be kept", name)
+		}
+	}
+
+	// A second run finds nothing to uninstall
+	out.Reset()
+	if code := installer.SilentUninstall(config, &out); code != installer.ExitNotInstalled {
+		t.Errorf("SilentUninstall() without manifest = %d, want %d", code, installer.ExitNotInstalled)
+	}
+	if !strings.Contains(out.String(), `"success": false`) {
+		t.Errorf("Expected failure summary, got %s", out.String())
+	}
+}
+
+// TestThemes applies every listed theme and checks its generated CSS
+func TestThemes(t *testing.T) {
+	primary := map[string]string{
+		"default":        "#2563eb",
+		"corporate-blue": "#1e3a8a",
+		"medical-green":  "#059669",
+		"tech-dark":      "#6366f1",
+		"minimal-light":  "#000000",
+	}
+
+	listed := installer.ListThemes()
+	if len(listed) != len(primary) {
+		t.Errorf("ListThemes() returned %d themes, want %d", len(listed), len(primary))
+	}
+	for _, info := range listed {
+		t.Run(info.Name, func(t *testing.T) {
+			want, ok := primary[info.Name]
+			if !ok {
+				t.Fatalf("unexpected theme %q", info.Name)
+			}
+			if info.Description == "" {
+				t.Error("theme has no description")
+			}
+
+			inst, err := installer.New(
+				installer.WithAppName("ThemeApp"),
+				installer.WithTheme(info.Name),
+			)
+			if err != nil {
+				t.Fatalf("WithTheme(%q) failed: %v", info.Name, err)
+			}
+
+			css := inst.GetConfig().GetThemeCSS()
+			if css == "" {
+				t.Fatal("GetThemeCSS() returned no CSS")
+			}
+			if strings.Count(css, "{") != strings.Count(css, "}") {
+				t.Error("CSS has unbalanced braces")
+			}
+			if !strings.Contains(css, ":root {") {
+				t.Error("CSS does not define variables in :root")
+			}
+			if decl := "--color-primary: " + want + ";"; !strings.Contains(css, decl) {
+				t.Errorf("CSS does not contain %q", decl)
+			}
+			for _, line := range strings.Split(css, "\n") {
+				if strings.HasPrefix(strings.TrimSpace(line), "--") && strings.Contains(line, ": ;") {
+					t.Errorf("empty CSS variable: %s", strings.TrimSpace(line))
+				}
+			}
+		})
+	}
+}
+
+// TestRenderThemePreview checks that previews show the theme and all sample sections
+func TestRenderThemePreview(t *testing.T) {
+	for name, theme := range installer.GetBuiltinThemes() {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := installer.RenderThemePreview(name, &buf); err != nil {
+				t.Fatalf("RenderThemePreview(%q) failed: %v", name, err)
+			}
+			page := buf.String()
+
+			if !strings.HasPrefix(page, "<!DOCTYPE html>") {
+				t.Error("preview is not a standalone HTML page")
+			}
+			for _, color := range []string{theme.Colors.Primary, theme.Colors.Background, theme.Colors.Text} {
+				if !strings.Contains(page, color) {
+					t.Errorf("preview does not contain theme color %s", color)
+				}
+			}
+			for _, id := range []string{"preview-welcome", "preview-components", "preview-summary"} {
+				if !strings.Contains(page, `id="`+id+`"`) {
+					t.Errorf("preview is missing section %s", id)
+				}
+			}
+			if strings.Contains(page, "<link") || strings.Contains(page, "http://") || strings.Contains(page, "https://") {
+				t.Error("preview references external resources")
+			}
+		})
+	}
+
+	if err := installer.RenderThemePreview("no-such-theme", &bytes.Buffer{}); err == nil {
+		t.Error("RenderThemePreview accepted an unknown theme")
+	}
+}
+
+// TestWithLogo checks that logos are validated when the installer is created
+func TestWithLogo(t *testing.T) {
+	var logo bytes.Buffer
+	if err := png.Encode(&logo, image.NewRGBA(image.Rect(0, 0, 48, 48))); err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		"logo.png":   {Data: logo.Bytes()},
+		"readme.txt": {Data: []byte("not an image")},
+	}
+
+	inst, err := installer.New(installer.WithAppName("LogoApp"), installer.WithLogo(fsys, "logo.png"))
+	if err != nil {
+		t.Fatalf("New() with a valid logo failed: %v", err)
+	}
+	if got := inst.GetConfig().Logo; got == nil || got.Width != 48 {
+		t.Errorf("Logo = %+v, want a 48px PNG", got)
+	}
+
+	if _, err := installer.New(installer.WithLogo(fsys, "readme.txt")); err == nil {
+		t.Error("New() accepted a logo that is not an image")
+	}
+}
+
+// TestResponseFileReplay tests that a written response file is replayed
+func TestResponseFileReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "responses.txt")
+	err := core.WriteResponseFile(path, installer.Settings{
+		core.SettingInstallDir:    "/opt/replay",
+		core.SettingAcceptLicense: "true",
+		core.SettingLocale:        "de",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inst, err := installer.New(installer.WithAppName("ReplayApp"), installer.WithResponseFile(path),
+		installer.WithSettings(core.SourceFlags, installer.Settings{core.SettingAcceptLicense: "false"}))
+	if err != nil {
+		t.Fatalf("Failed to create installer: %v", err)
+	}
+	config := inst.GetConfig()
+	if config.Locale == "de" {
+		t.Error("New() should not read the response file")
+	}
+	if err := config.LoadResponseFile(); err != nil {
+		t.Fatalf("Failed to load response file: %v", err)
+	}
+	if config.AcceptLicense {
+		t.Error("Flags should take precedence over the response file")
+	}
+	if !config.Unattended || config.InstallDir != "/opt/replay" || config.Locale != "de" {
+		t.Errorf("Response file not replayed: unattended=%v dir=%s locale=%s",
+			config.Unattended, config.InstallDir, config.Locale)
+	}
+	if inst.ConfigProvenance()[core.SettingLocale] != core.SourceFile {
+		t.Error("Replayed settings should come from the file")
+	}
+
+	if err := os.WriteFile(path, []byte("locale de\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&core.Config{ResponseFile: path}).LoadResponseFile(); err == nil {
+		t.Error("Expected error for a malformed response file")
+	}
+	if err := (&core.Config{ResponseFile: filepath.Join(t.TempDir(), "missing.txt")}).LoadResponseFile(); err == nil {
+		t.Error("Expected error for a missing response file")
+	}
+}
+
+// TestPathScopeSetting tests that silent installations read the PATH scope from settings
+func TestPathScopeSetting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "responses.txt")
+	if err := core.WriteResponseFile(path, installer.Settings{core.SettingPathScope: "system"}); err != nil {
+		t.Fatal(err)
+	}
+
+	inst, err := installer.New(installer.WithPathConfiguration(true, false), installer.WithResponseFile(path))
+	if err != nil {
+		t.Fatalf("Failed to create installer: %v", err)
+	}
+	if err := inst.GetConfig().LoadResponseFile(); err != nil {
+		t.Fatalf("Failed to load response file: %v", err)
+	}
+	if pc := inst.GetConfig().PathConfig; !pc.System || pc.Scope() != core.PathScopeNameSystem {
+		t.Errorf("PATH scope = %s, want system", pc.Scope())
+	}
+
+	config := &core.Config{PathConfig: &core.PathConfiguration{Enabled: true}}
+	if err := config.ApplySettings(core.Settings{core.SettingPathScope: "everywhere"}); err == nil {
+		t.Error("Expected error for an invalid PATH scope")
+	}
+}
+
+// TestInstallScopeSetting tests that a recorded install scope is replayed
+// with the defaults it implies, and that it forces the scope
+func TestInstallScopeSetting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "responses.txt")
+	if err := core.WriteResponseFile(path, installer.Settings{core.SettingInstallScope: "per-machine"}); err != nil {
+		t.Fatal(err)
+	}
+
+	inst, err := installer.New(installer.WithAppName("ScopeApp"), installer.WithPathConfiguration(true, false),
+		installer.WithInstallScopeChoice(), installer.WithResponseFile(path))
+	if err != nil {
+		t.Fatalf("Failed to create installer: %v", err)
+	}
+	config := inst.GetConfig()
+	if err := config.LoadResponseFile(); err != nil {
+		t.Fatalf("Failed to load response file: %v", err)
+	}
+	if config.InstallScope != core.ScopePerMachine || !config.PathConfig.System {
+		t.Errorf("scope %s, system PATH %v, want per-machine with the system PATH", config.InstallScope, config.PathConfig.System)
+	}
+	if want := core.DefaultInstallDir("ScopeApp", core.ScopePerMachine); config.InstallDir != want {
+		t.Errorf("InstallDir = %q, want %q", config.InstallDir, want)
+	}
+	if config.InstallScopeChoice() {
+		t.Error("a recorded scope should not be asked for again")
+	}
+
+	if err := config.ApplySettings(core.Settings{core.SettingInstallScope: "everyone"}); err == nil {
+		t.Error("Expected error for an invalid install scope")
+	}
+}
+
+func TestWindowOptions(t *testing.T) {
+	inst, err := installer.New(installer.WithAppName("WindowApp"))
+	if err != nil {
+		t.Fatalf("Failed to create installer: %v", err)
+	}
+	if config := inst.GetConfig(); config.FixedSize || config.WindowWidth != 0 {
+		t.Errorf("default window: fixed size %v, width %d, want resizable with the default size", config.FixedSize, config.WindowWidth)
+	}
+
+	inst, err = installer.New(installer.WithAppName("WindowApp"), installer.WithWindowSize(900, 720),
+		installer.WithMinWindowSize(640, 480), installer.WithResizable(false))
+	if err != nil {
+		t.Fatalf("Failed to create installer: %v", err)
+	}
+	config := inst.GetConfig()
+	if config.WindowWidth != 900 || config.WindowHeight != 720 || config.MinWidth != 640 || config.MinHeight != 480 || !config.FixedSize {
+		t.Errorf("window %dx%d, minimum %dx%d, fixed size %v", config.WindowWidth, config.WindowHeight, config.MinWidth, config.MinHeight, config.FixedSize)
+	}
+
+	if _, err := installer.New(installer.WithWindowSize(0, 720)); err == nil {
+		t.Error("Expected error for an invalid window size")
+	}
+}
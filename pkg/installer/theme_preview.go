@@ -0,0 +1,153 @@
+package installer
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mmso2016/setupkit/pkg/html"
+	"github.com/mmso2016/setupkit/pkg/installer/themes"
+)
+
+// previewComponents are the sample components shown in a theme preview
+var previewComponents = []Component{
+	{ID: "core", Name: "Core Files", Description: "Application binaries and libraries", Size: 45 << 20, Required: true, Selected: true},
+	{ID: "docs", Name: "Documentation", Description: "User guide and API reference", Size: 12 << 20, Selected: true},
+	{ID: "examples", Name: "Examples", Description: "Sample projects", Size: 3 << 20},
+}
+
+// previewLayout arranges the sample sections of a theme preview
+const previewLayout = `
+.preview-section {
+  margin-bottom: var(--spacing-large);
+}
+.preview-buttons {
+  display: flex;
+  justify-content: flex-end;
+  gap: var(--spacing-small);
+  margin-top: var(--spacing);
+}
+.preview-component {
+  display: flex;
+  align-items: center;
+  gap: var(--spacing-small);
+  padding: var(--spacing-small) 0;
+  border-bottom: 1px solid var(--color-border);
+}
+.preview-component:last-child {
+  border-bottom: none;
+}
+.preview-summary td {
+  padding: var(--spacing-small) var(--spacing) var(--spacing-small) 0;
+  vertical-align: top;
+}
+`
+
+// RenderThemePreview writes a standalone HTML page showing the welcome,
+// components and summary layouts with the theme applied. The CSS is inlined,
+// so the page can be opened in a browser without network access.
+func RenderThemePreview(themeName string, w io.Writer) error {
+	theme, err := themes.GetTheme(themeName)
+	if err != nil {
+		return err
+	}
+
+	doc := html.NewDocument().
+		SetTitle("Theme preview: " + theme.Name).
+		SetCharset("utf-8").
+		SetViewport("").
+		AddCSS(theme.CSS).
+		AddCSS(previewLayout)
+
+	doc.AddToBody(html.DIV().Class("installer-container").Children(
+		html.MAIN().Class("installer-content").Children(
+			html.H1(theme.Name).Class("installer-heading"),
+			html.P(theme.Description).Class("installer-text-muted"),
+			previewWelcome(),
+			previewComponentList(),
+			previewSummary(),
+		),
+	))
+
+	_, err = io.WriteString(w, doc.Render())
+	return err
+}
+
+// previewSection wraps sample content in a titled surface
+func previewSection(id, title string, content ...*html.Element) *html.Element {
+	section := html.SECTION().ID(id).Class("installer-surface preview-section").Children(
+		html.H2(title).Class("installer-heading"),
+	)
+	return section.Children(content...)
+}
+
+// previewButtons renders the navigation buttons of a sample page
+func previewButtons(primary string) *html.Element {
+	return html.DIV().Class("preview-buttons").Children(
+		html.BUTTON("Cancel").Class("installer-button secondary"),
+		html.BUTTON(primary).Class("installer-button"),
+	)
+}
+
+func previewWelcome() *html.Element {
+	return previewSection("preview-welcome", "Welcome",
+		html.P("Welcome to the Example App installation wizard."),
+		html.P("This wizard will guide you through the installation process.").Class("installer-text-muted"),
+		previewButtons("Next"),
+	)
+}
+
+func previewComponentList() *html.Element {
+	list := html.DIV()
+	for _, c := range previewComponents {
+		checkbox := html.INPUT("checkbox")
+		if c.Selected {
+			checkbox.Checked()
+		}
+		if c.Required {
+			checkbox.Disabled()
+		}
+		list.Child(html.DIV().Class("preview-component").Children(
+			checkbox,
+			html.STRONG(c.Name),
+			html.SPAN(c.Description).Class("installer-text-muted"),
+			html.SPAN(formatPreviewSize(c.DiskSize())).Class("installer-text-muted"),
+		))
+	}
+
+	return previewSection("preview-components", "Select Components",
+		list,
+		html.INPUT("text").Class("installer-input").Value("/opt/example-app"),
+		previewButtons("Next"),
+	)
+}
+
+func previewSummary() *html.Element {
+	var total int64
+	var names string
+	for _, c := range previewComponents {
+		if c.Selected {
+			total += c.DiskSize()
+			if names != "" {
+				names += ", "
+			}
+			names += c.Name
+		}
+	}
+
+	return previewSection("preview-summary", "Ready to Install",
+		html.TABLE().Class("preview-summary").Children(
+			html.TR().Children(html.TD("Installation path"), html.TD("/opt/example-app")),
+			html.TR().Children(html.TD("Components"), html.TD(names)),
+			html.TR().Children(html.TD("Required space"), html.TD(formatPreviewSize(total))),
+		),
+		html.P("Enough disk space is available.").Class("installer-success"),
+		html.DIV().Class("installer-progress-bar").Child(
+			html.DIV().Class("installer-progress-fill").Style("width: 40%"),
+		),
+		previewButtons("Install"),
+	)
+}
+
+func formatPreviewSize(bytes int64) string {
+	return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+}
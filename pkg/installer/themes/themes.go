@@ -1,4 +1,12 @@
-// Package themes provides theme management for the installer UI
+// Package themes provides theme management for the installer UI.
+//
+// The built-in themes, in the order ListThemes returns them, are:
+//
+//	default        Clean and modern, blue on white
+//	corporate-blue Professional dark blue
+//	medical-green  Calm green on a light green background
+//	tech-dark      Indigo on a dark slate background
+//	minimal-light  Black on white without animations
 package themes
 
 import (
@@ -275,20 +283,22 @@ var (
 	}
 )
 
+// builtinThemes lists the built-in themes in the order they are presented
+var builtinThemes = []Theme{
+	DefaultTheme,
+	CorporateBlueTheme,
+	MedicalGreenTheme,
+	TechDarkTheme,
+	MinimalLightTheme,
+}
+
 // GetBuiltinThemes returns all built-in themes
 func GetBuiltinThemes() map[string]Theme {
-	themes := map[string]Theme{
-		DefaultTheme.Name:        DefaultTheme,
-		CorporateBlueTheme.Name:  CorporateBlueTheme,
-		MedicalGreenTheme.Name:   MedicalGreenTheme,
-		TechDarkTheme.Name:       TechDarkTheme,
-		MinimalLightTheme.Name:   MinimalLightTheme,
-	}
-
-	// Generate CSS for each theme
-	for name, theme := range themes {
+	themes := make(map[string]Theme, len(builtinThemes))
+	for _, theme := range builtinThemes {
+		// Generate CSS for each theme
 		theme.CSS = GenerateCSS(theme)
-		themes[name] = theme
+		themes[theme.Name] = theme
 	}
 
 	return themes
@@ -304,18 +314,18 @@ func GetTheme(name string) (Theme, error) {
 	return theme, nil
 }
 
-// ListThemes returns a list of available theme names and descriptions
+// ListThemes returns the names and descriptions of the built-in themes, in
+// the order listed in the package documentation. Every name can be passed to
+// GetTheme.
 func ListThemes() []ThemeInfo {
-	themes := GetBuiltinThemes()
-	var result []ThemeInfo
-	
-	for _, theme := range themes {
+	result := make([]ThemeInfo, 0, len(builtinThemes))
+	for _, theme := range builtinThemes {
 		result = append(result, ThemeInfo{
 			Name:        theme.Name,
 			Description: theme.Description,
 		})
 	}
-	
+
 	return result
 }
 
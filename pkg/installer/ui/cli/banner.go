@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // Register GIF logos
+	_ "image/jpeg" // Register JPEG logos
+	_ "image/png"  // Register PNG logos
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// logoWidth is the width of a rendered logo in terminal columns
+const logoWidth = 32
+
+// TerminalCaps describes what the attached terminal can display
+type TerminalCaps struct {
+	TTY       bool
+	TrueColor bool
+}
+
+// DetectTerminalCaps inspects stdout and the environment
+func DetectTerminalCaps() TerminalCaps {
+	return terminalCaps(isTerminal(os.Stdout), os.Getenv)
+}
+
+// terminalCaps derives capabilities from the TTY state and environment
+func terminalCaps(tty bool, getenv func(string) string) TerminalCaps {
+	caps := TerminalCaps{TTY: tty}
+	if !tty || getenv("NO_COLOR") != "" {
+		return caps
+	}
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		caps.TrueColor = true
+	}
+	// Windows Terminal supports truecolor but does not set COLORTERM
+	if getenv("WT_SESSION") != "" {
+		caps.TrueColor = true
+	}
+	return caps
+}
+
+// ShowLogo reports whether a logo at logoPath should be drawn
+func (c TerminalCaps) ShowLogo(logoPath string) bool {
+	return logoPath != "" && c.TrueColor
+}
+
+// RenderBanner writes the welcome logo when the terminal can draw it, and the
+// banner text otherwise
+func RenderBanner(w io.Writer, config *core.Config, caps TerminalCaps) {
+	if caps.ShowLogo(config.CLILogo) {
+		if img, err := loadImage(config.CLILogo); err == nil {
+			renderImage(w, img, logoWidth)
+			return
+		}
+	} else if config.CLILogo == "" && config.Logo != nil && caps.TrueColor {
+		// The GUI logo, unless it is an SVG
+		if img, err := config.Logo.Image(); err == nil {
+			renderImage(w, img, logoWidth)
+			return
+		}
+	}
+	if config.CLIBanner != "" {
+		fmt.Fprintln(w, strings.TrimRight(config.CLIBanner, "\n"))
+	}
+}
+
+// loadImage decodes an image file
+func loadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// renderImage draws img scaled to width columns using upper half blocks,
+// two pixel rows per terminal line
+func renderImage(w io.Writer, img image.Image, width int) {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return
+	}
+	if bounds.Dx() < width {
+		width = bounds.Dx()
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	pixel := func(x, y int) (r, g, b uint32, visible bool) {
+		px := bounds.Min.X + x*bounds.Dx()/width
+		py := bounds.Min.Y + y*bounds.Dy()/height
+		r, g, b, a := img.At(px, py).RGBA()
+		return r >> 8, g >> 8, b >> 8, a >= 0x8000
+	}
+
+	var sb strings.Builder
+	for y := 0; y < height; y += 2 {
+		for x := 0; x < width; x++ {
+			tr, tg, tb, top := pixel(x, y)
+			var lr, lg, lb uint32
+			bottom := false
+			if y+1 < height {
+				lr, lg, lb, bottom = pixel(x, y+1)
+			}
+			switch {
+			case top && bottom:
+				fmt.Fprintf(&sb, "\033[38;2;%d;%d;%dm\033[48;2;%d;%d;%dm▀", tr, tg, tb, lr, lg, lb)
+			case top:
+				fmt.Fprintf(&sb, "\033[49m\033[38;2;%d;%d;%dm▀", tr, tg, tb)
+			case bottom:
+				fmt.Fprintf(&sb, "\033[49m\033[38;2;%d;%d;%dm▄", lr, lg, lb)
+			default:
+				sb.WriteString("\033[0m ")
+			}
+		}
+		sb.WriteString(ansiReset + "\n")
+	}
+	io.WriteString(w, sb.String())
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestTerminalCaps tests the truecolor capability decision
+func TestTerminalCaps(t *testing.T) {
+	tests := []struct {
+		name string
+		tty  bool
+		env  map[string]string
+		want bool
+	}{
+		{"truecolor tty", true, map[string]string{"COLORTERM": "truecolor"}, true},
+		{"24bit tty", true, map[string]string{"COLORTERM": "24bit"}, true},
+		{"windows terminal", true, map[string]string{"WT_SESSION": "abc"}, true},
+		{"256 colors", true, map[string]string{"COLORTERM": "", "TERM": "xterm-256color"}, false},
+		{"not a tty", false, map[string]string{"COLORTERM": "truecolor"}, false},
+		{"no color", true, map[string]string{"COLORTERM": "truecolor", "NO_COLOR": "1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caps := terminalCaps(tt.tty, func(key string) string { return tt.env[key] })
+			if caps.TrueColor != tt.want {
+				t.Errorf("TrueColor = %v, want %v", caps.TrueColor, tt.want)
+			}
+			if caps.ShowLogo("logo.png") != tt.want {
+				t.Errorf("ShowLogo() = %v, want %v", caps.ShowLogo("logo.png"), tt.want)
+			}
+		})
+	}
+	if (TerminalCaps{TTY: true, TrueColor: true}).ShowLogo("") {
+		t.Error("ShowLogo() without a logo path should be false")
+	}
+}
+
+// TestRenderBanner tests logo rendering and the fallback to banner text
+func TestRenderBanner(t *testing.T) {
+	logo := filepath.Join(t.TempDir(), "logo.png")
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	f, err := os.Create(logo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	config := &core.Config{CLIBanner: "== MyApp ==\n", CLILogo: logo}
+	trueColor := TerminalCaps{TTY: true, TrueColor: true}
+
+	var buf bytes.Buffer
+	RenderBanner(&buf, config, trueColor)
+	out := buf.String()
+	if !strings.Contains(out, "\033[38;2;255;0;0m") || !strings.Contains(out, "▀") {
+		t.Errorf("Expected truecolor half blocks, got %q", out)
+	}
+	if strings.Contains(out, "MyApp") {
+		t.Error("Banner text should be replaced by the logo")
+	}
+	if lines := strings.Count(out, "\n"); lines != 2 {
+		t.Errorf("4px high logo should take 2 lines, got %d", lines)
+	}
+
+	// Without truecolor the banner text is shown
+	buf.Reset()
+	RenderBanner(&buf, config, TerminalCaps{TTY: true})
+	if buf.String() != "== MyApp ==\n" {
+		t.Errorf("Fallback banner = %q", buf.String())
+	}
+
+	// An unreadable logo also falls back
+	buf.Reset()
+	config.CLILogo = filepath.Join(t.TempDir(), "missing.png")
+	RenderBanner(&buf, config, trueColor)
+	if buf.String() != "== MyApp ==\n" {
+		t.Errorf("Fallback banner for missing logo = %q", buf.String())
+	}
+
+	// The GUI logo is drawn when no CLI logo is set
+	buf.Reset()
+	data, err := os.ReadFile(logo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config = &core.Config{CLIBanner: "== MyApp ==\n", Logo: &core.Logo{Data: data, MIMEType: "image/png"}}
+	RenderBanner(&buf, config, trueColor)
+	if !strings.Contains(buf.String(), "▀") {
+		t.Errorf("Expected the GUI logo, got %q", buf.String())
+	}
+
+	// SVG logos cannot be drawn
+	buf.Reset()
+	config.Logo = &core.Logo{Data: []byte("<svg/>"), MIMEType: "image/svg+xml"}
+	RenderBanner(&buf, config, trueColor)
+	if buf.String() != "== MyApp ==\n" {
+		t.Errorf("Fallback banner for SVG logo = %q", buf.String())
+	}
+
+	// Nothing configured, nothing written
+	buf.Reset()
+	RenderBanner(&buf, &core.Config{}, trueColor)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output, got %q", buf.String())
+	}
+}
@@ -31,7 +31,7 @@ func New() *CLI {
 func (c *CLI) Initialize(ctx *core.Context) error {
 	c.context = ctx
 	// Store installer reference for later use
-	if installer, ok := ctx.Metadata["installer"].(*core.Installer); ok {
+	if installer := ctx.Installer(); installer != nil {
 		c.installer = installer
 	}
 	return nil
@@ -75,12 +75,16 @@ func (c *CLI) Run() error {
 	c.installer.SetInstallPath(path)
 
 	// Confirm installation
-	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Println("Ready to install:")
-	fmt.Printf("  Application: %s v%s\n", c.context.Config.AppName, c.context.Config.Version)
-	fmt.Printf("  Install to: %s\n", path)
-	fmt.Printf("  Components: %d selected\n", len(components))
-	fmt.Println(strings.Repeat("=", 50))
+	style := DetectStyle()
+	fmt.Println()
+	RenderSummaryPanel(os.Stdout, "Ready to install", []string{
+		fmt.Sprintf("Application: %s v%s", c.context.Config.AppName, c.context.Config.Version),
+		fmt.Sprintf("Install to:  %s", path),
+		fmt.Sprintf("Components:  %d selected", len(components)),
+	}, style)
+	fmt.Println()
+	RenderComponentTable(os.Stdout, components, style)
+	fmt.Println()
 
 	if !c.confirm("Proceed with installation?") {
 		fmt.Println("Installation cancelled.")
@@ -90,7 +94,7 @@ func (c *CLI) Run() error {
 	// Execute installation
 	fmt.Println("\nStarting installation...")
 	c.installer.SetUI(c) // Set the UI reference on the installer
-	if err := c.installer.ExecuteInstallation(); err != nil {
+	if err := c.installer.Execute(); err != nil {
 		return err
 	}
 
@@ -104,6 +108,8 @@ func (c *CLI) Shutdown() error {
 }
 
 func (c *CLI) ShowWelcome() error {
+	RenderBanner(os.Stdout, c.context.Config, DetectTerminalCaps())
+
 	fmt.Println("\n" + strings.Repeat("=", 50))
 	fmt.Printf("  Welcome to %s Setup\n", c.context.Config.AppName)
 	fmt.Printf("  Version: %s\n", c.context.Config.Version)
@@ -148,8 +154,8 @@ func (c *CLI) SelectComponents(components []core.Component) ([]core.Component, e
 		}
 
 		fmt.Printf("  [%s] %d. %s", status, i+1, comp.Name)
-		if comp.Size > 0 {
-			fmt.Printf(" (%s)", formatSize(comp.Size))
+		if size := comp.DiskSize(); size > 0 {
+			fmt.Printf(" (%s)", formatSize(size))
 		}
 		fmt.Println()
 
@@ -202,10 +208,10 @@ func (c *CLI) SelectInstallPath(defaultPath string) (string, error) {
 		return defaultPath, nil
 	}
 
-	// Expand home directory if needed
-	if strings.HasPrefix(input, "~") {
-		home, _ := os.UserHomeDir()
-		input = filepath.Join(home, input[1:])
+	// Expand ~, variables and special folders
+	input, err := core.ExpandPath(input)
+	if err != nil {
+		return "", err
 	}
 
 	return filepath.Clean(input), nil
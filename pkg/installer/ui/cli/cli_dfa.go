@@ -3,7 +3,9 @@ package cli
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -20,14 +22,18 @@ type CLIDFA struct {
 	context    *core.Context
 	controller *controller.InstallerController
 	reader     *bufio.Reader
+	echo       echoControl        // Hides typed passwords, nil if the input is no terminal
 	renderer   *html.SSRRenderer  // For HTML export capability
+	progress   progressDisplay
 }
 
 // NewDFA creates a new DFA-controlled CLI instance
 func NewDFA() *CLIDFA {
 	return &CLIDFA{
 		reader:   bufio.NewReader(os.Stdin),
+		echo:     stdinEcho(),
 		renderer: html.NewSSRRenderer(),
+		progress: progressDisplay{tty: isTerminal(os.Stdout)},
 	}
 }
 
@@ -66,36 +72,96 @@ func (c *CLIDFA) Shutdown() error {
 
 // ShowWelcome displays the welcome screen
 func (c *CLIDFA) ShowWelcome() error {
+	RenderBanner(os.Stdout, c.context.Config, DetectTerminalCaps())
+	
 	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Printf("  Welcome to %s Setup\n", c.context.Config.AppName)
-	fmt.Printf("  Version: %s\n", c.context.Config.Version)
-	fmt.Printf("  Publisher: %s\n", c.context.Config.Publisher)
+	fmt.Printf("  %s\n", c.t(core.MsgWelcomeHeading, c.context.Config.AppName))
+	fmt.Printf("  %s\n", c.t(core.MsgWelcomeVersion, c.context.Config.Version))
+	fmt.Printf("  %s\n", c.t(core.MsgWelcomePublisher, c.context.Config.Publisher))
 	fmt.Println(strings.Repeat("=", 50))
 	fmt.Println()
+
+	if c.controller != nil {
+		if warnings := c.controller.RequirementWarnings(); len(warnings) > 0 {
+			fmt.Println("⚠️  This computer is below the recommended system requirements:")
+			for _, warning := range warnings {
+				fmt.Printf("   - %s\n", warning)
+			}
+			fmt.Println("   You can install anyway, but the application may run slowly.")
+			fmt.Println()
+		}
+	}
 	
 	// Wait for user to proceed
-	return c.waitForNext("Press Enter to continue or 'q' to quit...")
+	return c.waitForNext(c.t(core.MsgPromptContinue, c.primaryLabel(controller.StateWelcome)))
 }
 
 // ShowLicense displays license and returns acceptance
 func (c *CLIDFA) ShowLicense(license string) (accepted bool, err error) {
-	fmt.Println("License Agreement:")
+	if err := c.printLicense("License Agreement", license); err != nil {
+		return false, err
+	}
+	accepted, err = c.acceptLicense("the license agreement")
+	if err == nil && !accepted {
+		fmt.Println("License not accepted. Installation cancelled.")
+	}
+	return accepted, err
+}
+
+// ShowLicenses displays each license and asks for its acceptance on its own
+func (c *CLIDFA) ShowLicenses(licenses []core.ApplicableLicense) ([]bool, error) {
+	answers := make([]bool, len(licenses))
+	for i, l := range licenses {
+		if err := c.printLicense(l.Title, l.Text); err != nil {
+			return nil, err
+		}
+		accepted, err := c.acceptLicense("the " + l.Title)
+		if err != nil {
+			return nil, err
+		}
+		if !accepted {
+			fmt.Printf("%s not accepted.\n", l.Title)
+		}
+		answers[i] = accepted
+	}
+	return answers, nil
+}
+
+// licensePageLines is the number of license lines shown at once
+const licensePageLines = 20
+
+// printLicense shows the beginning of a license text, or all of it page by
+// page if Config.RequireLicenseScroll asks for the license to be read
+func (c *CLIDFA) printLicense(title, license string) error {
+	fmt.Println(title + ":")
 	fmt.Println(strings.Repeat("-", 50))
 	
-	// Show license text (truncated for demo)
+	paged := c.context != nil && c.context.Config.RequireLicenseScroll
 	lines := strings.Split(license, "\n")
 	for i, line := range lines {
 		fmt.Println(line)
-		if i > 0 && (i+1)%20 == 0 {
+		if (i+1)%licensePageLines != 0 || i+1 == len(lines) {
+			continue
+		}
+		if !paged {
+			// Show license text (truncated for demo)
 			fmt.Println("\n... [" + strconv.Itoa(len(lines)-i-1) + " more lines] ...")
 			break
 		}
+		fmt.Printf("-- %d more lines, press Enter to continue --", len(lines)-i-1)
+		if _, err := c.reader.ReadString('\n'); err != nil {
+			return err
+		}
 	}
 	
 	fmt.Println(strings.Repeat("-", 50))
-	
+	return nil
+}
+
+// acceptLicense asks whether the user accepts what
+func (c *CLIDFA) acceptLicense(what string) (bool, error) {
 	for {
-		fmt.Print("Do you accept the license agreement? (y/n): ")
+		fmt.Printf("Do you accept %s? (y/n): ", what)
 		input, err := c.reader.ReadString('\n')
 		if err != nil {
 			return false, err
@@ -106,7 +172,6 @@ func (c *CLIDFA) ShowLicense(license string) (accepted bool, err error) {
 		case "y", "yes":
 			return true, nil
 		case "n", "no":
-			fmt.Println("License not accepted. Installation cancelled.")
 			return false, nil
 		default:
 			fmt.Println("Please enter 'y' for yes or 'n' for no.")
@@ -119,30 +184,19 @@ func (c *CLIDFA) ShowComponents(components []core.Component) (selected []core.Co
 	fmt.Println("Select components to install:")
 	fmt.Println()
 	
-	// Clone components to avoid modifying originals
-	workingComponents := make([]core.Component, len(components))
-	copy(workingComponents, components)
-	
-	for {
-		// Display current state
-		for i, comp := range workingComponents {
-			status := " "
-			if comp.Required {
-				status = "R"
-			} else if comp.Selected {
-				status = "X"
-			}
-			
-			fmt.Printf("  [%s] %d. %s (%.1f KB)\n", status, i+1, comp.Name, float64(comp.Size)/1024)
-			if comp.Description != "" {
-				fmt.Printf("      %s\n", comp.Description)
-			}
+	available := int64(-1)
+	if c.context != nil {
+		if space, err := core.AvailableSpace(c.context.Config.InstallDir); err == nil {
+			available = space
 		}
-		
-		fmt.Println()
-		fmt.Println("  R = Required, X = Selected")
-		fmt.Println()
-		fmt.Print("Enter component numbers to toggle (comma-separated), or press Enter to continue: ")
+	}
+	selection := newComponentSelection(components, available)
+	if c.context != nil {
+		selection.presets = c.context.Config.SelectionPresets
+	}
+	for {
+		selection.render(os.Stdout)
+		fmt.Print("Enter component numbers to toggle (comma-separated), '?' for help, or press Enter to continue: ")
 		
 		input, err := c.reader.ReadString('\n')
 		if err != nil {
@@ -153,36 +207,14 @@ func (c *CLIDFA) ShowComponents(components []core.Component) (selected []core.Co
 		if input == "" {
 			break // User pressed Enter, continue with current selection
 		}
-		
-		// Parse component numbers
-		numbers := strings.Split(input, ",")
-		for _, numStr := range numbers {
-			numStr = strings.TrimSpace(numStr)
-			num, err := strconv.Atoi(numStr)
-			if err != nil || num < 1 || num > len(workingComponents) {
-				fmt.Printf("Invalid component number: %s\n", numStr)
-				continue
-			}
-			
-			idx := num - 1
-			if !workingComponents[idx].Required {
-				workingComponents[idx].Selected = !workingComponents[idx].Selected
-			} else {
-				fmt.Printf("Component %d (%s) is required and cannot be deselected.\n", num, workingComponents[idx].Name)
-			}
+		if c.showHelp(controller.StateComponents, input) {
+			continue
 		}
+		selection.apply(os.Stdout, input)
 		fmt.Println()
 	}
 	
-	// Return selected components
-	var result []core.Component
-	for _, comp := range workingComponents {
-		if comp.Selected || comp.Required {
-			result = append(result, comp)
-		}
-	}
-	
-	return result, nil
+	return selection.selected(), nil
 }
 
 // ShowInstallPath allows user to select installation path
@@ -195,54 +227,92 @@ func (c *CLIDFA) ShowInstallPath(defaultPath string) (path string, err error) {
 	}
 	
 	input = strings.TrimSpace(input)
+	if c.showHelp(controller.StateInstallPath, input) {
+		return c.ShowInstallPath(defaultPath)
+	}
 	if input == "" {
 		return defaultPath, nil
 	}
 	
-	// Expand path if needed
-	if strings.HasPrefix(input, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err == nil {
-			input = filepath.Join(homeDir, input[2:])
-		}
+	// Expand ~, variables and special folders, asking again if that fails
+	path, err = core.ExpandPath(input)
+	if err != nil {
+		fmt.Printf("❌ %v\n\n", err)
+		return c.ShowInstallPath(defaultPath)
 	}
-	
-	return input, nil
+	return path, nil
 }
 
 // ShowSummary displays installation summary and gets confirmation
 func (c *CLIDFA) ShowSummary(config *core.Config, selectedComponents []core.Component, installPath string) (proceed bool, err error) {
-	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Println("Ready to install:")
-	fmt.Printf("  Application: %s v%s\n", config.AppName, config.Version)
-	fmt.Printf("  Install to: %s\n", installPath)
-	fmt.Printf("  Components: %d selected\n", len(selectedComponents))
+	style := DetectStyle()
+	
+	fmt.Println()
+	lines := []string{
+		fmt.Sprintf("Application: %s v%s", config.AppName, config.Version),
+		fmt.Sprintf("Install to:  %s", installPath),
+		fmt.Sprintf("Components:  %d selected", len(selectedComponents)),
+	}
+	// Compressed payloads are smaller than what they expand to
+	if download, size := core.SelectedDownloadSize(selectedComponents), core.SelectedSize(selectedComponents); download != size {
+		lines = append(lines, fmt.Sprintf("Disk space:  %s (download %s)", formatSize(size), formatSize(download)))
+	}
+	RenderSummaryPanel(os.Stdout, "Ready to install", lines, style)
 	
 	// Show selected components
 	fmt.Println("\nSelected components:")
-	for _, comp := range selectedComponents {
-		marker := ""
-		if comp.Required {
-			marker = " (required)"
+	RenderComponentTable(os.Stdout, selectedComponents, style)
+	
+	// Show what modifying an existing installation changes
+	if c.controller != nil {
+		plan, err := c.controller.ModifyPlan()
+		if err != nil {
+			return false, err
+		}
+		if plan != nil {
+			fmt.Println("\nChanges:")
+			for _, line := range plan.Describe() {
+				fmt.Printf("  %s\n", line)
+			}
 		}
-		fmt.Printf("  • %s%s\n", comp.Name, marker)
 	}
 	
-	fmt.Println(strings.Repeat("=", 50))
+	if config.CreateRestorePoint && !config.Portable && core.RestorePointSupported() {
+		fmt.Println("\nA system restore point will be created before installation.")
+	}
+	
+	if config.Portable {
+		fmt.Printf("\nPortable installation: nothing outside %s is changed.\n", installPath)
+	} else if change := config.PathConfig.Describe(); change != "" {
+		fmt.Printf("\n%s.\n", change)
+	}
+	
+	if privileges := core.PrivilegesFor(config, selectedComponents, installPath); len(privileges) > 0 {
+		fmt.Println("\n" + core.PrivilegesHeading)
+		for _, p := range privileges {
+			fmt.Printf("  - %s\n", p.Description)
+		}
+		fmt.Println(core.ElevationNotice)
+	}
+	
+	fmt.Println()
 	
-	return c.confirm("Proceed with installation?"), nil
+	if !c.confirm("Proceed with installation?") {
+		return false, nil
+	}
+	
+	// Offer to continue an installation that was interrupted
+	if checkpoint := core.FindCheckpoint(installPath); checkpoint != nil && len(checkpoint.Completed) > 0 && !config.Resume {
+		fmt.Printf("\nA previous installation stopped after %d of %d components.\n", len(checkpoint.Completed), len(checkpoint.Components))
+		config.Resume = c.confirm("Resume it instead of starting over?")
+	}
+	return true, nil
 }
 
 // ShowProgress displays installation progress
 func (c *CLIDFA) ShowProgress(progress *core.Progress) error {
-	// Simple progress display
-	percentage := int(progress.OverallProgress * 100)
-	fmt.Printf("\rInstalling %s... %d%% complete", progress.ComponentName, percentage)
-	
-	if progress.OverallProgress >= 1.0 {
-		fmt.Println() // New line when complete
-	}
-	
+	// Overall progress, the current component on a second line
+	c.progress.show(os.Stdout, progress)
 	return nil
 }
 
@@ -255,13 +325,115 @@ func (c *CLIDFA) ShowComplete(summary *core.InstallSummary) error {
 	fmt.Printf("  Components installed: %d\n", len(summary.ComponentsInstalled))
 	fmt.Println(strings.Repeat("=", 50))
 	fmt.Println()
+	if len(summary.Warnings) > 0 {
+		fmt.Println("⚠️  The installation completed with warnings:")
+		for _, warning := range summary.Warnings {
+			fmt.Printf("  - %s\n", warning)
+		}
+		fmt.Println()
+	}
+	if summary.RebootRequired {
+		fmt.Println("⚠️  A restart is required to complete the installation:")
+		for _, reason := range summary.RebootReasons {
+			fmt.Printf("  - %s\n", reason)
+		}
+		fmt.Println()
+	}
 	
+	c.offerCompletionActions(summary.Actions)
+	if summary.RebootRequired {
+		c.offerReboot()
+	}
 	return nil
 }
 
+// offerReboot restarts the computer now if the user confirms, or reminds
+// them to restart later
+func (c *CLIDFA) offerReboot() {
+	if c.controller == nil {
+		return
+	}
+	if !c.confirm("Restart the computer now? Save your work in other programs first.") {
+		fmt.Println("Please restart the computer later to complete the installation.")
+		return
+	}
+	message, err := c.controller.RunCompletionAction(core.RebootActionID)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	fmt.Printf("✅ %s\n", message)
+}
+
+// offerCompletionActions lets the user run the next steps of the summary
+// until Enter is pressed without a choice
+func (c *CLIDFA) offerCompletionActions(actions []core.CompletionAction) {
+	if len(actions) == 0 || c.controller == nil {
+		return
+	}
+	for {
+		fmt.Println("Next steps:")
+		for i, action := range actions {
+			fmt.Printf("  %d) %s\n", i+1, action.Label)
+		}
+		fmt.Print("Choose a step, or press Enter to finish: ")
+
+		input, err := c.readInput()
+		if err != nil || strings.TrimSpace(input) == "" {
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(input))
+		if err != nil || n < 1 || n > len(actions) {
+			fmt.Printf("Please enter a number between 1 and %d\n\n", len(actions))
+			continue
+		}
+		message, err := c.controller.RunCompletionAction(actions[n-1].ID)
+		if err != nil {
+			fmt.Printf("❌ %v\n\n", err)
+			continue
+		}
+		fmt.Printf("✅ %s\n\n", message)
+	}
+}
+
 // ShowErrorMessage displays an error message (InstallerView interface)
 func (c *CLIDFA) ShowErrorMessage(err error) error {
-	fmt.Printf("\n❌ Error: %v\n\n", err)
+	writeError(os.Stdout, err)
+	return nil
+}
+
+// writeError prints err followed by what the user can do about it, if known
+func writeError(w io.Writer, err error) {
+	fmt.Fprintf(w, "\n❌ Error: %v\n", err)
+	var installErr *core.InstallError
+	if errors.As(err, &installErr) && installErr.RolledBack {
+		fmt.Fprintln(w, "   The changes made so far have been undone.")
+	}
+	if hint := core.ErrorHint(err); hint != "" {
+		fmt.Fprintf(w, "\n👉 %s\n", hint)
+	}
+	fmt.Fprintln(w)
+}
+
+// ShowFieldErrors lists the invalid fields of the current step (InstallerView interface)
+func (c *CLIDFA) ShowFieldErrors(result controller.ValidationResult) error {
+	fmt.Printf("\n❌ Please correct the following:\n")
+	for _, e := range result {
+		fmt.Printf("   %s: %s\n", e.Field, e.Message)
+	}
+	fmt.Println()
+	return nil
+}
+
+// Confirm asks a yes/no question (InstallerView interface)
+func (c *CLIDFA) Confirm(title, message string) (bool, error) {
+	fmt.Printf("\n%s\n", title)
+	return c.confirm(message), nil
+}
+
+// ShowHelp prints the help of the current step (InstallerView interface)
+func (c *CLIDFA) ShowHelp(title, help string) error {
+	fmt.Printf("\n%s\n%s\n%s\n\n", title, strings.Repeat("-", len(title)), help)
 	return nil
 }
 
@@ -298,7 +470,7 @@ func (c *CLIDFA) ShowSuccess(summary *core.InstallSummary) error {
 
 // ShowError - core.UI interface method with retry logic
 func (c *CLIDFA) ShowError(err error, canRetry bool) (retry bool, errOut error) {
-	fmt.Printf("\n❌ Error: %v\n\n", err)
+	writeError(os.Stdout, err)
 	
 	if canRetry {
 		return c.confirm("Would you like to retry?"), nil
@@ -311,6 +483,42 @@ func (c *CLIDFA) ShowError(err error, canRetry bool) (retry bool, errOut error)
 // Helper Methods
 // ============================================================================
 
+// primaryLabel returns the label of the primary action in state
+func (c *CLIDFA) primaryLabel(state wizard.State) string {
+	if c.controller == nil {
+		return wizard.DefaultPrimaryLabel
+	}
+	return c.controller.PrimaryLabelFor(state)
+}
+
+// t translates the message key into the locale of the installer, see
+// core.Localizer.T
+func (c *CLIDFA) t(key string, args ...interface{}) string {
+	var localizer *core.Localizer
+	if c.controller != nil {
+		localizer = c.controller.Localizer()
+	}
+	return localizer.T(key, args...)
+}
+
+// showHelp prints the help of state if input asks for it with '?' and
+// reports whether it did
+func (c *CLIDFA) showHelp(state wizard.State, input string) bool {
+	if input != "?" {
+		return false
+	}
+	help := ""
+	if c.controller != nil {
+		help = c.controller.HelpFor(state)
+	}
+	if help == "" {
+		fmt.Println("No help is available for this step.")
+		return true
+	}
+	c.ShowHelp("Help", help)
+	return true
+}
+
 // waitForNext waits for user input to proceed
 func (c *CLIDFA) waitForNext(message string) error {
 	fmt.Print(message + " ")
@@ -322,7 +530,10 @@ func (c *CLIDFA) waitForNext(message string) error {
 
 	input = strings.TrimSpace(strings.ToLower(input))
 	if input == "q" || input == "quit" {
-		return fmt.Errorf("installation cancelled by user")
+		if quit, _ := c.Confirm("Quit setup", "Do you want to quit the installation?"); quit {
+			return fmt.Errorf("installation cancelled by user")
+		}
+		return c.waitForNext(message)
 	}
 
 	// After user confirms, advance to next state via DFA controller
@@ -365,24 +576,28 @@ func (c *CLIDFA) ExportHTMLPages(outputDir string) error {
 	}
 	
 	// Export welcome page
+	c.renderer.SetPrimaryLabel(c.primaryLabel(controller.StateWelcome))
 	welcomeDoc := c.renderer.RenderWelcomePage(c.context.Config)
 	if err := c.writeHTMLFile(filepath.Join(outputDir, "welcome.html"), welcomeDoc.Render()); err != nil {
 		return err
 	}
 	
 	// Export components page
+	c.renderer.SetPrimaryLabel(c.primaryLabel(controller.StateComponents))
 	componentsDoc := c.renderer.RenderComponentsPage(c.context.Config)
 	if err := c.writeHTMLFile(filepath.Join(outputDir, "components.html"), componentsDoc.Render()); err != nil {
 		return err
 	}
 	
 	// Export progress page
+	c.renderer.SetPrimaryLabel(c.primaryLabel(controller.StateProgress))
 	progressDoc := c.renderer.RenderProgressPage(c.context.Config, 50, "Installing...")
 	if err := c.writeHTMLFile(filepath.Join(outputDir, "progress.html"), progressDoc.Render()); err != nil {
 		return err
 	}
 	
 	// Export completion page
+	c.renderer.SetPrimaryLabel(c.primaryLabel(controller.StateComplete))
 	completeDoc := c.renderer.RenderCompletionPage(c.context.Config, true)
 	if err := c.writeHTMLFile(filepath.Join(outputDir, "complete.html"), completeDoc.Render()); err != nil {
 		return err
@@ -403,6 +618,21 @@ func (c *CLIDFA) writeHTMLFile(filename, content string) error {
 
 // ShowCustomState handles custom states in CLI mode
 func (c *CLIDFA) ShowCustomState(stateID wizard.State, data controller.CustomStateData) (controller.CustomStateData, error) {
+	if form, ok := data["form"].(*core.UIStateConfig); ok {
+		return c.showForm(stateID, form, data)
+	}
+
+	switch stateID {
+	case controller.StateLanguage:
+		return c.showLanguage(data)
+	case controller.StateReleaseNotes:
+		return data, c.showReleaseNotes(data)
+	case controller.StatePathScope:
+		return c.showPathScope(data)
+	case controller.StateInstallScope:
+		return c.showInstallScope(data)
+	}
+
 	fmt.Printf("\n=== Custom Configuration: %s ===\n", stateID)
 
 	switch stateID {
@@ -414,6 +644,163 @@ func (c *CLIDFA) ShowCustomState(stateID wizard.State, data controller.CustomSta
 	}
 }
 
+// showLanguage lets the user pick the locale of the installer by number.
+// The installer switches to it before the next screen is shown.
+func (c *CLIDFA) showLanguage(data controller.CustomStateData) (controller.CustomStateData, error) {
+	locales, _ := data["locales"].([]string)
+	current, _ := data["locale"].(string)
+
+	var localizer *core.Localizer
+	if c.controller != nil {
+		localizer = c.controller.Localizer()
+	}
+	fmt.Printf("\n%s\n", c.t(core.MsgLanguageTitle))
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(c.t(core.MsgLanguagePrompt))
+	for i, locale := range locales {
+		marker := " "
+		if locale == current {
+			marker = "*"
+		}
+		fmt.Printf(" %s %d. %s\n", marker, i+1, localizer.Name(locale))
+	}
+
+	for {
+		fmt.Printf("Language [1-%d, Enter keeps %s]: ", len(locales), localizer.Name(current))
+		input, err := c.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			break
+		}
+		if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(locales) {
+			current = locales[n-1]
+			break
+		}
+		fmt.Printf("Please enter a number between 1 and %d.\n", len(locales))
+	}
+
+	if c.controller != nil {
+		if err := c.controller.SetLocale(current); err != nil {
+			return nil, err
+		}
+		go func() {
+			if err := c.controller.Next(); err != nil {
+				fmt.Printf("Error advancing to next state: %v\n", err)
+			}
+		}()
+	}
+	return controller.CustomStateData{"locale": current}, nil
+}
+
+// showPathScope lets the user choose between the user and the system PATH
+func (c *CLIDFA) showPathScope(data controller.CustomStateData) (controller.CustomStateData, error) {
+	dirs, _ := data["dirs"].([]string)
+	current, _ := data["scope"].(string)
+	elevated, _ := data["elevated"].(bool)
+
+	fmt.Println("\nPATH")
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Printf("The installer adds %s to PATH.\n", strings.Join(dirs, ", "))
+	fmt.Println("  1. User PATH   - only for your account")
+	fmt.Println("  2. System PATH - for all users, needs administrator rights")
+	if !elevated {
+		fmt.Println("You will be asked to allow elevation if you choose the system PATH.")
+	}
+
+	scopes := []string{core.PathScopeNameUser, core.PathScopeNameSystem}
+	for {
+		fmt.Printf("PATH [1-2, Enter keeps %s]: ", current)
+		input, err := c.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			break
+		}
+		if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(scopes) {
+			current = scopes[n-1]
+			break
+		}
+		fmt.Println("Please enter 1 or 2.")
+	}
+
+	if c.controller != nil {
+		if err := c.controller.SetPathScope(current); err != nil {
+			return nil, err
+		}
+		go func() {
+			if err := c.controller.Next(); err != nil {
+				fmt.Printf("Error advancing to next state: %v\n", err)
+			}
+		}()
+	}
+	return controller.CustomStateData{"scope": current}, nil
+}
+
+// showInstallScope lets the user choose between installing for all users and
+// just for themselves by number
+func (c *CLIDFA) showInstallScope(data controller.CustomStateData) (controller.CustomStateData, error) {
+	current, _ := data["scope"].(string)
+	elevated, _ := data["elevated"].(bool)
+
+	fmt.Println("\nInstallation Scope")
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Printf("Install %s for all users of this computer or just for you?\n", c.context.Config.AppName)
+	fmt.Println("  1. All users - needs administrator rights")
+	fmt.Println("  2. Just me   - only for your account")
+	if !elevated {
+		fmt.Println("You will be asked to allow elevation if you install for all users.")
+	}
+
+	scopes := []string{core.ScopePerMachine.String(), core.ScopePerUser.String()}
+	for {
+		fmt.Printf("Scope [1-2, Enter keeps %s]: ", current)
+		input, err := c.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			break
+		}
+		if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(scopes) {
+			current = scopes[n-1]
+			break
+		}
+		fmt.Println("Please enter 1 or 2.")
+	}
+
+	if c.controller != nil {
+		if err := c.controller.SetInstallScope(current); err != nil {
+			return nil, err
+		}
+		go func() {
+			if err := c.controller.Next(); err != nil {
+				fmt.Printf("Error advancing to next state: %v\n", err)
+			}
+		}()
+	}
+	return controller.CustomStateData{"scope": current}, nil
+}
+
+// showReleaseNotes shows the changes since the installed version as plain text
+func (c *CLIDFA) showReleaseNotes(data controller.CustomStateData) error {
+	installed, _ := data["installed_version"].(string)
+	notes, _ := data["notes"].(string)
+
+	fmt.Printf("\nWhat's New in %s %s\n", c.context.Config.AppName, c.context.Config.Version)
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Printf("Updating from version %s\n\n", installed)
+	fmt.Println(markdownText(notes))
+	fmt.Println(strings.Repeat("-", 50))
+
+	return c.waitForNext(fmt.Sprintf("Press Enter for %s or 'q' to quit...", c.primaryLabel(controller.StateReleaseNotes)))
+}
+
 // handleDatabaseConfig handles database configuration in CLI mode
 func (c *CLIDFA) handleDatabaseConfig(data controller.CustomStateData) (controller.CustomStateData, error) {
 	fmt.Println("Database Configuration")
@@ -479,9 +866,12 @@ func (c *CLIDFA) handleDatabaseConfig(data controller.CustomStateData) (controll
 			newConfig.Username = strings.TrimSpace(input)
 		}
 
-		fmt.Print("Password: ")
-		if input, err := c.readInput(); err == nil {
-			newConfig.Password = strings.TrimSpace(input)
+		prompt := "Password: "
+		if newConfig.Password != "" {
+			prompt = "Password [unchanged]: "
+		}
+		if input, err := readSecret(c.reader, os.Stdout, prompt, c.echo); err == nil && input != "" {
+			newConfig.Password = input
 		}
 
 		fmt.Printf("Use SSL [%v]: ", newConfig.UseSSL)
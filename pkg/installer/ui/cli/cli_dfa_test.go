@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmso2016/setupkit/pkg/installer/controller"
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func TestCLIConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"maybe\nno\n", false},
+		{"", false}, // End of input
+	}
+	for _, tt := range tests {
+		c := NewDFAWithReader(bufio.NewReader(strings.NewReader(tt.input)))
+		got, err := c.Confirm("Cancel installation", "Are you sure?")
+		if err != nil {
+			t.Fatalf("Confirm(%q) error = %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("Confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCLIQuitNeedsConfirmation(t *testing.T) {
+	c := NewDFAWithReader(bufio.NewReader(strings.NewReader("q\nn\nq\ny\n")))
+	if err := c.waitForNext("Press Enter"); err == nil {
+		t.Fatal("waitForNext() succeeded, want the confirmed quit")
+	}
+
+	c = NewDFAWithReader(bufio.NewReader(strings.NewReader("q\nn\n\n")))
+	if err := c.waitForNext("Press Enter"); err != nil {
+		t.Errorf("waitForNext() error = %v, want to continue after the declined quit", err)
+	}
+}
+
+func TestCLIHelp(t *testing.T) {
+	config := &core.Config{AppName: "HelpApp"}
+	c := NewDFAWithReader(bufio.NewReader(strings.NewReader("?\n/opt/helpapp\n")))
+	c.SetController(controller.NewInstallerController(config, core.New(config)))
+
+	if !c.showHelp(controller.StateInstallPath, "?") {
+		t.Error("'?' should show the help")
+	}
+	if c.showHelp(controller.StateInstallPath, "/opt/app") {
+		t.Error("a path should not show the help")
+	}
+
+	// Asking for help repeats the question
+	path, err := c.ShowInstallPath("/opt/default")
+	if err != nil {
+		t.Fatalf("ShowInstallPath() error = %v", err)
+	}
+	if path != "/opt/helpapp" {
+		t.Errorf("ShowInstallPath() = %q, want the path entered after the help", path)
+	}
+}
+
+func TestCLIStartsWithController(t *testing.T) {
+	config := &core.Config{
+		AppName:    "StartApp",
+		Components: []core.Component{{ID: "core", Name: "Core", Required: true, Selected: true}},
+	}
+	c := NewDFAWithReader(bufio.NewReader(strings.NewReader("\n")))
+	if err := c.Initialize(&core.Context{Config: config, Logger: core.NewLogger("error", "")}); err != nil {
+		t.Fatal(err)
+	}
+	ic := controller.NewInstallerController(config, core.New(config))
+	ic.SetView(c)
+	c.SetController(ic)
+
+	// The welcome screen reads the button label while the DFA is entering the state
+	started := make(chan error, 1)
+	go func() { started <- ic.Start() }()
+	select {
+	case err := <-started:
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start() did not return, the welcome screen blocked on the controller")
+	}
+}
+
+func TestCLICompletionActions(t *testing.T) {
+	config := &core.Config{
+		AppName: "NextApp",
+		CompletionActions: []core.CompletionAction{
+			{ID: "docs", Label: "Open documentation", Kind: core.ActionOpenURL, Target: "https://example.com"},
+			{ID: "launch", Label: "Launch NextApp", Kind: core.ActionLaunch, Target: "nextapp"},
+		},
+	}
+	inst := core.New(config)
+	var invoked []string
+	record := func(i *core.Installer, action core.CompletionAction) (string, error) {
+		invoked = append(invoked, action.ID)
+		return "ran " + action.ID, nil
+	}
+	inst.SetCompletionHandler(core.ActionOpenURL, record)
+	inst.SetCompletionHandler(core.ActionLaunch, record)
+
+	c := NewDFAWithReader(bufio.NewReader(strings.NewReader("2\n7\n1\n\n")))
+	c.SetController(controller.NewInstallerController(config, inst))
+	c.offerCompletionActions(core.CompletionActions(config))
+
+	if got := strings.Join(invoked, ","); got != "launch,docs" {
+		t.Errorf("invoked %s, want launch,docs", got)
+	}
+}
+
+func TestCLIOfferReboot(t *testing.T) {
+	config := &core.Config{AppName: "RebootApp"}
+	inst := core.New(config)
+	inst.RequireReboot("Driver was installed")
+	restarts := 0
+	inst.SetCompletionHandler(core.ActionReboot, func(i *core.Installer, action core.CompletionAction) (string, error) {
+		restarts++
+		return "Restarting", nil
+	})
+
+	// Declined, then confirmed
+	c := NewDFAWithReader(bufio.NewReader(strings.NewReader("n\ny\n")))
+	c.SetController(controller.NewInstallerController(config, inst))
+	c.offerReboot()
+	if restarts != 0 {
+		t.Fatal("restarted without confirmation")
+	}
+	c.offerReboot()
+	if restarts != 1 {
+		t.Errorf("restarted %d times after confirmation, want 1", restarts)
+	}
+}
+
+func TestCLIShowLicensesAsksEach(t *testing.T) {
+	c := NewDFAWithReader(bufio.NewReader(strings.NewReader("y\nmaybe\nn\n")))
+	answers, err := c.ShowLicenses([]core.ApplicableLicense{
+		{ComponentID: "gpl-tools", Title: "GPL Tools License", Text: "GPL"},
+		{ComponentID: "apache-lib", Title: "Apache Lib License", Text: "Apache"},
+	})
+	if err != nil {
+		t.Fatalf("ShowLicenses() error = %v", err)
+	}
+	if len(answers) != 2 || !answers[0] || answers[1] {
+		t.Errorf("ShowLicenses() = %v, want [true false]", answers)
+	}
+}
+
+func TestCLILicenseScrollPagesThrough(t *testing.T) {
+	license := strings.Repeat("Clause\n", 44) + "Last clause"
+	newCLI := func(input string) *CLIDFA {
+		c := NewDFAWithReader(bufio.NewReader(strings.NewReader(input)))
+		c.Initialize(&core.Context{Config: &core.Config{RequireLicenseScroll: true}})
+		return c
+	}
+
+	// Two pages to continue through, then the confirmation
+	accepted, err := newCLI("\n\ny\n").ShowLicense(license)
+	if err != nil || !accepted {
+		t.Errorf("ShowLicense() = %v, %v, want the license accepted after the last page", accepted, err)
+	}
+
+	// An answer while paging only turns the page
+	if accepted, err := newCLI("y\n").ShowLicense(license); err == nil || accepted {
+		t.Errorf("ShowLicense() = %v, %v, want no acceptance before the last page", accepted, err)
+	}
+
+	// Without the option the text is shortened and accepted at once
+	c := NewDFAWithReader(bufio.NewReader(strings.NewReader("y\n")))
+	c.Initialize(&core.Context{Config: &core.Config{}})
+	if accepted, err := c.ShowLicense(license); err != nil || !accepted {
+		t.Errorf("ShowLicense() = %v, %v, want accepted without paging", accepted, err)
+	}
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// componentSelection is the state of the interactive component list
+type componentSelection struct {
+	components []core.Component
+	groups     []core.ComponentGroup // Empty when no component has a category
+	collapsed  map[int]bool          // Collapsed groups by position
+	available  int64                 // Free space on the target volume, -1 if unknown
+	presets    map[string][]string   // Quick selections by name, see core.ApplyPreset
+	filter     string                // Shows only matching components if set, see core.Component.MatchesFilter
+}
+
+// newComponentSelection copies components so the originals stay untouched.
+// available is the free space on the target volume or -1 if unknown.
+func newComponentSelection(components []core.Component, available int64) *componentSelection {
+	s := &componentSelection{
+		components: append([]core.Component(nil), components...),
+		collapsed:  make(map[int]bool),
+		available:  available,
+	}
+	if core.HasCategories(components) {
+		s.groups = core.GroupComponents(components)
+	}
+	return s
+}
+
+// groupLetter returns the letter that addresses a category
+func groupLetter(pos int) string {
+	return string(rune('A' + pos))
+}
+
+// render writes the component list, grouped under category headers if any.
+// A filter leaves out the components that do not match and the categories
+// without a match; components keep their numbers.
+func (s *componentSelection) render(w io.Writer) {
+	if s.filter != "" {
+		fmt.Fprintf(w, "  Filter: %q (enter / to clear)\n", s.filter)
+	}
+	if len(s.groups) == 0 {
+		for idx := range s.components {
+			if s.components[idx].MatchesFilter(s.filter) {
+				s.renderComponent(w, idx, "  ")
+			}
+		}
+	} else {
+		for pos, group := range s.groups {
+			var matching []int
+			for _, idx := range group.Indices {
+				if s.components[idx].MatchesFilter(s.filter) {
+					matching = append(matching, idx)
+				}
+			}
+			if len(matching) == 0 {
+				continue
+			}
+			marker := "[-]"
+			if s.collapsed[pos] {
+				marker = "[+]"
+			}
+			selected := 0
+			for _, idx := range group.Indices {
+				if s.components[idx].Selected || s.components[idx].Required {
+					selected++
+				}
+			}
+			fmt.Fprintf(w, "  %s %s. %s (%d/%d selected)\n", marker, groupLetter(pos), group.Category, selected, len(group.Indices))
+			if s.collapsed[pos] {
+				continue
+			}
+			for _, idx := range matching {
+				s.renderComponent(w, idx, "      ")
+			}
+		}
+	}
+
+	fmt.Fprintln(w)
+	s.renderSpace(w)
+	if len(s.presets) > 0 {
+		fmt.Fprintf(w, "  Presets: %s (current: %s)\n",
+			strings.Join(core.PresetNames(s.presets), ", "), core.MatchingPreset(s.components, s.presets))
+	}
+	fmt.Fprintln(w, "  R = Required, X = Selected")
+	if len(s.groups) > 0 {
+		fmt.Fprintln(w, "  Enter a category letter to select or deselect all of it, ~letter to collapse or expand it")
+	}
+	if len(s.presets) > 0 {
+		fmt.Fprintln(w, "  Enter a preset name to select its components")
+	}
+	if len(s.components) >= core.ComponentFilterThreshold {
+		fmt.Fprintln(w, "  Enter /term to show only components whose name or description contains term")
+	}
+	fmt.Fprintln(w)
+}
+
+// renderSpace writes the size of the selection and the space left, which is
+// recomputed on every render so it follows each toggle
+func (s *componentSelection) renderSpace(w io.Writer) {
+	estimate := core.EstimateSpace(s.components, s.available)
+	if estimate.Available < 0 {
+		fmt.Fprintf(w, "  Total size: %s\n", formatSize(estimate.Selected))
+		return
+	}
+	fmt.Fprintf(w, "  Total size: %s, free space: %s, remaining: %s\n",
+		formatSize(estimate.Selected), formatSize(estimate.Available), formatSignedSize(estimate.Remaining))
+	if estimate.OverBudget {
+		fmt.Fprintln(w, "  Warning: not enough disk space for this selection")
+	}
+}
+
+// formatSignedSize formats a size that may be negative
+func formatSignedSize(bytes int64) string {
+	if bytes < 0 {
+		return "-" + formatSize(-bytes)
+	}
+	return formatSize(bytes)
+}
+
+func (s *componentSelection) renderComponent(w io.Writer, idx int, indent string) {
+	comp := s.components[idx]
+	status := " "
+	if comp.Required {
+		status = "R"
+	} else if comp.Selected {
+		status = "X"
+	}
+	fmt.Fprintf(w, "%s[%s] %d. %s (%.1f KB)\n", indent, status, idx+1, comp.Name, float64(comp.DiskSize())/1024)
+	if comp.Description != "" {
+		fmt.Fprintf(w, "%s    %s\n", indent, comp.Description)
+	}
+}
+
+// apply handles comma-separated component numbers, category letters,
+// ~letter collapse toggles and preset names, writing a message for every
+// invalid entry. Input starting with / sets the filter instead, a lone /
+// clears it.
+func (s *componentSelection) apply(w io.Writer, input string) {
+	if strings.HasPrefix(input, "/") {
+		s.filter = strings.TrimSpace(input[1:])
+		return
+	}
+	for _, entry := range strings.Split(input, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if ids, ok := s.preset(entry); ok {
+			core.ApplyPreset(s.components, ids)
+			continue
+		}
+
+		if pos, ok := s.groupPosition(strings.TrimPrefix(entry, "~")); ok {
+			if strings.HasPrefix(entry, "~") {
+				s.collapsed[pos] = !s.collapsed[pos]
+			} else {
+				core.ToggleCategory(s.components, s.groups[pos])
+			}
+			continue
+		}
+
+		num, err := strconv.Atoi(entry)
+		if err != nil || num < 1 || num > len(s.components) {
+			fmt.Fprintf(w, "Invalid component number: %s\n", entry)
+			continue
+		}
+		idx := num - 1
+		if s.components[idx].Required {
+			fmt.Fprintf(w, "Component %d (%s) is required and cannot be deselected.\n", num, s.components[idx].Name)
+			continue
+		}
+		s.components[idx].Selected = !s.components[idx].Selected
+	}
+}
+
+// preset resolves a preset name, ignoring case
+func (s *componentSelection) preset(name string) ([]string, bool) {
+	for preset, ids := range s.presets {
+		if strings.EqualFold(preset, name) {
+			return ids, true
+		}
+	}
+	return nil, false
+}
+
+// groupPosition resolves a category letter
+func (s *componentSelection) groupPosition(letter string) (int, bool) {
+	if len(letter) != 1 {
+		return 0, false
+	}
+	pos := int(strings.ToUpper(letter)[0]) - 'A'
+	return pos, pos >= 0 && pos < len(s.groups)
+}
+
+// selected returns the components to install
+func (s *componentSelection) selected() []core.Component {
+	var result []core.Component
+	for _, comp := range s.components {
+		if comp.Selected || comp.Required {
+			result = append(result, comp)
+		}
+	}
+	return result
+}
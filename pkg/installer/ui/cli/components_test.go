@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func TestComponentSelectionGroups(t *testing.T) {
+	s := newComponentSelection([]core.Component{
+		{ID: "app", Name: "Application", Category: "Core", Required: true},
+		{ID: "cli", Name: "CLI Tools", Category: "Tools"},
+		{ID: "docs", Name: "Documentation"},
+		{ID: "gui", Name: "GUI Tools", Category: "Tools", Selected: true},
+	}, -1)
+
+	var out bytes.Buffer
+	s.render(&out)
+	text := out.String()
+	for _, want := range []string{"[-] A. Core (1/1 selected)", "[-] B. Tools (1/2 selected)", "[-] C. Other (0/1 selected)", "[ ] 2. CLI Tools"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Output lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "GUI Tools") > strings.Index(text, "C. Other") {
+		t.Error("Tools components should be listed under their category")
+	}
+
+	// Select all tools, try to deselect the required core group, collapse Other
+	out.Reset()
+	s.apply(&out, "b, a, ~c, 1")
+	if !strings.Contains(out.String(), "required and cannot be deselected") {
+		t.Errorf("Expected required message, got %q", out.String())
+	}
+	ids := []string{}
+	for _, comp := range s.selected() {
+		ids = append(ids, comp.ID)
+	}
+	if got := strings.Join(ids, ","); got != "app,cli,gui" {
+		t.Errorf("selected() = %s, want app,cli,gui", got)
+	}
+
+	out.Reset()
+	s.render(&out)
+	if !strings.Contains(out.String(), "[+] C. Other") || strings.Contains(out.String(), "Documentation") {
+		t.Errorf("Other category should be collapsed:\n%s", out.String())
+	}
+}
+
+func TestComponentSelectionFlat(t *testing.T) {
+	s := newComponentSelection([]core.Component{{ID: "a", Name: "Alpha"}, {ID: "b", Name: "Beta"}}, -1)
+	var out bytes.Buffer
+	s.render(&out)
+	if strings.Contains(out.String(), "Other") {
+		t.Errorf("Uncategorized list should not show a category header:\n%s", out.String())
+	}
+	s.apply(&out, "2, a, 9")
+	if len(s.selected()) != 1 || s.selected()[0].ID != "b" {
+		t.Errorf("selected() = %v, want only b", s.selected())
+	}
+	if !strings.Contains(out.String(), "Invalid component number: a") {
+		t.Errorf("Letters must be rejected without categories, got %q", out.String())
+	}
+}
+
+func TestComponentSelectionSpace(t *testing.T) {
+	s := newComponentSelection([]core.Component{
+		{ID: "app", Name: "Application", Size: 4096, Required: true},
+		{ID: "data", Name: "Sample Data", Size: 8192},
+	}, 8192)
+
+	var out bytes.Buffer
+	s.render(&out)
+	if !strings.Contains(out.String(), "Total size: 4.0 KB, free space: 8.0 KB, remaining: 4.0 KB") {
+		t.Errorf("Unexpected space line:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "Warning") {
+		t.Error("Selection fits, no warning expected")
+	}
+
+	s.apply(&out, "2")
+	out.Reset()
+	s.render(&out)
+	if !strings.Contains(out.String(), "Total size: 12.0 KB, free space: 8.0 KB, remaining: -4.0 KB") {
+		t.Errorf("Space not recomputed after toggle:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Warning: not enough disk space") {
+		t.Errorf("Expected over-budget warning:\n%s", out.String())
+	}
+}
+
+func TestComponentSelectionPresets(t *testing.T) {
+	s := newComponentSelection([]core.Component{
+		{ID: "app", Name: "Application", Required: true},
+		{ID: "docs", Name: "Documentation", Selected: true},
+		{ID: "samples", Name: "Samples", Dependencies: []string{"docs"}},
+		{ID: "tools", Name: "Tools"},
+	}, -1)
+	s.presets = map[string][]string{"Minimal": {}, "Typical": {"samples"}, "Full": {"docs", "samples", "tools"}}
+
+	var out bytes.Buffer
+	s.render(&out)
+	if !strings.Contains(out.String(), "Presets: Full, Minimal, Typical (current: Custom)") {
+		t.Errorf("Output lacks the presets:\n%s", out.String())
+	}
+
+	for _, tt := range []struct{ input, want string }{
+		{"typical", "app,docs,samples"},
+		{"Minimal", "app"},
+		{"FULL", "app,docs,samples,tools"},
+	} {
+		out.Reset()
+		s.apply(&out, tt.input)
+		ids := []string{}
+		for _, comp := range s.selected() {
+			ids = append(ids, comp.ID)
+		}
+		if got := strings.Join(ids, ","); got != tt.want {
+			t.Errorf("after %s selected() = %s, want %s", tt.input, got, tt.want)
+		}
+		if out.Len() != 0 {
+			t.Errorf("preset %s reported %q", tt.input, out.String())
+		}
+	}
+
+	out.Reset()
+	s.render(&out)
+	if !strings.Contains(out.String(), "(current: Full)") {
+		t.Errorf("Full preset should be current:\n%s", out.String())
+	}
+	s.apply(&out, "3")
+	out.Reset()
+	s.render(&out)
+	if !strings.Contains(out.String(), "(current: Custom)") {
+		t.Errorf("Deviating selection should be custom:\n%s", out.String())
+	}
+}
+
+func TestComponentSelectionFilter(t *testing.T) {
+	s := newComponentSelection([]core.Component{
+		{ID: "app", Name: "Application", Category: "Core", Required: true},
+		{ID: "pg", Name: "PostgreSQL Driver", Category: "Drivers"},
+		{ID: "mysql", Name: "MySQL Driver", Category: "Drivers"},
+		{ID: "docs", Name: "Documentation", Description: "Guides for the SQL drivers"},
+	}, -1)
+
+	var out bytes.Buffer
+	s.apply(&out, "/sql")
+	s.render(&out)
+	text := out.String()
+	for _, want := range []string{`Filter: "sql"`, "B. Drivers", "2. PostgreSQL Driver", "3. MySQL Driver", "4. Documentation"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Output lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Core") || strings.Contains(text, "Application") {
+		t.Errorf("Categories without a match should be left out:\n%s", text)
+	}
+
+	// Numbers stay those of the full list while filtered
+	s.apply(&out, "3")
+	if got := s.selected(); len(got) != 2 || got[1].ID != "mysql" {
+		t.Errorf("selected() = %v, want app and mysql", got)
+	}
+
+	out.Reset()
+	s.apply(&out, "/")
+	s.render(&out)
+	if strings.Contains(out.String(), "Filter:") || !strings.Contains(out.String(), "A. Core") {
+		t.Errorf("A lone / should clear the filter:\n%s", out.String())
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows && !linux && !darwin
+// +build !windows,!linux,!darwin
+
+package cli
+
+import (
+	"errors"
+	"os"
+)
+
+// disableEcho is not supported here; passwords are read with echo
+func disableEcho(f *os.File) (func() error, error) {
+	return nil, errors.New("turning off echo is not supported on this platform")
+}
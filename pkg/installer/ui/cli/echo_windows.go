@@ -0,0 +1,26 @@
+//go:build windows
+// +build windows
+
+package cli
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// disableEcho turns off the echo of the console f
+func disableEcho(f *os.File) (func() error, error) {
+	handle := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return nil, err
+	}
+
+	if err := windows.SetConsoleMode(handle, mode&^windows.ENABLE_ECHO_INPUT|windows.ENABLE_LINE_INPUT); err != nil {
+		return nil, err
+	}
+	return func() error {
+		return windows.SetConsoleMode(handle, mode)
+	}, nil
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mmso2016/setupkit/pkg/installer/controller"
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
+)
+
+// showForm asks for the fields of a form state and passes the values to the controller
+func (c *CLIDFA) showForm(stateID wizard.State, form *core.UIStateConfig, data controller.CustomStateData) (controller.CustomStateData, error) {
+	values, _ := data["values"].(controller.FormValues)
+	entered, err := c.readForm(os.Stdout, form, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.controller != nil {
+		if err := c.controller.SetFormValues(stateID, entered); err != nil {
+			return nil, err
+		}
+		go func() {
+			if err := c.controller.Next(); err != nil {
+				fmt.Printf("Error advancing to next state: %v\n", err)
+			}
+		}()
+	}
+	return controller.CustomStateData{"values": entered}, nil
+}
+
+// readForm prints the title of form and asks for each field in turn. Enter
+// keeps the current value; input of the wrong type repeats the question.
+func (c *CLIDFA) readForm(w io.Writer, form *core.UIStateConfig, values controller.FormValues) (controller.FormValues, error) {
+	if values == nil {
+		values = controller.FormDefaults(form)
+	}
+	fmt.Fprintf(w, "\n%s\n", form.Title)
+	fmt.Fprintln(w, strings.Repeat("-", 50))
+	if form.Description != "" {
+		fmt.Fprintln(w, form.Description)
+	}
+
+	entered := make(controller.FormValues, len(form.Fields))
+	for _, field := range form.Fields {
+		value, err := c.readField(w, field, values[field.ID])
+		if err != nil {
+			return nil, err
+		}
+		entered[field.ID] = value
+	}
+	return entered, nil
+}
+
+// readField asks for one field until the input fits its type
+func (c *CLIDFA) readField(w io.Writer, field core.UIField, current interface{}) (interface{}, error) {
+	label := field.Label
+	if label == "" {
+		label = field.ID
+	}
+	if field.Help != "" {
+		fmt.Fprintf(w, "  %s\n", field.Help)
+	}
+	options := enabledOptions(field)
+	if len(options) > 0 {
+		for i, option := range options {
+			marker := " "
+			if option.ID == current {
+				marker = "*"
+			}
+			fmt.Fprintf(w, " %s %d. %s\n", marker, i+1, optionLabel(option))
+		}
+	}
+
+	for {
+		var input string
+		var err error
+		prompt := fmt.Sprintf("%s%s [%s]: ", label, requiredMark(field), formatFieldValue(field, current))
+		if field.Type == core.FieldTypePassword {
+			if current == "" {
+				prompt = fmt.Sprintf("%s%s: ", label, requiredMark(field))
+			} else {
+				prompt = fmt.Sprintf("%s [unchanged]: ", label)
+			}
+			input, err = readSecret(c.reader, w, prompt, c.echo)
+		} else {
+			fmt.Fprint(w, prompt)
+			input, err = c.reader.ReadString('\n')
+			if err == io.EOF && input != "" {
+				err = nil
+			}
+			input = strings.TrimSpace(input)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if input == "" {
+			return current, nil
+		}
+
+		// Choices are entered by number or option ID
+		if len(options) > 0 {
+			if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(options) {
+				return options[n-1].ID, nil
+			}
+			for _, option := range options {
+				if strings.EqualFold(option.ID, input) {
+					return option.ID, nil
+				}
+			}
+			fmt.Fprintf(w, "Please enter a number between 1 and %d.\n", len(options))
+			continue
+		}
+
+		value, err := controller.ParseFieldValue(field, input)
+		if err != nil {
+			fmt.Fprintf(w, "%v.\n", err)
+			continue
+		}
+		return value, nil
+	}
+}
+
+// formatFieldValue shows a field value in a prompt
+func formatFieldValue(field core.UIField, value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "Y/n"
+		}
+		return "y/N"
+	case []string:
+		return strings.Join(v, ", ")
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func requiredMark(field core.UIField) string {
+	if field.Required {
+		return " *"
+	}
+	return ""
+}
+
+func enabledOptions(field core.UIField) []core.FieldOption {
+	var options []core.FieldOption
+	for _, option := range field.Options {
+		if !option.Disabled {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+func optionLabel(option core.FieldOption) string {
+	if option.Label != "" {
+		return option.Label
+	}
+	return option.ID
+}
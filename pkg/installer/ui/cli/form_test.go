@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/controller"
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func TestReadFormCollectsTypedValues(t *testing.T) {
+	form := &core.UIStateConfig{
+		Title:       "Database",
+		Description: "Connection to the database server",
+		Fields: []core.UIField{
+			{ID: "host", Label: "Host", Type: core.FieldTypeText, Required: true},
+			{ID: "port", Label: "Port", Type: core.FieldTypeNumber, Value: 5432},
+			{ID: "ssl", Label: "Use TLS", Type: core.FieldTypeCheckbox, Value: true, Help: "Encrypts the connection"},
+			{ID: "engine", Label: "Engine", Type: core.FieldTypeDropdown, Value: "postgres", Options: []core.FieldOption{
+				{ID: "postgres", Label: "PostgreSQL"},
+				{ID: "oracle", Label: "Oracle", Disabled: true},
+				{ID: "mysql", Label: "MySQL"},
+			}},
+			{ID: "schemas", Label: "Schemas", Type: core.FieldTypeList},
+			{ID: "password", Label: "Password", Type: core.FieldTypePassword},
+		},
+	}
+
+	// The port is entered wrong first, the engine by number
+	c := NewDFAWithReader(bufio.NewReader(strings.NewReader("db.example\n54x\n6543\nn\n2\napp, audit\n s3cret\n")))
+	var out bytes.Buffer
+	values, err := c.readForm(&out, form, nil)
+	if err != nil {
+		t.Fatalf("readForm failed: %v", err)
+	}
+
+	want := controller.FormValues{
+		"host":     "db.example",
+		"port":     6543,
+		"ssl":      false,
+		"engine":   "mysql",
+		"schemas":  []string{"app", "audit"},
+		"password": " s3cret",
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("readForm = %#v, want %#v", values, want)
+	}
+
+	text := out.String()
+	for _, want := range []string{
+		"Database\n", "Connection to the database server",
+		"Host * []: ", "Port [5432]: ", "Port must be a whole number.\n",
+		"  Encrypts the connection\n", "Use TLS [Y/n]: ",
+		" * 1. PostgreSQL\n", "   2. MySQL\n", "Engine [postgres]: ",
+		"Schemas []: ", "Password: ",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Output lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Oracle") {
+		t.Error("Disabled options should not be offered")
+	}
+}
+
+func TestReadFormKeepsCurrentValues(t *testing.T) {
+	form := &core.UIStateConfig{
+		Title: "Service",
+		Fields: []core.UIField{
+			{ID: "name", Label: "Name", Type: core.FieldTypeText},
+			{ID: "start", Label: "Start now", Type: core.FieldTypeCheckbox},
+		},
+	}
+	current := controller.FormValues{"name": "agent", "start": true}
+
+	c := NewDFAWithReader(bufio.NewReader(strings.NewReader("\n\n")))
+	var out bytes.Buffer
+	values, err := c.readForm(&out, form, current)
+	if err != nil {
+		t.Fatalf("readForm failed: %v", err)
+	}
+	if !reflect.DeepEqual(values, current) {
+		t.Errorf("readForm = %#v, want the current values %#v", values, current)
+	}
+	if !strings.Contains(out.String(), "Name [agent]: ") {
+		t.Errorf("Prompt lacks the current value:\n%s", out.String())
+	}
+}
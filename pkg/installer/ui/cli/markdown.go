@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownLink   = regexp.MustCompile(`\[([^\]]*)\]\(([^)]*)\)`)
+	markdownStrong = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownEm     = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	markdownCode   = regexp.MustCompile("`([^`]+)`")
+	markdownItem   = regexp.MustCompile(`^[-*+] +`)
+)
+
+// markdownText turns markdown such as release notes into plain text for the
+// terminal: headings are underlined, list items get bullets, code blocks are
+// indented and links show their target after the text
+func markdownText(source string) string {
+	var out []string
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, "    "+line)
+			continue
+		}
+
+		if heading := strings.TrimLeft(trimmed, "#"); heading != trimmed && strings.HasPrefix(heading, " ") {
+			heading = markdownInlineText(strings.TrimSpace(heading))
+			out = append(out, heading, strings.Repeat("-", len([]rune(heading))))
+			continue
+		}
+		if markdownItem.MatchString(trimmed) {
+			out = append(out, "  • "+markdownInlineText(markdownItem.ReplaceAllString(trimmed, "")))
+			continue
+		}
+		out = append(out, markdownInlineText(trimmed))
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// markdownInlineText removes inline markdown styles from a line
+func markdownInlineText(line string) string {
+	line = markdownLink.ReplaceAllStringFunc(line, func(link string) string {
+		m := markdownLink.FindStringSubmatch(link)
+		if m[1] == m[2] || m[1] == "" {
+			return m[2]
+		}
+		return m[1] + " (" + m[2] + ")"
+	})
+	line = markdownStrong.ReplaceAllString(line, "$1")
+	line = markdownEm.ReplaceAllString(line, "$1")
+	return markdownCode.ReplaceAllString(line, "$1")
+}
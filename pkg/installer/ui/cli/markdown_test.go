@@ -0,0 +1,22 @@
+package cli
+
+import "testing"
+
+// TestMarkdownText tests the plain text form of release notes
+func TestMarkdownText(t *testing.T) {
+	source := "## Version 2.0\n\nA **bold** and *new* release with `code`.\n\n- First\n* Second\n\n```\nrun --fast\n```\n\nSee [docs](https://example.com) or <https://example.com>."
+	want := "Version 2.0\n" +
+		"-----------\n" +
+		"\n" +
+		"A bold and new release with code.\n" +
+		"\n" +
+		"  • First\n" +
+		"  • Second\n" +
+		"\n" +
+		"    run --fast\n" +
+		"\n" +
+		"See docs (https://example.com) or <https://example.com>."
+	if got := markdownText(source); got != want {
+		t.Errorf("markdownText() =\n%s\nwant\n%s", got, want)
+	}
+}
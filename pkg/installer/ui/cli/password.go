@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// echoControl turns off the echo of typed characters and returns a function
+// restoring it
+type echoControl func() (restore func() error, err error)
+
+// stdinEcho returns the echo control of stdin, nil if stdin is not a terminal
+func stdinEcho() echoControl {
+	if !isTerminal(os.Stdin) {
+		return nil
+	}
+	return func() (func() error, error) {
+		return disableEcho(os.Stdin)
+	}
+}
+
+// readSecret prints prompt and reads a line from reader with echo turned
+// off. Without echo control, e.g. when the input is piped, the line is read
+// as is. The line ending is removed, other whitespace is kept.
+func readSecret(reader *bufio.Reader, out io.Writer, prompt string, echo echoControl) (string, error) {
+	fmt.Fprint(out, prompt)
+	if echo != nil {
+		if restore, err := echo(); err == nil {
+			defer func() {
+				restore()
+				// The Enter key was not echoed either
+				fmt.Fprintln(out)
+			}()
+		}
+	}
+
+	line, err := reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
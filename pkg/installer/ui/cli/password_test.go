@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestReadSecret tests that echo is off while the password is read and restored afterwards
+func TestReadSecret(t *testing.T) {
+	var disabled, restored int
+	echo := func() (func() error, error) {
+		disabled++
+		return func() error {
+			if disabled == 0 {
+				t.Error("restored before disabling")
+			}
+			restored++
+			return nil
+		}, nil
+	}
+
+	var out bytes.Buffer
+	got, err := readSecret(bufio.NewReader(strings.NewReader(" s3cret pass \r\nnext\n")), &out, "Password: ", echo)
+	if err != nil {
+		t.Fatalf("readSecret failed: %v", err)
+	}
+	if got != " s3cret pass " {
+		t.Errorf("readSecret = %q, want the line without its ending", got)
+	}
+	if disabled != 1 || restored != 1 {
+		t.Errorf("echo disabled %d and restored %d times, want once each", disabled, restored)
+	}
+	if out.String() != "Password: \n" {
+		t.Errorf("output = %q, want the prompt and a newline only", out.String())
+	}
+}
+
+// TestReadSecretFallback tests reading without echo control, as for piped input
+func TestReadSecretFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		echo echoControl
+	}{
+		{name: "no terminal"},
+		{name: "echo control fails", echo: func() (func() error, error) {
+			return nil, errors.New("not a console")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := readSecret(bufio.NewReader(strings.NewReader("piped")), &out, "Password: ", tt.echo)
+			if err != nil {
+				t.Fatalf("readSecret failed: %v", err)
+			}
+			if got != "piped" {
+				t.Errorf("readSecret = %q, want %q", got, "piped")
+			}
+			if out.String() != "Password: " {
+				t.Errorf("output = %q, want the prompt only", out.String())
+			}
+		})
+	}
+
+	if _, err := readSecret(bufio.NewReader(strings.NewReader("")), &bytes.Buffer{}, "Password: ", nil); err == nil {
+		t.Error("readSecret should fail on empty input")
+	}
+}
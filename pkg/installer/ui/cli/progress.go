@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// progressDisplay shows the overall progress and, on a second line, the
+// progress of the current component. On a terminal both lines are redrawn in
+// place; other output gets a line whenever the text changes. The phase of
+// the installation, if known, replaces "Installing..." on a terminal and gets
+// a line of its own otherwise.
+type progressDisplay struct {
+	tty   bool
+	drawn bool   // Both lines are on screen, the cursor at the end of the second
+	last  string // Text shown last
+	phase string // Phase shown last
+}
+
+func (d *progressDisplay) show(w io.Writer, progress *core.Progress) {
+	if !d.tty {
+		if phase := progress.PhaseStatus(); phase != d.phase {
+			if phase != "" {
+				fmt.Fprintln(w, phase)
+			}
+			d.phase = phase
+		}
+		// Outside the file copy a phase has no component progress to show
+		if progress.ComponentName == "" && progress.Phase != core.PhaseNone {
+			return
+		}
+		if text := progress.Status(); text != d.last {
+			fmt.Fprintln(w, text)
+			d.last = text
+		}
+		return
+	}
+
+	overall := fmt.Sprintf("Installing... %d%% complete", progress.OverallPercent())
+	if phase := progress.PhaseStatus(); phase != "" {
+		overall = fmt.Sprintf("%s, %d%% complete", phase, progress.OverallPercent())
+	}
+	var component string
+	if progress.ComponentName != "" {
+		component = fmt.Sprintf("  %s: %d%%", progress.ComponentName, progress.ComponentPercent())
+	}
+	done := progress.OverallProgress >= 1.0
+	if text := overall + "\n" + component; text != d.last || done {
+		if d.drawn {
+			fmt.Fprint(w, "\033[1A") // Back to the first line
+		}
+		fmt.Fprintf(w, "\r\033[2K%s\n\033[2K%s", overall, component)
+		d.drawn = true
+		d.last = text
+	}
+	if done {
+		fmt.Fprintln(w) // New line when complete
+		d.drawn = false
+		d.last = ""
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+var progressSteps = []*core.Progress{
+	{ComponentName: "Core", ComponentProgress: 0.4, OverallProgress: 0.2},
+	{ComponentName: "Core", ComponentProgress: 0.4, OverallProgress: 0.2},
+	{ComponentName: "Core", ComponentProgress: 1.0, OverallProgress: 0.5},
+	{ComponentName: "Docs", ComponentProgress: 1.0, OverallProgress: 1.0},
+}
+
+func TestProgressDisplayLines(t *testing.T) {
+	var d progressDisplay
+	var out bytes.Buffer
+	for _, p := range progressSteps {
+		d.show(&out, p)
+	}
+
+	want := "Installing Core: 40% (overall 20%)\n" +
+		"Installing Core: 100% (overall 50%)\n" +
+		"Installing Docs: 100% (overall 100%)\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestProgressDisplayTerminal(t *testing.T) {
+	d := progressDisplay{tty: true}
+	var out bytes.Buffer
+	for _, p := range progressSteps {
+		d.show(&out, p)
+	}
+
+	// Both lines are redrawn in place, a repeated report is skipped
+	want := "\r\033[2KInstalling... 20% complete\n\033[2K  Core: 40%" +
+		"\033[1A\r\033[2KInstalling... 50% complete\n\033[2K  Core: 100%" +
+		"\033[1A\r\033[2KInstalling... 100% complete\n\033[2K  Docs: 100%\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestProgressDisplayPhases(t *testing.T) {
+	var d progressDisplay
+	var out bytes.Buffer
+	steps := []*core.Progress{
+		{Phase: core.PhasePreInstall, Message: "Preparing installation..."},
+		{Phase: core.PhaseFileCopy, Message: "Installing files..."},
+		{Phase: core.PhaseFileCopy, ComponentName: "Core", ComponentProgress: 1.0, OverallProgress: 1.0},
+		{Phase: core.PhaseRegistration, OverallProgress: 1.0},
+	}
+	for _, p := range steps {
+		d.show(&out, p)
+	}
+
+	// Each phase gets a line, components their progress below
+	want := "Phase 1 of 4: Preparing installation\n" +
+		"Phase 2 of 4: Installing files\n" +
+		"Installing Core: 100% (overall 100%)\n" +
+		"Phase 3 of 4: Registering components\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+const (
+	ansiBold  = "\033[1m"
+	ansiReset = "\033[0m"
+)
+
+// Style controls how tables and panels are drawn
+type Style struct {
+	Plain bool // ASCII only, no colors
+}
+
+// DetectStyle returns the plain style when stdout is not a terminal or
+// colors are disabled through NO_COLOR
+func DetectStyle() Style {
+	return Style{Plain: !isTerminal(os.Stdout) || os.Getenv("NO_COLOR") != ""}
+}
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// RenderComponentTable writes components as an aligned Name/Size/Required
+// table followed by a totals row
+func RenderComponentTable(w io.Writer, components []core.Component, style Style) {
+	header := []string{"Name", "Size", "Required"}
+	rows := make([][]string, 0, len(components)+1)
+	var total int64
+	for _, comp := range components {
+		required := "no"
+		if comp.Required {
+			required = "yes"
+		}
+		rows = append(rows, []string{comp.Name, formatSize(comp.DiskSize()), required})
+		total += comp.DiskSize()
+	}
+	totals := []string{fmt.Sprintf("Total (%d)", len(components)), formatSize(total), ""}
+
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header, totals}, rows...) {
+		for col, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[col] {
+				widths[col] = n
+			}
+		}
+	}
+
+	rule := "-"
+	if !style.Plain {
+		rule = "─"
+	}
+	separator := "  " + strings.Repeat(rule, sumWidths(widths)+2*(len(widths)-1))
+
+	line := formatRow(header, widths)
+	if !style.Plain {
+		line = ansiBold + line + ansiReset
+	}
+	fmt.Fprintln(w, "  "+line)
+	fmt.Fprintln(w, separator)
+	for _, row := range rows {
+		fmt.Fprintln(w, "  "+formatRow(row, widths))
+	}
+	fmt.Fprintln(w, separator)
+	fmt.Fprintln(w, "  "+formatRow(totals, widths))
+}
+
+// RenderSummaryPanel writes lines inside a box with a title
+func RenderSummaryPanel(w io.Writer, title string, lines []string, style Style) {
+	width := utf8.RuneCountInString(title)
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(line); n > width {
+			width = n
+		}
+	}
+
+	horizontal, vertical, top, bottom := "─", "│", [2]string{"┌", "┐"}, [2]string{"└", "┘"}
+	if style.Plain {
+		horizontal, vertical, top, bottom = "-", "|", [2]string{"+", "+"}, [2]string{"+", "+"}
+	}
+	border := strings.Repeat(horizontal, width+2)
+
+	titleText := padRight(title, width)
+	if !style.Plain {
+		titleText = ansiBold + titleText + ansiReset
+	}
+
+	fmt.Fprintln(w, top[0]+border+top[1])
+	fmt.Fprintln(w, vertical+" "+titleText+" "+vertical)
+	fmt.Fprintln(w, vertical+border+vertical)
+	for _, line := range lines {
+		fmt.Fprintln(w, vertical+" "+padRight(line, width)+" "+vertical)
+	}
+	fmt.Fprintln(w, bottom[0]+border+bottom[1])
+}
+
+// formatRow pads cells to the column widths; sizes are right aligned
+func formatRow(cells []string, widths []int) string {
+	parts := make([]string, len(cells))
+	for col, cell := range cells {
+		if col == 1 {
+			parts[col] = padLeft(cell, widths[col])
+		} else {
+			parts[col] = padRight(cell, widths[col])
+		}
+	}
+	return strings.TrimRight(strings.Join(parts, "  "), " ")
+}
+
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+}
+
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s))) + s
+}
+
+func sumWidths(widths []int) int {
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+	return total
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// TestRenderComponentTable tests column alignment and the totals row
+func TestRenderComponentTable(t *testing.T) {
+	components := []core.Component{
+		{ID: "core", Name: "Core Files", Size: 10 * 1024 * 1024, Required: true},
+		{ID: "docs", Name: "Documentation", Size: 1536 * 1024},
+		{ID: "ex", Name: "Examples", Size: 2 * 1024 * 1024},
+	}
+
+	var buf bytes.Buffer
+	RenderComponentTable(&buf, components, Style{Plain: true})
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+
+	// header, rule, 3 rows, rule, totals
+	if len(lines) != 7 {
+		t.Fatalf("Expected 7 lines, got %d:\n%s", len(lines), buf.String())
+	}
+
+	// Size column ends at the same offset on every row; Required starts at the same offset
+	sizes := map[int]string{0: "Size", 2: "10.0 MB", 3: "1.5 MB", 4: "2.0 MB", 6: "13.5 MB"}
+	sizeEnd := strings.Index(lines[0], "Size") + len("Size")
+	for idx, size := range sizes {
+		if end := strings.Index(lines[idx], size) + len(size); end != sizeEnd {
+			t.Errorf("Size column misaligned in %q", lines[idx])
+		}
+	}
+	requiredStart := strings.Index(lines[0], "Required")
+	for _, idx := range []int{2, 3, 4} {
+		want := "no"
+		if idx == 2 {
+			want = "yes"
+		}
+		if got := strings.TrimSpace(lines[idx][requiredStart:]); got != want {
+			t.Errorf("Required column in %q = %q, want %q", lines[idx], got, want)
+		}
+	}
+
+	totals := lines[6]
+	if !strings.HasPrefix(strings.TrimSpace(totals), "Total (3)") {
+		t.Errorf("Totals row = %q", totals)
+	}
+	if !strings.Contains(totals, "13.5 MB") {
+		t.Errorf("Totals row should sum sizes, got %q", totals)
+	}
+	if strings.Contains(buf.String(), "\033[") {
+		t.Error("Plain style must not contain escape codes")
+	}
+}
+
+// TestRenderSummaryPanel tests that the panel box is closed on every line
+func TestRenderSummaryPanel(t *testing.T) {
+	for _, style := range []Style{{Plain: true}, {Plain: false}} {
+		var buf bytes.Buffer
+		RenderSummaryPanel(&buf, "Ready to install", []string{"Application: App v1.0", "Install to:  /opt/app"}, style)
+
+		lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+		if len(lines) != 6 {
+			t.Fatalf("Expected 6 lines, got %d:\n%s", len(lines), buf.String())
+		}
+		width := -1
+		for _, line := range lines {
+			line = strings.NewReplacer(ansiBold, "", ansiReset, "").Replace(line)
+			n := utf8.RuneCountInString(line)
+			if width == -1 {
+				width = n
+			} else if n != width {
+				t.Errorf("Panel line %q has width %d, want %d", line, n, width)
+			}
+		}
+	}
+}
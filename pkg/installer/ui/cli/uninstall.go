@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mmso2016/setupkit/pkg/installer/controller"
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// Ensure CLIDFA can drive the uninstall flow
+var _ controller.UninstallView = (*CLIDFA)(nil)
+
+// ShowRemovalPlan lists what the uninstall removes and asks about modified files
+func (c *CLIDFA) ShowRemovalPlan(plan *core.RemovalPlan) (proceed bool, opts core.UninstallOptions, err error) {
+	lines := []string{fmt.Sprintf("Location: %s", plan.InstallDir)}
+	for _, f := range plan.Files {
+		if f.Missing {
+			continue
+		}
+		marker := ""
+		if f.Modified {
+			marker = "  (modified)"
+		}
+		if f.Preserved {
+			marker += "  (kept unless user data is removed)"
+		}
+		lines = append(lines, "file      "+f.Path+marker)
+	}
+	for _, group := range []struct {
+		label   string
+		entries []string
+	}{
+		{"PATH      ", plan.PathEntries},
+		{"registry  ", plan.RegistryKeys},
+		{"shortcut  ", plan.Shortcuts},
+		{"service   ", plan.Services},
+	} {
+		for _, entry := range group.entries {
+			lines = append(lines, group.label+entry)
+		}
+	}
+
+	fmt.Println()
+	RenderSummaryPanel(os.Stdout, "The following will be removed", lines, DetectStyle())
+
+	for _, warning := range plan.Warnings {
+		fmt.Printf("⚠ %s\n", warning)
+	}
+	if len(plan.ModifiedFiles()) > 0 {
+		opts.KeepModified = c.confirm("Keep files that were modified after installation?")
+	}
+	if len(plan.UserData) > 0 {
+		fmt.Printf("%d file(s) in %s were not created by the installer.\n", len(plan.UserData), plan.InstallDir)
+		opts.RemoveUserData = c.confirmDefaultNo("Remove user data as well?")
+	}
+	return c.confirm("Proceed with uninstall?"), opts, nil
+}
+
+// confirmDefaultNo asks a yes/no question where an empty answer means no
+func (c *CLIDFA) confirmDefaultNo(message string) bool {
+	fmt.Print(message + " (y/N): ")
+	input, err := c.reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	switch strings.TrimSpace(strings.ToLower(input)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// ShowUninstallComplete reports the finished uninstall
+func (c *CLIDFA) ShowUninstallComplete(plan *core.RemovalPlan) error {
+	fmt.Printf("\n✅ Uninstall completed. %d component(s) removed from %s\n\n", len(plan.Components), plan.InstallDir)
+	return nil
+}
@@ -0,0 +1,92 @@
+//go:build !nogui
+// +build !nogui
+
+package ui
+
+import "sync"
+
+// pendingConfirm holds the question a GUI shows until the user answers it
+type pendingConfirm struct {
+	mu      sync.Mutex
+	title   string
+	message string
+	answer  chan bool
+	shown   chan struct{} // Signalled when a question is opened
+}
+
+func newPendingConfirm() *pendingConfirm {
+	return &pendingConfirm{shown: make(chan struct{}, 1)}
+}
+
+// ask opens a question, calls show to display it and waits for the answer
+func (p *pendingConfirm) ask(title, message string, show func()) bool {
+	answer := make(chan bool, 1)
+	p.mu.Lock()
+	p.title, p.message, p.answer = title, message, answer
+	p.mu.Unlock()
+
+	select {
+	case p.shown <- struct{}{}:
+	default:
+	}
+	show()
+
+	result := <-answer
+	p.mu.Lock()
+	p.answer = nil
+	p.mu.Unlock()
+	return result
+}
+
+// current returns the open question
+func (p *pendingConfirm) current() (title, message string, open bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.title, p.message, p.answer != nil
+}
+
+// reply answers the open question and reports whether there was one
+func (p *pendingConfirm) reply(yes bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.answer == nil {
+		return false
+	}
+	p.answer <- yes
+	p.answer = nil
+	return true
+}
+
+// resetShown forgets questions opened before an action
+func (p *pendingConfirm) resetShown() {
+	select {
+	case <-p.shown:
+	default:
+	}
+}
+
+// helpDialog holds the help a GUI shows until the user closes it
+type helpDialog struct {
+	mu    sync.Mutex
+	title string
+	text  string
+}
+
+// open shows help in place of the current page
+func (h *helpDialog) open(title, text string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.title, h.text = title, text
+}
+
+// close returns to the current page
+func (h *helpDialog) close() {
+	h.open("", "")
+}
+
+// current returns the open help
+func (h *helpDialog) current() (title, text string, open bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.title, h.text, h.text != ""
+}
@@ -34,8 +34,8 @@ func CreateUI(mode core.Mode) (core.UI, error) {
 
 // detectBestUI determines the best UI mode for the current environment
 func detectBestUI() (core.UI, error) {
-	// Check if we're in a GUI environment
-	if HasDisplay() {
+	// Check if we're in a GUI environment that can host the WebView
+	if HasDisplay() && GUIAvailable() == nil {
 		// Try to create WebView GUI
 		ui, err := createWebViewGUI()
 		if err == nil {
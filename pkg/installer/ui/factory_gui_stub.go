@@ -13,3 +13,8 @@ import (
 func createGUI() (core.UI, error) {
 	return nil, fmt.Errorf("GUI support not compiled in")
 }
+
+// GUIAvailable reports that the native GUI is not part of this build
+func GUIAvailable() error {
+	return fmt.Errorf("GUI support not compiled in")
+}
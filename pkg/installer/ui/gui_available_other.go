@@ -0,0 +1,12 @@
+//go:build !windows && !nogui
+// +build !windows,!nogui
+
+package ui
+
+import "fmt"
+
+// GUIAvailable reports why the native GUI cannot start; it needs WebView2,
+// which is only available on Windows
+func GUIAvailable() error {
+	return fmt.Errorf("native GUI requires WebView2 on Windows")
+}
@@ -0,0 +1,23 @@
+//go:build windows && !nogui
+// +build windows,!nogui
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// GUIAvailable reports why the native GUI cannot start, or nil when the
+// WebView2 runtime is installed
+func GUIAvailable() error {
+	found, err := core.DetectWebView2()
+	if found {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("WebView2 runtime not installed")
+}
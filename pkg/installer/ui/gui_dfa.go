@@ -5,11 +5,14 @@ package ui
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os/exec"
 	"runtime"
 	"strconv"
+	"strings"
 
 	"github.com/mmso2016/setupkit/pkg/html"
 	"github.com/mmso2016/setupkit/pkg/installer/controller"
@@ -35,6 +38,15 @@ type webViewUIDFA struct {
 
 	// User input storage
 	userInputs map[string]interface{}
+
+	// Question shown instead of the current page
+	confirm *pendingConfirm
+
+	// Help shown instead of the current page
+	help helpDialog
+
+	// Failed installation shown instead of the progress
+	failure error
 }
 
 // NewGUIDFA creates a new DFA-controlled GUI instance (public interface)
@@ -55,6 +67,7 @@ func (w *webViewUIDFA) Initialize(ctx *core.Context) error {
 	w.done = make(chan struct{})
 	w.finished = make(chan struct{})
 	w.userInputs = make(map[string]interface{})
+	w.confirm = newPendingConfirm()
 
 	// Setup HTTP server
 	w.setupHTTPServer()
@@ -81,7 +94,7 @@ func (w *webViewUIDFA) Run() error {
 	}()
 
 	// Open browser
-	url := fmt.Sprintf("http://localhost:%d", w.port)
+	url := fmt.Sprintf("http://127.0.0.1:%d", w.port)
 	fmt.Printf("Opening installer UI in browser: %s\n", url)
 
 	if err := w.openBrowser(url); err != nil {
@@ -134,6 +147,21 @@ func (w *webViewUIDFA) ShowLicense(license string) (accepted bool, err error) {
 	return true, nil // Default acceptance for GUI flow
 }
 
+// ShowLicenses displays the licenses with a checkbox each. It returns at
+// once with the licenses accepted before; the page posts the others to
+// handleLicense, which accepts them with InstallerController.AcceptLicenses.
+func (w *webViewUIDFA) ShowLicenses(licenses []core.ApplicableLicense) ([]bool, error) {
+	w.currentState = controller.StateLicense
+	w.userInputs["licenses"] = licenses
+	fmt.Printf("[GUI] Showing %d licenses\n", len(licenses))
+
+	accepted := make([]bool, len(licenses))
+	for i, l := range licenses {
+		accepted[i] = w.controller.IsLicenseAccepted(l.Text)
+	}
+	return accepted, nil
+}
+
 // ShowComponents displays component selection
 func (w *webViewUIDFA) ShowComponents(components []core.Component) (selected []core.Component, err error) {
 	w.currentState = controller.StateComponents
@@ -185,6 +213,8 @@ func (w *webViewUIDFA) ShowProgress(progress *core.Progress) error {
 func (w *webViewUIDFA) ShowComplete(summary *core.InstallSummary) error {
 	w.currentState = controller.StateComplete
 	fmt.Printf("[GUI] Installation completed successfully!\n")
+	w.userInputs["reboot"] = summary.RebootReasons
+	w.userInputs["warnings"] = summary.Warnings
 	
 	// Signal completion
 	go func() {
@@ -197,6 +227,31 @@ func (w *webViewUIDFA) ShowComplete(summary *core.InstallSummary) error {
 // ShowErrorMessage displays an error message (InstallerView interface)
 func (w *webViewUIDFA) ShowErrorMessage(err error) error {
 	fmt.Printf("[GUI] Error: %v\n", err)
+	var installErr *core.InstallError
+	if errors.As(err, &installErr) {
+		w.failure = err
+	}
+	return nil
+}
+
+// ShowFieldErrors displays the invalid fields of the current step (InstallerView interface)
+func (w *webViewUIDFA) ShowFieldErrors(result controller.ValidationResult) error {
+	for _, e := range result {
+		fmt.Printf("[GUI] Invalid %s: %s\n", e.Field, e.Message)
+	}
+	return nil
+}
+
+// Confirm shows a question in place of the current page and waits for the answer
+func (w *webViewUIDFA) Confirm(title, message string) (bool, error) {
+	fmt.Printf("[GUI] Confirm: %s\n", title)
+	return w.confirm.ask(title, message, func() {}), nil
+}
+
+// ShowHelp shows the help in place of the current page until it is closed
+func (w *webViewUIDFA) ShowHelp(title, help string) error {
+	fmt.Printf("[GUI] Help: %s\n", title)
+	w.help.open(title, help)
 	return nil
 }
 
@@ -250,6 +305,11 @@ func (w *webViewUIDFA) ShowCustomState(stateID wizard.State, data controller.Cus
 	w.currentState = stateID
 	w.userInputs["custom_state_data"] = data
 
+	if _, ok := data["form"].(*core.UIStateConfig); ok {
+		// Rendered from the declared fields, the values arrive at /api/form
+		return nil, nil
+	}
+
 	switch stateID {
 	case controller.StateDBConfig:
 		// Database configuration - use defaults or pre-configured values
@@ -263,6 +323,10 @@ func (w *webViewUIDFA) ShowCustomState(stateID wizard.State, data controller.Cus
 		fmt.Printf("[GUI] Using default database configuration: %s\n", defaultDB.String())
 		return controller.CustomStateData{"config": defaultDB}, nil
 
+	case controller.StateReleaseNotes, controller.StateLanguage, controller.StatePathScope, controller.StateInstallScope:
+		// Rendered via HTTP handler
+		return data, nil
+
 	default:
 		fmt.Printf("[GUI] Unknown custom state: %s\n", stateID)
 		// For GUI mode, return defaults for unknown states
@@ -285,13 +349,24 @@ func (w *webViewUIDFA) setupHTTPServer() {
 	mux.HandleFunc("/api/next", w.handleNext)
 	mux.HandleFunc("/api/prev", w.handlePrev)
 	mux.HandleFunc("/api/cancel", w.handleCancel)
+	mux.HandleFunc("/api/confirm", w.handleConfirm)
+	mux.HandleFunc("/api/help", w.handleHelp)
+	mux.HandleFunc("/api/jump", w.handleJump)
 	mux.HandleFunc("/api/finish", w.handleFinish)
+	mux.HandleFunc("/api/diagnostics", w.handleDiagnostics)
+	mux.HandleFunc("/api/action", w.handleAction)
+	mux.HandleFunc("/api/log", w.handleLog)
+	mux.HandleFunc("/api/locale", w.handleLocale)
+	mux.HandleFunc("/api/path-scope", w.handlePathScope)
+	mux.HandleFunc("/api/install-scope", w.handleInstallScope)
+	mux.HandleFunc("/api/form", w.handleForm)
 	mux.HandleFunc("/api/components", w.handleComponents)
 	mux.HandleFunc("/api/license", w.handleLicense)
 	mux.HandleFunc("/api/path", w.handlePath)
 
+	// Only this computer reaches the pages and the /api endpoints
 	w.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", w.port),
+		Addr:    fmt.Sprintf("127.0.0.1:%d", w.port),
 		Handler: mux,
 	}
 }
@@ -301,10 +376,22 @@ func (w *webViewUIDFA) setupHTTPServer() {
 func (w *webViewUIDFA) handleMainPage(wr http.ResponseWriter, req *http.Request) {
 	// Generate appropriate page based on current state
 	var doc *html.Document
+	if w.controller != nil {
+		w.renderer.SetPrimaryLabel(w.controller.PrimaryLabelFor(w.currentState))
+		w.renderer.SetHelp(w.controller.HelpFor(w.currentState))
+		w.renderer.SetWarnings(w.controller.RequirementWarnings())
+		w.renderer.SetLocalizer(w.controller.Localizer())
+	}
 
 	switch w.currentState {
+	case controller.StateLanguage:
+		data, _ := w.userInputs["custom_state_data"].(controller.CustomStateData)
+		locales, _ := data["locales"].([]string)
+		doc = w.renderer.RenderLanguagePage(w.context.Config, locales, w.controller.Localizer().Locale())
 	case controller.StateLicense:
-		if license, ok := w.userInputs["license_text"].(string); ok {
+		if licenses, ok := w.userInputs["licenses"].([]core.ApplicableLicense); ok {
+			doc = w.renderer.RenderLicensesPage(w.context.Config, licenses)
+		} else if license, ok := w.userInputs["license_text"].(string); ok {
 			doc = w.renderer.RenderLicensePage(w.context.Config, license)
 		} else {
 			doc = w.renderer.RenderLicensePage(w.context.Config, "No license text provided")
@@ -315,8 +402,23 @@ func (w *webViewUIDFA) handleMainPage(wr http.ResponseWriter, req *http.Request)
 		if defaultPath, ok := w.userInputs["default_path"].(string); ok {
 			doc = w.renderer.RenderInstallPathPage(w.context.Config, defaultPath)
 		} else {
-			doc = w.renderer.RenderInstallPathPage(w.context.Config, "/opt/"+w.context.Config.AppName)
+			doc = w.renderer.RenderInstallPathPage(w.context.Config, w.context.Config.DefaultInstallDir())
 		}
+	case controller.StatePathScope:
+		data, _ := w.userInputs["custom_state_data"].(controller.CustomStateData)
+		scope, _ := data["scope"].(string)
+		elevated, _ := data["elevated"].(bool)
+		doc = w.renderer.RenderPathScopePage(w.context.Config, scope, elevated)
+	case controller.StateInstallScope:
+		data, _ := w.userInputs["custom_state_data"].(controller.CustomStateData)
+		scope, _ := data["scope"].(string)
+		elevated, _ := data["elevated"].(bool)
+		doc = w.renderer.RenderInstallScopePage(w.context.Config, scope, elevated)
+	case controller.StateReleaseNotes:
+		notes, _ := w.userInputs["custom_state_data"].(controller.CustomStateData)
+		installed, _ := notes["installed_version"].(string)
+		text, _ := notes["notes"].(string)
+		doc = w.renderer.RenderReleaseNotesPage(w.context.Config, installed, text)
 	case controller.StateSummary:
 		var selectedComponents []core.Component
 		var installPath string
@@ -328,18 +430,43 @@ func (w *webViewUIDFA) handleMainPage(wr http.ResponseWriter, req *http.Request)
 			installPath = path
 		}
 
+		if w.controller != nil {
+			w.renderer.SetChangeLinks(changeLinks(w.controller, selectedComponents))
+			plan, _ := w.controller.ModifyPlan()
+			w.renderer.SetModifyPlan(plan)
+		}
 		doc = w.renderer.RenderSummaryPage(w.context.Config, selectedComponents, installPath)
 	case controller.StateProgress:
 		if progress, ok := w.userInputs["progress"].(*core.Progress); ok {
-			percentage := int(progress.OverallProgress * 100)
-			doc = w.renderer.RenderProgressPage(w.context.Config, percentage, progress.ComponentName)
+			doc = w.renderer.RenderInstallProgressPage(w.context.Config, progress)
 		} else {
 			doc = w.renderer.RenderProgressPage(w.context.Config, 0, "Starting...")
 		}
 	case controller.StateComplete:
+		reasons, _ := w.userInputs["reboot"].([]string)
+		w.renderer.SetReboot(reasons)
+		warnings, _ := w.userInputs["warnings"].([]string)
+		w.renderer.SetCompletionWarnings(warnings)
 		doc = w.renderer.RenderCompletionPage(w.context.Config, true)
 	default:
 		doc = w.renderer.RenderWelcomePage(w.context.Config)
+		if w.controller != nil {
+			if form := w.controller.FormFor(w.currentState); form != nil {
+				doc = w.renderer.RenderFormPage(w.context.Config, form, w.controller.FormValues(w.currentState))
+			}
+		}
+	}
+
+	if w.failure != nil {
+		doc = w.renderer.RenderErrorPage(w.context.Config, w.failure)
+	}
+
+	// Open help and questions replace the page until they are closed
+	if title, help, open := w.help.current(); open {
+		doc = w.renderer.RenderHelpDialog(w.context.Config, title, help)
+	}
+	if title, message, open := w.confirm.current(); open {
+		doc = w.renderer.RenderConfirmDialog(w.context.Config, title, message)
 	}
 
 	wr.Header().Set("Content-Type", "text/html")
@@ -349,47 +476,223 @@ func (w *webViewUIDFA) handleMainPage(wr http.ResponseWriter, req *http.Request)
 func (w *webViewUIDFA) handleNext(wr http.ResponseWriter, req *http.Request) {
 	fmt.Printf("[GUI] Next button clicked from state: %s\n", w.currentState)
 	
-	// Forward to DFA controller
-	go func() {
-		if err := w.controller.Next(); err != nil {
-			fmt.Printf("[GUI] Next transition error: %v\n", err)
-		}
-	}()
+	if err := w.applyPosted(req); err != nil {
+		http.Error(wr, err.Error(), http.StatusBadRequest)
+		return
+	}
+	
+	// Forward to DFA controller; the reload shows a question it asks
+	if err := w.await(w.controller.Next); err != nil {
+		fmt.Printf("[GUI] Next transition error: %v\n", err)
+	}
 	
-	fmt.Fprintf(wr, "{\"status\": \"ok\", \"action\": \"next\"}")
+	reply(wr, req, "{\"status\": \"ok\", \"action\": \"next\"}")
+}
+
+// applyPosted applies the choices that pages post with Next when JavaScript
+// is disabled: the locale, the PATH and installation scopes and the
+// components. Pages using fetch record them with their own endpoints.
+func (w *webViewUIDFA) applyPosted(req *http.Request) error {
+	if err := req.ParseForm(); err != nil {
+		return err
+	}
+	if locale := req.PostForm.Get("locale"); locale != "" {
+		if err := w.controller.SetLocale(locale); err != nil {
+			return err
+		}
+	}
+	if scope := req.PostForm.Get("pathScope"); scope != "" {
+		if err := w.controller.SetPathScope(scope); err != nil {
+			return err
+		}
+	}
+	if scope := req.PostForm.Get("installScope"); scope != "" {
+		if err := w.controller.SetInstallScope(scope); err != nil {
+			return err
+		}
+	}
+	// Browsers leave out unchecked boxes, so the page marks that it posted them
+	if req.PostForm.Get("components") == "posted" {
+		return w.controller.SetSelectedComponents(req.PostForm["component"])
+	}
+	return nil
 }
 
 func (w *webViewUIDFA) handlePrev(wr http.ResponseWriter, req *http.Request) {
 	fmt.Printf("[GUI] Back button clicked from state: %s\n", w.currentState)
 	
-	// Forward to DFA controller
-	go func() {
-		if err := w.controller.Back(); err != nil {
+	// Forward to DFA controller; a submitted form waits for the previous page
+	back := func() error {
+		err := w.controller.Back()
+		if err != nil {
 			fmt.Printf("[GUI] Back transition error: %v\n", err)
 		}
-	}()
+		return err
+	}
+	if formSubmitted(req) {
+		w.await(back)
+	} else {
+		go back()
+	}
 	
-	fmt.Fprintf(wr, "{\"status\": \"ok\", \"action\": \"prev\"}")
+	reply(wr, req, "{\"status\": \"ok\", \"action\": \"prev\"}")
 }
 
 func (w *webViewUIDFA) handleCancel(wr http.ResponseWriter, req *http.Request) {
 	fmt.Printf("[GUI] Cancel button clicked\n")
 	
-	// Forward to DFA controller
-	go func() {
-		if err := w.controller.Cancel(); err != nil {
-			fmt.Printf("[GUI] Cancel error: %v\n", err)
+	// Forward to DFA controller, which asks for confirmation first
+	cancelled := make(chan error, 1)
+	err := w.await(func() error {
+		err := w.controller.Cancel()
+		cancelled <- err
+		return err
+	})
+	switch {
+	case err == errConfirmShown:
+		// Finish once the question is answered
+		go func() {
+			if <-cancelled == nil {
+				w.done <- struct{}{}
+			}
+		}()
+		reply(wr, req, "{\"status\": \"confirm\"}")
+	case err != nil:
+		fmt.Printf("[GUI] Cancel error: %v\n", err)
+		reply(wr, req, "{\"status\": \"continue\"}")
+	default:
+		go func() {
+			w.done <- struct{}{}
+		}()
+		reply(wr, req, "{\"status\": \"cancelled\"}")
+	}
+}
+
+// handleConfirm receives the answer to the open question
+func (w *webViewUIDFA) handleConfirm(wr http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Answer bool `json:"answer"`
+	}
+	if formSubmitted(req) {
+		body.Answer = req.FormValue("answer") == "true"
+	} else if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(wr, "invalid answer", http.StatusBadRequest)
+		return
+	}
+	if !w.confirm.reply(body.Answer) {
+		http.Error(wr, "no open question", http.StatusConflict)
+		return
+	}
+	reply(wr, req, "{\"status\": \"ok\"}")
+}
+
+// handleHelp shows the help of the current state on POST and closes it on DELETE
+func (w *webViewUIDFA) handleHelp(wr http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPost:
+		if err := w.controller.Help(); err != nil {
+			http.Error(wr, err.Error(), http.StatusNotFound)
+			return
 		}
-		w.done <- struct{}{}
+	case http.MethodDelete:
+		w.help.close()
+	default:
+		http.Error(wr, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	fmt.Fprintf(wr, "{\"status\": \"ok\"}")
+}
+
+// handleJump returns from the summary to the step to change
+func (w *webViewUIDFA) handleJump(wr http.ResponseWriter, req *http.Request) {
+	var body struct {
+		State wizard.State `json:"state"`
+	}
+	if req.Method != http.MethodPost {
+		http.Error(wr, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(wr, "invalid state", http.StatusBadRequest)
+		return
+	}
+	if err := w.await(func() error { return w.controller.Jump(body.State) }); err != nil && err != errConfirmShown {
+		http.Error(wr, err.Error(), http.StatusConflict)
+		return
+	}
+	fmt.Fprintf(wr, "{\"status\": \"ok\"}")
+}
+
+// changeLinks returns the steps the summary offers to change
+func changeLinks(ctrl *controller.InstallerController, selected []core.Component) []html.ChangeLink {
+	var links []html.ChangeLink
+	for _, target := range ctrl.ChangeTargets(selected) {
+		link := html.ChangeLink{State: target.State, Label: target.Name}
+		switch target.State {
+		case controller.StateInstallPath:
+			link.Kind = html.ChangeInstallPath
+		case controller.StateComponents:
+			link.Kind = html.ChangeComponents
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// submitForm stores the values posted for a form state and returns the
+// fields that cannot be converted or break the field declarations
+func submitForm(ctrl *controller.InstallerController, state wizard.State, values map[string]string) controller.ValidationResult {
+	form := ctrl.FormFor(state)
+	if form == nil {
+		return controller.ValidationResult{{Field: "form", Message: fmt.Sprintf("%s is not a form state", state)}}
+	}
+	var result controller.ValidationResult
+	if err := ctrl.SetFormValues(state, values); errors.As(err, &result) {
+		return result
+	}
+	return controller.ValidateFormValues(form, ctrl.FormValues(state))
+}
+
+// formSubmitted reports whether req is a form of a page submitted by a
+// browser with JavaScript disabled, which asks for a page in return; fetch
+// asks for any content
+func formSubmitted(req *http.Request) bool {
+	return strings.Contains(req.Header.Get("Accept"), "text/html")
+}
+
+// reply answers fetch with status and a submitted form by redirecting to
+// the page of the current state
+func reply(wr http.ResponseWriter, req *http.Request, status string) {
+	if formSubmitted(req) {
+		http.Redirect(wr, req, "/", http.StatusSeeOther)
+		return
+	}
+	fmt.Fprint(wr, status)
+}
+
+// errConfirmShown reports that an action waits for the answer to a question
+var errConfirmShown = errors.New("waiting for confirmation")
+
+// await runs action and returns its result, or errConfirmShown as soon as the
+// action asks a question, leaving it to finish in the background
+func (w *webViewUIDFA) await(action func() error) error {
+	w.confirm.resetShown()
+	result := make(chan error, 1)
+	go func() {
+		result <- action()
 	}()
-	
-	fmt.Fprintf(wr, "{\"status\": \"cancelled\"}")
+	select {
+	case err := <-result:
+		return err
+	case <-w.confirm.shown:
+		return errConfirmShown
+	}
 }
 
 func (w *webViewUIDFA) handleFinish(wr http.ResponseWriter, req *http.Request) {
 	fmt.Printf("[GUI] Finish button clicked\n")
 	
-	fmt.Fprintf(wr, "{\"status\": \"finished\"}")
+	reply(wr, req, "{\"status\": \"finished\"}")
 	
 	// Signal completion
 	go func() {
@@ -397,6 +700,162 @@ func (w *webViewUIDFA) handleFinish(wr http.ResponseWriter, req *http.Request) {
 	}()
 }
 
+// handleDiagnostics saves a diagnostics archive and returns its path
+func (w *webViewUIDFA) handleDiagnostics(wr http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(wr, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	path, err := w.controller.SaveDiagnostics()
+	if err != nil {
+		http.Error(wr, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Printf("[GUI] Diagnostics saved to %s\n", path)
+	wr.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(wr).Encode(map[string]string{"path": path})
+}
+
+// handleLog serves the installer log with secrets redacted to this computer
+// only: the server listens on the loopback interface, see setupHTTPServer,
+// and requests from other addresses are refused.
+func (w *webViewUIDFA) handleLog(wr http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(wr, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !localRequest(req) {
+		http.Error(wr, "the log is only available on this computer", http.StatusForbidden)
+		return
+	}
+	log, err := w.controller.ReadLog()
+	if errors.Is(err, core.ErrNoLogFile) {
+		http.Error(wr, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(wr, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	wr.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	wr.Header().Set("Cache-Control", "no-store")
+	wr.Write(log)
+}
+
+// localRequest reports whether req comes from this computer
+func localRequest(req *http.Request) bool {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// handleAction performs a next step offered on the completion page and
+// returns its message
+func (w *webViewUIDFA) handleAction(wr http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(wr, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	message, err := w.controller.RunCompletionAction(req.FormValue("id"))
+	if err != nil {
+		http.Error(wr, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Printf("[GUI] %s\n", message)
+	wr.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(wr).Encode(map[string]string{"message": message})
+}
+
+// handleLocale switches the installer to the locale chosen on the language page
+func (w *webViewUIDFA) handleLocale(wr http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(wr, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := w.controller.SetLocale(req.FormValue("locale")); err != nil {
+		http.Error(wr, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fmt.Printf("[GUI] Locale switched to %s\n", req.FormValue("locale"))
+	fmt.Fprintf(wr, "{\"status\": \"ok\"}")
+}
+
+// handleForm records the values of a form state and reports the fields
+// that are invalid, so the page can mark them before moving on
+func (w *webViewUIDFA) handleForm(wr http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(wr, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		http.Error(wr, err.Error(), http.StatusBadRequest)
+		return
+	}
+	values := make(map[string]string, len(req.PostForm))
+	for id := range req.PostForm {
+		values[id] = req.PostForm.Get(id)
+	}
+
+	if formSubmitted(req) {
+		// Browsers leave out unchecked boxes, the page shows again with the
+		// values and moves on once they are accepted
+		if form := w.controller.FormFor(w.currentState); form != nil {
+			for _, field := range form.Fields {
+				if _, ok := values[field.ID]; !ok && field.Type == core.FieldTypeCheckbox {
+					values[field.ID] = "false"
+				}
+			}
+		}
+		if result := submitForm(w.controller, w.currentState, values); len(result) > 0 {
+			fmt.Printf("[GUI] Invalid form values: %v\n", result)
+		} else if err := w.await(w.controller.Next); err != nil {
+			fmt.Printf("[GUI] Next transition error: %v\n", err)
+		}
+		reply(wr, req, "")
+		return
+	}
+
+	wr.Header().Set("Content-Type", "application/json")
+	result := submitForm(w.controller, w.currentState, values)
+	if len(result) > 0 {
+		fmt.Printf("[GUI] Invalid form values: %v\n", result)
+		json.NewEncoder(wr).Encode(map[string]interface{}{"status": "error", "errors": result})
+		return
+	}
+	fmt.Fprintf(wr, "{\"status\": \"ok\"}")
+}
+
+// handlePathScope records the PATH chosen on the PATH page
+func (w *webViewUIDFA) handlePathScope(wr http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(wr, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := w.controller.SetPathScope(req.FormValue("scope")); err != nil {
+		http.Error(wr, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fmt.Printf("[GUI] PATH scope set to %s\n", req.FormValue("scope"))
+	fmt.Fprintf(wr, "{\"status\": \"ok\"}")
+}
+
+// handleInstallScope records the scope chosen on the installation scope page
+func (w *webViewUIDFA) handleInstallScope(wr http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(wr, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := w.controller.SetInstallScope(req.FormValue("scope")); err != nil {
+		http.Error(wr, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fmt.Printf("[GUI] Install scope set to %s\n", req.FormValue("scope"))
+	fmt.Fprintf(wr, "{\"status\": \"ok\"}")
+}
+
 func (w *webViewUIDFA) handleComponents(wr http.ResponseWriter, req *http.Request) {
 	// Return current component state as JSON
 	if components, ok := w.userInputs["available_components"].([]core.Component); ok {
@@ -416,13 +875,22 @@ func (w *webViewUIDFA) handleLicense(wr http.ResponseWriter, req *http.Request)
 		
 		fmt.Printf("[GUI] License accepted: %v\n", accepted)
 		
+		// Only the licenses of the components posted are accepted
 		if accepted {
+			if err := w.controller.AcceptLicenses(req.Form["component"]); err != nil {
+				fmt.Printf("[GUI] License acceptance error: %v\n", err)
+			}
+		}
+		
+		if accepted && formSubmitted(req) {
+			w.await(w.controller.Next)
+		} else if accepted {
 			go func() {
 				w.controller.Next()
 			}()
 		}
 		
-		fmt.Fprintf(wr, "{\"status\": \"ok\", \"accepted\": %s}", strconv.FormatBool(accepted))
+		reply(wr, req, fmt.Sprintf("{\"status\": \"ok\", \"accepted\": %s}", strconv.FormatBool(accepted)))
 	} else {
 		// Return license text
 		if license, ok := w.userInputs["license_text"].(string); ok {
@@ -446,11 +914,15 @@ func (w *webViewUIDFA) handlePath(wr http.ResponseWriter, req *http.Request) {
 		
 		fmt.Printf("[GUI] Install path selected: %s\n", path)
 		
-		go func() {
-			w.controller.Next()
-		}()
+		if formSubmitted(req) {
+			w.await(w.controller.Next)
+		} else {
+			go func() {
+				w.controller.Next()
+			}()
+		}
 		
-		fmt.Fprintf(wr, "{\"status\": \"ok\", \"path\": \"%s\"}", path)
+		reply(wr, req, fmt.Sprintf("{\"status\": \"ok\", \"path\": \"%s\"}", path))
 	} else {
 		// Return default path
 		if defaultPath, ok := w.userInputs["default_path"].(string); ok {
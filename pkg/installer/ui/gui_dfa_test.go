@@ -0,0 +1,164 @@
+//go:build !nogui
+// +build !nogui
+
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmso2016/setupkit/pkg/installer/controller"
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// newBrowserUI returns the browser UI with a started controller on the welcome
+// page, offering components besides the required core one
+func newBrowserUI(t *testing.T, components ...core.Component) *webViewUIDFA {
+	t.Helper()
+	config := &core.Config{
+		AppName:    "FormApp",
+		Version:    "1.0.0",
+		License:    "License text",
+		InstallDir: filepath.Join(t.TempDir(), "install"),
+		Components: append([]core.Component{
+			{ID: "core", Name: "Core", Required: true, Selected: true},
+		}, components...),
+	}
+	ctx := &core.Context{Config: config, Logger: core.NewLogger("error", ""), Metadata: map[string]interface{}{}}
+
+	w := &webViewUIDFA{}
+	if err := w.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := controller.NewInstallerController(config, core.New(config))
+	ctrl.SetView(w)
+	w.SetController(ctrl)
+	if err := ctrl.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// post sends values to path, as a submitted form if accept asks for a page
+func post(w *webViewUIDFA, path string, values url.Values, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", accept)
+	rec := httptest.NewRecorder()
+	w.server.Handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFormSubmissionAdvances(t *testing.T) {
+	w := newBrowserUI(t)
+
+	rec := post(w, "/api/next", url.Values{}, "text/html,application/xhtml+xml")
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/" {
+		t.Fatalf("got %d to %q, want a redirect to the page", rec.Code, rec.Header().Get("Location"))
+	}
+	if state := w.controller.GetCurrentState(); state != controller.StateLicense {
+		t.Fatalf("state = %s, want %s", state, controller.StateLicense)
+	}
+
+	rec = post(w, "/api/license", url.Values{"accepted": {"true"}}, "text/html")
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("license got %d, want a redirect", rec.Code)
+	}
+	if state := w.controller.GetCurrentState(); state == controller.StateLicense {
+		t.Fatalf("state stayed %s after accepting the license", state)
+	}
+
+	// The redirect renders the page of the new state
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	page := httptest.NewRecorder()
+	w.server.Handler.ServeHTTP(page, req)
+	if !strings.Contains(page.Body.String(), "<noscript>") {
+		t.Error("page offers no forms for browsers without JavaScript")
+	}
+}
+
+func TestComponentLicenseNeedsPostedComponent(t *testing.T) {
+	w := newBrowserUI(t, core.Component{ID: "gpl-tools", Name: "GPL Tools", License: "GPL text", Selected: true})
+	post(w, "/api/next", url.Values{}, "text/html")
+
+	post(w, "/api/license", url.Values{"accepted": {"true"}}, "text/html")
+	if state := w.controller.GetCurrentState(); state != controller.StateLicense {
+		t.Fatalf("state = %s without accepting the component license, want %s", state, controller.StateLicense)
+	}
+
+	post(w, "/api/license", url.Values{"accepted": {"true"}, "component": {"gpl-tools"}}, "text/html")
+	if state := w.controller.GetCurrentState(); state == controller.StateLicense {
+		t.Fatalf("state stayed %s after accepting the component license", state)
+	}
+}
+
+func TestFormSubmissionAppliesChoices(t *testing.T) {
+	w := newBrowserUI(t, core.Component{ID: "docs", Name: "Docs"})
+	post(w, "/api/next", url.Values{}, "text/html")
+	post(w, "/api/license", url.Values{"accepted": {"true"}}, "text/html")
+	if state := w.controller.GetCurrentState(); state != controller.StateComponents {
+		t.Fatalf("state = %s, want %s", state, controller.StateComponents)
+	}
+
+	post(w, "/api/next", url.Values{"components": {"posted"}, "component": {"docs"}}, "text/html")
+	if state := w.controller.GetCurrentState(); state == controller.StateComponents {
+		t.Fatalf("state stayed %s after posting the selection", state)
+	}
+	selected := w.controller.GetSelectedComponents()
+	if len(selected) != 2 || selected[1].ID != "docs" {
+		t.Errorf("selected = %v, want core and the posted docs", selected)
+	}
+
+	if rec := post(w, "/api/next", url.Values{"locale": {"xx"}}, "text/html"); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown locale got %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestFetchGetsJSON(t *testing.T) {
+	w := newBrowserUI(t)
+
+	rec := post(w, "/api/next", url.Values{}, "*/*")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"action": "next"`) {
+		t.Fatalf("got %d %q, want the JSON status", rec.Code, rec.Body.String())
+	}
+	if state := w.controller.GetCurrentState(); state != controller.StateLicense {
+		t.Fatalf("state = %s, want %s", state, controller.StateLicense)
+	}
+}
+
+func TestLogEndpoint(t *testing.T) {
+	w := newBrowserUI(t)
+	get := func(remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/log", nil)
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		w.server.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("127.0.0.1:50000"); rec.Code != http.StatusNotFound {
+		t.Errorf("without log file got %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	w.context.Config.LogFile = filepath.Join(t.TempDir(), "install.log")
+	if err := os.WriteFile(w.context.Config.LogFile, []byte("[INFO] Connecting db_password=hunter2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rec := get("127.0.0.1:50000")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "db_password=[REDACTED]") || strings.Contains(rec.Body.String(), "hunter2") {
+		t.Errorf("got %d %q, want the redacted log", rec.Code, rec.Body.String())
+	}
+
+	// Other computers on the network do not get it
+	if host, _, _ := strings.Cut(w.server.Addr, ":"); host != "127.0.0.1" {
+		t.Errorf("server listens on %q, want the loopback interface", w.server.Addr)
+	}
+	if rec := get("192.0.2.1:50000"); rec.Code != http.StatusForbidden || strings.Contains(rec.Body.String(), "db_password") {
+		t.Errorf("remote request got %d %q, want %d", rec.Code, rec.Body.String(), http.StatusForbidden)
+	}
+}
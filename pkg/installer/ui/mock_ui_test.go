@@ -2,6 +2,7 @@ package ui_test
 
 import (
 	"fmt"
+	"github.com/mmso2016/setupkit/pkg/installer/controller"
 	"github.com/mmso2016/setupkit/pkg/installer/core"
 	"github.com/mmso2016/setupkit/pkg/wizard"
 )
@@ -211,6 +212,22 @@ func (m *MockInstallerView) ShowErrorMessage(err error) error {
 	return nil
 }
 
+func (m *MockInstallerView) ShowFieldErrors(result controller.ValidationResult) error {
+	m.recordedCalls = append(m.recordedCalls, fmt.Sprintf("ShowFieldErrors: %v", result))
+	m.errors = append(m.errors, result)
+	return nil
+}
+
+func (m *MockInstallerView) Confirm(title, message string) (bool, error) {
+	m.recordedCalls = append(m.recordedCalls, fmt.Sprintf("Confirm: %s", title))
+	return true, nil
+}
+
+func (m *MockInstallerView) ShowHelp(title, help string) error {
+	m.recordedCalls = append(m.recordedCalls, fmt.Sprintf("ShowHelp: %s", title))
+	return nil
+}
+
 func (m *MockInstallerView) OnStateChanged(oldState, newState wizard.State) error {
 	m.recordedCalls = append(m.recordedCalls, fmt.Sprintf("OnStateChanged: %s -> %s", oldState, newState))
 	m.stateTransitions = append(m.stateTransitions, StateTransition{From: oldState, To: newState})
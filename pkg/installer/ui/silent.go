@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"fmt"
+
 	"github.com/mmso2016/setupkit/pkg/installer/core"
 )
 
@@ -25,9 +27,12 @@ func (s *SilentUI) Initialize(ctx *core.Context) error {
 func (s *SilentUI) Run() error {
 	// In silent mode, we directly execute the installation
 	// Get installer from context and set UI reference
-	installer := s.context.Metadata["installer"].(*core.Installer)
+	installer := s.context.Installer()
+	if installer == nil {
+		return fmt.Errorf("no installer found in context")
+	}
 	installer.SetUI(s) // Set the UI reference on the installer
-	return installer.ExecuteInstallation()
+	return installer.Execute()
 }
 
 func (s *SilentUI) Shutdown() error {
@@ -130,13 +130,57 @@ func (s *SilentUIDFA) ShowComplete(summary *core.InstallSummary) error {
 	for _, comp := range summary.ComponentsInstalled {
 		s.context.Logger.Info("Installed component", "name", comp)
 	}
+	for _, reason := range summary.RebootReasons {
+		s.context.Logger.Warn("Restart required", "reason", reason)
+	}
+	for _, skipped := range summary.ComponentsSkipped {
+		s.context.Logger.Warn("Component skipped", "component", skipped.ID, "reason", skipped.Reason)
+	}
+	for _, failure := range summary.PartialFailures {
+		s.context.Logger.Warn("Installation incomplete", "operation", failure.Operation, "error", failure.Err)
+	}
 	
 	return nil
 }
 
 // ShowErrorMessage displays an error message (silent)
 func (s *SilentUIDFA) ShowErrorMessage(err error) error {
-	s.context.Logger.Error("Installation error", "error", err)
+	s.context.Logger.Error("Installation error", errorFields(err)...)
+	return nil
+}
+
+// errorFields returns the log fields of err, followed by its remediation
+// hint if one is known
+func errorFields(err error, keysAndValues ...interface{}) []interface{} {
+	fields := append([]interface{}{"error", err}, keysAndValues...)
+	if hint := core.ErrorHint(err); hint != "" {
+		fields = append(fields, "hint", hint)
+	}
+	return fields
+}
+
+// ShowFieldErrors logs invalid fields (silent)
+func (s *SilentUIDFA) ShowFieldErrors(result controller.ValidationResult) error {
+	for _, e := range result {
+		s.context.Logger.Error("Invalid setting", "field", e.Field, "error", e.Message)
+	}
+	return nil
+}
+
+// Confirm answers questions without asking (silent). Nobody can answer, so
+// the configuration decides: with Force set the answer is yes. Installing into
+// a directory that already contains files is declined unless Force or
+// AllowOverwriteNonEmpty is set; with the latter the question is not asked.
+func (s *SilentUIDFA) Confirm(title, message string) (bool, error) {
+	s.context.Logger.Info("Confirmation answered from configuration",
+		"title", title,
+		"answer", s.config.Force)
+	return s.config.Force, nil
+}
+
+// ShowHelp has nobody to show the help to (silent)
+func (s *SilentUIDFA) ShowHelp(title, help string) error {
+	s.context.Logger.Debug("Help requested", "title", title)
 	return nil
 }
 
@@ -168,7 +212,7 @@ func (s *SilentUIDFA) ShowSuccess(summary *core.InstallSummary) error {
 
 // ShowError - core.UI interface method with retry logic
 func (s *SilentUIDFA) ShowError(err error, canRetry bool) (retry bool, errOut error) {
-	s.context.Logger.Error("Installation error", "error", err, "canRetry", canRetry)
+	s.context.Logger.Error("Installation error", errorFields(err, "canRetry", canRetry)...)
 	// In silent mode, never retry
 	return false, nil
 }
@@ -188,6 +232,12 @@ func (s *SilentUIDFA) RequestElevation(reason string) (bool, error) {
 func (s *SilentUIDFA) ShowCustomState(stateID wizard.State, data controller.CustomStateData) (controller.CustomStateData, error) {
 	s.context.Logger.Info("Processing custom state in silent mode", "state", stateID)
 
+	// Forms keep the values they have, the field defaults unless set in code
+	if _, ok := data["form"].(*core.UIStateConfig); ok {
+		s.context.Logger.Info("Using form values", "state", stateID)
+		return controller.CustomStateData{"values": data["values"]}, nil
+	}
+
 	// For silent mode, we need to provide default responses based on the state type
 	switch stateID {
 	case controller.StateDBConfig:
@@ -206,6 +256,27 @@ func (s *SilentUIDFA) ShowCustomState(stateID wizard.State, data controller.Cust
 
 		return controller.CustomStateData{"db_config": defaultDB}, nil
 
+	case controller.StateLanguage:
+		// Keeps the current locale; a response file sets it with core.SettingLocale
+		s.context.Logger.Info("Using locale", "locale", data["locale"])
+		return data, nil
+
+	case controller.StateInstallScope:
+		// Keeps the default scope; a response file sets it with core.SettingInstallScope
+		s.context.Logger.Info("Using install scope", "scope", data["scope"])
+		return data, nil
+
+	case controller.StatePathScope:
+		// Keeps the configured scope; a response file sets it with core.SettingPathScope
+		scope := s.context.Config.PathConfig.Scope()
+		s.context.Logger.Info("Using PATH scope", "scope", scope)
+		return controller.CustomStateData{"scope": scope}, nil
+
+	case controller.StateReleaseNotes:
+		// Nobody reads them in silent mode
+		s.context.Logger.Info("Updating existing installation", "from", data["installed_version"], "to", data["version"])
+		return data, nil
+
 	default:
 		s.context.Logger.Warn("Unknown custom state in silent mode", "state", stateID)
 		// Return the data as-is for unknown states
@@ -109,7 +109,7 @@ func (c *SSRController) updateViewData(viewName string) {
 	var totalSize int64
 	for i, comp := range c.selectedComponents {
 		c.viewData.SelectedComponents[i] = views.ComponentToViewModel(comp, i+1)
-		totalSize += comp.Size
+		totalSize += comp.DiskSize()
 	}
 	c.viewData.TotalSize = views.FormatSize(totalSize)
 	
@@ -117,6 +117,7 @@ func (c *SSRController) updateViewData(viewName string) {
 	for i, comp := range c.viewData.Components {
 		c.viewData.Components[i].Selected = c.isComponentSelected(comp.ID)
 	}
+	c.viewData.ComponentGroups = views.GroupComponentViewModels(c.viewData.Components, c.context.Config.Components)
 	
 	// View-specific updates
 	switch viewName {
@@ -131,6 +132,7 @@ func (c *SSRController) updateViewData(viewName string) {
 		c.viewData.PageDescription = "Choose installation directory"
 	case "summary":
 		c.viewData.PageDescription = "Review installation settings"
+		c.viewData.SetPrivileges(core.PrivilegesFor(c.context.Config, c.selectedComponents, c.installPath))
 	case "progress":
 		c.viewData.PageDescription = "Installation in progress"
 		c.viewData.CanGoBack = false
@@ -204,10 +206,18 @@ func (c *SSRController) handleComponentsInteraction() error {
 		return nil // Continue with current selection
 	}
 	
-	// Parse component selection
+	// Parse component selection; letters select or deselect a whole category
+	components := c.context.Config.Components
+	groups := core.GroupComponents(components)
 	selections := strings.Split(input, ",")
 	for _, s := range selections {
 		s = strings.TrimSpace(s)
+		if len(s) == 1 && core.HasCategories(components) {
+			if pos := int(strings.ToUpper(s)[0]) - 'A'; pos >= 0 && pos < len(groups) {
+				core.ToggleCategory(components, groups[pos])
+				continue
+			}
+		}
 		if idx, err := strconv.Atoi(s); err == nil {
 			if idx >= 1 && idx <= len(c.context.Config.Components) {
 				comp := &c.context.Config.Components[idx-1]
@@ -97,6 +97,23 @@ func (suite *DFAUITestSuite) TestSilentUIDFA() {
 	suite.NoError(err, "Silent UI DFA shutdown should succeed")
 }
 
+// Test that the silent UI answers confirmations from the configuration
+func (suite *DFAUITestSuite) TestSilentConfirm() {
+	silentUI := ui.NewSilentUIDFA()
+	suite.Require().NoError(silentUI.Initialize(suite.context))
+	defer func() { suite.config.Force = false }()
+
+	suite.config.Force = false
+	answer, err := silentUI.Confirm("Overwrite files", "Install anyway?")
+	suite.NoError(err)
+	suite.False(answer, "Silent UI should decline without Force")
+
+	suite.config.Force = true
+	answer, err = silentUI.Confirm("Overwrite files", "Install anyway?")
+	suite.NoError(err)
+	suite.True(answer, "Silent UI should agree with Force")
+}
+
 // Test CLI UI DFA Implementation (Non-interactive)
 func (suite *DFAUITestSuite) TestCLIUIDFA() {
 	// Create a mock reader that provides enter key inputs for interactive prompts
@@ -3,6 +3,8 @@ package views
 import (
 	"html/template"
 	texttemplate "text/template"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
 )
 
 // Embedded HTML templates for WebView
@@ -105,6 +107,10 @@ var htmlTemplates = map[string]string{
         .component-name { font-size: 18px; font-weight: 500; }
         .component-size { margin-left: auto; font-size: 14px; opacity: 0.8; }
         .component-desc { font-size: 14px; opacity: 0.9; line-height: 1.4; }
+        .category { margin: 20px 0; }
+        .category-header { display: flex; align-items: center; cursor: pointer; font-size: 20px; font-weight: 500; padding: 10px 0; }
+        .category-all { margin-left: auto; font-size: 14px; font-weight: normal; }
+        .warning { margin-top: 10px; color: #ffcc80; font-weight: 500; }
         .summary { background: rgba(255,255,255,0.1); padding: 20px; border-radius: 8px; margin: 30px 0; }
         .nav { display: flex; justify-content: space-between; margin-top: 30px; }
         .btn { padding: 12px 24px; border: none; border-radius: 6px; font-size: 16px; cursor: pointer; transition: all 0.3s; }
@@ -120,20 +126,35 @@ var htmlTemplates = map[string]string{
             <p>Choose which components to install</p>
         </div>
         
-        {{range .Components}}
+        {{define "component"}}
         <div class="component {{if .Required}}required{{end}}">
             <div class="component-header">
-                <input type="checkbox" {{if .Selected}}checked{{end}} {{if .Required}}disabled{{end}} onchange="updateSummary()">
+                <input type="checkbox" data-size="{{.SizeBytes}}" {{if .Selected}}checked{{end}} {{if .Required}}disabled data-required="true"{{end}} onchange="updateSummary()">
                 <span class="component-name">{{.Name}}</span>
                 <span class="component-size">{{.Size}}</span>
             </div>
             {{if .Description}}<div class="component-desc">{{.Description}}</div>{{end}}
         </div>
         {{end}}
+        {{if .ComponentGroups}}
+        {{range .ComponentGroups}}
+        <details class="category" open>
+            <summary class="category-header">
+                <span class="category-name">{{.Name}}</span>
+                <label class="category-all"><input type="checkbox" {{if .AllSelected}}checked{{end}} onclick="event.stopPropagation()" onchange="selectCategory(this)"> Select all</label>
+            </summary>
+            {{range .Components}}{{template "component" .}}{{end}}
+        </details>
+        {{end}}
+        {{else}}
+        {{range .Components}}{{template "component" .}}{{end}}
+        {{end}}
         
-        <div class="summary">
+        <div class="summary" id="summary" data-available="{{.AvailableBytes}}">
             <strong>Selected Components: <span id="selectedCount">{{len .SelectedComponents}}</span></strong><br>
             <strong>Total Size: <span id="totalSize">{{.TotalSize}}</span></strong>
+            {{if .AvailableSpace}}<br><strong>Free Space: {{.AvailableSpace}}, remaining: <span id="remainingSpace">{{.RemainingSpace}}</span></strong>{{end}}
+            <div class="warning" id="spaceWarning" {{if not .OverBudget}}hidden{{end}}>Not enough disk space for this selection</div>
         </div>
         
         <div class="nav">
@@ -142,8 +163,36 @@ var htmlTemplates = map[string]string{
         </div>
     </div>
     <script>
+        function formatSize(bytes) {
+            if (bytes < 1024) { return bytes + ' B'; }
+            var units = 'KMGTPE', exp = -1;
+            do { bytes /= 1024; exp++; } while (bytes >= 1024 && exp < units.length - 1);
+            return bytes.toFixed(1) + ' ' + units[exp] + 'B';
+        }
         function updateSummary() {
-            // Update component summary dynamically
+            var count = 0, total = 0;
+            document.querySelectorAll('.component input[data-size]').forEach(function (input) {
+                if (input.checked || input.dataset.required) {
+                    count++;
+                    total += Number(input.dataset.size);
+                }
+            });
+            document.getElementById('selectedCount').textContent = count;
+            document.getElementById('totalSize').textContent = formatSize(total);
+
+            var available = Number(document.getElementById('summary').dataset.available);
+            if (available < 0) { return; }
+            var remaining = available - total;
+            document.getElementById('remainingSpace').textContent = (remaining < 0 ? '-' : '') + formatSize(Math.abs(remaining));
+            // Same 20% reserve as core.EstimateSpace
+            document.getElementById('spaceWarning').hidden = total * 1.2 <= available;
+        }
+        function selectCategory(box) {
+            // Required components are disabled and keep their state
+            box.closest('details').querySelectorAll('.component input:not(:disabled)').forEach(function (input) {
+                input.checked = box.checked;
+            });
+            updateSummary();
         }
     </script>
 </body>
@@ -182,16 +231,22 @@ Do you accept the license agreement? (y/n): `,
 {{separator 60}}
 
 Choose components to install:
-
-{{range .Components}}
+{{define "component"}}
   [{{if .Required}}R{{else}}{{selected .Selected}}{{end}}] {{.Index}}. {{.Name}} ({{.Size}})
 {{if .Description}}      {{.Description}}{{end}}
 {{end}}
+{{if .ComponentGroups}}{{range .ComponentGroups}}
+[{{if .AllSelected}}X{{else}} {{end}}] {{.Letter}}. {{.Name}}
+{{range .Components}}{{template "component" .}}{{end}}{{end}}
+  Enter a category letter to select or deselect all of it
+{{else}}{{range .Components}}{{template "component" .}}{{end}}{{end}}
 
   R = Required, X = Selected
 
 Total selected: {{len .SelectedComponents}} components ({{.TotalSize}})
-
+{{if .AvailableSpace}}Free space: {{.AvailableSpace}}, remaining: {{.RemainingSpace}}
+{{end}}{{if .OverBudget}}Warning: not enough disk space for this selection
+{{end}}
 Enter component numbers to toggle (comma-separated), or press Enter to continue:
 `,
 
@@ -215,7 +270,11 @@ Total size: {{.TotalSize}}
 Selected components:
 {{range .SelectedComponents}}  - {{.Name}} ({{.Size}})
 {{end}}
-
+{{if .Privileges}}
+` + core.PrivilegesHeading + `
+{{range .Privileges}}  - {{.}}
+{{end}}` + core.ElevationNotice + `
+{{end}}
 {{separator 60}}
 Proceed with installation? (y/n): `,
 
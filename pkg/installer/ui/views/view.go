@@ -9,6 +9,7 @@ import (
 	texttemplate "text/template"
 
 	"github.com/mmso2016/setupkit/pkg/installer/core"
+	"github.com/mmso2016/setupkit/pkg/wizard"
 )
 
 // ViewType defines the target rendering format
@@ -37,8 +38,14 @@ type ViewData struct {
 	
 	// Components
 	Components         []ComponentViewModel
+	ComponentGroups    []ComponentGroupViewModel // Set when components declare categories
 	SelectedComponents []ComponentViewModel
 	TotalSize          string
+	AvailableBytes     int64  // Free space on the target volume, -1 if unknown
+	AvailableSpace     string
+	RemainingSpace     string
+	OverBudget         bool // The selection does not fit, see core.EstimateSpace
+	Privileges         []string // Operations that need administrator rights, see core.PrivilegesFor
 	
 	// Installation
 	InstallPath    string
@@ -62,11 +69,21 @@ type ComponentViewModel struct {
 	Name        string
 	Description string
 	Size        string
+	SizeBytes   int64
+	Category    string
 	Required    bool
 	Selected    bool
 	Index       int
 }
 
+// ComponentGroupViewModel represents a component category for view rendering
+type ComponentGroupViewModel struct {
+	Name        string
+	Letter      string // Addresses the category in the CLI
+	AllSelected bool
+	Components  []ComponentViewModel
+}
+
 // ViewRenderer handles rendering for different output formats
 type ViewRenderer struct {
 	htmlTemplates *template.Template
@@ -217,7 +234,9 @@ func ComponentToViewModel(comp core.Component, index int) ComponentViewModel {
 		ID:          comp.ID,
 		Name:        comp.Name,
 		Description: comp.Description,
-		Size:        formatSizeHelper(comp.Size),
+		Category:    comp.Category,
+		Size:        formatSizeHelper(comp.DiskSize()),
+		SizeBytes:   comp.DiskSize(),
 		Required:    comp.Required,
 		Selected:    comp.Selected,
 		Index:       index,
@@ -228,6 +247,7 @@ func ComponentToViewModel(comp core.Component, index int) ComponentViewModel {
 func ConfigToViewData(config *core.Config, pageTitle string) *ViewData {
 	components := make([]ComponentViewModel, len(config.Components))
 	var selectedComponents []ComponentViewModel
+	var selected []core.Component
 	var totalSize int64
 	
 	for i, comp := range config.Components {
@@ -236,12 +256,19 @@ func ConfigToViewData(config *core.Config, pageTitle string) *ViewData {
 		
 		if comp.Selected || comp.Required {
 			selectedComponents = append(selectedComponents, vm)
-			totalSize += comp.Size
+			selected = append(selected, comp)
+			totalSize += comp.DiskSize()
 		}
 	}
 	
-	return &ViewData{
+	available, err := core.AvailableSpace(config.InstallDir)
+	if err != nil {
+		available = -1
+	}
+	
+	data := &ViewData{
 		AppName:            config.AppName,
+		ComponentGroups:    GroupComponentViewModels(components, config.Components),
 		Version:            config.Version,
 		Publisher:          config.Publisher,
 		Website:            config.Website,
@@ -256,8 +283,61 @@ func ConfigToViewData(config *core.Config, pageTitle string) *ViewData {
 		CanGoBack:   true,
 		CanGoNext:   true,
 		CanCancel:   true,
-		NextLabel:   "Next",
+		NextLabel:   wizard.DefaultPrimaryLabel,
 		BackLabel:   "Back", 
 		CancelLabel: "Cancel",
 	}
-}
\ No newline at end of file
+	data.SetSpaceEstimate(core.EstimateSpace(config.Components, available))
+	data.SetPrivileges(core.PrivilegesFor(config, selected, config.InstallDir))
+	return data
+}
+
+// SetPrivileges updates the operations listed as needing administrator rights
+func (d *ViewData) SetPrivileges(reqs []core.PrivilegeRequirement) {
+	d.Privileges = nil
+	for _, req := range reqs {
+		d.Privileges = append(d.Privileges, req.Description)
+	}
+}
+
+// SetSpaceEstimate updates the size and free space shown for the selection
+func (d *ViewData) SetSpaceEstimate(estimate core.SpaceEstimate) {
+	d.TotalSize = formatSizeHelper(estimate.Selected)
+	d.AvailableBytes = estimate.Available
+	d.AvailableSpace = ""
+	d.RemainingSpace = ""
+	if estimate.Available >= 0 {
+		d.AvailableSpace = formatSizeHelper(estimate.Available)
+		if estimate.Remaining < 0 {
+			d.RemainingSpace = "-" + formatSizeHelper(-estimate.Remaining)
+		} else {
+			d.RemainingSpace = formatSizeHelper(estimate.Remaining)
+		}
+	}
+	d.OverBudget = estimate.OverBudget
+}
+
+// GroupComponentViewModels groups models, which correspond one to one to
+// components, by category. It returns nil if no component has a category.
+func GroupComponentViewModels(models []ComponentViewModel, components []core.Component) []ComponentGroupViewModel {
+	if !core.HasCategories(components) {
+		return nil
+	}
+	var groups []ComponentGroupViewModel
+	for pos, group := range core.GroupComponents(components) {
+		vm := ComponentGroupViewModel{
+			Name:        group.Category,
+			Letter:      string(rune('A' + pos)),
+			AllSelected: true,
+		}
+		for _, idx := range group.Indices {
+			model := models[idx]
+			vm.Components = append(vm.Components, model)
+			if !model.Selected && !model.Required {
+				vm.AllSelected = false
+			}
+		}
+		groups = append(groups, vm)
+	}
+	return groups
+}
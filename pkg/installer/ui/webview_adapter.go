@@ -33,8 +33,8 @@ func (w *webViewAdapter) Initialize(ctx *core.Context) error {
 	}
 
 	// Get installer from context
-	installer, ok := ctx.Metadata["installer"].(*core.Installer)
-	if !ok {
+	installer := ctx.Installer()
+	if installer == nil {
 		return fmt.Errorf("no installer found in context")
 	}
 
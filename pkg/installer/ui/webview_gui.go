@@ -4,6 +4,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"log"
 
@@ -31,6 +32,54 @@ type webViewNativeGUI struct {
 
 	// User input storage
 	userInputs map[string]interface{}
+
+	// Question shown instead of the current page
+	confirm *pendingConfirm
+}
+
+// Default size of the GUI window, see core.Config.WindowWidth
+const (
+	defaultWindowWidth  = 800
+	defaultWindowHeight = 600
+)
+
+// windowHint is a size constraint applied to the GUI window once it exists
+type windowHint struct {
+	Width, Height int
+	Hint          webview2.Hint
+}
+
+// webViewOptions maps the window settings of config to the WebView2
+// options, and to the size constraints to apply after creating the window:
+// a fixed size if FixedSize is set, otherwise the minimum size if any
+func webViewOptions(config *core.Config) (webview2.WebViewOptions, []windowHint) {
+	width, height := config.WindowWidth, config.WindowHeight
+	if width <= 0 {
+		width = defaultWindowWidth
+	}
+	if height <= 0 {
+		height = defaultWindowHeight
+	}
+	options := webview2.WebViewOptions{
+		Debug:     true,
+		AutoFocus: true,
+		DataPath:  "",
+		WindowOptions: webview2.WindowOptions{
+			Title:  fmt.Sprintf("%s v%s - Installer", config.AppName, config.Version),
+			Width:  uint(width),
+			Height: uint(height),
+			IconId: 2, // Use default icon
+		},
+	}
+
+	if config.FixedSize {
+		return options, []windowHint{{Width: width, Height: height, Hint: webview2.HintFixed}}
+	}
+	if config.MinWidth > 0 || config.MinHeight > 0 {
+		// WebView2 only applies a minimum with both bounds set
+		return options, []windowHint{{Width: max(config.MinWidth, 1), Height: max(config.MinHeight, 1), Hint: webview2.HintMin}}
+	}
+	return options, nil
 }
 
 // NewWebViewGUI creates a new native WebView GUI instance
@@ -45,23 +94,21 @@ func (w *webViewNativeGUI) Initialize(ctx *core.Context) error {
 	w.done = make(chan struct{})
 	w.finished = make(chan struct{})
 	w.userInputs = make(map[string]interface{})
+	w.confirm = newPendingConfirm()
+
+	if err := GUIAvailable(); err != nil {
+		return err
+	}
 
 	// Create WebView2 instance
-	title := fmt.Sprintf("%s v%s - Installer", ctx.Config.AppName, ctx.Config.Version)
-	wv := webview2.NewWithOptions(webview2.WebViewOptions{
-		Debug:     true,
-		AutoFocus: true,
-		DataPath:  "",
-		WindowOptions: webview2.WindowOptions{
-			Title:  title,
-			Width:  800,
-			Height: 600,
-			IconId: 2, // Use default icon
-		},
-	})
+	options, hints := webViewOptions(ctx.Config)
+	wv := webview2.NewWithOptions(options)
 	if wv == nil {
 		return fmt.Errorf("failed to create webview2 instance")
 	}
+	for _, hint := range hints {
+		wv.SetSize(hint.Width, hint.Height, hint.Hint)
+	}
 	w.webview = wv
 
 	// Bind JavaScript functions for installer interaction
@@ -139,6 +186,22 @@ func (w *webViewNativeGUI) ShowLicense(license string) (accepted bool, err error
 	return true, nil
 }
 
+// ShowLicenses displays the licenses with a checkbox each; like ShowLicense
+// it returns at once and the acceptance is handled by JavaScript bindings
+func (w *webViewNativeGUI) ShowLicenses(licenses []core.ApplicableLicense) ([]bool, error) {
+	w.currentState = controller.StateLicense
+	w.userInputs["licenses"] = licenses
+	fmt.Printf("[WebView] Showing %d licenses\n", len(licenses))
+
+	w.updateWebViewContent()
+
+	accepted := make([]bool, len(licenses))
+	for i := range accepted {
+		accepted[i] = true
+	}
+	return accepted, nil
+}
+
 // ShowComponents displays component selection
 func (w *webViewNativeGUI) ShowComponents(components []core.Component) (selected []core.Component, err error) {
 	w.currentState = controller.StateComponents
@@ -203,6 +266,8 @@ func (w *webViewNativeGUI) ShowProgress(progress *core.Progress) error {
 func (w *webViewNativeGUI) ShowComplete(summary *core.InstallSummary) error {
 	w.currentState = controller.StateComplete
 	fmt.Printf("[WebView] Installation completed successfully!\n")
+	w.userInputs["reboot"] = summary.RebootReasons
+	w.userInputs["warnings"] = summary.Warnings
 
 	// Update WebView content for completion state
 	w.updateWebViewContent()
@@ -218,6 +283,35 @@ func (w *webViewNativeGUI) ShowComplete(summary *core.InstallSummary) error {
 // ShowErrorMessage displays an error message
 func (w *webViewNativeGUI) ShowErrorMessage(err error) error {
 	fmt.Printf("[WebView] Error: %v\n", err)
+	var installErr *core.InstallError
+	if errors.As(err, &installErr) {
+		w.webview.SetHtml(w.renderer.RenderErrorPage(w.context.Config, err).Render())
+	}
+	return nil
+}
+
+// ShowFieldErrors displays the invalid fields of the current step
+func (w *webViewNativeGUI) ShowFieldErrors(result controller.ValidationResult) error {
+	for _, e := range result {
+		fmt.Printf("[WebView] Invalid %s: %s\n", e.Field, e.Message)
+	}
+	return nil
+}
+
+// Confirm shows a question in the window and waits for the answer
+func (w *webViewNativeGUI) Confirm(title, message string) (bool, error) {
+	fmt.Printf("[WebView] Confirm: %s\n", title)
+	answer := w.confirm.ask(title, message, func() {
+		w.webview.SetHtml(w.renderer.RenderConfirmDialog(w.context.Config, title, message).Render())
+	})
+	w.updateWebViewContent()
+	return answer, nil
+}
+
+// ShowHelp shows the help in the window until it is closed
+func (w *webViewNativeGUI) ShowHelp(title, help string) error {
+	fmt.Printf("[WebView] Help: %s\n", title)
+	w.webview.SetHtml(w.renderer.RenderHelpDialog(w.context.Config, title, help).Render())
 	return nil
 }
 
@@ -274,6 +368,12 @@ func (w *webViewNativeGUI) ShowCustomState(stateID wizard.State, data controller
 	w.currentState = stateID
 	w.userInputs["custom_state_data"] = data
 
+	if _, ok := data["form"].(*core.UIStateConfig); ok {
+		// Rendered from the declared fields, the values arrive through installerSetFormValues
+		w.updateWebViewContent()
+		return nil, nil
+	}
+
 	switch stateID {
 	case controller.StateDBConfig:
 		if config, exists := data["config"]; exists {
@@ -285,6 +385,10 @@ func (w *webViewNativeGUI) ShowCustomState(stateID wizard.State, data controller
 		fmt.Printf("[WebView] Using default database configuration: %s\n", defaultDB.String())
 		return controller.CustomStateData{"config": defaultDB}, nil
 
+	case controller.StateReleaseNotes, controller.StateLanguage, controller.StatePathScope, controller.StateInstallScope:
+		w.updateWebViewContent()
+		return data, nil
+
 	default:
 		fmt.Printf("[WebView] Unknown custom state: %s\n", stateID)
 		return data, nil
@@ -298,10 +402,22 @@ func (w *webViewNativeGUI) ShowCustomState(stateID wizard.State, data controller
 // updateWebViewContent renders the appropriate page based on current DFA state
 func (w *webViewNativeGUI) updateWebViewContent() {
 	var doc *html.Document
+	if w.controller != nil {
+		w.renderer.SetPrimaryLabel(w.controller.PrimaryLabelFor(w.currentState))
+		w.renderer.SetHelp(w.controller.HelpFor(w.currentState))
+		w.renderer.SetWarnings(w.controller.RequirementWarnings())
+		w.renderer.SetLocalizer(w.controller.Localizer())
+	}
 
 	switch w.currentState {
+	case controller.StateLanguage:
+		data, _ := w.userInputs["custom_state_data"].(controller.CustomStateData)
+		locales, _ := data["locales"].([]string)
+		doc = w.renderer.RenderLanguagePage(w.context.Config, locales, w.controller.Localizer().Locale())
 	case controller.StateLicense:
-		if license, ok := w.userInputs["license_text"].(string); ok {
+		if licenses, ok := w.userInputs["licenses"].([]core.ApplicableLicense); ok {
+			doc = w.renderer.RenderLicensesPage(w.context.Config, licenses)
+		} else if license, ok := w.userInputs["license_text"].(string); ok {
 			doc = w.renderer.RenderLicensePage(w.context.Config, license)
 		} else {
 			doc = w.renderer.RenderLicensePage(w.context.Config, "No license text provided")
@@ -312,8 +428,23 @@ func (w *webViewNativeGUI) updateWebViewContent() {
 		if defaultPath, ok := w.userInputs["default_path"].(string); ok {
 			doc = w.renderer.RenderInstallPathPage(w.context.Config, defaultPath)
 		} else {
-			doc = w.renderer.RenderInstallPathPage(w.context.Config, "/opt/"+w.context.Config.AppName)
+			doc = w.renderer.RenderInstallPathPage(w.context.Config, w.context.Config.DefaultInstallDir())
 		}
+	case controller.StatePathScope:
+		data, _ := w.userInputs["custom_state_data"].(controller.CustomStateData)
+		scope, _ := data["scope"].(string)
+		elevated, _ := data["elevated"].(bool)
+		doc = w.renderer.RenderPathScopePage(w.context.Config, scope, elevated)
+	case controller.StateInstallScope:
+		data, _ := w.userInputs["custom_state_data"].(controller.CustomStateData)
+		scope, _ := data["scope"].(string)
+		elevated, _ := data["elevated"].(bool)
+		doc = w.renderer.RenderInstallScopePage(w.context.Config, scope, elevated)
+	case controller.StateReleaseNotes:
+		notes, _ := w.userInputs["custom_state_data"].(controller.CustomStateData)
+		installed, _ := notes["installed_version"].(string)
+		text, _ := notes["notes"].(string)
+		doc = w.renderer.RenderReleaseNotesPage(w.context.Config, installed, text)
 	case controller.StateSummary:
 		var selectedComponents []core.Component
 		var installPath string
@@ -325,18 +456,31 @@ func (w *webViewNativeGUI) updateWebViewContent() {
 			installPath = path
 		}
 
+		if w.controller != nil {
+			w.renderer.SetChangeLinks(changeLinks(w.controller, selectedComponents))
+			plan, _ := w.controller.ModifyPlan()
+			w.renderer.SetModifyPlan(plan)
+		}
 		doc = w.renderer.RenderSummaryPage(w.context.Config, selectedComponents, installPath)
 	case controller.StateProgress:
 		if progress, ok := w.userInputs["progress"].(*core.Progress); ok {
-			percentage := int(progress.OverallProgress * 100)
-			doc = w.renderer.RenderProgressPage(w.context.Config, percentage, progress.ComponentName)
+			doc = w.renderer.RenderInstallProgressPage(w.context.Config, progress)
 		} else {
 			doc = w.renderer.RenderProgressPage(w.context.Config, 0, "Starting...")
 		}
 	case controller.StateComplete:
+		reasons, _ := w.userInputs["reboot"].([]string)
+		w.renderer.SetReboot(reasons)
+		warnings, _ := w.userInputs["warnings"].([]string)
+		w.renderer.SetCompletionWarnings(warnings)
 		doc = w.renderer.RenderCompletionPage(w.context.Config, true)
 	default:
 		doc = w.renderer.RenderWelcomePage(w.context.Config)
+		if w.controller != nil {
+			if form := w.controller.FormFor(w.currentState); form != nil {
+				doc = w.renderer.RenderFormPage(w.context.Config, form, w.controller.FormValues(w.currentState))
+			}
+		}
 	}
 
 	// Update WebView2 content
@@ -372,12 +516,104 @@ func (w *webViewNativeGUI) setupJavaScriptBindings() {
 		fmt.Printf("[WebView] Cancel button clicked\n")
 		go func() {
 			if err := w.controller.Cancel(); err != nil {
+				// Declined or not possible, the installation continues
 				fmt.Printf("[WebView] Cancel error: %v\n", err)
+				return
 			}
 			w.done <- struct{}{}
 		}()
 	})
 
+	w.webview.Bind("installerConfirm", func(yes bool) {
+		fmt.Printf("[WebView] Confirmation answered: %v\n", yes)
+		w.confirm.reply(yes)
+	})
+
+	w.webview.Bind("installerHelp", func() {
+		if err := w.controller.Help(); err != nil {
+			fmt.Printf("[WebView] Help error: %v\n", err)
+		}
+	})
+
+	w.webview.Bind("installerCloseHelp", func() {
+		w.updateWebViewContent()
+	})
+
+	w.webview.Bind("installerJump", func(state string) {
+		fmt.Printf("[WebView] Change requested for state: %s\n", state)
+		go func() {
+			if err := w.controller.Jump(wizard.State(state)); err != nil {
+				fmt.Printf("[WebView] Jump error: %v\n", err)
+			}
+		}()
+	})
+
+	w.webview.Bind("installerSaveDiagnostics", func() (string, error) {
+		path, err := w.controller.SaveDiagnostics()
+		if err != nil {
+			fmt.Printf("[WebView] Diagnostics error: %v\n", err)
+			return "", err
+		}
+		fmt.Printf("[WebView] Diagnostics saved to %s\n", path)
+		return path, nil
+	})
+
+	w.webview.Bind("installerViewLog", func() (string, error) {
+		message, err := w.controller.OpenLog()
+		if err != nil {
+			fmt.Printf("[WebView] View log error: %v\n", err)
+			return "", err
+		}
+		fmt.Printf("[WebView] %s\n", message)
+		return message, nil
+	})
+
+	w.webview.Bind("installerRunAction", func(id string) (string, error) {
+		message, err := w.controller.RunCompletionAction(id)
+		if err != nil {
+			fmt.Printf("[WebView] Completion action error: %v\n", err)
+			return "", err
+		}
+		fmt.Printf("[WebView] %s\n", message)
+		return message, nil
+	})
+
+	w.webview.Bind("installerSetLocale", func(locale string) error {
+		if err := w.controller.SetLocale(locale); err != nil {
+			fmt.Printf("[WebView] Locale error: %v\n", err)
+			return err
+		}
+		fmt.Printf("[WebView] Locale switched to %s\n", locale)
+		w.updateWebViewContent()
+		return nil
+	})
+
+	w.webview.Bind("installerSetPathScope", func(scope string) error {
+		if err := w.controller.SetPathScope(scope); err != nil {
+			fmt.Printf("[WebView] PATH scope error: %v\n", err)
+			return err
+		}
+		fmt.Printf("[WebView] PATH scope set to %s\n", scope)
+		return nil
+	})
+
+	w.webview.Bind("installerSetInstallScope", func(scope string) error {
+		if err := w.controller.SetInstallScope(scope); err != nil {
+			fmt.Printf("[WebView] Install scope error: %v\n", err)
+			return err
+		}
+		fmt.Printf("[WebView] Install scope set to %s\n", scope)
+		return nil
+	})
+
+	w.webview.Bind("installerSetFormValues", func(values map[string]string) controller.ValidationResult {
+		result := submitForm(w.controller, w.currentState, values)
+		if len(result) > 0 {
+			fmt.Printf("[WebView] Invalid form values: %v\n", result)
+		}
+		return result
+	})
+
 	// State query function for WebView to know current state
 	w.webview.Bind("getCurrentState", func() string {
 		return string(w.currentState)
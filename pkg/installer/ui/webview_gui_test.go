@@ -0,0 +1,48 @@
+//go:build !nogui
+// +build !nogui
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jchv/go-webview2"
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+func TestWebViewOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        core.Config
+		width, height uint
+		hints         []windowHint
+	}{
+		{"fixed default size", core.Config{FixedSize: true}, 800, 600,
+			[]windowHint{{Width: 800, Height: 600, Hint: webview2.HintFixed}}},
+		{"fixed configured size", core.Config{WindowWidth: 1024, WindowHeight: 768, FixedSize: true}, 1024, 768,
+			[]windowHint{{Width: 1024, Height: 768, Hint: webview2.HintFixed}}},
+		{"resizable by default", core.Config{}, 800, 600, nil},
+		{"resizable", core.Config{WindowWidth: 900, WindowHeight: 720}, 900, 720, nil},
+		{"resizable with minimum", core.Config{MinWidth: 640, MinHeight: 480}, 800, 600,
+			[]windowHint{{Width: 640, Height: 480, Hint: webview2.HintMin}}},
+		{"minimum width only", core.Config{MinWidth: 640}, 800, 600,
+			[]windowHint{{Width: 640, Height: 1, Hint: webview2.HintMin}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.AppName, tt.config.Version = "BrandedApp", "2.0.0"
+			options, hints := webViewOptions(&tt.config)
+			window := options.WindowOptions
+			if window.Width != tt.width || window.Height != tt.height {
+				t.Errorf("window size = %dx%d, want %dx%d", window.Width, window.Height, tt.width, tt.height)
+			}
+			if window.Title != "BrandedApp v2.0.0 - Installer" {
+				t.Errorf("window title = %q", window.Title)
+			}
+			if !reflect.DeepEqual(hints, tt.hints) {
+				t.Errorf("size hints = %+v, want %+v", hints, tt.hints)
+			}
+		})
+	}
+}
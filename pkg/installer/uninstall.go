@@ -0,0 +1,64 @@
+package installer
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/mmso2016/setupkit/pkg/installer/core"
+)
+
+// UninstallSummary is the machine-readable result of a silent uninstall
+type UninstallSummary struct {
+	Success    bool     `json:"success"`
+	ExitCode   int      `json:"exit_code"`
+	InstallDir string   `json:"install_dir"`
+	Components []string `json:"components"`
+	Removed    []string `json:"removed"`
+	Kept       []string `json:"kept"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// SilentUninstall removes the installation in config.InstallDir without any
+// prompts. Paths matching PreserveOnUninstall and files the installer did not
+// create are kept. A JSON summary is written to out and the exit code returned.
+func SilentUninstall(config *Config, out io.Writer) int {
+	summary := UninstallSummary{
+		InstallDir: config.InstallDir,
+		Components: []string{},
+		Removed:    []string{},
+		Kept:       []string{},
+	}
+
+	finish := func(code int, err error) int {
+		summary.ExitCode = code
+		summary.Success = code == ExitSuccess
+		if err != nil {
+			summary.Error = err.Error()
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		enc.Encode(summary)
+		return code
+	}
+
+	uninstaller, err := core.NewUninstaller(config)
+	if err != nil {
+		return finish(ExitNotInstalled, err)
+	}
+	uninstaller.SetLogger(core.NewNullLogger())
+
+	plan, err := uninstaller.PreviewRemoval()
+	if err != nil {
+		return finish(ExitFileError, err)
+	}
+	opts := core.UninstallOptions{}
+	remove, keep := plan.Partition(opts)
+	summary.Components = append(summary.Components, plan.Components...)
+	summary.Kept = append(summary.Kept, keep...)
+
+	if err := uninstaller.Uninstall(plan, opts); err != nil {
+		return finish(ExitFileError, err)
+	}
+	summary.Removed = append(summary.Removed, remove...)
+	return finish(ExitSuccess, nil)
+}
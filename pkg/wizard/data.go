@@ -0,0 +1,87 @@
+package wizard
+
+// DataAs returns data[key] as T. The second result is false if the key is
+// absent or holds a value of another type.
+func DataAs[T any](data map[string]interface{}, key string) (T, bool) {
+	value, ok := data[key].(T)
+	return value, ok
+}
+
+// GetAs returns the DFA data value for key as T. The second result is false if
+// the key is absent or holds a value of another type.
+func GetAs[T any](d *DFA, key string) (T, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return DataAs[T](d.data, key)
+}
+
+// GetString returns the data value for key as a string
+func (d *DFA) GetString(key string) (string, bool) {
+	return GetAs[string](d, key)
+}
+
+// GetBool returns the data value for key as a bool
+func (d *DFA) GetBool(key string) (bool, bool) {
+	return GetAs[bool](d, key)
+}
+
+// GetInt returns the data value for key as an int. Other integer types and
+// whole floats (as produced by JSON decoding) are converted.
+func (d *DFA) GetInt(key string) (int, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return toInt(d.data[key])
+}
+
+// MustGetString returns the data value for key as a string, or "" if absent or of another type
+func (d *DFA) MustGetString(key string) string {
+	value, _ := d.GetString(key)
+	return value
+}
+
+// MustGetBool returns the data value for key as a bool, or false if absent or of another type
+func (d *DFA) MustGetBool(key string) bool {
+	value, _ := d.GetBool(key)
+	return value
+}
+
+// MustGetInt returns the data value for key as an int, or 0 if absent or of another type
+func (d *DFA) MustGetInt(key string) int {
+	value, _ := d.GetInt(key)
+	return value
+}
+
+// toInt converts numeric values to int
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int8:
+		return int(v), true
+	case int16:
+		return int(v), true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case uint:
+		return int(v), true
+	case uint8:
+		return int(v), true
+	case uint16:
+		return int(v), true
+	case uint32:
+		return int(v), true
+	case uint64:
+		return int(v), true
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), true
+		}
+	case float32:
+		if v == float32(int(v)) {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
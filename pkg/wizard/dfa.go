@@ -66,8 +66,17 @@ const (
 	ActionRetry    Action = "retry"
 	ActionValidate Action = "validate"
 	ActionSave     Action = "save"
+	ActionHelp     Action = "help" // Shows the state's HelpText; never leaves the state
+	ActionJump     Action = "jump" // Returns to a visited state, see DFA.Jump
 )
 
+// DefaultPrimaryLabel labels the ActionNext button of states without a PrimaryLabel
+const DefaultPrimaryLabel = "Next"
+
+// ErrForwardOnly is returned by Back and Jump when the DFA is forward-only,
+// see DFA.SetForwardOnly
+var ErrForwardOnly = errors.New("going back is disabled in forward-only mode")
+
 // SubAction represents actions within a sub-state
 type SubAction string
 
@@ -102,6 +111,7 @@ type Callbacks struct {
 	OnCancel          func(state State, data map[string]interface{})
 	BeforeTransition  func(from, to State, action Action) error
 	AfterTransition   func(from, to State, action Action) error
+	OnPanic           func(state State, err *PanicError) // A transition from state was undone after a panic
 }
 
 // StateConfig defines the configuration for a state
@@ -115,6 +125,13 @@ type StateConfig struct {
 	CanCancel bool
 	CanSkip   bool
 
+	// PrimaryLabel is the label of the button that triggers ActionNext,
+	// DefaultPrimaryLabel if empty
+	PrimaryLabel string
+
+	// HelpText explains the state; shown on ActionHelp
+	HelpText string
+
 	// Validation
 	ValidateFunc    func(data map[string]interface{}) error
 	ValidateOnEntry func(data map[string]interface{}) error
@@ -123,6 +140,10 @@ type StateConfig struct {
 	// Entry control
 	CanEnterFunc func(data map[string]interface{}) bool
 
+	// Optional states are passed over by Next when CanEnterFunc refuses
+	// entry, instead of failing the transition
+	Optional bool
+
 	// Callbacks
 	OnEnter      func(data map[string]interface{}) error
 	OnExit       func(data map[string]interface{}) error
@@ -223,13 +244,24 @@ type DFA struct {
 	// History for back navigation
 	history []State
 	future  []State // For redo functionality
+	jump    *pendingJump
 
 	// Data store
 	data map[string]interface{}
 
+	// Data snapshots for Rollback
+	snapshots    map[int]map[string]interface{}
+	nextSnapshot int
+
 	// Callbacks
 	callbacks *Callbacks
 
+	// Transition middleware chain
+	middleware []TransitionMiddleware
+
+	// State change subscribers
+	subscribers []chan StateChange
+
 	// Global validation
 	GlobalValidator func(state State, data map[string]interface{}) error
 
@@ -241,11 +273,21 @@ type DFA struct {
 	AllowBackToAny bool // Allow going back to any previous state
 	strictMode     bool // Enforce all validations
 	DryRun         bool // Dry-run mode for testing
+	forwardOnly    bool // No Back or Jump; the history only holds the current state
+
+	// Source of the current time, time.Now unless replaced by SetClock
+	clock func() time.Time
 
 	// Dry-run tracking
 	dryRunLog []string
 }
 
+// pendingJump records where Next leads back to after a Jump
+type pendingJump struct {
+	returnTo State
+	passed   map[State]bool // Visited between the jump target and returnTo
+}
+
 // NewHierarchical creates a new hierarchical DFA instance
 func NewHierarchical() *HierarchicalDFA {
 	return &HierarchicalDFA{
@@ -271,6 +313,7 @@ func New() *DFA {
 		transitions: make([]TransitionRule, 0),
 		maxHistory:  100,
 		strictMode:  true,
+		clock:       time.Now,
 		dryRunLog:   make([]string, 0),
 	}
 }
@@ -282,6 +325,30 @@ func (d *DFA) SetMaxHistory(max int) {
 	d.maxHistory = max
 }
 
+// SetForwardOnly disables Back and Jump, e.g. for silent and kiosk flows.
+// The history is trimmed to the current state and no longer grows, so
+// CanTransition(ActionBack) is always false. Data and final states work as
+// before.
+func (d *DFA) SetForwardOnly(forwardOnly bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.forwardOnly = forwardOnly
+	if forwardOnly {
+		if d.current != "" {
+			d.history = []State{d.current}
+		}
+		d.future = []State{}
+		d.jump = nil
+	}
+}
+
+// IsForwardOnly reports whether Back and Jump are disabled, see SetForwardOnly
+func (d *DFA) IsForwardOnly() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.forwardOnly
+}
+
 // SetCallbacks sets the callbacks for the DFA
 func (d *DFA) SetCallbacks(callbacks *Callbacks) {
 	d.mu.Lock()
@@ -296,6 +363,17 @@ func (d *DFA) SetStrictMode(strict bool) {
 	d.strictMode = strict
 }
 
+// SetClock replaces the source of the current time, e.g. with a fixed clock
+// in tests; nil restores time.Now
+func (d *DFA) SetClock(clock func() time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if clock == nil {
+		clock = time.Now
+	}
+	d.clock = clock
+}
+
 // SetDryRun enables or disables dry-run mode
 func (d *DFA) SetDryRun(dryRun bool) {
 	d.mu.Lock()
@@ -385,6 +463,26 @@ func (d *DFA) GetStateConfig(state State) (*StateConfig, error) {
 	return config, nil
 }
 
+// PrimaryLabel returns the label of the primary action in state
+func (d *DFA) PrimaryLabel(state State) string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if config, exists := d.states[state]; exists && config.PrimaryLabel != "" {
+		return config.PrimaryLabel
+	}
+	return DefaultPrimaryLabel
+}
+
+// HelpText returns the help text of state, empty if it has none
+func (d *DFA) HelpText(state State) string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if config, exists := d.states[state]; exists {
+		return config.HelpText
+	}
+	return ""
+}
+
 // CurrentState returns the current state
 func (d *DFA) CurrentState() State {
 	d.mu.RLock()
@@ -490,21 +588,67 @@ func (d *DFA) Next() error {
 		}
 	}
 
-	// Determine next state
-	var nextState State
-	var err error
+	nextState, err := d.nextStateInternal(d.current, config)
+	if err != nil {
+		return err
+	}
 
-	if config.NextStateFunc != nil {
-		nextState, err = config.NextStateFunc(d.data)
-		if err != nil {
+	// Pass over optional states that cannot be entered and, after a Jump,
+	// the states that were visited before it
+	var passed []State
+	for seen := map[State]bool{}; ; {
+		next, exists := d.states[nextState]
+		if !exists {
+			break
+		}
+		optional := next.Optional && next.CanEnterFunc != nil && !next.CanEnterFunc(d.data) && !d.DryRun
+		visited := d.jump != nil && d.jump.passed[nextState]
+		if !optional && !visited {
+			break
+		}
+		if seen[nextState] {
+			return fmt.Errorf("skipped states loop at %s", nextState)
+		}
+		seen[nextState] = true
+		if optional {
+			d.logDryRun("Skipping optional state: %s", nextState)
+		} else {
+			d.logDryRun("Passing visited state: %s", nextState)
+			passed = append(passed, nextState)
+		}
+		if nextState, err = d.nextStateInternal(nextState, next); err != nil {
 			return err
 		}
+	}
+
+	// Passed states stay in the history, so Back still leads through them
+	history := d.history
+	d.history = append(append([]State{}, history...), passed...)
+	if err := d.transitionToInternal(nextState, ActionNext); err != nil {
+		d.history = history
+		return err
+	}
+	if d.jump != nil && nextState == d.jump.returnTo {
+		d.jump = nil
+	}
+	return nil
+}
+
+// nextStateInternal determines the state ActionNext leads to from state
+// (internal, assumes lock is held)
+func (d *DFA) nextStateInternal(from State, config *StateConfig) (State, error) {
+	var nextState State
+	if config.NextStateFunc != nil {
+		var err error
+		if nextState, err = config.NextStateFunc(d.data); err != nil {
+			return "", err
+		}
 	} else if next, ok := config.Transitions[ActionNext]; ok {
 		nextState = next
 	} else {
 		// Look for global transition rule
 		for _, rule := range d.transitions {
-			if rule.From == d.current && rule.Action == ActionNext {
+			if rule.From == from && rule.Action == ActionNext {
 				if rule.Condition == nil || rule.Condition(d.data) {
 					nextState = rule.To
 					break
@@ -514,10 +658,9 @@ func (d *DFA) Next() error {
 	}
 
 	if nextState == "" {
-		return errors.New("no next state defined")
+		return "", errors.New("no next state defined")
 	}
-
-	return d.transitionToInternal(nextState, ActionNext)
+	return nextState, nil
 }
 
 // Back moves to the previous state
@@ -527,6 +670,9 @@ func (d *DFA) Back() error {
 
 	d.logDryRun("Attempting Back from state: %s", d.current)
 
+	if d.forwardOnly {
+		return ErrForwardOnly
+	}
 	if len(d.history) <= 1 {
 		return errors.New("no previous state in history")
 	}
@@ -543,13 +689,71 @@ func (d *DFA) Back() error {
 	// Get previous state (skip current in history)
 	prevState := d.history[len(d.history)-2]
 
+	// The transition only restores what it sees, so keep what Back changes
+	history, future, jump := d.history, d.future, d.jump
+
 	// Add current state to future for redo
 	d.future = append(d.future, d.current)
 
 	// Remove current from history
 	d.history = d.history[:len(d.history)-1]
+	d.jump = nil
 
-	return d.transitionToInternal(prevState, ActionBack)
+	if err := d.transitionToInternal(prevState, ActionBack); err != nil {
+		d.history, d.future, d.jump = history, future, jump
+		return err
+	}
+	return nil
+}
+
+// Jump returns to state, which must have been visited before the current
+// state. The data entered since is kept. Next then leads back to the current
+// state, passing over the states visited in between, so a single step can be
+// repeated without going through the others again.
+func (d *DFA) Jump(to State) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.logDryRun("Attempting Jump from state %s to %s", d.current, to)
+
+	if d.forwardOnly {
+		return ErrForwardOnly
+	}
+
+	config, exists := d.states[d.current]
+	if !exists {
+		return fmt.Errorf("current state %s does not exist", d.current)
+	}
+
+	if !config.CanGoBack {
+		return errors.New("cannot jump back from current state")
+	}
+
+	target := -1
+	for i := len(d.history) - 2; i >= 0; i-- {
+		if d.history[i] == to {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		return fmt.Errorf("state %s was not visited before %s", to, d.current)
+	}
+
+	jump := &pendingJump{returnTo: d.current, passed: make(map[State]bool)}
+	for _, state := range d.history[target+1 : len(d.history)-1] {
+		jump.passed[state] = true
+	}
+
+	// The transition adds the target again
+	history := d.history
+	d.history = append([]State{}, history[:target]...)
+	if err := d.transitionToInternal(to, ActionJump); err != nil {
+		d.history = history
+		return err
+	}
+	d.jump = jump
+	return nil
 }
 
 // Skip skips the current state
@@ -622,6 +826,19 @@ func (d *DFA) Transition(action Action) error {
 		return fmt.Errorf("current state %s does not exist", d.current)
 	}
 
+	// Help is shown by the UI, the state stays
+	if action == ActionHelp {
+		if config.HelpText == "" {
+			return fmt.Errorf("state %s has no help", d.current)
+		}
+		return nil
+	}
+
+	// Explicit Back transitions do not go around a forward-only flow either
+	if action == ActionBack && d.forwardOnly {
+		return ErrForwardOnly
+	}
+
 	// Check state-specific transitions
 	if next, ok := config.Transitions[action]; ok {
 		return d.transitionToInternal(next, action)
@@ -657,11 +874,13 @@ func (d *DFA) canTransitionInternal(action Action) bool {
 	case ActionNext:
 		return config.CanGoNext
 	case ActionBack:
-		return config.CanGoBack && len(d.history) > 1
+		return config.CanGoBack && len(d.history) > 1 && !d.forwardOnly
 	case ActionSkip:
 		return config.CanSkip
 	case ActionCancel:
 		return config.CanCancel
+	case ActionHelp:
+		return config.HelpText != ""
 	default:
 		// Check if custom action is defined
 		if _, ok := config.Transitions[action]; ok {
@@ -710,8 +929,8 @@ func (d *DFA) ValidateCurrentState() error {
 	return nil
 }
 
-// transitionToInternal performs the actual transition (internal, assumes lock held)
-func (d *DFA) transitionToInternal(to State, action Action) error {
+// performTransition performs the actual transition (internal, assumes lock held)
+func (d *DFA) performTransition(to State, action Action) error {
 	from := d.current
 
 	d.logDryRun("Transition: %s -> %s (action: %s)", from, to, action)
@@ -772,7 +991,10 @@ func (d *DFA) transitionToInternal(to State, action Action) error {
 	d.current = to
 
 	// Update history (don't add duplicates for back action)
-	if action != ActionBack {
+	history := d.history
+	if d.forwardOnly {
+		d.history = []State{to}
+	} else if action != ActionBack {
 		d.history = append(d.history, to)
 		// Trim history if needed
 		if d.maxHistory > 0 && len(d.history) > d.maxHistory {
@@ -788,9 +1010,7 @@ func (d *DFA) transitionToInternal(to State, action Action) error {
 		if err := toConfig.ValidateOnEntry(d.data); err != nil {
 			// Rollback
 			d.current = oldCurrent
-			if action != ActionBack {
-				d.history = d.history[:len(d.history)-1]
-			}
+			d.history = history
 			return err
 		}
 	}
@@ -800,9 +1020,7 @@ func (d *DFA) transitionToInternal(to State, action Action) error {
 		if err := d.callbacks.OnEnter(to, d.data); err != nil {
 			// Rollback
 			d.current = oldCurrent
-			if action != ActionBack {
-				d.history = d.history[:len(d.history)-1]
-			}
+			d.history = history
 			return err
 		}
 	}
@@ -812,9 +1030,7 @@ func (d *DFA) transitionToInternal(to State, action Action) error {
 		if err := toConfig.OnEnter(d.data); err != nil {
 			// Rollback
 			d.current = oldCurrent
-			if action != ActionBack {
-				d.history = d.history[:len(d.history)-1]
-			}
+			d.history = history
 			return err
 		}
 	}
@@ -826,7 +1042,7 @@ func (d *DFA) transitionToInternal(to State, action Action) error {
 				// In dry-run mode, use a fixed timestamp for consistency
 				d.data["completed_at"] = "2024-01-01 12:00:00"
 			} else {
-				d.data["completed_at"] = time.Now().Format(time.RFC3339)
+				d.data["completed_at"] = d.clock().Format(time.RFC3339)
 			}
 		}
 	}
@@ -836,6 +1052,11 @@ func (d *DFA) transitionToInternal(to State, action Action) error {
 		d.callbacks.AfterTransition(from, to, action)
 	}
 
+	// Notify subscribers
+	if !d.DryRun {
+		d.notifySubscribers(StateChange{From: from, To: to, Action: action})
+	}
+
 	return nil
 }
 
@@ -854,7 +1075,9 @@ func (d *DFA) Reset() {
 	d.current = d.initial
 	d.history = []State{d.initial}
 	d.future = []State{}
+	d.jump = nil
 	d.data = make(map[string]interface{})
+	d.snapshots = nil
 	d.dryRunLog = []string{}
 
 	d.logDryRun("DFA reset to initial state: %s", d.initial)
@@ -917,12 +1140,15 @@ func (d *DFA) GetAvailableActions() []Action {
 	if d.canTransitionInternal(ActionCancel) {
 		actions = append(actions, ActionCancel)
 	}
+	if d.canTransitionInternal(ActionHelp) {
+		actions = append(actions, ActionHelp)
+	}
 
 	// Add custom actions
 	if config, exists := d.states[d.current]; exists {
 		for action := range config.Transitions {
 			if action != ActionNext && action != ActionBack &&
-				action != ActionSkip && action != ActionCancel {
+				action != ActionSkip && action != ActionCancel && action != ActionHelp {
 				actions = append(actions, action)
 			}
 		}
@@ -1008,12 +1234,15 @@ func (d *DFA) Clone() *DFA {
 
 	// Copy transitions
 	clone.transitions = append([]TransitionRule{}, d.transitions...)
+	clone.middleware = append([]TransitionMiddleware{}, d.middleware...)
 
 	// Copy options
 	clone.maxHistory = d.maxHistory
 	clone.AllowBackToAny = d.AllowBackToAny
 	clone.strictMode = d.strictMode
 	clone.DryRun = d.DryRun
+	clone.forwardOnly = d.forwardOnly
+	clone.clock = d.clock
 
 	return clone
 }
@@ -1,10 +1,13 @@
 package wizard
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 // TestNewDFA tests DFA creation
@@ -1079,3 +1082,724 @@ func BenchmarkClone(b *testing.B) {
 		_ = dfa.Clone()
 	}
 }
+
+// newLinearDFA creates a DFA with the given states connected in order
+func newLinearDFA(t *testing.T, states ...State) *DFA {
+	t.Helper()
+	dfa := New()
+	for i, state := range states {
+		config := &StateConfig{
+			Name:        string(state),
+			CanGoBack:   i > 0,
+			CanGoNext:   i < len(states)-1,
+			CanCancel:   true,
+			Transitions: make(map[Action]State),
+		}
+		if i < len(states)-1 {
+			config.Transitions[ActionNext] = states[i+1]
+		}
+		if i > 0 {
+			config.Transitions[ActionBack] = states[i-1]
+		}
+		if err := dfa.AddState(state, config); err != nil {
+			t.Fatalf("AddState(%s) failed: %v", state, err)
+		}
+	}
+	return dfa
+}
+
+// TestTransitionMiddleware tests middleware ordering and short-circuiting
+func TestTransitionMiddleware(t *testing.T) {
+	dfa := newLinearDFA(t, "welcome", "license", "finish")
+
+	var calls []string
+	record := func(name string) TransitionMiddleware {
+		return func(next TransitionFunc) TransitionFunc {
+			return func(from, to State, action Action) error {
+				calls = append(calls, fmt.Sprintf("%s:before:%s->%s", name, from, to))
+				err := next(from, to, action)
+				calls = append(calls, fmt.Sprintf("%s:after:%s->%s", name, from, to))
+				return err
+			}
+		}
+	}
+	dfa.Use(record("outer"))
+	dfa.Use(record("inner"))
+
+	if err := dfa.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	calls = nil
+
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+
+	expected := []string{
+		"outer:before:welcome->license",
+		"inner:before:welcome->license",
+		"inner:after:welcome->license",
+		"outer:after:welcome->license",
+	}
+	if strings.Join(calls, ",") != strings.Join(expected, ",") {
+		t.Errorf("Unexpected middleware order:\n got: %v\nwant: %v", calls, expected)
+	}
+
+	// A middleware that blocks transitions into "finish"
+	blocked := 0
+	dfa.Use(func(next TransitionFunc) TransitionFunc {
+		return func(from, to State, action Action) error {
+			if to == "finish" {
+				blocked++
+				return fmt.Errorf("finish is locked")
+			}
+			return next(from, to, action)
+		}
+	})
+
+	err := dfa.Next()
+	if err == nil || !strings.Contains(err.Error(), "finish is locked") {
+		t.Errorf("Expected short-circuit error, got: %v", err)
+	}
+	if blocked != 1 {
+		t.Errorf("Expected blocking middleware to run once, ran %d times", blocked)
+	}
+	if dfa.CurrentState() != "license" {
+		t.Errorf("State should not change after short-circuit, got %s", dfa.CurrentState())
+	}
+}
+
+// TestSnapshotRollback tests reverting data edits to a snapshot
+func TestSnapshotRollback(t *testing.T) {
+	dfa := newLinearDFA(t, "welcome", "config")
+	if err := dfa.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	dfa.SetData("host", "localhost")
+	dfa.SetData("port", 5432)
+
+	token := dfa.Snapshot()
+
+	dfa.SetData("host", "db.example.com")
+	dfa.SetData("user", "admin")
+	later := dfa.Snapshot()
+	dfa.SetData("port", 6543)
+
+	if err := dfa.Rollback(token); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+
+	if host, _ := dfa.GetData("host"); host != "localhost" {
+		t.Errorf("Expected host localhost after rollback, got %v", host)
+	}
+	if port, _ := dfa.GetData("port"); port != 5432 {
+		t.Errorf("Expected port 5432 after rollback, got %v", port)
+	}
+	if _, exists := dfa.GetData("user"); exists {
+		t.Error("Key added after snapshot should be removed by rollback")
+	}
+
+	// Rolling back does not consume the token, but discards newer snapshots
+	dfa.SetData("host", "other")
+	if err := dfa.Rollback(token); err != nil {
+		t.Errorf("Second rollback to same token failed: %v", err)
+	}
+	if host, _ := dfa.GetData("host"); host != "localhost" {
+		t.Errorf("Expected host localhost after second rollback, got %v", host)
+	}
+	if err := dfa.Rollback(later); err == nil {
+		t.Error("Expected error rolling back to discarded snapshot")
+	}
+	if err := dfa.Rollback(999); err == nil {
+		t.Error("Expected error for unknown snapshot token")
+	}
+}
+
+// TestTypedDataAccessors tests typed getters for present, absent and wrong-type keys
+func TestTypedDataAccessors(t *testing.T) {
+	dfa := New()
+	dfa.SetData("name", "SetupKit")
+	dfa.SetData("accepted", true)
+	dfa.SetData("port", 8080)
+	dfa.SetData("json_port", float64(5432))
+	dfa.SetData("ratio", 0.5)
+	dfa.SetData("tags", []string{"a", "b"})
+
+	// Present keys
+	if v, ok := dfa.GetString("name"); !ok || v != "SetupKit" {
+		t.Errorf("GetString(name) = %q, %v", v, ok)
+	}
+	if v, ok := dfa.GetBool("accepted"); !ok || !v {
+		t.Errorf("GetBool(accepted) = %v, %v", v, ok)
+	}
+	if v, ok := dfa.GetInt("port"); !ok || v != 8080 {
+		t.Errorf("GetInt(port) = %d, %v", v, ok)
+	}
+	if v, ok := dfa.GetInt("json_port"); !ok || v != 5432 {
+		t.Errorf("GetInt(json_port) = %d, %v", v, ok)
+	}
+	if v, ok := GetAs[[]string](dfa, "tags"); !ok || len(v) != 2 {
+		t.Errorf("GetAs[[]string](tags) = %v, %v", v, ok)
+	}
+
+	// Absent keys
+	if _, ok := dfa.GetString("missing"); ok {
+		t.Error("GetString(missing) should not be ok")
+	}
+	if _, ok := dfa.GetBool("missing"); ok {
+		t.Error("GetBool(missing) should not be ok")
+	}
+	if _, ok := dfa.GetInt("missing"); ok {
+		t.Error("GetInt(missing) should not be ok")
+	}
+
+	// Wrong types
+	if _, ok := dfa.GetString("port"); ok {
+		t.Error("GetString(port) should not be ok for int value")
+	}
+	if _, ok := dfa.GetBool("name"); ok {
+		t.Error("GetBool(name) should not be ok for string value")
+	}
+	if _, ok := dfa.GetInt("ratio"); ok {
+		t.Error("GetInt(ratio) should not be ok for fractional value")
+	}
+	if _, ok := GetAs[int](dfa, "tags"); ok {
+		t.Error("GetAs[int](tags) should not be ok for slice value")
+	}
+
+	// Must variants return zero values instead of panicking
+	if v := dfa.MustGetString("port"); v != "" {
+		t.Errorf("MustGetString(port) = %q, want empty", v)
+	}
+	if v := dfa.MustGetBool("missing"); v {
+		t.Error("MustGetBool(missing) should be false")
+	}
+	if v := dfa.MustGetInt("name"); v != 0 {
+		t.Errorf("MustGetInt(name) = %d, want 0", v)
+	}
+	if v := dfa.MustGetString("name"); v != "SetupKit" {
+		t.Errorf("MustGetString(name) = %q", v)
+	}
+
+	// Map-based helper used by validators
+	data := map[string]interface{}{"path": "/opt/app", "flag": "yes"}
+	if v, ok := DataAs[string](data, "path"); !ok || v != "/opt/app" {
+		t.Errorf("DataAs[string](path) = %q, %v", v, ok)
+	}
+	if _, ok := DataAs[bool](data, "flag"); ok {
+		t.Error("DataAs[bool](flag) should not be ok for string value")
+	}
+}
+
+// TestSubscribe tests that multiple subscribers receive the same transition events
+func TestSubscribe(t *testing.T) {
+	dfa := newLinearDFA(t, "welcome", "license", "finish")
+
+	first := dfa.Subscribe()
+	second := dfa.Subscribe()
+
+	if err := dfa.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if err := dfa.Back(); err != nil {
+		t.Fatalf("Back failed: %v", err)
+	}
+
+	expected := []StateChange{
+		{From: "", To: "welcome", Action: ActionNext},
+		{From: "welcome", To: "license", Action: ActionNext},
+		{From: "license", To: "welcome", Action: ActionBack},
+	}
+	for name, ch := range map[string]<-chan StateChange{"first": first, "second": second} {
+		for i, want := range expected {
+			select {
+			case got := <-ch:
+				if got != want {
+					t.Errorf("%s subscriber event %d = %+v, want %+v", name, i, got, want)
+				}
+			default:
+				t.Fatalf("%s subscriber missing event %d", name, i)
+			}
+		}
+	}
+
+	// Unsubscribed channels are closed and receive nothing more
+	dfa.Unsubscribe(first)
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if _, open := <-first; open {
+		t.Error("Unsubscribed channel should be closed")
+	}
+	if got := <-second; got.To != "license" {
+		t.Errorf("Remaining subscriber got %+v", got)
+	}
+}
+
+// TestSubscribeSlowListener tests that a full subscriber does not block transitions
+func TestSubscribeSlowListener(t *testing.T) {
+	dfa := newLinearDFA(t, "a", "b")
+	slow := dfa.Subscribe()
+
+	if err := dfa.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	for i := 0; i < subscriberBuffer*2; i++ {
+		if err := dfa.Next(); err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if err := dfa.Back(); err != nil {
+			t.Fatalf("Back failed: %v", err)
+		}
+	}
+
+	if len(slow) != subscriberBuffer {
+		t.Errorf("Expected buffer to be full with %d events, got %d", subscriberBuffer, len(slow))
+	}
+}
+
+func TestHelpAction(t *testing.T) {
+	dfa := New()
+	dfa.AddState("start", &StateConfig{
+		Name:      "Start",
+		CanGoNext: true,
+		HelpText:  "Explains the start",
+		Transitions: map[Action]State{
+			ActionNext: "end",
+		},
+	})
+	dfa.AddState("end", &StateConfig{Name: "End"})
+	dfa.SetInitialState("start")
+	if err := dfa.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	if got := dfa.HelpText("start"); got != "Explains the start" {
+		t.Errorf("HelpText(start) = %q", got)
+	}
+	if !dfa.CanTransition(ActionHelp) {
+		t.Error("Help should be available in a state with help text")
+	}
+	if err := dfa.Transition(ActionHelp); err != nil {
+		t.Fatalf("Transition(ActionHelp) failed: %v", err)
+	}
+	if dfa.CurrentState() != "start" {
+		t.Errorf("Help left the state for %s", dfa.CurrentState())
+	}
+	if history := dfa.GetHistory(); len(history) != 1 {
+		t.Errorf("Help should not be recorded in the history, got %v", history)
+	}
+
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if dfa.CanTransition(ActionHelp) {
+		t.Error("Help should not be available without help text")
+	}
+	for _, action := range dfa.GetAvailableActions() {
+		if action == ActionHelp {
+			t.Error("Available actions should not include help without help text")
+		}
+	}
+	if err := dfa.Transition(ActionHelp); err == nil {
+		t.Error("Transition(ActionHelp) should fail without help text")
+	}
+}
+
+func TestOptionalStateSkipped(t *testing.T) {
+	newDFA := func(enabled bool) *DFA {
+		dfa := New()
+		dfa.AddState("start", &StateConfig{
+			Name:        "Start",
+			CanGoNext:   true,
+			Transitions: map[Action]State{ActionNext: "extra"},
+		})
+		dfa.AddState("extra", &StateConfig{
+			Name:         "Extra",
+			CanGoNext:    true,
+			CanGoBack:    true,
+			Optional:     true,
+			CanEnterFunc: func(data map[string]interface{}) bool { return enabled },
+			Transitions:  map[Action]State{ActionNext: "end"},
+		})
+		dfa.AddState("end", &StateConfig{Name: "End", CanGoBack: true})
+		dfa.SetInitialState("start")
+		if err := dfa.Start(); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		return dfa
+	}
+
+	dfa := newDFA(true)
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if dfa.CurrentState() != "extra" {
+		t.Errorf("Expected enterable optional state, got %s", dfa.CurrentState())
+	}
+
+	dfa = newDFA(false)
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if dfa.CurrentState() != "end" {
+		t.Errorf("Expected optional state to be skipped, got %s", dfa.CurrentState())
+	}
+	if err := dfa.Back(); err != nil {
+		t.Fatalf("Back failed: %v", err)
+	}
+	if dfa.CurrentState() != "start" {
+		t.Errorf("Back should return past the skipped state, got %s", dfa.CurrentState())
+	}
+}
+
+func TestJump(t *testing.T) {
+	dfa := New()
+	for _, s := range []struct{ state, next State }{{"start", "path"}, {"path", "options"}, {"options", "summary"}, {"summary", "done"}} {
+		dfa.AddState(s.state, &StateConfig{
+			Name:        string(s.state),
+			CanGoNext:   true,
+			CanGoBack:   true,
+			Transitions: map[Action]State{ActionNext: s.next},
+		})
+	}
+	dfa.AddState("done", &StateConfig{Name: "done"})
+	dfa.SetInitialState("start")
+	if err := dfa.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := dfa.Next(); err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+	}
+	dfa.SetData("path", "/opt/old")
+	dfa.SetData("option", "kept")
+
+	if err := dfa.Jump("done"); err == nil {
+		t.Error("Jump to a state not visited yet should fail")
+	}
+	if err := dfa.Jump("path"); err != nil {
+		t.Fatalf("Jump failed: %v", err)
+	}
+	if dfa.CurrentState() != "path" {
+		t.Fatalf("Expected path after Jump, got %s", dfa.CurrentState())
+	}
+	dfa.SetData("path", "/opt/new")
+
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if dfa.CurrentState() != "summary" {
+		t.Errorf("Next after Jump should return to summary, got %s", dfa.CurrentState())
+	}
+	if got, _ := dfa.GetData("path"); got != "/opt/new" {
+		t.Errorf("Changed data lost, path = %v", got)
+	}
+	if got, _ := dfa.GetData("option"); got != "kept" {
+		t.Errorf("Other data lost, option = %v", got)
+	}
+	if history := dfa.GetHistory(); !reflect.DeepEqual(history, []State{"start", "path", "options", "summary"}) {
+		t.Errorf("Unexpected history after Jump: %v", history)
+	}
+
+	// Back undoes a jump, Next follows the flow again
+	if err := dfa.Jump("path"); err != nil {
+		t.Fatalf("Jump failed: %v", err)
+	}
+	if err := dfa.Back(); err != nil {
+		t.Fatalf("Back failed: %v", err)
+	}
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if dfa.CurrentState() != "options" {
+		t.Errorf("Expected options after Back from a jump, got %s", dfa.CurrentState())
+	}
+}
+
+// TestForwardOnly tests that a forward-only DFA cannot go back, keeps only
+// the current state as history and still completes with its data
+func TestForwardOnly(t *testing.T) {
+	dfa := New()
+	for _, s := range []struct{ state, next State }{{"start", "options"}, {"options", "summary"}, {"summary", "done"}} {
+		dfa.AddState(s.state, &StateConfig{
+			Name:        string(s.state),
+			CanGoNext:   true,
+			CanGoBack:   true,
+			Transitions: map[Action]State{ActionNext: s.next, ActionBack: "start"},
+		})
+	}
+	dfa.AddState("done", &StateConfig{Name: "done"})
+	dfa.AddFinalState("done")
+	if err := dfa.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+
+	// Switching over trims the history gathered so far
+	dfa.SetForwardOnly(true)
+	if history := dfa.GetHistory(); !reflect.DeepEqual(history, []State{"options"}) {
+		t.Errorf("history after SetForwardOnly = %v, want only the current state", history)
+	}
+	dfa.SetData("option", "kept")
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+
+	if dfa.CanTransition(ActionBack) {
+		t.Error("CanTransition(ActionBack) = true in forward-only mode")
+	}
+	if err := dfa.Back(); !errors.Is(err, ErrForwardOnly) {
+		t.Errorf("Back() error = %v, want ErrForwardOnly", err)
+	}
+	if err := dfa.Jump("options"); !errors.Is(err, ErrForwardOnly) {
+		t.Errorf("Jump() error = %v, want ErrForwardOnly", err)
+	}
+	if err := dfa.Transition(ActionBack); !errors.Is(err, ErrForwardOnly) {
+		t.Errorf("Transition(ActionBack) error = %v, want ErrForwardOnly", err)
+	}
+	if !dfa.Clone().IsForwardOnly() {
+		t.Error("Clone() dropped forward-only mode")
+	}
+	if dfa.CurrentState() != "summary" {
+		t.Errorf("state = %s after refused navigation, want summary", dfa.CurrentState())
+	}
+
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if !dfa.IsInFinalState() {
+		t.Error("forward-only flow did not complete")
+	}
+	if history := dfa.GetHistory(); !reflect.DeepEqual(history, []State{"done"}) {
+		t.Errorf("history = %v, want only the final state", history)
+	}
+	if got, _ := dfa.GetData("option"); got != "kept" {
+		t.Errorf("data lost, option = %v", got)
+	}
+	if _, ok := dfa.GetData("completed_at"); !ok {
+		t.Error("completion time not recorded")
+	}
+}
+
+// TestSetClock tests that the completion time comes from the injected clock
+func TestSetClock(t *testing.T) {
+	fixed := time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+	run := func(setup func(dfa *DFA)) interface{} {
+		dfa := New()
+		dfa.AddState("start", &StateConfig{
+			Name:        "Start",
+			CanGoNext:   true,
+			Transitions: map[Action]State{ActionNext: "done"},
+		})
+		dfa.AddState("done", &StateConfig{Name: "Done"})
+		dfa.AddFinalState("done")
+		dfa.SetInitialState("start")
+		setup(dfa)
+		if err := dfa.Start(); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		if err := dfa.Next(); err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		completed, _ := dfa.GetData("completed_at")
+		return completed
+	}
+
+	if got := run(func(dfa *DFA) { dfa.SetClock(func() time.Time { return fixed }) }); got != "2025-03-14T15:09:26Z" {
+		t.Errorf("completed_at = %v, want the injected clock value", got)
+	}
+
+	// A clone keeps the clock
+	original := New()
+	original.SetClock(func() time.Time { return fixed })
+	if got := original.Clone().clock(); !got.Equal(fixed) {
+		t.Errorf("clone clock = %v, want the injected clock", got)
+	}
+
+	// The dry run keeps its own fixed timestamp
+	if got := run(func(dfa *DFA) {
+		dfa.SetClock(func() time.Time { return fixed })
+		dfa.SetDryRun(true)
+	}); got != "2024-01-01 12:00:00" {
+		t.Errorf("dry run completed_at = %v", got)
+	}
+
+	// Without a clock the current time is used
+	before := time.Now().Add(-time.Second)
+	got, _ := run(func(dfa *DFA) { dfa.SetClock(nil) }).(string)
+	completed, err := time.Parse(time.RFC3339, got)
+	if err != nil || completed.Before(before.Truncate(time.Second)) {
+		t.Errorf("completed_at = %q, want the current time", got)
+	}
+}
+
+// TestOnEnterPanic tests that a panic while entering a state is returned as an
+// error and leaves the DFA in the previous state
+func TestOnEnterPanic(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		setup func(dfa *DFA, onEnter func(map[string]interface{}) error)
+	}{
+		{"state OnEnter", func(dfa *DFA, onEnter func(map[string]interface{}) error) {
+			config, _ := dfa.GetStateConfig("boom")
+			config.OnEnter = onEnter
+		}},
+		{"callback", func(dfa *DFA, onEnter func(map[string]interface{}) error) {
+			dfa.SetCallbacks(&Callbacks{
+				OnEnter: func(state State, data map[string]interface{}) error {
+					if state == "boom" {
+						return onEnter(data)
+					}
+					return nil
+				},
+				OnPanic: func(state State, err *PanicError) {
+					dfa.data["panicked_in"] = string(state)
+				},
+			})
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dfa := New()
+			dfa.AddState("start", &StateConfig{
+				Name:        "Start",
+				CanGoNext:   true,
+				Transitions: map[Action]State{ActionNext: "boom"},
+			})
+			dfa.AddState("boom", &StateConfig{Name: "Boom", CanGoBack: true})
+			dfa.SetInitialState("start")
+			if err := dfa.Start(); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			tt.setup(dfa, func(data map[string]interface{}) error {
+				data["half_done"] = true
+				var options map[string]string
+				options["mode"] = "fast"
+				return nil
+			})
+
+			live := dfa.data
+			err := dfa.Next()
+			var panicErr *PanicError
+			if !errors.As(err, &panicErr) {
+				t.Fatalf("Next() error = %v, want a PanicError", err)
+			}
+			if panicErr.State != "boom" || !strings.Contains(err.Error(), "nil map") || len(panicErr.Stack) == 0 {
+				t.Errorf("unexpected panic error %v, state %s", err, panicErr.State)
+			}
+			if dfa.CurrentState() != "start" {
+				t.Errorf("Expected to stay on start, got %s", dfa.CurrentState())
+			}
+			if history := dfa.GetHistory(); !reflect.DeepEqual(history, []State{"start"}) {
+				t.Errorf("Expected history [start], got %v", history)
+			}
+			if _, ok := dfa.GetData("half_done"); ok {
+				t.Error("data changed by the failed transition should be undone")
+			}
+			if dfa.SetData("after", true); live["after"] != true {
+				t.Error("the data should be restored in place, not replaced")
+			}
+			if tt.name == "callback" {
+				if state, _ := dfa.GetData("panicked_in"); state != "start" {
+					t.Errorf("OnPanic reported state %v, want start", state)
+				}
+			}
+
+			// The DFA keeps working
+			if err := dfa.Next(); err == nil || dfa.CurrentState() != "start" {
+				t.Errorf("Expected the panic again on start, got %v in %s", err, dfa.CurrentState())
+			}
+		})
+	}
+}
+
+// TestBackOnEnterPanic tests that a panic while going back leaves the history
+// and the redo stack as they were before Back
+func TestBackOnEnterPanic(t *testing.T) {
+	dfa := New()
+	panicking := false
+	dfa.AddState("start", &StateConfig{
+		Name:        "Start",
+		CanGoNext:   true,
+		Transitions: map[Action]State{ActionNext: "middle"},
+		OnEnter: func(data map[string]interface{}) error {
+			if panicking {
+				panic("start failed")
+			}
+			return nil
+		},
+	})
+	dfa.AddState("middle", &StateConfig{
+		Name:        "Middle",
+		CanGoNext:   true,
+		CanGoBack:   true,
+		Transitions: map[Action]State{ActionNext: "end", ActionBack: "start"},
+	})
+	dfa.AddState("end", &StateConfig{Name: "End", CanGoBack: true, Transitions: map[Action]State{ActionBack: "middle"}})
+	dfa.SetInitialState("start")
+	if err := dfa.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if err := dfa.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if err := dfa.Back(); err != nil {
+		t.Fatalf("Back failed: %v", err)
+	}
+
+	panicking = true
+	var panicErr *PanicError
+	if err := dfa.Back(); !errors.As(err, &panicErr) {
+		t.Fatalf("Back() error = %v, want a PanicError", err)
+	}
+	if dfa.CurrentState() != "middle" {
+		t.Errorf("Expected to stay on middle, got %s", dfa.CurrentState())
+	}
+	if history := dfa.GetHistory(); !reflect.DeepEqual(history, []State{"start", "middle"}) {
+		t.Errorf("Expected history [start middle], got %v", history)
+	}
+	if !reflect.DeepEqual(dfa.future, []State{"end"}) {
+		t.Errorf("Expected redo stack [end], got %v", dfa.future)
+	}
+
+	// The DFA keeps working
+	panicking = false
+	if err := dfa.Back(); err != nil || dfa.CurrentState() != "start" {
+		t.Errorf("Back() = %v in %s, want start", err, dfa.CurrentState())
+	}
+}
+
+// TestUnreachable tests that states no transition leads to are reported
+func TestUnreachable(t *testing.T) {
+	dfa := New()
+	dfa.AddState("start", &StateConfig{Name: "start", Transitions: map[Action]State{ActionNext: "path"}})
+	dfa.AddState("path", &StateConfig{Name: "path", Transitions: map[Action]State{ActionBack: "start"}})
+	dfa.AddState("options", &StateConfig{Name: "options"})
+	dfa.AddState("orphan", &StateConfig{Name: "orphan", Transitions: map[Action]State{ActionNext: "done"}})
+	dfa.AddState("done", &StateConfig{Name: "done"})
+	dfa.AddTransition(TransitionRule{From: "path", To: "options", Action: ActionNext})
+	dfa.SetInitialState("start")
+
+	// Only the orphan leads to done
+	if got := dfa.Unreachable(); !reflect.DeepEqual(got, []State{"done", "orphan"}) {
+		t.Errorf("Unreachable() = %v, want [done orphan]", got)
+	}
+
+	dfa.AddTransition(TransitionRule{From: "options", To: "orphan", Action: ActionNext})
+	if got := dfa.Unreachable(); len(got) != 0 {
+		t.Errorf("Unreachable() = %v after linking the orphan, want none", got)
+	}
+}
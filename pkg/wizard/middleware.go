@@ -0,0 +1,43 @@
+package wizard
+
+// TransitionFunc performs a transition from one state to another
+type TransitionFunc func(from, to State, action Action) error
+
+// TransitionMiddleware wraps a transition to add cross-cutting behavior such as
+// logging, metrics or persistence. A middleware may short-circuit the transition
+// by returning an error without calling next.
+//
+// Middleware runs while the DFA lock is held and must not call DFA methods.
+type TransitionMiddleware func(next TransitionFunc) TransitionFunc
+
+// Use registers a transition middleware. Middlewares run in registration order,
+// the first registered being the outermost.
+func (d *DFA) Use(middleware TransitionMiddleware) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.middleware = append(d.middleware, middleware)
+}
+
+// transitionToInternal runs the transition through the middleware chain. A
+// panic in a callback or middleware undoes the transition and is returned as
+// a PanicError (internal, assumes lock held).
+func (d *DFA) transitionToInternal(to State, action Action) (err error) {
+	saved := d.saveTransitionState()
+	defer func() {
+		if r := recover(); r != nil {
+			err = d.undoPanic(saved, to, r)
+		}
+	}()
+
+	if len(d.middleware) == 0 {
+		return d.performTransition(to, action)
+	}
+
+	next := TransitionFunc(func(_, to State, action Action) error {
+		return d.performTransition(to, action)
+	})
+	for i := len(d.middleware) - 1; i >= 0; i-- {
+		next = d.middleware[i](next)
+	}
+	return next(d.current, to, action)
+}
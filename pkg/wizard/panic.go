@@ -0,0 +1,65 @@
+package wizard
+
+import (
+	"fmt"
+	"maps"
+	"runtime/debug"
+)
+
+// PanicError is returned by a transition during which a callback panicked,
+// for example because of a nil map access in a state's OnEnter. The
+// transition is undone, so the DFA stays in the state it was in.
+type PanicError struct {
+	State State       // State being entered
+	Value interface{} // Value passed to panic
+	Stack []byte      // Stack of the panic, for the log
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic while entering state %s: %v", e.State, e.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// transitionState is what a transition changes, saved to undo it. A
+// transition only appends to the history and the future or replaces them,
+// so keeping their slices is enough; the data is written by the callbacks
+// and copied.
+type transitionState struct {
+	current State
+	history []State
+	future  []State
+	data    map[string]interface{}
+}
+
+// saveTransitionState saves what a transition changes (internal, assumes lock held)
+func (d *DFA) saveTransitionState() transitionState {
+	return transitionState{
+		current: d.current,
+		history: d.history,
+		future:  d.future,
+		data:    maps.Clone(d.data),
+	}
+}
+
+// undoPanic restores saved after a panic with value during the transition to
+// to and returns the PanicError, after reporting it to OnPanic (internal,
+// assumes lock held)
+func (d *DFA) undoPanic(saved transitionState, to State, value interface{}) error {
+	d.current = saved.current
+	d.history = saved.history
+	d.future = saved.future
+	// In place, so the map the callbacks were given stays the DFA's data
+	clear(d.data)
+	maps.Copy(d.data, saved.data)
+
+	err := &PanicError{State: to, Value: value, Stack: debug.Stack()}
+	if d.callbacks != nil && d.callbacks.OnPanic != nil {
+		d.callbacks.OnPanic(d.current, err)
+	}
+	return err
+}
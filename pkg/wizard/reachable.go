@@ -0,0 +1,57 @@
+package wizard
+
+import "sort"
+
+// Unreachable returns the states, sorted, that no chain of transitions leads
+// to from the initial state. The walk follows the states' transitions and the
+// transition rules; targets a NextStateFunc picks at runtime are not known
+// and so not followed.
+func (d *DFA) Unreachable() []State {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	reached := d.reachableInternal()
+	var unreachable []State
+	for state := range d.states {
+		if !reached[state] {
+			unreachable = append(unreachable, state)
+		}
+	}
+	sort.Slice(unreachable, func(i, j int) bool { return unreachable[i] < unreachable[j] })
+	return unreachable
+}
+
+// reachableInternal walks the transitions breadth first from the initial
+// state and returns the states reached (internal, assumes lock is held)
+func (d *DFA) reachableInternal() map[State]bool {
+	reached := make(map[State]bool)
+	if d.initial == "" {
+		return reached
+	}
+
+	queue := []State{d.initial}
+	reached[d.initial] = true
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		var targets []State
+		if config, exists := d.states[state]; exists {
+			for _, target := range config.Transitions {
+				targets = append(targets, target)
+			}
+		}
+		for _, rule := range d.transitions {
+			if rule.From == state {
+				targets = append(targets, rule.To)
+			}
+		}
+		for _, target := range targets {
+			if !reached[target] {
+				reached[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+	return reached
+}
@@ -0,0 +1,51 @@
+package wizard
+
+import "fmt"
+
+// Snapshot saves a shallow copy of the data store and returns a token that can
+// be passed to Rollback. Unlike Back, which only changes the current state,
+// rolling back reverts data edits made after the snapshot.
+func (d *DFA) Snapshot() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.snapshots == nil {
+		d.snapshots = make(map[int]map[string]interface{})
+	}
+	d.nextSnapshot++
+	d.snapshots[d.nextSnapshot] = copyData(d.data)
+
+	d.logDryRun("Snapshot: %d", d.nextSnapshot)
+	return d.nextSnapshot
+}
+
+// Rollback restores the data store to the snapshot identified by token.
+// Snapshots taken after token are discarded; token itself stays valid.
+func (d *DFA) Rollback(token int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	snapshot, exists := d.snapshots[token]
+	if !exists {
+		return fmt.Errorf("snapshot %d does not exist", token)
+	}
+
+	d.data = copyData(snapshot)
+	for t := range d.snapshots {
+		if t > token {
+			delete(d.snapshots, t)
+		}
+	}
+
+	d.logDryRun("Rollback: %d", token)
+	return nil
+}
+
+// copyData returns a shallow copy of a data map
+func copyData(data map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		result[k] = v
+	}
+	return result
+}
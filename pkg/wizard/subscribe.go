@@ -0,0 +1,48 @@
+package wizard
+
+// subscriberBuffer is the number of events buffered per subscriber before events are dropped
+const subscriberBuffer = 32
+
+// StateChange describes a completed transition
+type StateChange struct {
+	From   State
+	To     State
+	Action Action
+}
+
+// Subscribe returns a channel that receives an event after every completed
+// transition. Delivery never blocks transitions: when a subscriber's buffer is
+// full, further events for it are dropped until it catches up.
+func (d *DFA) Subscribe() <-chan StateChange {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ch := make(chan StateChange, subscriberBuffer)
+	d.subscribers = append(d.subscribers, ch)
+	return ch
+}
+
+// Unsubscribe stops delivery to a channel returned by Subscribe and closes it
+func (d *DFA) Unsubscribe(ch <-chan StateChange) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, sub := range d.subscribers {
+		if (<-chan StateChange)(sub) == ch {
+			close(sub)
+			d.subscribers = append(d.subscribers[:i], d.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// notifySubscribers sends a state change to all subscribers without blocking (internal, assumes lock held)
+func (d *DFA) notifySubscribers(change StateChange) {
+	for _, sub := range d.subscribers {
+		select {
+		case sub <- change:
+		default:
+			// Slow subscriber, drop the event
+		}
+	}
+}